@@ -426,6 +426,11 @@ type FelixConfigurationSpec struct {
 	// set to false. This reduces the number of metrics reported, reducing Prometheus load. [Default: true]
 	PrometheusWireGuardMetricsEnabled *bool `json:"prometheusWireGuardMetricsEnabled,omitempty"`
 
+	// PrometheusBPFWorkloadMetricsEnabled controls whether the BPF packet counters, which are reported
+	// per interface, hook and reason, are also labelled with the workload that owns the interface.
+	// [Default: false]
+	PrometheusBPFWorkloadMetricsEnabled *bool `json:"prometheusBPFWorkloadMetricsEnabled,omitempty"`
+
 	// FailsafeInboundHostPorts is a list of ProtoPort struct objects including UDP/TCP/SCTP ports and CIDRs that Felix will
 	// allow incoming traffic to host endpoints on irrespective of the security policy. This is useful to avoid accidentally
 	// cutting off a host with incorrect configuration. For backwards compatibility, if the protocol is not specified,
@@ -708,7 +713,10 @@ type FelixConfigurationSpec struct {
 
 	// BPFMapSizeNATBackend sets the size for NAT back end map.
 	// This is the total number of endpoints. This is mostly
-	// more than the size of the number of services.
+	// more than the size of the number of services. Each service that
+	// uses Maglev load balancing needs a further 1009 entries for its
+	// lookup table; if they do not fit, the lookup table is not written
+	// and the service's backends are picked at random.
 	BPFMapSizeNATBackend *int `json:"bpfMapSizeNATBackend,omitempty"`
 
 	// BPFMapSizeNATAffinity sets the size of the BPF map that stores the affinity of a connection (for services that
@@ -778,6 +786,70 @@ type FelixConfigurationSpec struct {
 	//+kubebuilder:validation:Enum=Enabled;Disabled;L2Only
 	BPFRedirectToPeer string `json:"bpfRedirectToPeer,omitempty"`
 
+	// BPFEventsSampleRate controls the stream of events that the BPF programs emit when they drop a packet
+	// because of policy or a failed RPF check, or when a packet is sent to a service that has no backends.
+	// One in every BPFEventsSampleRate events is emitted.  The events can be followed with
+	// `calico-bpf events --follow`.  Zero disables the events. [Default: 0]
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BPFEventsSampleRate *int `json:"bpfEventsSampleRate,omitempty"`
+
+	// BPFEgressBandwidthFQEnabled controls whether Felix replaces the root qdisc of the host's data
+	// interfaces with fq while a workload has an egress bandwidth limit.  The BPF programs pace the
+	// packets of the limited workloads by setting their departure time, which only fq respects; without
+	// it, the limit is only enforced by dropping the packets that would have to wait too long.  Felix
+	// restores the previous root qdisc when no workload has an egress limit any more.  The interfaces
+	// of the workloads with an ingress limit always get fq. [Default: false]
+	BPFEgressBandwidthFQEnabled *bool `json:"bpfEgressBandwidthFQEnabled,omitempty"`
+
+	// BPFXDPDDoSProtection enables the DDoS protection of the host endpoints in XDP.  It drops
+	// the packets from the sources in the blocklist, and the SYNs and UDP packets above the
+	// rate limits of their source prefix.  The traffic from the hosts and the workloads of
+	// the cluster is not rate limited.  The counters of the dropped packets are shown by
+	// `calico-bpf counters dump` and exported as Prometheus metrics. [Default: Disabled]
+	//+kubebuilder:validation:Enum=Enabled;Disabled
+	BPFXDPDDoSProtection string `json:"bpfXDPDDoSProtection,omitempty" validate:"omitempty,oneof=Enabled Disabled"`
+
+	// BPFXDPSYNRateLimit is the number of TCP SYNs per second that the host endpoints accept from
+	// a source prefix when BPFXDPDDoSProtection is enabled.  Zero means no limit. [Default: 0]
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BPFXDPSYNRateLimit *int `json:"bpfXDPSYNRateLimit,omitempty"`
+
+	// BPFXDPUDPRateLimit is the number of UDP packets per second that the host endpoints accept
+	// from a source prefix when BPFXDPDDoSProtection is enabled.  Zero means no limit. [Default: 0]
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BPFXDPUDPRateLimit *int `json:"bpfXDPUDPRateLimit,omitempty"`
+
+	// BPFXDPSYNCookies controls whether the SYNs above BPFXDPSYNRateLimit that are sent to a
+	// listening socket of the host are answered with a SYN cookie from XDP, rather than dropped.
+	// Only IPv4 is supported.  The kernel only generates and accepts the cookies with the
+	// net.ipv4.tcp_syncookies sysctl enabled, so Felix sets it to 1 if it is 0. [Default: Disabled]
+	//+kubebuilder:validation:Enum=Enabled;Disabled
+	BPFXDPSYNCookies string `json:"bpfXDPSYNCookies,omitempty" validate:"omitempty,oneof=Enabled Disabled"`
+
+	// BPFXDPRateLimitPrefixLengthV4 is the length of the IPv4 source prefixes that share a
+	// rate limit. [Default: 32]
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	BPFXDPRateLimitPrefixLengthV4 *int `json:"bpfXDPRateLimitPrefixLengthV4,omitempty" validate:"omitempty,gte=0,lte=32"`
+
+	// BPFXDPRateLimitPrefixLengthV6 is the length of the IPv6 source prefixes that share a
+	// rate limit. [Default: 64]
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	BPFXDPRateLimitPrefixLengthV6 *int `json:"bpfXDPRateLimitPrefixLengthV6,omitempty" validate:"omitempty,gte=0,lte=128"`
+
+	// BPFXDPBlocklistSelector selects the GlobalNetworkSets whose nets are blocked by the DDoS
+	// protection of the host endpoints, for example `ddos-blocklist == 'true'`.  The sets can be
+	// updated at any time.  Endpoints and namespaced NetworkSets that match the selector are
+	// ignored, and the traffic from the hosts and the workloads of the cluster is never
+	// blocked. [Default: ""]
+	BPFXDPBlocklistSelector string `json:"bpfXDPBlocklistSelector,omitempty" validate:"omitempty,selector"`
+
 	// RouteSource configures where Felix gets its routing information.
 	// - WorkloadIPs: use workload endpoints to construct routes.
 	// - CalicoIPAM: the default - use IPAM data to construct routes.
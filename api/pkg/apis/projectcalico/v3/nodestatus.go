@@ -76,6 +76,9 @@ type CalicoNodeStatusStatus struct {
 
 	// Routes reports routes known to the Calico BGP daemon on the node.
 	Routes CalicoNodeBGPRouteStatus `json:"routes,omitempty"`
+
+	// Services reports the service IPs that the node advertises, or has chosen not to advertise.
+	Services CalicoNodeServiceStatus `json:"services,omitempty"`
 }
 
 // CalicoNodeAgentStatus defines the observed state of agent status on the node.
@@ -106,6 +109,21 @@ type CalicoNodeBGPStatus struct {
 
 	// PeersV6 represents IPv6 BGP peers status on the node.
 	PeersV6 []CalicoNodePeer `json:"peersV6,omitempty"`
+
+	// GracefulShutdown reports the progress of a BGP graceful shutdown of the node,
+	// if one has been requested.
+	GracefulShutdown *CalicoNodeBGPGracefulShutdown `json:"gracefulShutdown,omitempty"`
+}
+
+// CalicoNodeBGPGracefulShutdown contains the status of a BGP graceful shutdown (RFC 8326)
+// of the node.
+type CalicoNodeBGPGracefulShutdown struct {
+	// State is Draining while the node advertises its routes with the GRACEFUL_SHUTDOWN
+	// community, and Complete once its BGP sessions have been shut down.
+	State BGPGracefulShutdownState `json:"state"`
+
+	// TeardownTime is the time at which the BGP sessions of the node are shut down.
+	TeardownTime metav1.Time `json:"teardownTime"`
 }
 
 // CalicoNodeBGPRouteStatus defines the observed state of routes status on the node.
@@ -117,6 +135,15 @@ type CalicoNodeBGPRouteStatus struct {
 	RoutesV6 []CalicoNodeRoute `json:"routesV6,omitempty"`
 }
 
+// CalicoNodeServiceStatus defines the observed state of service IP advertisement on the node.
+type CalicoNodeServiceStatus struct {
+	// ServicesV4 represents the IPv4 service IPs considered for advertisement by the node.
+	ServicesV4 []CalicoNodeServiceAdvertisement `json:"servicesV4,omitempty"`
+
+	// ServicesV6 represents the IPv6 service IPs considered for advertisement by the node.
+	ServicesV6 []CalicoNodeServiceAdvertisement `json:"servicesV6,omitempty"`
+}
+
 // BGPDaemonStatus defines the observed state of BGP daemon.
 type BGPDaemonStatus struct {
 	// The state of the BGP Daemon.
@@ -178,6 +205,24 @@ type CalicoNodeRouteLearnedFrom struct {
 	PeerIP string `json:"peerIP,omitempty" validate:"omitempty,ip"`
 }
 
+// CalicoNodeServiceAdvertisement contains the advertisement status of a single service IP on the node.
+type CalicoNodeServiceAdvertisement struct {
+	// Service is the namespace and name of the service that owns the IP.
+	Service string `json:"service,omitempty"`
+
+	// Destination is the route (in CIDR notation) for the service IP.
+	Destination string `json:"destination,omitempty"`
+
+	// Type of the service IP.
+	Type ServiceIPType `json:"type,omitempty"`
+
+	// Advertised is true if the node is advertising the route for the service IP.
+	Advertised bool `json:"advertised"`
+
+	// Reason explains why the service IP is, or is not, being advertised by the node.
+	Reason string `json:"reason,omitempty"`
+}
+
 // NewCalicoNodeStatus creates a new (zeroed) CalicoNodeStatus struct with the TypeMetadata initialised to the current
 // version.
 func NewCalicoNodeStatus() *CalicoNodeStatus {
@@ -209,9 +254,18 @@ const (
 type NodeStatusClassType string
 
 const (
-	NodeStatusClassTypeAgent  NodeStatusClassType = "Agent"
-	NodeStatusClassTypeBGP    NodeStatusClassType = "BGP"
-	NodeStatusClassTypeRoutes NodeStatusClassType = "Routes"
+	NodeStatusClassTypeAgent    NodeStatusClassType = "Agent"
+	NodeStatusClassTypeBGP      NodeStatusClassType = "BGP"
+	NodeStatusClassTypeRoutes   NodeStatusClassType = "Routes"
+	NodeStatusClassTypeServices NodeStatusClassType = "Services"
+)
+
+type ServiceIPType string
+
+const (
+	ServiceIPTypeClusterIP      ServiceIPType = "ClusterIP"
+	ServiceIPTypeExternalIP     ServiceIPType = "ExternalIP"
+	ServiceIPTypeLoadBalancerIP ServiceIPType = "LoadBalancerIP"
 )
 
 type BGPPeerType string
@@ -240,3 +294,10 @@ const (
 	BGPSessionStateEstablished BGPSessionState = "Established"
 	BGPSessionStateClose       BGPSessionState = "Close"
 )
+
+type BGPGracefulShutdownState string
+
+const (
+	BGPGracefulShutdownStateDraining BGPGracefulShutdownState = "Draining"
+	BGPGracefulShutdownStateComplete BGPGracefulShutdownState = "Complete"
+)
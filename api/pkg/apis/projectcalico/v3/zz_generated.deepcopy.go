@@ -593,6 +593,23 @@ func (in *CalicoNodeAgentStatus) DeepCopy() *CalicoNodeAgentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeBGPGracefulShutdown) DeepCopyInto(out *CalicoNodeBGPGracefulShutdown) {
+	*out = *in
+	in.TeardownTime.DeepCopyInto(&out.TeardownTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeBGPGracefulShutdown.
+func (in *CalicoNodeBGPGracefulShutdown) DeepCopy() *CalicoNodeBGPGracefulShutdown {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeBGPGracefulShutdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoNodeBGPRouteStatus) DeepCopyInto(out *CalicoNodeBGPRouteStatus) {
 	*out = *in
@@ -632,6 +649,11 @@ func (in *CalicoNodeBGPStatus) DeepCopyInto(out *CalicoNodeBGPStatus) {
 		*out = make([]CalicoNodePeer, len(*in))
 		copy(*out, *in)
 	}
+	if in.GracefulShutdown != nil {
+		in, out := &in.GracefulShutdown, &out.GracefulShutdown
+		*out = new(CalicoNodeBGPGracefulShutdown)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -694,6 +716,48 @@ func (in *CalicoNodeRouteLearnedFrom) DeepCopy() *CalicoNodeRouteLearnedFrom {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeServiceAdvertisement) DeepCopyInto(out *CalicoNodeServiceAdvertisement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeServiceAdvertisement.
+func (in *CalicoNodeServiceAdvertisement) DeepCopy() *CalicoNodeServiceAdvertisement {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeServiceAdvertisement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoNodeServiceStatus) DeepCopyInto(out *CalicoNodeServiceStatus) {
+	*out = *in
+	if in.ServicesV4 != nil {
+		in, out := &in.ServicesV4, &out.ServicesV4
+		*out = make([]CalicoNodeServiceAdvertisement, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServicesV6 != nil {
+		in, out := &in.ServicesV6, &out.ServicesV6
+		*out = make([]CalicoNodeServiceAdvertisement, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoNodeServiceStatus.
+func (in *CalicoNodeServiceStatus) DeepCopy() *CalicoNodeServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoNodeServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CalicoNodeStatus) DeepCopyInto(out *CalicoNodeStatus) {
 	*out = *in
@@ -788,6 +852,7 @@ func (in *CalicoNodeStatusStatus) DeepCopyInto(out *CalicoNodeStatusStatus) {
 	out.Agent = in.Agent
 	in.BGP.DeepCopyInto(&out.BGP)
 	in.Routes.DeepCopyInto(&out.Routes)
+	in.Services.DeepCopyInto(&out.Services)
 	return
 }
 
@@ -1260,6 +1325,11 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PrometheusBPFWorkloadMetricsEnabled != nil {
+		in, out := &in.PrometheusBPFWorkloadMetricsEnabled, &out.PrometheusBPFWorkloadMetricsEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.FailsafeInboundHostPorts != nil {
 		in, out := &in.FailsafeInboundHostPorts, &out.FailsafeInboundHostPorts
 		*out = new([]ProtoPort)
@@ -1529,6 +1599,36 @@ func (in *FelixConfigurationSpec) DeepCopyInto(out *FelixConfigurationSpec) {
 			copy(*out, *in)
 		}
 	}
+	if in.BPFEventsSampleRate != nil {
+		in, out := &in.BPFEventsSampleRate, &out.BPFEventsSampleRate
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFEgressBandwidthFQEnabled != nil {
+		in, out := &in.BPFEgressBandwidthFQEnabled, &out.BPFEgressBandwidthFQEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BPFXDPSYNRateLimit != nil {
+		in, out := &in.BPFXDPSYNRateLimit, &out.BPFXDPSYNRateLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFXDPUDPRateLimit != nil {
+		in, out := &in.BPFXDPUDPRateLimit, &out.BPFXDPUDPRateLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFXDPRateLimitPrefixLengthV4 != nil {
+		in, out := &in.BPFXDPRateLimitPrefixLengthV4, &out.BPFXDPRateLimitPrefixLengthV4
+		*out = new(int)
+		**out = **in
+	}
+	if in.BPFXDPRateLimitPrefixLengthV6 != nil {
+		in, out := &in.BPFXDPRateLimitPrefixLengthV6, &out.BPFXDPRateLimitPrefixLengthV6
+		*out = new(int)
+		**out = **in
+	}
 	if in.RouteTableRanges != nil {
 		in, out := &in.RouteTableRanges, &out.RouteTableRanges
 		*out = new(RouteTableRanges)
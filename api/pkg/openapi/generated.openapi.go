@@ -37,11 +37,14 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.BlockAffinityList":                  schema_pkg_apis_projectcalico_v3_BlockAffinityList(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.BlockAffinitySpec":                  schema_pkg_apis_projectcalico_v3_BlockAffinitySpec(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeAgentStatus":              schema_pkg_apis_projectcalico_v3_CalicoNodeAgentStatus(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPGracefulShutdown":      schema_pkg_apis_projectcalico_v3_CalicoNodeBGPGracefulShutdown(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPRouteStatus":           schema_pkg_apis_projectcalico_v3_CalicoNodeBGPRouteStatus(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPStatus":                schema_pkg_apis_projectcalico_v3_CalicoNodeBGPStatus(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodePeer":                     schema_pkg_apis_projectcalico_v3_CalicoNodePeer(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeRoute":                    schema_pkg_apis_projectcalico_v3_CalicoNodeRoute(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeRouteLearnedFrom":         schema_pkg_apis_projectcalico_v3_CalicoNodeRouteLearnedFrom(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceAdvertisement":     schema_pkg_apis_projectcalico_v3_CalicoNodeServiceAdvertisement(ref),
+		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceStatus":            schema_pkg_apis_projectcalico_v3_CalicoNodeServiceStatus(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeStatus":                   schema_pkg_apis_projectcalico_v3_CalicoNodeStatus(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeStatusList":               schema_pkg_apis_projectcalico_v3_CalicoNodeStatusList(ref),
 		"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeStatusSpec":               schema_pkg_apis_projectcalico_v3_CalicoNodeStatusSpec(ref),
@@ -1424,6 +1427,37 @@ func schema_pkg_apis_projectcalico_v3_CalicoNodeAgentStatus(ref common.Reference
 	}
 }
 
+func schema_pkg_apis_projectcalico_v3_CalicoNodeBGPGracefulShutdown(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CalicoNodeBGPGracefulShutdown contains the status of a BGP graceful shutdown (RFC 8326) of the node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is Draining while the node advertises its routes with the GRACEFUL_SHUTDOWN community, and Complete once its BGP sessions have been shut down.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"teardownTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TeardownTime is the time at which the BGP sessions of the node are shut down.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"state", "teardownTime"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_projectcalico_v3_CalicoNodeBGPRouteStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1534,12 +1568,18 @@ func schema_pkg_apis_projectcalico_v3_CalicoNodeBGPStatus(ref common.ReferenceCa
 							},
 						},
 					},
+					"gracefulShutdown": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GracefulShutdown reports the progress of a BGP graceful shutdown of the node, if one has been requested.",
+							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPGracefulShutdown"),
+						},
+					},
 				},
 				Required: []string{"numberEstablishedV4", "numberNotEstablishedV4", "numberEstablishedV6", "numberNotEstablishedV6"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodePeer"},
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPGracefulShutdown", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodePeer"},
 	}
 }
 
@@ -1661,6 +1701,99 @@ func schema_pkg_apis_projectcalico_v3_CalicoNodeRouteLearnedFrom(ref common.Refe
 	}
 }
 
+func schema_pkg_apis_projectcalico_v3_CalicoNodeServiceAdvertisement(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CalicoNodeServiceAdvertisement contains the advertisement status of a single service IP on the node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service is the namespace and name of the service that owns the IP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is the route (in CIDR notation) for the service IP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the service IP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"advertised": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Advertised is true if the node is advertising the route for the service IP.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reason explains why the service IP is, or is not, being advertised by the node.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"advertised"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_projectcalico_v3_CalicoNodeServiceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CalicoNodeServiceStatus defines the observed state of service IP advertisement on the node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"servicesV4": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServicesV4 represents the IPv4 service IPs considered for advertisement by the node.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceAdvertisement"),
+									},
+								},
+							},
+						},
+					},
+					"servicesV6": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServicesV6 represents the IPv6 service IPs considered for advertisement by the node.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceAdvertisement"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceAdvertisement"},
+	}
+}
+
 func schema_pkg_apis_projectcalico_v3_CalicoNodeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1832,11 +1965,18 @@ func schema_pkg_apis_projectcalico_v3_CalicoNodeStatusStatus(ref common.Referenc
 							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPRouteStatus"),
 						},
 					},
+					"services": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Services reports the service IPs that the node advertises, or has chosen not to advertise.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeAgentStatus", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPRouteStatus", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeAgentStatus", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPRouteStatus", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeBGPStatus", "github.com/projectcalico/api/pkg/apis/projectcalico/v3.CalicoNodeServiceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -2697,6 +2837,13 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"prometheusBPFWorkloadMetricsEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PrometheusBPFWorkloadMetricsEnabled controls whether the BPF packet counters, which are reported per interface, hook and reason, are also labelled with the workload that owns the interface. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"failsafeInboundHostPorts": {
 						SchemaProps: spec.SchemaProps{
 							Description: "FailsafeInboundHostPorts is a list of ProtoPort struct objects including UDP/TCP/SCTP ports and CIDRs that Felix will allow incoming traffic to host endpoints on irrespective of the security policy. This is useful to avoid accidentally cutting off a host with incorrect configuration. For backwards compatibility, if the protocol is not specified, it defaults to \"tcp\". If a CIDR is not specified, it will allow traffic from all addresses. To disable all inbound host ports, use the value \"[]\". The default value allows ssh access, DHCP, BGP, etcd and the Kubernetes API. [Default: tcp:22, udp:68, tcp:179, tcp:2379, tcp:2380, tcp:5473, tcp:6443, tcp:6666, tcp:6667 ]",
@@ -3101,7 +3248,7 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 					},
 					"bpfMapSizeNATBackend": {
 						SchemaProps: spec.SchemaProps{
-							Description: "BPFMapSizeNATBackend sets the size for NAT back end map. This is the total number of endpoints. This is mostly more than the size of the number of services.",
+							Description: "BPFMapSizeNATBackend sets the size for NAT back end map. This is the total number of endpoints. This is mostly more than the size of the number of services. Each service that uses Maglev load balancing needs a further 1009 entries for its lookup table; if they do not fit, the lookup table is not written and the service's backends are picked at random.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
@@ -3213,6 +3360,69 @@ func schema_pkg_apis_projectcalico_v3_FelixConfigurationSpec(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"bpfEventsSampleRate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFEventsSampleRate controls the stream of events that the BPF programs emit when they drop a packet because of policy or a failed RPF check, or when a packet is sent to a service that has no backends. One in every BPFEventsSampleRate events is emitted.  The events can be followed with `calico-bpf events --follow`.  Zero disables the events. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfEgressBandwidthFQEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFEgressBandwidthFQEnabled controls whether Felix replaces the root qdisc of the host's data interfaces with fq while a workload has an egress bandwidth limit.  The BPF programs pace the packets of the limited workloads by setting their departure time, which only fq respects; without it, the limit is only enforced by dropping the packets that would have to wait too long.  Felix restores the previous root qdisc when no workload has an egress limit any more.  The interfaces of the workloads with an ingress limit always get fq. [Default: false]",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"bpfXDPDDoSProtection": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPDDoSProtection enables the DDoS protection of the host endpoints in XDP.  It drops the packets from the sources in the blocklist, and the SYNs and UDP packets above the rate limits of their source prefix.  The traffic from the hosts and the workloads of the cluster is not rate limited.  The counters of the dropped packets are shown by `calico-bpf counters dump` and exported as Prometheus metrics. [Default: Disabled]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfXDPSYNRateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPSYNRateLimit is the number of TCP SYNs per second that the host endpoints accept from a source prefix when BPFXDPDDoSProtection is enabled.  Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfXDPUDPRateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPUDPRateLimit is the number of UDP packets per second that the host endpoints accept from a source prefix when BPFXDPDDoSProtection is enabled.  Zero means no limit. [Default: 0]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfXDPSYNCookies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPSYNCookies controls whether the SYNs above BPFXDPSYNRateLimit that are sent to a listening socket of the host are answered with a SYN cookie from XDP, rather than dropped. Only IPv4 is supported.  The kernel only generates and accepts the cookies with the net.ipv4.tcp_syncookies sysctl enabled, so Felix sets it to 1 if it is 0. [Default: Disabled]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"bpfXDPRateLimitPrefixLengthV4": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPRateLimitPrefixLengthV4 is the length of the IPv4 source prefixes that share a rate limit. [Default: 32]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfXDPRateLimitPrefixLengthV6": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPRateLimitPrefixLengthV6 is the length of the IPv6 source prefixes that share a rate limit. [Default: 64]",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bpfXDPBlocklistSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BPFXDPBlocklistSelector selects the GlobalNetworkSets whose nets are blocked by the DDoS protection of the host endpoints, for example `ddos-blocklist == 'true'`.  The sets can be updated at any time.  Endpoints and namespaced NetworkSets that match the selector are ignored, and the traffic from the hosts and the workloads of the cluster is never blocked. [Default: \"\"]",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"routeSource": {
 						SchemaProps: spec.SchemaProps{
 							Description: "RouteSource configures where Felix gets its routing information. - WorkloadIPs: use workload endpoints to construct routes. - CalicoIPAM: the default - use IPAM data to construct routes.",
@@ -41,6 +41,8 @@ func main() {
     get          Get a resource identified by file, directory, stdin or resource type and
                  name.
     label        Add or update labels of resources.
+    diff         Show the changes that applying a resource by file, directory or stdin
+                 would make.
     convert      Convert config files between different API versions.
     ipam         IP address management.
     node         Calico node management.
@@ -121,6 +123,8 @@ Description:
 			err = commands.Get(args)
 		case "label":
 			err = commands.Label(args)
+		case "diff":
+			err = commands.Diff(args)
 		case "convert":
 			err = commands.Convert(args)
 		case "version":
@@ -22,6 +22,7 @@ import (
 	"github.com/docopt/docopt-go"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/argutils"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
@@ -29,7 +30,7 @@ import (
 
 func Apply(args []string) error {
 	doc := constants.DatastoreIntro + `Usage:
-  <BINARY_NAME> apply --filename=<FILENAME> [--recursive] [--skip-empty]
+  <BINARY_NAME> apply --filename=<FILENAME> [--recursive] [--skip-empty] [--dry-run]
                   [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>] [--allow-version-mismatch]
 
 Examples:
@@ -39,6 +40,9 @@ Examples:
   # Apply a policy based on the JSON passed into stdin.
   cat policy.json | <BINARY_NAME> apply -f -
 
+  # Check which of the resources in a directory would be created or updated.
+  <BINARY_NAME> apply -f ./manifests/ --dry-run
+
 Options:
   -h --help                    Show this screen.
   -f --filename=<FILENAME>     Filename to use to apply the resource.  If set to
@@ -48,6 +52,8 @@ Options:
   -R --recursive               Process the filename specified in -f or --filename recursively.
      --skip-empty              Do not error if any files or directory specified using -f or --filename contain no
                                data.
+     --dry-run                 Validate the resources and report whether each one would
+                               be created, updated or unchanged, without applying them.
   -c --config=<CONFIG>         Path to the file containing connection
                                configuration in YAML or JSON format.
                                [default: ` + constants.DefaultConfigPath + `]
@@ -80,6 +86,11 @@ Description:
   When applying a resource to perform an update, the complete resource spec
   must be provided, it is not sufficient to supply only the fields that are
   being updated.
+
+  With --dry-run, nothing is written to the datastore.  Each resource is
+  validated and compared with the resource in the datastore in the same way as
+  the diff command, and the command reports whether it would be created,
+  updated or left unchanged.  Use the diff command to see the changes.
 `
 	// Replace all instances of BINARY_NAME with the name of the binary.
 	name, _ := util.NameAndDescription()
@@ -99,6 +110,10 @@ Description:
 		os.Setenv("K8S_CURRENT_CONTEXT", context.(string))
 	}
 
+	if argutils.ArgBoolOrFalse(parsedArgs, "--dry-run") {
+		return applyDryRun(parsedArgs)
+	}
+
 	results := common.ExecuteConfigCommand(parsedArgs, common.ActionApply)
 	log.Infof("results: %+v", results)
 
@@ -140,3 +155,34 @@ Description:
 
 	return nil
 }
+
+// applyDryRun validates the resources and reports what applying them would do, without
+// modifying the datastore.
+func applyDryRun(parsedArgs map[string]interface{}) error {
+	results := common.ExecuteConfigCommand(parsedArgs, common.ActionDiff)
+	log.Infof("results: %+v", results)
+
+	if results.FileInvalid {
+		return fmt.Errorf("Failed to execute command: %v", results.Err)
+	} else if results.Err != nil {
+		return results.Err
+	} else if results.NumResources == 0 {
+		fmt.Println("No resources specified")
+		return nil
+	}
+
+	counts := map[common.ChangeType]int{}
+	for _, d := range results.Diffs {
+		change := d.Change()
+		counts[change]++
+		fmt.Printf("%s %s (dry run)\n", d.Identifier(), change)
+	}
+	fmt.Printf("Dry run: %d resource(s) would be created, %d updated and %d unchanged\n",
+		counts[common.ChangeCreate], counts[common.ChangeUpdate], counts[common.ChangeUnchanged])
+
+	if len(results.ResErrs) > 0 {
+		return fmt.Errorf("Dry run failed for %d out of %d resource(s): %v",
+			len(results.ResErrs), results.NumResources, results.ResErrs)
+	}
+	return nil
+}
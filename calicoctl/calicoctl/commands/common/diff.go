@@ -0,0 +1,184 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pmezard/go-difflib/difflib"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/go-yaml-wrapper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	calicoErrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/names"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	validator "github.com/projectcalico/calico/libcalico-go/lib/validator/v3"
+)
+
+// ChangeType is the change that applying a resource would make to the datastore.
+type ChangeType string
+
+const (
+	ChangeCreate    ChangeType = "created"
+	ChangeUpdate    ChangeType = "updated"
+	ChangeUnchanged ChangeType = "unchanged"
+)
+
+// ResourceDiff describes the change that applying a resource would make to the datastore.
+type ResourceDiff struct {
+	// The resource from the file, with defaults filled in.
+	Resource resourcemgr.ResourceObject
+
+	// The resource in the datastore, or nil if it does not exist.
+	Live resourcemgr.ResourceObject
+
+	// A unified diff between the normalized live and new resources, or blank if
+	// they are the same.
+	Diff string
+}
+
+// Change returns whether applying the resource would create, update or leave unchanged the
+// resource in the datastore.
+func (d ResourceDiff) Change() ChangeType {
+	switch {
+	case d.Live == nil:
+		return ChangeCreate
+	case d.Diff != "":
+		return ChangeUpdate
+	default:
+		return ChangeUnchanged
+	}
+}
+
+// Identifier returns the kind, namespace and name of the resource, for use in messages.
+func (d ResourceDiff) Identifier() string {
+	return resourceIdentifier(d.Resource)
+}
+
+func resourceIdentifier(r resourcemgr.ResourceObject) string {
+	kind := r.GetObjectKind().GroupVersionKind().Kind
+	if ns := r.GetObjectMeta().GetNamespace(); ns != "" {
+		return fmt.Sprintf("%s(%s/%s)", kind, ns, r.GetObjectMeta().GetName())
+	}
+	return fmt.Sprintf("%s(%s)", kind, r.GetObjectMeta().GetName())
+}
+
+// diffResource validates the resource and compares it with the live resource in the datastore,
+// without modifying the datastore.
+func diffResource(args map[string]interface{}, client client.Interface, resource resourcemgr.ResourceObject) (ResourceDiff, error) {
+	rm := resourcemgr.GetResourceManager(resource)
+	if err := handleNamespace(resource, rm, args); err != nil {
+		return ResourceDiff{}, err
+	}
+	if resource.GetObjectMeta().GetName() == "" {
+		return ResourceDiff{}, fmt.Errorf("resource name may not be empty")
+	}
+
+	// The client defaults some fields before validating the resource, so do the same.
+	desired := resource.DeepCopyObject().(resourcemgr.ResourceObject)
+	setDefaults(desired)
+	if err := validator.Validate(desired); err != nil {
+		return ResourceDiff{}, err
+	}
+	d := ResourceDiff{Resource: desired}
+
+	live, err := rm.GetOrList(context.Background(), client, resource)
+	if err != nil {
+		if _, ok := err.(calicoErrors.ErrorResourceDoesNotExist); !ok {
+			return ResourceDiff{}, err
+		}
+	} else {
+		d.Live = live.(resourcemgr.ResourceObject)
+	}
+
+	d.Diff, err = unifiedDiff(d.Live, desired)
+	if err != nil {
+		return ResourceDiff{}, err
+	}
+	return d, nil
+}
+
+// unifiedDiff returns a unified diff between the YAML of the normalized live and desired
+// resources.  A nil live resource is treated as empty.
+func unifiedDiff(live, desired resourcemgr.ResourceObject) (string, error) {
+	id := resourceIdentifier(desired)
+	from, to := "/dev/null", "file/"+id
+	var liveYAML, desiredYAML []byte
+	var err error
+	if live != nil {
+		from = "live/" + id
+		liveYAML, err = yaml.Marshal(normalize(live, desired))
+		if err != nil {
+			return "", err
+		}
+	}
+	desiredYAML, err = yaml.Marshal(normalize(desired, desired))
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(liveYAML)),
+		B:        difflib.SplitLines(string(desiredYAML)),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+}
+
+// normalize returns a copy of the resource with the server-populated fields removed and the
+// client-defaulted fields filled in, so that it can be compared with a resource from a file.
+// As with apply, only the name, namespace, labels and annotations are kept from the metadata.
+func normalize(r, desired resourcemgr.ResourceObject) resourcemgr.ResourceObject {
+	out := r.DeepCopyObject().(resourcemgr.ResourceObject)
+	out.GetObjectKind().SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+	rom := r.GetObjectMeta()
+	meta := &v1.ObjectMeta{
+		Name:        rom.GetName(),
+		Namespace:   rom.GetNamespace(),
+		Labels:      rom.GetLabels(),
+		Annotations: rom.GetAnnotations(),
+	}
+	meta.DeepCopyInto(out.GetObjectMeta().(*v1.ObjectMeta))
+
+	// Status is always written by Calico components rather than by users.
+	if status := reflect.ValueOf(out).Elem().FieldByName("Status"); status.IsValid() && status.CanSet() {
+		status.Set(reflect.Zero(status.Type()))
+	}
+
+	setDefaults(out)
+	return out
+}
+
+// setDefaults fills in the fields that the Calico client defaults when it writes a resource,
+// so that omitting them from a file does not show up as a difference.
+func setDefaults(r resourcemgr.ResourceObject) {
+	switch r := r.(type) {
+	case *apiv3.GlobalNetworkPolicy:
+		r.Spec.Tier = names.TierOrDefault(r.Spec.Tier)
+	case *apiv3.NetworkPolicy:
+		r.Spec.Tier = names.TierOrDefault(r.Spec.Tier)
+	case *apiv3.IPPool:
+		// The client also normalizes the CIDR as part of its validation.
+		if _, cidr, err := cnet.ParseCIDR(r.Spec.CIDR); err == nil {
+			r.Spec.CIDR = cidr.String()
+		}
+	}
+	client.ApplyDefaults(r)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Testing unifiedDiff", func() {
+	var desired, live *apiv3.GlobalNetworkPolicy
+
+	BeforeEach(func() {
+		// The policy as written in a file, relying on defaults for the tier and types.
+		desired = apiv3.NewGlobalNetworkPolicy()
+		desired.Name = "payments"
+		desired.Labels = map[string]string{"team": "payments"}
+		desired.Spec.Selector = "app == 'payments'"
+		desired.Spec.Ingress = []apiv3.Rule{{Action: apiv3.Allow}}
+
+		// The same policy as read back from the datastore.
+		live = desired.DeepCopy()
+		live.TypeMeta = metav1.TypeMeta{}
+		live.UID = types.UID("f1e2d3c4")
+		live.ResourceVersion = "1234"
+		live.CreationTimestamp = metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		live.Labels = map[string]string{"team": "payments", apiv3.LabelTier: "default"}
+		live.Spec.Tier = "default"
+		live.Spec.Types = []apiv3.PolicyType{apiv3.PolicyTypeIngress}
+	})
+
+	It("should ignore server-populated and defaulted fields", func() {
+		d, err := unifiedDiff(live, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(BeEmpty())
+		Expect(ResourceDiff{Resource: desired, Live: live, Diff: d}.Change()).To(Equal(ChangeUnchanged))
+	})
+
+	It("should show changes to the spec and labels", func() {
+		desired.Labels["owner"] = "alice"
+		desired.Spec.Selector = "app == 'checkout'"
+
+		d, err := unifiedDiff(live, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(HavePrefix("--- live/GlobalNetworkPolicy(payments)\n+++ file/GlobalNetworkPolicy(payments)\n"))
+		Expect(d).To(ContainSubstring("+    owner: alice\n"))
+		Expect(d).To(ContainSubstring("-  selector: app == 'payments'\n"))
+		Expect(d).To(ContainSubstring("+  selector: app == 'checkout'\n"))
+		Expect(d).NotTo(ContainSubstring("resourceVersion"))
+		Expect(ResourceDiff{Resource: desired, Live: live, Diff: d}.Change()).To(Equal(ChangeUpdate))
+	})
+
+	It("should show explicitly removed fields", func() {
+		desired.Spec.Ingress = nil
+		desired.Spec.Egress = []apiv3.Rule{{Action: apiv3.Deny}}
+
+		d, err := unifiedDiff(live, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(ContainSubstring("-  ingress:\n"))
+		Expect(d).To(ContainSubstring("+  egress:\n"))
+		Expect(d).To(ContainSubstring("-  - Ingress\n+  - Egress\n"))
+	})
+
+	It("should diff a new resource against nothing", func() {
+		d, err := unifiedDiff(nil, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(HavePrefix("--- /dev/null\n+++ file/GlobalNetworkPolicy(payments)\n"))
+		Expect(d).To(ContainSubstring("+kind: GlobalNetworkPolicy\n"))
+		Expect(ResourceDiff{Resource: desired, Diff: d}.Change()).To(Equal(ChangeCreate))
+	})
+})
+
+var _ = Describe("Testing setDefaults", func() {
+	It("should default IP pool fields in the same way as the client", func() {
+		pool := apiv3.NewIPPool()
+		pool.Name = "pool"
+		pool.Spec.CIDR = "10.1.2.3/16"
+		setDefaults(pool)
+		Expect(pool.Spec.CIDR).To(Equal("10.1.0.0/16"))
+		Expect(pool.Spec.BlockSize).To(Equal(26))
+		Expect(pool.Spec.NodeSelector).To(Equal("all()"))
+		Expect(pool.Spec.AllowedUses).To(ConsistOf(apiv3.IPPoolAllowedUseWorkload, apiv3.IPPoolAllowedUseTunnel))
+		Expect(pool.Spec.IPIPMode).To(Equal(apiv3.IPIPModeNever))
+		Expect(pool.Spec.VXLANMode).To(Equal(apiv3.VXLANModeNever))
+
+		pool.Spec.CIDR = "fd00::/64"
+		pool.Spec.BlockSize = 0
+		setDefaults(pool)
+		Expect(pool.Spec.BlockSize).To(Equal(122))
+	})
+
+	It("should not override fields that are set", func() {
+		pool := apiv3.NewIPPool()
+		pool.Spec.CIDR = "10.1.0.0/16"
+		pool.Spec.BlockSize = 28
+		pool.Spec.NodeSelector = "zone == 'a'"
+		pool.Spec.VXLANMode = apiv3.VXLANModeCrossSubnet
+		setDefaults(pool)
+		Expect(pool.Spec.BlockSize).To(Equal(28))
+		Expect(pool.Spec.NodeSelector).To(Equal("zone == 'a'"))
+		Expect(pool.Spec.VXLANMode).To(Equal(apiv3.VXLANModeCrossSubnet))
+	})
+})
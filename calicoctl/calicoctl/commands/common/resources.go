@@ -32,6 +32,7 @@ import (
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	calicoErrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
 )
 
 type action int
@@ -43,6 +44,7 @@ const (
 	ActionDelete
 	ActionGetOrList
 	ActionPatch
+	ActionDiff
 )
 
 // Convert loaded resources to a slice of resources for easier processing.
@@ -105,6 +107,9 @@ type CommandResults struct {
 	// Errors associated with individual resources
 	ResErrs []error
 
+	// The differences between each resource and the datastore (diff action only).
+	Diffs []ResourceDiff
+
 	// The Calico API client used for the requests (useful if required
 	// again).
 	Client client.Interface
@@ -115,7 +120,7 @@ type fileError struct {
 }
 
 // ExecuteConfigCommand is main function called by all of the resource management commands
-// in calicoctl (apply, create, replace, get, delete and patch).  This provides common function
+// in calicoctl (apply, create, replace, get, delete, patch and diff).  This provides common function
 // for all these commands:
 //   - Load resources from file (or if not specified determine the resource from
 //     the command line options).
@@ -124,6 +129,7 @@ type fileError struct {
 //     the client interface, collate results and exit on the first error.
 func ExecuteConfigCommand(args map[string]interface{}, action action) CommandResults {
 	var resources []resourcemgr.ResourceObject
+	var sel selector.Selector
 
 	singleKind := false
 
@@ -190,6 +196,11 @@ func ExecuteConfigCommand(args map[string]interface{}, action action) CommandRes
 			return CommandResults{Err: err}
 		}
 
+		sel, err = selectorFromArgs(args)
+		if err != nil {
+			return CommandResults{Err: err}
+		}
+
 		if len(resources) == 0 {
 			// No resources specified on non-file input is always an error.
 			return CommandResults{
@@ -219,6 +230,15 @@ func ExecuteConfigCommand(args map[string]interface{}, action action) CommandRes
 	}
 	log.Infof("Client: %v", cclient)
 
+	// Other than get, commands that take a selector act on each matching resource individually,
+	// so list and filter them up front.
+	if sel != nil && action != ActionGetOrList {
+		resources, err = listMatchingResources(args, cclient, resources, sel)
+		if err != nil {
+			return CommandResults{Err: err, Client: cclient}
+		}
+	}
+
 	// Initialise the command results with the number of resources and the name of the
 	// kind of resource (if only dealing with a single resource).
 	results := CommandResults{Client: cclient}
@@ -263,6 +283,19 @@ func ExecuteConfigCommand(args map[string]interface{}, action action) CommandRes
 	}
 
 	for _, r := range resources {
+		if action == ActionDiff {
+			// Diffs don't modify the datastore, so collect the differences rather than the
+			// resulting resources.
+			d, err := diffResource(args, cclient, r)
+			if err != nil {
+				results.ResErrs = append(results.ResErrs, err)
+				continue
+			}
+			results.Diffs = append(results.Diffs, d)
+			results.NumHandled++
+			continue
+		}
+
 		res, err := ExecuteResourceAction(args, cclient, r, action)
 		if err != nil {
 			switch action {
@@ -274,6 +307,14 @@ func ExecuteConfigCommand(args map[string]interface{}, action action) CommandRes
 			}
 		}
 
+		if sel != nil && action == ActionGetOrList {
+			for i := range res {
+				if err := filterListBySelector(res[i], sel); err != nil {
+					results.ResErrs = append(results.ResErrs, err)
+				}
+			}
+		}
+
 		// Remove the cluster specific metadata if the "--export" flag is specified
 		// Skip removing cluster specific metadata if this is is called as a "list"
 		// operation (no specific name is specified).
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/argutils"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+// selectorFromArgs parses the --selector argument, returning nil if it was not specified.  A selector
+// picks resources by their labels, so it cannot be combined with resource names.
+func selectorFromArgs(args map[string]interface{}) (selector.Selector, error) {
+	s := argutils.ArgStringOrBlank(args, "--selector")
+	if s == "" {
+		return nil, nil
+	}
+	switch n := args["<NAME>"].(type) {
+	case string:
+		if n != "" {
+			return nil, fmt.Errorf("a resource name cannot be specified with --selector")
+		}
+	case []string:
+		if len(n) > 0 {
+			return nil, fmt.Errorf("resource names cannot be specified with --selector")
+		}
+	}
+	sel, err := selector.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %v", s, err)
+	}
+	return sel, nil
+}
+
+// filterListBySelector removes the resources that do not match the selector from the resource
+// list.  None of the datastores can evaluate Calico selectors so this is always done client side,
+// after listing all the resources of the kind.
+func filterListBySelector(list runtime.Object, sel selector.Selector) error {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	matches := []runtime.Object{}
+	for _, item := range items {
+		r, ok := item.(resourcemgr.ResourceObject)
+		if !ok {
+			return fmt.Errorf("unexpected resource type %T in list", item)
+		}
+		if sel.Evaluate(r.GetObjectMeta().GetLabels()) {
+			matches = append(matches, item)
+		}
+	}
+	log.Debugf("%d of %d resources match selector %s", len(matches), len(items), sel.String())
+	return meta.SetList(list, matches)
+}
+
+// listMatchingResources replaces each of the (unnamed) resources from the command line with the
+// resources of that kind that match the selector, so that the action can be applied to each of
+// them individually.
+func listMatchingResources(
+	args map[string]interface{}, client client.Interface, resources []resourcemgr.ResourceObject, sel selector.Selector,
+) ([]resourcemgr.ResourceObject, error) {
+	var matches []resourcemgr.ResourceObject
+	for _, r := range resources {
+		res, err := ExecuteResourceAction(args, client, r, ActionGetOrList)
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range res {
+			if err := filterListBySelector(list, sel); err != nil {
+				return nil, err
+			}
+			converted, err := convertToSliceOfResources(list)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, converted...)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+)
+
+var _ = DescribeTable("Testing selectorFromArgs",
+	func(args map[string]interface{}, expected string, expectedErr string) {
+		sel, err := selectorFromArgs(args)
+		if expectedErr != "" {
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		if expected == "" {
+			Expect(sel).To(BeNil())
+		} else {
+			Expect(sel.String()).To(Equal(expected))
+		}
+	},
+	Entry("no selector", map[string]interface{}{"<NAME>": []string{"foo"}}, "", ""),
+	Entry("selector", map[string]interface{}{"<NAME>": []string{}, "--selector": "team == 'payments'"},
+		`team == "payments"`, ""),
+	Entry("selector without a name argument", map[string]interface{}{"--selector": "has(pci) && tier in {'a', 'b'}"},
+		`(has(pci) && tier in {"a", "b"})`, ""),
+	Entry("selector with names", map[string]interface{}{"<NAME>": []string{"foo"}, "--selector": "has(a)"},
+		"", "resource names cannot be specified with --selector"),
+	Entry("selector with a name", map[string]interface{}{"<NAME>": "foo", "--selector": "has(a)"},
+		"", "a resource name cannot be specified with --selector"),
+	Entry("invalid selector", map[string]interface{}{"--selector": "has(a"},
+		"", `invalid selector "has(a"`),
+)
+
+var _ = Describe("Testing filterListBySelector", func() {
+	gnp := func(name string, labels map[string]string) apiv3.GlobalNetworkPolicy {
+		p := apiv3.NewGlobalNetworkPolicy()
+		p.Name = name
+		p.Labels = labels
+		return *p
+	}
+
+	It("should keep only the resources with matching labels", func() {
+		list := &apiv3.GlobalNetworkPolicyList{}
+		list.Items = []apiv3.GlobalNetworkPolicy{
+			gnp("payments-ingress", map[string]string{"team": "payments", "pci": ""}),
+			gnp("payments-egress", map[string]string{"team": "payments"}),
+			gnp("frontend", map[string]string{"team": "frontend", "pci": ""}),
+			gnp("unlabelled", nil),
+		}
+		sel, err := selectorFromArgs(map[string]interface{}{"--selector": "team == 'payments' || has(pci)"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(filterListBySelector(list, sel)).To(Succeed())
+		var names []string
+		for _, p := range list.Items {
+			names = append(names, p.Name)
+		}
+		Expect(names).To(Equal([]string{"payments-ingress", "payments-egress", "frontend"}))
+	})
+
+	It("should evaluate the selector against workload endpoint labels", func() {
+		list := libapiv3.NewWorkloadEndpointList()
+		wep := libapiv3.NewWorkloadEndpoint()
+		wep.Name = "node1-k8s-web--1-eth0"
+		wep.Namespace = "default"
+		wep.Labels = map[string]string{
+			"app":                            "web",
+			"projectcalico.org/namespace":    "default",
+			"projectcalico.org/orchestrator": "k8s",
+		}
+		list.Items = []libapiv3.WorkloadEndpoint{*wep}
+
+		sel, err := selectorFromArgs(map[string]interface{}{"--selector": "projectcalico.org/namespace == 'default' && app != 'db'"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filterListBySelector(list, sel)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+
+		sel, err = selectorFromArgs(map[string]interface{}{"--selector": "app == 'db'"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filterListBySelector(list, sel)).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"github.com/docopt/docopt-go"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/argutils"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
@@ -30,7 +31,7 @@ import (
 
 func Delete(args []string) error {
 	doc := constants.DatastoreIntro + `Usage:
-  <BINARY_NAME> delete ( (<KIND> [<NAME>...]) |
+  <BINARY_NAME> delete ( (<KIND> [<NAME>...] [--selector=<SELECTOR>]) |
                    --filename=<FILE> [--recursive] [--skip-empty] )
                    [--skip-not-exists] [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>] [--allow-version-mismatch]
 
@@ -44,6 +45,9 @@ Examples:
   # Delete policies with names "foo" and "bar"
   <BINARY_NAME> delete policy foo bar
 
+  # Delete the global network policies labelled team=payments.
+  <BINARY_NAME> delete globalnetworkpolicies -l "team == 'payments'"
+
 Options:
   -h --help                    Show this screen.
   -s --skip-not-exists         Skip over and treat as successful, resources that
@@ -55,6 +59,9 @@ Options:
   -R --recursive               Process the filename specified in -f or --filename recursively.
      --skip-empty              Do not error if any files or directory specified using -f or --filename contain no
                                data.
+  -l --selector=<SELECTOR>     Only delete resources whose labels match the Calico
+                               selector, for example "team == 'payments' && has(pci)".
+                               Cannot be used with <NAME>.
   -c --config=<CONFIG>         Path to the file containing connection
                                configuration in YAML or JSON format.
                                [default: ` + constants.DefaultConfigPath + `]
@@ -81,7 +88,9 @@ Description:
 
   When deleting resources by type, only a single type may be specified at a
   time.  The name is required along with any and other identifiers required to
-  uniquely identify a resource of the specified type.
+  uniquely identify a resource of the specified type.  Alternatively, the
+  --selector option deletes every resource of the specified type (in the
+  namespace, for namespaced types) whose labels match a Calico selector.
 
   The output of the command indicates how many resources were successfully
   deleted, and the error reason if an error occurred.  If the --skip-not-exists
@@ -119,7 +128,11 @@ Description:
 		if results.Err != nil {
 			return results.Err
 		}
-		fmt.Println("No resources specified")
+		if argutils.ArgStringOrBlank(parsedArgs, "--selector") != "" {
+			fmt.Println("No resources match the selector")
+		} else {
+			fmt.Println("No resources specified")
+		}
 	} else if results.Err == nil && results.NumHandled > 0 {
 		if results.SingleKind != "" {
 			fmt.Printf("Successfully deleted %d '%s' resource(s)\n", results.NumHandled, results.SingleKind)
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+)
+
+func Diff(args []string) error {
+	doc := constants.DatastoreIntro + `Usage:
+  <BINARY_NAME> diff --filename=<FILENAME> [--recursive] [--skip-empty]
+                 [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>] [--allow-version-mismatch]
+
+Examples:
+  # Show the changes that applying the resources in policy.yaml would make.
+  <BINARY_NAME> diff -f ./policy.yaml
+
+  # Show the changes that applying a directory of resources would make.
+  <BINARY_NAME> diff -f ./manifests/ -R
+
+Options:
+  -h --help                    Show this screen.
+  -f --filename=<FILENAME>     Filename to use to diff the resource.  If set to
+                               "-" loads from stdin. If filename is a directory, this command is
+                               invoked for each .json .yaml and .yml file within that directory,
+                               terminating after the first failure.
+  -R --recursive               Process the filename specified in -f or --filename recursively.
+     --skip-empty              Do not error if any files or directory specified using -f or --filename contain no
+                               data.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+  -n --namespace=<NS>          Namespace of the resource.
+                               Only applicable to NetworkPolicy, NetworkSet, and WorkloadEndpoint.
+                               Uses the default namespace if not specified.
+     --context=<context>       The name of the kubeconfig context to use.
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  The diff command shows the changes that applying a set of resources by
+  filename or stdin would make to the datastore, without making them.  JSON and
+  YAML formats are accepted.
+
+  Valid resource types are:
+
+<RESOURCE_LIST>
+  Each resource is validated and then compared with the resource of the same
+  type and name in the datastore.  A unified diff is printed for each resource
+  that would be created or updated.
+
+  Before comparing, fields that are populated by the datastore (such as the UID,
+  resource version and creation timestamp, and the status) are removed from
+  the live resource, and fields that Calico fills in with default values when a
+  resource is written are defaulted in the resource from the file.  As with
+  apply, only the labels and annotations are compared from the metadata.
+
+  The command exits with status 0 if applying the resources would not change
+  anything, and status 1 if there are differences or an error occurred.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	// Replace <RESOURCE_LIST> with the list of resource types.
+	doc = strings.Replace(doc, "<RESOURCE_LIST>", util.Resources(), 1)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+	if context := parsedArgs["--context"]; context != nil {
+		os.Setenv("K8S_CURRENT_CONTEXT", context.(string))
+	}
+
+	results := common.ExecuteConfigCommand(parsedArgs, common.ActionDiff)
+	log.Infof("results: %+v", results)
+
+	if results.FileInvalid {
+		return fmt.Errorf("Failed to execute command: %v", results.Err)
+	} else if results.Err != nil {
+		return results.Err
+	} else if results.NumResources == 0 {
+		fmt.Println("No resources specified")
+		return nil
+	}
+
+	numDiffs := 0
+	for _, d := range results.Diffs {
+		if d.Diff == "" {
+			continue
+		}
+		fmt.Print(d.Diff)
+		numDiffs++
+	}
+
+	if len(results.ResErrs) > 0 {
+		var errStr string
+		for _, err := range results.ResErrs {
+			errStr += fmt.Sprintf("Failed to diff resource: %v\n", err)
+		}
+		return errors.New(errStr)
+	} else if numDiffs > 0 {
+		return fmt.Errorf("%d of %d resource(s) differ from the datastore", numDiffs, results.NumResources)
+	}
+	return nil
+}
@@ -31,7 +31,7 @@ import (
 
 func Get(args []string) error {
 	doc := constants.DatastoreIntro + `Usage:
-  <BINARY_NAME> get ( (<KIND> [<NAME>...]) |
+  <BINARY_NAME> get ( (<KIND> [<NAME>...] [--selector=<SELECTOR>]) |
                 --filename=<FILENAME> [--recursive] [--skip-empty] )
                 [--output=<OUTPUT>] [--config=<CONFIG>] [--namespace=<NS>] [--all-namespaces] [--export] [--context=<context>] [--allow-version-mismatch]
 
@@ -42,6 +42,12 @@ Examples:
   # List specific policies in YAML format
   <BINARY_NAME> get -o yaml policy my-policy-1 my-policy-2
 
+  # List the global network policies labelled team=payments.
+  <BINARY_NAME> get globalnetworkpolicies -l "team == 'payments'"
+
+  # List the workload endpoints in all namespaces that match a selector.
+  <BINARY_NAME> get workloadendpoints -A -l "app in {'web', 'api'} && !has(canary)"
+
 Options:
   -h --help                    Show this screen.
   -f --filename=<FILENAME>     Filename to use to get the resource.  If set to
@@ -60,6 +66,9 @@ Options:
   -n --namespace=<NS>          Namespace of the resource.
                                Only applicable to NetworkPolicy, NetworkSet, and WorkloadEndpoint.
                                Uses the default namespace if not specified.
+  -l --selector=<SELECTOR>     Only list resources whose labels match the Calico
+                               selector, for example "team == 'payments' && has(pci)".
+                               Cannot be used with <NAME>.
   -A --all-namespaces          If present, list the requested object(s) across all namespaces.
      --export                  If present, returns the requested object(s) stripped of
                                cluster-specific information. This flag will be ignored
@@ -83,7 +92,9 @@ Description:
   time.  The name and other identifiers (hostname, scope) are optional, and are
   wildcarded when omitted. Thus if you specify no identifiers at all (other
   than type), then all configured resources of the requested type will be
-  returned.
+  returned.  The --selector option narrows this down to the resources whose
+  labels match a selector.  Any Calico selector expression may be used; the
+  selector is evaluated by calicoctl after listing the resources.
 
   By default the results are output in a ps-style table output.  There are
   alternative ways to display the data using the --output option:
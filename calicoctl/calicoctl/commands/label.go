@@ -21,7 +21,9 @@ import (
 
 	docopt "github.com/docopt/docopt-go"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
 
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/argutils"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/resourcemgr"
@@ -34,6 +36,10 @@ func Label(args []string) error {
   	              ( <key>=<value> [--overwrite] |
   	                <key> --remove )
                   [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>]) [--allow-version-mismatch]
+  <BINARY_NAME> label (<KIND> --selector=<SELECTOR>
+  	              ( <key>=<value> [--overwrite] |
+  	                <key> --remove )
+                  [--config=<CONFIG>] [--namespace=<NS>] [--context=<context>]) [--allow-version-mismatch]
 
 
 
@@ -48,11 +54,17 @@ Examples:
   # Remove label with key 'cluster' of the node
   <BINARY_NAME> label nodes node1 cluster --remove
 
+  # Label all the nodes that have the label 'zone' set to 'us-east-1a'
+  <BINARY_NAME> label nodes -l "zone == 'us-east-1a'" rack=r1
+
 Options:
   -h --help                    Show this screen.
   -c --config=<CONFIG>         Path to the file containing connection
                                configuration in YAML or JSON format.
                                [default: ` + constants.DefaultConfigPath + `]
+  -l --selector=<SELECTOR>     Only label resources whose labels match the Calico
+                               selector, for example "team == 'payments' && has(pci)".
+                               Cannot be used with <NAME>.
   -n --namespace=<NS>          Namespace of the resource.
                                Only applicable to NetworkPolicy, NetworkSet, and WorkloadEndpoint.
                                Uses the default namespace if not specified.
@@ -75,6 +87,11 @@ Description:
 
   Attempting to label resources that do not exist will get an error.
 
+  With --selector, every resource of the specified type whose labels match the
+  Calico selector is labeled.  It is an error if no resources match.  If some of
+  the updates fail, the error lists the resources that were labeled and those
+  that were not.
+
   Attempting to remove a label that does not in the resource will get an error.
 
   When labeling a resource on an existing key:
@@ -102,7 +119,10 @@ Description:
 	log.Debugf("parse args: %+v\n", parsedArgs)
 	// get results.
 	kind := parsedArgs["<KIND>"].(string)
-	name := parsedArgs["<NAME>"].(string)
+	target := argutils.ArgStringOrBlank(parsedArgs, "<NAME>")
+	if sel := argutils.ArgStringOrBlank(parsedArgs, "--selector"); sel != "" {
+		target = fmt.Sprintf("matching %q", sel)
+	}
 	// TODO: convert kind into the formal format
 
 	// parse key/value.
@@ -126,58 +146,101 @@ Description:
 		return fmt.Errorf("Failed to execute command: %v", results.Err)
 	} else if results.Err != nil {
 		return fmt.Errorf("failed to get %s %s, error %v",
-			kind, name, results.Err)
-	} else if len(results.Resources) == 0 {
-		return fmt.Errorf("%s %s not found", kind, name)
+			kind, target, results.Err)
 	}
 
-	resource := results.Resources[0].(resourcemgr.ResourceObject)
-	labels := resource.GetObjectMeta().GetLabels()
-	overwrite := parsedArgs["--overwrite"].(bool)
-	overwritten := false
-	client := results.Client
-	if labels == nil {
-		labels = make(map[string]string)
+	// With a selector, the result is a list of the matching resources.
+	var resources []resourcemgr.ResourceObject
+	for _, r := range results.Resources {
+		if !meta.IsListType(r) {
+			resources = append(resources, r.(resourcemgr.ResourceObject))
+			continue
+		}
+		items, err := meta.ExtractList(r)
+		if err != nil {
+			return fmt.Errorf("failed to get %s %s, error %v", kind, target, err)
+		}
+		for _, item := range items {
+			resources = append(resources, item.(resourcemgr.ResourceObject))
+		}
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf("%s %s not found", kind, target)
 	}
 
-	if remove {
-		// remove label.
-		_, ok := labels[key]
-		if !ok {
-			// raise error if the key does not exist.
-			return fmt.Errorf("cannot remove label of %s %s, key %s does not exist",
-				kind, name, key)
-		} else {
-			delete(labels, key)
+	// Check that the label can be applied to all the resources before updating any of them.
+	overwrite := parsedArgs["--overwrite"].(bool)
+	overwritten := false
+	for _, resource := range resources {
+		labels := resource.GetObjectMeta().GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
 		}
-	} else {
-		// add or update label.
-		oldValue, ok := labels[key]
-		if ok {
-			if overwrite || value == oldValue {
-				labels[key] = value
-				overwritten = true
-			} else {
-				return fmt.Errorf("failed to update label of %s %s, key %s is already present. please use '--overwrite' to set a new value.",
+		name := resource.GetObjectMeta().GetName()
+
+		if remove {
+			// remove label.
+			_, ok := labels[key]
+			if !ok {
+				// raise error if the key does not exist.
+				return fmt.Errorf("cannot remove label of %s %s, key %s does not exist",
 					kind, name, key)
+			} else {
+				delete(labels, key)
 			}
 		} else {
-			labels[key] = value
+			// add or update label.
+			oldValue, ok := labels[key]
+			if ok {
+				if overwrite || value == oldValue {
+					labels[key] = value
+					overwritten = true
+				} else {
+					return fmt.Errorf("failed to update label of %s %s, key %s is already present. please use '--overwrite' to set a new value.",
+						kind, name, key)
+				}
+			} else {
+				labels[key] = value
+			}
 		}
+		resource.GetObjectMeta().SetLabels(labels)
 	}
 
-	resource.GetObjectMeta().SetLabels(labels)
-	_, err = common.ExecuteResourceAction(parsedArgs, client, resource, common.ActionUpdate)
-	if err != nil {
-		return fmt.Errorf("failed to update %s %s, label not changed", kind, name)
+	// Carry on after a failure so that, with a selector, we can report which of the resources were
+	// updated and which were not.
+	client := results.Client
+	var updated, failed []string
+	for _, resource := range resources {
+		name := resource.GetObjectMeta().GetName()
+		_, err = common.ExecuteResourceAction(parsedArgs, client, resource, common.ActionUpdate)
+		if err != nil {
+			log.WithError(err).Debugf("Failed to update %s %s", kind, name)
+			failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+			continue
+		}
+		updated = append(updated, name)
+	}
+	if len(resources) == 1 && len(failed) > 0 {
+		return fmt.Errorf("failed to update %s %s, label not changed", kind, resources[0].GetObjectMeta().GetName())
+	} else if len(updated) == 0 {
+		return fmt.Errorf("failed to update %s %s, label not changed on any of them: %s",
+			kind, target, strings.Join(failed, ", "))
+	} else if len(failed) > 0 {
+		return fmt.Errorf("failed to update %d of the %d %s %s, label changed on %s but not on %s",
+			len(failed), len(resources), kind, target, strings.Join(updated, ", "), strings.Join(failed, ", "))
 	}
 
+	if len(resources) > 1 {
+		target = fmt.Sprintf("(%d resources)", len(resources))
+	} else {
+		target = resources[0].GetObjectMeta().GetName()
+	}
 	if remove {
-		fmt.Printf("Successfully removed label %s from %s %s\n", key, kind, name)
+		fmt.Printf("Successfully removed label %s from %s %s\n", key, kind, target)
 	} else if overwritten {
-		fmt.Printf("Successfully updated label %s on %s %s\n", key, kind, name)
+		fmt.Printf("Successfully updated label %s on %s %s\n", key, kind, target)
 	} else {
-		fmt.Printf("Successfully set label %s on %s %s\n", key, kind, name)
+		fmt.Printf("Successfully set label %s on %s %s\n", key, kind, target)
 	}
 	return nil
 }
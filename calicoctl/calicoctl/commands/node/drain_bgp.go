@@ -0,0 +1,132 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docopt/docopt-go"
+
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+	"github.com/projectcalico/calico/libcalico-go/lib/names"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// DrainBGP requests, or cancels, a BGP graceful shutdown of a node.
+func DrainBGP(args []string) error {
+	doc := `Usage:
+  <BINARY_NAME> node drain-bgp [--node=<NODE>] [--wait=<DURATION>] [--cancel] [--config=<CONFIG>] [--allow-version-mismatch]
+
+Options:
+  -h --help                    Show this screen.
+     --node=<NODE>             The name of the node to drain.  Defaults to the
+                               hostname of this host.
+     --wait=<DURATION>         How long to advertise the node's routes with the
+                               GRACEFUL_SHUTDOWN community before shutting down
+                               its BGP sessions.  [default: ` + gracefulshutdown.DefaultWait.String() + `]
+     --cancel                  Cancel a graceful shutdown and re-establish the
+                               node's BGP sessions.
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
+     --allow-version-mismatch  Allow client and cluster versions mismatch.
+
+Description:
+  Perform a BGP graceful shutdown (RFC 8326) of a Calico node, for example
+  before draining it for maintenance.  The node advertises all of its routes
+  with the GRACEFUL_SHUTDOWN community and a local preference of 0, so that
+  its peers move traffic to alternative paths, and then shuts down its BGP
+  sessions once the wait has passed.
+
+  The shutdown is requested by setting the ` + gracefulshutdown.Annotation + `
+  annotation on the node, and remains in effect until cancelled.  Use
+  '<BINARY_NAME> node status' on the node, or the node's CalicoNodeStatus, to see
+  its progress.
+`
+	// Replace all instances of BINARY_NAME with the name of the binary.
+	name, _ := util.NameAndDescription()
+	doc = strings.ReplaceAll(doc, "<BINARY_NAME>", name)
+
+	parsedArgs, err := docopt.ParseArgs(doc, args, "")
+	if err != nil {
+		return fmt.Errorf("Invalid option: 'calicoctl %s'. Use flag '--help' to read about a specific subcommand.", strings.Join(args, " "))
+	}
+	if len(parsedArgs) == 0 {
+		return nil
+	}
+
+	err = common.CheckVersionMismatch(parsedArgs["--config"], parsedArgs["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	wait, err := time.ParseDuration(parsedArgs["--wait"].(string))
+	if err != nil || wait < 0 {
+		return fmt.Errorf("Invalid --wait value %q: must be a non-negative duration, for example 30s", parsedArgs["--wait"])
+	}
+	cancel := parsedArgs["--cancel"].(bool)
+
+	nodeName, _ := parsedArgs["--node"].(string)
+	if nodeName == "" {
+		if nodeName, err = names.Hostname(); err != nil {
+			return fmt.Errorf("Unable to determine the name of this node, specify it with --node: %s", err)
+		}
+	}
+
+	cf := parsedArgs["--config"].(string)
+	client, err := clientmgr.NewClient(cf)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	node, err := client.Nodes().Get(ctx, nodeName, options.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Error retrieving node %s: %s", nodeName, err)
+	}
+
+	var teardown time.Time
+	if cancel {
+		if _, ok := node.Annotations[gracefulshutdown.Annotation]; !ok {
+			fmt.Printf("No BGP graceful shutdown in progress for node %s.\n", nodeName)
+			return nil
+		}
+		delete(node.Annotations, gracefulshutdown.Annotation)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		teardown = time.Now().Add(wait).UTC()
+		node.Annotations[gracefulshutdown.Annotation] = teardown.Format(time.RFC3339)
+	}
+
+	if _, err := client.Nodes().Update(ctx, node, options.SetOptions{}); err != nil {
+		return fmt.Errorf("Error updating node %s: %s", nodeName, err)
+	}
+
+	if cancel {
+		fmt.Printf("Cancelled BGP graceful shutdown of node %s.\n", nodeName)
+	} else {
+		fmt.Printf("Draining BGP routes from node %s, sessions will be shut down at %s.\n", nodeName, teardown.Format(time.RFC3339))
+	}
+	return nil
+}
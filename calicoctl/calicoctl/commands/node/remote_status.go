@@ -0,0 +1,264 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
+)
+
+const (
+	// The update period of the temporary CalicoNodeStatus resources.  calico/node reports
+	// the status as soon as the resource is created, so this only matters if the first
+	// report fails.
+	remoteStatusUpdatePeriodSeconds = 5
+
+	// How often to check whether calico/node has reported the status.
+	remoteStatusPollInterval = time.Second
+
+	// The maximum number of temporary CalicoNodeStatus resources to create or delete at
+	// once, to limit the load on the datastore for large clusters.
+	remoteStatusMaxConcurrency = 10
+)
+
+// Mapping the CalicoNodeStatus peer type to the display type, matching the local output.
+var nodeStatusPeerTypeMap = map[apiv3.BGPPeerType]string{
+	apiv3.BGPPeerTypeGlobalPeer: "global",
+	apiv3.BGPPeerTypeNodeMesh:   "node-to-node mesh",
+	apiv3.BGPPeerTypeNodePeer:   "node specific",
+}
+
+// listNodeNames returns the names of all nodes, sorted.
+func listNodeNames(ctx context.Context, c client.Interface) ([]string, error) {
+	nodes, err := c.Nodes().List(ctx, options.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing nodes: %s", err)
+	}
+	var names []string
+	for _, n := range nodes.Items {
+		names = append(names, n.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// getRemoteStatus asks calico/node on each of the given nodes to report its status by
+// creating a temporary CalicoNodeStatus resource, and waits for the reports.  The
+// temporary resources are always deleted before returning.  Nodes that do not report
+// before the context is done are returned with an empty status.
+func getRemoteStatus(ctx context.Context, c client.Interface, nodes []string, classes []apiv3.NodeStatusClassType) []*apiv3.CalicoNodeStatus {
+	period := uint32(remoteStatusUpdatePeriodSeconds)
+
+	var lock sync.Mutex
+	requests := map[string]string{}
+	defer func() {
+		// Use a fresh context so that we clean up even if the caller's context is done.
+		forEachLimited(nodes, func(node string) {
+			lock.Lock()
+			name, ok := requests[node]
+			lock.Unlock()
+			if !ok {
+				return
+			}
+			if _, err := c.CalicoNodeStatus().Delete(context.Background(), name, options.DeleteOptions{}); err != nil {
+				fmt.Printf("Error deleting temporary CalicoNodeStatus %s: %v\n", name, err)
+			}
+		})
+	}()
+
+	forEachLimited(nodes, func(node string) {
+		s := apiv3.NewCalicoNodeStatus()
+		s.Name = "calicoctl-status-" + rand.String(8)
+		s.Spec = apiv3.CalicoNodeStatusSpec{
+			Node:                node,
+			Classes:             classes,
+			UpdatePeriodSeconds: &period,
+		}
+		created, err := c.CalicoNodeStatus().Create(ctx, s, options.SetOptions{})
+		if err != nil {
+			fmt.Printf("Error requesting status of node %s: %v\n", node, err)
+			return
+		}
+		lock.Lock()
+		requests[node] = created.Name
+		lock.Unlock()
+	})
+
+	// Poll for the reports with a single List, rather than a Get per node, picking out
+	// the temporary resources created above.
+	requested := map[string]bool{}
+	for _, name := range requests {
+		requested[name] = true
+	}
+	results := map[string]*apiv3.CalicoNodeStatus{}
+
+	ticker := time.NewTicker(remoteStatusPollInterval)
+	defer ticker.Stop()
+wait:
+	for len(results) < len(requests) {
+		select {
+		case <-ctx.Done():
+			break wait
+		case <-ticker.C:
+		}
+		list, err := c.CalicoNodeStatus().List(ctx, options.ListOptions{})
+		if err != nil {
+			log.WithError(err).Debug("Failed to list CalicoNodeStatus resources")
+			continue
+		}
+		for i := range list.Items {
+			s := &list.Items[i]
+			if requested[s.Name] && !s.Status.LastUpdated.IsZero() {
+				results[s.Spec.Node] = s
+			}
+		}
+	}
+
+	var statuses []*apiv3.CalicoNodeStatus
+	for _, node := range nodes {
+		s, ok := results[node]
+		if !ok {
+			s = apiv3.NewCalicoNodeStatus()
+			s.Spec.Node = node
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// forEachLimited calls fn for each of the given nodes, running at most
+// remoteStatusMaxConcurrency calls at once.  It returns once all the calls have returned.
+func forEachLimited(nodes []string, fn func(node string)) {
+	sem := make(chan struct{}, remoteStatusMaxConcurrency)
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(node)
+		}(node)
+	}
+	wg.Wait()
+}
+
+// printRemoteStatus displays the status reported by calico/node for a single node.
+func printRemoteStatus(out io.Writer, s *apiv3.CalicoNodeStatus, services bool) {
+	fmt.Fprintf(out, "\nNode %s\n", s.Spec.Node)
+	if s.Status.LastUpdated.IsZero() {
+		fmt.Fprintf(out, "No status reported, calico/node may not be running on this node.\n")
+		return
+	}
+	fmt.Fprintf(out, "Status reported at %s.\n", s.Status.LastUpdated.UTC().Format(time.RFC3339))
+
+	for _, ipv := range []string{"4", "6"} {
+		bird := s.Status.Agent.BIRDV4
+		peers := s.Status.BGP.PeersV4
+		routes := s.Status.Routes.RoutesV4
+		if ipv == "6" {
+			bird = s.Status.Agent.BIRDV6
+			peers = s.Status.BGP.PeersV6
+			routes = s.Status.Routes.RoutesV6
+		}
+
+		fmt.Fprintf(out, "\nIPv%s BGP status\n", ipv)
+		if bird.State == "" {
+			fmt.Fprintf(out, "BIRDv%s is not running.\n", ipv)
+			continue
+		}
+		fmt.Fprintf(out, "BIRDv%s %s, version %s, router ID %s, up since %s.\n",
+			ipv, bird.State, bird.Version, bird.RouterID, bird.LastBootTime)
+
+		if len(peers) == 0 {
+			fmt.Fprintf(out, "No IPv%s peers found.\n", ipv)
+		} else {
+			printRemotePeers(out, peers)
+		}
+
+		fmt.Fprintf(out, "\nIPv%s routes\n", ipv)
+		if len(routes) == 0 {
+			fmt.Fprintf(out, "No IPv%s routes found.\n", ipv)
+		} else {
+			printRemoteRoutes(out, routes)
+		}
+	}
+
+	if gs := s.Status.BGP.GracefulShutdown; gs != nil {
+		fmt.Fprintf(out, "\nBGP graceful shutdown %s, sessions shut down at %s.\n", gs.State, gs.TeardownTime.UTC().Format(time.RFC3339))
+	}
+
+	if services {
+		fmt.Fprintf(out, "\nService advertisements\n")
+		var advertisements []apiv3.CalicoNodeServiceAdvertisement
+		advertisements = append(advertisements, s.Status.Services.ServicesV4...)
+		advertisements = append(advertisements, s.Status.Services.ServicesV6...)
+		if len(advertisements) == 0 {
+			fmt.Fprintf(out, "No service IPs found.\n")
+			return
+		}
+		servicestatus.PrintTable(out, advertisements)
+	}
+}
+
+// printRemotePeers prints out the peers reported in a CalicoNodeStatus in table format.
+func printRemotePeers(out io.Writer, peers []apiv3.CalicoNodePeer) {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Peer address", "Peer type", "State", "Since"})
+
+	for _, peer := range peers {
+		peerType, ok := nodeStatusPeerTypeMap[peer.Type]
+		if !ok {
+			peerType = string(peer.Type)
+		}
+		table.Append([]string{peer.PeerIP, peerType, string(peer.State), peer.Since})
+	}
+
+	table.Render()
+}
+
+// printRemoteRoutes prints out the routes reported in a CalicoNodeStatus in table format.
+func printRemoteRoutes(out io.Writer, routes []apiv3.CalicoNodeRoute) {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Destination", "Gateway", "Interface", "Type", "Learned from", "Peer"})
+
+	for _, r := range routes {
+		table.Append([]string{
+			r.Destination,
+			r.Gateway,
+			r.Interface,
+			string(r.Type),
+			string(r.LearnedFrom.SourceType),
+			r.LearnedFrom.PeerIP,
+		})
+	}
+
+	table.Render()
+}
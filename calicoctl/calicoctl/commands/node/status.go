@@ -16,35 +16,62 @@ package node
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"net"
 	"os"
+	"os/signal"
 	"reflect"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/docopt/docopt-go"
 	"github.com/olekukonko/tablewriter"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/shirou/gopsutil/v4/process"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/clientmgr"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/common"
+	"github.com/projectcalico/calico/calicoctl/calicoctl/commands/constants"
 	"github.com/projectcalico/calico/calicoctl/calicoctl/util"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
 )
 
 // Status prints status of the node and returns error (if any)
 func Status(args []string) error {
 	doc := `Usage:
-  <BINARY_NAME> node status [--allow-version-mismatch]
+  <BINARY_NAME> node status [--services] [--allow-version-mismatch]
+  <BINARY_NAME> node status (--node=<NODE> | --all) [--services] [--timeout=<TIMEOUT>]
+                            [--config=<CONFIG>] [--allow-version-mismatch]
 
 Options:
   -h --help                    Show this screen.
+     --services                Also show the service IPs that this node is
+                               advertising, or has chosen not to advertise, and why.
+     --node=<NODE>             Show the status of the named node, which need not
+                               be this host.
+     --all                     Show the status of every node in the cluster.
+     --timeout=<TIMEOUT>       How long to wait for the nodes to report their
+                               status.  [default: 30s]
+  -c --config=<CONFIG>         Path to the file containing connection
+                               configuration in YAML or JSON format.
+                               [default: ` + constants.DefaultConfigPath + `]
      --allow-version-mismatch  Allow client and cluster versions mismatch.
 
 Description:
   Check the status of the Calico node instance.  This includes the status and
   uptime of the node instance, and BGP peering states.
+
+  Without --node or --all, the status is read directly from the Calico node
+  instance on this host.  With --node or --all, calicoctl creates a temporary
+  CalicoNodeStatus resource for each node, waits for calico/node to report
+  its BIRD state, BGP peers and routes, and deletes the resources afterwards.
 `
 	// Replace all instances of BINARY_NAME with the name of the binary.
 	name, _ := util.NameAndDescription()
@@ -58,6 +85,10 @@ Description:
 		return nil
 	}
 
+	if parsedArgs["--node"] != nil || parsedArgs["--all"].(bool) {
+		return remoteStatus(parsedArgs)
+	}
+
 	// Note: Intentionally not check version mismatch for this command
 
 	// Must run this command as root to be able to connect to BIRD sockets
@@ -98,6 +129,12 @@ Description:
 		fmt.Printf("\nThe BGP backend process (BIRD) is not running.\n")
 	}
 
+	printGracefulShutdown()
+
+	if parsedArgs["--services"].(bool) {
+		printServiceAdvertisements()
+	}
+
 	// Have to manually enter an empty line because the table print
 	// library prints the last line, so can't insert a '\n' there
 	fmt.Println()
@@ -105,6 +142,62 @@ Description:
 	return nil
 }
 
+// remoteStatus displays the status of one or all nodes, as reported by calico/node in
+// CalicoNodeStatus resources.
+func remoteStatus(parsedArgs map[string]interface{}) error {
+	err := common.CheckVersionMismatch(parsedArgs["--config"], parsedArgs["--allow-version-mismatch"])
+	if err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(parsedArgs["--timeout"].(string))
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("Invalid --timeout value %q: must be a positive duration, for example 30s", parsedArgs["--timeout"])
+	}
+	services := parsedArgs["--services"].(bool)
+
+	cf := parsedArgs["--config"].(string)
+	client, err := clientmgr.NewClient(cf)
+	if err != nil {
+		return err
+	}
+
+	// Stop waiting, but still clean up, on timeout or interrupt.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	var nodes []string
+	if parsedArgs["--all"].(bool) {
+		if nodes, err = listNodeNames(ctx, client); err != nil {
+			return err
+		}
+	} else {
+		nodeName := parsedArgs["--node"].(string)
+		if _, err := client.Nodes().Get(ctx, nodeName, options.GetOptions{}); err != nil {
+			return fmt.Errorf("Error retrieving node %s: %s", nodeName, err)
+		}
+		nodes = []string{nodeName}
+	}
+
+	classes := []apiv3.NodeStatusClassType{
+		apiv3.NodeStatusClassTypeAgent,
+		apiv3.NodeStatusClassTypeBGP,
+		apiv3.NodeStatusClassTypeRoutes,
+	}
+	if services {
+		classes = append(classes, apiv3.NodeStatusClassTypeServices)
+	}
+
+	for _, s := range getRemoteStatus(ctx, client, nodes, classes) {
+		printRemoteStatus(os.Stdout, s, services)
+	}
+	fmt.Println()
+
+	return nil
+}
+
 func psContains(proc []string, procList []*process.Process) bool {
 	for _, p := range procList {
 		cmds, err := p.CmdlineSlice()
@@ -334,6 +427,46 @@ func scanBIRDPeers(ipv string, conn net.Conn) ([]bgpPeer, error) {
 	return peers, scanner.Err()
 }
 
+// printServiceAdvertisements displays the service IPs that confd is advertising, or
+// has chosen not to advertise, in table format.
+func printServiceAdvertisements() {
+	fmt.Printf("\nService advertisements\n")
+
+	advertisements, err := servicestatus.Read(servicestatus.DefaultPath)
+	if os.IsNotExist(err) {
+		fmt.Printf("No service advertisement status found, service advertisement may not be configured.\n")
+		return
+	} else if err != nil {
+		fmt.Printf("Error reading service advertisement status: %v\n", err)
+		return
+	}
+
+	if len(advertisements) == 0 {
+		fmt.Printf("No service IPs found.\n")
+		return
+	}
+
+	servicestatus.PrintTable(os.Stdout, advertisements)
+}
+
+// printGracefulShutdown prints the progress of any BGP graceful shutdown of this node.
+func printGracefulShutdown() {
+	gs, err := gracefulshutdown.Read(gracefulshutdown.DefaultPath)
+	if err != nil {
+		fmt.Printf("\nError reading BGP graceful shutdown status: %v\n", err)
+		return
+	} else if gs == nil {
+		return
+	}
+
+	switch gs.State {
+	case apiv3.BGPGracefulShutdownStateDraining:
+		fmt.Printf("\nBGP graceful shutdown in progress, sessions will be shut down at %s.\n", gs.TeardownTime.Format(time.RFC3339))
+	default:
+		fmt.Printf("\nBGP graceful shutdown complete, sessions were shut down at %s.\n", gs.TeardownTime.Format(time.RFC3339))
+	}
+}
+
 // printPeers prints out the slice of peers in table format.
 func printPeers(peers []bgpPeer) {
 	table := tablewriter.NewWriter(os.Stdout)
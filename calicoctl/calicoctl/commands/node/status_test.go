@@ -16,12 +16,16 @@ package node
 
 import (
 	"bytes"
+	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func init() {
@@ -138,6 +142,84 @@ We never get here
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Describe("Test remote node status output", func() {
+
+		It("should render the status reported in a CalicoNodeStatus", func() {
+			s := apiv3.NewCalicoNodeStatus()
+			s.Spec.Node = "node-1"
+			s.Status.LastUpdated = metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+			s.Status.Agent.BIRDV4 = apiv3.BGPDaemonStatus{
+				State:        apiv3.BGPDaemonStateReady,
+				Version:      "v0.3.3+birdv1.6.8",
+				RouterID:     "172.17.8.101",
+				LastBootTime: "2024-05-01 11:00:00",
+			}
+			s.Status.BGP.PeersV4 = []apiv3.CalicoNodePeer{{
+				PeerIP: "172.17.8.102",
+				Type:   apiv3.BGPPeerTypeNodeMesh,
+				State:  apiv3.BGPSessionStateEstablished,
+				Since:  "11:00:05",
+			}}
+			s.Status.Routes.RoutesV4 = []apiv3.CalicoNodeRoute{{
+				Type:        apiv3.RouteTypeFIB,
+				Destination: "192.168.10.0/26",
+				Gateway:     "172.17.8.102",
+				Interface:   "eth0",
+				LearnedFrom: apiv3.CalicoNodeRouteLearnedFrom{
+					SourceType: apiv3.RouteSourceTypeNodeMesh,
+					PeerIP:     "172.17.8.102",
+				},
+			}}
+
+			out := &bytes.Buffer{}
+			printRemoteStatus(out, s, false)
+			Expect(out.String()).To(ContainSubstring("Node node-1"))
+			Expect(out.String()).To(ContainSubstring("BIRDv4 Ready, version v0.3.3+birdv1.6.8, router ID 172.17.8.101"))
+			Expect(out.String()).To(MatchRegexp(`172\.17\.8\.102 +\| +node-to-node mesh +\| +Established`))
+			Expect(out.String()).To(MatchRegexp(`192\.168\.10\.0/26 +\| +172\.17\.8\.102 +\| +eth0 +\| +FIB +\| +NodeMesh`))
+			Expect(out.String()).To(ContainSubstring("BIRDv6 is not running."))
+			Expect(out.String()).NotTo(ContainSubstring("Service advertisements"))
+		})
+
+		It("should report nodes that did not respond", func() {
+			s := apiv3.NewCalicoNodeStatus()
+			s.Spec.Node = "node-2"
+
+			out := &bytes.Buffer{}
+			printRemoteStatus(out, s, true)
+			Expect(out.String()).To(ContainSubstring("No status reported"))
+			Expect(out.String()).NotTo(ContainSubstring("BGP status"))
+		})
+
+		It("should limit the number of concurrent requests", func() {
+			var nodes []string
+			for i := 0; i < 50; i++ {
+				nodes = append(nodes, fmt.Sprintf("node-%d", i))
+			}
+
+			var lock sync.Mutex
+			var running, maxRunning int
+			called := map[string]bool{}
+			forEachLimited(nodes, func(node string) {
+				lock.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				called[node] = true
+				lock.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				lock.Lock()
+				running--
+				lock.Unlock()
+			})
+			Expect(called).To(HaveLen(len(nodes)))
+			Expect(maxRunning).To(BeNumerically("<=", remoteStatusMaxConcurrency))
+		})
+	})
 }
 
 // Implement a Mock net.Conn interface, used to emulate reading data from a
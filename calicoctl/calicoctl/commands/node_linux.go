@@ -35,6 +35,7 @@ func Node(args []string) error {
     status       View the current status of a Calico node.
     diags        Gather a diagnostics bundle for a Calico node.
     checksystem  Verify the compute host is able to run a Calico node instance.
+    drain-bgp    Gracefully shut down the BGP sessions of a Calico node.
 
 Options:
   -h --help      Show this screen.
@@ -74,6 +75,8 @@ Description:
 		return node.Checksystem(args)
 	case "run":
 		return node.Run(args)
+	case "drain-bgp":
+		return node.DrainBGP(args)
 	default:
 		fmt.Println(doc)
 	}
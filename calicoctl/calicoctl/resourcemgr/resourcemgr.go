@@ -355,7 +355,8 @@ func GetResourcesFromArgs(args map[string]interface{}) ([]ResourceObject, error)
 	var names []string
 
 	switch args[argname].(type) {
-	case string:
+	case string, nil:
+		// The name is optional when a selector is specified instead.
 		name := argutils.ArgStringOrBlank(args, argname)
 		names = append(names, name)
 	case []string:
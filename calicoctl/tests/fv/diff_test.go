@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	. "github.com/projectcalico/calico/calicoctl/tests/fv/utils"
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+func TestDiffAndDryRun(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+
+	// Create a Calico client.
+	config := apiconfig.NewCalicoAPIConfig()
+	config.Spec.DatastoreType = "etcdv3"
+	config.Spec.EtcdEndpoints = "http://127.0.0.1:2379"
+	client, err := clientv3.New(*config)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Create a pool and a policy, relying on the client to default some of their fields.
+	pool := v3.NewIPPool()
+	pool.Name = "diff-test-pool"
+	pool.Spec.CIDR = "10.66.0.0/16"
+	_, err = client.IPPools().Create(ctx, pool, options.SetOptions{})
+	Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		_, _ = client.IPPools().Delete(ctx, "diff-test-pool", options.DeleteOptions{})
+	}()
+
+	gnp := v3.NewGlobalNetworkPolicy()
+	gnp.Name = "diff-test-policy"
+	gnp.Spec.Selector = "app == 'web'"
+	gnp.Spec.Ingress = []v3.Rule{{Action: v3.Allow}}
+	_, err = client.GlobalNetworkPolicies().Create(ctx, gnp, options.SetOptions{})
+	Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		_, _ = client.GlobalNetworkPolicies().Delete(ctx, "diff-test-policy", options.DeleteOptions{})
+		_, _ = client.GlobalNetworkPolicies().Delete(ctx, "diff-test-new-policy", options.DeleteOptions{})
+	}()
+
+	// Set Calico version in ClusterInformation
+	out, err := SetCalicoVersion(false)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(out).To(ContainSubstring("Calico version set to"))
+
+	dir := t.TempDir()
+	writeFile := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(data), 0644)).To(Succeed())
+		return path
+	}
+
+	// The same resources as were created are unchanged.
+	unchanged := writeFile("unchanged.yaml", `
+apiVersion: projectcalico.org/v3
+kind: IPPool
+metadata:
+  name: diff-test-pool
+spec:
+  cidr: 10.66.0.0/16
+---
+apiVersion: projectcalico.org/v3
+kind: GlobalNetworkPolicy
+metadata:
+  name: diff-test-policy
+spec:
+  selector: app == 'web'
+  ingress:
+  - action: Allow
+`)
+	out = Calicoctl(false, "diff", "-f", unchanged)
+	Expect(out).To(BeEmpty())
+	out = Calicoctl(false, "apply", "-f", unchanged, "--dry-run")
+	Expect(out).To(ContainSubstring("IPPool(diff-test-pool) unchanged (dry run)"))
+	Expect(out).To(ContainSubstring("GlobalNetworkPolicy(diff-test-policy) unchanged (dry run)"))
+	Expect(out).To(ContainSubstring("Dry run: 0 resource(s) would be created, 0 updated and 2 unchanged"))
+
+	// A changed policy and a new one.
+	changed := writeFile("changed.yaml", `
+apiVersion: projectcalico.org/v3
+kind: GlobalNetworkPolicy
+metadata:
+  name: diff-test-policy
+spec:
+  selector: app == 'api'
+  ingress:
+  - action: Allow
+---
+apiVersion: projectcalico.org/v3
+kind: GlobalNetworkPolicy
+metadata:
+  name: diff-test-new-policy
+spec:
+  selector: all()
+`)
+	out, err = CalicoctlMayFail(false, "diff", "-f", changed)
+	Expect(err).To(HaveOccurred())
+	Expect(out).To(ContainSubstring("--- live/GlobalNetworkPolicy(diff-test-policy)"))
+	Expect(out).To(ContainSubstring("-  selector: app == 'web'"))
+	Expect(out).To(ContainSubstring("+  selector: app == 'api'"))
+	Expect(out).To(ContainSubstring("--- /dev/null\n+++ file/GlobalNetworkPolicy(diff-test-new-policy)"))
+	Expect(out).To(ContainSubstring("2 of 2 resource(s) differ from the datastore"))
+
+	out = Calicoctl(false, "apply", "-f", changed, "--dry-run")
+	Expect(out).To(ContainSubstring("GlobalNetworkPolicy(diff-test-policy) updated (dry run)"))
+	Expect(out).To(ContainSubstring("GlobalNetworkPolicy(diff-test-new-policy) created (dry run)"))
+
+	// Nothing was written.
+	_, err = client.GlobalNetworkPolicies().Get(ctx, "diff-test-new-policy", options.GetOptions{})
+	Expect(err).To(HaveOccurred())
+	p, err := client.GlobalNetworkPolicies().Get(ctx, "diff-test-policy", options.GetOptions{})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(p.Spec.Selector).To(Equal("app == 'web'"))
+
+	// Invalid resources are reported.
+	invalid := writeFile("invalid.yaml", `
+apiVersion: projectcalico.org/v3
+kind: GlobalNetworkPolicy
+metadata:
+  name: diff-test-policy
+spec:
+  selector: app ==
+`)
+	out, err = CalicoctlMayFail(false, "apply", "-f", invalid, "--dry-run")
+	Expect(err).To(HaveOccurred())
+	Expect(out).To(ContainSubstring("Dry run failed for 1 out of 1 resource(s)"))
+}
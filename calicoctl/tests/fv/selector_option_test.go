@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fv_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	. "github.com/projectcalico/calico/calicoctl/tests/fv/utils"
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+func TestSelectorOption(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := context.Background()
+
+	// Create a Calico client.
+	config := apiconfig.NewCalicoAPIConfig()
+	config.Spec.DatastoreType = "etcdv3"
+	config.Spec.EtcdEndpoints = "http://127.0.0.1:2379"
+	client, err := clientv3.New(*config)
+	Expect(err).NotTo(HaveOccurred())
+
+	// Create some policies with different labels.
+	for name, labels := range map[string]map[string]string{
+		"payments-ingress": {"team": "payments", "pci": ""},
+		"payments-egress":  {"team": "payments"},
+		"frontend":         {"team": "frontend"},
+	} {
+		gnp := v3.NewGlobalNetworkPolicy()
+		gnp.Name = name
+		gnp.Labels = labels
+		_, err = client.GlobalNetworkPolicies().Create(ctx, gnp, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	defer func() {
+		for _, name := range []string{"payments-ingress", "payments-egress", "frontend"} {
+			_, _ = client.GlobalNetworkPolicies().Delete(ctx, name, options.DeleteOptions{})
+		}
+	}()
+
+	// Set Calico version in ClusterInformation
+	out, err := SetCalicoVersion(false)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(out).To(ContainSubstring("Calico version set to"))
+
+	out = Calicoctl(false, "get", "globalnetworkpolicies", "-l", "team == 'payments'")
+	Expect(out).To(ContainSubstring("payments-ingress"))
+	Expect(out).To(ContainSubstring("payments-egress"))
+	Expect(out).NotTo(ContainSubstring("frontend"))
+
+	out = Calicoctl(false, "get", "globalnetworkpolicies", "--selector", "team == 'payments' && has(pci)")
+	Expect(out).To(ContainSubstring("payments-ingress"))
+	Expect(out).NotTo(ContainSubstring("payments-egress"))
+
+	out, err = CalicoctlMayFail(false, "get", "globalnetworkpolicies", "frontend", "-l", "has(team)")
+	Expect(err).To(HaveOccurred())
+	Expect(out).To(ContainSubstring("resource names cannot be specified with --selector"))
+
+	out, err = CalicoctlMayFail(false, "get", "globalnetworkpolicies", "-l", "has(team")
+	Expect(err).To(HaveOccurred())
+	Expect(out).To(ContainSubstring("invalid selector"))
+
+	out = Calicoctl(false, "label", "globalnetworkpolicies", "-l", "team == 'payments'", "owner=alice")
+	Expect(out).To(Equal("Successfully set label owner on globalnetworkpolicies (2 resources)\n"))
+	out = Calicoctl(false, "get", "globalnetworkpolicies", "-l", "owner == 'alice'")
+	Expect(out).To(ContainSubstring("payments-ingress"))
+	Expect(out).To(ContainSubstring("payments-egress"))
+	Expect(out).NotTo(ContainSubstring("frontend"))
+
+	out = Calicoctl(false, "delete", "globalnetworkpolicies", "-l", "team == 'payments'")
+	Expect(out).To(Equal("Successfully deleted 2 'GlobalNetworkPolicy' resource(s)\n"))
+	out = Calicoctl(false, "get", "globalnetworkpolicies")
+	Expect(out).To(ContainSubstring("frontend"))
+	Expect(out).NotTo(ContainSubstring("payments"))
+
+	out = Calicoctl(false, "delete", "globalnetworkpolicies", "-l", "team == 'payments'")
+	Expect(out).To(Equal("No resources match the selector\n"))
+}
@@ -43,8 +43,10 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/watchersyncer"
 	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	lerr "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
 	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
 	"github.com/projectcalico/calico/libcalico-go/lib/set"
 	"github.com/projectcalico/calico/typha/pkg/syncclientutils"
 	"github.com/projectcalico/calico/typha/pkg/syncproto"
@@ -146,6 +148,7 @@ func NewCalicoClient(confdConfig *config.Config) (*client, error) {
 		globalBGPConfig:         cfg,
 		nodeIPs:                 make(map[string]struct{}),
 		programmedRouteRefCount: make(map[string]int),
+		gracefulShutdown:        gracefulShutdownState{statusPath: gracefulshutdown.DefaultPath},
 
 		// Track which routes we have sent, and which we have not. We need maps for
 		// each of the three types of routes we track.
@@ -250,6 +253,26 @@ func NewCalicoClient(confdConfig *config.Config) (*client, error) {
 		}
 	} else {
 		c.OnSyncChange(SourceRouteGenerator, true)
+
+		// Clear out any service advertisement status left behind by a previous run.
+		if err := servicestatus.Write(servicestatus.DefaultPath, nil); err != nil {
+			log.WithError(err).Debug("Failed to clear service advertisement status")
+		}
+	}
+
+	if advertiseAnnotatedPodIPs() {
+		// Create and start the pod route generator, which advertises the individual IPs of
+		// local pods that have opted in via annotation.
+		log.Info("Starting route generator for annotated pod advertisement")
+		if c.prg, err = NewPodRouteGenerator(c); err != nil {
+			log.WithError(err).Error("Failed to start pod route generator, pod IPs will not be advertised")
+			c.OnSyncChange(SourcePodRouteGenerator, true)
+			c.prg = nil
+		} else {
+			c.prg.Start()
+		}
+	} else {
+		c.OnSyncChange(SourcePodRouteGenerator, true)
 	}
 
 	// Start a goroutine to process updates in a way that's decoupled from their sources.
@@ -276,8 +299,9 @@ func NewCalicoClient(confdConfig *config.Config) (*client, error) {
 }
 
 var (
-	SourceSyncer         string = "SourceSyncer"
-	SourceRouteGenerator string = "SourceRouteGenerator"
+	SourceSyncer            string = "SourceSyncer"
+	SourceRouteGenerator    string = "SourceRouteGenerator"
+	SourcePodRouteGenerator string = "SourcePodRouteGenerator"
 )
 
 // client implements the StoreClient interface for confd, and also implements the
@@ -299,6 +323,9 @@ type client struct {
 	// The route generator
 	rg *routeGenerator
 
+	// The route generator for individually advertised pod IPs.
+	prg *podRouteGenerator
+
 	// Keep reference counts of programmed routes. We have multiple route
 	// sources to track - k8s Services, and BGPConfiguration - and they can
 	// provide duplicate routes.
@@ -353,6 +380,9 @@ type client struct {
 
 	// Cached value of the default BGP configuration for node to node mesh BGP password lookup.
 	globalBGPConfig *apiv3.BGPConfiguration
+
+	// State of any BGP graceful shutdown requested for this node.
+	gracefulShutdown gracefulShutdownState
 }
 
 // SetPrefixes is called from confd to notify this client of the full set of prefixes that will
@@ -419,13 +449,13 @@ func (c *client) OnSyncChange(source string, ready bool) {
 	log.Infof("Source %v readiness changed, ready=%v", source, ready)
 
 	// Check if we are fully in sync, before applying this change.
-	oldFullSync := c.sourceReady[SourceSyncer] && c.sourceReady[SourceRouteGenerator]
+	oldFullSync := c.allSourcesReadyLockHeld()
 
 	// Apply the change.
 	c.sourceReady[source] = ready
 
 	// Check if we are fully in sync now.
-	newFullSync := c.sourceReady[SourceSyncer] && c.sourceReady[SourceRouteGenerator]
+	newFullSync := c.allSourcesReadyLockHeld()
 
 	if newFullSync == oldFullSync {
 		log.Debugf("No change to full sync status (%v)", newFullSync)
@@ -447,6 +477,12 @@ func (c *client) OnSyncChange(source string, ready bool) {
 	}
 }
 
+// allSourcesReadyLockHeld returns true if all data sources (the syncer and the route
+// generators) are ready.  It must be called with the cache lock held.
+func (c *client) allSourcesReadyLockHeld() bool {
+	return c.sourceReady[SourceSyncer] && c.sourceReady[SourceRouteGenerator] && c.sourceReady[SourcePodRouteGenerator]
+}
+
 type bgpPeer struct {
 	PeerIP          cnet.IP              `json:"ip"`
 	ASNum           numorstring.ASNumber `json:"as_num,string"`
@@ -957,6 +993,15 @@ func (c *client) onUpdates(updates []api.Update, needUpdatePeersV1 bool) {
 				}
 			}
 
+			if v3key.Name == template.NodeName {
+				// Track any BGP graceful shutdown requested for this node.
+				var annotation string
+				if v3res, ok := u.Value.(*libapiv3.Node); ok {
+					annotation = v3res.Annotations[gracefulshutdown.Annotation]
+				}
+				c.onGracefulShutdownAnnotationLockHeld(annotation)
+			}
+
 			// Update our cache of node labels.
 			if u.Value == nil {
 				// This was a delete - remove node labels.
@@ -1784,10 +1829,12 @@ func (c *client) updateLogLevel() {
 }
 
 var (
-	routeKeyPrefix    = "/calico/staticroutes/"
-	rejectKeyPrefix   = "/calico/rejectcidrs/"
-	routeKeyPrefixV6  = "/calico/staticroutesv6/"
-	rejectKeyPrefixV6 = "/calico/rejectcidrsv6/"
+	routeKeyPrefix           = "/calico/staticroutes/"
+	rejectKeyPrefix          = "/calico/rejectcidrs/"
+	routeKeyPrefixV6         = "/calico/staticroutesv6/"
+	rejectKeyPrefixV6        = "/calico/rejectcidrsv6/"
+	workloadRouteKeyPrefix   = "/calico/workloadroutes/"
+	workloadRouteKeyPrefixV6 = "/calico/workloadroutesv6/"
 )
 
 func (c *client) addRoutesLockHeld(prefixV4, prefixV6 string, cidrs []string) {
@@ -1849,6 +1896,29 @@ func (c *client) DeleteStaticRoutes(cidrs []string) {
 	c.onNewUpdates()
 }
 
+// AddWorkloadRoutes adds the given CIDRs as individual workload routes to be advertised
+// from this node.  Unlike static routes, these are not programmed as blackhole routes
+// since the kernel already has a route to the workload.
+func (c *client) AddWorkloadRoutes(cidrs []string) {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	c.incrementCacheRevision()
+	c.addRoutesLockHeld(workloadRouteKeyPrefix, workloadRouteKeyPrefixV6, cidrs)
+	c.onNewUpdates()
+}
+
+// DeleteWorkloadRoutes withdraws the given CIDRs from the set of individual workload
+// routes advertised from this node.
+func (c *client) DeleteWorkloadRoutes(cidrs []string) {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	c.incrementCacheRevision()
+	c.deleteRoutesLockHeld(workloadRouteKeyPrefix, workloadRouteKeyPrefixV6, cidrs)
+	c.onNewUpdates()
+}
+
 func (c *client) setPeerConfigFieldsFromV3Resource(peers []*bgpPeer, v3res *apiv3.BGPPeer) {
 	// Get the password, if one is configured
 	password := c.getPassword(v3res)
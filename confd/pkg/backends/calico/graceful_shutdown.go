@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package calico
+
+import (
+	"fmt"
+	"time"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/confd/pkg/resource/template"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+)
+
+const (
+	// Values of the graceful shutdown key.  While draining, the BIRD templates advertise
+	// all routes with the GRACEFUL_SHUTDOWN community.  On teardown they also disable
+	// the BGP sessions.
+	gracefulShutdownDraining = "draining"
+	gracefulShutdownTeardown = "teardown"
+)
+
+// gracefulShutdownState tracks a BGP graceful shutdown requested for this node.
+type gracefulShutdownState struct {
+	// Where the status of the shutdown is written for the node status reporter.
+	statusPath string
+
+	// Whether any graceful shutdown left in progress by a previous run has been picked up.
+	resumed bool
+
+	// The current annotation value, when it was first seen and when the BGP sessions
+	// are torn down.
+	annotation string
+	firstSeen  time.Time
+	teardown   time.Time
+	timer      *time.Timer
+}
+
+func gracefulShutdownKey() string {
+	return fmt.Sprintf("/calico/bgp/v1/host/%s/graceful_shutdown", template.NodeName)
+}
+
+// onGracefulShutdownAnnotationLockHeld handles a change to the graceful shutdown
+// annotation on this node.  An empty value cancels any graceful shutdown in progress.
+// The caller should be holding the cacheLock.
+func (c *client) onGracefulShutdownAnnotationLockHeld(value string) {
+	gs := &c.gracefulShutdown
+	if !gs.resumed {
+		// This is the first update since confd started.  Carry on with any graceful
+		// shutdown that was in progress, so that a relative wait is not restarted, or
+		// clear out the status it left behind if the annotation has since been removed.
+		gs.resumed = true
+		if requested, err := gracefulshutdown.RequestTime(gs.statusPath); err != nil {
+			log.WithError(err).Warning("Failed to read previous BGP graceful shutdown status")
+		} else if value != "" {
+			gs.firstSeen = requested
+		}
+		if value == "" {
+			c.setGracefulShutdownLockHeld("")
+			return
+		}
+	}
+	if value == gs.annotation {
+		return
+	}
+	if gs.timer != nil {
+		gs.timer.Stop()
+		gs.timer = nil
+	}
+
+	if value == "" {
+		log.Info("BGP graceful shutdown cancelled")
+		*gs = gracefulShutdownState{statusPath: gs.statusPath, resumed: true}
+		c.setGracefulShutdownLockHeld("")
+		return
+	}
+
+	now := time.Now()
+	if gs.firstSeen.IsZero() {
+		gs.firstSeen = now
+	}
+	gs.annotation = value
+	teardown, err := gracefulshutdown.TeardownTime(value, gs.firstSeen)
+	if err != nil {
+		log.WithError(err).Warningf("Invalid BGP graceful shutdown request, waiting %v before teardown", gracefulshutdown.DefaultWait)
+		teardown = gs.firstSeen.Add(gracefulshutdown.DefaultWait)
+	}
+	gs.teardown = teardown
+
+	if !now.Before(teardown) {
+		log.Info("BGP graceful shutdown teardown time has passed, shutting down BGP sessions")
+		c.setGracefulShutdownLockHeld(gracefulShutdownTeardown)
+		return
+	}
+	log.WithField("teardownTime", teardown).Info("BGP graceful shutdown requested, draining routes")
+	c.setGracefulShutdownLockHeld(gracefulShutdownDraining)
+	gs.timer = time.AfterFunc(teardown.Sub(now), c.onGracefulShutdownTimer)
+}
+
+// onGracefulShutdownTimer tears down the BGP sessions once the drain period has passed.
+func (c *client) onGracefulShutdownTimer() {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	gs := &c.gracefulShutdown
+	if gs.annotation == "" || time.Now().Before(gs.teardown) {
+		// The shutdown has been cancelled or rescheduled since the timer was set.
+		return
+	}
+	log.Info("BGP graceful shutdown drain period complete, shutting down BGP sessions")
+	c.incrementCacheRevision()
+	c.setGracefulShutdownLockHeld(gracefulShutdownTeardown)
+	c.onNewUpdates()
+}
+
+// setGracefulShutdownLockHeld updates the graceful shutdown key used by the templates
+// and the status reported for the node.  The caller should be holding the cacheLock.
+func (c *client) setGracefulShutdownLockHeld(value string) {
+	k := gracefulShutdownKey()
+	if value == "" {
+		if _, ok := c.cache[k]; ok {
+			delete(c.cache, k)
+			c.keyUpdated(k)
+		}
+	} else if c.cache[k] != value {
+		c.cache[k] = value
+		c.keyUpdated(k)
+	}
+
+	var status *apiv3.CalicoNodeBGPGracefulShutdown
+	switch value {
+	case gracefulShutdownDraining:
+		status = &apiv3.CalicoNodeBGPGracefulShutdown{
+			State:        apiv3.BGPGracefulShutdownStateDraining,
+			TeardownTime: metav1.NewTime(c.gracefulShutdown.teardown),
+		}
+	case gracefulShutdownTeardown:
+		status = &apiv3.CalicoNodeBGPGracefulShutdown{
+			State:        apiv3.BGPGracefulShutdownStateComplete,
+			TeardownTime: metav1.NewTime(c.gracefulShutdown.teardown),
+		}
+	}
+	if err := gracefulshutdown.Write(c.gracefulShutdown.statusPath, status, c.gracefulShutdown.firstSeen); err != nil {
+		log.WithError(err).Warning("Failed to write BGP graceful shutdown status")
+	}
+}
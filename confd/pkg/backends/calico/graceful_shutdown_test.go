@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package calico
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/confd/pkg/resource/template"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+)
+
+var _ = Describe("BGP graceful shutdown", func() {
+	var (
+		c            *client
+		dir          string
+		key          string
+		origNodeName string
+	)
+
+	BeforeEach(func() {
+		origNodeName = template.NodeName
+		template.NodeName = "node1"
+		key = "/calico/bgp/v1/host/node1/graceful_shutdown"
+
+		var err error
+		dir, err = os.MkdirTemp("", "gracefulshutdown")
+		Expect(err).NotTo(HaveOccurred())
+
+		c = &client{
+			cache:             make(map[string]string),
+			revisionsByPrefix: make(map[string]uint64),
+			syncedOnce:        true,
+			gracefulShutdown:  gracefulShutdownState{statusPath: filepath.Join(dir, "status.json")},
+		}
+		c.watcherCond = sync.NewCond(&c.cacheLock)
+	})
+
+	AfterEach(func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("")
+		c.cacheLock.Unlock()
+		template.NodeName = origNodeName
+		_ = os.RemoveAll(dir)
+	})
+
+	readStatus := func() *apiv3.CalicoNodeBGPGracefulShutdown {
+		status, err := gracefulshutdown.Read(c.gracefulShutdown.statusPath)
+		Expect(err).NotTo(HaveOccurred())
+		return status
+	}
+
+	getKey := func() string {
+		c.cacheLock.Lock()
+		defer c.cacheLock.Unlock()
+		return c.cache[key]
+	}
+
+	It("should drain and then tear down the BGP sessions", func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("200ms")
+		c.cacheLock.Unlock()
+
+		Expect(getKey()).To(Equal(gracefulShutdownDraining))
+		Expect(readStatus().State).To(Equal(apiv3.BGPGracefulShutdownStateDraining))
+
+		Eventually(getKey).Should(Equal(gracefulShutdownTeardown))
+		Expect(readStatus().State).To(Equal(apiv3.BGPGracefulShutdownStateComplete))
+	})
+
+	It("should tear down immediately if the teardown time has passed", func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld(time.Now().Add(-time.Minute).Format(time.RFC3339))
+		c.cacheLock.Unlock()
+
+		Expect(getKey()).To(Equal(gracefulShutdownTeardown))
+		Expect(readStatus().State).To(Equal(apiv3.BGPGracefulShutdownStateComplete))
+	})
+
+	It("should use the default wait for an invalid annotation", func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("soon")
+		c.cacheLock.Unlock()
+
+		Expect(getKey()).To(Equal(gracefulShutdownDraining))
+		status := readStatus()
+		Expect(status.TeardownTime.Time).To(BeTemporally("~", time.Now().Add(gracefulshutdown.DefaultWait), 5*time.Second))
+	})
+
+	It("should restore the BGP sessions when the annotation is removed", func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("true")
+		c.cacheLock.Unlock()
+		Expect(getKey()).To(Equal(gracefulShutdownDraining))
+
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("")
+		c.cacheLock.Unlock()
+		Expect(c.cache).NotTo(HaveKey(key))
+		Expect(readStatus()).To(BeNil())
+	})
+})
+
+var _ = Describe("BGP graceful shutdown after a restart", func() {
+	var (
+		c            *client
+		dir          string
+		origNodeName string
+	)
+
+	BeforeEach(func() {
+		origNodeName = template.NodeName
+		template.NodeName = "node1"
+
+		var err error
+		dir, err = os.MkdirTemp("", "gracefulshutdown")
+		Expect(err).NotTo(HaveOccurred())
+
+		c = &client{
+			cache:             make(map[string]string),
+			revisionsByPrefix: make(map[string]uint64),
+			syncedOnce:        true,
+			gracefulShutdown:  gracefulShutdownState{statusPath: filepath.Join(dir, "status.json")},
+		}
+		c.watcherCond = sync.NewCond(&c.cacheLock)
+	})
+
+	AfterEach(func() {
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("")
+		c.cacheLock.Unlock()
+		template.NodeName = origNodeName
+		_ = os.RemoveAll(dir)
+	})
+
+	writePrevious := func(requested time.Time) {
+		Expect(gracefulshutdown.Write(c.gracefulShutdown.statusPath, &apiv3.CalicoNodeBGPGracefulShutdown{
+			State:        apiv3.BGPGracefulShutdownStateDraining,
+			TeardownTime: metav1.NewTime(requested.Add(time.Hour)),
+		}, requested)).To(Succeed())
+	}
+
+	It("should resume the wait from when the shutdown was first requested", func() {
+		requested := time.Now().Add(-time.Minute).Truncate(time.Second)
+		writePrevious(requested)
+
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("1h")
+		c.cacheLock.Unlock()
+
+		status, err := gracefulshutdown.Read(c.gracefulShutdown.statusPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(apiv3.BGPGracefulShutdownStateDraining))
+		Expect(status.TeardownTime.Time).To(BeTemporally("==", requested.Add(time.Hour)))
+	})
+
+	It("should tear down at once if the wait ran out while confd was down", func() {
+		writePrevious(time.Now().Add(-time.Hour))
+
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("true")
+		c.cacheLock.Unlock()
+
+		Expect(c.cache).To(HaveKeyWithValue("/calico/bgp/v1/host/node1/graceful_shutdown", gracefulShutdownTeardown))
+	})
+
+	It("should clear the previous status if the annotation has been removed", func() {
+		writePrevious(time.Now().Add(-time.Minute))
+
+		c.cacheLock.Lock()
+		c.onGracefulShutdownAnnotationLockHeld("")
+		c.cacheLock.Unlock()
+
+		_, err := os.Stat(c.gracefulShutdown.statusPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
+
+var _ = DescribeTable("Graceful shutdown teardown time",
+	func(value string, expected time.Duration, expectErr bool) {
+		firstSeen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		teardown, err := gracefulshutdown.TeardownTime(value, firstSeen)
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(teardown.Sub(firstSeen)).To(Equal(expected))
+	},
+	Entry("RFC 3339 time", "2024-05-01T12:05:00Z", 5*time.Minute, false),
+	Entry("duration", "90s", 90*time.Second, false),
+	Entry("true", "true", gracefulshutdown.DefaultWait, false),
+	Entry("negative duration", "-1m", time.Duration(0), true),
+	Entry("false", "false", time.Duration(0), true),
+	Entry("garbage", "soon", time.Duration(0), true),
+)
@@ -0,0 +1,223 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package calico
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// envAdvertiseAnnotatedPodIPs enables advertisement of individual pod IPs for pods
+	// on this node that carry the advertisePodIPAnnotation.
+	envAdvertiseAnnotatedPodIPs = "CALICO_ADVERTISE_ANNOTATED_POD_IPS"
+
+	// advertisePodIPAnnotation marks a pod whose IPs should be advertised as /32 (or /128)
+	// routes from the node hosting it, in addition to the aggregated block route.  This is
+	// typically used for anycast workloads that run on several nodes with the same IP.
+	advertisePodIPAnnotation = "projectcalico.org/advertise-pod-ip"
+)
+
+// advertiseAnnotatedPodIPs returns true if confd has been configured to advertise
+// the IPs of annotated pods.
+func advertiseAnnotatedPodIPs() bool {
+	return strings.ToLower(os.Getenv(envAdvertiseAnnotatedPodIPs)) == "true"
+}
+
+// podRouteGenerator monitors the pods on this node and advertises host routes for the
+// IPs of those that have opted in via the advertisePodIPAnnotation and are ready.
+type podRouteGenerator struct {
+	sync.Mutex
+	client      *client
+	nodeName    string
+	podInformer cache.Controller
+	podRouteMap map[string][]string
+}
+
+// NewPodRouteGenerator initializes a kube-api client and the pod informer.
+func NewPodRouteGenerator(c *client) (prg *podRouteGenerator, err error) {
+	prg = &podRouteGenerator{
+		client:      c,
+		nodeName:    k8sNodeName(),
+		podRouteMap: make(map[string][]string),
+	}
+	log.Debugf("Pod route generator configured to use node name %s", prg.nodeName)
+
+	client, err := newK8sClientset()
+	if err != nil {
+		return
+	}
+
+	// Only watch pods scheduled to this node.
+	podWatcher := cache.NewListWatchFromClient(
+		client.CoreV1().RESTClient(), "pods", "", fields.OneTermEqualSelector("spec.nodeName", prg.nodeName),
+	)
+	podHandler := cache.ResourceEventHandlerFuncs{AddFunc: prg.onPodAdd, UpdateFunc: prg.onPodUpdate, DeleteFunc: prg.onPodDelete}
+	_, prg.podInformer = cache.NewIndexerInformer(podWatcher, &v1.Pod{}, 0, podHandler, cache.Indexers{})
+
+	return
+}
+
+// Start starts the pod route generator so that it will monitor the pods on this node.
+func (prg *podRouteGenerator) Start() {
+	ch := make(chan struct{})
+	go prg.podInformer.Run(ch)
+
+	// Wait for the informer to sync, then notify the main client.
+	log.Info("Starting route generator for annotated pods")
+	go func() {
+		for !prg.podInformer.HasSynced() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		prg.client.OnSyncChange(SourcePodRouteGenerator, true)
+		log.Info("Pod route generator in sync")
+	}()
+}
+
+// setRoutesForPod advertises the routes for the given pod if it should be advertised,
+// and withdraws any routes previously advertised for it that no longer apply.
+func (prg *podRouteGenerator) setRoutesForPod(pod *v1.Pod) {
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		log.WithError(err).Warn("setRoutesForPod: error on retrieving key for pod, passing")
+		return
+	}
+
+	var routes []string
+	if prg.advertiseThisPod(pod) {
+		for _, podIP := range pod.Status.PodIPs {
+			routes = append(routes, podIP.IP)
+		}
+		routes = addFullIPLength(routes)
+	}
+	log.WithFields(log.Fields{"pod": key, "routes": routes}).Debug("Setting routes for pod")
+
+	prg.Lock()
+	defer prg.Unlock()
+	prg.setRoutesForKey(key, routes)
+}
+
+// unsetRoutesForPod withdraws all routes advertised for the given pod.
+func (prg *podRouteGenerator) unsetRoutesForPod(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.WithError(err).Warn("unsetRoutesForPod: error on retrieving key for object, passing")
+		return
+	}
+
+	prg.Lock()
+	defer prg.Unlock()
+	prg.setRoutesForKey(key, nil)
+}
+
+// setRoutesForKey replaces the set of routes advertised for the given pod key.
+func (prg *podRouteGenerator) setRoutesForKey(key string, routes []string) {
+	var toWithdraw []string
+	for _, route := range prg.podRouteMap[key] {
+		if !contains(routes, route) {
+			toWithdraw = append(toWithdraw, route)
+		}
+	}
+	var toAdvertise []string
+	for _, route := range routes {
+		if !contains(prg.podRouteMap[key], route) {
+			toAdvertise = append(toAdvertise, route)
+		}
+	}
+
+	if len(toWithdraw) > 0 {
+		prg.client.DeleteWorkloadRoutes(toWithdraw)
+	}
+	if len(toAdvertise) > 0 {
+		prg.client.AddWorkloadRoutes(toAdvertise)
+	}
+
+	if len(routes) == 0 {
+		delete(prg.podRouteMap, key)
+	} else {
+		prg.podRouteMap[key] = routes
+	}
+}
+
+// advertiseThisPod returns true if the IPs of the given pod should be advertised from
+// this node, false otherwise.
+func (prg *podRouteGenerator) advertiseThisPod(pod *v1.Pod) bool {
+	logc := log.WithField("pod", pod.Namespace+"/"+pod.Name)
+
+	if strings.ToLower(pod.Annotations[advertisePodIPAnnotation]) != "true" {
+		logc.Debug("Skipping pod without advertisement annotation")
+		return false
+	}
+
+	// Host networked pods share the node's IP, which is advertised by other means.
+	if pod.Spec.HostNetwork {
+		logc.Debug("Skipping host networked pod")
+		return false
+	}
+
+	if pod.Spec.NodeName != prg.nodeName {
+		logc.Debug("Skipping pod on another node")
+		return false
+	}
+
+	if pod.DeletionTimestamp != nil {
+		logc.Debug("Skipping terminating pod")
+		return false
+	}
+
+	// Only advertise pods that are able to serve traffic, so that upstream routers
+	// stop sending traffic to this node as soon as the pod becomes unready.
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			if cond.Status == v1.ConditionTrue {
+				return true
+			}
+			break
+		}
+	}
+	logc.Debug("Skipping pod that is not ready")
+	return false
+}
+
+// onPodAdd is called when a k8s pod is created
+func (prg *podRouteGenerator) onPodAdd(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		log.Warn("onPodAdd: failed to assert type to pod, passing")
+		return
+	}
+	prg.setRoutesForPod(pod)
+}
+
+// onPodUpdate is called when a k8s pod is updated
+func (prg *podRouteGenerator) onPodUpdate(_, obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		log.Warn("onPodUpdate: failed to assert type to pod, passing")
+		return
+	}
+	prg.setRoutesForPod(pod)
+}
+
+// onPodDelete is called when a k8s pod is deleted
+func (prg *podRouteGenerator) onPodDelete(obj interface{}) {
+	prg.unsetRoutesForPod(obj)
+}
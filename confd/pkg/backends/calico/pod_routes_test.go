@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calico
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildAnycastPod(ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "dns",
+			Name:        "anycast",
+			Annotations: map[string]string{advertisePodIPAnnotation: "true"},
+		},
+		Spec: v1.PodSpec{NodeName: "foobar"},
+		Status: v1.PodStatus{
+			PodIPs:     []v1.PodIP{{IP: "192.168.10.5"}, {IP: "fd00::5"}},
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+var _ = Describe("PodRouteGenerator", func() {
+	var prg *podRouteGenerator
+
+	BeforeEach(func() {
+		prg = &podRouteGenerator{
+			nodeName:    "foobar",
+			podRouteMap: make(map[string][]string),
+			client: &client{
+				cache:                   make(map[string]string),
+				syncedOnce:              true,
+				programmedRouteRefCount: make(map[string]int),
+			},
+		}
+		prg.client.watcherCond = sync.NewCond(&prg.client.cacheLock)
+	})
+
+	It("should advertise the IPs of a ready annotated pod", func() {
+		prg.onPodAdd(buildAnycastPod(true))
+		Expect(prg.client.cache["/calico/workloadroutes/192.168.10.5-32"]).To(Equal("192.168.10.5/32"))
+		Expect(prg.client.cache["/calico/workloadroutesv6/fd00::5-128"]).To(Equal("fd00::5/128"))
+		Expect(prg.client.cache).NotTo(HaveKey("/calico/staticroutes/192.168.10.5-32"))
+	})
+
+	It("should not advertise a pod without the annotation", func() {
+		pod := buildAnycastPod(true)
+		pod.Annotations = nil
+		prg.onPodAdd(pod)
+		Expect(prg.client.cache).To(BeEmpty())
+	})
+
+	It("should not advertise a pod that is not ready", func() {
+		prg.onPodAdd(buildAnycastPod(false))
+		Expect(prg.client.cache).To(BeEmpty())
+	})
+
+	It("should not advertise a pod on another node", func() {
+		pod := buildAnycastPod(true)
+		pod.Spec.NodeName = "other"
+		prg.onPodAdd(pod)
+		Expect(prg.client.cache).To(BeEmpty())
+	})
+
+	It("should withdraw the routes when the pod becomes unready", func() {
+		prg.onPodAdd(buildAnycastPod(true))
+		Expect(prg.client.cache).To(HaveLen(2))
+
+		prg.onPodUpdate(nil, buildAnycastPod(false))
+		Expect(prg.client.cache).To(BeEmpty())
+		Expect(prg.podRouteMap).To(BeEmpty())
+	})
+
+	It("should withdraw the routes when the pod is deleted", func() {
+		pod := buildAnycastPod(true)
+		prg.onPodAdd(pod)
+		Expect(prg.client.cache).To(HaveLen(2))
+
+		prg.onPodDelete(pod)
+		Expect(prg.client.cache).To(BeEmpty())
+		Expect(prg.client.programmedRouteRefCount).To(BeEmpty())
+	})
+})
@@ -17,10 +17,13 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -28,11 +31,15 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/projectcalico/calico/confd/pkg/resource/template"
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
 	"github.com/projectcalico/calico/libcalico-go/lib/winutils"
 )
 
 const (
 	envAdvertiseClusterIPs = "CALICO_ADVERTISE_CLUSTER_IPS"
+
+	// How often the service advertisement status is flushed to disk, if it has changed.
+	statusWriteInterval = 2 * time.Second
 )
 
 // routeGenerator defines the data fields
@@ -47,17 +54,18 @@ type routeGenerator struct {
 	svcRouteMap                map[string]map[string]bool
 	routeAdvertisementRefCount map[string]int
 	resyncKnownRoutesTrigger   chan struct{}
+
+	// Advertisement status of each service's IPs, keyed by service.  This is written
+	// to statusPath so that it can be reported in CalicoNodeStatus.
+	svcStatusMap map[string][]apiv3.CalicoNodeServiceAdvertisement
+	statusDirty  bool
+	statusPath   string
 }
 
 // NewRouteGenerator initializes a kube-api client and the informers
 func NewRouteGenerator(c *client) (rg *routeGenerator, err error) {
 	// Determine the node name we'll use to check for local endpoints.
-	// This value should match the name of the node in the Kubernetes API.
-	// Prefer CALICO_K8S_NODE_REF, and fall back to the Calico node name.
-	nodename := template.NodeName
-	if n := os.Getenv("CALICO_K8S_NODE_REF"); n != "" {
-		nodename = n
-	}
+	nodename := k8sNodeName()
 	log.Debugf("Route generator configured to use node name %s", nodename)
 
 	// initialize empty route generator
@@ -67,20 +75,13 @@ func NewRouteGenerator(c *client) (rg *routeGenerator, err error) {
 		svcRouteMap:                make(map[string]map[string]bool),
 		routeAdvertisementRefCount: make(map[string]int),
 		resyncKnownRoutesTrigger:   make(chan struct{}, 1),
+		svcStatusMap:               make(map[string][]apiv3.CalicoNodeServiceAdvertisement),
+		statusDirty:                true,
+		statusPath:                 servicestatus.DefaultPath,
 	}
 
 	// set up k8s client
-	// attempt 1: KUBECONFIG env var
-	cfgFile := os.Getenv("KUBECONFIG")
-	cfg, err := winutils.BuildConfigFromFlags("", cfgFile)
-	if err != nil {
-		log.WithError(err).Info("KUBECONFIG environment variable not found, attempting in-cluster")
-		// attempt 2: in cluster config
-		if cfg, err = winutils.GetInClusterConfig(); err != nil {
-			return
-		}
-	}
-	client, err := kubernetes.NewForConfig(cfg)
+	client, err := newK8sClientset()
 	if err != nil {
 		return
 	}
@@ -98,6 +99,31 @@ func NewRouteGenerator(c *client) (rg *routeGenerator, err error) {
 	return
 }
 
+// k8sNodeName returns the name of this node in the Kubernetes API.
+// Prefer CALICO_K8S_NODE_REF, and fall back to the Calico node name.
+func k8sNodeName() string {
+	if n := os.Getenv("CALICO_K8S_NODE_REF"); n != "" {
+		return n
+	}
+	return template.NodeName
+}
+
+// newK8sClientset creates a kube-api client, using the KUBECONFIG environment
+// variable if set and falling back to the in-cluster config otherwise.
+func newK8sClientset() (*kubernetes.Clientset, error) {
+	// attempt 1: KUBECONFIG env var
+	cfgFile := os.Getenv("KUBECONFIG")
+	cfg, err := winutils.BuildConfigFromFlags("", cfgFile)
+	if err != nil {
+		log.WithError(err).Info("KUBECONFIG environment variable not found, attempting in-cluster")
+		// attempt 2: in cluster config
+		if cfg, err = winutils.GetInClusterConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
 // Start starts the RouteGenerator so that it will monitor Kubernetes services.
 func (rg *routeGenerator) Start() {
 	ch := make(chan struct{})
@@ -115,6 +141,9 @@ func (rg *routeGenerator) Start() {
 		rg.client.OnSyncChange(SourceRouteGenerator, true)
 		log.Info("RouteGenerator in sync")
 
+		// Start reporting the advertisement status of services.
+		go rg.writeStatusLoop()
+
 		// Loop waiting for trigger to recheck node-specific routes.
 		for range rg.resyncKnownRoutesTrigger {
 			rg.resyncKnownRoutes()
@@ -122,6 +151,46 @@ func (rg *routeGenerator) Start() {
 	}()
 }
 
+// writeStatusLoop periodically writes the service advertisement status, if it has changed.
+func (rg *routeGenerator) writeStatusLoop() {
+	for range time.NewTicker(statusWriteInterval).C {
+		rg.Lock()
+		var advertisements []apiv3.CalicoNodeServiceAdvertisement
+		if rg.statusDirty {
+			advertisements = rg.getServiceAdvertisementStatus()
+		}
+		dirty := rg.statusDirty
+		rg.statusDirty = false
+		rg.Unlock()
+
+		if !dirty {
+			continue
+		}
+		if err := servicestatus.Write(rg.statusPath, advertisements); err != nil {
+			log.WithError(err).Warn("Failed to write service advertisement status")
+			rg.Lock()
+			rg.statusDirty = true
+			rg.Unlock()
+		}
+	}
+}
+
+// getServiceAdvertisementStatus returns the advertisement status of all known service IPs,
+// sorted by service.
+func (rg *routeGenerator) getServiceAdvertisementStatus() []apiv3.CalicoNodeServiceAdvertisement {
+	keys := make([]string, 0, len(rg.svcStatusMap))
+	for key := range rg.svcStatusMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	advertisements := []apiv3.CalicoNodeServiceAdvertisement{}
+	for _, key := range keys {
+		advertisements = append(advertisements, rg.svcStatusMap[key]...)
+	}
+	return advertisements
+}
+
 // Called by the client to trigger us to recheck and advertise or withdraw node-specific routes.
 // Must not block since this is called by the client while it holds its lock.
 func (rg *routeGenerator) TriggerResync() {
@@ -201,8 +270,9 @@ func (rg *routeGenerator) setRouteForSvc(svc *v1.Service, ep *v1.Endpoints) {
 	rg.Lock()
 	defer rg.Unlock()
 
-	advertise := rg.advertiseThisService(svc, ep)
-	logCtx.WithField("advertise", advertise).Debug("Checking routes for service")
+	advertise, reason := rg.advertiseThisService(svc, ep)
+	logCtx.WithFields(log.Fields{"advertise": advertise, "reason": reason}).Debug("Checking routes for service")
+	rg.setServiceStatus(key, rg.getServiceIPStatus(svc, advertise, reason))
 	if advertise {
 		routes := rg.getAllRoutesForService(svc)
 		rg.setRoutesForKey(key, routes)
@@ -264,6 +334,75 @@ func (rg *routeGenerator) getAllRoutesForService(svc *v1.Service) []string {
 	return addFullIPLength(routes)
 }
 
+// getServiceIPStatus returns the advertisement status for each of the IPs of the given
+// service, given whether the service is to be advertised from this node and why.
+func (rg *routeGenerator) getServiceIPStatus(svc *v1.Service, advertise bool, reason string) []apiv3.CalicoNodeServiceAdvertisement {
+	svcID := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+	var status []apiv3.CalicoNodeServiceAdvertisement
+	add := func(ip string, ipType apiv3.ServiceIPType, allowed bool, notAllowedReason string) {
+		s := apiv3.CalicoNodeServiceAdvertisement{
+			Service:     svcID,
+			Destination: addFullIPLength([]string{ip})[0],
+			Type:        ipType,
+			Advertised:  advertise,
+			Reason:      reason,
+		}
+		if !allowed {
+			s.Advertised = false
+			s.Reason = notAllowedReason
+		}
+		status = append(status, s)
+	}
+
+	clusterIPs := svc.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && svc.Spec.ClusterIP != "" {
+		clusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	for i, clusterIP := range clusterIPs {
+		if clusterIP == "" || clusterIP == "None" {
+			continue
+		}
+		if i > 0 {
+			// Only the primary cluster IP (which is always ClusterIPs[0]) is advertised; see
+			// getAllRoutesForService.
+			add(clusterIP, apiv3.ServiceIPTypeClusterIP,
+				false, "Only the primary cluster IP of a dual-stack service is advertised")
+			continue
+		}
+		add(clusterIP, apiv3.ServiceIPTypeClusterIP,
+			rg.client.AdvertiseClusterIPs(), "Service cluster IPs are not configured for advertisement")
+	}
+	for _, externalIP := range svc.Spec.ExternalIPs {
+		if externalIP == "" {
+			continue
+		}
+		add(externalIP, apiv3.ServiceIPTypeExternalIP,
+			rg.isAllowedExternalIP(externalIP), "External IP is not within the configured service external IPs")
+	}
+	for _, lbIngress := range svc.Status.LoadBalancer.Ingress {
+		if len(lbIngress.IP) == 0 {
+			continue
+		}
+		add(lbIngress.IP, apiv3.ServiceIPTypeLoadBalancerIP,
+			rg.isAllowedLoadBalancerIP(lbIngress.IP), "LoadBalancer IP is not within the configured service load balancer IPs")
+	}
+	return status
+}
+
+// setServiceStatus records the advertisement status of the IPs of the service with
+// the given key.
+func (rg *routeGenerator) setServiceStatus(key string, status []apiv3.CalicoNodeServiceAdvertisement) {
+	if reflect.DeepEqual(rg.svcStatusMap[key], status) {
+		return
+	}
+	if len(status) == 0 {
+		delete(rg.svcStatusMap, key)
+	} else {
+		rg.svcStatusMap[key] = status
+	}
+	rg.statusDirty = true
+}
+
 // getAdvertisedRoutes returns the routes that are currently advertised and
 // associated with the given key.
 func (rg *routeGenerator) getAdvertisedRoutes(key string) []string {
@@ -427,26 +566,26 @@ func contains(items []string, target string) bool {
 }
 
 // advertiseThisService returns true if this service should be advertised on this node,
-// false otherwise.
-func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints) bool {
+// false otherwise, along with the reason for the decision.
+func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints) (bool, string) {
 	logc := log.WithField("svc", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
 
 	// Don't advertise routes if this node is explicitly excluded from load balancers.
 	if rg.client.ExcludeServiceAdvertisement() {
 		logc.Debug("Skipping service because node is explicitly excluded from load balancers")
-		return false
+		return false, "Node is excluded from external load balancers"
 	}
 
 	// do nothing if the svc is not a relevant type
 	if (svc.Spec.Type != v1.ServiceTypeClusterIP) && (svc.Spec.Type != v1.ServiceTypeNodePort) && (svc.Spec.Type != v1.ServiceTypeLoadBalancer) {
 		logc.Debugf("Skipping service with type %s", svc.Spec.Type)
-		return false
+		return false, fmt.Sprintf("Services of type %s are not advertised", svc.Spec.Type)
 	}
 
 	// also do nothing if the clusterIP is empty or None
 	if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == "None" {
 		logc.Debug("Skipping service with no cluster IP")
-		return false
+		return false, "Service has no cluster IP"
 	}
 
 	// we need to announce single IPs for services of type externalTrafficPolicy Cluster.
@@ -456,14 +595,14 @@ func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints
 	if svc.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeCluster {
 		if svc.Spec.Type == v1.ServiceTypeLoadBalancer && rg.isSingleLoadBalancerIP(svc.Spec.LoadBalancerIP) {
 			logc.Debug("Advertising load balancer of type cluster because of single IP definition")
-			return true
+			return true, "Single LoadBalancer IP with externalTrafficPolicy=Cluster"
 		}
 
 		if svc.Spec.Type == v1.ServiceTypeLoadBalancer || svc.Spec.Type == v1.ServiceTypeNodePort {
 			for _, extIP := range svc.Spec.ExternalIPs {
 				if rg.isSingleExternalIP(extIP) {
 					logc.Debug("Advertising external IP of type cluster because of single IP definition")
-					return true
+					return true, "Single external IP with externalTrafficPolicy=Cluster"
 				}
 			}
 		}
@@ -472,7 +611,7 @@ func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints
 	// we only need to advertise local services, since we advertise the entire cluster IP range.
 	if svc.Spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyTypeLocal {
 		logc.Debugf("Skipping service with non-local external traffic policy '%s'", svc.Spec.ExternalTrafficPolicy)
-		return false
+		return false, "externalTrafficPolicy is not Local, covered by the advertised service CIDRs"
 	}
 
 	isIPv6 := func(ip string) bool { return strings.Contains(ip, ":") }
@@ -485,12 +624,12 @@ func (rg *routeGenerator) advertiseThisService(svc *v1.Service, ep *v1.Endpoints
 		for _, address := range subset.Addresses {
 			if address.NodeName != nil && *address.NodeName == rg.nodeName && isIPv6(address.IP) == svcIsIPv6 {
 				logc.Debugf("Advertising local service")
-				return true
+				return true, "externalTrafficPolicy=Local with local endpoints"
 			}
 		}
 	}
 	logc.Debugf("Skipping service with no local endpoints")
-	return false
+	return false, "externalTrafficPolicy=Local with no local endpoints"
 }
 
 // unsetRouteForSvc removes the route from the svcClusterRouteMap
@@ -509,6 +648,7 @@ func (rg *routeGenerator) unsetRouteForSvc(obj interface{}) {
 
 	routes := rg.getAdvertisedRoutes(key)
 	rg.withdrawRoutesForKey(key, routes)
+	rg.setServiceStatus(key, nil)
 }
 
 // advertiseRoute advertises a route associated with the given key and
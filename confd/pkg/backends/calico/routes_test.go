@@ -138,6 +138,7 @@ var _ = Describe("RouteGenerator", func() {
 			epIndexer:                  cache.NewIndexer(cache.MetaNamespaceKeyFunc, nil),
 			svcRouteMap:                make(map[string]map[string]bool),
 			routeAdvertisementRefCount: make(map[string]int),
+			svcStatusMap:               make(map[string][]apiv3.CalicoNodeServiceAdvertisement),
 			client: &client{
 				cache:                    make(map[string]string),
 				syncedOnce:               true,
@@ -248,6 +249,82 @@ var _ = Describe("RouteGenerator", func() {
 		})
 	})
 
+	Describe("service advertisement status", func() {
+		It("should report why service IPs are advertised or suppressed", func() {
+			svc, ep := buildSimpleService()
+			addEndpointSubset(ep, rg.nodeName)
+			err := rg.epIndexer.Add(ep)
+			Expect(err).NotTo(HaveOccurred())
+
+			rg.setRouteForSvc(svc, nil)
+			Expect(rg.statusDirty).To(BeTrue())
+			Expect(rg.getServiceAdvertisementStatus()).To(Equal([]apiv3.CalicoNodeServiceAdvertisement{
+				{
+					Service:     "foo/bar",
+					Destination: "127.0.0.1/32",
+					Type:        apiv3.ServiceIPTypeClusterIP,
+					Advertised:  true,
+					Reason:      "externalTrafficPolicy=Local with local endpoints",
+				},
+				{
+					Service:     "foo/bar",
+					Destination: "45.12.70.5/32",
+					Type:        apiv3.ServiceIPTypeExternalIP,
+					Advertised:  false,
+					Reason:      "External IP is not within the configured service external IPs",
+				},
+				{
+					Service:     "foo/bar",
+					Destination: "172.217.3.5/32",
+					Type:        apiv3.ServiceIPTypeExternalIP,
+					Advertised:  true,
+					Reason:      "externalTrafficPolicy=Local with local endpoints",
+				},
+			}))
+
+			// Remove the local endpoint, all IPs should now be suppressed.
+			ep.Subsets = nil
+			err = rg.epIndexer.Add(ep)
+			Expect(err).NotTo(HaveOccurred())
+			rg.setRouteForSvc(svc, nil)
+			for _, s := range rg.getServiceAdvertisementStatus() {
+				Expect(s.Advertised).To(BeFalse())
+			}
+			Expect(rg.getServiceAdvertisementStatus()[0].Reason).To(Equal("externalTrafficPolicy=Local with no local endpoints"))
+
+			// Delete the service, it should no longer be reported.
+			rg.unsetRouteForSvc(svc)
+			Expect(rg.getServiceAdvertisementStatus()).To(BeEmpty())
+		})
+
+		It("should report each of the cluster IPs of a dual-stack service", func() {
+			svc, ep := buildSimpleService()
+			svc.Spec.ExternalIPs = nil
+			svc.Spec.ClusterIPs = []string{svc.Spec.ClusterIP, "fd00:96::1"}
+			addEndpointSubset(ep, rg.nodeName)
+			err := rg.epIndexer.Add(ep)
+			Expect(err).NotTo(HaveOccurred())
+
+			rg.setRouteForSvc(svc, nil)
+			Expect(rg.getServiceAdvertisementStatus()).To(Equal([]apiv3.CalicoNodeServiceAdvertisement{
+				{
+					Service:     "foo/bar",
+					Destination: "127.0.0.1/32",
+					Type:        apiv3.ServiceIPTypeClusterIP,
+					Advertised:  true,
+					Reason:      "externalTrafficPolicy=Local with local endpoints",
+				},
+				{
+					Service:     "foo/bar",
+					Destination: "fd00:96::1/128",
+					Type:        apiv3.ServiceIPTypeClusterIP,
+					Advertised:  false,
+					Reason:      "Only the primary cluster IP of a dual-stack service is advertised",
+				},
+			}))
+		})
+	})
+
 	Describe("resourceInformerHandlers", func() {
 		var (
 			svc, svc2, svc3, svc4 *v1.Service
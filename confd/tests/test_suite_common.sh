@@ -36,6 +36,7 @@ execute_test_suite() {
         run_extra_test test_node_mesh_bgp_password
         run_extra_test test_bgp_password
         run_extra_test test_bgp_sourceaddr_gracefulrestart
+        run_extra_test test_bgp_graceful_shutdown
         run_extra_test test_node_deletion
         run_extra_test test_idle_peers
         run_extra_test test_router_id_hash
@@ -950,6 +951,92 @@ EOF
     $CALICOCTL delete node node2
 }
 
+test_bgp_graceful_shutdown() {
+    # Run confd as a background process.
+    echo "Running confd as background process"
+    NODENAME=node1 BGP_LOGSEVERITYSCREEN="debug" confd -confdir=/etc/calico/confd >$LOGPATH/logd1 2>&1 &
+    CONFD_PID=$!
+    echo "Running with PID " $CONFD_PID
+
+    # Turn the node-mesh off.
+    turn_mesh_off
+
+    # Create 2 nodes with a peering between them, and request a graceful shutdown
+    # of node1 with a teardown time far in the future.
+    $CALICOCTL apply -f - <<EOF
+kind: Node
+apiVersion: projectcalico.org/v3
+metadata:
+  name: node1
+  annotations:
+    projectcalico.org/bgp-graceful-shutdown: "2099-01-01T00:00:00Z"
+spec:
+  bgp:
+    ipv4Address: 172.17.0.5/24
+---
+kind: Node
+apiVersion: projectcalico.org/v3
+metadata:
+  name: node2
+spec:
+  bgp:
+    ipv4Address: 172.17.0.6/24
+---
+kind: BGPPeer
+apiVersion: projectcalico.org/v3
+metadata:
+  name: bgppeer-1
+spec:
+  node: node1
+  peerIP: 172.17.0.6
+  asNumber: 64512
+EOF
+
+    # Expect routes to be advertised with the GRACEFUL_SHUTDOWN community.
+    test_confd_templates graceful_shutdown/draining
+
+    # Move the teardown time into the past.
+    $CALICOCTL apply -f - <<EOF
+kind: Node
+apiVersion: projectcalico.org/v3
+metadata:
+  name: node1
+  annotations:
+    projectcalico.org/bgp-graceful-shutdown: "2000-01-01T00:00:00Z"
+spec:
+  bgp:
+    ipv4Address: 172.17.0.5/24
+EOF
+
+    # Expect the BGP sessions to be disabled.
+    test_confd_templates graceful_shutdown/teardown
+
+    # Cancel the graceful shutdown.
+    $CALICOCTL apply -f - <<EOF
+kind: Node
+apiVersion: projectcalico.org/v3
+metadata:
+  name: node1
+spec:
+  bgp:
+    ipv4Address: 172.17.0.5/24
+EOF
+
+    # Expect the BGP sessions to be restored, without the GRACEFUL_SHUTDOWN community.
+    test_confd_templates graceful_shutdown/cancelled
+
+    # Kill confd.
+    kill -9 $CONFD_PID
+
+    # Turn the node-mesh back on.
+    turn_mesh_on
+
+    # Delete remaining resources.
+    $CALICOCTL delete bgppeer bgppeer-1
+    $CALICOCTL delete node node1
+    $CALICOCTL delete node node2
+}
+
 test_node_mesh_bgp_password() {
     # For KDD, run Typha and clean up the output directory.
     if [ "$DATASTORE_TYPE" = kubernetes ]; then
@@ -0,0 +1,331 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_DDOS_H__
+#define __CALI_DDOS_H__
+
+#include <linux/if_ether.h>
+#include <linux/ip.h>
+#include <linux/tcp.h>
+
+#include "bpf.h"
+#include "types.h"
+#include "counters.h"
+#include "reasons.h"
+#include "routes.h"
+#include "parsing.h"
+
+/* The DDoS protection of the host endpoints runs in XDP, before the untracked
+ * policy, for the packets that are not failsafe:
+ *
+ * - packets from the sources in the blocklist are dropped,
+ * - SYNs and UDP packets above the per second limit of their source prefix
+ *   are dropped, or, with SYN cookies enabled, the SYNs to the listening
+ *   sockets of the host are answered with a SYN cookie straight from XDP.
+ *
+ * The traffic from the cluster, i.e. from the hosts and the workloads, is never
+ * blocked or rate limited.
+ */
+
+/* The flags should be kept in sync with constants defined in bpf/ddos/map.go */
+enum cali_ddos_flags {
+	CALI_DDOS_ENABLED	= 0x1,
+	CALI_DDOS_SYN_COOKIES	= 0x2,
+};
+
+struct cali_ddos_cfg {
+	__u32 flags;
+	/* The number of packets per second allowed from a source prefix, no
+	 * limit if zero. */
+	__u32 syn_rate;
+	__u32 udp_rate;
+	/* The length of the source prefixes that share a limit. */
+	__u8 prefix_len_v4;
+	__u8 prefix_len_v6;
+	__u16 __pad;
+};
+
+CALI_MAP_V1(cali_ddos_cfg,
+		BPF_MAP_TYPE_ARRAY,
+		__u32, struct cali_ddos_cfg, 1, 0)
+
+struct cali_ddos_bl_key {
+	__u32 prefixlen;
+	ipv46_addr_t addr;
+};
+
+#ifdef IPVER6
+CALI_MAP_NAMED(cali_v6_ddos_bl, cali_ddos_bl,,
+#else
+CALI_MAP_NAMED(cali_v4_ddos_bl, cali_ddos_bl,,
+#endif
+		BPF_MAP_TYPE_LPM_TRIE,
+		struct cali_ddos_bl_key, __u32,
+		64*1024, BPF_F_NO_PREALLOC)
+
+struct cali_ddos_rl_val {
+	__u64 window_start;
+	__u32 syn;
+	__u32 udp;
+};
+
+#ifdef IPVER6
+CALI_MAP_NAMED(cali_v6_ddos_rl, cali_ddos_rl,,
+#else
+CALI_MAP_NAMED(cali_v4_ddos_rl, cali_ddos_rl,,
+#endif
+		BPF_MAP_TYPE_LRU_HASH,
+		ipv46_addr_t, struct cali_ddos_rl_val,
+		64*1024, 0)
+
+#define DDOS_WINDOW_NS	(1000 * 1000 * 1000ull)
+
+enum ddos_verdict {
+	DDOS_PASS,
+	DDOS_DROP,
+	DDOS_TX,
+};
+
+static CALI_BPF_INLINE ipv46_addr_t ddos_src_prefix(struct cali_tc_ctx *ctx, struct cali_ddos_cfg *cfg)
+{
+#ifdef IPVER6
+	return ip_prefix(ctx->state->ip_src, cfg->prefix_len_v6);
+#else
+	return ip_prefix(ctx->state->ip_src, cfg->prefix_len_v4);
+#endif
+}
+
+/* ddos_over_rate counts the packet against the limit of its source prefix and
+ * returns true if the prefix is over the limit in the current second.
+ */
+static CALI_BPF_INLINE bool ddos_over_rate(struct cali_tc_ctx *ctx, struct cali_ddos_cfg *cfg,
+					   bool syn, __u32 rate)
+{
+	ipv46_addr_t prefix = ddos_src_prefix(ctx, cfg);
+	struct cali_ddos_rl_val *v = cali_ddos_rl_lookup_elem(&prefix);
+	__u64 now = bpf_ktime_get_ns();
+
+	if (!v) {
+		struct cali_ddos_rl_val nv = {
+			.window_start = now,
+			.syn = syn,
+			.udp = !syn,
+		};
+		cali_ddos_rl_update_elem(&prefix, &nv, BPF_ANY);
+		return false;
+	}
+
+	if (now - v->window_start >= DDOS_WINDOW_NS) {
+		/* Racing CPUs may lose a few counts, which is fine for a limit. */
+		v->window_start = now;
+		v->syn = 0;
+		v->udp = 0;
+	}
+
+	if (syn) {
+		__sync_fetch_and_add(&v->syn, 1);
+		return v->syn > rate;
+	}
+	__sync_fetch_and_add(&v->udp, 1);
+	return v->udp > rate;
+}
+
+static CALI_BPF_INLINE __sum16 ddos_csum_fold(__s64 csum)
+{
+	__u32 sum = (__u32)csum;
+
+	sum = (sum & 0xffff) + (sum >> 16);
+	sum = (sum & 0xffff) + (sum >> 16);
+	return (__sum16)~sum;
+}
+
+/* ddos_syn_cookie turns the SYN into a SYN-ACK with a SYN cookie, if there is a
+ * listening socket for it. The kernel validates the cookie when the ACK
+ * arrives. It returns true if the packet is to be transmitted back.
+ */
+static CALI_BPF_INLINE bool ddos_syn_cookie(struct cali_tc_ctx *ctx)
+{
+#if !defined(IPVER6) && defined(BPF_CORE_SUPPORTED)
+	if (!bpf_core_enum_value_exists(enum bpf_func_id, BPF_FUNC_tcp_gen_syncookie) ||
+			!bpf_core_enum_value_exists(enum bpf_func_id, BPF_FUNC_skc_lookup_tcp)) {
+		return false;
+	}
+	if (ctx->ipheader_len != IP_SIZE) {
+		return false;
+	}
+
+	struct tcphdr *th = tcp_hdr(ctx);
+	__u32 thlen = th->doff * 4;
+
+	if (thlen < TCP_SIZE || thlen > 60) {
+		return false;
+	}
+
+	struct bpf_sock_tuple tuple = {
+		.ipv4 = {
+			.saddr = ctx->state->ip_src,
+			.daddr = ctx->state->ip_dst,
+			.sport = th->source,
+			.dport = th->dest,
+		},
+	};
+	struct bpf_sock *sk = bpf_skc_lookup_tcp(ctx->xdp, &tuple, sizeof(tuple.ipv4), BPF_F_CURRENT_NETNS, 0);
+	if (!sk) {
+		CALI_DEBUG("DDoS: no socket for SYN cookie");
+		return false;
+	}
+	if (sk->state != BPF_TCP_LISTEN) {
+		bpf_sk_release(sk);
+		return false;
+	}
+
+	/* The helper needs the headers, with the TCP options, on the stack. */
+	struct iphdr iph;
+	__u8 tcp[60];
+
+	if (bpf_load_bytes(ctx, skb_iphdr_offset(ctx), &iph, IP_SIZE) ||
+			bpf_load_bytes(ctx, skb_l4hdr_offset(ctx), tcp, thlen)) {
+		bpf_sk_release(sk);
+		return false;
+	}
+
+	__s64 value = bpf_tcp_gen_syncookie(sk, &iph, IP_SIZE, tcp, thlen);
+	bpf_sk_release(sk);
+	if (value < 0) {
+		CALI_DEBUG("DDoS: failed to generate SYN cookie %d", value);
+		return false;
+	}
+
+	__u32 cookie = (__u32)value;
+	__u16 mss = (value >> 32) & 0xffff;
+
+	/* Turn the SYN into the SYN-ACK, with just the MSS option. */
+	int len = skb_iphdr_offset(ctx) + IP_SIZE + TCP_SIZE + 4;
+	int delta = len - (int)(ctx->xdp->data_end - ctx->xdp->data);
+
+	if (delta && bpf_xdp_adjust_tail(ctx->xdp, delta)) {
+		return false;
+	}
+	skb_refresh_start_end(ctx);
+	if (ctx->data_start + skb_iphdr_offset(ctx) + IP_SIZE + TCP_SIZE + 4 > ctx->data_end) {
+		return false;
+	}
+
+	struct iphdr *ip = ctx->data_start + skb_iphdr_offset(ctx);
+	struct tcphdr *tcph = (void *)(ip + 1);
+	__u8 *opts = (void *)(tcph + 1);
+
+	if (!CALI_F_L3) {
+		struct ethhdr *eth = ctx->data_start;
+		unsigned char mac[ETH_ALEN];
+
+		__builtin_memcpy(mac, eth->h_source, ETH_ALEN);
+		__builtin_memcpy(eth->h_source, eth->h_dest, ETH_ALEN);
+		__builtin_memcpy(eth->h_dest, mac, ETH_ALEN);
+	}
+
+	__be32 addr = ip->saddr;
+	ip->saddr = ip->daddr;
+	ip->daddr = addr;
+	ip->tos = 0;
+	ip->tot_len = bpf_htons(IP_SIZE + TCP_SIZE + 4);
+	ip->id = 0;
+	ip->frag_off = bpf_htons(0x4000); /* DF */
+	ip->ttl = 64;
+	ip->check = 0;
+	ip->check = ddos_csum_fold(bpf_csum_diff(0, 0, (__u32 *)ip, IP_SIZE, 0));
+
+	__be16 port = tcph->source;
+	tcph->source = tcph->dest;
+	tcph->dest = port;
+	tcph->ack_seq = bpf_htonl(bpf_ntohl(tcph->seq) + 1);
+	tcph->seq = bpf_htonl(cookie);
+	/* doff 6, SYN and ACK. */
+	((__be16 *)tcph)[6] = bpf_htons((6 << 12) | 0x12);
+	tcph->window = bpf_htons(65535);
+	tcph->urg_ptr = 0;
+	tcph->check = 0;
+	opts[0] = 2; /* MSS */
+	opts[1] = 4;
+	opts[2] = mss >> 8;
+	opts[3] = mss & 0xff;
+
+	struct {
+		__be32 saddr;
+		__be32 daddr;
+		__u8 zero;
+		__u8 proto;
+		__be16 len;
+	} pseudo = {
+		.saddr = ip->saddr,
+		.daddr = ip->daddr,
+		.proto = IPPROTO_TCP,
+		.len = bpf_htons(TCP_SIZE + 4),
+	};
+	__s64 csum = bpf_csum_diff(0, 0, (__u32 *)&pseudo, sizeof(pseudo), 0);
+	csum = bpf_csum_diff(0, 0, (__u32 *)tcph, TCP_SIZE + 4, csum);
+	tcph->check = ddos_csum_fold(csum);
+
+	return true;
+#else
+	return false;
+#endif
+}
+
+static CALI_BPF_INLINE enum ddos_verdict ddos_check(struct cali_tc_ctx *ctx)
+{
+	__u32 zero = 0;
+	struct cali_ddos_cfg *cfg = cali_ddos_cfg_lookup_elem(&zero);
+
+	if (!cfg || !(cfg->flags & CALI_DDOS_ENABLED)) {
+		return DDOS_PASS;
+	}
+
+	if (cali_rt_lookup_flags(&ctx->state->ip_src) & (CALI_RT_HOST | CALI_RT_WORKLOAD | CALI_RT_IN_POOL)) {
+		return DDOS_PASS;
+	}
+
+	struct cali_ddos_bl_key key = {
+		.prefixlen = sizeof(ipv46_addr_t) * 8,
+		.addr = ctx->state->ip_src,
+	};
+	if (cali_ddos_bl_lookup_elem(&key)) {
+		CALI_DEBUG("DDoS: source " IP_FMT " in blocklist", debug_ip(ctx->state->ip_src));
+		deny_reason(ctx, CALI_REASON_DDOS_BLOCKLIST);
+		return DDOS_DROP;
+	}
+
+	switch (ctx->state->ip_proto) {
+	case IPPROTO_TCP:
+		{
+			struct tcphdr *th = tcp_hdr(ctx);
+
+			if (!th->syn || th->ack || !cfg->syn_rate) {
+				break;
+			}
+			if (!ddos_over_rate(ctx, cfg, true, cfg->syn_rate)) {
+				break;
+			}
+			if ((cfg->flags & CALI_DDOS_SYN_COOKIES) && ddos_syn_cookie(ctx)) {
+				CALI_DEBUG("DDoS: SYN answered with a SYN cookie");
+				counter_inc(ctx, CALI_REASON_SYN_COOKIE);
+				return DDOS_TX;
+			}
+			CALI_DEBUG("DDoS: SYN over rate limit");
+			deny_reason(ctx, CALI_REASON_DDOS_SYN_RATE);
+			return DDOS_DROP;
+		}
+	case IPPROTO_UDP:
+		if (cfg->udp_rate && ddos_over_rate(ctx, cfg, false, cfg->udp_rate)) {
+			CALI_DEBUG("DDoS: UDP over rate limit");
+			deny_reason(ctx, CALI_REASON_DDOS_UDP_RATE);
+			return DDOS_DROP;
+		}
+		break;
+	}
+
+	return DDOS_PASS;
+}
+
+#endif /* __CALI_DDOS_H__ */
@@ -0,0 +1,98 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_EVENTS_H__
+#define __CALI_EVENTS_H__
+
+#include "bpf.h"
+#include "counters.h"
+#include "ip_addr.h"
+
+/* The event types should be kept in sync with constants defined in
+ * bpf/events/events.go
+ */
+enum calico_event_type {
+	CALI_EVENT_POLICY_DENY = 1,
+	CALI_EVENT_RPF_FAIL,
+	CALI_EVENT_NAT_MISS,
+};
+
+struct calico_event {
+	__u64 timestamp_ns;
+	__u32 type;
+	__u32 ifindex;
+	__u32 hook;
+	__u32 __pad;
+	ipv6_addr_t ip_src;
+	ipv6_addr_t ip_dst;
+	__u16 sport;
+	__u16 dport;
+	__u8 ip_proto;
+	__u8 ipv6;
+	__u8 __pad2[2];
+	__u64 rule_id;
+};
+
+struct calico_events_cfg {
+	/* Report one in every sample_rate events, none if zero. */
+	__u32 sample_rate;
+};
+
+CALI_MAP_V1(cali_ev_cfg,
+		BPF_MAP_TYPE_ARRAY,
+		__u32, struct calico_events_cfg, 1, 0)
+
+/* We use a perf event array rather than a ring buffer as the latter is not
+ * available on all the kernels that we support. Felix sets its size to the
+ * number of possible CPUs.
+ */
+CALI_MAP_V1(cali_events,
+		BPF_MAP_TYPE_PERF_EVENT_ARRAY,
+		__u32, __u32, 1, 0)
+
+static CALI_BPF_INLINE void event_emit(struct cali_tc_ctx *ctx, __u32 type)
+{
+	__u32 key = 0;
+	struct calico_events_cfg *cfg = cali_ev_cfg_lookup_elem(&key);
+
+	if (!cfg || !cfg->sample_rate) {
+		return;
+	}
+	if (cfg->sample_rate > 1 && (bpf_get_prandom_u32() % cfg->sample_rate)) {
+		return;
+	}
+
+	struct calico_event ev = {
+		.timestamp_ns = bpf_ktime_get_ns(),
+		.type = type,
+		.ifindex = ctx->skb->ifindex,
+		.hook = (CALI_F_TO_HEP || CALI_F_TO_WEP) ? COUNTERS_TC_EGRESS : COUNTERS_TC_INGRESS,
+		.sport = ctx->state->sport,
+		.dport = ctx->state->dport,
+		.ip_proto = ctx->state->ip_proto,
+	};
+
+#ifdef IPVER6
+	ev.ip_src = ctx->state->ip_src;
+	ev.ip_dst = ctx->state->ip_dst;
+	ev.ipv6 = 1;
+#else
+	ev.ip_src.a = ctx->state->ip_src;
+	ev.ip_dst.a = ctx->state->ip_dst;
+#endif
+
+	if (type == CALI_EVENT_POLICY_DENY) {
+		__u32 n = ctx->state->rules_hit;
+		if (n > 0 && n <= MAX_RULE_IDS) {
+			ev.rule_id = ctx->state->rule_ids[n - 1];
+		}
+	}
+
+	int err = bpf_perf_event_output(ctx->skb, &cali_events, BPF_F_CURRENT_CPU, &ev, sizeof(ev));
+	if (err) {
+		CALI_DEBUG("Failed to emit event %d: %d", type, err);
+	}
+}
+
+#endif /* __CALI_EVENTS_H__ */
@@ -8,6 +8,7 @@
 #include "types.h"
 #include "skb.h"
 #include "ifstate.h"
+#include "qos.h"
 
 #if CALI_FIB_ENABLED
 #define fwd_fib(fwd)			((fwd)->fib)
@@ -141,8 +142,12 @@ skip_redir_ifindex:
 	} else if (CALI_F_FROM_HEP && bpf_core_enum_value_exists(enum bpf_func_id, BPF_FUNC_redirect_peer)) {
 		bool redirect_peer = GLOBAL_FLAGS & CALI_GLOBALS_REDIRECT_PEER;
 
+		/* Redirecting to the peer skips the qdisc of the workload's interface,
+		 * which paces the traffic of the workloads with bandwidth limits.
+		 */
 		if (redirect_peer && ct_result_rc(state->ct_result.rc) == CALI_CT_ESTABLISHED_BYPASS &&
-			state->ct_result.ifindex_fwd != CT_INVALID_IFINDEX) {
+			state->ct_result.ifindex_fwd != CT_INVALID_IFINDEX &&
+			!qos_ingress_limited(state->ct_result.ifindex_fwd)) {
 			rc = bpf_redirect_peer(state->ct_result.ifindex_fwd, 0);
 			if (rc == TC_ACT_REDIRECT) {
 				CALI_DEBUG("Redirect to peer interface (%d) succeeded.", state->ct_result.ifindex_fwd);
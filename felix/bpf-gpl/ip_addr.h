@@ -120,4 +120,29 @@ typedef ipv4_addr_t ipv46_addr_t;
 				}
 #endif
 
+static CALI_BPF_INLINE __be32 ip_mask32(__be32 w, int bits)
+{
+	if (bits <= 0) {
+		return 0;
+	}
+	if (bits >= 32) {
+		return w;
+	}
+	return w & bpf_htonl(~0u << (32 - bits));
+}
+
+/* ip_prefix returns the first len bits of the address, the rest zeroed. */
+static CALI_BPF_INLINE ipv46_addr_t ip_prefix(ipv46_addr_t ip, int len)
+{
+#ifdef IPVER6
+	ip.a = ip_mask32(ip.a, len);
+	ip.b = ip_mask32(ip.b, len - 32);
+	ip.c = ip_mask32(ip.c, len - 64);
+	ip.d = ip_mask32(ip.d, len - 96);
+#else
+	ip = ip_mask32(ip, len);
+#endif
+	return ip;
+}
+
 #endif /* __CALI_IP_ADDR_H__ */
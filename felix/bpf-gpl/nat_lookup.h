@@ -14,9 +14,47 @@
 #include "routes.h"
 #include "nat_types.h"
 
+static CALI_BPF_INLINE __u32 nat_maglev_mix(__u32 h, __u32 v)
+{
+	v *= 0xcc9e2d51;
+	v = (v << 15) | (v >> 17);
+	v *= 0x1b873593;
+	h ^= v;
+	h = (h << 13) | (h >> 19);
+	return h * 5 + 0xe6546b64;
+}
+
+/* nat_maglev_hash hashes the client side of a flow to pick its slot in a Maglev
+ * lookup table. It does not depend on anything local to this node so that all nodes
+ * behind an ECMP router pick the same backend for the flow.
+ */
+static CALI_BPF_INLINE __u32 nat_maglev_hash(ipv46_addr_t *ip_src, __u16 sport, __u8 ip_proto)
+{
+	__u32 h = ip_proto;
+
+#ifdef IPVER6
+	h = nat_maglev_mix(h, ip_src->a);
+	h = nat_maglev_mix(h, ip_src->b);
+	h = nat_maglev_mix(h, ip_src->c);
+	h = nat_maglev_mix(h, ip_src->d);
+#else
+	h = nat_maglev_mix(h, *ip_src);
+#endif
+	h = nat_maglev_mix(h, sport);
+
+	h ^= h >> 16;
+	h *= 0x85ebca6b;
+	h ^= h >> 13;
+	h *= 0xc2b2ae35;
+	h ^= h >> 16;
+
+	return h;
+}
+
 static CALI_BPF_INLINE struct calico_nat_dest* calico_nat_lookup(ipv46_addr_t *ip_src,
 								 ipv46_addr_t *ip_dst,
 								 __u8 ip_proto,
+								 __u16 sport,
 								 __u16 dport,
 								 bool from_tun,
 								 nat_lookup_result *res,
@@ -158,7 +196,15 @@ static CALI_BPF_INLINE struct calico_nat_dest* calico_nat_lookup(ipv46_addr_t *i
 		.protocol = ip_proto,
 	};
 	affkey.nat_key = nat_data;
-	affkey.client_ip = *ip_src;
+	affkey.prefix_len = nat_lv1_val->flags >> NAT_FLG_AFF_PREFIX_SHIFT;
+	if (affkey.prefix_len) {
+		/* All clients from the prefix share the backend, e.g. when they are
+		 * behind a carrier-grade NAT.
+		 */
+		affkey.client_ip = ip_prefix(*ip_src, affkey.prefix_len);
+	} else {
+		affkey.client_ip = *ip_src;
+	}
 
 	CALI_DEBUG("NAT: backend affinity %d seconds", nat_lv1_val->affinity_timeo ? : affinity_always_timeo);
 
@@ -185,15 +231,36 @@ static CALI_BPF_INLINE struct calico_nat_dest* calico_nat_lookup(ipv46_addr_t *i
 
 skip_affinity:
 	nat_lv2_key.id = nat_lv1_val->id;
-	nat_lv2_key.ordinal = bpf_get_prandom_u32();
-	nat_lv2_key.ordinal %= count;
+	nat_lv2_val = NULL;
 
-	CALI_DEBUG("NAT: 1st level hit; id=%d ordinal=%d", nat_lv2_key.id, nat_lv2_key.ordinal);
+	/* The Maglev lookup table contains all backends of the service, so we can
+	 * only use it if we are not restricted to the local ones. Connect-time
+	 * load balancing does not know the source of the flow, all flows would hash
+	 * to the same backend, so it picks a random one.
+	 */
+	if (!CALI_F_CGROUP && (nat_lv1_val->flags & NAT_FLG_MAGLEV) && count == nat_lv1_val->count) {
+		nat_lv2_key.ordinal = nat_maglev_hash(ip_src, sport, ip_proto);
+		nat_lv2_key.ordinal %= NAT_MAGLEV_LUT_SIZE;
+		nat_lv2_key.ordinal += NAT_MAGLEV_ORDINAL_BASE;
 
-	if (!(nat_lv2_val = cali_nat_be_lookup_elem(&nat_lv2_key))) {
-		CALI_DEBUG("NAT: backend miss");
-		*res = NAT_NO_BACKEND;
-		return NULL;
+		CALI_DEBUG("NAT: maglev lookup; id=%d ordinal=0x%x", nat_lv2_key.id, nat_lv2_key.ordinal);
+
+		if (!(nat_lv2_val = cali_nat_be_lookup_elem(&nat_lv2_key))) {
+			CALI_DEBUG("NAT: maglev miss, picking a random backend");
+		}
+	}
+
+	if (!nat_lv2_val) {
+		nat_lv2_key.ordinal = bpf_get_prandom_u32();
+		nat_lv2_key.ordinal %= count;
+
+		CALI_DEBUG("NAT: 1st level hit; id=%d ordinal=%d", nat_lv2_key.id, nat_lv2_key.ordinal);
+
+		if (!(nat_lv2_val = cali_nat_be_lookup_elem(&nat_lv2_key))) {
+			CALI_DEBUG("NAT: backend miss");
+			*res = NAT_NO_BACKEND;
+			return NULL;
+		}
 	}
 
 	CALI_DEBUG("NAT: backend selected " IP_FMT ":%d", debug_ip(nat_lv2_val->addr), nat_lv2_val->port);
@@ -218,11 +285,11 @@ skip_affinity:
 #if !(CALI_F_XDP) && !(CALI_F_CGROUP)
 static CALI_BPF_INLINE struct calico_nat_dest* calico_nat_lookup_tc(struct cali_tc_ctx *ctx,
 								    ipv46_addr_t *ip_src, ipv46_addr_t *ip_dst,
-								    __u8 ip_proto, __u16 dport,
+								    __u8 ip_proto, __u16 sport, __u16 dport,
 								    bool from_tun,
 								    nat_lookup_result *res)
 {
-	return calico_nat_lookup(ip_src, ip_dst, ip_proto, dport, from_tun, res, 0, false, ctx);
+	return calico_nat_lookup(ip_src, ip_dst, ip_proto, sport, dport, from_tun, res, 0, false, ctx);
 }
 #endif
 
@@ -60,6 +60,12 @@ struct calico_nat_value {
 #define NAT_FLG_EXTERNAL_LOCAL	0x1
 #define NAT_FLG_INTERNAL_LOCAL	0x2
 #define NAT_FLG_NAT_EXCLUDE	0x4
+#define NAT_FLG_MAGLEV		0x8
+
+/* The top byte of the flags is the length of the client prefix that shares the
+ * session affinity, 0 for the whole client address.
+ */
+#define NAT_FLG_AFF_PREFIX_SHIFT	24
 
 #ifdef IPVER6
 CALI_MAP_NAMED(cali_v6_nat_fe, cali_nat_fe, 3,
@@ -84,6 +90,13 @@ struct calico_nat_dest {
 	__u8 pad[2];
 };
 
+/* Services with NAT_FLG_MAGLEV have a Maglev lookup table of NAT_MAGLEV_LUT_SIZE
+ * backends stored at ordinals starting at NAT_MAGLEV_ORDINAL_BASE. Must match
+ * MaglevLUTSize and MaglevOrdinalBase in felix/bpf/nat.
+ */
+#define NAT_MAGLEV_LUT_SIZE	1009
+#define NAT_MAGLEV_ORDINAL_BASE	0x80000000
+
 #ifdef IPVER6
 CALI_MAP_NAMED(cali_v6_nat_be, cali_nat_be,,
 #else
@@ -96,7 +109,7 @@ CALI_MAP_NAMED(cali_v4_nat_be, cali_nat_be,,
 struct calico_nat_affinity_key {
 	struct calico_nat nat_key;
 	ipv46_addr_t client_ip;
-	__u32 padding;
+	__u32 prefix_len;
 };
 
 struct calico_nat_affinity_val {
@@ -0,0 +1,139 @@
+// Project Calico BPF dataplane programs.
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+#ifndef __CALI_QOS_H__
+#define __CALI_QOS_H__
+
+#include <linux/if_ether.h>
+#include <linux/ipv6.h>
+
+#include "bpf.h"
+#include "ip_addr.h"
+#include "routes.h"
+
+/* The bandwidth of the workloads is limited by setting the earliest departure
+ * time (EDT) of their packets, which the fq qdisc of the interface that the
+ * packets leave by respects.
+ *
+ * The traffic to a workload is paced when it leaves the host side of the
+ * workload's interface. The traffic from a workload is paced when it leaves a
+ * host interface, because forwarding clears the departure time. The traffic
+ * between the workloads on the same host is therefore only limited by the
+ * ingress limit of the receiving workload.
+ */
+
+struct cali_qos_key {
+	__u32 ifindex; /* The ifindex of the workload's interface. */
+	__u32 egress;  /* 1 for the traffic from the workload, 0 for the traffic to it. */
+};
+
+struct cali_qos_val {
+	__u64 rate_bytes_per_sec;
+	__u64 t_last; /* The departure time of the last packet. */
+};
+
+CALI_MAP_V1(cali_qos,
+		BPF_MAP_TYPE_HASH,
+		struct cali_qos_key, struct cali_qos_val, 64*1024, BPF_F_NO_PREALLOC)
+
+/* Packets that would leave later than the horizon are dropped so that a queue
+ * does not build up in the fq qdisc.
+ */
+#define QOS_HORIZON_NS	(2 * 1000 * 1000 * 1000ull)
+#define NSEC_PER_SEC	(1000 * 1000 * 1000ull)
+
+/* qos_edt_sched sets the departure time of the packet according to the given
+ * limit. It returns false if the packet should be dropped.
+ */
+static CALI_BPF_INLINE bool qos_edt_sched(struct __sk_buff *skb, struct cali_qos_key *key)
+{
+	struct cali_qos_val *val = cali_qos_lookup_elem(key);
+
+	if (!val || !val->rate_bytes_per_sec) {
+		return true;
+	}
+
+	__u64 now = bpf_ktime_get_ns();
+	__u64 delay = (__u64)skb->len * NSEC_PER_SEC / val->rate_bytes_per_sec;
+	__u64 t = skb->tstamp;
+	__u64 t_next;
+
+	if (t < now) {
+		t = now;
+	}
+	t_next = val->t_last + delay;
+	if (t_next <= t) {
+		/* The workload was below its limit, the packet may leave now. */
+		val->t_last = t;
+		return true;
+	}
+	if (t_next - now >= QOS_HORIZON_NS) {
+		return false;
+	}
+	val->t_last = t_next;
+	skb->tstamp = t_next;
+
+	return true;
+}
+
+/* qos_to_wep paces the traffic to the workload. */
+static CALI_BPF_INLINE bool qos_to_wep(struct __sk_buff *skb)
+{
+	struct cali_qos_key key = {
+		.ifindex = skb->ifindex,
+		.egress = 0,
+	};
+
+	return qos_edt_sched(skb, &key);
+}
+
+/* qos_to_hep paces the traffic from the local workloads. */
+static CALI_BPF_INLINE bool qos_to_hep(struct __sk_buff *skb)
+{
+	ipv46_addr_t src;
+	__u32 offset = CALI_F_L3 ? 0 : sizeof(struct ethhdr);
+
+#ifdef IPVER6
+	if (skb->protocol != bpf_htons(ETH_P_IPV6)) {
+		return true;
+	}
+	offset += offsetof(struct ipv6hdr, saddr);
+#else
+	if (skb->protocol != bpf_htons(ETH_P_IP)) {
+		return true;
+	}
+	offset += offsetof(struct iphdr, saddr);
+#endif
+	if (bpf_skb_load_bytes(skb, offset, &src, sizeof(src))) {
+		return true;
+	}
+
+	struct cali_rt *rt = cali_rt_lookup(&src);
+	if (!rt || !cali_rt_flags_local_workload(rt->flags)) {
+		return true;
+	}
+
+	struct cali_qos_key key = {
+		.ifindex = rt->if_index,
+		.egress = 1,
+	};
+
+	return qos_edt_sched(skb, &key);
+}
+
+/* qos_ingress_limited returns true if the traffic to the workload with the
+ * given interface is paced, so it must go through the host side of the
+ * workload's interface.
+ */
+static CALI_BPF_INLINE bool qos_ingress_limited(__u32 ifindex)
+{
+	struct cali_qos_key key = {
+		.ifindex = ifindex,
+		.egress = 0,
+	};
+
+	return cali_qos_lookup_elem(&key) != NULL;
+}
+
+#endif /* __CALI_QOS_H__ */
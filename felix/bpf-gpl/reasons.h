@@ -23,6 +23,11 @@ enum calico_reason {
 	CALI_REASON_UNAUTH_SOURCE,
 	CALI_REASON_RT_UNKNOWN,
 	CALI_REASON_BLACK_HOLE,
+	CALI_REASON_DDOS_BLOCKLIST,
+	CALI_REASON_DDOS_SYN_RATE,
+	CALI_REASON_DDOS_UDP_RATE,
+	CALI_REASON_SYN_COOKIE,
+	COUNTER_DROPPED_BY_QOS,
 	CALI_REASON_ACCEPTED_BY_XDP, // Not used by counters map
 	CALI_REASON_WEP_NOT_READY,
 	CALI_REASON_NATIFACE,
@@ -54,6 +54,8 @@
 #include "metadata.h"
 #include "bpf_helpers.h"
 #include "rule_counters.h"
+#include "events.h"
+#include "qos.h"
 
 #define HAS_HOST_CONFLICT_PROG CALI_F_TO_HEP
 
@@ -75,6 +77,18 @@ int calico_tc_main(struct __sk_buff *skb)
 		return TC_ACT_UNSPEC;
 	}
 
+	/* Pace the packets of the workloads with bandwidth limits before anything
+	 * else as the packets that bypass the rest of the processing count too.
+	 */
+	if ((CALI_F_TO_WEP && !qos_to_wep(skb)) ||
+			(CALI_F_TO_HEP && !CALI_F_LO && !qos_to_hep(skb))) {
+		counters_t *counters = counters_get(skb->ifindex);
+		if (counters) {
+			(*counters)[COUNTER_DROPPED_BY_QOS]++;
+		}
+		return TC_ACT_SHOT;
+	}
+
 	/* Optimisation: if another BPF program has already pre-approved the packet,
 	 * skip all processing. */
 	if (CALI_F_FROM_HOST && skb->mark == CALI_SKB_MARK_BYPASS) {
@@ -322,6 +336,7 @@ static CALI_BPF_INLINE void calico_tc_process_ct_lookup(struct cali_tc_ctx *ctx)
 	}
 
 	if (ct_result_rpf_failed(ctx->state->ct_result.rc)) {
+		event_emit(ctx, CALI_EVENT_RPF_FAIL);
 		goto deny;
 	}
 
@@ -404,7 +419,7 @@ static CALI_BPF_INLINE void calico_tc_process_ct_lookup(struct cali_tc_ctx *ctx)
 	if (CALI_F_TO_HOST || (CALI_F_FROM_HOST && !skb_seen(ctx->skb) && !ctx->nat_dest /* no sport conflict */)) {
 		ctx->nat_dest = calico_nat_lookup_tc(ctx,
 						     &ctx->state->ip_src, &ctx->state->ip_dst,
-						     ctx->state->ip_proto, ctx->state->dport,
+						     ctx->state->ip_proto, ctx->state->sport, ctx->state->dport,
 						     !ip_void(ctx->state->tun_ip), &nat_res);
 	}
 
@@ -418,6 +433,7 @@ static CALI_BPF_INLINE void calico_tc_process_ct_lookup(struct cali_tc_ctx *ctx)
 		ctx->state->post_nat_dport = ctx->nat_dest->port;
 	} else if (nat_res == NAT_NO_BACKEND) {
 		/* send icmp port unreachable if there is no backend for a service */
+		event_emit(ctx, CALI_EVENT_NAT_MISS);
 #ifdef IPVER6
 		ctx->state->icmp_type = ICMPV6_DEST_UNREACH;
 		ctx->state->icmp_code = ICMPV6_PORT_UNREACH;
@@ -449,6 +465,7 @@ syn_force_policy:
 			ctx->state->ip_proto != IPPROTO_ICMPV6 &&
 #endif
 			!hep_rpf_check(ctx)) {
+			event_emit(ctx, CALI_EVENT_RPF_FAIL);
 			goto deny;
 		}
 	}
@@ -471,6 +488,7 @@ syn_force_policy:
 		struct cali_rt *r = cali_rt_lookup(&ctx->state->ip_src);
 		/* Do RPF check since it's our responsibility to police that. */
 		if (!wep_rpf_check(ctx, r)) {
+			event_emit(ctx, CALI_EVENT_RPF_FAIL);
 			goto deny;
 		}
 
@@ -1959,6 +1977,7 @@ int calico_tc_skb_drop(struct __sk_buff *skb)
 	update_rule_counters(ctx);
 	skb_log(ctx, false);
 	counter_inc(ctx, CALI_REASON_DROPPED_BY_POLICY);
+	event_emit(ctx, CALI_EVENT_POLICY_DENY);
 
 	CALI_DEBUG("proto=%d", ctx->state->ip_proto);
 	CALI_DEBUG("src=" IP_FMT " dst=" IP_FMT "", debug_ip(ctx->state->ip_src),
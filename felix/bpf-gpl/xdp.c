@@ -30,6 +30,7 @@
 #include "policy.h"
 #include "metadata.h"
 #include "globals.h"
+#include "ddos.h"
 
 /* calico_xdp is the main function used in all of the xdp programs */
 SEC("xdp")
@@ -114,6 +115,15 @@ int calico_xdp_main(struct xdp_md *xdp)
 		goto allow;
 	}
 
+	switch (ddos_check(ctx)) {
+	case DDOS_DROP:
+		goto deny;
+	case DDOS_TX:
+		return XDP_TX;
+	default:
+		break;
+	}
+
 	// Jump to the policy program
 	CALI_DEBUG("About to jump to policy program at %d", ctx->xdp_globals->jumps[PROG_INDEX_POLICY]);
 	CALI_JUMP_TO_POLICY(ctx);
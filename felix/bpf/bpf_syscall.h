@@ -113,6 +113,17 @@ void bpf_attr_setup_prog_run(union bpf_attr *attr, __u32 prog_fd,
    attr->test.repeat = repeat;
 }
 
+// bpf_attr_setup_prog_run_ctx sets up the context of a BPF_PROG_TEST_RUN, for example
+// the __sk_buff of a TC program.
+void bpf_attr_setup_prog_run_ctx(union bpf_attr *attr,
+                                 __u32 ctx_size_in, void *ctx_in,
+                                 __u32 ctx_size_out, void *ctx_out) {
+   attr->test.ctx_size_in = ctx_size_in;
+   attr->test.ctx_size_out = ctx_size_out;
+   attr->test.ctx_in = (__u64)(unsigned long)ctx_in;
+   attr->test.ctx_out = (__u64)(unsigned long)ctx_out;
+}
+
 // bpf_attr_setup_get_info sets up the bpf_attr union for use with BPF_OBJ_GET_INFO_BY_FD.
 // A C function makes this easier because unions aren't easy to access from Go.
 void bpf_attr_setup_get_info(union bpf_attr *attr, __u32 map_fd,
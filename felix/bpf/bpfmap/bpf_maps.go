@@ -23,6 +23,8 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/arp"
 	"github.com/projectcalico/calico/felix/bpf/conntrack"
 	"github.com/projectcalico/calico/felix/bpf/counters"
+	"github.com/projectcalico/calico/felix/bpf/ddos"
+	"github.com/projectcalico/calico/felix/bpf/events"
 	"github.com/projectcalico/calico/felix/bpf/failsafes"
 	"github.com/projectcalico/calico/felix/bpf/hook"
 	"github.com/projectcalico/calico/felix/bpf/ifstate"
@@ -30,6 +32,7 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/jump"
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/qos"
 	"github.com/projectcalico/calico/felix/bpf/routes"
 	"github.com/projectcalico/calico/felix/bpf/state"
 )
@@ -45,6 +48,8 @@ type IPMaps struct {
 	CtMap        maps.Map
 	SrMsgMap     maps.Map
 	CtNatsMap    maps.Map
+	BlocklistMap maps.Map
+	RateLimitMap maps.Map
 }
 
 type CommonMaps struct {
@@ -56,6 +61,10 @@ type CommonMaps struct {
 	JumpMap         maps.MapWithDeleteIfExists
 	XDPProgramsMap  maps.Map
 	XDPJumpMap      maps.MapWithDeleteIfExists
+	EventsMap       maps.Map
+	EventsConfigMap maps.Map
+	QoSMap          maps.Map
+	DDoSConfigMap   maps.Map
 }
 
 type Maps struct {
@@ -87,6 +96,10 @@ func getCommonMaps() *CommonMaps {
 		JumpMap:         jump.Map().(maps.MapWithDeleteIfExists),
 		XDPProgramsMap:  hook.NewXDPProgramsMap(),
 		XDPJumpMap:      jump.XDPMap().(maps.MapWithDeleteIfExists),
+		EventsMap:       events.Map(),
+		EventsConfigMap: events.ConfigMap(),
+		QoSMap:          qos.Map(),
+		DDoSConfigMap:   ddos.ConfigMap(),
 	}
 }
 
@@ -116,9 +129,21 @@ func getIPMaps(ipFamily int) *IPMaps {
 		CtMap:        getmap(conntrack.Map, conntrack.MapV6),
 		SrMsgMap:     getmap(nat.SendRecvMsgMap, nat.SendRecvMsgMapV6),
 		CtNatsMap:    getmap(nat.AllNATsMsgMap, nat.AllNATsMsgMapV6),
+		BlocklistMap: getmap(ddos.BlocklistMap, ddos.BlocklistMapV6),
+		RateLimitMap: getmap(ddos.RateLimitMap, ddos.RateLimitMapV6),
 	}
 }
 
+// All returns the maps of both IP families, without opening or creating them.
+func All() []maps.Map {
+	m := &Maps{
+		CommonMaps: getCommonMaps(),
+		V4:         getIPMaps(4),
+		V6:         getIPMaps(6),
+	}
+	return m.slice()
+}
+
 func CreateBPFMaps(ipV6Enabled bool) (*Maps, error) {
 	ret := new(Maps)
 
@@ -168,6 +193,10 @@ func (c *CommonMaps) slice() []maps.Map {
 		c.JumpMap,
 		c.XDPProgramsMap,
 		c.XDPJumpMap,
+		c.EventsMap,
+		c.EventsConfigMap,
+		c.QoSMap,
+		c.DDoSConfigMap,
 	}
 }
 
@@ -183,6 +212,8 @@ func (i *IPMaps) slice() []maps.Map {
 		i.CtMap,
 		i.SrMsgMap,
 		i.CtNatsMap,
+		i.BlocklistMap,
+		i.RateLimitMap,
 	}
 }
 
@@ -0,0 +1,207 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	v2 "github.com/projectcalico/calico/felix/bpf/conntrack/v2"
+	curVer "github.com/projectcalico/calico/felix/bpf/conntrack/v3"
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+// The states of TCP entries, as returned by EntryState.
+const (
+	StateSynSent     = "SYN-SENT"
+	StateEstablished = "ESTABLISHED"
+	StateClosed      = "CLOSED"
+)
+
+// EntryState returns the state of a TCP entry, "" for other protocols and for the NAT
+// forward entries, which do not track it.
+func EntryState(k KeyInterface, v ValueInterface) string {
+	if k.Proto() != ProtoTCP || v.Type() == TypeNATForward {
+		return ""
+	}
+
+	d := v.Data()
+	switch {
+	case (v.IsForwardDSR() && d.FINsSeenDSR()) || d.FINsSeen() || d.RSTSeen():
+		return StateClosed
+	case d.Established():
+		return StateEstablished
+	default:
+		return StateSynSent
+	}
+}
+
+// UpgradeV2 converts an entry of the version 2 map to the current format so that it can
+// be filtered and summarised like the current entries.
+func UpgradeV2(k v2.Key, v v2.Value) (KeyInterface, ValueInterface) {
+	return k.Upgrade().(curVer.Key), v.Upgrade().(curVer.Value)
+}
+
+// Filter selects conntrack entries.  The zero Filter matches all the entries; each set
+// field narrows the selection.
+type Filter struct {
+	// CIDRs match the entries with either address, or the original destination or
+	// source of NAT entries, in one of them.
+	CIDRs []ip.CIDR
+	// Port matches the entries with either port, or the original port of NAT entries,
+	// equal to it.
+	Port  uint16
+	Proto uint8
+	// NATOnly matches only the NAT entries.
+	NATOnly bool
+	// State matches the TCP entries in that state, see EntryState.
+	State string
+	// MinAge and MaxAge match the entries created at least, or at most, that long ago.
+	MinAge time.Duration
+	MaxAge time.Duration
+}
+
+// ParseState returns the state matching the given case-insensitive name.
+func ParseState(s string) (string, error) {
+	for _, state := range []string{StateSynSent, StateEstablished, StateClosed} {
+		if strings.EqualFold(s, state) {
+			return state, nil
+		}
+	}
+	return "", fmt.Errorf("unknown conntrack state %q, expected %s, %s or %s",
+		s, StateSynSent, StateEstablished, StateClosed)
+}
+
+// Match returns whether the entry matches the filter.  now is the current time as
+// returned by bpf.KTimeNanos().
+func (f *Filter) Match(k KeyInterface, v ValueInterface, now int64) bool {
+	if f.Proto != 0 && k.Proto() != f.Proto {
+		return false
+	}
+
+	isNAT := v.Type() == TypeNATForward || v.Type() == TypeNATReverse
+	if f.NATOnly && !isNAT {
+		return false
+	}
+
+	if f.Port != 0 {
+		ports := []uint16{k.PortA(), k.PortB()}
+		if v.Type() == TypeNATReverse {
+			ports = append(ports, v.OrigPort())
+		}
+		if !containsPort(ports, f.Port) {
+			return false
+		}
+	}
+
+	if len(f.CIDRs) > 0 {
+		addrs := []net.IP{k.AddrA(), k.AddrB()}
+		if v.Type() == TypeNATReverse {
+			addrs = append(addrs, v.OrigIP(), v.OrigSrcIP())
+		}
+		if !f.containsAddr(addrs) {
+			return false
+		}
+	}
+
+	if f.State != "" && EntryState(k, v) != f.State {
+		return false
+	}
+
+	age := time.Duration(now - v.Created())
+	if f.MinAge != 0 && age < f.MinAge {
+		return false
+	}
+	if f.MaxAge != 0 && age > f.MaxAge {
+		return false
+	}
+
+	return true
+}
+
+func containsPort(ports []uint16, port uint16) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) containsAddr(addrs []net.IP) bool {
+	for _, a := range addrs {
+		if a == nil || a.IsUnspecified() {
+			continue
+		}
+		addr := ip.FromNetIP(a)
+		if addr == nil {
+			continue
+		}
+		for _, cidr := range f.CIDRs {
+			if cidr.Contains(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Summary counts the conntrack entries by type, protocol, state and endpoint address.
+type Summary struct {
+	Total   int            `json:"total"`
+	ByType  map[string]int `json:"byType"`
+	ByProto map[uint8]int  `json:"byProto"`
+	// ByState counts the TCP entries by their state.
+	ByState map[string]int `json:"byState"`
+	// ByAddr counts the entries of each address on either side of them.
+	ByAddr map[string]int `json:"byAddr"`
+}
+
+func NewSummary() *Summary {
+	return &Summary{
+		ByType:  map[string]int{},
+		ByProto: map[uint8]int{},
+		ByState: map[string]int{},
+		ByAddr:  map[string]int{},
+	}
+}
+
+// TypeString returns the name of the type of an entry.
+func TypeString(t uint8) string {
+	switch t {
+	case TypeNormal:
+		return "normal"
+	case TypeNATForward:
+		return "nat-forward"
+	case TypeNATReverse:
+		return "nat-reverse"
+	}
+	return fmt.Sprintf("unknown(%d)", t)
+}
+
+func (s *Summary) Add(k KeyInterface, v ValueInterface) {
+	s.Total++
+	s.ByType[TypeString(v.Type())]++
+	s.ByProto[k.Proto()]++
+	if state := EntryState(k, v); state != "" {
+		s.ByState[state]++
+	}
+	s.ByAddr[k.AddrA().String()]++
+	if !k.AddrB().Equal(k.AddrA()) {
+		s.ByAddr[k.AddrB().String()]++
+	}
+}
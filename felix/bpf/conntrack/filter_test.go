@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conntrack_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	v2 "github.com/projectcalico/calico/felix/bpf/conntrack/v2"
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+var _ = Describe("BPF Conntrack filter", func() {
+	const now = int64(100 * time.Second)
+
+	established := conntrack.Leg{SynSeen: true, AckSeen: true}
+	tcpKey := conntrack.NewKey(conntrack.ProtoTCP, net.ParseIP("10.0.0.1"), 5000, net.ParseIP("10.0.0.2"), 80)
+	udpKey := conntrack.NewKey(conntrack.ProtoUDP, net.ParseIP("10.0.0.1"), 5000, net.ParseIP("10.0.0.3"), 53)
+
+	tcpEstablished := conntrack.NewValueNormal(90*time.Second, 99*time.Second, 0, established, established)
+	tcpSynSent := conntrack.NewValueNormal(99*time.Second, 99*time.Second, 0, conntrack.Leg{SynSeen: true}, conntrack.Leg{})
+	natReverse := conntrack.NewValueNATReverse(50*time.Second, 99*time.Second, 0, established, established,
+		nil, net.ParseIP("10.96.0.10"), 8080)
+
+	table.DescribeTable("matching entries",
+		func(f conntrack.Filter, k conntrack.Key, v conntrack.Value, expected bool) {
+			Expect(f.Match(k, v, now)).To(Equal(expected))
+		},
+		table.Entry("empty filter", conntrack.Filter{}, udpKey, tcpSynSent, true),
+		table.Entry("proto", conntrack.Filter{Proto: conntrack.ProtoUDP}, tcpKey, tcpEstablished, false),
+		table.Entry("port A", conntrack.Filter{Port: 5000}, tcpKey, tcpEstablished, true),
+		table.Entry("port B", conntrack.Filter{Port: 80}, tcpKey, tcpEstablished, true),
+		table.Entry("other port", conntrack.Filter{Port: 81}, tcpKey, tcpEstablished, false),
+		table.Entry("NAT original port", conntrack.Filter{Port: 8080}, tcpKey, natReverse, true),
+		table.Entry("CIDR", conntrack.Filter{CIDRs: []ip.CIDR{ip.MustParseCIDROrIP("10.0.0.2/32")}},
+			tcpKey, tcpEstablished, true),
+		table.Entry("other CIDR", conntrack.Filter{CIDRs: []ip.CIDR{ip.MustParseCIDROrIP("10.0.1.0/24")}},
+			tcpKey, tcpEstablished, false),
+		table.Entry("NAT original destination", conntrack.Filter{CIDRs: []ip.CIDR{ip.MustParseCIDROrIP("10.96.0.0/12")}},
+			tcpKey, natReverse, true),
+		table.Entry("NAT only", conntrack.Filter{NATOnly: true}, tcpKey, tcpEstablished, false),
+		table.Entry("NAT only with NAT", conntrack.Filter{NATOnly: true}, tcpKey, natReverse, true),
+		table.Entry("state", conntrack.Filter{State: conntrack.StateEstablished}, tcpKey, tcpEstablished, true),
+		table.Entry("other state", conntrack.Filter{State: conntrack.StateEstablished}, tcpKey, tcpSynSent, false),
+		table.Entry("state of UDP", conntrack.Filter{State: conntrack.StateEstablished}, udpKey, tcpEstablished, false),
+		table.Entry("min age", conntrack.Filter{MinAge: 5 * time.Second}, tcpKey, tcpEstablished, true),
+		table.Entry("min age too young", conntrack.Filter{MinAge: 5 * time.Second}, tcpKey, tcpSynSent, false),
+		table.Entry("max age", conntrack.Filter{MaxAge: 5 * time.Second}, tcpKey, tcpSynSent, true),
+		table.Entry("max age too old", conntrack.Filter{MaxAge: 5 * time.Second}, tcpKey, tcpEstablished, false),
+	)
+
+	It("should filter upgraded v2 entries", func() {
+		k, v := conntrack.UpgradeV2(
+			v2.NewKey(conntrack.ProtoTCP, net.ParseIP("10.0.0.1"), 5000, net.ParseIP("10.0.0.2"), 80),
+			v2.NewValueNormal(90*time.Second, 99*time.Second, 0,
+				v2.Leg{SynSeen: true, AckSeen: true}, v2.Leg{SynSeen: true, AckSeen: true}),
+		)
+		f := conntrack.Filter{Port: 80, State: conntrack.StateEstablished}
+		Expect(f.Match(k, v, now)).To(BeTrue())
+	})
+
+	It("should parse states", func() {
+		state, err := conntrack.ParseState("established")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(conntrack.StateEstablished))
+		_, err = conntrack.ParseState("listening")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should summarise entries", func() {
+		s := conntrack.NewSummary()
+		s.Add(tcpKey, tcpEstablished)
+		s.Add(tcpKey, natReverse)
+		s.Add(udpKey, tcpSynSent)
+
+		Expect(s.Total).To(Equal(3))
+		Expect(s.ByType).To(Equal(map[string]int{"normal": 2, "nat-reverse": 1}))
+		Expect(s.ByProto).To(Equal(map[uint8]int{conntrack.ProtoTCP: 2, conntrack.ProtoUDP: 1}))
+		Expect(s.ByState).To(Equal(map[string]int{conntrack.StateEstablished: 2}))
+		Expect(s.ByAddr).To(Equal(map[string]int{"10.0.0.1": 3, "10.0.0.2": 2, "10.0.0.3": 1}))
+	})
+})
@@ -27,7 +27,7 @@ import (
 )
 
 const (
-	MaxCounterNumber    int = 14
+	MaxCounterNumber    int = 20
 	counterMapKeySize   int = 8
 	counterMapValueSize int = 8
 )
@@ -56,6 +56,10 @@ func (k Key) IfIndex() int {
 	return int(binary.LittleEndian.Uint32(k[:4]))
 }
 
+func (k Key) Hook() hook.Hook {
+	return hook.Hook(binary.LittleEndian.Uint32(k[4:8]))
+}
+
 // The following values are used as index to counters map, and should be kept in sync
 // with constants defined in bpf-gpl/reasons.h.
 const (
@@ -73,12 +77,19 @@ const (
 	DroppedUnauthSource
 	DroppedUnknownRoute
 	DroppedBlackholeRoute
+	DroppedByDDoSBlocklist
+	DroppedBySYNRateLimit
+	DroppedByUDPRateLimit
+	RepliedWithSYNCookie
+	DroppedByQoS
 )
 
 type Description struct {
 	Category string
 	Caption  string
 	Counter  int
+	// Name is used as the reason label of the Prometheus metrics.
+	Name string
 }
 
 type DescList []Description
@@ -102,58 +113,97 @@ var descriptions DescList = DescList{
 	{
 		Counter:  TotalPackets,
 		Category: "Total", Caption: "packets",
+		Name: "total",
 	},
 	{
 		Counter:  AcceptedByFailsafe,
 		Category: "Accepted", Caption: "by failsafe",
+		Name: "failsafe",
 	},
 	{
 		Counter:  AcceptedByPolicy,
 		Category: "Accepted", Caption: "by policy",
+		Name: "policy",
 	},
 	{
 		Counter:  AcceptedByAnotherProgram,
 		Category: "Accepted", Caption: "by another program",
+		Name: "another_program",
 	},
 	{
 		Counter:  DroppedByPolicy,
 		Category: "Dropped", Caption: "by policy",
+		Name: "policy",
 	},
 	{
 		Counter:  DroppedShortPacket,
 		Category: "Dropped", Caption: "too short packets",
+		Name: "short_packet",
 	},
 	{
 		Counter:  DroppedFailedCSUM,
 		Category: "Dropped", Caption: "incorrect checksum",
+		Name: "checksum",
 	},
 	{
 		Counter:  DroppedIPOptions,
 		Category: "Dropped", Caption: "packets with unsupported IP options",
+		Name: "ip_options",
 	},
 	{
 		Counter:  DroppedIPMalformed,
 		Category: "Dropped", Caption: "malformed IP packets",
+		Name: "ip_malformed",
 	},
 	{
 		Counter:  DroppedFailedEncap,
 		Category: "Dropped", Caption: "failed encapsulation",
+		Name: "encap_failed",
 	},
 	{
 		Counter:  DroppedFailedDecap,
 		Category: "Dropped", Caption: "failed decapsulation",
+		Name: "decap_failed",
 	},
 	{
 		Counter:  DroppedUnauthSource,
 		Category: "Dropped", Caption: "packets with unknown source",
+		Name: "unauthorized_source",
 	},
 	{
 		Counter:  DroppedUnknownRoute,
 		Category: "Dropped", Caption: "packets with unknown route",
+		Name: "unknown_route",
 	},
 	{
 		Counter:  DroppedBlackholeRoute,
 		Category: "Dropped", Caption: "packets hitting blackhole route",
+		Name: "blackhole_route",
+	},
+	{
+		Counter:  DroppedByDDoSBlocklist,
+		Category: "Dropped", Caption: "by DDoS blocklist",
+		Name: "ddos_blocklist",
+	},
+	{
+		Counter:  DroppedBySYNRateLimit,
+		Category: "Dropped", Caption: "by SYN rate limit",
+		Name: "syn_rate_limit",
+	},
+	{
+		Counter:  DroppedByUDPRateLimit,
+		Category: "Dropped", Caption: "by UDP rate limit",
+		Name: "udp_rate_limit",
+	},
+	{
+		Counter:  RepliedWithSYNCookie,
+		Category: "Replied", Caption: "with SYN cookie",
+		Name: "syn_cookie",
+	},
+	{
+		Counter:  DroppedByQoS,
+		Category: "Dropped", Caption: "by bandwidth limit",
+		Name: "qos",
 	},
 }
 
@@ -168,6 +218,11 @@ func Read(m maps.Map, ifindex int, hook hook.Hook) ([]uint64, error) {
 		return []uint64{}, fmt.Errorf("failed to read counters map. err=%w", err)
 	}
 
+	return sumPerCPU(values), nil
+}
+
+// sumPerCPU sums up the per-CPU values of a counters map entry.
+func sumPerCPU(values []byte) []uint64 {
 	bpfCounters := make([]uint64, MaxCounterNumber)
 	for i := range bpfCounters {
 		for cpu := 0; cpu < maps.NumPossibleCPUs(); cpu++ {
@@ -176,7 +231,7 @@ func Read(m maps.Map, ifindex int, hook hook.Hook) ([]uint64, error) {
 			bpfCounters[i] += data
 		}
 	}
-	return bpfCounters, nil
+	return bpfCounters
 }
 
 func Flush(m maps.Map, ifindex int, hook hook.Hook) error {
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counters
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+const (
+	labelIface        = "iface"
+	labelHook         = "hook"
+	labelReason       = "reason"
+	labelOrchestrator = "orchestrator"
+	labelWorkload     = "workload"
+	labelEndpoint     = "endpoint"
+
+	defaultCollectionRatelimit = time.Second
+)
+
+var (
+	workloadMetricLabels  = []string{labelIface, labelHook, labelReason, labelOrchestrator, labelWorkload, labelEndpoint}
+	interfaceMetricLabels = []string{labelIface, labelHook, labelReason}
+)
+
+// newDescs returns the descriptions of the dropped, accepted and replied packets metrics.
+func newDescs(labels []string) (dropped, accepted, replied *prometheus.Desc) {
+	dropped = prometheus.NewDesc(
+		"felix_bpf_dropped_packets",
+		"Number of packets dropped by the BPF programs attached to an interface, by reason.",
+		labels, nil,
+	)
+	accepted = prometheus.NewDesc(
+		"felix_bpf_accepted_packets",
+		"Number of packets accepted by the BPF programs attached to an interface, by reason.",
+		labels, nil,
+	)
+	replied = prometheus.NewDesc(
+		"felix_bpf_replied_packets",
+		"Number of packets that the BPF programs attached to an interface answered in place of the host, by reason.",
+		labels, nil,
+	)
+	return
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Collector exports the counters map as Prometheus metrics.  The map is read when the
+// metrics are scraped, at most once per rate limit interval.  The counters are reported per
+// interface, so that the series of an interface ends when the interface goes away rather
+// than a sum going backwards.  With per-workload metrics, the interfaces of workloads are
+// also labelled with the identity of the workload.  Counters that are zero are not exported.
+type Collector struct {
+	m           maps.Map
+	perWorkload bool
+
+	droppedDesc  *prometheus.Desc
+	acceptedDesc *prometheus.Desc
+	repliedDesc  *prometheus.Desc
+
+	lock      sync.Mutex
+	workloads map[string]proto.WorkloadEndpointID
+
+	metrics            []prometheus.Metric
+	lastCollectionTime time.Time
+	rateLimitInterval  time.Duration
+
+	interfaceByIndex func(int) (*net.Interface, error)
+}
+
+func NewCollector(m maps.Map, perWorkload bool) *Collector {
+	labels := interfaceMetricLabels
+	if perWorkload {
+		labels = workloadMetricLabels
+	}
+	c := &Collector{
+		m:                 m,
+		perWorkload:       perWorkload,
+		workloads:         map[string]proto.WorkloadEndpointID{},
+		rateLimitInterval: defaultCollectionRatelimit,
+		interfaceByIndex:  net.InterfaceByIndex,
+	}
+	c.droppedDesc, c.acceptedDesc, c.repliedDesc = newDescs(labels)
+	return c
+}
+
+// SetWorkload records the workload that owns an interface, nil if none.
+func (c *Collector) SetWorkload(iface string, id *proto.WorkloadEndpointID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if id == nil {
+		delete(c.workloads, iface)
+		return
+	}
+	c.workloads[iface] = *id
+}
+
+func (c *Collector) Describe(d chan<- *prometheus.Desc) {
+	d <- c.droppedDesc
+	d <- c.acceptedDesc
+	d <- c.repliedDesc
+}
+
+func (c *Collector) Collect(m chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if time.Since(c.lastCollectionTime) >= c.rateLimitInterval {
+		c.metrics = c.collect()
+		c.lastCollectionTime = time.Now()
+	}
+
+	for _, metric := range c.metrics {
+		m <- metric
+	}
+}
+
+func (c *Collector) collect() []prometheus.Metric {
+	var metrics []prometheus.Metric
+
+	err := c.m.Iter(func(k, v []byte) maps.IteratorAction {
+		var key Key
+		copy(key[:], k)
+
+		iface, err := c.interfaceByIndex(key.IfIndex())
+		if err != nil {
+			// The interface is gone, the entry will be cleaned up.
+			return maps.IterNone
+		}
+
+		wl := c.workloads[iface.Name]
+		values := sumPerCPU(v)
+
+		for _, d := range descriptions {
+			var desc *prometheus.Desc
+			switch d.Category {
+			case "Dropped":
+				desc = c.droppedDesc
+			case "Accepted":
+				desc = c.acceptedDesc
+			case "Replied":
+				desc = c.repliedDesc
+			default:
+				continue
+			}
+			if values[d.Counter] == 0 {
+				// Most reasons never happen on most interfaces, leave them out to
+				// keep the number of series down.
+				continue
+			}
+			if !c.perWorkload {
+				metrics = append(metrics, prometheus.MustNewConstMetric(
+					desc, prometheus.CounterValue, float64(values[d.Counter]),
+					iface.Name, key.Hook().String(), d.Name,
+				))
+				continue
+			}
+			metrics = append(metrics, prometheus.MustNewConstMetric(
+				desc, prometheus.CounterValue, float64(values[d.Counter]),
+				iface.Name, key.Hook().String(), d.Name, wl.OrchestratorId, wl.WorkloadId, wl.EndpointId,
+			))
+		}
+
+		return maps.IterNone
+	})
+	if err != nil {
+		log.WithError(err).Warn("Failed to read BPF counters map.")
+		return nil
+	}
+
+	return metrics
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counters
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/projectcalico/calico/felix/bpf/hook"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+func TestCollector(t *testing.T) {
+	RegisterTestingT(t)
+
+	params := MapParameters
+	params.ValueSize = counterMapValueSize * MaxCounterNumber * maps.NumPossibleCPUs()
+	m := mock.NewMockMap(params)
+
+	// Count the drops on the first CPU and the accepted packets on the last one.
+	setCounters := func(ifindex int, h hook.Hook, counts map[int]uint64) {
+		v := make([]byte, params.ValueSize)
+		for c, n := range counts {
+			cpu := 0
+			if c < DroppedByPolicy {
+				cpu = maps.NumPossibleCPUs() - 1
+			}
+			begin := c*counterMapValueSize + cpu*MaxCounterNumber*counterMapValueSize
+			binary.LittleEndian.PutUint64(v[begin:begin+counterMapValueSize], n)
+		}
+		Expect(m.Update(NewKey(ifindex, h).AsBytes(), v)).To(Succeed())
+	}
+	setCounters(1, hook.Ingress, map[int]uint64{AcceptedByPolicy: 10, DroppedByPolicy: 3})
+	setCounters(2, hook.Egress, map[int]uint64{DroppedUnknownRoute: 5, DroppedByQoS: 8})
+	setCounters(1, hook.XDP, map[int]uint64{DroppedBySYNRateLimit: 4, RepliedWithSYNCookie: 6})
+	// The interface of this entry is gone.
+	setCounters(3, hook.Ingress, map[int]uint64{DroppedByPolicy: 7})
+
+	c := NewCollector(m, true)
+	c.interfaceByIndex = func(idx int) (*net.Interface, error) {
+		switch idx {
+		case 1:
+			return &net.Interface{Index: 1, Name: "eth0"}, nil
+		case 2:
+			return &net.Interface{Index: 2, Name: "cali1234"}, nil
+		}
+		return nil, fmt.Errorf("no such interface")
+	}
+	c.SetWorkload("cali1234", &proto.WorkloadEndpointID{
+		OrchestratorId: "k8s",
+		WorkloadId:     "default/pod1",
+		EndpointId:     "eth0",
+	})
+
+	Expect(testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP felix_bpf_dropped_packets Number of packets dropped by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_dropped_packets counter
+felix_bpf_dropped_packets{endpoint="",hook="ingress",iface="eth0",orchestrator="",reason="policy",workload=""} 3
+felix_bpf_dropped_packets{endpoint="",hook="xdp",iface="eth0",orchestrator="",reason="syn_rate_limit",workload=""} 4
+felix_bpf_dropped_packets{endpoint="eth0",hook="egress",iface="cali1234",orchestrator="k8s",reason="unknown_route",workload="default/pod1"} 5
+felix_bpf_dropped_packets{endpoint="eth0",hook="egress",iface="cali1234",orchestrator="k8s",reason="qos",workload="default/pod1"} 8
+# HELP felix_bpf_accepted_packets Number of packets accepted by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_accepted_packets counter
+felix_bpf_accepted_packets{endpoint="",hook="ingress",iface="eth0",orchestrator="",reason="policy",workload=""} 10
+# HELP felix_bpf_replied_packets Number of packets that the BPF programs attached to an interface answered in place of the host, by reason.
+# TYPE felix_bpf_replied_packets counter
+felix_bpf_replied_packets{endpoint="",hook="xdp",iface="eth0",orchestrator="",reason="syn_cookie",workload=""} 6
+`), "felix_bpf_dropped_packets", "felix_bpf_accepted_packets", "felix_bpf_replied_packets")).To(Succeed())
+
+	// Removing the workload removes its labels, once the rate limit has passed.
+	c.SetWorkload("cali1234", nil)
+	c.rateLimitInterval = 0
+	Expect(testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP felix_bpf_dropped_packets Number of packets dropped by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_dropped_packets counter
+felix_bpf_dropped_packets{endpoint="",hook="ingress",iface="eth0",orchestrator="",reason="policy",workload=""} 3
+felix_bpf_dropped_packets{endpoint="",hook="xdp",iface="eth0",orchestrator="",reason="syn_rate_limit",workload=""} 4
+felix_bpf_dropped_packets{endpoint="",hook="egress",iface="cali1234",orchestrator="",reason="unknown_route",workload=""} 5
+felix_bpf_dropped_packets{endpoint="",hook="egress",iface="cali1234",orchestrator="",reason="qos",workload=""} 8
+`), "felix_bpf_dropped_packets")).To(Succeed())
+
+	// Without per-workload metrics, the counters are reported per interface, without the
+	// workload labels.
+	setCounters(2, hook.Ingress, map[int]uint64{DroppedByPolicy: 2})
+	c = NewCollector(m, false)
+	c.rateLimitInterval = 0
+	c.interfaceByIndex = func(idx int) (*net.Interface, error) {
+		return &net.Interface{Index: idx, Name: fmt.Sprintf("if%d", idx)}, nil
+	}
+	Expect(testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP felix_bpf_dropped_packets Number of packets dropped by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_dropped_packets counter
+felix_bpf_dropped_packets{hook="ingress",iface="if1",reason="policy"} 3
+felix_bpf_dropped_packets{hook="ingress",iface="if2",reason="policy"} 2
+felix_bpf_dropped_packets{hook="ingress",iface="if3",reason="policy"} 7
+felix_bpf_dropped_packets{hook="xdp",iface="if1",reason="syn_rate_limit"} 4
+felix_bpf_dropped_packets{hook="egress",iface="if2",reason="unknown_route"} 5
+felix_bpf_dropped_packets{hook="egress",iface="if2",reason="qos"} 8
+# HELP felix_bpf_accepted_packets Number of packets accepted by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_accepted_packets counter
+felix_bpf_accepted_packets{hook="ingress",iface="if1",reason="policy"} 10
+`), "felix_bpf_dropped_packets", "felix_bpf_accepted_packets")).To(Succeed())
+
+	// When an interface goes away, its series end and the counters of the other interfaces
+	// do not go down.
+	Expect(m.Delete(NewKey(3, hook.Ingress).AsBytes())).To(Succeed())
+	c.interfaceByIndex = func(idx int) (*net.Interface, error) {
+		if idx == 3 {
+			return nil, fmt.Errorf("no such interface")
+		}
+		return &net.Interface{Index: idx, Name: fmt.Sprintf("if%d", idx)}, nil
+	}
+	Expect(testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP felix_bpf_dropped_packets Number of packets dropped by the BPF programs attached to an interface, by reason.
+# TYPE felix_bpf_dropped_packets counter
+felix_bpf_dropped_packets{hook="ingress",iface="if1",reason="policy"} 3
+felix_bpf_dropped_packets{hook="ingress",iface="if2",reason="policy"} 2
+felix_bpf_dropped_packets{hook="xdp",iface="if1",reason="syn_rate_limit"} 4
+felix_bpf_dropped_packets{hook="egress",iface="if2",reason="unknown_route"} 5
+felix_bpf_dropped_packets{hook="egress",iface="if2",reason="qos"} 8
+`), "felix_bpf_dropped_packets")).To(Succeed())
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ddos holds the maps of the DDoS protection that the XDP programs of the host
+// endpoints implement: its configuration, the blocklist of sources and the per source
+// prefix rate limit counters.
+package ddos
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+// The flags should be kept in sync with bpf-gpl/ddos.h
+const (
+	FlagEnabled    = 0x1
+	FlagSYNCookies = 0x2
+)
+
+// struct cali_ddos_cfg {
+//  __u32 flags;
+//  __u32 syn_rate;
+//  __u32 udp_rate;
+//  __u8 prefix_len_v4;
+//  __u8 prefix_len_v6;
+//  __u16 __pad;
+// };
+
+const (
+	configKey       = 0
+	ConfigValueSize = 16
+)
+
+var ConfigMapParameters = maps.MapParameters{
+	Type:       "array",
+	KeySize:    4,
+	ValueSize:  ConfigValueSize,
+	MaxEntries: 1,
+	Name:       "cali_ddos_cfg",
+}
+
+// ConfigMap returns the map that configures the DDoS protection.
+func ConfigMap() maps.Map {
+	return maps.NewPinnedMap(ConfigMapParameters)
+}
+
+// Config is the configuration of the DDoS protection.
+type Config struct {
+	Enabled    bool
+	SYNCookies bool
+	// SYNRate and UDPRate are the number of packets per second allowed from a source
+	// prefix, zero for no limit.
+	SYNRate     uint32
+	UDPRate     uint32
+	PrefixLenV4 uint8
+	PrefixLenV6 uint8
+}
+
+func (c Config) AsBytes() []byte {
+	var v [ConfigValueSize]byte
+
+	var flags uint32
+	if c.Enabled {
+		flags |= FlagEnabled
+	}
+	if c.SYNCookies {
+		flags |= FlagSYNCookies
+	}
+	binary.LittleEndian.PutUint32(v[0:4], flags)
+	binary.LittleEndian.PutUint32(v[4:8], c.SYNRate)
+	binary.LittleEndian.PutUint32(v[8:12], c.UDPRate)
+	v[12] = c.PrefixLenV4
+	v[13] = c.PrefixLenV6
+
+	return v[:]
+}
+
+func ConfigFromBytes(b []byte) Config {
+	flags := binary.LittleEndian.Uint32(b[0:4])
+	return Config{
+		Enabled:     flags&FlagEnabled != 0,
+		SYNCookies:  flags&FlagSYNCookies != 0,
+		SYNRate:     binary.LittleEndian.Uint32(b[4:8]),
+		UDPRate:     binary.LittleEndian.Uint32(b[8:12]),
+		PrefixLenV4: b[12],
+		PrefixLenV6: b[13],
+	}
+}
+
+// SetConfig writes the configuration to the map.
+func SetConfig(m maps.Map, c Config) error {
+	var k [4]byte
+	binary.LittleEndian.PutUint32(k[:], configKey)
+	return m.Update(k[:], c.AsBytes())
+}
+
+// ReadConfig reads the configuration from the map.
+func ReadConfig(m maps.Map) (Config, error) {
+	var k [4]byte
+	binary.LittleEndian.PutUint32(k[:], configKey)
+	v, err := m.Get(k[:])
+	if err != nil {
+		return Config{}, err
+	}
+	return ConfigFromBytes(v), nil
+}
+
+// struct cali_ddos_bl_key {
+//  __u32 prefixlen;
+//  ipv46_addr_t addr;
+// };
+
+const (
+	BlocklistKeySize   = 8
+	BlocklistKeyV6Size = 20
+	BlocklistValueSize = 4
+	BlocklistMaxSize   = 64 * 1024
+)
+
+var BlocklistMapParameters = maps.MapParameters{
+	Type:       "lpm_trie",
+	KeySize:    BlocklistKeySize,
+	ValueSize:  BlocklistValueSize,
+	MaxEntries: BlocklistMaxSize,
+	Name:       "cali_v4_ddos_bl",
+	Flags:      unix.BPF_F_NO_PREALLOC,
+}
+
+var BlocklistMapV6Parameters = maps.MapParameters{
+	Type:       "lpm_trie",
+	KeySize:    BlocklistKeyV6Size,
+	ValueSize:  BlocklistValueSize,
+	MaxEntries: BlocklistMaxSize,
+	Name:       "cali_v6_ddos_bl",
+	Flags:      unix.BPF_F_NO_PREALLOC,
+}
+
+// BlocklistMap returns the map of the IPv4 sources whose packets are dropped.
+func BlocklistMap() maps.Map {
+	return maps.NewPinnedMap(BlocklistMapParameters)
+}
+
+// BlocklistMapV6 returns the map of the IPv6 sources whose packets are dropped.
+func BlocklistMapV6() maps.Map {
+	return maps.NewPinnedMap(BlocklistMapV6Parameters)
+}
+
+type BlocklistKey [BlocklistKeySize]byte
+
+func NewBlocklistKey(cidr ip.CIDR) BlocklistKey {
+	var k BlocklistKey
+
+	binary.LittleEndian.PutUint32(k[:4], uint32(cidr.Prefix()))
+	copy(k[4:8], cidr.Addr().AsNetIP().To4())
+
+	return k
+}
+
+func BlocklistKeyFromBytes(b []byte) BlocklistKey {
+	var k BlocklistKey
+	copy(k[:], b)
+	return k
+}
+
+func (k BlocklistKey) AsBytes() []byte {
+	return k[:]
+}
+
+func (k BlocklistKey) CIDR() ip.CIDR {
+	return ip.CIDRFromAddrAndPrefix(ip.FromNetIP(net.IP(k[4:8])), int(binary.LittleEndian.Uint32(k[:4])))
+}
+
+func (k BlocklistKey) String() string {
+	return k.CIDR().String()
+}
+
+type BlocklistKeyV6 [BlocklistKeyV6Size]byte
+
+func NewBlocklistKeyV6(cidr ip.CIDR) BlocklistKeyV6 {
+	var k BlocklistKeyV6
+
+	binary.LittleEndian.PutUint32(k[:4], uint32(cidr.Prefix()))
+	copy(k[4:20], cidr.Addr().AsNetIP().To16())
+
+	return k
+}
+
+func BlocklistKeyV6FromBytes(b []byte) BlocklistKeyV6 {
+	var k BlocklistKeyV6
+	copy(k[:], b)
+	return k
+}
+
+func (k BlocklistKeyV6) AsBytes() []byte {
+	return k[:]
+}
+
+func (k BlocklistKeyV6) CIDR() ip.CIDR {
+	return ip.CIDRFromAddrAndPrefix(ip.FromNetIP(net.IP(k[4:20])), int(binary.LittleEndian.Uint32(k[:4])))
+}
+
+func (k BlocklistKeyV6) String() string {
+	return k.CIDR().String()
+}
+
+// BlocklistValue is the value of the entries of the blocklist maps, which is unused.
+type BlocklistValue [BlocklistValueSize]byte
+
+func BlocklistValueFromBytes(b []byte) BlocklistValue {
+	var v BlocklistValue
+	copy(v[:], b)
+	return v
+}
+
+func (v BlocklistValue) AsBytes() []byte {
+	return v[:]
+}
+
+// The key of the rate limit maps is the masked source address.
+//
+// struct cali_ddos_rl_val {
+//  __u64 window_start;
+//  __u32 syn;
+//  __u32 udp;
+// };
+
+const (
+	RateLimitValueSize = 16
+	RateLimitMaxSize   = 64 * 1024
+)
+
+var RateLimitMapParameters = maps.MapParameters{
+	Type:         "lru_hash",
+	KeySize:      4,
+	ValueSize:    RateLimitValueSize,
+	MaxEntries:   RateLimitMaxSize,
+	Name:         "cali_v4_ddos_rl",
+	UpdatedByBPF: true,
+}
+
+var RateLimitMapV6Parameters = maps.MapParameters{
+	Type:         "lru_hash",
+	KeySize:      16,
+	ValueSize:    RateLimitValueSize,
+	MaxEntries:   RateLimitMaxSize,
+	Name:         "cali_v6_ddos_rl",
+	UpdatedByBPF: true,
+}
+
+// RateLimitMap returns the map in which the programs count the packets of the IPv4 source
+// prefixes.
+func RateLimitMap() maps.Map {
+	return maps.NewPinnedMap(RateLimitMapParameters)
+}
+
+// RateLimitMapV6 returns the map in which the programs count the packets of the IPv6
+// source prefixes.
+func RateLimitMapV6() maps.Map {
+	return maps.NewPinnedMap(RateLimitMapV6Parameters)
+}
+
+// RateLimitValue holds the packets counted from a source prefix since the start of the
+// current one second window.
+type RateLimitValue [RateLimitValueSize]byte
+
+func RateLimitValueFromBytes(b []byte) RateLimitValue {
+	var v RateLimitValue
+	copy(v[:], b)
+	return v
+}
+
+func (v RateLimitValue) AsBytes() []byte {
+	return v[:]
+}
+
+// WindowStart returns the start of the window in monotonic time, as returned by
+// bpf_ktime_get_ns.
+func (v RateLimitValue) WindowStart() time.Duration {
+	return time.Duration(binary.LittleEndian.Uint64(v[0:8]))
+}
+
+func (v RateLimitValue) SYNs() uint32 {
+	return binary.LittleEndian.Uint32(v[8:12])
+}
+
+func (v RateLimitValue) UDPs() uint32 {
+	return binary.LittleEndian.Uint32(v[12:16])
+}
+
+func (v RateLimitValue) String() string {
+	return fmt.Sprintf("{windowStart: %d, syn: %d, udp: %d}", v.WindowStart(), v.SYNs(), v.UDPs())
+}
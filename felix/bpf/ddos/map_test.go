@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddos
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/ip"
+)
+
+func TestConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	c := Config{
+		Enabled:     true,
+		SYNCookies:  true,
+		SYNRate:     1000,
+		UDPRate:     2000,
+		PrefixLenV4: 24,
+		PrefixLenV6: 64,
+	}
+	b := c.AsBytes()
+	Expect(b).To(HaveLen(ConfigValueSize))
+	Expect(b[0]).To(Equal(byte(FlagEnabled | FlagSYNCookies)))
+	Expect(ConfigFromBytes(b)).To(Equal(c))
+}
+
+func TestBlocklistKey(t *testing.T) {
+	RegisterTestingT(t)
+
+	k := NewBlocklistKey(ip.MustParseCIDROrIP("10.1.2.0/24"))
+	Expect(k.AsBytes()).To(Equal([]byte{24, 0, 0, 0, 10, 1, 2, 0}))
+	Expect(BlocklistKeyFromBytes(k.AsBytes()).String()).To(Equal("10.1.2.0/24"))
+
+	k6 := NewBlocklistKeyV6(ip.MustParseCIDROrIP("dead:beef::1"))
+	Expect(BlocklistKeyV6FromBytes(k6.AsBytes())).To(Equal(k6))
+	Expect(k6.String()).To(Equal("dead:beef::1/128"))
+}
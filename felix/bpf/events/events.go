@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events reads the events that the BPF programs emit about the packets that they
+// drop or cannot forward, and streams them to clients such as calico-bpf.
+package events
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/projectcalico/calico/felix/bpf/hook"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+// Type is the type of an event. The values must be kept in sync with
+// bpf-gpl/events.h
+type Type uint32
+
+const (
+	TypePolicyDeny Type = iota + 1
+	TypeRPFFail
+	TypeNATMiss
+)
+
+var typeNames = map[Type]string{
+	TypePolicyDeny: "policy-deny",
+	TypeRPFFail:    "rpf-fail",
+	TypeNATMiss:    "nat-miss",
+}
+
+func (t Type) String() string {
+	if s, ok := typeNames[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(t))
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *Type) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	typ, err := ParseType(s)
+	if err != nil {
+		return err
+	}
+	*t = typ
+	return nil
+}
+
+// ParseType returns the type of the given name.
+func ParseType(s string) (Type, error) {
+	for t, name := range typeNames {
+		if strings.EqualFold(s, name) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown event type '%s'", s)
+}
+
+// TypeNames returns the names of all the event types.
+func TypeNames() []string {
+	return []string{TypePolicyDeny.String(), TypeRPFFail.String(), TypeNATMiss.String()}
+}
+
+// Event is an event emitted by the BPF programs.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    Type      `json:"type"`
+	IfIndex int       `json:"ifindex"`
+	Iface   string    `json:"iface,omitempty"`
+	Hook    string    `json:"hook"`
+	Proto   uint8     `json:"proto"`
+	Src     net.IP    `json:"src"`
+	Dst     net.IP    `json:"dst"`
+	SrcPort uint16    `json:"srcPort,omitempty"`
+	DstPort uint16    `json:"dstPort,omitempty"`
+	// RuleID is the match ID of the policy rule that denied the packet, if any.
+	RuleID uint64 `json:"ruleID,omitempty"`
+}
+
+func (e *Event) String() string {
+	src, dst := e.Src.String(), e.Dst.String()
+	if e.SrcPort != 0 || e.DstPort != 0 {
+		src = net.JoinHostPort(src, fmt.Sprint(e.SrcPort))
+		dst = net.JoinHostPort(dst, fmt.Sprint(e.DstPort))
+	}
+	iface := e.Iface
+	if iface == "" {
+		iface = fmt.Sprintf("if%d", e.IfIndex)
+	}
+	s := fmt.Sprintf("%s %s %s %s proto %d %s -> %s",
+		e.Time.Format("15:04:05.000000"), e.Type, iface, e.Hook, e.Proto, src, dst)
+	if e.RuleID != 0 {
+		s += fmt.Sprintf(" rule %d", e.RuleID)
+	}
+	return s
+}
+
+// struct calico_event {
+//  __u64 timestamp_ns;
+//  __u32 type;
+//  __u32 ifindex;
+//  __u32 hook;
+//  __u32 __pad;
+//  ipv6_addr_t ip_src;
+//  ipv6_addr_t ip_dst;
+//  __u16 sport;
+//  __u16 dport;
+//  __u8 ip_proto;
+//  __u8 ipv6;
+//  __u8 __pad2[2];
+//  __u64 rule_id;
+// };
+
+const rawEventSize = 72
+
+// Parse decodes an event as emitted by the BPF programs. The timestamp of the event is
+// the time since boot, bootTime converts it to the wall clock.
+func Parse(raw []byte, bootTime time.Time) (*Event, error) {
+	if len(raw) < rawEventSize {
+		return nil, fmt.Errorf("event too short: %d bytes", len(raw))
+	}
+
+	e := &Event{
+		Time:    bootTime.Add(time.Duration(binary.LittleEndian.Uint64(raw[0:8]))),
+		Type:    Type(binary.LittleEndian.Uint32(raw[8:12])),
+		IfIndex: int(binary.LittleEndian.Uint32(raw[12:16])),
+		Hook:    hook.Hook(binary.LittleEndian.Uint32(raw[16:20])).String(),
+		SrcPort: binary.LittleEndian.Uint16(raw[56:58]),
+		DstPort: binary.LittleEndian.Uint16(raw[58:60]),
+		Proto:   raw[60],
+		RuleID:  binary.LittleEndian.Uint64(raw[64:72]),
+	}
+
+	if raw[61] != 0 {
+		e.Src = append(net.IP(nil), raw[24:40]...)
+		e.Dst = append(net.IP(nil), raw[40:56]...)
+	} else {
+		e.Src = net.IPv4(raw[24], raw[25], raw[26], raw[27]).To4()
+		e.Dst = net.IPv4(raw[40], raw[41], raw[42], raw[43]).To4()
+	}
+
+	return e, nil
+}
+
+var MapParameters = maps.MapParameters{
+	Type:       "perf_event_array",
+	KeySize:    4,
+	ValueSize:  4,
+	MaxEntries: 1,
+	Name:       "cali_events",
+}
+
+// Map returns the map that the BPF programs emit the events to. It has an entry per
+// possible CPU.
+func Map() maps.Map {
+	maps.SetSize(MapParameters.VersionedName(), maps.NumPossibleCPUs())
+	return maps.NewPinnedMap(MapParameters)
+}
+
+const configKey = 0
+
+var ConfigMapParameters = maps.MapParameters{
+	Type:       "array",
+	KeySize:    4,
+	ValueSize:  4,
+	MaxEntries: 1,
+	Name:       "cali_ev_cfg",
+}
+
+// ConfigMap returns the map that configures which of the events the BPF programs emit.
+func ConfigMap() maps.Map {
+	return maps.NewPinnedMap(ConfigMapParameters)
+}
+
+// SetSampleRate makes the BPF programs emit one in every rate events, none if rate is
+// zero.
+func SetSampleRate(m maps.Map, rate int) error {
+	var k, v [4]byte
+	binary.LittleEndian.PutUint32(k[:], configKey)
+	binary.LittleEndian.PutUint32(v[:], uint32(rate))
+	return m.Update(k[:], v[:])
+}
+
+// SampleRate returns the rate that the BPF programs emit the events at.
+func SampleRate(m maps.Map) (int, error) {
+	var k [4]byte
+	binary.LittleEndian.PutUint32(k[:], configKey)
+	v, err := m.Get(k[:])
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint32(v)), nil
+}
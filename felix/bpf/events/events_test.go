@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func rawEvent(typ Type, v6 bool, src, dst net.IP, ruleID uint64) []byte {
+	raw := make([]byte, rawEventSize)
+	binary.LittleEndian.PutUint64(raw[0:8], uint64(5*time.Second))
+	binary.LittleEndian.PutUint32(raw[8:12], uint32(typ))
+	binary.LittleEndian.PutUint32(raw[12:16], 7)
+	binary.LittleEndian.PutUint32(raw[16:20], 1)
+	if v6 {
+		copy(raw[24:40], src.To16())
+		copy(raw[40:56], dst.To16())
+		raw[61] = 1
+	} else {
+		copy(raw[24:28], src.To4())
+		copy(raw[40:44], dst.To4())
+	}
+	binary.LittleEndian.PutUint16(raw[56:58], 1234)
+	binary.LittleEndian.PutUint16(raw[58:60], 80)
+	raw[60] = 6
+	binary.LittleEndian.PutUint64(raw[64:72], ruleID)
+	return raw
+}
+
+func TestParse(t *testing.T) {
+	RegisterTestingT(t)
+
+	boot := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	e, err := Parse(rawEvent(TypePolicyDeny, false, net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2"), 0x1234), boot)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(*e).To(Equal(Event{
+		Time:    boot.Add(5 * time.Second),
+		Type:    TypePolicyDeny,
+		IfIndex: 7,
+		Hook:    "egress",
+		Proto:   6,
+		Src:     net.ParseIP("10.0.0.1").To4(),
+		Dst:     net.ParseIP("10.0.0.2").To4(),
+		SrcPort: 1234,
+		DstPort: 80,
+		RuleID:  0x1234,
+	}))
+
+	e, err = Parse(rawEvent(TypeRPFFail, true, net.ParseIP("dead::1"), net.ParseIP("dead::2"), 0), boot)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(e.Type).To(Equal(TypeRPFFail))
+	Expect(e.Src.String()).To(Equal("dead::1"))
+	Expect(e.Dst.String()).To(Equal("dead::2"))
+
+	_, err = Parse(make([]byte, 10), boot)
+	Expect(err).To(HaveOccurred())
+}
+
+func TestEventJSON(t *testing.T) {
+	RegisterTestingT(t)
+
+	e := Event{
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Type:    TypeNATMiss,
+		IfIndex: 3,
+		Iface:   "eth0",
+		Hook:    "ingress",
+		Proto:   17,
+		Src:     net.ParseIP("10.0.0.1").To4(),
+		Dst:     net.ParseIP("10.96.0.10").To4(),
+		SrcPort: 5000,
+		DstPort: 53,
+	}
+
+	b, err := json.Marshal(e)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(string(b)).To(ContainSubstring(`"type":"nat-miss"`))
+
+	var decoded Event
+	Expect(json.Unmarshal(b, &decoded)).To(Succeed())
+	Expect(decoded.Type).To(Equal(TypeNATMiss))
+	Expect(decoded.String()).To(Equal(e.String()))
+	Expect(e.String()).To(Equal("00:00:00.000000 nat-miss eth0 ingress proto 17 10.0.0.1:5000 -> 10.96.0.10:53"))
+}
+
+func TestServerStop(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir, err := os.MkdirTemp("", "bpfevents")
+	Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	// Serve clients without an events map, publishing the events by hand.
+	s := NewServer(nil, filepath.Join(dir, "events.sock"))
+	s.listener, err = net.Listen("unix", s.socketPath)
+	Expect(err).NotTo(HaveOccurred())
+	s.wg.Add(1)
+	go s.acceptLoop(s.listener)
+
+	conn, err := net.Dial("unix", s.socketPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer conn.Close()
+	Eventually(func() int {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return len(s.clients)
+	}).Should(Equal(1))
+
+	s.publish(&Event{Type: TypePolicyDeny})
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	Expect(err).NotTo(HaveOccurred())
+	Expect(line).To(ContainSubstring("policy-deny"))
+
+	// Stop disconnects the client, removes the socket and returns once the
+	// goroutines have exited.
+	s.Stop()
+	_, err = conn.Read(make([]byte, 1))
+	Expect(err).To(HaveOccurred())
+	_, err = os.Stat(s.socketPath)
+	Expect(os.IsNotExist(err)).To(BeTrue())
+	s.Stop()
+}
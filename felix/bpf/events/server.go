@@ -0,0 +1,270 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/perf"
+	"github.com/projectcalico/calico/libcalico-go/lib/logutils"
+)
+
+// SocketPath is the unix socket that Felix streams the events on, one JSON encoded
+// event per line.
+const SocketPath = "/var/run/calico/bpf/events.sock"
+
+const (
+	perCPUBufferSize = 64 * 1024
+	// clientQueueLen is the number of events that we queue for a client before we
+	// start dropping events for it.
+	clientQueueLen = 1000
+)
+
+var (
+	lostEventsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "felix_bpf_events_lost",
+		Help: "Number of BPF events lost because Felix did not read them fast enough.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(lostEventsCounter)
+}
+
+// Server reads the events from the events map and streams them to all the clients
+// connected to its socket. The events are not stored, a client gets the events that
+// happen while it is connected. A client that does not keep up loses events.
+type Server struct {
+	eventsMap  maps.Map
+	socketPath string
+
+	lock    sync.Mutex
+	clients map[chan []byte]net.Conn
+	stopped bool
+
+	reader   *perf.Reader
+	listener net.Listener
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	lostLog *logutils.RateLimitedLogger
+
+	interfaceByIndex func(int) (*net.Interface, error)
+}
+
+func NewServer(eventsMap maps.Map, socketPath string) *Server {
+	return &Server{
+		eventsMap:        eventsMap,
+		socketPath:       socketPath,
+		clients:          map[chan []byte]net.Conn{},
+		lostLog:          logutils.NewRateLimitedLogger(logutils.OptInterval(time.Minute)),
+		interfaceByIndex: net.InterfaceByIndex,
+	}
+}
+
+// Start starts reading the events and accepting clients in the background.
+func (s *Server) Start() error {
+	rd, err := perf.NewReader(s.eventsMap, perCPUBufferSize)
+	if err != nil {
+		return fmt.Errorf("failed to read events map %s: %w", s.eventsMap.Path(), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0700); err != nil {
+		rd.Close()
+		return err
+	}
+	_ = os.Remove(s.socketPath)
+	l, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		rd.Close()
+		return fmt.Errorf("failed to listen on %s: %w", s.socketPath, err)
+	}
+
+	s.reader = rd
+	s.listener = l
+	s.wg.Add(2)
+	go s.readLoop(rd)
+	go s.acceptLoop(l)
+
+	log.WithField("socket", s.socketPath).Info("Streaming BPF events.")
+	return nil
+}
+
+// Stop stops reading the events, disconnects the clients and removes the socket.  It
+// waits for the background goroutines to exit.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		if s.listener == nil {
+			return
+		}
+		_ = s.listener.Close()
+		if s.reader != nil {
+			_ = s.reader.Close()
+		}
+
+		s.lock.Lock()
+		s.stopped = true
+		for _, conn := range s.clients {
+			_ = conn.Close()
+		}
+		s.lock.Unlock()
+
+		s.wg.Wait()
+		_ = os.Remove(s.socketPath)
+		log.Info("Stopped streaming BPF events.")
+	})
+}
+
+func (s *Server) readLoop(rd *perf.Reader) {
+	defer s.wg.Done()
+
+	bootTime := bootTime()
+
+	for {
+		rec, err := rd.Read()
+		if err != nil {
+			if errors.Is(err, perf.ErrClosed) {
+				return
+			}
+			log.WithError(err).Warn("Failed to read BPF event.")
+			continue
+		}
+		if rec.LostSamples > 0 {
+			lostEventsCounter.Add(float64(rec.LostSamples))
+			s.lostLog.WithField("lost", rec.LostSamples).Warn(
+				"Lost BPF events, consider increasing BPFEventsSampleRate.")
+			continue
+		}
+
+		e, err := Parse(rec.RawSample, bootTime)
+		if err != nil {
+			log.WithError(err).Warn("Failed to parse BPF event.")
+			continue
+		}
+
+		if iface, err := s.interfaceByIndex(e.IfIndex); err == nil {
+			e.Iface = iface.Name
+		}
+
+		s.publish(e)
+	}
+}
+
+func (s *Server) publish(e *Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.clients) == 0 {
+		return
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).Warn("Failed to encode BPF event.")
+		return
+	}
+	b = append(b, '\n')
+
+	for c := range s.clients {
+		select {
+		case c <- b:
+		default:
+			// The client is too slow, drop the event.
+		}
+	}
+}
+
+func (s *Server) acceptLoop(l net.Listener) {
+	defer s.wg.Done()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.WithError(err).Error("Failed to accept BPF events client.")
+			time.Sleep(time.Second)
+			continue
+		}
+		s.wg.Add(1)
+		go s.serveClient(conn)
+	}
+}
+
+func (s *Server) serveClient(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	c := make(chan []byte, clientQueueLen)
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return
+	}
+	s.clients[c] = conn
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.clients, c)
+		s.lock.Unlock()
+	}()
+
+	// Clients do not send anything, a read returns when the client goes away.
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		close(done)
+	}()
+
+	log.Debug("BPF events client connected.")
+	for {
+		select {
+		case b := <-c:
+			if _, err := conn.Write(b); err != nil {
+				log.WithError(err).Debug("BPF events client disconnected.")
+				return
+			}
+		case <-done:
+			log.Debug("BPF events client disconnected.")
+			return
+		}
+	}
+}
+
+// bootTime returns the wall clock time of the boot, which is when the monotonic
+// clock, that the BPF programs use for the timestamps, started.
+func bootTime() time.Time {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		log.WithError(err).Warn("Failed to read the monotonic clock.")
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(ts.Nano()))
+}
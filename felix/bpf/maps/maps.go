@@ -55,6 +55,13 @@ func NumPossibleCPUs() int {
 	return cachedNumPossibleCPUs
 }
 
+// SetNumPossibleCPUs overrides the number of possible CPUs used to decode the values
+// of per-CPU maps, for decoding maps captured on another node.
+func SetNumPossibleCPUs(n int) {
+	cachedNumPossibleCPUsOnce.Do(func() {})
+	cachedNumPossibleCPUs = n
+}
+
 type FD uint32
 
 func (f FD) Close() error {
@@ -202,12 +202,26 @@ const (
 	NATFlgExternalLocal = 0x1
 	NATFlgInternalLocal = 0x2
 	NATFlgExclude       = 0x4
+	NATFlgMaglev        = 0x8
+
+	// natFlgAffinityPrefixShift is the position of the affinity prefix length in
+	// the top byte of the flags.
+	natFlgAffinityPrefixShift = 24
+	natFlgMask                = 1<<natFlgAffinityPrefixShift - 1
 )
 
+// NATFlgsAffinityPrefixLen returns the flags that make all the clients from a
+// prefix of the given length share the session affinity.  0 means that each
+// client address has its own affinity.
+func NATFlgsAffinityPrefixLen(prefixLen int) uint32 {
+	return uint32(prefixLen) << natFlgAffinityPrefixShift
+}
+
 var flgTostr = map[int]string{
 	NATFlgExternalLocal: "external-local",
 	NATFlgInternalLocal: "internal-local",
 	NATFlgExclude:       "nat-exclude",
+	NATFlgMaglev:        "maglev",
 }
 
 type FrontendValue [frontendValueSize]byte
@@ -248,8 +262,14 @@ func (v FrontendValue) Flags() uint32 {
 	return binary.LittleEndian.Uint32(v[16:20])
 }
 
+// AffinityPrefixLen returns the length of the client prefix that shares the session
+// affinity, 0 if each client address has its own affinity.
+func (v FrontendValue) AffinityPrefixLen() int {
+	return int(v.Flags() >> natFlgAffinityPrefixShift)
+}
+
 func (v FrontendValue) FlagsAsString() string {
-	flgs := v.Flags()
+	flgs := v.Flags() & natFlgMask
 	fstr := ""
 
 	for i := 0; i < 32; i++ {
@@ -272,6 +292,10 @@ func (v FrontendValue) FlagsAsString() string {
 }
 
 func (v FrontendValue) String() string {
+	if l := v.AffinityPrefixLen(); l != 0 {
+		return fmt.Sprintf("NATValue{ID:%d,Count:%d,LocalCount:%d,AffinityTimeout:%d,AffinityPrefixLen:%d,Flags:{%s}}",
+			v.ID(), v.Count(), v.LocalCount(), v.AffinityTimeout(), l, v.FlagsAsString())
+	}
 	return fmt.Sprintf("NATValue{ID:%d,Count:%d,LocalCount:%d,AffinityTimeout:%d,Flags:{%s}}",
 		v.ID(), v.Count(), v.LocalCount(), v.AffinityTimeout(), v.FlagsAsString())
 }
@@ -295,6 +319,20 @@ func NewNATBackendKey(id, ordinal uint32) BackendKey {
 	return v
 }
 
+// Services with NATFlgMaglev have, in addition to their backends, a Maglev lookup
+// table of MaglevLUTSize backends stored in the backend map at ordinals starting at
+// MaglevOrdinalBase.  MaglevLUTSize must be a prime and must match the BPF programs.
+const (
+	MaglevLUTSize     = 1009
+	MaglevOrdinalBase = 0x80000000
+)
+
+// NewNATBackendKeyMaglev returns the key of the given slot of a service's Maglev
+// lookup table.
+func NewNATBackendKeyMaglev(id, slot uint32) BackendKey {
+	return NewNATBackendKey(id, MaglevOrdinalBase+slot)
+}
+
 func (v BackendKey) ID() uint32 {
 	return binary.LittleEndian.Uint32(v[:4])
 }
@@ -505,7 +543,7 @@ func BackendMapMemIter(m BackendMapMem) func(k, v []byte) {
 // struct calico_nat_v4_affinity_key {
 //    struct calico_nat_v4 nat_key;
 // 	  uint32_t client_ip;
-// 	  uint32_t padding;
+// 	  uint32_t prefix_len;
 // };
 
 const affinityKeySize = frontendAffKeySize + 8
@@ -516,6 +554,7 @@ type AffinityKey [affinityKeySize]byte
 
 type AffinityKeyInterface interface {
 	ClientIP() net.IP
+	PrefixLen() int
 	FrontendAffinityKey() FrontEndAffinityKeyInterface
 	String() string
 	AsBytes() []byte
@@ -569,6 +608,21 @@ func (k AffinityKey) ClientIP() net.IP {
 	return k[frontendAffKeySize : frontendAffKeySize+4]
 }
 
+// PrefixLen returns the length of the client prefix that the key is for, 0 if it
+// is for a single client.
+func (k AffinityKey) PrefixLen() int {
+	return int(binary.LittleEndian.Uint32(k[frontendAffKeySize+4 : frontendAffKeySize+8]))
+}
+
+// WithPrefixLen returns a copy of the key for all the clients in the prefix of the
+// given length of its ClientIP.
+func (k AffinityKey) WithPrefixLen(prefixLen int) AffinityKey {
+	mask := net.CIDRMask(prefixLen, 32)
+	copy(k[frontendAffKeySize:frontendAffKeySize+4], k.ClientIP().Mask(mask))
+	binary.LittleEndian.PutUint32(k[frontendAffKeySize+4:frontendAffKeySize+8], uint32(prefixLen))
+	return k
+}
+
 // FrontendKey returns the FrontendKey part of the key
 func (k AffinityKey) FrontendAffinityKey() FrontEndAffinityKeyInterface {
 	var f FrontEndAffinityKey
@@ -578,6 +632,9 @@ func (k AffinityKey) FrontendAffinityKey() FrontEndAffinityKeyInterface {
 }
 
 func (k AffinityKey) String() string {
+	if l := k.PrefixLen(); l != 0 {
+		return fmt.Sprintf("AffinityKey{ClientIP:%v/%d %s}", k.ClientIP(), l, k.FrontendAffinityKey())
+	}
 	return fmt.Sprintf("AffinityKey{ClientIP:%v %s}", k.ClientIP(), k.FrontendAffinityKey())
 }
 
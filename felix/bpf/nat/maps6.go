@@ -404,7 +404,22 @@ func NewAffinityKeyV6(clientIP net.IP, fEndKey FrontendKeyV6) AffinityKeyV6 {
 
 // ClientIP returns the ClientIP part of the key
 func (k AffinityKeyV6) ClientIP() net.IP {
-	return k[frontendAffKeyV6Size : frontendAffKeyV6Size+4]
+	return k[frontendAffKeyV6Size : frontendAffKeyV6Size+16]
+}
+
+// PrefixLen returns the length of the client prefix that the key is for, 0 if it
+// is for a single client.
+func (k AffinityKeyV6) PrefixLen() int {
+	return int(binary.LittleEndian.Uint32(k[frontendAffKeyV6Size+16 : frontendAffKeyV6Size+20]))
+}
+
+// WithPrefixLen returns a copy of the key for all the clients in the prefix of the
+// given length of its ClientIP.
+func (k AffinityKeyV6) WithPrefixLen(prefixLen int) AffinityKeyV6 {
+	mask := net.CIDRMask(prefixLen, 128)
+	copy(k[frontendAffKeyV6Size:frontendAffKeyV6Size+16], k.ClientIP().Mask(mask))
+	binary.LittleEndian.PutUint32(k[frontendAffKeyV6Size+16:frontendAffKeyV6Size+20], uint32(prefixLen))
+	return k
 }
 
 // FrontendKeyV6 returns the FrontendKeyV6 part of the key
@@ -416,6 +431,9 @@ func (k AffinityKeyV6) FrontendAffinityKey() FrontEndAffinityKeyInterface {
 }
 
 func (k AffinityKeyV6) String() string {
+	if l := k.PrefixLen(); l != 0 {
+		return fmt.Sprintf("AffinityKeyV6{ClientIP:%v/%d %s}", k.ClientIP(), l, k.FrontendAffinityKey())
+	}
 	return fmt.Sprintf("AffinityKeyV6{ClientIP:%v %s}", k.ClientIP(), k.FrontendAffinityKey())
 }
 
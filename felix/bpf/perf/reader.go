@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package perf reads the records that the BPF programs emit to a perf event array
+// with bpf_perf_event_output.
+package perf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+// ErrClosed is returned by Read once the Reader is closed.
+var ErrClosed = errors.New("perf reader closed")
+
+const (
+	recordHeaderSize = 8
+
+	// Record types from include/uapi/linux/perf_event.h
+	recordLost   = 2
+	recordSample = 9
+)
+
+// Record is a sample emitted by a BPF program or a count of the samples that the
+// kernel dropped because the buffer of the CPU was full.
+type Record struct {
+	CPU         int
+	RawSample   []byte
+	LostSamples uint64
+}
+
+// Reader reads the records from a perf event array. It opens a perf buffer for every
+// online CPU and stores it in the array for the BPF programs to write to.
+type Reader struct {
+	array maps.Map
+
+	lock    sync.Mutex
+	rings   map[int]*ring
+	pending []*ring
+	epollFD int
+	wakeFD  int
+	events  []unix.EpollEvent
+	closed  bool
+
+	closeOnce sync.Once
+}
+
+// NewReader creates a Reader for the given perf event array, the array must be open.
+// perCPUBufferSize is rounded up to a power of two number of pages.
+func NewReader(array maps.Map, perCPUBufferSize int) (*Reader, error) {
+	epollFD, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create epoll: %w", err)
+	}
+	wakeFD, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		_ = unix.Close(epollFD)
+		return nil, fmt.Errorf("failed to create eventfd: %w", err)
+	}
+
+	r := &Reader{
+		array:   array,
+		rings:   map[int]*ring{},
+		epollFD: epollFD,
+		wakeFD:  wakeFD,
+	}
+
+	if err := r.watch(wakeFD); err != nil {
+		r.cleanUp()
+		return nil, err
+	}
+
+	pages := 1
+	for pages*os.Getpagesize() < perCPUBufferSize {
+		pages *= 2
+	}
+
+	for cpu := 0; cpu < maps.NumPossibleCPUs(); cpu++ {
+		rg, err := openRing(cpu, pages)
+		if errors.Is(err, unix.ENODEV) {
+			// The CPU is offline.
+			log.WithField("cpu", cpu).Debug("Skipping offline CPU.")
+			continue
+		}
+		if err != nil {
+			r.cleanUp()
+			return nil, err
+		}
+		r.rings[rg.fd] = rg
+
+		if err := r.watch(rg.fd); err != nil {
+			r.cleanUp()
+			return nil, err
+		}
+		if err := array.Update(cpuKey(cpu), cpuKey(rg.fd)); err != nil {
+			r.cleanUp()
+			return nil, fmt.Errorf("failed to store the perf buffer of CPU %d: %w", cpu, err)
+		}
+	}
+
+	if len(r.rings) == 0 {
+		r.cleanUp()
+		return nil, errors.New("no online CPUs")
+	}
+
+	r.events = make([]unix.EpollEvent, len(r.rings)+1)
+	return r, nil
+}
+
+func (r *Reader) watch(fd int) error {
+	ev := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(fd)}
+	if err := unix.EpollCtl(r.epollFD, unix.EPOLL_CTL_ADD, fd, &ev); err != nil {
+		return fmt.Errorf("failed to add fd %d to epoll: %w", fd, err)
+	}
+	return nil
+}
+
+// Read blocks until there is a record to return or the Reader is closed, when it
+// returns ErrClosed. It must not be called concurrently.
+func (r *Reader) Read() (Record, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for {
+		if r.closed {
+			return Record{}, ErrClosed
+		}
+
+		for len(r.pending) > 0 {
+			if rec, ok := r.pending[0].next(); ok {
+				return rec, nil
+			}
+			r.pending = r.pending[1:]
+		}
+
+		n, err := unix.EpollWait(r.epollFD, r.events, -1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to wait for perf records: %w", err)
+		}
+		for _, ev := range r.events[:n] {
+			if int(ev.Fd) == r.wakeFD {
+				r.closed = true
+				continue
+			}
+			if rg := r.rings[int(ev.Fd)]; rg != nil {
+				r.pending = append(r.pending, rg)
+			}
+		}
+	}
+}
+
+// Close wakes up a blocked Read, removes the perf buffers from the array and frees
+// them.
+func (r *Reader) Close() error {
+	r.closeOnce.Do(func() {
+		var one [8]byte
+		binary.LittleEndian.PutUint64(one[:], 1)
+		_, _ = unix.Write(r.wakeFD, one[:])
+
+		// Wait for Read to return.
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		r.closed = true
+		r.cleanUp()
+	})
+	return nil
+}
+
+func (r *Reader) cleanUp() {
+	for _, rg := range r.rings {
+		if err := r.array.Delete(cpuKey(rg.cpu)); err != nil && !errors.Is(err, unix.ENOENT) {
+			log.WithError(err).WithField("cpu", rg.cpu).Warn("Failed to remove perf buffer.")
+		}
+		rg.close()
+	}
+	r.rings = nil
+	r.pending = nil
+	_ = unix.Close(r.wakeFD)
+	_ = unix.Close(r.epollFD)
+}
+
+func cpuKey(v int) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(v))
+	return b[:]
+}
+
+// ring is the perf buffer of a single CPU. The first page holds the metadata, the
+// kernel appends the records to the data pages behind it and advances data_head, we
+// advance data_tail as we consume them.
+type ring struct {
+	cpu  int
+	fd   int
+	mem  []byte
+	meta *unix.PerfEventMmapPage
+	data []byte
+}
+
+func openRing(cpu, pages int) (*ring, error) {
+	attr := unix.PerfEventAttr{
+		Type:        unix.PERF_TYPE_SOFTWARE,
+		Config:      unix.PERF_COUNT_SW_BPF_OUTPUT,
+		Sample_type: unix.PERF_SAMPLE_RAW,
+		// Wake us up for every sample.
+		Wakeup: 1,
+	}
+	attr.Size = uint32(unsafe.Sizeof(attr))
+
+	fd, err := unix.PerfEventOpen(&attr, -1, cpu, -1, unix.PERF_FLAG_FD_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open perf event for CPU %d: %w", cpu, err)
+	}
+
+	size := os.Getpagesize() * (pages + 1)
+	mem, err := unix.Mmap(fd, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to map perf buffer for CPU %d: %w", cpu, err)
+	}
+
+	if err := unix.IoctlSetInt(fd, unix.PERF_EVENT_IOC_ENABLE, 0); err != nil {
+		_ = unix.Munmap(mem)
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to enable perf event for CPU %d: %w", cpu, err)
+	}
+
+	return newRing(cpu, fd, mem), nil
+}
+
+func newRing(cpu, fd int, mem []byte) *ring {
+	return &ring{
+		cpu:  cpu,
+		fd:   fd,
+		mem:  mem,
+		meta: (*unix.PerfEventMmapPage)(unsafe.Pointer(&mem[0])),
+		data: mem[os.Getpagesize():],
+	}
+}
+
+// next returns the next sample or lost record from the ring, false if there is none.
+func (rg *ring) next() (Record, bool) {
+	for {
+		head := atomic.LoadUint64(&rg.meta.Data_head)
+		tail := rg.meta.Data_tail
+		if tail == head {
+			return Record{}, false
+		}
+
+		hdr := rg.read(tail, recordHeaderSize)
+		typ := binary.LittleEndian.Uint32(hdr[0:4])
+		size := uint64(binary.LittleEndian.Uint16(hdr[6:8]))
+
+		rec := Record{CPU: rg.cpu}
+		ok := true
+		switch typ {
+		case recordSample:
+			n := binary.LittleEndian.Uint32(rg.read(tail+recordHeaderSize, 4))
+			rec.RawSample = rg.read(tail+recordHeaderSize+4, int(n))
+		case recordLost:
+			// struct { u64 id; u64 lost; }
+			rec.LostSamples = binary.LittleEndian.Uint64(rg.read(tail+recordHeaderSize+8, 8))
+		default:
+			ok = false
+		}
+
+		// Release the space to the kernel once we have copied the record.
+		atomic.StoreUint64(&rg.meta.Data_tail, tail+size)
+		if ok {
+			return rec, true
+		}
+	}
+}
+
+// read copies n bytes at offset off of the data pages, handling the wrap around.
+func (rg *ring) read(off uint64, n int) []byte {
+	b := make([]byte, n)
+	start := int(off % uint64(len(rg.data)))
+	copied := copy(b, rg.data[start:])
+	copy(b[copied:], rg.data)
+	return b
+}
+
+func (rg *ring) close() {
+	_ = unix.Munmap(rg.mem)
+	_ = unix.Close(rg.fd)
+}
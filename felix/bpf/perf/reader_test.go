@@ -0,0 +1,114 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeRing is a ring backed by plain memory that we write the records to the same
+// way the kernel does.
+func fakeRing(dataSize int) *ring {
+	return newRing(3, -1, make([]byte, os.Getpagesize()+dataSize))
+}
+
+func (rg *ring) write(b []byte) {
+	head := rg.meta.Data_head
+	for i := range b {
+		rg.data[(head+uint64(i))%uint64(len(rg.data))] = b[i]
+	}
+	rg.meta.Data_head = head + uint64(len(b))
+}
+
+func sampleRecord(raw []byte) []byte {
+	// The kernel pads the records to 8 bytes.
+	size := (recordHeaderSize + 4 + len(raw) + 7) &^ 7
+	b := make([]byte, size)
+	binary.LittleEndian.PutUint32(b[0:4], recordSample)
+	binary.LittleEndian.PutUint16(b[6:8], uint16(size))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(len(raw)))
+	copy(b[12:], raw)
+	return b
+}
+
+func lostRecord(lost uint64) []byte {
+	b := make([]byte, recordHeaderSize+16)
+	binary.LittleEndian.PutUint32(b[0:4], recordLost)
+	binary.LittleEndian.PutUint16(b[6:8], uint16(len(b)))
+	binary.LittleEndian.PutUint64(b[16:24], lost)
+	return b
+}
+
+func TestRingNext(t *testing.T) {
+	RegisterTestingT(t)
+
+	rg := fakeRing(64)
+
+	_, ok := rg.next()
+	Expect(ok).To(BeFalse())
+
+	rg.write(sampleRecord([]byte("hello")))
+	rg.write(lostRecord(7))
+
+	rec, ok := rg.next()
+	Expect(ok).To(BeTrue())
+	Expect(rec).To(Equal(Record{CPU: 3, RawSample: []byte("hello")}))
+
+	rec, ok = rg.next()
+	Expect(ok).To(BeTrue())
+	Expect(rec).To(Equal(Record{CPU: 3, LostSamples: 7}))
+
+	_, ok = rg.next()
+	Expect(ok).To(BeFalse())
+	Expect(rg.meta.Data_tail).To(Equal(rg.meta.Data_head))
+}
+
+func TestRingNextWrapsAround(t *testing.T) {
+	RegisterTestingT(t)
+
+	rg := fakeRing(64)
+
+	// Move the start of the ring close to its end so that the next sample wraps.
+	rg.meta.Data_head = 56
+	rg.meta.Data_tail = 56
+
+	raw := []byte("0123456789abcdefghij")
+	rg.write(sampleRecord(raw))
+
+	rec, ok := rg.next()
+	Expect(ok).To(BeTrue())
+	Expect(rec.RawSample).To(Equal(raw))
+	Expect(rg.meta.Data_tail).To(Equal(uint64(56 + 32)))
+}
+
+func TestRingNextSkipsOtherRecords(t *testing.T) {
+	RegisterTestingT(t)
+
+	rg := fakeRing(64)
+
+	other := make([]byte, recordHeaderSize)
+	binary.LittleEndian.PutUint32(other[0:4], 1)
+	binary.LittleEndian.PutUint16(other[6:8], recordHeaderSize)
+	rg.write(other)
+	rg.write(sampleRecord([]byte("x")))
+
+	rec, ok := rg.next()
+	Expect(ok).To(BeTrue())
+	Expect(rec.RawSample).To(Equal([]byte("x")))
+}
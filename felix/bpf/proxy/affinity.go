@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AffinityPrefixLengths are the lengths of the client prefixes that share the
+// ClientIP session affinity of a service, so that all clients behind, for
+// instance, a carrier-grade NAT reach the same backend.  0 means that each client
+// address has its own affinity.
+type AffinityPrefixLengths struct {
+	V4 int
+	V6 int
+}
+
+func (p AffinityPrefixLengths) String() string {
+	return fmt.Sprintf("ipv4=%d,ipv6=%d", p.V4, p.V6)
+}
+
+// For returns the prefix length for the IP family.
+func (p AffinityPrefixLengths) For(ipFamily int) int {
+	if ipFamily == 6 {
+		return p.V6
+	}
+	return p.V4
+}
+
+// ParseAffinityPrefixLengths parses the value of the AffinityPrefixLengthAnnotation,
+// a comma separated list of ipv4=L and ipv6=L.  The families that are not listed
+// keep an affinity per client address.
+func ParseAffinityPrefixLengths(v string) (*AffinityPrefixLengths, error) {
+	var p AffinityPrefixLengths
+	for _, part := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("affinity prefix length %q is not in the form family=length", part)
+		}
+
+		maxLen := 32
+		length := &p.V4
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "ipv4":
+		case "ipv6":
+			maxLen = 128
+			length = &p.V6
+		default:
+			return nil, fmt.Errorf("unknown IP family %q, expected ipv4 or ipv6", name)
+		}
+
+		l, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || l < 1 || l > maxLen {
+			return nil, fmt.Errorf("affinity prefix length %q is not a number between 1 and %d", part, maxLen)
+		}
+		// The full address length is the same as the default affinity per client.
+		if l == maxLen {
+			l = 0
+		}
+		*length = l
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+)
+
+func TestParseAffinityPrefixLengths(t *testing.T) {
+	RegisterTestingT(t)
+
+	for v, expected := range map[string]proxy.AffinityPrefixLengths{
+		"ipv4=24":             {V4: 24},
+		"ipv6=64":             {V6: 64},
+		" IPv4 = 16, ipv6=48": {V4: 16, V6: 48},
+		"ipv4=32,ipv6=128":    {},
+	} {
+		p, err := proxy.ParseAffinityPrefixLengths(v)
+		Expect(err).NotTo(HaveOccurred(), v)
+		Expect(*p).To(Equal(expected), v)
+	}
+
+	for _, v := range []string{"", "24", "ipv4=0", "ipv4=33", "ipv6=129", "ipv5=24", "ipv4=x"} {
+		_, err := proxy.ParseAffinityPrefixLengths(v)
+		Expect(err).To(HaveOccurred(), v)
+	}
+
+	p := proxy.AffinityPrefixLengths{V4: 24, V6: 64}
+	Expect(p.For(4)).To(Equal(24))
+	Expect(p.For(6)).To(Equal(64))
+}
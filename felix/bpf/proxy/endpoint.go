@@ -53,6 +53,9 @@ type endpointInfo struct {
 	// zoneHints represent the zone hints for the endpoint. This is based on
 	// endpoint.hints.forZones[*].name in the EndpointSlice API.
 	zoneHints sets.Set[string]
+
+	// zone is the zone of the endpoint, endpoint.zone in the EndpointSlice API.
+	zone string
 }
 
 var _ k8sp.Endpoint = &endpointInfo{}
@@ -94,6 +97,13 @@ func EndpointInfoOptZoneHints(b sets.Set[string]) EndpoiontInfoOpt {
 	}
 }
 
+// EndpointInfoOptZone applies the given zone to the endpoint's zone field.
+func EndpointInfoOptZone(zone string) EndpoiontInfoOpt {
+	return func(ep *endpointInfo) {
+		ep.zone = zone
+	}
+}
+
 // NewEndpointInfo creates a new endpointInfo, returning it as a k8s proxy Endpoint.
 func NewEndpointInfo(ip string, port int, opts ...EndpoiontInfoOpt) k8sp.Endpoint {
 	ep := &endpointInfo{
@@ -150,3 +160,8 @@ func (info *endpointInfo) IsTerminating() bool {
 func (info *endpointInfo) ZoneHints() sets.Set[string] {
 	return info.zoneHints
 }
+
+// Zone returns the zone of the endpoint, empty if it is not known.
+func (info *endpointInfo) Zone() string {
+	return info.zone
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// MaglevLUT computes a Maglev lookup table (Eisenbud et al., NSDI '16) of the given
+// size, which must be a prime, for the given backends.  Each entry of the table is an
+// index into backends.
+//
+// The table depends only on the set of backends and not on their order, so all nodes
+// compute the same table.  Adding or removing a backend changes only a small fraction
+// of the entries, besides those of the removed backend.
+func MaglevLUT(backends []string, size int) []int {
+	n := len(backends)
+	if n == 0 || size <= 1 {
+		return nil
+	}
+
+	// Fill the table in the order of the backends' names so that the result does not
+	// depend on the order in which we got them.
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return backends[order[a]] < backends[order[b]]
+	})
+
+	// Each backend prefers the table entries in the order of its own permutation,
+	// which is given by an offset and a skip derived from its name.
+	offset := make([]uint64, n)
+	skip := make([]uint64, n)
+	for i, b := range backends {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(b))
+		sum := h.Sum64()
+		offset[i] = (sum >> 32) % uint64(size)
+		skip[i] = (sum&0xffffffff)%uint64(size-1) + 1
+	}
+
+	lut := make([]int, size)
+	for i := range lut {
+		lut[i] = -1
+	}
+	next := make([]uint64, n)
+
+	for filled := 0; ; {
+		for _, i := range order {
+			c := (offset[i] + next[i]*skip[i]) % uint64(size)
+			for lut[c] >= 0 {
+				next[i]++
+				c = (offset[i] + next[i]*skip[i]) % uint64(size)
+			}
+			lut[c] = i
+			next[i]++
+			filled++
+			if filled == size {
+				return lut
+			}
+		}
+	}
+}
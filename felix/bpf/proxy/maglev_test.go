@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/proxy"
+)
+
+var _ = Describe("Maglev lookup table", func() {
+	backends := func(n int) []string {
+		var bs []string
+		for i := 0; i < n; i++ {
+			bs = append(bs, fmt.Sprintf("10.1.%d.%d:8080", i/256, i%256))
+		}
+		return bs
+	}
+
+	// resolve maps a lookup table to the names of the backends.
+	resolve := func(bs []string, lut []int) []string {
+		names := make([]string, len(lut))
+		for i, idx := range lut {
+			names[i] = bs[idx]
+		}
+		return names
+	}
+
+	It("should be empty without backends", func() {
+		Expect(proxy.MaglevLUT(nil, nat.MaglevLUTSize)).To(BeEmpty())
+	})
+
+	It("should fill every entry and spread the backends evenly", func() {
+		bs := backends(10)
+		lut := proxy.MaglevLUT(bs, nat.MaglevLUTSize)
+		Expect(lut).To(HaveLen(nat.MaglevLUTSize))
+
+		counts := make([]int, len(bs))
+		for _, idx := range lut {
+			Expect(idx).To(BeNumerically(">=", 0))
+			Expect(idx).To(BeNumerically("<", len(bs)))
+			counts[idx]++
+		}
+		// Maglev guarantees that the counts differ by at most one.
+		for _, c := range counts {
+			Expect(c).To(BeNumerically("~", nat.MaglevLUTSize/len(bs), 1))
+		}
+	})
+
+	It("should not depend on the order of the backends", func() {
+		bs := backends(7)
+		reversed := make([]string, len(bs))
+		for i, b := range bs {
+			reversed[len(bs)-1-i] = b
+		}
+
+		Expect(resolve(reversed, proxy.MaglevLUT(reversed, nat.MaglevLUTSize))).
+			To(Equal(resolve(bs, proxy.MaglevLUT(bs, nat.MaglevLUTSize))))
+	})
+
+	It("should remap only a small fraction of the entries when a backend is removed", func() {
+		bs := backends(10)
+		before := resolve(bs, proxy.MaglevLUT(bs, nat.MaglevLUTSize))
+
+		removed := bs[3]
+		bs = append(bs[:3:3], bs[4:]...)
+		after := resolve(bs, proxy.MaglevLUT(bs, nat.MaglevLUTSize))
+
+		moved := 0
+		for i := range before {
+			if before[i] != removed && before[i] != after[i] {
+				moved++
+			}
+		}
+		// The entries of the removed backend must move, only a few of the others may.
+		Expect(moved).To(BeNumerically("<", nat.MaglevLUTSize/10))
+	})
+})
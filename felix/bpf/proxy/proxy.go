@@ -86,6 +86,7 @@ type proxy struct {
 
 	epsChanges *k8sp.EndpointsChangeTracker
 	svcChanges *k8sp.ServiceChangeTracker
+	epsZones   *endpointZones
 
 	svcMap k8sp.ServicePortMap
 	epsMap k8sp.EndpointsMap
@@ -159,8 +160,9 @@ func New(k8s kubernetes.Interface, dp DPSyncer, hostname string, opts ...Option)
 	p.healthzServer = healthcheck.NewProxierHealthServer("0.0.0.0:10256", p.minDPSyncPeriod)
 	p.svcHealthServer = healthcheck.NewServiceHealthServer(p.hostname, p.recorder, util.NewNodePortAddresses(ipVersion, []string{"0.0.0.0/0"}, nil), p.healthzServer)
 
+	p.epsZones = newEndpointZones()
 	p.epsChanges = k8sp.NewEndpointsChangeTracker(p.hostname,
-		nil, // change if you want to provide more ctx
+		p.makeEndpointInfo,
 		ipVersion,
 		p.recorder,
 		nil,
@@ -307,6 +309,7 @@ func (p *proxy) OnEndpointSliceAdd(eps *discovery.EndpointSlice) {
 	if p.IPFamily() != eps.AddressType {
 		return
 	}
+	p.epsZones.update(eps, false)
 	if p.epsChanges.EndpointSliceUpdate(eps, false) && p.isInitialized() {
 		p.syncDP()
 	}
@@ -316,6 +319,7 @@ func (p *proxy) OnEndpointSliceUpdate(_, eps *discovery.EndpointSlice) {
 	if p.IPFamily() != eps.AddressType {
 		return
 	}
+	p.epsZones.update(eps, false)
 	if p.epsChanges.EndpointSliceUpdate(eps, false) && p.isInitialized() {
 		p.syncDP()
 	}
@@ -325,6 +329,7 @@ func (p *proxy) OnEndpointSliceDelete(eps *discovery.EndpointSlice) {
 	if p.IPFamily() != eps.AddressType {
 		return
 	}
+	p.epsZones.update(eps, true)
 	if p.epsChanges.EndpointSliceUpdate(eps, true) && p.isInitialized() {
 		p.syncDP()
 	}
@@ -385,16 +390,41 @@ const (
 	ReapTerminatingUDPImmediatelly = "TerminatingImmediately"
 
 	ExcludeServiceAnnotation = "projectcalico.org/natExcludeService"
+
+	// LoadBalancingAnnotation selects how backends are picked for new flows. By
+	// default a random backend is picked. With LoadBalancingMaglev a backend is
+	// picked by consistent hashing so that backend churn remaps only a fraction of
+	// flows and all nodes pick the same backend for a flow. Each such service uses
+	// nat.MaglevLUTSize extra entries in the NAT backend map.
+	LoadBalancingAnnotation = "projectcalico.org/natLoadBalancing"
+	LoadBalancingMaglev     = "Maglev"
+
+	// LocalityAnnotation weights the backends of a service by their locality
+	// relative to the node, see ParseLocalityWeights.  It replaces the filtering by
+	// topology aware hints.  It does not apply to services with Maglev load
+	// balancing.  The weights are scaled to at most MaxLocalitySlots backend
+	// entries per service.
+	LocalityAnnotation = "projectcalico.org/natLocality"
+
+	// AffinityPrefixLengthAnnotation makes all the clients from a prefix share the
+	// ClientIP session affinity of a service, see ParseAffinityPrefixLengths.
+	AffinityPrefixLengthAnnotation = "projectcalico.org/natAffinityPrefixLength"
 )
 
 type ServiceAnnotations interface {
 	ReapTerminatingUDP() bool
 	ExcludeService() bool
+	Maglev() bool
+	Locality() *LocalityWeights
+	AffinityPrefixLengths() *AffinityPrefixLengths
 }
 
 type servicePortAnnotations struct {
 	reapTerminatingUDP bool
 	excludeService     bool
+	maglev             bool
+	locality           *LocalityWeights
+	affinityPrefixLens *AffinityPrefixLengths
 }
 
 func (s *servicePortAnnotations) ReapTerminatingUDP() bool {
@@ -405,11 +435,36 @@ func (s *servicePortAnnotations) ExcludeService() bool {
 	return s.excludeService
 }
 
+func (s *servicePortAnnotations) Maglev() bool {
+	return s.maglev
+}
+
+func (s *servicePortAnnotations) Locality() *LocalityWeights {
+	return s.locality
+}
+
+func (s *servicePortAnnotations) AffinityPrefixLengths() *AffinityPrefixLengths {
+	return s.affinityPrefixLens
+}
+
 type servicePort struct {
 	k8sp.ServicePort
 	servicePortAnnotations
 }
 
+// makeEndpointInfo makes the endpoints that the EndpointsChangeTracker keeps, adding
+// the zone of the endpoint that it does not keep.
+func (p *proxy) makeEndpointInfo(base *k8sp.BaseEndpointInfo, svcPortName *k8sp.ServicePortName) k8sp.Endpoint {
+	return NewEndpointInfo(base.IP(), base.Port(),
+		EndpointInfoOptIsLocal(base.IsLocal()),
+		EndpointInfoOptIsReady(base.IsReady()),
+		EndpointInfoOptIsServing(base.IsServing()),
+		EndpointInfoOptIsTerminating(base.IsTerminating()),
+		EndpointInfoOptZoneHints(base.ZoneHints()),
+		EndpointInfoOptZone(p.epsZones.zone(svcPortName.NamespacedName, base.IP())),
+	)
+}
+
 func makeServiceInfo(_ *v1.ServicePort, s *v1.Service, baseSvc *k8sp.BaseServicePortInfo) k8sp.ServicePort {
 	svc := &servicePort{
 		ServicePort: baseSvc,
@@ -426,6 +481,34 @@ func makeServiceInfo(_ *v1.ServicePort, s *v1.Service, baseSvc *k8sp.BaseService
 		}
 	}
 
+	if v, ok := s.ObjectMeta.Annotations[LoadBalancingAnnotation]; ok && strings.EqualFold(v, LoadBalancingMaglev) {
+		svc.maglev = true
+		if baseSvc.ExternalPolicyLocal() || baseSvc.InternalPolicyLocal() {
+			// The lookup table holds all the backends, so it cannot be used when a
+			// flow is restricted to the local ones.
+			log.WithField("service", s.Name).Info("Service uses Maglev load balancing and a Local traffic policy, " +
+				"flows that are restricted to local backends pick one at random instead.")
+		}
+	}
+
+	if v, ok := s.ObjectMeta.Annotations[LocalityAnnotation]; ok {
+		w, err := ParseLocalityWeights(v)
+		if err != nil {
+			log.WithError(err).WithField("service", s.Name).Warnf("Ignoring invalid %s annotation.", LocalityAnnotation)
+		} else {
+			svc.locality = w
+		}
+	}
+
+	if v, ok := s.ObjectMeta.Annotations[AffinityPrefixLengthAnnotation]; ok {
+		p, err := ParseAffinityPrefixLengths(v)
+		if err != nil {
+			log.WithError(err).WithField("service", s.Name).Warnf("Ignoring invalid %s annotation.", AffinityPrefixLengthAnnotation)
+		} else {
+			svc.affinityPrefixLens = p
+		}
+	}
+
 out:
 	return svc
 }
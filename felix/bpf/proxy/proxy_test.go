@@ -567,6 +567,30 @@ var _ = Describe("BPF Proxy", func() {
 			})
 		})
 
+		Context("with endpoint zones", func() {
+			BeforeEach(func() {
+				slice := testSvcEpsSlice.DeepCopy()
+				slice.Endpoints[0].Zone = strPtr("us-west-2a")
+				k8s = fake.NewSimpleClientset(testSvc, slice)
+			})
+
+			It("should keep the zones of the endpoints", func() {
+				dp.checkState(func(s proxy.DPSyncerState) {
+					Expect(len(s.EpsMap)).To(Equal(1))
+					zones := map[string]string{}
+					for _, eps := range s.EpsMap {
+						for _, ep := range eps {
+							zones[ep.IP()] = ep.(interface{ Zone() string }).Zone()
+						}
+					}
+					Expect(zones).To(Equal(map[string]string{
+						testSvcEpsSlice.Endpoints[0].Addresses[0]: "us-west-2a",
+						testSvcEpsSlice.Endpoints[1].Addresses[0]: "",
+					}))
+				})
+			})
+		})
+
 		Context("annotated service", func() {
 			BeforeEach(func() {
 				testSvc := &v1.Service{
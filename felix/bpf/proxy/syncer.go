@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -105,8 +106,9 @@ func isSvcKeyDerived(skey svcKey) bool {
 }
 
 type stickyFrontend struct {
-	id    uint32
-	timeo time.Duration
+	id        uint32
+	timeo     time.Duration
+	prefixLen int
 }
 
 // Syncer is an implementation of DPSyncer interface. It is not thread safe and
@@ -118,10 +120,17 @@ type Syncer struct {
 	bpfEps  *cachingmap.CachingMap[nat.BackendKey, nat.BackendValueInterface]
 	bpfAff  maps.Map
 
+	// backendMapSize is the capacity of the backend map, or zero if not known, and
+	// numBackends the number of backend map entries written by the current Apply().
+	backendMapSize int
+	numBackends    int
+
 	nextSvcID uint32
 
 	nodePortIPs []net.IP
 	rt          Routes
+	// nodeZone is the zone of this node as of the last Apply()
+	nodeZone string
 
 	// new maps are valid during the Apply()'s runtime to provide easy access
 	// to updating them. They become prev at the end of it to be compared
@@ -225,6 +234,8 @@ func NewSyncer(family int, nodePortIPs []net.IP,
 		prevEpsMap:    make(k8sp.EndpointsMap),
 		stop:          make(chan struct{}),
 		excludedCIDRs: excludedCIDRs,
+
+		backendMapSize: maps.Size(backendMap.GetName()),
 	}
 
 	switch family {
@@ -549,6 +560,9 @@ func (s *Syncer) applyDerived(
 			flags |= nat.NATFlgInternalLocal
 		}
 	}
+	if sinfo.Maglev() {
+		flags |= nat.NATFlgMaglev
+	}
 
 	newInfo := svcInfo{
 		id:         svc.id,
@@ -586,6 +600,7 @@ func (s *Syncer) apply(state DPSyncerState) error {
 	s.newSvcMap = make(map[svcKey]svcInfo, len(state.SvcMap))
 	s.newEpsMap = make(k8sp.EndpointsMap, len(state.EpsMap))
 	nodeZone := state.NodeZone
+	s.nodeZone = nodeZone
 
 	var expNPMisses []*expandMiss
 
@@ -593,6 +608,7 @@ func (s *Syncer) apply(state DPSyncerState) error {
 	// let CachingMap calculate deltas...
 	s.bpfSvcs.Desired().DeleteAll()
 	s.bpfEps.Desired().DeleteAll()
+	s.numBackends = 0
 
 	// insert or update existing services
 	for sname, sinfo := range state.SvcMap {
@@ -606,7 +622,9 @@ func (s *Syncer) apply(state DPSyncerState) error {
 		for _, ep := range state.EpsMap[sname] {
 			zoneHints := ep.ZoneHints()
 			if ep.IsReady() || ep.IsTerminating() {
-				if ShouldAppendTopologyAwareEndpoint(nodeZone, hintsAnnotation, zoneHints) {
+				// Locality weights use the zones of the endpoints to prefer the ones in the
+				// same zone instead of filtering the other endpoints out.
+				if svc.Locality() != nil || ShouldAppendTopologyAwareEndpoint(nodeZone, hintsAnnotation, zoneHints) {
 					eps = append(eps, ep)
 				} else {
 					log.Debugf("Topology Aware Hints: '%s' for Endpoint: '%s' however Zone: '%s' does not match Zone Hints: '%v'\n",
@@ -747,6 +765,7 @@ func (s *Syncer) Apply(state DPSyncerState) error {
 
 func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp.Endpoint) (int, int, error) {
 	cpEps := make([]k8sp.Endpoint, 0, len(eps))
+	ready := make([]k8sp.Endpoint, 0, len(eps))
 
 	cnt := 0
 	local := 0
@@ -757,34 +776,72 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 		s.stickyEps[id] = make(map[nat.BackendValueInterface]struct{})
 	}
 
-	for _, ep := range eps {
+	// With locality weights, each backend is written to as many slots as its weight
+	// and the BPF programs pick a slot at random.
+	var slots []int
+	if w := sinfo.Locality(); w != nil && !sinfo.Maglev() {
+		slots = LocalityWeightedSlots(*w, s.nodeZone, eps)
+		n := 0
+		for _, sl := range slots {
+			n += sl
+		}
+		if slots == nil {
+			log.WithFields(log.Fields{
+				"service":  skey.sname,
+				"weights":  w,
+				"maxSlots": MaxLocalitySlots,
+			}).Warn("Service has too many backends for its locality weights, its backends are used evenly.")
+		} else if s.backendMapSize > 0 && s.numBackends+n > s.backendMapSize {
+			log.WithFields(log.Fields{
+				"service": skey.sname,
+				"weights": w,
+				"mapSize": s.backendMapSize,
+			}).Warn("NAT backend map is too small for the locality weights of a service, " +
+				"its backends are used evenly.  Increase BPFMapSizeNATBackend.")
+			slots = nil
+		}
+	}
+	epSlots := func(i int) int {
+		if slots == nil {
+			return 1
+		}
+		return slots[i]
+	}
+
+	for i, ep := range eps {
 		if !ep.IsLocal() {
 			continue
 		}
 
 		// eps could contain Ready and Terminating pods but only write Ready pods to backend.
 		if ep.IsReady() {
-			if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
-				return 0, 0, err
+			for n := epSlots(i); n > 0; n-- {
+				if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
+					return 0, 0, err
+				}
+				cnt++
+				local++
 			}
-			cnt++
-			local++
+			ready = append(ready, ep)
 		}
 
 		cpEps = append(cpEps, ep)
 	}
 
-	for _, ep := range eps {
+	for i, ep := range eps {
 		if ep.IsLocal() {
 			continue
 		}
 
 		// eps could contain Ready and Terminating pods but only write Ready pods to backend.
 		if ep.IsReady() {
-			if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
-				return 0, 0, err
+			for n := epSlots(i); n > 0; n-- {
+				if err := s.writeSvcBackend(id, uint32(cnt), ep); err != nil {
+					return 0, 0, err
+				}
+				cnt++
 			}
-			cnt++
+			ready = append(ready, ep)
 		}
 
 		cpEps = append(cpEps, ep)
@@ -794,6 +851,12 @@ func (s *Syncer) updateService(skey svcKey, sinfo Service, id uint32, eps []k8sp
 	if sinfo.InternalPolicyLocal() {
 		flags |= nat.NATFlgInternalLocal
 	}
+	if sinfo.Maglev() {
+		flags |= nat.NATFlgMaglev
+		if err := s.writeSvcMaglevLUT(id, ready); err != nil {
+			return 0, 0, err
+		}
+	}
 
 	if err := s.writeSvc(sinfo, id, cnt, local, flags); err != nil {
 		return 0, 0, err
@@ -829,6 +892,7 @@ func (s *Syncer) writeSvcBackend(svcID uint32, idx uint32, ep k8sp.Endpoint) err
 	tgtPort := ep.Port()
 	val := s.newBackendValue(ip, uint16(tgtPort))
 	s.bpfEps.Desired().Set(key, val)
+	s.numBackends++
 
 	if s.stickyEps[svcID] != nil {
 		s.stickyEps[svcID][val] = struct{}{}
@@ -837,6 +901,48 @@ func (s *Syncer) writeSvcBackend(svcID uint32, idx uint32, ep k8sp.Endpoint) err
 	return nil
 }
 
+// writeSvcMaglevLUT writes the Maglev lookup table of a service with the given
+// backends.  The BPF programs use it instead of picking a random backend.
+func (s *Syncer) writeSvcMaglevLUT(svcID uint32, eps []k8sp.Endpoint) error {
+	if len(eps) == 0 {
+		return nil
+	}
+
+	if s.backendMapSize > 0 && s.numBackends+nat.MaglevLUTSize > s.backendMapSize {
+		// Without the lookup table the BPF programs fall back to picking a random
+		// backend, which is better than running out of space for the backends.
+		log.WithFields(log.Fields{
+			"svcID":       svcID,
+			"mapSize":     s.backendMapSize,
+			"lookupTable": nat.MaglevLUTSize,
+		}).Warn("NAT backend map is too small for the Maglev lookup table of a service, " +
+			"its backends are picked at random.  Increase BPFMapSizeNATBackend.")
+		return nil
+	}
+
+	names := make([]string, len(eps))
+	vals := make([]nat.BackendValueInterface, len(eps))
+	for i, ep := range eps {
+		port := ep.Port()
+		names[i] = net.JoinHostPort(ep.IP(), strconv.Itoa(port))
+		vals[i] = s.newBackendValue(net.ParseIP(ep.IP()), uint16(port))
+	}
+
+	if log.GetLevel() >= log.DebugLevel {
+		log.WithFields(log.Fields{
+			"svcID":    svcID,
+			"backends": names,
+		}).Debug("Writing service Maglev lookup table.")
+	}
+
+	for slot, idx := range MaglevLUT(names, nat.MaglevLUTSize) {
+		s.bpfEps.Desired().Set(nat.NewNATBackendKeyMaglev(svcID, uint32(slot)), vals[idx])
+	}
+	s.numBackends += nat.MaglevLUTSize
+
+	return nil
+}
+
 func (s *Syncer) getSvcNATKey(svc k8sp.ServicePort) (nat.FrontendKeyInterface, error) {
 	ip := svc.ClusterIP()
 	port := svc.Port()
@@ -877,11 +983,12 @@ func (s *Syncer) getSvcNATKeyLBSrcRange(svc k8sp.ServicePort) ([]nat.FrontendKey
 	return keys, nil
 }
 
-func (s *Syncer) writeLBSrcRangeSvcNATKeys(svc k8sp.ServicePort, svcID uint32, count, local int, flags uint32) error {
+func (s *Syncer) writeLBSrcRangeSvcNATKeys(svc Service, svcID uint32, count, local int, flags uint32) error {
 	var key nat.FrontendKeyInterface
 	affinityTimeo := uint32(0)
 	if svc.SessionAffinityType() == v1.ServiceAffinityClientIP {
 		affinityTimeo = uint32(svc.StickyMaxAgeSeconds())
+		flags |= nat.NATFlgsAffinityPrefixLen(s.affinityPrefixLen(svc))
 	}
 
 	if len(svc.LoadBalancerSourceRanges()) == 0 {
@@ -924,8 +1031,11 @@ func (s *Syncer) writeSvc(svc Service, svcID uint32, count, local int, flags uin
 	}
 
 	affinityTimeo := uint32(0)
+	prefixLen := 0
 	if svc.SessionAffinityType() == v1.ServiceAffinityClientIP {
 		affinityTimeo = uint32(svc.StickyMaxAgeSeconds())
+		prefixLen = s.affinityPrefixLen(svc)
+		flags |= nat.NATFlgsAffinityPrefixLen(prefixLen)
 	}
 
 	val := nat.NewNATValueWithFlags(svcID, uint32(count), uint32(local), affinityTimeo, flags)
@@ -939,14 +1049,24 @@ func (s *Syncer) writeSvc(svc Service, svcID uint32, count, local int, flags uin
 	if s.stickyEps[svcID] != nil {
 		affkey := key.AffinityKeyCopy()
 		s.stickySvcs[affkey] = stickyFrontend{
-			id:    svcID,
-			timeo: time.Duration(affinityTimeo) * time.Second,
+			id:        svcID,
+			timeo:     time.Duration(affinityTimeo) * time.Second,
+			prefixLen: prefixLen,
 		}
 	}
 
 	return nil
 }
 
+// affinityPrefixLen returns the length of the client prefixes that share the
+// session affinity of the service, 0 for an affinity per client.
+func (s *Syncer) affinityPrefixLen(svc Service) int {
+	if p := svc.AffinityPrefixLengths(); p != nil {
+		return p.For(s.ipFamily)
+	}
+	return 0
+}
+
 // ProtoV1ToInt translates k8s v1.Protocol to its IANA number and returns
 // error if the proto is not recognized
 func ProtoV1ToInt(p v1.Protocol) (uint8, error) {
@@ -1193,6 +1313,13 @@ func (s *Syncer) cleanupSticky() error {
 			return maps.IterDelete
 		}
 
+		if key.PrefixLen() != fend.prefixLen {
+			if debug {
+				log.Debugf("cleaning affinity %v:%v - prefix length changed", key, val)
+			}
+			return maps.IterDelete
+		}
+
 		if _, ok := s.stickyEps[fend.id][val.Backend()]; !ok {
 			if debug {
 				log.Debugf("cleaning affinity %v:%v - no such a backend", key, val)
@@ -1540,3 +1667,25 @@ func K8sSvcWithReapTerminatingUDP() K8sServicePortOption {
 		s.(*servicePort).reapTerminatingUDP = true
 	}
 }
+
+// K8sSvcWithMaglev selects Maglev load balancing
+func K8sSvcWithMaglev() K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).maglev = true
+	}
+}
+
+// K8sSvcWithLocality sets the locality weights of the backends
+func K8sSvcWithLocality(w LocalityWeights) K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).locality = &w
+	}
+}
+
+// K8sSvcWithAffinityPrefixLengths sets the lengths of the client prefixes that
+// share the session affinity
+func K8sSvcWithAffinityPrefixLengths(p AffinityPrefixLengths) K8sServicePortOption {
+	return func(s interface{}) {
+		s.(*servicePort).affinityPrefixLens = &p
+	}
+}
@@ -1274,6 +1274,203 @@ var _ = Describe("BPF Syncer", func() {
 		})
 
 	})
+
+	It("should program a Maglev lookup table if service annotated as such", func() {
+		maglevState := func(opts ...proxy.K8sServicePortOption) proxy.DPSyncerState {
+			return proxy.DPSyncerState{
+				SvcMap: k8sp.ServicePortMap{
+					svcKey: proxy.NewK8sServicePort(
+						net.IPv4(10, 0, 0, 1),
+						1234,
+						v1.ProtocolTCP,
+						opts...,
+					),
+				},
+				EpsMap: k8sp.EndpointsMap{
+					svcKey: []k8sp.Endpoint{
+						proxy.NewEndpointInfo("10.1.0.1", 5555, proxy.EndpointInfoOptIsReady(true)),
+						proxy.NewEndpointInfo("10.1.0.2", 5555, proxy.EndpointInfoOptIsReady(true)),
+						proxy.NewEndpointInfo("10.1.0.3", 5555, proxy.EndpointInfoOptIsTerminating(true)),
+					},
+				},
+			}
+		}
+
+		err := s.Apply(maglevState(proxy.K8sSvcWithMaglev()))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(svcs.m).To(HaveLen(1))
+		val, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+		Expect(ok).To(BeTrue())
+		Expect(val.Count()).To(Equal(uint32(2)))
+		Expect(val.Flags() & nat.NATFlgMaglev).NotTo(BeZero())
+
+		// 2 backends and the lookup table, which has only the ready backends.
+		Expect(eps.m).To(HaveLen(2 + nat.MaglevLUTSize))
+		seen := map[nat.BackendValue]int{}
+		for slot := uint32(0); slot < nat.MaglevLUTSize; slot++ {
+			bval, ok := eps.m[nat.NewNATBackendKeyMaglev(val.ID(), slot)]
+			Expect(ok).To(BeTrue())
+			seen[bval]++
+		}
+		Expect(seen).To(HaveLen(2))
+		Expect(seen).To(HaveKey(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555)))
+		Expect(seen).To(HaveKey(nat.NewNATBackendValue(net.IPv4(10, 1, 0, 2), 5555)))
+
+		By("removing the annotation")
+
+		err = s.Apply(maglevState())
+		Expect(err).NotTo(HaveOccurred())
+
+		val, ok = svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+		Expect(ok).To(BeTrue())
+		Expect(val.Flags() & nat.NATFlgMaglev).To(BeZero())
+		Expect(eps.m).To(HaveLen(2))
+	})
+
+	It("should not program a Maglev lookup table that does not fit in the backend map", func() {
+		maps.SetSize(eps.GetName(), 100)
+		defer maps.SetSize(eps.GetName(), 0)
+		s, _ = proxy.NewSyncer(4, nodeIPs, svcs, eps, aff, rt, nil)
+
+		err := s.Apply(proxy.DPSyncerState{
+			SvcMap: k8sp.ServicePortMap{
+				svcKey: proxy.NewK8sServicePort(
+					net.IPv4(10, 0, 0, 1),
+					1234,
+					v1.ProtocolTCP,
+					proxy.K8sSvcWithMaglev(),
+				),
+			},
+			EpsMap: k8sp.EndpointsMap{
+				svcKey: []k8sp.Endpoint{
+					proxy.NewEndpointInfo("10.1.0.1", 5555, proxy.EndpointInfoOptIsReady(true)),
+					proxy.NewEndpointInfo("10.1.0.2", 5555, proxy.EndpointInfoOptIsReady(true)),
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The BPF programs pick a random backend when the lookup table is missing.
+		val, ok := svcs.m[nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))]
+		Expect(ok).To(BeTrue())
+		Expect(val.Count()).To(Equal(uint32(2)))
+		Expect(eps.m).To(HaveLen(2))
+	})
+
+	It("should weight the backends by locality if service annotated as such", func() {
+		localityState := func(localReady bool) proxy.DPSyncerState {
+			return proxy.DPSyncerState{
+				SvcMap: k8sp.ServicePortMap{
+					svcKey: proxy.NewK8sServicePort(
+						net.IPv4(10, 0, 0, 1),
+						1234,
+						v1.ProtocolTCP,
+						proxy.K8sSvcWithLocality(proxy.LocalityWeights{Node: 6, Zone: 2}),
+					),
+				},
+				EpsMap: k8sp.EndpointsMap{
+					svcKey: []k8sp.Endpoint{
+						proxy.NewEndpointInfo("10.1.0.1", 5555, proxy.EndpointInfoOptIsReady(localReady),
+							proxy.EndpointInfoOptIsTerminating(!localReady), proxy.EndpointInfoOptIsLocal(true)),
+						proxy.NewEndpointInfo("10.2.0.1", 5555, proxy.EndpointInfoOptIsReady(true),
+							proxy.EndpointInfoOptZone("us-west-2a")),
+						proxy.NewEndpointInfo("10.3.0.1", 5555, proxy.EndpointInfoOptIsReady(true),
+							proxy.EndpointInfoOptZone("us-west-2b")),
+					},
+				},
+				NodeZone: "us-west-2a",
+			}
+		}
+		backends := func(svc nat.FrontendValue) map[nat.BackendValue]int {
+			res := map[nat.BackendValue]int{}
+			for idx := uint32(0); idx < svc.Count(); idx++ {
+				bval, ok := eps.m[nat.NewNATBackendKey(svc.ID(), idx)]
+				Expect(ok).To(BeTrue())
+				res[bval]++
+			}
+			return res
+		}
+		natKey := nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))
+
+		err := s.Apply(localityState(true))
+		Expect(err).NotTo(HaveOccurred())
+
+		val, ok := svcs.m[natKey]
+		Expect(ok).To(BeTrue())
+		Expect(val.Count()).To(Equal(uint32(4)))
+		Expect(val.LocalCount()).To(Equal(uint32(3)))
+		Expect(backends(val)).To(Equal(map[nat.BackendValue]int{
+			nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555): 3,
+			nat.NewNATBackendValue(net.IPv4(10, 2, 0, 1), 5555): 1,
+		}))
+
+		By("falling back when the local backend is not ready")
+
+		err = s.Apply(localityState(false))
+		Expect(err).NotTo(HaveOccurred())
+
+		val, ok = svcs.m[natKey]
+		Expect(ok).To(BeTrue())
+		Expect(val.Count()).To(Equal(uint32(1)))
+		Expect(val.LocalCount()).To(BeZero())
+		Expect(backends(val)).To(Equal(map[nat.BackendValue]int{
+			nat.NewNATBackendValue(net.IPv4(10, 2, 0, 1), 5555): 1,
+		}))
+
+		By("using the backends evenly when the weighted slots do not fit in the backend map")
+
+		maps.SetSize(eps.GetName(), 3)
+		defer maps.SetSize(eps.GetName(), 0)
+		s, _ = proxy.NewSyncer(4, nodeIPs, svcs, eps, aff, rt, nil)
+
+		err = s.Apply(localityState(true))
+		Expect(err).NotTo(HaveOccurred())
+
+		val, ok = svcs.m[natKey]
+		Expect(ok).To(BeTrue())
+		Expect(val.Count()).To(Equal(uint32(3)))
+		Expect(val.LocalCount()).To(Equal(uint32(1)))
+		Expect(backends(val)).To(Equal(map[nat.BackendValue]int{
+			nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555): 1,
+			nat.NewNATBackendValue(net.IPv4(10, 2, 0, 1), 5555): 1,
+			nat.NewNATBackendValue(net.IPv4(10, 3, 0, 1), 5555): 1,
+		}))
+	})
+	It("should share the affinity of a client prefix if service annotated as such", func() {
+		natKey := nat.NewNATKey(net.IPv4(10, 0, 0, 1), 1234, proxy.ProtoV1ToIntPanic(v1.ProtocolTCP))
+		state.SvcMap[svcKey] = proxy.NewK8sServicePort(
+			net.IPv4(10, 0, 0, 1),
+			1234,
+			v1.ProtocolTCP,
+			proxy.K8sSvcWithStickyClientIP(60),
+			proxy.K8sSvcWithAffinityPrefixLengths(proxy.AffinityPrefixLengths{V4: 24, V6: 64}),
+		)
+
+		err := s.Apply(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		val, ok := svcs.m[natKey]
+		Expect(ok).To(BeTrue())
+		Expect(val.AffinityTimeout()).To(Equal(60 * time.Second))
+		Expect(val.AffinityPrefixLen()).To(Equal(24))
+		Expect(val.Flags() & nat.NATFlgMaglev).To(BeZero())
+
+		By("evicting the affinity entries of other prefix lengths")
+
+		backend := nat.NewAffinityValue(uint64(bpf.KTimeNanos()), nat.NewNATBackendValue(net.IPv4(10, 1, 0, 1), 5555))
+		clientKey := nat.NewAffinityKey(net.IPv4(5, 5, 5, 5), natKey)
+		prefixKey := clientKey.WithPrefixLen(24)
+		Expect(prefixKey.ClientIP()).To(Equal(net.IP{5, 5, 5, 0}))
+		Expect(aff.Update(clientKey.AsBytes(), backend.AsBytes())).To(Succeed())
+		Expect(aff.Update(prefixKey.AsBytes(), backend.AsBytes())).To(Succeed())
+
+		err = s.Apply(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(aff.m).To(HaveLen(1))
+		Expect(aff.m).To(HaveKey(prefixKey))
+	})
 })
 
 type mockNATMap struct {
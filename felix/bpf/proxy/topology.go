@@ -15,9 +15,17 @@
 package proxy
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8sp "k8s.io/kubernetes/pkg/proxy"
 )
 
 //nolint:staticcheck // Ignore SA1019 deprecated until kubernetes/pkg/proxy/types.go fixes sets.String
@@ -56,3 +64,237 @@ func ShouldAppendTopologyAwareEndpoint(nodeZone string, hintsAnnotation string,
 	// Return whether zone hints contain node label zone.
 	return zoneHints.Has(nodeZone)
 }
+
+const (
+	// LocalityPreferLocal is the value of the LocalityAnnotation that sends new flows
+	// to the node-local backends, then to the backends in the same zone and then to any
+	// backend.  It is equivalent to "node=1,zone=0,any=0".
+	LocalityPreferLocal = "PreferLocal"
+
+	// MaxLocalityWeight is the maximum weight of a locality.
+	MaxLocalityWeight = 100
+
+	// MaxLocalitySlots is the maximum number of slots that the backends of a service
+	// with locality weights get in total.  Larger weights are scaled down to fit.
+	MaxLocalitySlots = 64
+)
+
+// LocalityWeights are the weights of the backends of a service by their locality
+// relative to this node.  A backend with weight 2 gets twice as many new flows as a
+// backend with weight 1.  Backends with weight 0 are used only as a fallback, when
+// there are no backends with a positive weight; the localities then fall back in
+// the order node, zone, any.
+type LocalityWeights struct {
+	Node uint32
+	Zone uint32
+	Any  uint32
+}
+
+func (w LocalityWeights) String() string {
+	return fmt.Sprintf("node=%d,zone=%d,any=%d", w.Node, w.Zone, w.Any)
+}
+
+// ParseLocalityWeights parses the value of the LocalityAnnotation, which is either
+// LocalityPreferLocal or a comma separated list of node=W, zone=W and any=W.  The
+// localities that are not listed have weight 0.
+func ParseLocalityWeights(v string) (*LocalityWeights, error) {
+	if strings.EqualFold(v, LocalityPreferLocal) {
+		return &LocalityWeights{Node: 1}, nil
+	}
+
+	var w LocalityWeights
+	for _, part := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("locality weight %q is not in the form locality=weight", part)
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+		if err != nil || weight > MaxLocalityWeight {
+			return nil, fmt.Errorf("locality weight %q is not a number between 0 and %d", part, MaxLocalityWeight)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "node":
+			w.Node = uint32(weight)
+		case "zone":
+			w.Zone = uint32(weight)
+		case "any":
+			w.Any = uint32(weight)
+		default:
+			return nil, fmt.Errorf("unknown locality %q, expected node, zone or any", name)
+		}
+	}
+
+	return &w, nil
+}
+
+const (
+	localityNode = iota
+	localityZone
+	localityAny
+	numLocalities
+)
+
+func endpointLocality(nodeZone string, ep k8sp.Endpoint) int {
+	if ep.IsLocal() {
+		return localityNode
+	}
+	if z, ok := ep.(interface{ Zone() string }); ok && nodeZone != "" && z.Zone() == nodeZone {
+		return localityZone
+	}
+	return localityAny
+}
+
+// LocalityWeightedSlots returns, for each of the endpoints, the number of slots it
+// gets in the backends of a service with the given locality weights.  The BPF
+// programs pick one of the slots at random, so the slots implement the weights.
+// Endpoints that are not ready get no slot, so that when all the ready endpoints of
+// a locality are gone, its share of the flows falls back to the other localities.
+// The nodeZone is the zone of this node; the endpoints in the same zone are those
+// whose EndpointSlice zone is the same, whether or not the slice has zone hints.
+//
+// The weights are scaled down so that there are at most MaxLocalitySlots slots in
+// total, every locality in use keeping at least one slot per endpoint.  If even that
+// does not fit, LocalityWeightedSlots returns nil and the endpoints should get a slot
+// each.
+func LocalityWeightedSlots(w LocalityWeights, nodeZone string, eps []k8sp.Endpoint) []int {
+	weights := [numLocalities]uint32{w.Node, w.Zone, w.Any}
+
+	var ready [numLocalities]int
+	for _, ep := range eps {
+		if ep.IsReady() {
+			ready[endpointLocality(nodeZone, ep)]++
+		}
+	}
+
+	// Use the localities with a positive weight that have ready endpoints, or else
+	// the first locality that has ready endpoints.
+	var slots [numLocalities]uint32
+	for l := range weights {
+		if weights[l] > 0 && ready[l] > 0 {
+			slots[l] = weights[l]
+		}
+	}
+	if localitySlotsTotal(slots, ready) == 0 {
+		for l := range ready {
+			if ready[l] > 0 {
+				slots[l] = 1
+				break
+			}
+		}
+	}
+
+	var divisor uint32
+	for l := range slots {
+		divisor = gcd(divisor, slots[l])
+	}
+	if divisor > 1 {
+		for l := range slots {
+			slots[l] /= divisor
+		}
+	}
+
+	if t := localitySlotsTotal(slots, ready); t > MaxLocalitySlots {
+		for l := range slots {
+			if slots[l] == 0 {
+				continue
+			}
+			slots[l] = uint32(uint64(slots[l]) * MaxLocalitySlots / t)
+			if slots[l] == 0 {
+				slots[l] = 1
+			}
+		}
+		if localitySlotsTotal(slots, ready) > MaxLocalitySlots {
+			return nil
+		}
+	}
+
+	res := make([]int, len(eps))
+	for i, ep := range eps {
+		if ep.IsReady() {
+			res[i] = int(slots[endpointLocality(nodeZone, ep)])
+		}
+	}
+
+	return res
+}
+
+// localitySlotsTotal returns the number of slots that the ready endpoints of the localities get.
+func localitySlotsTotal(slots [numLocalities]uint32, ready [numLocalities]int) uint64 {
+	var t uint64
+	for l := range slots {
+		t += uint64(slots[l]) * uint64(ready[l])
+	}
+	return t
+}
+
+func gcd(a, b uint32) uint32 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// endpointZones keeps the zones of the endpoints of the EndpointSlices, which the
+// EndpointsChangeTracker drops, so that the endpoints it makes can carry them.
+type endpointZones struct {
+	lock sync.Mutex
+	// bySvc holds, for each service, the zones of the addresses of each of its slices.
+	bySvc map[types.NamespacedName]map[string]map[string]string
+}
+
+func newEndpointZones() *endpointZones {
+	return &endpointZones{
+		bySvc: map[types.NamespacedName]map[string]map[string]string{},
+	}
+}
+
+func (z *endpointZones) update(eps *discovery.EndpointSlice, remove bool) {
+	svcName := eps.Labels[discovery.LabelServiceName]
+	if svcName == "" {
+		return
+	}
+	svc := types.NamespacedName{Namespace: eps.Namespace, Name: svcName}
+
+	zones := map[string]string{}
+	if !remove {
+		for _, ep := range eps.Endpoints {
+			if ep.Zone == nil || *ep.Zone == "" {
+				continue
+			}
+			for _, addr := range ep.Addresses {
+				zones[addr] = *ep.Zone
+			}
+		}
+	}
+
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	slices := z.bySvc[svc]
+	if len(zones) == 0 {
+		delete(slices, eps.Name)
+		if len(slices) == 0 {
+			delete(z.bySvc, svc)
+		}
+		return
+	}
+	if slices == nil {
+		slices = map[string]map[string]string{}
+		z.bySvc[svc] = slices
+	}
+	slices[eps.Name] = zones
+}
+
+// zone returns the zone of the endpoint of the service with the given address, empty
+// if it is not known.
+func (z *endpointZones) zone(svc types.NamespacedName, addr string) string {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	for _, zones := range z.bySvc[svc] {
+		if zone, ok := zones[addr]; ok {
+			return zone
+		}
+	}
+	return ""
+}
@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8sp "k8s.io/kubernetes/pkg/proxy"
 
 	"github.com/projectcalico/calico/felix/bpf/proxy"
 )
@@ -89,3 +90,70 @@ func TestShouldAppendTopologyAwareEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLocalityWeights(t *testing.T) {
+	RegisterTestingT(t)
+
+	w, err := proxy.ParseLocalityWeights("PreferLocal")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(*w).To(Equal(proxy.LocalityWeights{Node: 1}))
+
+	w, err = proxy.ParseLocalityWeights("node=80, zone=15,any=5")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(*w).To(Equal(proxy.LocalityWeights{Node: 80, Zone: 15, Any: 5}))
+	Expect(w.String()).To(Equal("node=80,zone=15,any=5"))
+
+	for _, v := range []string{"", "node", "node=x", "node=101", "rack=1"} {
+		_, err = proxy.ParseLocalityWeights(v)
+		Expect(err).To(HaveOccurred(), v)
+	}
+}
+
+func TestLocalityWeightedSlots(t *testing.T) {
+	RegisterTestingT(t)
+
+	local := proxy.NewEndpointInfo("10.0.0.1", 80, proxy.EndpointInfoOptIsReady(true), proxy.EndpointInfoOptIsLocal(true))
+	localNotReady := proxy.NewEndpointInfo("10.0.0.2", 80, proxy.EndpointInfoOptIsTerminating(true), proxy.EndpointInfoOptIsLocal(true))
+	zone := proxy.NewEndpointInfo("10.0.1.1", 80, proxy.EndpointInfoOptIsReady(true),
+		proxy.EndpointInfoOptZone("us-west-2a"), proxy.EndpointInfoOptZoneHints(sets.New[string]("us-west-2a")))
+	other := proxy.NewEndpointInfo("10.0.2.1", 80, proxy.EndpointInfoOptIsReady(true),
+		proxy.EndpointInfoOptZone("us-west-2b"), proxy.EndpointInfoOptZoneHints(sets.New[string]("us-west-2b")))
+	eps := []k8sp.Endpoint{local, localNotReady, zone, other}
+
+	// The weights are reduced by their greatest common divisor.
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 80, Zone: 20, Any: 20}, "us-west-2a", eps)).
+		To(Equal([]int{4, 0, 1, 1}))
+	// Without the node zone, there is no zone locality.
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 2, Any: 1}, "", eps)).
+		To(Equal([]int{2, 0, 1, 1}))
+	// Localities with weight 0 are used only as a fallback, in order.
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 1}, "us-west-2a", eps)).
+		To(Equal([]int{1, 0, 0, 0}))
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 1}, "us-west-2a", eps[1:])).
+		To(Equal([]int{0, 1, 0}))
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Zone: 1}, "us-west-2a", []k8sp.Endpoint{local, other})).
+		To(Equal([]int{1, 0}))
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{}, "us-west-2a", nil)).To(BeEmpty())
+
+	// The zone of an endpoint counts without topology aware routing, when the
+	// EndpointSlice has no hints, and the hints alone do not.
+	zoneNoHints := proxy.NewEndpointInfo("10.0.1.2", 80, proxy.EndpointInfoOptIsReady(true),
+		proxy.EndpointInfoOptZone("us-west-2a"))
+	hintsOnly := proxy.NewEndpointInfo("10.0.2.2", 80, proxy.EndpointInfoOptIsReady(true),
+		proxy.EndpointInfoOptZoneHints(sets.New[string]("us-west-2a")))
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Zone: 1}, "us-west-2a",
+		[]k8sp.Endpoint{zoneNoHints, hintsOnly, other})).To(Equal([]int{1, 0, 0}))
+
+	// Large weights are scaled down to fit the slots.
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 100, Any: 1}, "", eps)).
+		To(Equal([]int{62, 0, 1, 1}))
+
+	// Too many backends for their weights to fit.
+	many := []k8sp.Endpoint{local}
+	for i := 0; i < proxy.MaxLocalitySlots; i++ {
+		many = append(many, other)
+	}
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 2, Any: 1}, "", many)).To(BeNil())
+	Expect(proxy.LocalityWeightedSlots(proxy.LocalityWeights{Node: 2, Any: 1}, "", many[:proxy.MaxLocalitySlots-1])).
+		To(HaveLen(proxy.MaxLocalitySlots - 1))
+}
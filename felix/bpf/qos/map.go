@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qos holds the map that configures the bandwidth limits of the workloads, which the
+// BPF programs enforce by setting the departure time of their packets.
+package qos
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+// struct cali_qos_key {
+//  __u32 ifindex;
+//  __u32 egress;
+// };
+//
+// struct cali_qos_val {
+//  __u64 rate_bytes_per_sec;
+//  __u64 t_last;
+// };
+
+const (
+	KeySize    = 8
+	ValueSize  = 16
+	MaxEntries = 64 * 1024
+)
+
+var MapParameters = maps.MapParameters{
+	Type:         "hash",
+	KeySize:      KeySize,
+	ValueSize:    ValueSize,
+	MaxEntries:   MaxEntries,
+	Name:         "cali_qos",
+	Flags:        unix.BPF_F_NO_PREALLOC,
+	UpdatedByBPF: true,
+}
+
+func Map() maps.Map {
+	return maps.NewPinnedMap(MapParameters)
+}
+
+type Key [KeySize]byte
+
+// NewKey returns the key of the limit of the traffic from the workload with the given
+// interface if egress is true, of the traffic to it otherwise.
+func NewKey(ifIndex uint32, egress bool) Key {
+	var k Key
+
+	binary.LittleEndian.PutUint32(k[0:4], ifIndex)
+	if egress {
+		binary.LittleEndian.PutUint32(k[4:8], 1)
+	}
+
+	return k
+}
+
+func KeyFromBytes(b []byte) Key {
+	var k Key
+	copy(k[:], b)
+	return k
+}
+
+func (k Key) AsBytes() []byte {
+	return k[:]
+}
+
+func (k Key) IfIndex() uint32 {
+	return binary.LittleEndian.Uint32(k[0:4])
+}
+
+func (k Key) Egress() bool {
+	return binary.LittleEndian.Uint32(k[4:8]) != 0
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("{ifIndex: %d, egress: %t}", k.IfIndex(), k.Egress())
+}
+
+type Value [ValueSize]byte
+
+// NewValue returns the value of a limit of the given number of bits per second.
+func NewValue(rateBits int64) Value {
+	var v Value
+
+	binary.LittleEndian.PutUint64(v[0:8], uint64(rateBits)/8)
+
+	return v
+}
+
+// WithRate returns a copy of the value with the limit changed to the given number of bits
+// per second, keeping the departure time that the BPF programs maintain.
+func (v Value) WithRate(rateBits int64) Value {
+	binary.LittleEndian.PutUint64(v[0:8], uint64(rateBits)/8)
+	return v
+}
+
+func ValueFromBytes(b []byte) Value {
+	var v Value
+	copy(v[:], b)
+	return v
+}
+
+func (v Value) AsBytes() []byte {
+	return v[:]
+}
+
+// RateBytes returns the limit in bytes per second.
+func (v Value) RateBytes() uint64 {
+	return binary.LittleEndian.Uint64(v[0:8])
+}
+
+// TLast returns the departure time of the last limited packet.
+func (v Value) TLast() uint64 {
+	return binary.LittleEndian.Uint64(v[8:16])
+}
+
+func (v Value) String() string {
+	return fmt.Sprintf("{rate: %d B/s}", v.RateBytes())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestKeyValue(t *testing.T) {
+	RegisterTestingT(t)
+
+	k := NewKey(7, true)
+	Expect(k.IfIndex()).To(Equal(uint32(7)))
+	Expect(k.Egress()).To(BeTrue())
+	Expect(KeyFromBytes(k.AsBytes())).To(Equal(k))
+	Expect(NewKey(7, false).Egress()).To(BeFalse())
+	Expect(k.String()).To(Equal("{ifIndex: 7, egress: true}"))
+
+	v := NewValue(8 * 1000 * 1000)
+	Expect(v.RateBytes()).To(Equal(uint64(1000 * 1000)))
+	Expect(ValueFromBytes(v.AsBytes())).To(Equal(v))
+
+	var b [ValueSize]byte
+	copy(b[:], v.AsBytes())
+	b[8] = 42
+	v = ValueFromBytes(b[:]).WithRate(16 * 1000 * 1000)
+	Expect(v.RateBytes()).To(Equal(uint64(2 * 1000 * 1000)))
+	Expect(v.TLast()).To(Equal(uint64(42)))
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot writes the contents of the BPF maps of a node, and the files that
+// describe its BPF programs, into a single archive and reads them back as read-only maps,
+// so that they can be decoded on another machine with the usual key/value decoders.
+//
+// The archive is a gzipped tar with a file per map under maps/, holding its entries as
+// consecutive key and value pairs, a file per extra file under files/ and a manifest.json.
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+// Version is the version of the archive format, the Manifest records it.
+const Version = 1
+
+const (
+	manifestName = "manifest.json"
+	mapsDir      = "maps"
+	filesDir     = "files"
+)
+
+var ErrReadOnly = errors.New("snapshot maps are read-only")
+
+// Manifest describes the contents of a snapshot.
+type Manifest struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Hostname string    `json:"hostname"`
+	// NumPossibleCPUs is the number of CPUs of the node, needed to decode the values of
+	// per-CPU maps.
+	NumPossibleCPUs int `json:"numPossibleCPUs"`
+	// KTimeNanos is the monotonic time of the node when the snapshot was taken, the
+	// timestamps in the maps are relative to it.  Zero in snapshots of older versions.
+	KTimeNanos int64     `json:"ktimeNanos,omitempty"`
+	Maps       []MapInfo `json:"maps"`
+	Files      []string  `json:"files"`
+}
+
+// MapInfo describes a map of a snapshot.
+type MapInfo struct {
+	// Name is the versioned name of the map.
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	KeySize int    `json:"keySize"`
+	// ValueSize is the size of the values of the entries, for the per-CPU maps, it
+	// includes the values of all the CPUs.
+	ValueSize int `json:"valueSize"`
+	Entries   int `json:"entries"`
+}
+
+// Writer writes a snapshot.  The caller must call Close to complete it.
+type Writer struct {
+	manifest Manifest
+	gz       *gzip.Writer
+	tw       *tar.Writer
+}
+
+func NewWriter(w io.Writer, hostname string, numPossibleCPUs int, ktimeNanos int64) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		manifest: Manifest{
+			Version:         Version,
+			Created:         time.Now().UTC(),
+			Hostname:        hostname,
+			NumPossibleCPUs: numPossibleCPUs,
+			KTimeNanos:      ktimeNanos,
+		},
+		gz: gz,
+		tw: tar.NewWriter(gz),
+	}
+}
+
+func (w *Writer) writeFile(name string, data []byte) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: w.manifest.Created,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.tw.Write(data)
+	return err
+}
+
+// AddMap adds the entries of an open map to the snapshot.
+func (w *Writer) AddMap(m maps.Map, mapType string) error {
+	info := MapInfo{
+		Name: m.GetName(),
+		Type: mapType,
+	}
+
+	var buf bytes.Buffer
+	err := m.Iter(func(k, v []byte) maps.IteratorAction {
+		if info.Entries == 0 {
+			info.KeySize = len(k)
+			info.ValueSize = len(v)
+		}
+		buf.Write(k)
+		buf.Write(v)
+		info.Entries++
+		return maps.IterNone
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read map %s: %w", info.Name, err)
+	}
+
+	if err := w.writeFile(path.Join(mapsDir, info.Name), buf.Bytes()); err != nil {
+		return err
+	}
+	w.manifest.Maps = append(w.manifest.Maps, info)
+	return nil
+}
+
+// AddFile adds a file to the snapshot under its base name.
+func (w *Writer) AddFile(name string, data []byte) error {
+	name = path.Base(name)
+	if err := w.writeFile(path.Join(filesDir, name), data); err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, name)
+	return nil
+}
+
+// Close writes the manifest and completes the snapshot.
+func (w *Writer) Close() error {
+	manifest, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := w.writeFile(manifestName, manifest); err != nil {
+		return err
+	}
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// Snapshot is a snapshot read back from an archive.
+type Snapshot struct {
+	Manifest
+
+	maps  map[string]*Map
+	files map[string][]byte
+}
+
+// Open reads the snapshot from the given file.
+func Open(filename string) (*Snapshot, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read reads a snapshot.
+func Read(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	contents := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from snapshot: %w", hdr.Name, err)
+		}
+		contents[hdr.Name] = data
+	}
+
+	manifest, ok := contents[manifestName]
+	if !ok {
+		return nil, fmt.Errorf("not a snapshot: no %s", manifestName)
+	}
+	s := &Snapshot{
+		maps:  map[string]*Map{},
+		files: map[string][]byte{},
+	}
+	if err := json.Unmarshal(manifest, &s.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestName, err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", s.Version, Version)
+	}
+
+	for _, info := range s.Maps {
+		m, err := newMap(info, contents[path.Join(mapsDir, info.Name)])
+		if err != nil {
+			return nil, err
+		}
+		s.maps[info.Name] = m
+	}
+	for _, name := range s.Files {
+		s.files[name] = contents[path.Join(filesDir, name)]
+	}
+
+	return s, nil
+}
+
+// Map returns the map with the given versioned name, nil if the snapshot does not have it.
+func (s *Snapshot) Map(name string) *Map {
+	return s.maps[name]
+}
+
+// File returns the file with the base name of the given name.
+func (s *Snapshot) File(name string) ([]byte, bool) {
+	data, ok := s.files[path.Base(name)]
+	return data, ok
+}
+
+// Map is a read-only map of a snapshot.  It implements maps.Map so that it can be used
+// instead of the pinned map.
+type Map struct {
+	MapInfo
+
+	keys   [][]byte
+	values [][]byte
+	index  map[string]int
+}
+
+var _ maps.MapWithExistsCheck = (*Map)(nil)
+
+func newMap(info MapInfo, data []byte) (*Map, error) {
+	entrySize := info.KeySize + info.ValueSize
+	if len(data) != info.Entries*entrySize {
+		return nil, fmt.Errorf("map %s has %d bytes of entries, expected %d",
+			info.Name, len(data), info.Entries*entrySize)
+	}
+
+	m := &Map{
+		MapInfo: info,
+		index:   make(map[string]int, info.Entries),
+	}
+	for i := 0; i < info.Entries; i++ {
+		entry := data[i*entrySize : (i+1)*entrySize]
+		m.keys = append(m.keys, entry[:info.KeySize])
+		m.values = append(m.values, entry[info.KeySize:])
+		m.index[string(entry[:info.KeySize])] = i
+	}
+
+	return m, nil
+}
+
+func (m *Map) GetName() string {
+	return m.Name
+}
+
+func (m *Map) EnsureExists() error {
+	return ErrReadOnly
+}
+
+func (m *Map) Open() error {
+	return nil
+}
+
+func (m *Map) Close() error {
+	return nil
+}
+
+func (m *Map) MapFD() maps.FD {
+	return 0
+}
+
+func (m *Map) Path() string {
+	return "snapshot:" + m.Name
+}
+
+func (m *Map) CopyDeltaFromOldMap() error {
+	return ErrReadOnly
+}
+
+func (m *Map) Iter(f maps.IterCallback) error {
+	for i := range m.keys {
+		if f(m.keys[i], m.values[i]) == maps.IterDelete {
+			return ErrReadOnly
+		}
+	}
+	return nil
+}
+
+func (m *Map) Update(k, v []byte) error {
+	return ErrReadOnly
+}
+
+func (m *Map) Get(k []byte) ([]byte, error) {
+	i, ok := m.index[string(k)]
+	if !ok {
+		return nil, unix.ENOENT
+	}
+	return m.values[i], nil
+}
+
+func (m *Map) Delete(k []byte) error {
+	return ErrReadOnly
+}
+
+func (m *Map) ErrIsNotExists(err error) bool {
+	return maps.IsNotExists(err)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+)
+
+func TestWriteRead(t *testing.T) {
+	RegisterTestingT(t)
+
+	m := mock.NewMockMap(maps.MapParameters{
+		Type:      "hash",
+		KeySize:   2,
+		ValueSize: 3,
+		Name:      "cali_test",
+		Version:   2,
+	})
+	Expect(m.Update([]byte{1, 2}, []byte{3, 4, 5})).To(Succeed())
+	Expect(m.Update([]byte{6, 7}, []byte{8, 9, 10})).To(Succeed())
+	empty := mock.NewMockMap(maps.MapParameters{Type: "hash", KeySize: 4, ValueSize: 4, Name: "cali_empty"})
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "node1", 4, 12345)
+	Expect(w.AddMap(m, "hash")).To(Succeed())
+	Expect(w.AddMap(empty, "hash")).To(Succeed())
+	Expect(w.AddFile("/var/run/calico/bpf/policy/eth0_ingress_v4.json", []byte("{}"))).To(Succeed())
+	Expect(w.Close()).To(Succeed())
+
+	s, err := Read(&buf)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(s.Version).To(Equal(Version))
+	Expect(s.Hostname).To(Equal("node1"))
+	Expect(s.NumPossibleCPUs).To(Equal(4))
+	Expect(s.KTimeNanos).To(Equal(int64(12345)))
+	Expect(s.Maps).To(HaveLen(2))
+
+	sm := s.Map(m.GetName())
+	Expect(sm).NotTo(BeNil())
+	Expect(sm.Entries).To(Equal(2))
+	contents := map[string]string{}
+	Expect(sm.Iter(func(k, v []byte) maps.IteratorAction {
+		contents[string(k)] = string(v)
+		return maps.IterNone
+	})).To(Succeed())
+	Expect(contents).To(Equal(m.Contents))
+
+	v, err := sm.Get([]byte{6, 7})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(v).To(Equal([]byte{8, 9, 10}))
+	_, err = sm.Get([]byte{0, 0})
+	Expect(sm.ErrIsNotExists(err)).To(BeTrue())
+
+	Expect(sm.Update([]byte{0, 0}, []byte{0, 0, 0})).To(Equal(ErrReadOnly))
+	Expect(sm.Delete([]byte{1, 2})).To(Equal(ErrReadOnly))
+	Expect(sm.Iter(func(k, v []byte) maps.IteratorAction {
+		return maps.IterDelete
+	})).To(Equal(ErrReadOnly))
+
+	Expect(s.Map(empty.GetName()).Entries).To(BeZero())
+	Expect(s.Map("cali_missing")).To(BeNil())
+
+	data, ok := s.File("eth0_ingress_v4.json")
+	Expect(ok).To(BeTrue())
+	Expect(data).To(Equal([]byte("{}")))
+}
+
+func TestReadErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := Read(bytes.NewReader([]byte("not a snapshot")))
+	Expect(err).To(HaveOccurred())
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "node1", 1, 0)
+	w.manifest.Version = Version + 1
+	Expect(w.Close()).To(Succeed())
+	_, err = Read(&buf)
+	Expect(err).To(MatchError(ContainSubstring("unsupported snapshot version")))
+}
@@ -15,6 +15,8 @@
 package calc
 
 import (
+	"strings"
+
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -23,10 +25,12 @@ import (
 	"github.com/projectcalico/calico/felix/dispatcher"
 	"github.com/projectcalico/calico/felix/labelindex"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
 	"github.com/projectcalico/calico/felix/serviceindex"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
 )
 
 var gaugeNumActiveSelectors = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -340,6 +344,30 @@ func NewCalculationGraph(callbacks PipelineCallbacks, conf *config.Config, liveC
 	}
 	cg.ipsetMemberIndex = ipsetMemberIndex
 
+	// The XDP programs of the host endpoints drop the traffic from the nets of the
+	// GlobalNetworkSets that the DDoS blocklist selector matches.  The blocklist gets its own
+	// index, which only sees the GlobalNetworkSets, so that a selector that also matches
+	// endpoints or namespaced NetworkSets cannot block their IPs.
+	if conf.BPFEnabled && conf.BPFXDPDDoSProtection == "Enabled" && conf.BPFXDPBlocklistSelector != "" {
+		if sel, err := selector.Parse(conf.BPFXDPBlocklistSelector); err != nil {
+			log.WithError(err).WithField("selector", conf.BPFXDPBlocklistSelector).Error(
+				"Invalid BPFXDPBlocklistSelector, ignoring it.")
+		} else {
+			blocklistIndex := labelindex.NewSelectorAndNamedPortIndex(false)
+			allUpdDispatcher.Register(model.NetworkSetKey{}, func(update api.Update) (filterOut bool) {
+				if strings.Contains(update.Key.(model.NetworkSetKey).Name, "/") {
+					// A namespaced NetworkSet, whose name is prefixed with its namespace.
+					return
+				}
+				return blocklistIndex.OnUpdate(update)
+			})
+			blocklistIndex.OnMemberAdded = callbacks.OnIPSetMemberAdded
+			blocklistIndex.OnMemberRemoved = callbacks.OnIPSetMemberRemoved
+			callbacks.OnIPSetAdded(rules.IPSetIDXDPBlocklist, proto.IPSetUpdate_NET)
+			blocklistIndex.UpdateIPSet(rules.IPSetIDXDPBlocklist, sel, labelindex.ProtocolNone, "")
+		}
+	}
+
 	// The endpoint policy resolver marries up the active policies with local endpoints and
 	// calculates the complete, ordered set of policies that apply to each endpoint.
 	//
@@ -31,6 +31,7 @@ import (
 	"github.com/projectcalico/calico/felix/dataplane/mock"
 	"github.com/projectcalico/calico/felix/dispatcher"
 	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/net"
@@ -205,6 +206,80 @@ var _ = DescribeTable("Calculation graph pass-through tests",
 	),
 )
 
+var _ = Describe("XDP DDoS blocklist", func() {
+	It("should only contain the nets of the selected GlobalNetworkSets", func() {
+		eb := NewEventSequencer(nil)
+		members := map[string]bool{}
+		eb.Callback = func(message interface{}) {
+			switch m := message.(type) {
+			case *proto.IPSetUpdate:
+				if m.Id == rules.IPSetIDXDPBlocklist {
+					Expect(m.Type).To(Equal(proto.IPSetUpdate_NET))
+					for _, member := range m.Members {
+						members[member] = true
+					}
+				}
+			case *proto.IPSetDeltaUpdate:
+				if m.Id == rules.IPSetIDXDPBlocklist {
+					for _, member := range m.RemovedMembers {
+						delete(members, member)
+					}
+					for _, member := range m.AddedMembers {
+						members[member] = true
+					}
+				}
+			}
+		}
+		conf := config.New()
+		conf.FelixHostname = "hostname"
+		conf.BPFEnabled = true
+		conf.BPFXDPDDoSProtection = "Enabled"
+		conf.BPFXDPBlocklistSelector = "ddos-blocklist == 'true'"
+		cg := NewCalculationGraph(eb, conf, func() {}).AllUpdDispatcher
+
+		labels := map[string]string{"ddos-blocklist": "true"}
+		update := func(key model.Key, value interface{}) {
+			cg.OnUpdate(api.Update{
+				UpdateType: api.UpdateTypeKVNew,
+				KVPair:     model.KVPair{Key: key, Value: value},
+			})
+		}
+		update(model.NetworkSetKey{Name: "blocked"}, &model.NetworkSet{
+			Nets:   []net.IPNet{mustParseNet("10.1.0.0/16")},
+			Labels: labels,
+		})
+		update(model.NetworkSetKey{Name: "other"}, &model.NetworkSet{
+			Nets: []net.IPNet{mustParseNet("10.2.0.0/16")},
+		})
+		update(model.NetworkSetKey{Name: "ns/blocked"}, &model.NetworkSet{
+			Nets:   []net.IPNet{mustParseNet("10.3.0.0/16")},
+			Labels: labels,
+		})
+		update(model.WorkloadEndpointKey{
+			Hostname:       "remote",
+			OrchestratorID: "k8s",
+			WorkloadID:     "ns/pod",
+			EndpointID:     "eth0",
+		}, &model.WorkloadEndpoint{
+			IPv4Nets: []net.IPNet{mustParseNet("10.4.0.1/32")},
+			Labels:   labels,
+		})
+		update(model.HostEndpointKey{Hostname: "remote", EndpointID: "eth0"}, &model.HostEndpoint{
+			ExpectedIPv4Addrs: []net.IP{mustParseIP("10.5.0.1")},
+			Labels:            labels,
+		})
+		eb.Flush()
+		Expect(members).To(Equal(map[string]bool{"10.1.0.0/16": true}))
+
+		cg.OnUpdate(api.Update{
+			UpdateType: api.UpdateTypeKVDeleted,
+			KVPair:     model.KVPair{Key: model.NetworkSetKey{Name: "blocked"}},
+		})
+		eb.Flush()
+		Expect(members).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Host IP duplicate squashing test", func() {
 	var eb *EventSequencer
 	var messagesReceived []interface{}
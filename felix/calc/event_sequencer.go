@@ -394,6 +394,11 @@ func ModelWorkloadEndpointToProto(ep *model.WorkloadEndpoint, tiers []*proto.Tie
 	if ep.Mac != nil {
 		mac = ep.Mac.String()
 	}
+	var ingressBandwidth, egressBandwidth int64
+	if ep.QoSControls != nil {
+		ingressBandwidth = ep.QoSControls.IngressBandwidth
+		egressBandwidth = ep.QoSControls.EgressBandwidth
+	}
 	return &proto.WorkloadEndpoint{
 		State:                      ep.State,
 		Name:                       ep.Name,
@@ -406,6 +411,8 @@ func ModelWorkloadEndpointToProto(ep *model.WorkloadEndpoint, tiers []*proto.Tie
 		Ipv6Nat:                    natsToProtoNatInfo(ep.IPv6NAT),
 		AllowSpoofedSourcePrefixes: netsToStrings(ep.AllowSpoofedSourcePrefixes),
 		Annotations:                ep.Annotations,
+		IngressBandwidth:           ingressBandwidth,
+		EgressBandwidth:            egressBandwidth,
 	}
 }
 
@@ -79,6 +79,22 @@ var _ = DescribeTable("ModelWorkloadEndpointToProto",
 		Ipv6Nat:                    []*proto.NatInfo{},
 		AllowSpoofedSourcePrefixes: []string{"8.8.8.8/32"},
 	}),
+	Entry("workload endpoint with bandwidth limits", model.WorkloadEndpoint{
+		State:       "up",
+		Name:        "bill",
+		QoSControls: &model.QoSControls{IngressBandwidth: 1000000, EgressBandwidth: 2000000},
+	}, proto.WorkloadEndpoint{
+		State:                      "up",
+		Name:                       "bill",
+		Ipv4Nets:                   []string{},
+		Ipv6Nets:                   []string{},
+		Tiers:                      []*proto.TierInfo{},
+		Ipv4Nat:                    []*proto.NatInfo{},
+		Ipv6Nat:                    []*proto.NatInfo{},
+		AllowSpoofedSourcePrefixes: []string{},
+		IngressBandwidth:           1000000,
+		EgressBandwidth:            2000000,
+	}),
 )
 
 var _ = Describe("ParsedRulesToActivePolicyUpdate", func() {
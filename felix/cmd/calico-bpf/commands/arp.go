@@ -64,9 +64,9 @@ func dumpARP() error {
 
 	if ipv6 != nil && *ipv6 {
 		v6 = true
-		arpMap = arp.MapV6()
+		arpMap = dumpMap(arp.MapV6())
 	} else {
-		arpMap = arp.Map()
+		arpMap = dumpMap(arp.Map())
 	}
 
 	if err := arpMap.Open(); err != nil {
@@ -16,23 +16,28 @@ package commands
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docopt/docopt-go"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/sys/unix"
 
-	"github.com/projectcalico/calico/felix/bpf"
 	"github.com/projectcalico/calico/felix/bpf/conntrack"
 	v2 "github.com/projectcalico/calico/felix/bpf/conntrack/v2"
 	v3 "github.com/projectcalico/calico/felix/bpf/conntrack/v3"
 	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 )
 
 func init() {
@@ -65,6 +70,21 @@ type conntrackDumpCmd struct {
 	version int
 	raw     bool
 	ipv6    bool
+
+	verStr   string
+	ips      []string
+	port     uint16
+	proto    string
+	natOnly  bool
+	state    string
+	minAge   time.Duration
+	maxAge   time.Duration
+	workload string
+	ifPrefix []string
+	output   string
+	summary  bool
+
+	filter conntrack.Filter
 }
 
 func newConntrackDumpCmd() *cobra.Command {
@@ -75,45 +95,244 @@ func newConntrackDumpCmd() *cobra.Command {
 		},
 	}
 
-	var version string
-
-	cmd.Command.Flags().StringVarP((&version), "ver", "v", "", "version to dump from")
-	cmd.Command.Flags().BoolVar((&cmd.raw), "raw", false, "dump the raw conntrack table as is. For version < 3 it is always raw")
+	flags := cmd.Command.Flags()
+	flags.StringVarP(&cmd.verStr, "ver", "v", "", "version to dump from")
+	flags.BoolVar(&cmd.raw, "raw", false, "dump the raw conntrack table as is. For version < 3 it is always raw")
+	flags.StringSliceVar(&cmd.ips, "ip", nil, "only dump entries with an address, or NAT original address, in one of these IPs or CIDRs")
+	flags.Uint16Var(&cmd.port, "port", 0, "only dump entries with a port, or NAT original port, equal to this one")
+	flags.StringVar(&cmd.proto, "proto", "", "only dump entries of this protocol, tcp, udp, icmp, icmp6 or a number")
+	flags.BoolVar(&cmd.natOnly, "nat-only", false, "only dump NAT entries")
+	flags.StringVar(&cmd.state, "state", "", "only dump TCP entries in this state, syn-sent, established or closed")
+	flags.DurationVar(&cmd.minAge, "min-age", 0, "only dump entries created at least this long ago")
+	flags.DurationVar(&cmd.maxAge, "max-age", 0, "only dump entries created at most this long ago")
+	flags.StringVar(&cmd.workload, "workload", "", "only dump entries of the local workload <namespace>/<pod>")
+	flags.StringSliceVar(&cmd.ifPrefix, "interface-prefix", defaultInterfacePrefixes(),
+		"the prefixes of the workload interfaces, as in Felix's InterfacePrefix, used by --workload")
+	flags.StringVarP(&cmd.output, "output", "o", "text", "output format, text or json")
+	flags.BoolVar(&cmd.summary, "summary", false, "only print the counts of the matching entries by type, protocol, state and address")
+	addFromSnapshotFlag(cmd.Command)
 	cmd.Command.Args = cmd.Args
 	cmd.Command.Run = cmd.Run
 
-	if version != "" {
-		v, err := strconv.Atoi(version)
+	return cmd.Command
+}
+
+func (cmd *conntrackDumpCmd) Args(c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %v", args)
+	}
+	return cmd.parseFlags()
+}
+
+func (cmd *conntrackDumpCmd) parseFlags() error {
+	if cmd.verStr != "" {
+		v, err := strconv.Atoi(cmd.verStr)
 		if err != nil {
-			cmd.PrintErr("--ver needs to be a number")
-			os.Exit(-1)
+			return fmt.Errorf("--ver needs to be a number")
 		}
 		cmd.version = v
 	}
 
-	return cmd.Command
-}
+	switch cmd.output {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unknown output format %q, expected text or json", cmd.output)
+	}
 
-func dumpCtMapV2(ctMap maps.Map) error {
-	err := ctMap.Iter(func(k, v []byte) maps.IteratorAction {
-		var ctKey v2.Key
-		if len(k) != len(ctKey) {
-			log.Panic("Key has unexpected length")
+	if len(cmd.ips) > 0 && cmd.workload != "" {
+		return fmt.Errorf("--ip and --workload are mutually exclusive")
+	}
+	if cmd.workload != "" && len(strings.Split(cmd.workload, "/")) != 2 {
+		return fmt.Errorf("--workload needs to be <namespace>/<pod>")
+	}
+
+	cmd.filter = conntrack.Filter{
+		Port:    cmd.port,
+		NATOnly: cmd.natOnly,
+		MinAge:  cmd.minAge,
+		MaxAge:  cmd.maxAge,
+	}
+
+	for _, s := range cmd.ips {
+		cidr, err := ip.ParseCIDROrIP(s)
+		if err != nil {
+			return fmt.Errorf("--ip: %q is not an IP or CIDR", s)
 		}
-		copy(ctKey[:], k[:])
+		cmd.filter.CIDRs = append(cmd.filter.CIDRs, cidr)
+	}
 
-		var ctVal v2.Value
-		if len(v) != len(ctVal) {
-			log.Panic("Value has unexpected length")
+	if cmd.proto != "" {
+		proto, err := parseProto(cmd.proto)
+		if err != nil {
+			return err
 		}
-		copy(ctVal[:], v[:])
+		cmd.filter.Proto = proto
+	}
 
-		fmt.Printf("%v -> %v", ctKey, ctVal)
-		dumpExtrav2(ctKey, ctVal)
-		fmt.Printf("\n")
+	if cmd.state != "" {
+		state, err := conntrack.ParseState(cmd.state)
+		if err != nil {
+			return err
+		}
+		cmd.filter.State = state
+	}
+
+	return nil
+}
+
+func parseProto(s string) (uint8, error) {
+	switch strings.ToLower(s) {
+	case "tcp":
+		return 6, nil
+	case "udp":
+		return 17, nil
+	case "icmp":
+		return 1, nil
+	case "icmp6", "icmpv6":
+		return 58, nil
+	}
+
+	p, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown protocol %s", s)
+	}
+	return uint8(p), nil
+}
+
+// workloadCIDRs returns the CIDRs of the local workload routes through the interface.
+func workloadCIDRs(routesMap maps.Map, ifindex int, v6 bool) ([]ip.CIDR, error) {
+	var cidrs []ip.CIDR
+
+	err := routesMap.Iter(func(k, v []byte) maps.IteratorAction {
+		var (
+			key   routes.KeyInterface
+			value routes.ValueInterface
+		)
+
+		if v6 {
+			key = routes.KeyV6InftFromBytes(k)
+			value = routes.ValueV6InftFromBytes(v)
+		} else {
+			key = routes.KeyInftFromBytes(k)
+			value = routes.ValueInftFromBytes(v)
+		}
+
+		if value.Flags()&routes.FlagsLocalWorkload == routes.FlagsLocalWorkload &&
+			int(value.IfaceIndex()) == ifindex {
+			cidrs = append(cidrs, key.Dest())
+		}
 		return maps.IterNone
 	})
-	return err
+
+	return cidrs, err
+}
+
+// defaultInterfacePrefixes returns the workload interface prefixes from the environment of
+// Felix, if set, or the default one.
+func defaultInterfacePrefixes() []string {
+	if p := os.Getenv("FELIX_INTERFACEPREFIX"); p != "" {
+		return strings.Split(p, ",")
+	}
+	return []string{"cali"}
+}
+
+// workloadIfaceNames returns the possible names of the interface of the workload, one per
+// prefix.
+func workloadIfaceNames(namespace, pod string, prefixes []string) []string {
+	// The name is the first prefix followed by a hash of the workload, the hash is the
+	// same whatever the prefix.
+	name := conversion.NewConverter().VethNameForWorkload(namespace, pod)
+	hash := name[len(name)-11:]
+
+	var names []string
+	for _, p := range prefixes {
+		names = append(names, p+hash)
+	}
+	return names
+}
+
+func (cmd *conntrackDumpCmd) resolveWorkload() error {
+	parts := strings.Split(cmd.workload, "/")
+
+	var (
+		iface     *net.Interface
+		ifaceName string
+		err       error
+	)
+	for _, ifaceName = range workloadIfaceNames(parts[0], parts[1], cmd.ifPrefix) {
+		if iface, err = dumpInterfaceByName(ifaceName); err == nil {
+			break
+		}
+	}
+	if iface == nil {
+		return errors.WithMessagef(err, "failed to find the interface of workload %s", cmd.workload)
+	}
+
+	routesMap := dumpMap(routes.Map())
+	if cmd.ipv6 {
+		routesMap = dumpMap(routes.MapV6())
+	}
+	if err := routesMap.Open(); err != nil {
+		return errors.WithMessage(err, "failed to open routes map")
+	}
+
+	cidrs, err := workloadCIDRs(routesMap, iface.Index, cmd.ipv6)
+	if err != nil {
+		return errors.WithMessage(err, "failed to iterate over routes")
+	}
+	if len(cidrs) == 0 {
+		return fmt.Errorf("no routes to workload %s through %s", cmd.workload, ifaceName)
+	}
+
+	cmd.filter.CIDRs = cidrs
+	return nil
+}
+
+// conntrackEntry is the JSON representation of a conntrack entry.
+type conntrackEntry struct {
+	Proto     string `json:"proto"`
+	Type      string `json:"type"`
+	AddrA     net.IP `json:"addrA"`
+	PortA     uint16 `json:"portA"`
+	AddrB     net.IP `json:"addrB"`
+	PortB     uint16 `json:"portB"`
+	Flags     uint16 `json:"flags"`
+	State     string `json:"state,omitempty"`
+	Age       string `json:"age"`
+	ActiveAgo string `json:"activeAgo"`
+	OrigDst   net.IP `json:"origDst,omitempty"`
+	OrigPort  uint16 `json:"origPort,omitempty"`
+	OrigSPort uint16 `json:"origSPort,omitempty"`
+	TunnelIP  net.IP `json:"tunnelIP,omitempty"`
+}
+
+func newConntrackEntry(k conntrack.KeyInterface, v conntrack.ValueInterface, now int64) conntrackEntry {
+	e := conntrackEntry{
+		Proto:     protoStr(k.Proto()),
+		Type:      conntrack.TypeString(v.Type()),
+		AddrA:     k.AddrA(),
+		PortA:     k.PortA(),
+		AddrB:     k.AddrB(),
+		PortB:     k.PortB(),
+		Flags:     v.Flags(),
+		State:     conntrack.EntryState(k, v),
+		Age:       time.Duration(now - v.Created()).String(),
+		ActiveAgo: time.Duration(now - v.LastSeen()).String(),
+	}
+
+	if v.Type() == conntrack.TypeNATReverse {
+		d := v.Data()
+		e.OrigDst = d.OrigDst
+		e.OrigPort = d.OrigPort
+		if !d.TunIP.IsUnspecified() {
+			e.TunnelIP = d.TunIP
+		}
+	}
+	if v.Flags()&v3.FlagHostPSNAT != 0 {
+		e.OrigSPort = v.Data().OrigSPort
+	}
+
+	return e
 }
 
 func (cmd *conntrackDumpCmd) Run(c *cobra.Command, _ []string) {
@@ -126,23 +345,26 @@ func (cmd *conntrackDumpCmd) Run(c *cobra.Command, _ []string) {
 
 	switch cmd.version {
 	case 2:
-		ctMap = conntrack.MapV2()
+		ctMap = dumpMap(conntrack.MapV2())
 	default:
 		if cmd.ipv6 {
-			ctMap = conntrack.MapV6()
+			ctMap = dumpMap(conntrack.MapV6())
 		} else {
-			ctMap = conntrack.Map()
+			ctMap = dumpMap(conntrack.Map())
 		}
 	}
 	if err := ctMap.Open(); err != nil {
 		log.WithError(err).Fatal("Failed to access ConntrackMap")
 	}
-	if cmd.version == 2 {
-		err := dumpCtMapV2(ctMap)
-		if err != nil {
-			log.WithError(err).Fatal("Failed to iterate over conntrack entries")
+
+	if (cmd.minAge != 0 || cmd.maxAge != 0) && dumpKTimeNanos() == 0 {
+		log.Fatal("The snapshot does not record its time, --min-age and --max-age cannot be used with it")
+	}
+
+	if cmd.workload != "" {
+		if err := cmd.resolveWorkload(); err != nil {
+			log.WithError(err).Fatal("Failed to resolve the workload")
 		}
-		return
 	}
 
 	keyFromBytes := conntrack.KeyFromBytes
@@ -152,15 +374,54 @@ func (cmd *conntrackDumpCmd) Run(c *cobra.Command, _ []string) {
 		valFromBytes = conntrack.ValueV6FromBytes
 	}
 
+	now := dumpKTimeNanos()
+	summary := conntrack.NewSummary()
+	entries := []conntrackEntry{}
+
 	err := ctMap.Iter(func(k, v []byte) maps.IteratorAction {
-		ctKey := keyFromBytes(k)
-		ctVal := valFromBytes(v)
+		var (
+			ctKey conntrack.KeyInterface
+			ctVal conntrack.ValueInterface
+
+			ctKeyV2 v2.Key
+			ctValV2 v2.Value
+		)
+
+		if cmd.version == 2 {
+			if len(k) != len(ctKeyV2) {
+				log.Panic("Key has unexpected length")
+			}
+			copy(ctKeyV2[:], k[:])
 
-		if cmd.raw {
+			if len(v) != len(ctValV2) {
+				log.Panic("Value has unexpected length")
+			}
+			copy(ctValV2[:], v[:])
+
+			ctKey, ctVal = conntrack.UpgradeV2(ctKeyV2, ctValV2)
+		} else {
+			ctKey = keyFromBytes(k)
+			ctVal = valFromBytes(v)
+		}
+
+		if !cmd.filter.Match(ctKey, ctVal, now) {
+			return maps.IterNone
+		}
+
+		switch {
+		case cmd.summary:
+			summary.Add(ctKey, ctVal)
+		case cmd.output == "json":
+			entries = append(entries, newConntrackEntry(ctKey, ctVal, now))
+		case cmd.version == 2:
+			fmt.Printf("%v -> %v", ctKeyV2, ctValV2)
+			dumpExtrav2(ctKeyV2, ctValV2)
+			fmt.Printf("\n")
+		case cmd.raw:
 			fmt.Printf("%v -> %v", ctKey, ctVal)
 			dumpExtra(ctKey, ctVal)
 			fmt.Printf("\n")
-		} else {
+		default:
 			cmd.prettyDump(ctKey, ctVal)
 		}
 		return maps.IterNone
@@ -168,6 +429,58 @@ func (cmd *conntrackDumpCmd) Run(c *cobra.Command, _ []string) {
 	if err != nil {
 		log.WithError(err).Fatal("Failed to iterate over conntrack entries")
 	}
+
+	switch {
+	case cmd.summary && cmd.output == "json":
+		printJSON(summary)
+	case cmd.summary:
+		printSummary(summary)
+	case cmd.output == "json":
+		printJSON(entries)
+	}
+}
+
+func printJSON(v interface{}) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.WithError(err).Fatal("Failed to marshal to JSON")
+	}
+	fmt.Println(string(out))
+}
+
+func printSummary(s *conntrack.Summary) {
+	fmt.Printf("Total: %d\n", s.Total)
+
+	printCounts := func(title string, counts map[string]int) {
+		if len(counts) == 0 {
+			return
+		}
+		fmt.Printf("%s:\n", title)
+		keys := make([]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		// Largest counts first so that the busiest endpoints stand out.
+		sort.Slice(keys, func(i, j int) bool {
+			if counts[keys[i]] != counts[keys[j]] {
+				return counts[keys[i]] > counts[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		for _, k := range keys {
+			fmt.Printf("  %-40s %d\n", k, counts[k])
+		}
+	}
+
+	byProto := map[string]int{}
+	for p, n := range s.ByProto {
+		byProto[protoStr(p)] += n
+	}
+
+	printCounts("By type", s.ByType)
+	printCounts("By protocol", byProto)
+	printCounts("By state", s.ByState)
+	printCounts("By address", s.ByAddr)
 }
 
 func protoStr(proto uint8) string {
@@ -215,25 +528,19 @@ func (cmd *conntrackDumpCmd) prettyDump(k conntrack.KeyInterface, v conntrack.Va
 		cmd.Printf("source port changed from %d ", d.OrigSPort)
 	}
 
-	now := bpf.KTimeNanos()
+	now := dumpKTimeNanos()
 	cmd.Printf(" Age: %s Active ago %s",
 		time.Duration(now-v.Created()), time.Duration(now-v.LastSeen()))
 
-	if k.Proto() == 6 {
-		if (v.IsForwardDSR() && d.FINsSeenDSR()) || d.FINsSeen() || d.RSTSeen() {
-			cmd.Printf(" CLOSED")
-		} else if d.Established() {
-			cmd.Printf(" ESTABLISHED")
-		} else {
-			cmd.Printf(" SYN-SENT")
-		}
+	if state := conntrack.EntryState(k, v); state != "" {
+		cmd.Printf(" %s", state)
 	}
 
 	cmd.Printf("\n")
 }
 
 func dumpExtrav2(k v2.Key, v v2.Value) {
-	now := bpf.KTimeNanos()
+	now := dumpKTimeNanos()
 
 	fmt.Printf(" Age: %s Active ago %s",
 		time.Duration(now-v.Created()), time.Duration(now-v.LastSeen()))
@@ -262,7 +569,7 @@ func dumpExtrav2(k v2.Key, v v2.Value) {
 }
 
 func dumpExtra(k conntrack.KeyInterface, v conntrack.ValueInterface) {
-	now := bpf.KTimeNanos()
+	now := dumpKTimeNanos()
 
 	fmt.Printf(" Age: %s Active ago %s",
 		time.Duration(now-v.Created()), time.Duration(now-v.LastSeen()))
@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+)
+
+func TestConntrackDumpFlags(t *testing.T) {
+	RegisterTestingT(t)
+
+	cmd := &conntrackDumpCmd{
+		verStr: "2",
+		ips:    []string{"10.0.0.1", "10.1.0.0/16"},
+		port:   80,
+		proto:  "TCP",
+		state:  "established",
+		minAge: time.Minute,
+		output: "json",
+	}
+	Expect(cmd.parseFlags()).To(Succeed())
+	Expect(cmd.version).To(Equal(2))
+	Expect(cmd.filter).To(Equal(conntrack.Filter{
+		CIDRs: []ip.CIDR{
+			ip.MustParseCIDROrIP("10.0.0.1/32"),
+			ip.MustParseCIDROrIP("10.1.0.0/16"),
+		},
+		Port:   80,
+		Proto:  6,
+		State:  conntrack.StateEstablished,
+		MinAge: time.Minute,
+	}))
+
+	for _, cmd := range []*conntrackDumpCmd{
+		{verStr: "two", output: "text"},
+		{output: "yaml"},
+		{ips: []string{"10.0.0.300"}, output: "text"},
+		{proto: "sctp", output: "text"},
+		{state: "listening", output: "text"},
+		{workload: "pod", output: "text"},
+		{workload: "ns/pod", ips: []string{"10.0.0.1"}, output: "text"},
+	} {
+		Expect(cmd.parseFlags()).NotTo(Succeed(), "%+v", cmd)
+	}
+}
+
+func TestWorkloadCIDRs(t *testing.T) {
+	RegisterTestingT(t)
+
+	m := mock.NewMockMap(routes.MapParameters)
+	for _, r := range []struct {
+		cidr  string
+		value routes.Value
+	}{
+		{"10.65.0.2/32", routes.NewValueWithIfIndex(routes.FlagsLocalWorkload, 5)},
+		{"10.65.0.3/32", routes.NewValueWithIfIndex(routes.FlagsLocalWorkload, 6)},
+		{"10.65.1.0/26", routes.NewValueWithNextHop(routes.FlagsRemoteWorkload, ip.FromString("172.17.0.7"))},
+		{"172.17.0.6/32", routes.NewValue(routes.FlagsLocalHost)},
+	} {
+		Expect(m.Update(routes.NewKey(ip.MustParseCIDROrIP(r.cidr)).AsBytes(), r.value.AsBytes())).To(Succeed())
+	}
+
+	cidrs, err := workloadCIDRs(m, 5, false)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(cidrs).To(ConsistOf(ip.MustParseCIDROrIP("10.65.0.2/32")))
+}
+
+func TestWorkloadIfaceNames(t *testing.T) {
+	RegisterTestingT(t)
+	t.Setenv("FELIX_INTERFACEPREFIX", "")
+
+	names := workloadIfaceNames("default", "nginx", []string{"cali", "tap"})
+	Expect(names).To(HaveLen(2))
+	Expect(names[0]).To(Equal(conversion.NewConverter().VethNameForWorkload("default", "nginx")))
+	Expect(names[0]).To(HavePrefix("cali"))
+	Expect(names[1]).To(Equal("tap" + strings.TrimPrefix(names[0], "cali")))
+}
@@ -48,7 +48,7 @@ var countersDumpCmd = &cobra.Command{
 	Short: "dumps counters",
 	Run: func(cmd *cobra.Command, args []string) {
 		iface := parseFlags(cmd)
-		m := counters.Map()
+		m := dumpMap(counters.Map())
 		if err := m.Open(); err != nil {
 			log.WithError(err).Error("Failed to open counter map.")
 			return
@@ -58,7 +58,7 @@ var countersDumpCmd = &cobra.Command{
 		if iface == "" {
 			doForAllInterfaces("dump", dumpInterface)
 		} else {
-			i, err := net.InterfaceByName(iface)
+			i, err := dumpInterfaceByName(iface)
 			if err != nil {
 				log.WithError(err).Errorf("No such interface: %s", iface)
 				return
@@ -106,13 +106,13 @@ func parseFlags(cmd *cobra.Command) string {
 }
 
 func doForAllInterfaces(action string, fn func(maps.Map, *net.Interface) error) {
-	interfaces, err := net.Interfaces()
+	interfaces, err := dumpInterfaces()
 	if err != nil {
 		log.WithError(err).Error("failed to get list of interfaces.")
 		return
 	}
 
-	m := counters.Map()
+	m := dumpMap(counters.Map())
 	if err := m.Open(); err != nil {
 		log.WithError(err).Error("Failed to open counter map.")
 		return
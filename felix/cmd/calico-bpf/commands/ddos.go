@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/projectcalico/calico/felix/bpf/ddos"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+)
+
+func init() {
+	ddosCmd.AddCommand(ddosDumpCmd)
+	rootCmd.AddCommand(ddosCmd)
+}
+
+var ddosDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "dumps the XDP DDoS protection configuration, blocklist and rate limits",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := dumpDDoS(); err != nil {
+			log.WithError(err).Error("Failed to dump the XDP DDoS protection maps.")
+		}
+	},
+}
+
+// ddosCmd represents the ddos command
+var ddosCmd = &cobra.Command{
+	Use:   "ddos",
+	Short: "Manipulates the XDP DDoS protection",
+}
+
+func dumpDDoS() error {
+	cfgMap := dumpMap(ddos.ConfigMap())
+	if err := cfgMap.Open(); err != nil {
+		return errors.WithMessage(err, "failed to open config map")
+	}
+	cfg, err := ddos.ReadConfig(cfgMap)
+	if err != nil {
+		return errors.WithMessage(err, "failed to read config")
+	}
+	fmt.Printf("enabled: %t, syn cookies: %t, syn rate: %d/s, udp rate: %d/s, prefix length: /%d (v4) /%d (v6)\n",
+		cfg.Enabled, cfg.SYNCookies, cfg.SYNRate, cfg.UDPRate, cfg.PrefixLenV4, cfg.PrefixLenV6)
+
+	blocklistMap, rateLimitMap := dumpMap(ddos.BlocklistMap()), dumpMap(ddos.RateLimitMap())
+	keyString := func(k []byte) string { return ddos.BlocklistKeyFromBytes(k).String() }
+	if ipv6 != nil && *ipv6 {
+		blocklistMap, rateLimitMap = dumpMap(ddos.BlocklistMapV6()), dumpMap(ddos.RateLimitMapV6())
+		keyString = func(k []byte) string { return ddos.BlocklistKeyV6FromBytes(k).String() }
+	}
+
+	if err := blocklistMap.Open(); err != nil {
+		return errors.WithMessage(err, "failed to open blocklist map")
+	}
+	fmt.Println("blocklist:")
+	err = blocklistMap.Iter(func(k, v []byte) maps.IteratorAction {
+		fmt.Printf("  %s\n", keyString(k))
+		return maps.IterNone
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to iterate over blocklist map")
+	}
+
+	if err := rateLimitMap.Open(); err != nil {
+		return errors.WithMessage(err, "failed to open rate limit map")
+	}
+	fmt.Println("rate limits:")
+	return rateLimitMap.Iter(func(k, v []byte) maps.IteratorAction {
+		val := ddos.RateLimitValueFromBytes(v)
+		fmt.Printf("  %s: syn %d, udp %d\n", net.IP(k), val.SYNs(), val.UDPs())
+		return maps.IterNone
+	})
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/projectcalico/calico/felix/bpf/events"
+	"github.com/projectcalico/calico/felix/bpf/hook"
+)
+
+func init() {
+	eventsCmd.Flags().BoolP("follow", "f", false, "Stream the events as they happen")
+	eventsCmd.Flags().StringSlice("type", nil,
+		"Show only events of the given types: "+strings.Join(events.TypeNames(), ", "))
+	eventsCmd.Flags().String("iface", "", "Show only events on the given interface")
+	eventsCmd.Flags().String("src", "", "Show only events with a source IP in the given CIDR")
+	eventsCmd.Flags().String("dst", "", "Show only events with a destination IP in the given CIDR")
+	eventsCmd.Flags().Uint8("proto", 0, "Show only events with the given IP protocol number")
+	eventsCmd.Flags().Uint16("port", 0, "Show only events with the given source or destination port")
+	eventsCmd.Flags().Bool("json", false, "Print the events as JSON, one per line")
+	rootCmd.AddCommand(eventsCmd)
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events [--follow] [--type <type>,...] [--iface <iface>] [--src <cidr>] [--dst <cidr>] [--proto <proto>] [--port <port>] [--json]",
+	Short: "shows the events of the dataplane, such as policy drops",
+	Long: "Shows the events that the BPF programs emit when they drop a packet because of policy " +
+		"or a failed RPF check, or when a packet is sent to a service without backends. Felix " +
+		"streams the events when BPFEventsSampleRate is not zero. Without --follow, shows the " +
+		"current sample rate.",
+	Run: func(cmd *cobra.Command, args []string) {
+		follow, _ := cmd.Flags().GetBool("follow")
+		if !follow {
+			showEventsConfig(cmd)
+			return
+		}
+
+		f, err := parseEventsFilter(cmd)
+		if err != nil {
+			log.WithError(err).Error("Invalid filter.")
+			return
+		}
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if err := followEvents(cmd, f, asJSON); err != nil {
+			log.WithError(err).Error("Failed to follow events.")
+		}
+	},
+}
+
+func showEventsConfig(cmd *cobra.Command) {
+	m := events.ConfigMap()
+	if err := m.Open(); err != nil {
+		log.WithError(err).Error("Failed to open events config map.")
+		return
+	}
+	defer m.Close()
+
+	rate, err := events.SampleRate(m)
+	if err != nil {
+		log.WithError(err).Error("Failed to read events config map.")
+		return
+	}
+	if rate == 0 {
+		cmd.Println("Events are disabled, set BPFEventsSampleRate to enable them.")
+		return
+	}
+	cmd.Printf("Events sample rate: 1 in %d\n", rate)
+}
+
+type eventsFilter struct {
+	types    map[events.Type]bool
+	iface    string
+	src, dst *net.IPNet
+	proto    uint8
+	port     uint16
+}
+
+func parseEventsFilter(cmd *cobra.Command) (*eventsFilter, error) {
+	var (
+		f   eventsFilter
+		err error
+	)
+
+	types, _ := cmd.Flags().GetStringSlice("type")
+	if len(types) > 0 {
+		f.types = map[events.Type]bool{}
+		for _, t := range types {
+			typ, err := events.ParseType(t)
+			if err != nil {
+				return nil, err
+			}
+			f.types[typ] = true
+		}
+	}
+
+	f.iface, _ = cmd.Flags().GetString("iface")
+
+	parseCIDR := func(flag string) (*net.IPNet, error) {
+		s, _ := cmd.Flags().GetString(flag)
+		if s == "" {
+			return nil, nil
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP '%s'", s)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			s = fmt.Sprintf("%s/%d", s, bits)
+		}
+		_, cidr, err := net.ParseCIDR(s)
+		return cidr, err
+	}
+	if f.src, err = parseCIDR("src"); err != nil {
+		return nil, err
+	}
+	if f.dst, err = parseCIDR("dst"); err != nil {
+		return nil, err
+	}
+
+	f.proto, _ = cmd.Flags().GetUint8("proto")
+	f.port, _ = cmd.Flags().GetUint16("port")
+
+	return &f, nil
+}
+
+func (f *eventsFilter) matches(e *events.Event) bool {
+	if f.types != nil && !f.types[e.Type] {
+		return false
+	}
+	if f.iface != "" && f.iface != e.Iface {
+		return false
+	}
+	if f.src != nil && !f.src.Contains(e.Src) {
+		return false
+	}
+	if f.dst != nil && !f.dst.Contains(e.Dst) {
+		return false
+	}
+	if f.proto != 0 && f.proto != e.Proto {
+		return false
+	}
+	if f.port != 0 && f.port != e.SrcPort && f.port != e.DstPort {
+		return false
+	}
+	return true
+}
+
+func followEvents(cmd *cobra.Command, f *eventsFilter, asJSON bool) error {
+	conn, err := net.Dial("unix", events.SocketPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to Felix, is BPFEventsSampleRate set?")
+	}
+	defer conn.Close()
+
+	// Rule names are resolved lazily and cached per interface and hook.
+	names := map[string]map[uint64]string{}
+	ruleName := func(e *events.Event) string {
+		key := e.Iface + "/" + e.Hook
+		n, ok := names[key]
+		if !ok {
+			n, _ = traceRuleNames(e.Iface, hook.StringToHook(e.Hook), e.Src.To4() == nil)
+			names[key] = n
+		}
+		return n[e.RuleID]
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.WithError(err).Warn("Failed to decode event.")
+			continue
+		}
+		if !f.matches(&e) {
+			continue
+		}
+		if asJSON {
+			cmd.Println(scanner.Text())
+			continue
+		}
+		s := e.String()
+		if e.RuleID != 0 {
+			if name := ruleName(&e); name != "" {
+				s += " // " + name
+			}
+		}
+		cmd.Println(s)
+	}
+	return scanner.Err()
+}
@@ -48,11 +48,11 @@ var ipsetsCmd = &cobra.Command{
 }
 
 func dumpIPSets() error {
-	ipsetMap := ipsets.Map()
+	ipsetMap := dumpMap(ipsets.Map())
 	fromBytes := ipsets.IPSetEntryFromBytes
 
 	if ipv6 != nil && *ipv6 {
-		ipsetMap = ipsets.MapV6()
+		ipsetMap = dumpMap(ipsets.MapV6())
 		fromBytes = ipsets.IPSetEntryV6FromBytes
 	}
 
@@ -24,11 +24,17 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/ip"
 )
 
 func init() {
 	natCmd.AddCommand(natDumpCmd)
+	natAffDumpCmd.AddCommand(natAffDelCmd)
+	addAffFilterFlags(natAffDumpCmd)
+	addAffFilterFlags(natAffDelCmd)
+	natAffDelCmd.Flags().BoolVar(&natAffDelAll, "all", false, "deletes all the entries")
 	natCmd.AddCommand(natAffDumpCmd)
 
 	natSetCmd.AddCommand(newNatSetFrontend())
@@ -63,6 +69,7 @@ var natDumpCmd = &cobra.Command{
 var natAffDumpCmd = &cobra.Command{
 	Use:   "aff",
 	Short: "dumps the affinity table",
+	Args:  parseAffFilter,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := dumpAff(cmd); err != nil {
 			log.WithError(err).Error("Failed to dump affinity map")
@@ -70,6 +77,121 @@ var natAffDumpCmd = &cobra.Command{
 	},
 }
 
+var natAffDelCmd = &cobra.Command{
+	Use:   "del",
+	Short: "deletes entries from the affinity table so that the clients pick a new backend",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := parseAffFilter(cmd, args); err != nil {
+			return err
+		}
+		if natAffDelAll == (affFilter.client != nil || affFilter.svc != nil) {
+			return fmt.Errorf("either --all or --client and/or --service must be set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := delAff(cmd); err != nil {
+			log.WithError(err).Error("Failed to delete from affinity map")
+		}
+	},
+}
+
+var (
+	affFilterClient  string
+	affFilterService string
+	natAffDelAll     bool
+
+	affFilter natAffFilter
+)
+
+func addAffFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&affFilterClient, "client", "",
+		"only the entries of the client IP or CIDR, including the entries of the prefixes that contain it")
+	cmd.Flags().StringVar(&affFilterService, "service", "", "only the entries of the service <ip>[:<port>]")
+}
+
+// natAffFilter selects affinity entries by client and service.
+type natAffFilter struct {
+	client  ip.CIDR
+	svc     net.IP
+	svcPort uint16
+}
+
+func parseAffFilter(_ *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments %v", args)
+	}
+
+	f, err := newNATAffFilter(affFilterClient, affFilterService)
+	if err != nil {
+		return err
+	}
+	affFilter = f
+	return nil
+}
+
+func newNATAffFilter(client, svc string) (natAffFilter, error) {
+	var f natAffFilter
+
+	if client != "" {
+		cidr, err := ip.ParseCIDROrIP(client)
+		if err != nil {
+			return f, fmt.Errorf("--client: %q is not an IP or CIDR", client)
+		}
+		f.client = cidr
+	}
+
+	if svc != "" {
+		host, port := svc, ""
+		if h, p, err := net.SplitHostPort(svc); err == nil {
+			host, port = h, p
+		}
+		f.svc = net.ParseIP(host)
+		if f.svc == nil {
+			return f, fmt.Errorf("--service: %q is not an IP", host)
+		}
+		if port != "" {
+			p, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return f, fmt.Errorf("--service: %q is not 16-bit uint", port)
+			}
+			f.svcPort = uint16(p)
+		}
+	}
+
+	return f, nil
+}
+
+func (f natAffFilter) match(k nat.AffinityKeyInterface) bool {
+	if f.svc != nil {
+		fe := k.FrontendAffinityKey()
+		if !fe.Addr().Equal(f.svc) || (f.svcPort != 0 && fe.Port() != f.svcPort) {
+			return false
+		}
+	}
+
+	if f.client != nil {
+		clientIP := ip.FromNetIP(k.ClientIP())
+		prefixLen := k.PrefixLen()
+		if prefixLen == 0 {
+			prefixLen = int(clientIP.AsCIDR().Prefix())
+		}
+		entry := ip.CIDRFromAddrAndPrefix(clientIP, prefixLen)
+		if !entry.Contains(f.client.Addr()) && !f.client.Contains(entry.Addr()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func affMapAndKeyFn() (maps.Map, func([]byte) nat.AffinityKeyInterface, func([]byte) nat.AffinityValueInterface) {
+	if ipv6 != nil && *ipv6 {
+		return nat.AffinityMapV6(), nat.AffinityKeyV6IntfFromBytes, nat.AffinityValueV6IntfFromBytes
+	}
+	return nat.AffinityMap(), nat.AffinityKeyIntfFromBytes, nat.AffinityValueIntfFromBytes
+}
+
 var natSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "sets an entry in the NAT tables",
@@ -81,13 +203,21 @@ var natDelCmd = &cobra.Command{
 }
 
 func dumpAff(cmd *cobra.Command) (err error) {
-	affMap, err := nat.LoadAffinityMap(nat.AffinityMap())
-	if err != nil {
+	m, keyFromBytes, valueFromBytes := affMapAndKeyFn()
+	affMap := dumpMap(m)
+	if err := affMap.Open(); err != nil {
 		return err
 	}
 
-	for k, v := range affMap {
-		cmd.Printf("%-40s %s\n", k, v)
+	err = affMap.Iter(func(k, v []byte) maps.IteratorAction {
+		key := keyFromBytes(k)
+		if affFilter.match(key) {
+			cmd.Printf("%-40s %s\n", key, valueFromBytes(v))
+		}
+		return maps.IterNone
+	})
+	if err != nil {
+		return err
 	}
 
 	cmd.Printf("\n")
@@ -95,26 +225,49 @@ func dumpAff(cmd *cobra.Command) (err error) {
 	return nil
 }
 
+func delAff(cmd *cobra.Command) error {
+	affMap, keyFromBytes, _ := affMapAndKeyFn()
+	if err := affMap.Open(); err != nil {
+		return err
+	}
+
+	deleted := 0
+	err := affMap.Iter(func(k, v []byte) maps.IteratorAction {
+		if natAffDelAll || affFilter.match(keyFromBytes(k)) {
+			deleted++
+			return maps.IterDelete
+		}
+		return maps.IterNone
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("deleted %d entries\n", deleted)
+
+	return nil
+}
+
 func dump(cmd *cobra.Command) error {
 	if ipv6 != nil && *ipv6 {
-		natMap, err := nat.LoadFrontendMapV6(nat.FrontendMapV6())
+		natMap, err := nat.LoadFrontendMapV6(dumpMap(nat.FrontendMapV6()))
 		if err != nil {
 			return err
 		}
 
-		back, err := nat.LoadBackendMapV6(nat.BackendMapV6())
+		back, err := nat.LoadBackendMapV6(dumpMap(nat.BackendMapV6()))
 		if err != nil {
 			return err
 		}
 
 		dumpNice[nat.FrontendKeyV6, nat.BackendValueV6](cmd.Printf, natMap, back)
 	} else {
-		natMap, err := nat.LoadFrontendMap(nat.FrontendMap())
+		natMap, err := nat.LoadFrontendMap(dumpMap(nat.FrontendMap()))
 		if err != nil {
 			return err
 		}
 
-		back, err := nat.LoadBackendMap(nat.BackendMap())
+		back, err := nat.LoadBackendMap(dumpMap(nat.BackendMap()))
 		if err != nil {
 			return err
 		}
@@ -140,6 +293,9 @@ func dumpNice[FK nat.FrontendKeyComparable, BV nat.BackendValueInterface](printf
 		if flags != "" {
 			flags = " flags " + flags
 		}
+		if l := nv.AffinityPrefixLen(); l != 0 {
+			flags += fmt.Sprintf(" affinity prefix /%d", l)
+		}
 		printf("%s port %d proto %d id %d count %d local %d%s\n",
 			nk.Addr(), nk.Port(), nk.Proto(), id, count, local, flags)
 		for i := 0; i < count; i++ {
@@ -19,6 +19,8 @@ import (
 	"net"
 	"testing"
 
+	. "github.com/onsi/gomega"
+
 	nat2 "github.com/projectcalico/calico/felix/bpf/nat"
 )
 
@@ -37,3 +39,38 @@ func TestNATDump(t *testing.T) {
 
 	dumpNice(func(format string, i ...interface{}) { fmt.Printf(format, i...) }, nat, back)
 }
+
+func TestNATAffFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	svcKey := nat2.NewNATKey(net.IPv4(10, 96, 0, 10), 80, 6)
+	clientKey := nat2.NewAffinityKey(net.IPv4(5, 5, 5, 5), svcKey)
+	prefixKey := nat2.NewAffinityKey(net.IPv4(6, 6, 6, 6), svcKey).WithPrefixLen(24)
+
+	for _, tc := range []struct {
+		client, svc string
+		key         nat2.AffinityKey
+		expected    bool
+	}{
+		{"", "", clientKey, true},
+		{"5.5.5.5", "", clientKey, true},
+		{"5.5.0.0/16", "", clientKey, true},
+		{"5.5.5.6", "", clientKey, false},
+		{"6.6.6.7", "", prefixKey, true},
+		{"6.6.0.0/16", "", prefixKey, true},
+		{"6.6.7.0/24", "", prefixKey, false},
+		{"", "10.96.0.10", clientKey, true},
+		{"", "10.96.0.10:80", clientKey, true},
+		{"", "10.96.0.10:443", clientKey, false},
+		{"", "10.96.0.11", clientKey, false},
+	} {
+		f, err := newNATAffFilter(tc.client, tc.svc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.match(tc.key)).To(Equal(tc.expected), "client %q service %q key %s", tc.client, tc.svc, tc.key)
+	}
+
+	for _, tc := range [][2]string{{"5.5.5", ""}, {"", "10.96.0"}, {"", "10.96.0.10:http"}} {
+		_, err := newNATAffFilter(tc[0], tc[1])
+		Expect(err).To(HaveOccurred())
+	}
+}
@@ -17,8 +17,6 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
 	"strconv"
 	"strings"
 
@@ -66,7 +64,7 @@ var policyDumpCmd = &cobra.Command{
 			hooks = []hook.Hook{hook.XDP}
 		}
 
-		rmap := counters.PolicyMap()
+		rmap := dumpMap(counters.PolicyMap())
 		m, err := counters.LoadPolicyMap(rmap)
 		if err != nil {
 			log.WithError(err).Error("error loading rule counters map.")
@@ -126,17 +124,11 @@ func dumpPolicyInfo(cmd *cobra.Command, iface string, h hook.Hook, m counters.Po
 		family = proto.IPVersion_IPV6
 	}
 	filename := bpf.PolicyDebugJSONFileName(iface, h.String(), family)
-	_, err := os.Stat(filename)
+	byteValue, err := readDumpFile(filename)
 	if err != nil {
 		return err
 	}
 
-	jsonFile, err := os.Open(filename)
-	if err != nil {
-		return err
-	}
-
-	byteValue, _ := io.ReadAll(jsonFile)
 	dec := json.NewDecoder(strings.NewReader(string(byteValue)))
 	err = dec.Decode(&policyDbg)
 	if err != nil {
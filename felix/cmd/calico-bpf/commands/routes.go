@@ -52,9 +52,9 @@ func dumpRoutes() error {
 	var routesMap maps.Map
 
 	if ipv6 != nil && *ipv6 {
-		routesMap = routes.MapV6()
+		routesMap = dumpMap(routes.MapV6())
 	} else {
-		routesMap = routes.Map()
+		routesMap = dumpMap(routes.Map())
 	}
 
 	if err := routesMap.Open(); err != nil {
@@ -0,0 +1,242 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/projectcalico/calico/felix/bpf"
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	"github.com/projectcalico/calico/felix/bpf/ifstate"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/snapshot"
+)
+
+var (
+	snapshotOutput string
+
+	// fromSnapshot is the snapshot given by --from-snapshot to the dump commands, the
+	// dumps decode its maps instead of the maps of the node.
+	fromSnapshot   string
+	loadedSnapshot *snapshot.Snapshot
+)
+
+func init() {
+	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "",
+		"file to write the snapshot to (default calico-bpf-snapshot-<hostname>-<time>.tar.gz)")
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.PersistentPreRunE = loadSnapshot
+
+	for _, cmd := range []*cobra.Command{
+		arpDumpCmd,
+		countersDumpCmd,
+		ddosDumpCmd,
+		ifstateDumpCmd,
+		ipsetsDumpCmd,
+		natDumpCmd,
+		natAffDumpCmd,
+		policyDumpCmd,
+		routesDumpCmd,
+	} {
+		addFromSnapshotFlag(cmd)
+	}
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot [--output <file>]",
+	Short: "Saves all Calico BPF maps into an archive for offline analysis with --from-snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := writeSnapshot(cmd); err != nil {
+			log.WithError(err).Error("Failed to take snapshot.")
+		}
+	},
+}
+
+func addFromSnapshotFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "",
+		"decode the maps of a snapshot taken by 'calico-bpf snapshot' instead of the maps of this node")
+}
+
+func writeSnapshot(cmd *cobra.Command) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get hostname")
+	}
+
+	filename := snapshotOutput
+	if filename == "" {
+		filename = fmt.Sprintf("calico-bpf-snapshot-%s-%s.tar.gz", hostname, time.Now().UTC().Format("20060102-150405"))
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create snapshot file")
+	}
+	defer f.Close()
+
+	w := snapshot.NewWriter(f, hostname, maps.NumPossibleCPUs(), bpf.KTimeNanos())
+
+	for _, m := range bpfmap.All() {
+		pm, ok := m.(*maps.PinnedMap)
+		if !ok {
+			continue
+		}
+		switch pm.Type {
+		case "prog_array", "ringbuf", "perf_event_array":
+			// Not data that can be decoded offline.
+			continue
+		}
+		if err := pm.Open(); err != nil {
+			log.WithError(err).WithField("map", pm.GetName()).Debug("Map not present, skipping it.")
+			continue
+		}
+		err := w.AddMap(pm, pm.Type)
+		pm.Close()
+		if err != nil {
+			return err
+		}
+		log.WithField("map", pm.GetName()).Info("Added map to snapshot.")
+	}
+
+	policyFiles, err := filepath.Glob(filepath.Join(bpf.RuntimePolDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, name := range policyFiles {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			log.WithError(err).WithField("file", name).Warn("Failed to read policy debug file, skipping it.")
+			continue
+		}
+		if err := w.AddFile(name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return errors.WithMessage(err, "failed to write snapshot")
+	}
+	cmd.Printf("Snapshot written to %s\n", filename)
+	return nil
+}
+
+func loadSnapshot(cmd *cobra.Command, args []string) error {
+	if fromSnapshot == "" {
+		return nil
+	}
+
+	s, err := snapshot.Open(fromSnapshot)
+	if err != nil {
+		return errors.WithMessage(err, "failed to read snapshot")
+	}
+	log.WithFields(log.Fields{
+		"hostname": s.Hostname,
+		"created":  s.Created,
+	}).Info("Decoding maps from snapshot.")
+
+	maps.SetNumPossibleCPUs(s.NumPossibleCPUs)
+	loadedSnapshot = s
+	return nil
+}
+
+// missingMap stands in for a map that the snapshot does not have, it fails to open.
+type missingMap struct {
+	maps.Map
+	name string
+}
+
+func (m missingMap) Open() error {
+	return fmt.Errorf("map %s is not in the snapshot", m.name)
+}
+
+// dumpMap returns the given map or, with --from-snapshot, its copy in the snapshot.
+func dumpMap(m maps.Map) maps.Map {
+	if loadedSnapshot == nil {
+		return m
+	}
+	if sm := loadedSnapshot.Map(m.GetName()); sm != nil {
+		return sm
+	}
+	return missingMap{Map: m, name: m.GetName()}
+}
+
+// readDumpFile reads the given file or, with --from-snapshot, its copy in the snapshot.
+func readDumpFile(filename string) ([]byte, error) {
+	if loadedSnapshot == nil {
+		return os.ReadFile(filename)
+	}
+	data, ok := loadedSnapshot.File(filename)
+	if !ok {
+		return nil, fmt.Errorf("file %s is not in the snapshot", filepath.Base(filename))
+	}
+	return data, nil
+}
+
+// dumpKTimeNanos returns the monotonic time of the node or, with --from-snapshot, the
+// time at which the snapshot was taken, zero if the snapshot does not record it.
+func dumpKTimeNanos() int64 {
+	if loadedSnapshot == nil {
+		return bpf.KTimeNanos()
+	}
+	return loadedSnapshot.KTimeNanos
+}
+
+// dumpInterfaces returns the interfaces of the node or, with --from-snapshot, those of
+// the node of the snapshot, as recorded in its ifstate map.
+func dumpInterfaces() ([]net.Interface, error) {
+	if loadedSnapshot == nil {
+		return net.Interfaces()
+	}
+
+	m := dumpMap(ifstate.Map())
+	if err := m.Open(); err != nil {
+		return nil, err
+	}
+	var ifaces []net.Interface
+	err := m.Iter(func(k, v []byte) maps.IteratorAction {
+		ifaces = append(ifaces, net.Interface{
+			Index: int(ifstate.KeyFromBytes(k).IfIndex()),
+			Name:  ifstate.ValueFromBytes(v).IfName(),
+		})
+		return maps.IterNone
+	})
+	return ifaces, err
+}
+
+// dumpInterfaceByName is net.InterfaceByName, which uses the snapshot with
+// --from-snapshot.
+func dumpInterfaceByName(name string) (*net.Interface, error) {
+	if loadedSnapshot == nil {
+		return net.InterfaceByName(name)
+	}
+
+	ifaces, err := dumpInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	for i := range ifaces {
+		if ifaces[i].Name == name {
+			return &ifaces[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no interface %s in the snapshot", name)
+}
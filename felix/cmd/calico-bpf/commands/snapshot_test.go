@@ -0,0 +1,80 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf"
+	"github.com/projectcalico/calico/felix/bpf/ifstate"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/routes"
+	"github.com/projectcalico/calico/felix/bpf/snapshot"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+func TestFromSnapshot(t *testing.T) {
+	RegisterTestingT(t)
+
+	params := ifstate.MapParams
+	params.Name = params.VersionedName()
+	params.Version = 0
+	ifstateMap := mock.NewMockMap(params)
+	Expect(ifstateMap.Update(ifstate.NewKey(3).AsBytes(),
+		ifstate.NewValue(0, "eth0", -1, -1, -1, -1, -1, -1, -1, -1).AsBytes())).To(Succeed())
+
+	policyFile := bpf.PolicyDebugJSONFileName("eth0", "ingress", proto.IPVersion_IPV4)
+
+	filename := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	f, err := os.Create(filename)
+	Expect(err).NotTo(HaveOccurred())
+	w := snapshot.NewWriter(f, "node1", 2, 12345)
+	Expect(w.AddMap(ifstateMap, params.Type)).To(Succeed())
+	Expect(w.AddFile(policyFile, []byte("{}"))).To(Succeed())
+	Expect(w.Close()).To(Succeed())
+	Expect(f.Close()).To(Succeed())
+
+	fromSnapshot = filename
+	defer func() {
+		fromSnapshot = ""
+		loadedSnapshot = nil
+	}()
+	Expect(loadSnapshot(nil, nil)).To(Succeed())
+	Expect(dumpKTimeNanos()).To(Equal(int64(12345)))
+
+	ifaces, err := dumpInterfaces()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(ifaces).To(HaveLen(1))
+	Expect(ifaces[0].Index).To(Equal(3))
+	Expect(ifaces[0].Name).To(Equal("eth0"))
+
+	iface, err := dumpInterfaceByName("eth0")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(iface.Index).To(Equal(3))
+	_, err = dumpInterfaceByName("eth1")
+	Expect(err).To(HaveOccurred())
+
+	Expect(dumpMap(routes.Map()).Open()).To(MatchError(ContainSubstring("not in the snapshot")))
+
+	data, err := readDumpFile(policyFile)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(data).To(Equal([]byte("{}")))
+	_, err = readDumpFile(bpf.PolicyDebugJSONFileName("eth0", "egress", proto.IPVersion_IPV4))
+	Expect(err).To(HaveOccurred())
+}
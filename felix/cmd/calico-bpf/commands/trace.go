@@ -0,0 +1,658 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/projectcalico/calico/felix/bpf"
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	"github.com/projectcalico/calico/felix/bpf/counters"
+	"github.com/projectcalico/calico/felix/bpf/hook"
+	"github.com/projectcalico/calico/felix/bpf/libbpf"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/bpf/nat"
+	"github.com/projectcalico/calico/felix/bpf/tc"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+func init() {
+	traceCmd.Flags().String("src", "", "Source IP of the packet")
+	traceCmd.Flags().String("dst", "", "Destination IP of the packet")
+	traceCmd.Flags().String("proto", "tcp", "Protocol of the packet: 'tcp', 'udp' or 'icmp'")
+	traceCmd.Flags().Uint16("sport", 54321, "Source port of the packet")
+	traceCmd.Flags().Uint16("dport", 0, "Destination port of the packet")
+	traceCmd.Flags().Uint32("mark", 0, "Mark of the packet (tc hooks only)")
+	traceCmd.Flags().Bool("keep-conntrack", false,
+		"Keep the conntrack entries created by the packet instead of removing them")
+	rootCmd.AddCommand(traceCmd)
+}
+
+var traceCmd = &cobra.Command{
+	Use: "trace <interface> <hook> --src <ip> --dst <ip> [--proto <proto>] [--sport <port>] [--dport <port>]\n" +
+		"\n\thook - can be 'ingress', 'egress' or 'xdp'.",
+	Short: "runs a synthetic packet through the program attached to an interface",
+	Long: "Builds a packet from the given parameters and runs it through the program that " +
+		"is attached to the interface and hook, using BPF_PROG_TEST_RUN. Prints the verdict, " +
+		"the reason for a drop, the NAT translation, the conntrack entries that the packet " +
+		"used or created and the policy rules that it matched.\n\n" +
+		"Side effects: the program is the live one and runs against the live maps, so the " +
+		"packet is handled like a real packet would be. It is added to the interface and " +
+		"policy rule counters, which are what 'calico-bpf counters' and Felix's felix_bpf_* " +
+		"Prometheus metrics report, and it may emit BPF events, such as a policy drop, to " +
+		"'calico-bpf events' and Felix. These are not undone. Conntrack entries created by " +
+		"the packet are removed afterwards, unless --keep-conntrack is given. NAT affinity " +
+		"entries of the source IP are always restored to their state before the packet.\n\n" +
+		"Concurrent traffic on the interface may show up in the counters and rule matches.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runTrace(cmd, args); err != nil {
+			log.WithError(err).Error("Failed to trace packet.")
+		}
+	},
+}
+
+type traceParams struct {
+	src, dst     net.IP
+	proto        uint8
+	sport, dport uint16
+	mark         uint32
+}
+
+func parseTraceParams(cmd *cobra.Command) (*traceParams, error) {
+	var (
+		p   traceParams
+		err error
+	)
+
+	src, _ := cmd.Flags().GetString("src")
+	dst, _ := cmd.Flags().GetString("dst")
+	if p.src = net.ParseIP(src); p.src == nil {
+		return nil, fmt.Errorf("invalid source IP: '%s'", src)
+	}
+	if p.dst = net.ParseIP(dst); p.dst == nil {
+		return nil, fmt.Errorf("invalid destination IP: '%s'", dst)
+	}
+	if (p.src.To4() == nil) != (p.dst.To4() == nil) {
+		return nil, fmt.Errorf("source and destination IPs are of different families")
+	}
+	if p.src.To4() == nil && !*ipv6 {
+		return nil, fmt.Errorf("IPv6 addresses require the --ipv6 flag")
+	}
+	if p.src.To4() != nil && *ipv6 {
+		return nil, fmt.Errorf("IPv4 addresses cannot be used with the --ipv6 flag")
+	}
+
+	protoStr, _ := cmd.Flags().GetString("proto")
+	switch strings.ToLower(protoStr) {
+	case "tcp":
+		p.proto = conntrack.ProtoTCP
+	case "udp":
+		p.proto = conntrack.ProtoUDP
+	case "icmp":
+		p.proto = conntrack.ProtoICMP
+		if *ipv6 {
+			p.proto = conntrack.ProtoICMP6
+		}
+	default:
+		return nil, fmt.Errorf("invalid protocol: '%s'", protoStr)
+	}
+
+	if p.sport, err = cmd.Flags().GetUint16("sport"); err != nil {
+		return nil, err
+	}
+	if p.dport, err = cmd.Flags().GetUint16("dport"); err != nil {
+		return nil, err
+	}
+	if p.dport == 0 && (p.proto == conntrack.ProtoTCP || p.proto == conntrack.ProtoUDP) {
+		return nil, fmt.Errorf("destination port is required for %s", protoStr)
+	}
+	if p.mark, err = cmd.Flags().GetUint32("mark"); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// buildTracePacket builds an ethernet frame carrying a TCP SYN, a UDP datagram or an
+// ICMP echo request with the given parameters.
+func buildTracePacket(p *traceParams) ([]byte, error) {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+		DstMAC:       net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+
+	var (
+		ip   gopacket.NetworkLayer
+		l4   []gopacket.SerializableLayer
+		ipv4 *layers.IPv4
+		ip6  *layers.IPv6
+	)
+
+	if p.src.To4() != nil {
+		ipv4 = &layers.IPv4{
+			Version:  4,
+			IHL:      5,
+			TTL:      64,
+			Flags:    layers.IPv4DontFragment,
+			SrcIP:    p.src.To4(),
+			DstIP:    p.dst.To4(),
+			Protocol: layers.IPProtocol(p.proto),
+		}
+		ip = ipv4
+	} else {
+		eth.EthernetType = layers.EthernetTypeIPv6
+		ip6 = &layers.IPv6{
+			Version:    6,
+			HopLimit:   64,
+			SrcIP:      p.src,
+			DstIP:      p.dst,
+			NextHeader: layers.IPProtocol(p.proto),
+		}
+		ip = ip6
+	}
+
+	payload := gopacket.Payload([]byte("calico-bpf trace"))
+
+	switch p.proto {
+	case conntrack.ProtoTCP:
+		tcp := &layers.TCP{
+			SrcPort:    layers.TCPPort(p.sport),
+			DstPort:    layers.TCPPort(p.dport),
+			SYN:        true,
+			Seq:        1,
+			Window:     65535,
+			DataOffset: 5,
+		}
+		_ = tcp.SetNetworkLayerForChecksum(ip)
+		l4 = []gopacket.SerializableLayer{tcp}
+	case conntrack.ProtoUDP:
+		udp := &layers.UDP{
+			SrcPort: layers.UDPPort(p.sport),
+			DstPort: layers.UDPPort(p.dport),
+		}
+		_ = udp.SetNetworkLayerForChecksum(ip)
+		l4 = []gopacket.SerializableLayer{udp, payload}
+	case conntrack.ProtoICMP:
+		l4 = []gopacket.SerializableLayer{&layers.ICMPv4{
+			TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0),
+			Id:       p.sport,
+			Seq:      1,
+		}, payload}
+	case conntrack.ProtoICMP6:
+		icmp := &layers.ICMPv6{
+			TypeCode: layers.CreateICMPv6TypeCode(layers.ICMPv6TypeEchoRequest, 0),
+		}
+		_ = icmp.SetNetworkLayerForChecksum(ip)
+		l4 = []gopacket.SerializableLayer{icmp, &layers.ICMPv6Echo{
+			Identifier: p.sport,
+			SeqNumber:  1,
+		}, payload}
+	default:
+		return nil, fmt.Errorf("unsupported protocol %d", p.proto)
+	}
+
+	pkt := []gopacket.SerializableLayer{eth}
+	if ipv4 != nil {
+		pkt = append(pkt, ipv4)
+	} else {
+		pkt = append(pkt, ip6)
+	}
+	pkt = append(pkt, l4...)
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+	if err := gopacket.SerializeLayers(buf, opts, pkt...); err != nil {
+		return nil, errors.Wrap(err, "failed to build packet")
+	}
+	return buf.Bytes(), nil
+}
+
+// buildTraceCtx builds the context that the program is run with, that is the prefix of
+// struct __sk_buff for tc programs and struct xdp_md for XDP programs.
+func buildTraceCtx(h hook.Hook, ifindex int, mark uint32, pktLen int) []byte {
+	if h == hook.XDP {
+		ctx := make([]byte, 24)
+		binary.LittleEndian.PutUint32(ctx[4:8], uint32(pktLen)) // data_end
+		binary.LittleEndian.PutUint32(ctx[12:16], uint32(ifindex))
+		return ctx
+	}
+
+	ctx := make([]byte, 48)
+	binary.LittleEndian.PutUint32(ctx[8:12], mark)
+	if h == hook.Ingress {
+		binary.LittleEndian.PutUint32(ctx[36:40], uint32(ifindex))
+	}
+	binary.LittleEndian.PutUint32(ctx[40:44], uint32(ifindex))
+	return ctx
+}
+
+func traceVerdict(h hook.Hook, rc int32) string {
+	if h == hook.XDP {
+		switch rc {
+		case 0:
+			return "XDP_ABORTED"
+		case 1:
+			return "XDP_DROP"
+		case 2:
+			return "XDP_PASS"
+		case 3:
+			return "XDP_TX"
+		case 4:
+			return "XDP_REDIRECT"
+		}
+	} else {
+		switch rc {
+		case -1:
+			return "TC_ACT_UNSPEC"
+		case 0:
+			return "TC_ACT_OK"
+		case 2:
+			return "TC_ACT_SHOT"
+		case 7:
+			return "TC_ACT_REDIRECT"
+		}
+	}
+	return fmt.Sprintf("unknown (%d)", rc)
+}
+
+func attachedProgramID(iface string, h hook.Hook) (int, error) {
+	if h == hook.XDP {
+		return libbpf.GetXDPProgramID(iface)
+	}
+	ap := &tc.AttachPoint{
+		AttachPoint: bpf.AttachPoint{
+			Iface: iface,
+			Hook:  h,
+		},
+	}
+	return ap.ProgramID()
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	iface, hookStr, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	h := hook.StringToHook(hookStr)
+	if h == hook.Bad {
+		return fmt.Errorf("invalid hook: '%s'", hookStr)
+	}
+	p, err := parseTraceParams(cmd)
+	if err != nil {
+		return err
+	}
+	keepCT, _ := cmd.Flags().GetBool("keep-conntrack")
+
+	ifc, err := net.InterfaceByName(iface)
+	if err != nil {
+		return errors.Wrapf(err, "no such interface: %s", iface)
+	}
+
+	progID, err := attachedProgramID(iface, h)
+	if err != nil {
+		return errors.Wrapf(err, "no program attached to %s %s", iface, h)
+	}
+	progFD, err := bpf.GetProgFDByID(progID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get program %d", progID)
+	}
+	defer progFD.Close()
+
+	pkt, err := buildTracePacket(p)
+	if err != nil {
+		return err
+	}
+
+	cMap := counters.Map()
+	if err := cMap.Open(); err != nil {
+		return errors.Wrap(err, "failed to open counters map")
+	}
+	defer cMap.Close()
+
+	ctMap := conntrack.Map()
+	if *ipv6 {
+		ctMap = conntrack.MapV6()
+	}
+	if err := ctMap.Open(); err != nil {
+		return errors.Wrap(err, "failed to open conntrack map")
+	}
+	defer ctMap.Close()
+
+	affMap, affKeyFromBytes, _ := affMapAndKeyFn()
+	if err := affMap.Open(); err != nil {
+		return errors.Wrap(err, "failed to open NAT affinity map")
+	}
+	defer affMap.Close()
+
+	affBefore, err := traceAffinitySnapshot(affMap, affKeyFromBytes, p.src)
+	if err != nil {
+		return errors.Wrap(err, "failed to read NAT affinity")
+	}
+
+	cntBefore, err := counters.Read(cMap, ifc.Index, h)
+	if err != nil {
+		return errors.Wrap(err, "failed to read counters")
+	}
+	rulesBefore, err := counters.LoadPolicyMap(counters.PolicyMap())
+	if err != nil {
+		return errors.Wrap(err, "failed to read rule counters")
+	}
+	ctBefore := traceCTLookup(ctMap, p)
+
+	res, err := bpf.RunBPFProgramWithCtx(progFD, pkt, buildTraceCtx(h, ifc.Index, p.mark, len(pkt)), 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to run program")
+	}
+
+	cntAfter, err := counters.Read(cMap, ifc.Index, h)
+	if err != nil {
+		return errors.Wrap(err, "failed to read counters")
+	}
+	rulesAfter, err := counters.LoadPolicyMap(counters.PolicyMap())
+	if err != nil {
+		return errors.Wrap(err, "failed to read rule counters")
+	}
+	ctAfter := traceCTLookup(ctMap, p)
+	affAfter, err := traceAffinitySnapshot(affMap, affKeyFromBytes, p.src)
+	if err != nil {
+		log.WithError(err).Warn("Failed to read NAT affinity, entries created by the packet are not removed.")
+	}
+
+	cmd.Printf("Program: %d on %s %s\n", progID, iface, h)
+	cmd.Printf("Packet: %s\n", describeTracePacket(pkt))
+	cmd.Printf("Verdict: %s\n", traceVerdict(h, res.RC))
+
+	for _, d := range counters.Descriptions() {
+		if d.Category != "Dropped" && d.Category != "Accepted" {
+			continue
+		}
+		if cntAfter[d.Counter] > cntBefore[d.Counter] {
+			cmd.Printf("%s: %s\n", d.Category, d.Caption)
+		}
+	}
+
+	if len(res.DataOut) > 0 {
+		if out := describeTracePacket(res.DataOut); out != describeTracePacket(pkt) {
+			cmd.Printf("NAT: %s -> %s\n", describeTracePacket(pkt), out)
+		} else {
+			cmd.Println("NAT: none")
+		}
+	}
+
+	printTraceConntrack(cmd, ctMap, ctBefore, ctAfter, keepCT)
+	restoreTraceAffinity(cmd, affMap, affKeyFromBytes, affBefore, affAfter)
+	printTraceRules(cmd, iface, h, rulesBefore, rulesAfter)
+
+	return nil
+}
+
+// describeTracePacket returns the addresses and ports of the innermost packet of a frame
+// and whether it is encapsulated.
+func describeTracePacket(data []byte) string {
+	pkt := gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+
+	var (
+		src, dst     string
+		sport, dport string
+		ipLayers     int
+	)
+	for _, l := range pkt.Layers() {
+		switch l := l.(type) {
+		case *layers.IPv4:
+			src, dst = l.SrcIP.String(), l.DstIP.String()
+			sport, dport = "", ""
+			ipLayers++
+		case *layers.IPv6:
+			src, dst = l.SrcIP.String(), l.DstIP.String()
+			sport, dport = "", ""
+			ipLayers++
+		case *layers.TCP:
+			sport, dport = fmt.Sprint(uint16(l.SrcPort)), fmt.Sprint(uint16(l.DstPort))
+		case *layers.UDP:
+			sport, dport = fmt.Sprint(uint16(l.SrcPort)), fmt.Sprint(uint16(l.DstPort))
+		}
+	}
+	if ipLayers == 0 {
+		return fmt.Sprintf("unparsable (%d bytes)", len(data))
+	}
+
+	s := fmt.Sprintf("%s -> %s", src, dst)
+	if sport != "" {
+		s = fmt.Sprintf("%s -> %s", net.JoinHostPort(src, sport), net.JoinHostPort(dst, dport))
+	}
+	if ipLayers > 1 {
+		s += " (encapsulated)"
+	}
+	return s
+}
+
+type traceCTEntry struct {
+	key []byte
+	val conntrack.ValueInterface
+}
+
+// traceCTLookup looks up the conntrack entries of the packet's flow, in both orders of
+// the addresses, as the key has the lower address first.
+func traceCTLookup(m maps.Map, p *traceParams) map[string]traceCTEntry {
+	if p.proto != conntrack.ProtoTCP && p.proto != conntrack.ProtoUDP {
+		return nil
+	}
+
+	var keys [][]byte
+	if *ipv6 {
+		keys = [][]byte{
+			conntrack.NewKeyV6(p.proto, p.src, p.sport, p.dst, p.dport).AsBytes(),
+			conntrack.NewKeyV6(p.proto, p.dst, p.dport, p.src, p.sport).AsBytes(),
+		}
+	} else {
+		keys = [][]byte{
+			conntrack.NewKey(p.proto, p.src, p.sport, p.dst, p.dport).AsBytes(),
+			conntrack.NewKey(p.proto, p.dst, p.dport, p.src, p.sport).AsBytes(),
+		}
+	}
+
+	entries := map[string]traceCTEntry{}
+	for _, k := range keys {
+		if v, err := m.Get(k); err == nil {
+			entries[string(k)] = traceCTEntry{key: k, val: traceCTValue(v)}
+		}
+	}
+	return entries
+}
+
+func traceCTValue(v []byte) conntrack.ValueInterface {
+	if *ipv6 {
+		var val conntrack.ValueV6
+		copy(val[:], v)
+		return val
+	}
+	return conntrack.ValueFromBytes(v)
+}
+
+func traceCTKeyString(k []byte) string {
+	if *ipv6 {
+		var key conntrack.KeyV6
+		copy(key[:], k)
+		return key.String()
+	}
+	return conntrack.KeyFromBytes(k).String()
+}
+
+func printTraceConntrack(cmd *cobra.Command, m maps.Map, before, after map[string]traceCTEntry, keep bool) {
+	if before == nil {
+		cmd.Println("Conntrack: not checked for this protocol")
+		return
+	}
+	if len(after) == 0 {
+		cmd.Println("Conntrack: no entry")
+		return
+	}
+
+	var created []traceCTEntry
+	for k, e := range after {
+		if _, ok := before[k]; ok {
+			cmd.Printf("Conntrack: used existing entry %s %s\n", traceCTKeyString(e.key), e.val)
+			continue
+		}
+		cmd.Printf("Conntrack: created entry %s %s\n", traceCTKeyString(e.key), e.val)
+		created = append(created, e)
+	}
+
+	for _, e := range created {
+		if e.val.Type() != conntrack.TypeNATForward {
+			continue
+		}
+		revKey := e.val.ReverseNATKey().AsBytes()
+		v, err := m.Get(revKey)
+		if err != nil {
+			continue
+		}
+		rev := traceCTValue(v)
+		// The reverse entry is created together with the forward one, with the same
+		// creation time.
+		if rev.Created() != e.val.Created() {
+			cmd.Printf("Conntrack: used existing reverse entry %s %s\n", traceCTKeyString(revKey), rev)
+			continue
+		}
+		cmd.Printf("Conntrack: created reverse entry %s %s\n", traceCTKeyString(revKey), rev)
+		created = append(created, traceCTEntry{key: revKey, val: rev})
+	}
+
+	if keep {
+		return
+	}
+	for _, e := range created {
+		if err := m.Delete(e.key); err != nil {
+			log.WithError(err).Warnf("Failed to remove conntrack entry %s", traceCTKeyString(e.key))
+		}
+	}
+}
+
+// traceAffinitySnapshot returns the NAT affinity entries that apply to the given client.
+func traceAffinitySnapshot(m maps.Map, keyFromBytes func([]byte) nat.AffinityKeyInterface,
+	client net.IP) (map[string][]byte, error) {
+
+	entries := map[string][]byte{}
+	err := m.Iter(func(k, v []byte) maps.IteratorAction {
+		key := keyFromBytes(k)
+		bits := 8 * len(key.ClientIP())
+		prefixLen := key.PrefixLen()
+		if prefixLen == 0 {
+			prefixLen = bits
+		}
+		clients := net.IPNet{IP: key.ClientIP(), Mask: net.CIDRMask(prefixLen, bits)}
+		if clients.Contains(client) {
+			entries[string(k)] = append([]byte(nil), v...)
+		}
+		return maps.IterNone
+	})
+	return entries, err
+}
+
+// restoreTraceAffinity undoes the changes that the packet made to the NAT affinity
+// entries, so that tracing a packet does not pin a real client to a backend.
+func restoreTraceAffinity(cmd *cobra.Command, m maps.Map, keyFromBytes func([]byte) nat.AffinityKeyInterface,
+	before, after map[string][]byte) {
+
+	for k, v := range after {
+		key := keyFromBytes([]byte(k))
+		old, ok := before[k]
+		if !ok {
+			cmd.Printf("NAT affinity: created entry %s, removed\n", key)
+			if err := m.Delete([]byte(k)); err != nil {
+				log.WithError(err).Warnf("Failed to remove NAT affinity entry %s", key)
+			}
+		} else if string(old) != string(v) {
+			cmd.Printf("NAT affinity: updated entry %s, restored\n", key)
+			if err := m.Update([]byte(k), old); err != nil {
+				log.WithError(err).Warnf("Failed to restore NAT affinity entry %s", key)
+			}
+		}
+	}
+}
+
+// traceRuleNames maps the match IDs of the rules of the policy program attached to the
+// interface and hook to the rules.
+func traceRuleNames(iface string, h hook.Hook, v6 bool) (map[uint64]string, error) {
+	family := proto.IPVersion_IPV4
+	if v6 {
+		family = proto.IPVersion_IPV6
+	}
+
+	f, err := os.Open(bpf.PolicyDebugJSONFileName(iface, h.String(), family))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var policyDbg bpf.PolicyDebugInfo
+	if err := json.NewDecoder(f).Decode(&policyDbg); err != nil {
+		return nil, err
+	}
+
+	names := map[uint64]string{}
+	var rule string
+	for _, insn := range policyDbg.PolicyInfo {
+		for _, comment := range insn.Comments {
+			if strings.Contains(comment, "Start of rule") {
+				rule = comment
+			} else if strings.Contains(comment, "Rule MatchID") {
+				names[getRuleMatchID(comment)] = rule
+			}
+		}
+	}
+	return names, nil
+}
+
+func printTraceRules(cmd *cobra.Command, iface string, h hook.Hook, before, after counters.PolicyMapMem) {
+	var ids []uint64
+	for id, cnt := range after {
+		if cnt > before[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		cmd.Println("Matched rules: none")
+		return
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	names, err := traceRuleNames(iface, h, *ipv6)
+	if err != nil {
+		log.WithError(err).Debug("No policy debug info.")
+	}
+
+	cmd.Println("Matched rules:")
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			cmd.Printf("  %d // %s\n", id, name)
+		} else {
+			cmd.Printf("  %d\n", id)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/projectcalico/calico/felix/bpf/conntrack"
+	"github.com/projectcalico/calico/felix/bpf/hook"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/bpf/nat"
+)
+
+func TestBuildTracePacket(t *testing.T) {
+	RegisterTestingT(t)
+
+	pkt, err := buildTracePacket(&traceParams{
+		src:   net.ParseIP("10.65.0.1"),
+		dst:   net.ParseIP("10.96.0.10"),
+		proto: conntrack.ProtoTCP,
+		sport: 1234,
+		dport: 53,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(describeTracePacket(pkt)).To(Equal("10.65.0.1:1234 -> 10.96.0.10:53"))
+
+	pkt, err = buildTracePacket(&traceParams{
+		src:   net.ParseIP("dead:beef::1"),
+		dst:   net.ParseIP("dead:beef::2"),
+		proto: conntrack.ProtoUDP,
+		sport: 1234,
+		dport: 53,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(describeTracePacket(pkt)).To(Equal("[dead:beef::1]:1234 -> [dead:beef::2]:53"))
+
+	pkt, err = buildTracePacket(&traceParams{
+		src:   net.ParseIP("10.65.0.1"),
+		dst:   net.ParseIP("10.65.0.2"),
+		proto: conntrack.ProtoICMP,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(describeTracePacket(pkt)).To(Equal("10.65.0.1 -> 10.65.0.2"))
+}
+
+func TestBuildTraceCtx(t *testing.T) {
+	RegisterTestingT(t)
+
+	ctx := buildTraceCtx(hook.Ingress, 7, 0x1000000, 100)
+	Expect(ctx).To(HaveLen(48))
+	Expect(binary.LittleEndian.Uint32(ctx[8:12])).To(Equal(uint32(0x1000000)))
+	Expect(binary.LittleEndian.Uint32(ctx[36:40])).To(Equal(uint32(7)))
+	Expect(binary.LittleEndian.Uint32(ctx[40:44])).To(Equal(uint32(7)))
+
+	ctx = buildTraceCtx(hook.Egress, 7, 0, 100)
+	Expect(binary.LittleEndian.Uint32(ctx[36:40])).To(Equal(uint32(0)))
+	Expect(binary.LittleEndian.Uint32(ctx[40:44])).To(Equal(uint32(7)))
+
+	ctx = buildTraceCtx(hook.XDP, 7, 0, 100)
+	Expect(ctx).To(HaveLen(24))
+	Expect(binary.LittleEndian.Uint32(ctx[4:8])).To(Equal(uint32(100)))
+	Expect(binary.LittleEndian.Uint32(ctx[12:16])).To(Equal(uint32(7)))
+}
+
+func TestRestoreTraceAffinity(t *testing.T) {
+	RegisterTestingT(t)
+
+	m := mock.NewMockMap(nat.AffinityMapParameters)
+	client := net.ParseIP("10.65.0.1")
+	svc1 := nat.NewNATKey(net.ParseIP("10.96.0.10"), 53, conntrack.ProtoUDP)
+	svc2 := nat.NewNATKey(net.ParseIP("10.96.0.11"), 80, conntrack.ProtoTCP)
+	backend1 := nat.NewNATBackendValue(net.ParseIP("10.65.1.1"), 53)
+	backend2 := nat.NewNATBackendValue(net.ParseIP("10.65.1.2"), 80)
+
+	existing := nat.NewAffinityKey(client, svc1)
+	prefix := nat.NewAffinityKey(client, svc2).WithPrefixLen(24)
+	other := nat.NewAffinityKey(net.ParseIP("10.65.2.1"), svc1)
+	Expect(m.Update(existing.AsBytes(), nat.NewAffinityValue(1, backend1).AsBytes())).To(Succeed())
+	Expect(m.Update(prefix.AsBytes(), nat.NewAffinityValue(1, backend2).AsBytes())).To(Succeed())
+	Expect(m.Update(other.AsBytes(), nat.NewAffinityValue(1, backend1).AsBytes())).To(Succeed())
+
+	before, err := traceAffinitySnapshot(m, nat.AffinityKeyIntfFromBytes, client)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(before).To(HaveLen(2))
+
+	// The packet refreshes the existing entry and creates a new one.
+	created := nat.NewAffinityKey(client, nat.NewNATKey(net.ParseIP("10.96.0.12"), 80, conntrack.ProtoTCP))
+	Expect(m.Update(existing.AsBytes(), nat.NewAffinityValue(2, backend1).AsBytes())).To(Succeed())
+	Expect(m.Update(created.AsBytes(), nat.NewAffinityValue(2, backend2).AsBytes())).To(Succeed())
+
+	after, err := traceAffinitySnapshot(m, nat.AffinityKeyIntfFromBytes, client)
+	Expect(err).NotTo(HaveOccurred())
+
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+	restoreTraceAffinity(cmd, m, nat.AffinityKeyIntfFromBytes, before, after)
+
+	Expect(m.Contents).To(HaveLen(3))
+	Expect(m.Contents).NotTo(HaveKey(string(created.AsBytes())))
+	Expect(m.Contents[string(existing.AsBytes())]).To(Equal(string(nat.NewAffinityValue(1, backend1).AsBytes())))
+	Expect(out.String()).To(ContainSubstring("created entry"))
+	Expect(out.String()).To(ContainSubstring("updated entry"))
+}
@@ -209,6 +209,15 @@ type Config struct {
 	BPFDisableGROForIfaces             *regexp.Regexp    `config:"regexp;"`
 	BPFExcludeCIDRsFromNAT             []string          `config:"cidr-list;;"`
 	BPFRedirectToPeer                  string            `config:"oneof(Disabled,Enabled,L2Only);L2Only;non-zero"`
+	BPFEventsSampleRate                int               `config:"int(0);0"`
+	BPFEgressBandwidthFQEnabled        bool              `config:"bool;false"`
+	BPFXDPDDoSProtection               string            `config:"oneof(Disabled,Enabled);Disabled;non-zero"`
+	BPFXDPSYNRateLimit                 int               `config:"int(0);0"`
+	BPFXDPUDPRateLimit                 int               `config:"int(0);0"`
+	BPFXDPSYNCookies                   string            `config:"oneof(Disabled,Enabled);Disabled;non-zero"`
+	BPFXDPRateLimitPrefixLengthV4      int               `config:"int(0:32);32"`
+	BPFXDPRateLimitPrefixLengthV6      int               `config:"int(0:128);64"`
+	BPFXDPBlocklistSelector            string            `config:"string;"`
 
 	// DebugBPFCgroupV2 controls the cgroup v2 path that we apply the connect-time load balancer to.  Most distros
 	// are configured for cgroup v1, which prevents all but the root cgroup v2 from working so this is only useful
@@ -258,6 +267,11 @@ type Config struct {
 	TyphaReadTimeout time.Duration `config:"seconds;30;local"`
 	// TyphaWriteTimeout write timeout when writing data to Typha.
 	TyphaWriteTimeout time.Duration `config:"seconds;10;local"`
+	// TyphaNodeScopedView asks Typha for a view scoped to this node: full detail for the workload endpoints of this
+	// node and only the fields that are needed for policy and routing for those of other nodes.  This reduces
+	// Felix's memory usage and the bandwidth used by Typha in large clusters.  Ignored by Typha versions that don't
+	// support it.
+	TyphaNodeScopedView bool `config:"bool;false;local"`
 
 	// TyphaKeyFile path to the TLS private key to use when communicating with Typha.  If this parameter is specified,
 	// the other TLS parameters must also be specified.
@@ -383,12 +397,13 @@ type Config struct {
 	HealthHost             string                   `config:"host-address;localhost"`
 	HealthTimeoutOverrides map[string]time.Duration `config:"keydurationlist;;"`
 
-	PrometheusMetricsEnabled          bool   `config:"bool;false"`
-	PrometheusMetricsHost             string `config:"host-address;"`
-	PrometheusMetricsPort             int    `config:"int(0:65535);9091"`
-	PrometheusGoMetricsEnabled        bool   `config:"bool;true"`
-	PrometheusProcessMetricsEnabled   bool   `config:"bool;true"`
-	PrometheusWireGuardMetricsEnabled bool   `config:"bool;true"`
+	PrometheusMetricsEnabled            bool   `config:"bool;false"`
+	PrometheusMetricsHost               string `config:"host-address;"`
+	PrometheusMetricsPort               int    `config:"int(0:65535);9091"`
+	PrometheusGoMetricsEnabled          bool   `config:"bool;true"`
+	PrometheusProcessMetricsEnabled     bool   `config:"bool;true"`
+	PrometheusWireGuardMetricsEnabled   bool   `config:"bool;true"`
+	PrometheusBPFWorkloadMetricsEnabled bool   `config:"bool;false"`
 
 	FailsafeInboundHostPorts  []ProtoPort `config:"port-list;tcp:22,udp:68,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:6666,tcp:6667;die-on-fail"`
 	FailsafeOutboundHostPorts []ProtoPort `config:"port-list;udp:53,udp:67,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:6666,tcp:6667;die-on-fail"`
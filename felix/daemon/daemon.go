@@ -511,6 +511,7 @@ configRetry:
 				CAFile:       configParams.TyphaCAFile,
 				ServerCN:     configParams.TyphaCN,
 				ServerURISAN: configParams.TyphaURISAN,
+				ScopeToNode:  configParams.TyphaNodeScopedView,
 			},
 		)
 	} else {
@@ -681,6 +682,17 @@ configRetry:
 		go policySyncAPIBinder.SearchAndBind(sc)
 	}
 
+	if stoppable, ok := dpDriver.(interface{ Stop() }); ok {
+		// The dataplane runs in this process, let it clean up when we shut down.
+		sc := make(chan *sync.WaitGroup)
+		stopSignalChans = append(stopSignalChans, sc)
+		go func() {
+			stopWG := <-sc
+			stoppable.Stop()
+			stopWG.Done()
+		}()
+	}
+
 	// Send the opening message to the dataplane driver, giving it its
 	// config.
 	dpConnector.ToDataplane <- configParams.ToConfigUpdate()
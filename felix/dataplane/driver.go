@@ -384,6 +384,16 @@ func StartDataplaneDriver(
 			MTUIfacePattern:                    configParams.MTUIfacePattern,
 			BPFExcludeCIDRsFromNAT:             configParams.BPFExcludeCIDRsFromNAT,
 			BPFRedirectToPeer:                  configParams.BPFRedirectToPeer,
+			BPFEventsSampleRate:                configParams.BPFEventsSampleRate,
+			BPFEgressBandwidthFQEnabled:        configParams.BPFEgressBandwidthFQEnabled,
+			BPFXDPDDoSProtection:               configParams.BPFXDPDDoSProtection == "Enabled",
+			BPFXDPSYNRateLimit:                 configParams.BPFXDPSYNRateLimit,
+			BPFXDPUDPRateLimit:                 configParams.BPFXDPUDPRateLimit,
+			BPFXDPSYNCookies:                   configParams.BPFXDPSYNCookies == "Enabled",
+			BPFXDPRateLimitPrefixLengthV4:      configParams.BPFXDPRateLimitPrefixLengthV4,
+			BPFXDPRateLimitPrefixLengthV6:      configParams.BPFXDPRateLimitPrefixLengthV6,
+			BPFXDPBlocklistSelector:            configParams.BPFXDPBlocklistSelector,
+			BPFWorkloadMetricsEnabled:          configParams.PrometheusBPFWorkloadMetricsEnabled,
 			ServiceLoopPrevention:              configParams.ServiceLoopPrevention,
 
 			KubeClientSet: k8sClientSet,
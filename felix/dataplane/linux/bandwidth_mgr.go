@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/projectcalico/calico/felix/dataplane/linux/qos"
+	"github.com/projectcalico/calico/felix/ifacemonitor"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+type bandwidthLimits struct {
+	ingress, egress int64
+}
+
+// bandwidthManager limits the bandwidth of the workloads that have bandwidth limits, using tc
+// qdiscs on their interfaces. It is only used with the iptables dataplane, the BPF dataplane
+// limits the bandwidth in its programs.
+type bandwidthManager struct {
+	// wlIfaces maps the workloads to their interface names.
+	wlIfaces map[proto.WorkloadEndpointID]string
+	// desired and programmed are the limits by interface name.
+	desired    map[string]bandwidthLimits
+	programmed map[string]bandwidthLimits
+	dirty      set.Set[string]
+
+	ifbsCleanedUp bool
+
+	// Shims for the qos package.
+	setIngressLimit func(iface string, rate int64) error
+	setEgressLimit  func(iface string, rate int64) error
+	cleanUpIfbs     func(wlIfaces map[string]bool) error
+}
+
+func newBandwidthManager() *bandwidthManager {
+	return newBandwidthManagerWithShims(qos.SetIngressLimit, qos.SetEgressLimit, qos.CleanUpIfbs)
+}
+
+func newBandwidthManagerWithShims(
+	setIngressLimit func(iface string, rate int64) error,
+	setEgressLimit func(iface string, rate int64) error,
+	cleanUpIfbs func(wlIfaces map[string]bool) error,
+) *bandwidthManager {
+	return &bandwidthManager{
+		wlIfaces:        map[proto.WorkloadEndpointID]string{},
+		desired:         map[string]bandwidthLimits{},
+		programmed:      map[string]bandwidthLimits{},
+		dirty:           set.New[string](),
+		setIngressLimit: setIngressLimit,
+		setEgressLimit:  setEgressLimit,
+		cleanUpIfbs:     cleanUpIfbs,
+	}
+}
+
+func (m *bandwidthManager) OnUpdate(protoBufMsg interface{}) {
+	switch msg := protoBufMsg.(type) {
+	case *proto.WorkloadEndpointUpdate:
+		m.removeWorkload(*msg.Id)
+		ep := msg.Endpoint
+		m.wlIfaces[*msg.Id] = ep.Name
+		if ep.IngressBandwidth != 0 || ep.EgressBandwidth != 0 {
+			m.desired[ep.Name] = bandwidthLimits{ingress: ep.IngressBandwidth, egress: ep.EgressBandwidth}
+		}
+		m.dirty.Add(ep.Name)
+	case *proto.WorkloadEndpointRemove:
+		m.removeWorkload(*msg.Id)
+	case *ifaceStateUpdate:
+		switch msg.State {
+		case ifacemonitor.StateUp:
+			// The interface may be new, with none of the qdiscs. Setting the limits again
+			// is harmless if it is not.
+			if _, ok := m.desired[msg.Name]; ok {
+				delete(m.programmed, msg.Name)
+				m.dirty.Add(msg.Name)
+			}
+		case ifacemonitor.StateNotPresent:
+			// The qdiscs are gone with the interface, but not the ifb.
+			if l, ok := m.programmed[msg.Name]; ok {
+				l.ingress = 0
+				m.programmed[msg.Name] = l
+			}
+		}
+	}
+}
+
+func (m *bandwidthManager) removeWorkload(id proto.WorkloadEndpointID) {
+	iface, ok := m.wlIfaces[id]
+	if !ok {
+		return
+	}
+	delete(m.wlIfaces, id)
+	delete(m.desired, iface)
+	m.dirty.Add(iface)
+}
+
+func (m *bandwidthManager) CompleteDeferredWork() error {
+	var lastErr error
+
+	if !m.ifbsCleanedUp {
+		// Remove the ifb devices of the workloads that went away while we were not running.
+		wanted := map[string]bool{}
+		for iface, l := range m.desired {
+			if l.egress != 0 {
+				wanted[iface] = true
+			}
+		}
+		if err := m.cleanUpIfbs(wanted); err != nil {
+			log.WithError(err).Warn("Failed to clean up bandwidth limiting devices.")
+			lastErr = err
+		} else {
+			m.ifbsCleanedUp = true
+		}
+	}
+
+	m.dirty.Iter(func(iface string) error {
+		if err := m.apply(iface); err != nil {
+			log.WithError(err).WithField("iface", iface).Warn("Failed to set bandwidth limits, will retry.")
+			lastErr = err
+			return nil
+		}
+		return set.RemoveItem
+	})
+
+	return lastErr
+}
+
+func (m *bandwidthManager) apply(iface string) error {
+	want := m.desired[iface]
+	have := m.programmed[iface]
+	var lnf netlink.LinkNotFoundError
+
+	if want.ingress != have.ingress {
+		err := m.setIngressLimit(iface, want.ingress)
+		if errors.As(err, &lnf) {
+			// The limit is set when the interface shows up.
+			log.WithField("iface", iface).Debug("Interface not present, deferring bandwidth limit.")
+			err = nil
+			want.ingress = 0
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set ingress bandwidth limit: %w", err)
+		}
+		have.ingress = want.ingress
+	}
+
+	if want.egress != have.egress {
+		err := m.setEgressLimit(iface, want.egress)
+		if errors.As(err, &lnf) {
+			log.WithField("iface", iface).Debug("Interface not present, deferring bandwidth limit.")
+			err = nil
+			want.egress = 0
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set egress bandwidth limit: %w", err)
+		}
+		have.egress = want.egress
+	}
+
+	if have == (bandwidthLimits{}) {
+		delete(m.programmed, iface)
+	} else {
+		m.programmed[iface] = have
+	}
+	log.WithFields(log.Fields{
+		"iface":   iface,
+		"ingress": have.ingress,
+		"egress":  have.egress,
+	}).Debug("Bandwidth limits set.")
+	return nil
+}
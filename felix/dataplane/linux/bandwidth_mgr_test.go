@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+
+	"github.com/projectcalico/calico/felix/ifacemonitor"
+	"github.com/projectcalico/calico/felix/proto"
+)
+
+type mockQoS struct {
+	ingress, egress map[string]int64
+	cleanedUp       map[string]bool
+	failures        int
+	missing         map[string]bool
+}
+
+func newMockQoS() *mockQoS {
+	return &mockQoS{
+		ingress: map[string]int64{},
+		egress:  map[string]int64{},
+		missing: map[string]bool{},
+	}
+}
+
+func (q *mockQoS) set(limits map[string]int64) func(string, int64) error {
+	return func(iface string, rate int64) error {
+		if q.failures > 0 {
+			q.failures--
+			return errors.New("dummy failure")
+		}
+		if q.missing[iface] {
+			return netlink.LinkNotFoundError{}
+		}
+		if rate == 0 {
+			delete(limits, iface)
+		} else {
+			limits[iface] = rate
+		}
+		return nil
+	}
+}
+
+func (q *mockQoS) cleanUp(wanted map[string]bool) error {
+	q.cleanedUp = wanted
+	return nil
+}
+
+var _ = Describe("Bandwidth manager", func() {
+	var (
+		mgr *bandwidthManager
+		qos *mockQoS
+	)
+
+	wlID := proto.WorkloadEndpointID{
+		OrchestratorId: "k8s",
+		WorkloadId:     "default/pod",
+		EndpointId:     "eth0",
+	}
+
+	wlUpdate := func(iface string, ingress, egress int64) *proto.WorkloadEndpointUpdate {
+		return &proto.WorkloadEndpointUpdate{
+			Id: &wlID,
+			Endpoint: &proto.WorkloadEndpoint{
+				Name:             iface,
+				IngressBandwidth: ingress,
+				EgressBandwidth:  egress,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		qos = newMockQoS()
+		mgr = newBandwidthManagerWithShims(qos.set(qos.ingress), qos.set(qos.egress), qos.cleanUp)
+	})
+
+	It("should clean up the unused ifbs on the first apply", func() {
+		mgr.OnUpdate(wlUpdate("cali1", 0, 2000))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.cleanedUp).To(Equal(map[string]bool{"cali1": true}))
+	})
+
+	It("should set, change and remove the limits", func() {
+		mgr.OnUpdate(wlUpdate("cali1", 1000, 2000))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(Equal(map[string]int64{"cali1": 1000}))
+		Expect(qos.egress).To(Equal(map[string]int64{"cali1": 2000}))
+
+		mgr.OnUpdate(wlUpdate("cali1", 0, 3000))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(BeEmpty())
+		Expect(qos.egress).To(Equal(map[string]int64{"cali1": 3000}))
+
+		mgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: &wlID})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(BeEmpty())
+		Expect(qos.egress).To(BeEmpty())
+	})
+
+	It("should move the limits when the interface changes", func() {
+		mgr.OnUpdate(wlUpdate("cali1", 1000, 0))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		mgr.OnUpdate(wlUpdate("cali2", 1000, 0))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(Equal(map[string]int64{"cali2": 1000}))
+	})
+
+	It("should retry after a failure", func() {
+		mgr.OnUpdate(wlUpdate("cali1", 1000, 0))
+		qos.failures = 1
+		Expect(mgr.CompleteDeferredWork()).NotTo(Succeed())
+		Expect(qos.ingress).To(BeEmpty())
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(Equal(map[string]int64{"cali1": 1000}))
+	})
+
+	It("should set the limits when the interface comes up", func() {
+		qos.missing["cali1"] = true
+		mgr.OnUpdate(wlUpdate("cali1", 1000, 0))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(BeEmpty())
+
+		delete(qos.missing, "cali1")
+		mgr.OnUpdate(&ifaceStateUpdate{Name: "cali1", State: ifacemonitor.StateUp, Index: 5})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(Equal(map[string]int64{"cali1": 1000}))
+	})
+
+	It("should reprogram the ingress limit of a recreated interface", func() {
+		mgr.OnUpdate(wlUpdate("cali1", 1000, 0))
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+
+		// Simulate the qdisc going away with the interface.
+		delete(qos.ingress, "cali1")
+		mgr.OnUpdate(&ifaceStateUpdate{Name: "cali1", State: ifacemonitor.StateNotPresent})
+		mgr.OnUpdate(&ifaceStateUpdate{Name: "cali1", State: ifacemonitor.StateUp, Index: 6})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(qos.ingress).To(Equal(map[string]int64{"cali1": 1000}))
+	})
+})
@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	bpfddos "github.com/projectcalico/calico/felix/bpf/ddos"
+	"github.com/projectcalico/calico/felix/bpf/maps"
+	"github.com/projectcalico/calico/felix/cachingmap"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+	"github.com/projectcalico/calico/libcalico-go/lib/set"
+)
+
+// tcpSYNCookiesPath is the sysctl that the kernel checks before it generates a SYN cookie for
+// XDP, and before it accepts the ACK that returns the cookie.
+const tcpSYNCookiesPath = "/proc/sys/net/ipv4/tcp_syncookies"
+
+// bpfDDoSManager programs the DDoS protection of the XDP programs of the host endpoints: its
+// configuration, and the blocklist, which holds the members of the blocklist IP set.  The
+// calculation graph fills that IP set with the nets of the GlobalNetworkSets that the blocklist
+// selector matches.
+type bpfDDoSManager struct {
+	config    bpfddos.Config
+	configMap maps.Map
+	configSet bool
+
+	readProcSys  func(path string) ([]byte, error)
+	writeProcSys procSysWriter
+
+	// blocklistSetID is the ID of the blocklist IP set, "" if there is no blocklist.
+	blocklistSetID string
+	members        set.Set[string]
+	dirty          bool
+
+	blocklistV4 *cachingmap.CachingMap[bpfddos.BlocklistKey, bpfddos.BlocklistValue]
+	blocklistV6 *cachingmap.CachingMap[bpfddos.BlocklistKeyV6, bpfddos.BlocklistValue]
+}
+
+func newBPFDDoSManager(config *Config, bpfmaps *bpfmap.Maps) *bpfDDoSManager {
+	m := &bpfDDoSManager{
+		config: bpfddos.Config{
+			Enabled:     config.BPFXDPDDoSProtection,
+			SYNCookies:  config.BPFXDPSYNCookies,
+			SYNRate:     uint32(config.BPFXDPSYNRateLimit),
+			UDPRate:     uint32(config.BPFXDPUDPRateLimit),
+			PrefixLenV4: uint8(config.BPFXDPRateLimitPrefixLengthV4),
+			PrefixLenV6: uint8(config.BPFXDPRateLimitPrefixLengthV6),
+		},
+		configMap:    bpfmaps.CommonMaps.DDoSConfigMap,
+		readProcSys:  os.ReadFile,
+		writeProcSys: writeProcSys,
+		members:      set.New[string](),
+		// Clean up the blocklist of a previous configuration.
+		dirty: true,
+		blocklistV4: cachingmap.New[bpfddos.BlocklistKey, bpfddos.BlocklistValue](
+			bpfddos.BlocklistMapParameters.Name,
+			maps.NewTypedMap[bpfddos.BlocklistKey, bpfddos.BlocklistValue](
+				bpfmaps.V4.BlocklistMap.(maps.MapWithExistsCheck), bpfddos.BlocklistKeyFromBytes, bpfddos.BlocklistValueFromBytes,
+			)),
+	}
+
+	if bpfmaps.V6 != nil {
+		m.blocklistV6 = cachingmap.New[bpfddos.BlocklistKeyV6, bpfddos.BlocklistValue](
+			bpfddos.BlocklistMapV6Parameters.Name,
+			maps.NewTypedMap[bpfddos.BlocklistKeyV6, bpfddos.BlocklistValue](
+				bpfmaps.V6.BlocklistMap.(maps.MapWithExistsCheck), bpfddos.BlocklistKeyV6FromBytes, bpfddos.BlocklistValueFromBytes,
+			))
+	}
+
+	if config.BPFXDPDDoSProtection && config.BPFXDPBlocklistSelector != "" {
+		if _, err := selector.Parse(config.BPFXDPBlocklistSelector); err != nil {
+			log.WithError(err).WithField("selector", config.BPFXDPBlocklistSelector).Error(
+				"Invalid BPFXDPBlocklistSelector, the blocklist is empty.")
+		} else {
+			m.blocklistSetID = rules.IPSetIDXDPBlocklist
+		}
+	}
+
+	return m
+}
+
+func (m *bpfDDoSManager) OnUpdate(msg interface{}) {
+	if m.blocklistSetID == "" {
+		return
+	}
+
+	switch msg := msg.(type) {
+	case *proto.IPSetUpdate:
+		if msg.Id != m.blocklistSetID {
+			return
+		}
+		m.members = set.FromArray(msg.Members)
+		m.dirty = true
+	case *proto.IPSetDeltaUpdate:
+		if msg.Id != m.blocklistSetID {
+			return
+		}
+		for _, member := range msg.RemovedMembers {
+			m.members.Discard(member)
+		}
+		for _, member := range msg.AddedMembers {
+			m.members.Add(member)
+		}
+		m.dirty = true
+	case *proto.IPSetRemove:
+		if msg.Id != m.blocklistSetID {
+			return
+		}
+		m.members.Clear()
+		m.dirty = true
+	}
+}
+
+func (m *bpfDDoSManager) CompleteDeferredWork() error {
+	if !m.configSet {
+		if m.config.Enabled && m.config.SYNCookies {
+			if err := m.ensureSYNCookies(); err != nil {
+				return err
+			}
+		}
+		if err := bpfddos.SetConfig(m.configMap, m.config); err != nil {
+			return fmt.Errorf("failed to configure the XDP DDoS protection: %w", err)
+		}
+		m.configSet = true
+	}
+
+	if !m.dirty {
+		return nil
+	}
+
+	m.blocklistV4.Desired().DeleteAll()
+	if m.blocklistV6 != nil {
+		m.blocklistV6.Desired().DeleteAll()
+	}
+	m.members.Iter(func(member string) error {
+		cidr, err := ip.ParseCIDROrIP(member)
+		if err != nil {
+			log.WithError(err).WithField("member", member).Warn("Ignoring invalid blocklist member.")
+			return nil
+		}
+		if cidr.Version() == 4 {
+			m.blocklistV4.Desired().Set(bpfddos.NewBlocklistKey(cidr), bpfddos.BlocklistValue{})
+		} else if m.blocklistV6 != nil {
+			m.blocklistV6.Desired().Set(bpfddos.NewBlocklistKeyV6(cidr), bpfddos.BlocklistValue{})
+		}
+		return nil
+	})
+
+	var errs []error
+	if err := m.blocklistV4.ApplyAllChanges(); err != nil {
+		errs = append(errs, err)
+	}
+	if m.blocklistV6 != nil {
+		if err := m.blocklistV6.ApplyAllChanges(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to update the XDP DDoS blocklist: %w", err)
+	}
+
+	log.WithField("members", m.members.Len()).Debug("XDP DDoS blocklist updated.")
+	m.dirty = false
+	return nil
+}
+
+// ensureSYNCookies enables the kernel's SYN cookies if they are disabled, without them the kernel
+// neither generates the cookies for XDP nor accepts the ACKs that return them.  Either of the
+// enabled modes, 1 and 2, works.
+func (m *bpfDDoSManager) ensureSYNCookies() error {
+	v, err := m.readProcSys(tcpSYNCookiesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", tcpSYNCookiesPath, err)
+	}
+	if strings.TrimSpace(string(v)) != "0" {
+		return nil
+	}
+	log.Info("Enabling TCP SYN cookies for the XDP DDoS protection.")
+	if err := m.writeProcSys(tcpSYNCookiesPath, "1"); err != nil {
+		return fmt.Errorf("failed to enable TCP SYN cookies: %w", err)
+	}
+	return nil
+}
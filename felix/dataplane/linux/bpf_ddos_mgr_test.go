@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intdataplane
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/felix/bpf/bpfmap"
+	bpfddos "github.com/projectcalico/calico/felix/bpf/ddos"
+	"github.com/projectcalico/calico/felix/bpf/mock"
+	"github.com/projectcalico/calico/felix/ip"
+	"github.com/projectcalico/calico/felix/proto"
+	"github.com/projectcalico/calico/felix/rules"
+)
+
+var _ = Describe("BPF DDoS manager", func() {
+	var (
+		mgr                *bpfDDoSManager
+		cfgMap, blV4, blV6 *mock.Map
+		config             Config
+		setID              string
+	)
+
+	blocklist := func() []string {
+		var cidrs []string
+		for k := range blV4.Contents {
+			cidrs = append(cidrs, bpfddos.BlocklistKeyFromBytes([]byte(k)).String())
+		}
+		for k := range blV6.Contents {
+			cidrs = append(cidrs, bpfddos.BlocklistKeyV6FromBytes([]byte(k)).String())
+		}
+		return cidrs
+	}
+
+	BeforeEach(func() {
+		cfgMap = mock.NewMockMap(bpfddos.ConfigMapParameters)
+		blV4 = mock.NewMockMap(bpfddos.BlocklistMapParameters)
+		blV6 = mock.NewMockMap(bpfddos.BlocklistMapV6Parameters)
+		config = Config{
+			BPFXDPDDoSProtection:          true,
+			BPFXDPSYNRateLimit:            100,
+			BPFXDPRateLimitPrefixLengthV4: 24,
+			BPFXDPRateLimitPrefixLengthV6: 64,
+			BPFXDPBlocklistSelector:       "ddos-blocklist == 'true'",
+		}
+		setID = rules.IPSetIDXDPBlocklist
+	})
+
+	JustBeforeEach(func() {
+		mgr = newBPFDDoSManager(&config, &bpfmap.Maps{
+			CommonMaps: &bpfmap.CommonMaps{DDoSConfigMap: cfgMap},
+			V4:         &bpfmap.IPMaps{BlocklistMap: blV4},
+			V6:         &bpfmap.IPMaps{BlocklistMap: blV6},
+		})
+	})
+
+	It("should write the config", func() {
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(bpfddos.ReadConfig(cfgMap)).To(Equal(bpfddos.Config{
+			Enabled:     true,
+			SYNRate:     100,
+			PrefixLenV4: 24,
+			PrefixLenV6: 64,
+		}))
+	})
+
+	It("should mirror the members of the blocklist IP set", func() {
+		mgr.OnUpdate(&proto.IPSetUpdate{Id: setID, Members: []string{"10.0.0.0/24", "dead:beef::1/128"}})
+		mgr.OnUpdate(&proto.IPSetUpdate{Id: "other", Members: []string{"10.1.0.0/16"}})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(blocklist()).To(ConsistOf("10.0.0.0/24", "dead:beef::1/128"))
+
+		mgr.OnUpdate(&proto.IPSetDeltaUpdate{
+			Id:             setID,
+			AddedMembers:   []string{"192.168.1.1"},
+			RemovedMembers: []string{"10.0.0.0/24"},
+		})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(blocklist()).To(ConsistOf("192.168.1.1/32", "dead:beef::1/128"))
+
+		mgr.OnUpdate(&proto.IPSetRemove{Id: setID})
+		Expect(mgr.CompleteDeferredWork()).To(Succeed())
+		Expect(blocklist()).To(BeEmpty())
+	})
+
+	Context("with stale entries and the protection disabled", func() {
+		BeforeEach(func() {
+			config.BPFXDPDDoSProtection = false
+			k := bpfddos.NewBlocklistKey(ip.MustParseCIDROrIP("10.0.0.0/24"))
+			Expect(blV4.Update(k.AsBytes(), bpfddos.BlocklistValue{}.AsBytes())).To(Succeed())
+		})
+
+		It("should clean up the blocklist", func() {
+			mgr.OnUpdate(&proto.IPSetUpdate{Id: setID, Members: []string{"10.1.0.0/16"}})
+			Expect(mgr.CompleteDeferredWork()).To(Succeed())
+			Expect(blocklist()).To(BeEmpty())
+			Expect(bpfddos.ReadConfig(cfgMap)).To(Equal(bpfddos.Config{
+				SYNRate:     100,
+				PrefixLenV4: 24,
+				PrefixLenV6: 64,
+			}))
+		})
+	})
+
+	Context("with SYN cookies", func() {
+		var sysctl string
+
+		BeforeEach(func() {
+			config.BPFXDPSYNCookies = true
+			sysctl = "0\n"
+		})
+
+		JustBeforeEach(func() {
+			mgr.readProcSys = func(path string) ([]byte, error) {
+				Expect(path).To(Equal(tcpSYNCookiesPath))
+				return []byte(sysctl), nil
+			}
+			mgr.writeProcSys = func(path, value string) error {
+				Expect(path).To(Equal(tcpSYNCookiesPath))
+				sysctl = value
+				return nil
+			}
+		})
+
+		It("should enable the kernel's SYN cookies", func() {
+			Expect(mgr.CompleteDeferredWork()).To(Succeed())
+			Expect(sysctl).To(Equal("1"))
+			Expect(bpfddos.ReadConfig(cfgMap)).To(HaveField("SYNCookies", BeTrue()))
+		})
+
+		It("should leave the kernel's SYN cookies alone if they are enabled", func() {
+			sysctl = "2\n"
+			Expect(mgr.CompleteDeferredWork()).To(Succeed())
+			Expect(sysctl).To(Equal("2\n"))
+		})
+	})
+})
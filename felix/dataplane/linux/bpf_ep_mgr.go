@@ -60,11 +60,13 @@ import (
 	"github.com/projectcalico/calico/felix/bpf/libbpf"
 	"github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/polprog"
+	bpfqos "github.com/projectcalico/calico/felix/bpf/qos"
 	"github.com/projectcalico/calico/felix/bpf/tc"
 	tcdefs "github.com/projectcalico/calico/felix/bpf/tc/defs"
 	"github.com/projectcalico/calico/felix/bpf/xdp"
 	"github.com/projectcalico/calico/felix/cachingmap"
 	"github.com/projectcalico/calico/felix/dataplane/linux/dataplanedefs"
+	"github.com/projectcalico/calico/felix/dataplane/linux/qos"
 	"github.com/projectcalico/calico/felix/environment"
 	"github.com/projectcalico/calico/felix/ethtool"
 	"github.com/projectcalico/calico/felix/generictables"
@@ -177,6 +179,8 @@ type bpfDataplane interface {
 	interfaceByIndex(int) (*net.Interface, error)
 	queryClassifier(int, int, int, bool) (int, error)
 	getIfaceLink(string) (netlink.Link, error)
+	ensureFQ(iface string) ([]netlink.Qdisc, error)
+	restoreQdiscs(iface string, replaced []netlink.Qdisc) error
 }
 
 type hasLoadPolicyProgram interface {
@@ -306,6 +310,17 @@ type bpfEndpointManager struct {
 	removeOldJumps          bool
 	legacyCleanUp           bool
 
+	// qosMap holds the bandwidth limits of the workloads, qosDirty is set when they may have
+	// changed. fqIfaces are the interfaces that we gave an fq qdisc to pace the packets, with the
+	// qdiscs that it replaced. The data interfaces only get fq if egressBandwidthFQ is set.
+	qosMap            *cachingmap.CachingMap[bpfqos.Key, bpfqos.Value]
+	qosDirty          bool
+	fqIfaces          map[string][]netlink.Qdisc
+	egressBandwidthFQ bool
+
+	// countersCollector exports the BPF counters as Prometheus metrics.
+	countersCollector *counters.Collector
+
 	jumpMapAlloc     *jumpMapAlloc
 	xdpJumpMapAlloc  *jumpMapAlloc
 	policyDefaultObj *libbpf.Obj
@@ -343,6 +358,9 @@ type bpfEndpointManager struct {
 
 	// XDP
 	xdpModes []bpf.XDPMode
+	// xdpDDoSProtection is set when the XDP programs protect the host endpoints, which then
+	// need them even without untracked policy.
+	xdpDDoSProtection bool
 
 	// IPv6 Support
 	ipv6Enabled bool
@@ -498,10 +516,18 @@ func newBPFEndpointManager(
 		bpfExtToServiceConnmark: config.BPFExtToServiceConnmark,
 		psnatPorts:              config.BPFPSNATPorts,
 		commonMaps:              bpfmaps.CommonMaps,
+		countersCollector:       counters.NewCollector(bpfmaps.CommonMaps.CountersMap, config.BPFWorkloadMetricsEnabled),
 		ifStateMap: cachingmap.New[ifstate.Key, ifstate.Value](ifstate.MapParams.Name,
 			maps.NewTypedMap[ifstate.Key, ifstate.Value](
 				bpfmaps.CommonMaps.IfStateMap.(maps.MapWithExistsCheck), ifstate.KeyFromBytes, ifstate.ValueFromBytes,
 			)),
+		qosMap: cachingmap.New[bpfqos.Key, bpfqos.Value](bpfqos.MapParameters.Name,
+			maps.NewTypedMap[bpfqos.Key, bpfqos.Value](
+				bpfmaps.CommonMaps.QoSMap.(maps.MapWithExistsCheck), bpfqos.KeyFromBytes, bpfqos.ValueFromBytes,
+			)),
+		qosDirty:          true,
+		fqIfaces:          map[string][]netlink.Qdisc{},
+		egressBandwidthFQ: config.BPFEgressBandwidthFQEnabled,
 
 		// Note: the allocators only allocate a fraction of the map, the
 		// rest is reserved for sub-programs generated if a single program
@@ -521,6 +547,7 @@ func newBPFEndpointManager(
 		bpfDisableGROForIfaces: config.BPFDisableGROForIfaces,
 		bpfPolicyDebugEnabled:  config.BPFPolicyDebugEnabled,
 		bpfRedirectToPeer:      config.BPFRedirectToPeer,
+		xdpDDoSProtection:      config.BPFXDPDDoSProtection,
 		polNameToMatchIDs:      map[string]set.Set[polprog.RuleMatchID]{},
 		dirtyRules:             set.New[polprog.RuleMatchID](),
 
@@ -767,6 +794,10 @@ func (m *bpfEndpointManager) withIface(ifaceName string, fn func(iface *bpfInter
 		m.nameToIface[ifaceName] = iface
 	}
 
+	if !reflect.DeepEqual(iface.info.endpointID, ifaceCopy.info.endpointID) {
+		m.countersCollector.SetWorkload(ifaceName, iface.info.endpointID)
+	}
+
 	dirty = dirty || iface.info != ifaceCopy.info
 
 	if !dirty {
@@ -1103,7 +1134,11 @@ func (m *bpfEndpointManager) onInterfaceUpdate(update *ifaceStateUpdate) {
 		if err := bpf.ForgetIfaceAttachedProg(update.Name); err != nil {
 			log.WithError(err).Errorf("Error in removing interface %s json file. err=%v", update.Name, err)
 		}
+		delete(m.fqIfaces, update.Name)
 	}
+	// The bandwidth limits are keyed on the ifindex of the workload interfaces and
+	// need fq on the data interfaces.
+	m.qosDirty = true
 
 	if !m.isDataIface(update.Name) && !m.isWorkloadIface(update.Name) && !m.isL3Iface(update.Name) {
 		if update.State == ifacemonitor.StateUp {
@@ -1275,6 +1310,7 @@ func (m *bpfEndpointManager) onWorkloadEndpointUpdate(msg *proto.WorkloadEndpoin
 		iface.info.endpointID = &wlID
 		return true // Force interface to be marked dirty in case policies changed.
 	})
+	m.qosDirty = true
 }
 
 // onWorkloadEndpointRemove removes the workload from the cache and the index, which maps from policy to workload.
@@ -1294,6 +1330,7 @@ func (m *bpfEndpointManager) onWorkloadEndpointRemove(msg *proto.WorkloadEndpoin
 		iface.info.endpointID = nil
 		return false
 	})
+	m.qosDirty = true
 	// Remove policy debug info if any
 	m.removeIfaceAllPolicyDebugInfo(oldWEP.Name)
 }
@@ -1708,6 +1745,12 @@ func (m *bpfEndpointManager) CompleteDeferredWork() error {
 		m.happyWEPsDirty = false
 	}
 	bpfHappyEndpointsGauge.Set(float64(len(m.happyWEPs)))
+
+	if err := m.updateQoS(); err != nil {
+		log.WithError(err).Warn("Failed to update bandwidth limits BPF map.")
+		return err
+	}
+
 	// Copy data from old map to the new map
 	m.copyDeltaOnce.Do(func() {
 		log.Info("Copy delta entries from old map to the new map")
@@ -1843,7 +1886,7 @@ func (m *bpfEndpointManager) doApplyPolicyToDataIface(iface, masterIface string,
 		defer parallelWG.Done()
 		xdpAP := mergeAttachPoints(xdpAP4, xdpAP6)
 		if xdpAP != nil {
-			if hepPtr != nil && len(hepPtr.UntrackedTiers) == 1 {
+			if m.hepNeedsXDP(hepPtr) {
 				_, xdpErr = m.dp.ensureProgramAttached(xdpAP)
 			} else {
 				xdpErr = m.dp.ensureNoProgram(xdpAP)
@@ -2085,6 +2128,105 @@ func (m *bpfEndpointManager) updateWEPsInDataplane() {
 	}
 }
 
+// updateQoS writes the bandwidth limits of the workloads to the BPF map and makes sure that the
+// interfaces that the limited packets leave by have fq qdiscs, which respect the departure time
+// that the BPF programs set.
+func (m *bpfEndpointManager) updateQoS() error {
+	if !m.qosDirty {
+		return nil
+	}
+
+	m.ifacesLock.Lock()
+	defer m.ifacesLock.Unlock()
+
+	wantFQ := set.New[string]()
+	ensureFQ := func(iface string) {
+		wantFQ.Add(iface)
+		if _, ok := m.fqIfaces[iface]; ok {
+			return
+		}
+		replaced, err := m.dp.ensureFQ(iface)
+		if err != nil {
+			// Not fatal, the packets are then only limited by the drop horizon.
+			if !isLinkNotFoundError(err) {
+				log.WithError(err).WithField("iface", iface).Warn("Failed to add fq qdisc for bandwidth limiting.")
+			}
+			return
+		}
+		m.fqIfaces[iface] = replaced
+	}
+
+	// The BPF programs keep the departure time of the last packet in the same value as the
+	// limit. Reload the map so that we compare only the limits and keep the departure times,
+	// rewriting them would let a workload burst after every resync.
+	if err := m.qosMap.LoadCacheFromDataplane(); err != nil {
+		return err
+	}
+	setLimit := func(k bpfqos.Key, rateBits int64) {
+		v := bpfqos.NewValue(rateBits)
+		if cur, ok := m.qosMap.Dataplane().Get(k); ok {
+			v = cur.WithRate(rateBits)
+		}
+		m.qosMap.Desired().Set(k, v)
+	}
+
+	m.qosMap.Desired().DeleteAll()
+	egressLimited := false
+	for _, wep := range m.allWEPs {
+		if wep.IngressBandwidth == 0 && wep.EgressBandwidth == 0 {
+			continue
+		}
+		iface, ok := m.nameToIface[wep.Name]
+		if !ok || iface.info.ifIndex == 0 {
+			// We are called again when the interface shows up.
+			continue
+		}
+		ifindex := uint32(iface.info.ifIndex)
+		if wep.IngressBandwidth != 0 {
+			setLimit(bpfqos.NewKey(ifindex, false), wep.IngressBandwidth)
+			ensureFQ(wep.Name)
+		}
+		if wep.EgressBandwidth != 0 {
+			setLimit(bpfqos.NewKey(ifindex, true), wep.EgressBandwidth)
+			egressLimited = true
+		}
+	}
+
+	if egressLimited && m.egressBandwidthFQ {
+		for name, iface := range m.nameToIface {
+			if !iface.info.isUP || !m.isDataIface(name) || name == dataplanedefs.BPFOutDev || name == "lo" {
+				continue
+			}
+			// The packets queue on the slaves of a bond, and the tunnels hand their packets
+			// on to a data interface.
+			switch iface.info.ifaceType {
+			case IfaceTypeData, IfaceTypeL3, IfaceTypeBondSlave:
+			default:
+				continue
+			}
+			ensureFQ(name)
+		}
+	}
+
+	// Give the interfaces that no longer need fq their qdiscs back.
+	for iface, replaced := range m.fqIfaces {
+		if wantFQ.Contains(iface) {
+			continue
+		}
+		if err := m.dp.restoreQdiscs(iface, replaced); err != nil {
+			log.WithError(err).WithField("iface", iface).Warn("Failed to restore qdisc after bandwidth limiting.")
+			continue
+		}
+		delete(m.fqIfaces, iface)
+	}
+
+	if err := m.qosMap.ApplyAllChanges(); err != nil {
+		return err
+	}
+	m.qosDirty = false
+	return nil
+}
+
 func (m *bpfEndpointManager) allocJumpIndicesForWEP(ifaceName string, idx *bpfInterfaceJumpIndices) error {
 	var err error
 	if idx.policyIdx[hook.Ingress] == -1 {
@@ -2764,7 +2906,7 @@ func (d *bpfEndpointManagerDataplane) attachXDPProgram(ap *xdp.AttachPoint, ep *
 	}
 
 	m := d.mgr
-	if ep != nil && len(ep.UntrackedTiers) == 1 {
+	if m.hepNeedsXDP(ep) {
 		err := m.dp.ensureProgramLoaded(ap, d.ipFamily)
 		if err != nil {
 			return nil, err
@@ -2773,9 +2915,11 @@ func (d *bpfEndpointManagerDataplane) attachXDPProgram(ap *xdp.AttachPoint, ep *
 		ap.Log().Infof("Building program for untracked policy hep=%v, family=%v", ep.Name, d.ipFamily)
 		rules := polprog.Rules{
 			ForHostInterface: true,
-			HostNormalTiers:  m.extractTiers(ep.UntrackedTiers, PolDirnIngress, false),
 			ForXDP:           true,
 		}
+		if len(ep.UntrackedTiers) == 1 {
+			rules.HostNormalTiers = m.extractTiers(ep.UntrackedTiers, PolDirnIngress, false)
+		}
 		ap.Log().Infof("Rules: %v", rules)
 		err = m.updatePolicyProgramFn(rules, "xdp", ap, d.ipFamily)
 		ap.Log().WithError(err).Debugf("Applied untracked policy hep=%v", ep.Name)
@@ -2784,6 +2928,13 @@ func (d *bpfEndpointManagerDataplane) attachXDPProgram(ap *xdp.AttachPoint, ep *
 	return ap, nil
 }
 
+// hepNeedsXDP returns true if the XDP program must be attached to the interfaces of the host
+// endpoint, either for its untracked policy or for the DDoS protection.  Without untracked
+// policy, the policy program passes all the packets.
+func (m *bpfEndpointManager) hepNeedsXDP(ep *proto.HostEndpoint) bool {
+	return ep != nil && (len(ep.UntrackedTiers) == 1 || m.xdpDDoSProtection)
+}
+
 // PolDirection is the Calico datamodel direction of policy.  On a host endpoint, ingress is towards the host.
 // On a workload endpoint, ingress is towards the workload.
 type PolDirection int
@@ -3285,6 +3436,14 @@ func (m *bpfEndpointManager) setAcceptLocal(iface string, val bool) error {
 	return nil
 }
 
+func (m *bpfEndpointManager) ensureFQ(iface string) ([]netlink.Qdisc, error) {
+	return qos.EnsureFQ(iface)
+}
+
+func (m *bpfEndpointManager) restoreQdiscs(iface string, replaced []netlink.Qdisc) error {
+	return qos.RestoreQdiscs(iface, replaced)
+}
+
 func (m *bpfEndpointManager) setRPFilter(iface string, val int) error {
 	// We only support IPv4 for now.
 	path := fmt.Sprintf("/proc/sys/net/ipv4/conf/%s/rp_filter", iface)
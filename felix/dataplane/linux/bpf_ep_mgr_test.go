@@ -44,6 +44,7 @@ import (
 	bpfmaps "github.com/projectcalico/calico/felix/bpf/maps"
 	"github.com/projectcalico/calico/felix/bpf/mock"
 	"github.com/projectcalico/calico/felix/bpf/polprog"
+	bpfqos "github.com/projectcalico/calico/felix/bpf/qos"
 	"github.com/projectcalico/calico/felix/bpf/state"
 	"github.com/projectcalico/calico/felix/bpf/tc"
 	"github.com/projectcalico/calico/felix/bpf/xdp"
@@ -69,6 +70,7 @@ type mockDataplane struct {
 	policy      map[string]polprog.Rules
 	routes      map[ip.CIDR]struct{}
 	netlinkShim netlinkshim.Interface
+	fqIfaces    set.Set[string]
 
 	ensureStartedFn    func()
 	ensureQdiscFn      func(string) (bool, error)
@@ -88,6 +90,7 @@ func newMockDataplane() *mockDataplane {
 		policy:      map[string]polprog.Rules{},
 		routes:      map[ip.CIDR]struct{}{},
 		netlinkShim: netlinkShim,
+		fqIfaces:    set.New[string](),
 	}
 }
 
@@ -186,6 +189,20 @@ func (m *mockDataplane) setRPFilter(iface string, val int) error {
 	return nil
 }
 
+func (m *mockDataplane) ensureFQ(iface string) ([]netlink.Qdisc, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fqIfaces.Add(iface)
+	return []netlink.Qdisc{&netlink.GenericQdisc{QdiscType: "noqueue"}}, nil
+}
+
+func (m *mockDataplane) restoreQdiscs(iface string, replaced []netlink.Qdisc) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.fqIfaces.Discard(iface)
+	return nil
+}
+
 func (m *mockDataplane) getIfaceLink(name string) (netlink.Link, error) {
 	link, err := m.netlinkShim.LinkByName(name)
 	if err != nil {
@@ -352,6 +369,7 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		countersMap          *mock.Map
 		jumpMap              *mock.Map
 		xdpJumpMap           *mock.Map
+		qosMap               *mock.Map
 	)
 
 	BeforeEach(func() {
@@ -403,6 +421,8 @@ var _ = Describe("BPF Endpoint Manager", func() {
 		commonMaps.JumpMap = jumpMap
 		xdpJumpMap = mock.NewMockMap(progsParams)
 		commonMaps.XDPJumpMap = xdpJumpMap
+		qosMap = mock.NewMockMap(bpfqos.MapParameters)
+		commonMaps.QoSMap = qosMap
 
 		maps.V4 = v4Maps
 		maps.V6 = v6Maps
@@ -741,6 +761,102 @@ var _ = Describe("BPF Endpoint Manager", func() {
 			Expect(dp.numOfAttaches("cali12345:ingress")).To(Equal(5))
 			Expect(dp.numOfAttaches("cali12345:egress")).To(Equal(5))
 		})
+
+		Context("with bandwidth limits", func() {
+			id := &proto.WorkloadEndpointID{
+				OrchestratorId: "k8s",
+				WorkloadId:     "cali12345",
+				EndpointId:     "cali12345",
+			}
+
+			addLimitedWEP := func() {
+				genIfaceUpdate("eth0", ifacemonitor.StateUp, 10)()
+				bpfEpMgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+					Id: id,
+					Endpoint: &proto.WorkloadEndpoint{
+						Name:             "cali12345",
+						IngressBandwidth: 8000000,
+						EgressBandwidth:  16000000,
+					},
+				})
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+			}
+
+			It("must program the bandwidth limits", func() {
+				addLimitedWEP()
+
+				v, err := qosMap.Get(bpfqos.NewKey(15, false).AsBytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bpfqos.ValueFromBytes(v).RateBytes()).To(Equal(uint64(1000000)))
+				v, err = qosMap.Get(bpfqos.NewKey(15, true).AsBytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bpfqos.ValueFromBytes(v).RateBytes()).To(Equal(uint64(2000000)))
+				Expect(dp.fqIfaces.Contains("cali12345")).To(BeTrue())
+				// The root qdisc of the host interfaces is left alone unless enabled.
+				Expect(dp.fqIfaces.Contains("eth0")).To(BeFalse())
+
+				bpfEpMgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: id})
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+				Expect(qosMap.IsEmpty()).To(BeTrue())
+				Expect(dp.fqIfaces.Len()).To(Equal(0))
+			})
+
+			It("must keep the departure times that the BPF programs maintain", func() {
+				addLimitedWEP()
+
+				// The BPF programs have paced some packets.
+				k := bpfqos.NewKey(15, false)
+				v, err := qosMap.Get(k.AsBytes())
+				Expect(err).NotTo(HaveOccurred())
+				binary.LittleEndian.PutUint64(v[8:16], 12345)
+				Expect(qosMap.Update(k.AsBytes(), v)).To(Succeed())
+
+				// A resync does not touch the value.
+				bpfEpMgr.qosDirty = true
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+				v, err = qosMap.Get(k.AsBytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bpfqos.ValueFromBytes(v).RateBytes()).To(Equal(uint64(1000000)))
+				Expect(bpfqos.ValueFromBytes(v).TLast()).To(Equal(uint64(12345)))
+
+				// Changing the limit keeps the departure time too.
+				bpfEpMgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+					Id: id,
+					Endpoint: &proto.WorkloadEndpoint{
+						Name:             "cali12345",
+						IngressBandwidth: 16000000,
+					},
+				})
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+				v, err = qosMap.Get(k.AsBytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bpfqos.ValueFromBytes(v).RateBytes()).To(Equal(uint64(2000000)))
+				Expect(bpfqos.ValueFromBytes(v).TLast()).To(Equal(uint64(12345)))
+			})
+
+			It("must replace and restore the root qdisc of the data interfaces if enabled", func() {
+				bpfEpMgr.egressBandwidthFQ = true
+				addLimitedWEP()
+				Expect(dp.fqIfaces.Contains("cali12345")).To(BeTrue())
+				Expect(dp.fqIfaces.Contains("eth0")).To(BeTrue())
+
+				// Without an egress limit, eth0 gets its qdisc back.
+				bpfEpMgr.OnUpdate(&proto.WorkloadEndpointUpdate{
+					Id: id,
+					Endpoint: &proto.WorkloadEndpoint{
+						Name:             "cali12345",
+						IngressBandwidth: 8000000,
+					},
+				})
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+				Expect(dp.fqIfaces.Contains("cali12345")).To(BeTrue())
+				Expect(dp.fqIfaces.Contains("eth0")).To(BeFalse())
+
+				bpfEpMgr.OnUpdate(&proto.WorkloadEndpointRemove{Id: id})
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+				Expect(dp.fqIfaces.Len()).To(Equal(0))
+			})
+		})
 	})
 
 	Context("Ifacetype detection", func() {
@@ -1061,6 +1177,16 @@ var _ = Describe("BPF Endpoint Manager", func() {
 
 				// Check no XDP.
 				Eventually(dp.setAndReturn(&eth0X, "eth0:xdp")).Should(BeNil())
+
+				By("enabling the DDoS protection")
+				bpfEpMgr.xdpDDoSProtection = true
+				bpfEpMgr.dirtyIfaceNames.Add("eth0")
+				Expect(bpfEpMgr.CompleteDeferredWork()).To(Succeed())
+
+				// Check XDP without policy.
+				Eventually(dp.setAndReturn(&eth0X, "eth0:xdp")).ShouldNot(BeNil())
+				Expect(eth0X.ForXDP).To(BeTrue())
+				Expect(eth0X.HostNormalTiers).To(BeEmpty())
 			})
 		})
 
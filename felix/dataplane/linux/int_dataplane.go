@@ -39,6 +39,7 @@ import (
 	"github.com/projectcalico/calico/felix/bpf"
 	"github.com/projectcalico/calico/felix/bpf/bpfmap"
 	bpfconntrack "github.com/projectcalico/calico/felix/bpf/conntrack"
+	bpfevents "github.com/projectcalico/calico/felix/bpf/events"
 	"github.com/projectcalico/calico/felix/bpf/failsafes"
 	bpfifstate "github.com/projectcalico/calico/felix/bpf/ifstate"
 	bpfipsets "github.com/projectcalico/calico/felix/bpf/ipsets"
@@ -229,6 +230,16 @@ type Config struct {
 	BPFDisableGROForIfaces             *regexp.Regexp
 	BPFExcludeCIDRsFromNAT             []string
 	BPFRedirectToPeer                  string
+	BPFEventsSampleRate                int
+	BPFEgressBandwidthFQEnabled        bool
+	BPFXDPDDoSProtection               bool
+	BPFXDPSYNRateLimit                 int
+	BPFXDPUDPRateLimit                 int
+	BPFXDPSYNCookies                   bool
+	BPFXDPRateLimitPrefixLengthV4      int
+	BPFXDPRateLimitPrefixLengthV6      int
+	BPFXDPBlocklistSelector            string
+	BPFWorkloadMetricsEnabled          bool
 	KubeProxyMinSyncPeriod             time.Duration
 	SidecarAccelerationEnabled         bool
 	ServiceLoopPrevention              string
@@ -314,6 +325,9 @@ type InternalDataplane struct {
 
 	endpointStatusCombiner *endpointStatusCombiner
 
+	// bpfEventsServer streams the BPF events to calico-bpf, if enabled.
+	bpfEventsServer *bpfevents.Server
+
 	allManagers             []Manager
 	managersWithRouteTables []ManagerWithRouteTables
 	managersWithRouteRules  []ManagerWithRouteRules
@@ -855,6 +869,22 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 		}
 
 		dp.RegisterManager(bpfEndpointManager)
+		prometheus.MustRegister(bpfEndpointManager.countersCollector)
+
+		// Start consuming the events before we let the programs emit them.
+		if config.BPFEventsSampleRate > 0 {
+			eventsServer := bpfevents.NewServer(bpfMaps.CommonMaps.EventsMap, bpfevents.SocketPath)
+			if err := eventsServer.Start(); err != nil {
+				log.WithError(err).Error("Failed to start the stream of BPF events.")
+			} else {
+				dp.bpfEventsServer = eventsServer
+			}
+		}
+		if err := bpfevents.SetSampleRate(bpfMaps.CommonMaps.EventsConfigMap, config.BPFEventsSampleRate); err != nil {
+			log.WithError(err).Error("Failed to configure BPF events.")
+		}
+
+		dp.RegisterManager(newBPFDDoSManager(&config, bpfMaps))
 
 		// HostNetworkedNAT is Enabled and CTLB enabled.
 		// HostNetworkedNAT is Disabled and CTLB is either disabled/TCP.
@@ -926,6 +956,10 @@ func NewIntDataplaneDriver(config Config) *InternalDataplane {
 	dp.endpointsSourceV4 = epManager
 	dp.RegisterManager(newFloatingIPManager(natTableV4, ruleRenderer, 4, config.FloatingIPsEnabled))
 	dp.RegisterManager(newMasqManager(ipSetsV4, natTableV4, ruleRenderer, config.MaxIPSetSize, 4))
+	if !config.BPFEnabled {
+		// In BPF mode, the BPF programs limit the bandwidth of the workloads.
+		dp.RegisterManager(newBandwidthManager())
+	}
 	if config.RulesConfig.IPIPEnabled {
 		log.Info("IPIP enabled, starting thread to keep tunnel configuration in sync.")
 		// Add a manager to keep the all-hosts IP set up to date.
@@ -1419,6 +1453,14 @@ func (d *InternalDataplane) Start() {
 	go d.monitorHostMTU()
 }
 
+// Stop releases the resources that the dataplane holds outside of the kernel dataplane
+// state, such as the BPF events socket.  It is called when Felix shuts down.
+func (d *InternalDataplane) Stop() {
+	if d.bpfEventsServer != nil {
+		d.bpfEventsServer.Stop()
+	}
+}
+
 // onIfaceInSync is used as a callback from the interface monitor.  We use it to send a message back to
 // the main goroutine via a channel.
 func (d *InternalDataplane) onIfaceInSync() {
@@ -0,0 +1,361 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qos programs the tc qdiscs that limit the bandwidth of workloads.
+//
+// The traffic to a workload is shaped by a tbf qdisc on the host side of its interface. The
+// traffic from a workload arrives on the ingress of that interface, where it cannot be shaped,
+// so it is redirected to an ifb device that has the tbf qdisc instead.
+//
+// In BPF mode the programs pace the packets themselves by setting their departure time, which
+// only needs an fq qdisc on the interfaces that the packets leave by.
+package qos
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// IfbPrefix is the prefix of the names of the ifb devices that we create.
+	IfbPrefix = "bwcali"
+
+	// latencyUsec is the longest time that a packet may wait in a tbf qdisc before it is
+	// dropped.
+	latencyUsec = 25000
+	// minBurstBytes is the smallest burst that we allow, it must fit a GSO packet.
+	minBurstBytes = 64 * 1024
+)
+
+// IfbName returns the name of the ifb device that shapes the traffic from the workload with the
+// given interface.
+func IfbName(workloadIface string) string {
+	h := sha1.Sum([]byte(workloadIface))
+	return IfbPrefix + hex.EncodeToString(h[:])[:unix.IFNAMSIZ-1-len(IfbPrefix)]
+}
+
+// tbfParams returns the parameters of a tbf qdisc that limits the traffic to the given rate in
+// bits per second: the rate in bytes per second, the burst in bytes and the queue limit in bytes.
+func tbfParams(rateBits int64) (rate uint64, burst, limit uint32) {
+	rate = uint64(rateBits) / 8
+	// Allow a burst of 100ms worth of traffic.
+	b := rate / 10
+	if b < minBurstBytes {
+		b = minBurstBytes
+	}
+	if b > 1<<31 {
+		b = 1 << 31
+	}
+	burst = uint32(b)
+	l := rate*latencyUsec/netlink.TIME_UNITS_PER_SEC + uint64(burst)
+	if l > 1<<32-1 {
+		l = 1<<32 - 1
+	}
+	return rate, burst, uint32(l)
+}
+
+func newTbf(linkIndex int, rateBits int64) *netlink.Tbf {
+	rate, burst, limit := tbfParams(rateBits)
+	return &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: linkIndex,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  limit,
+		Buffer: netlink.Xmittime(rate, burst),
+	}
+}
+
+func rootQdisc(link netlink.Link) (netlink.Qdisc, error) {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range qdiscs {
+		if q.Attrs().Parent == netlink.HANDLE_ROOT {
+			return q, nil
+		}
+	}
+	return nil, nil
+}
+
+// setTbf limits the egress of the link to the given rate, zero removes the limit.
+func setTbf(link netlink.Link, rateBits int64) error {
+	current, err := rootQdisc(link)
+	if err != nil {
+		return err
+	}
+	if rateBits == 0 {
+		if tbf, ok := current.(*netlink.Tbf); ok {
+			return netlink.QdiscDel(tbf)
+		}
+		return nil
+	}
+	want := newTbf(link.Attrs().Index, rateBits)
+	if tbf, ok := current.(*netlink.Tbf); ok && tbf.Rate == want.Rate && tbf.Limit == want.Limit {
+		return nil
+	}
+	return netlink.QdiscReplace(want)
+}
+
+// SetIngressLimit limits the bandwidth of the traffic to the workload with the given interface,
+// zero removes the limit.
+func SetIngressLimit(workloadIface string, rateBits int64) error {
+	link, err := netlink.LinkByName(workloadIface)
+	if err != nil {
+		return err
+	}
+	return setTbf(link, rateBits)
+}
+
+// SetEgressLimit limits the bandwidth of the traffic from the workload with the given interface,
+// zero removes the limit.
+func SetEgressLimit(workloadIface string, rateBits int64) error {
+	if rateBits == 0 {
+		return RemoveEgressLimit(workloadIface)
+	}
+
+	link, err := netlink.LinkByName(workloadIface)
+	if err != nil {
+		return err
+	}
+
+	ifbName := IfbName(workloadIface)
+	ifb, err := netlink.LinkByName(ifbName)
+	var lnf netlink.LinkNotFoundError
+	if errors.As(err, &lnf) {
+		attrs := netlink.NewLinkAttrs()
+		attrs.Name = ifbName
+		attrs.TxQLen = 1000
+		attrs.MTU = link.Attrs().MTU
+		if err := netlink.LinkAdd(&netlink.Ifb{LinkAttrs: attrs}); err != nil {
+			return fmt.Errorf("failed to create %s: %w", ifbName, err)
+		}
+		if ifb, err = netlink.LinkByName(ifbName); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	if err := netlink.LinkSetUp(ifb); err != nil {
+		return err
+	}
+	if err := setTbf(ifb, rateBits); err != nil {
+		return err
+	}
+
+	ingress := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+	if err := netlink.QdiscReplace(ingress); err != nil {
+		return fmt.Errorf("failed to add ingress qdisc to %s: %w", workloadIface, err)
+	}
+
+	redirect := netlink.NewMirredAction(ifb.Attrs().Index)
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    ingress.Handle,
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		// Match all the packets.
+		Sel: &netlink.TcU32Sel{
+			Keys:  []netlink.TcU32Key{{}},
+			Flags: netlink.TC_U32_TERMINAL,
+		},
+		Actions: []netlink.Action{redirect},
+	}
+	if err := netlink.FilterReplace(filter); err != nil {
+		return fmt.Errorf("failed to redirect %s to %s: %w", workloadIface, ifbName, err)
+	}
+
+	return nil
+}
+
+// RemoveEgressLimit removes the limit of the bandwidth of the traffic from the workload with
+// the given interface. It works after the interface is gone.
+func RemoveEgressLimit(workloadIface string) error {
+	if link, err := netlink.LinkByName(workloadIface); err == nil {
+		ingress := &netlink.Ingress{
+			QdiscAttrs: netlink.QdiscAttrs{
+				LinkIndex: link.Attrs().Index,
+				Handle:    netlink.MakeHandle(0xffff, 0),
+				Parent:    netlink.HANDLE_INGRESS,
+			},
+		}
+		if err := netlink.QdiscDel(ingress); err != nil && !errors.Is(err, unix.ENOENT) &&
+			!errors.Is(err, unix.EINVAL) {
+			return fmt.Errorf("failed to remove ingress qdisc from %s: %w", workloadIface, err)
+		}
+	}
+
+	return removeIfb(IfbName(workloadIface))
+}
+
+func removeIfb(name string) error {
+	ifb, err := netlink.LinkByName(name)
+	var lnf netlink.LinkNotFoundError
+	if errors.As(err, &lnf) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return netlink.LinkDel(ifb)
+}
+
+// CleanUpIfbs removes the ifb devices that we created for the workloads that are not in the
+// given set of workload interfaces.
+func CleanUpIfbs(workloadIfaces map[string]bool) error {
+	wanted := map[string]bool{}
+	for iface := range workloadIfaces {
+		wanted[IfbName(iface)] = true
+	}
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, l := range links {
+		name := l.Attrs().Name
+		if l.Type() != "ifb" || !strings.HasPrefix(name, IfbPrefix) || wanted[name] {
+			continue
+		}
+		log.WithField("ifb", name).Info("Removing unused bandwidth limiting device.")
+		if err := netlink.LinkDel(l); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// EnsureFQ makes the root qdisc of the interface an fq qdisc, which respects the departure time
+// that the BPF programs set on the packets. A multi-queue root qdisc gets an fq qdisc on each of
+// its queues instead. It returns the qdiscs that it replaced, for RestoreQdiscs.
+func EnsureFQ(iface string) ([]netlink.Qdisc, error) {
+	link, err := netlink.LinkByName(iface)
+	if err != nil {
+		return nil, err
+	}
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, err
+	}
+
+	var root netlink.Qdisc
+	for _, q := range qdiscs {
+		if q.Attrs().Parent == netlink.HANDLE_ROOT {
+			root = q
+		}
+	}
+
+	var replaced []netlink.Qdisc
+	if root != nil && root.Type() == "mq" {
+		major, _ := netlink.MajorMinor(root.Attrs().Handle)
+		queues := map[uint32]netlink.Qdisc{}
+		for _, q := range qdiscs {
+			if qmajor, _ := netlink.MajorMinor(q.Attrs().Parent); qmajor == major {
+				queues[q.Attrs().Parent] = q
+			}
+		}
+		for i := 1; i <= link.Attrs().NumTxQueues; i++ {
+			parent := netlink.MakeHandle(major, uint16(i))
+			q := queues[parent]
+			if q != nil && q.Type() == "fq" {
+				continue
+			}
+			fq := netlink.NewFq(netlink.QdiscAttrs{LinkIndex: link.Attrs().Index, Parent: parent})
+			if err := netlink.QdiscReplace(fq); err != nil {
+				return replaced, fmt.Errorf("failed to add fq qdisc to queue %d of %s: %w", i, iface, err)
+			}
+			if q != nil {
+				replaced = append(replaced, q)
+			}
+		}
+		return replaced, nil
+	}
+
+	if root != nil && root.Type() == "fq" {
+		return nil, nil
+	}
+	log.WithField("iface", iface).Info("Replacing root qdisc with fq for bandwidth limiting.")
+	fq := netlink.NewFq(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(1, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	})
+	if err := netlink.QdiscReplace(fq); err != nil {
+		return nil, err
+	}
+	if root != nil {
+		replaced = append(replaced, root)
+	}
+	return replaced, nil
+}
+
+// RestoreQdiscs puts back the qdiscs that EnsureFQ replaced on the interface. It does nothing if
+// the interface is gone.
+func RestoreQdiscs(iface string, replaced []netlink.Qdisc) error {
+	link, err := netlink.LinkByName(iface)
+	var lnf netlink.LinkNotFoundError
+	if errors.As(err, &lnf) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, q := range replaced {
+		q.Attrs().LinkIndex = link.Attrs().Index
+		if q.Attrs().Parent == netlink.HANDLE_ROOT {
+			// Removing our root qdisc makes the kernel attach its default qdisc again, which
+			// is all that there was before if the old qdisc had no handle.
+			log.WithFields(log.Fields{"iface": iface, "qdisc": q.Type()}).Info(
+				"Restoring root qdisc that was replaced for bandwidth limiting.")
+			err = netlink.QdiscDel(&netlink.GenericQdisc{
+				QdiscAttrs: netlink.QdiscAttrs{
+					LinkIndex: link.Attrs().Index,
+					Handle:    netlink.MakeHandle(1, 0),
+					Parent:    netlink.HANDLE_ROOT,
+				},
+				QdiscType: "fq",
+			})
+			if (err == nil || errors.Is(err, unix.ENOENT)) && q.Attrs().Handle != 0 {
+				err = netlink.QdiscReplace(q)
+			}
+		} else {
+			// Removing the qdisc of a queue would leave the queue without one, so we replace
+			// it instead.
+			err = netlink.QdiscReplace(q)
+		}
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			lastErr = fmt.Errorf("failed to restore %s qdisc of %s: %w", q.Type(), iface, err)
+		}
+	}
+	return lastErr
+}
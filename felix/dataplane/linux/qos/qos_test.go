@@ -0,0 +1,48 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIfbName(t *testing.T) {
+	RegisterTestingT(t)
+
+	name := IfbName("cali1234567890a")
+	Expect(name).To(HaveLen(15))
+	Expect(strings.HasPrefix(name, IfbPrefix)).To(BeTrue())
+	Expect(IfbName("cali1234567890a")).To(Equal(name))
+	Expect(IfbName("cali1234567890b")).NotTo(Equal(name))
+}
+
+func TestTbfParams(t *testing.T) {
+	RegisterTestingT(t)
+
+	// 1Gbit/s allows 100ms worth of burst.
+	rate, burst, limit := tbfParams(1000 * 1000 * 1000)
+	Expect(rate).To(Equal(uint64(125 * 1000 * 1000)))
+	Expect(burst).To(Equal(uint32(12500 * 1000)))
+	Expect(limit).To(Equal(uint32(125*1000*1000/40 + 12500*1000)))
+
+	// A low rate still allows a GSO packet.
+	rate, burst, limit = tbfParams(1000 * 1000)
+	Expect(rate).To(Equal(uint64(125 * 1000)))
+	Expect(burst).To(Equal(uint32(minBurstBytes)))
+	Expect(limit).To(Equal(uint32(125*1000/40 + minBurstBytes)))
+}
@@ -2996,6 +2996,9 @@ type WorkloadEndpoint struct {
 	Ipv6Nat                    []*NatInfo        `protobuf:"bytes,9,rep,name=ipv6_nat,json=ipv6Nat" json:"ipv6_nat,omitempty"`
 	AllowSpoofedSourcePrefixes []string          `protobuf:"bytes,10,rep,name=allow_spoofed_source_prefixes,json=allowSpoofedSourcePrefixes" json:"allow_spoofed_source_prefixes,omitempty"`
 	Annotations                map[string]string `protobuf:"bytes,11,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Bandwidth limits in bits per second, zero if there is no limit.
+	IngressBandwidth int64 `protobuf:"varint,12,opt,name=ingress_bandwidth,json=ingressBandwidth,proto3" json:"ingress_bandwidth,omitempty"`
+	EgressBandwidth  int64 `protobuf:"varint,13,opt,name=egress_bandwidth,json=egressBandwidth,proto3" json:"egress_bandwidth,omitempty"`
 }
 
 func (m *WorkloadEndpoint) Reset()                    { *m = WorkloadEndpoint{} }
@@ -3080,6 +3083,20 @@ func (m *WorkloadEndpoint) GetAnnotations() map[string]string {
 	return nil
 }
 
+func (m *WorkloadEndpoint) GetIngressBandwidth() int64 {
+	if m != nil {
+		return m.IngressBandwidth
+	}
+	return 0
+}
+
+func (m *WorkloadEndpoint) GetEgressBandwidth() int64 {
+	if m != nil {
+		return m.EgressBandwidth
+	}
+	return 0
+}
+
 type WorkloadEndpointRemove struct {
 	Id *WorkloadEndpointID `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 }
@@ -6837,6 +6854,16 @@ func (m *WorkloadEndpoint) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], v)
 		}
 	}
+	if m.IngressBandwidth != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.IngressBandwidth))
+	}
+	if m.EgressBandwidth != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintFelixbackend(dAtA, i, uint64(m.EgressBandwidth))
+	}
 	return i, nil
 }
 
@@ -9628,6 +9655,12 @@ func (m *WorkloadEndpoint) Size() (n int) {
 			n += mapEntrySize + 1 + sovFelixbackend(uint64(mapEntrySize))
 		}
 	}
+	if m.IngressBandwidth != 0 {
+		n += 1 + sovFelixbackend(uint64(m.IngressBandwidth))
+	}
+	if m.EgressBandwidth != 0 {
+		n += 1 + sovFelixbackend(uint64(m.EgressBandwidth))
+	}
 	return n
 }
 
@@ -16453,6 +16486,44 @@ func (m *WorkloadEndpoint) Unmarshal(dAtA []byte) error {
 			}
 			m.Annotations[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IngressBandwidth", wireType)
+			}
+			m.IngressBandwidth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IngressBandwidth |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EgressBandwidth", wireType)
+			}
+			m.EgressBandwidth = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFelixbackend
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EgressBandwidth |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFelixbackend(dAtA[iNdEx:])
@@ -22097,271 +22168,273 @@ var (
 func init() { proto1.RegisterFile("felixbackend.proto", fileDescriptorFelixbackend) }
 
 var fileDescriptorFelixbackend = []byte{
-	// 4243 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x7b, 0xcd, 0x73, 0x1c, 0x49,
-	0x5a, 0xb7, 0xaa, 0x25, 0xb5, 0xba, 0x9f, 0xfe, 0x74, 0xea, 0xab, 0x25, 0xdb, 0xb2, 0xa7, 0x3c,
-	0xde, 0xd1, 0x78, 0x77, 0x3c, 0x7e, 0x35, 0x72, 0x7b, 0x3d, 0xef, 0x32, 0x1b, 0x6d, 0xb5, 0x66,
-	0xd4, 0x33, 0xb6, 0x24, 0x4a, 0x1a, 0x0d, 0xb3, 0x6c, 0x44, 0x51, 0xaa, 0x4a, 0x49, 0x85, 0xab,
-	0xab, 0x6a, 0xaa, 0xb2, 0xf5, 0xb1, 0x9c, 0x80, 0x25, 0x02, 0x82, 0x03, 0x1c, 0x08, 0x02, 0xee,
-	0x9c, 0x08, 0xfe, 0x03, 0x0e, 0x5c, 0x77, 0x83, 0x0b, 0x04, 0x67, 0x22, 0x88, 0xe1, 0x46, 0x70,
-	0x81, 0x08, 0xee, 0x44, 0x7e, 0xd6, 0x47, 0x57, 0xcb, 0x32, 0x3b, 0x70, 0x52, 0xe7, 0xf3, 0xf1,
-	0xcb, 0x27, 0x9f, 0x7a, 0xf2, 0xc9, 0xcc, 0x27, 0x53, 0x80, 0x4e, 0xb0, 0xe7, 0x5e, 0x1e, 0x5b,
-	0xf6, 0x6b, 0xec, 0x3b, 0x8f, 0xc3, 0x28, 0x20, 0x01, 0x9a, 0x65, 0x34, 0xbd, 0x01, 0xb5, 0x83,
-	0x2b, 0xdf, 0x36, 0xf0, 0x37, 0x23, 0x1c, 0x13, 0xfd, 0xef, 0x97, 0xa0, 0x76, 0x18, 0xf4, 0x2d,
-	0x62, 0x85, 0x9e, 0xe5, 0x63, 0xb4, 0x0e, 0x73, 0xae, 0x6f, 0xc6, 0x57, 0xbe, 0xdd, 0xd1, 0xee,
-	0x6b, 0xeb, 0xb5, 0x8d, 0xc6, 0x63, 0xa6, 0xf7, 0x78, 0xe0, 0x53, 0xb5, 0x9d, 0x29, 0xa3, 0xec,
-	0xb2, 0x5f, 0xe8, 0x19, 0xd4, 0xdd, 0x30, 0xc6, 0xc4, 0x1c, 0x85, 0x8e, 0x45, 0x70, 0xa7, 0xc4,
-	0xc4, 0x91, 0x14, 0xdf, 0x3f, 0xc0, 0xe4, 0x4b, 0xc6, 0xd9, 0x99, 0x32, 0x6a, 0x4c, 0x92, 0x37,
-	0xd1, 0x67, 0x80, 0xb8, 0xa2, 0x83, 0x3d, 0x62, 0x49, 0xf5, 0x69, 0xa6, 0xbe, 0x9c, 0x56, 0xef,
-	0x53, 0xbe, 0xc2, 0x68, 0x33, 0xa5, 0x14, 0x2d, 0xb1, 0x20, 0xc2, 0xc3, 0xe0, 0x1c, 0x77, 0x66,
-	0xc6, 0x2d, 0x30, 0x18, 0x47, 0x59, 0xc0, 0x9b, 0x68, 0x1f, 0x16, 0x2d, 0x9b, 0xb8, 0xe7, 0xd8,
-	0x0c, 0xa3, 0xe0, 0xc4, 0xf5, 0xb0, 0x34, 0x62, 0x96, 0x21, 0xac, 0x0a, 0x84, 0x1e, 0x93, 0xd9,
-	0xe7, 0x22, 0xca, 0x8e, 0x79, 0x6b, 0x9c, 0x5c, 0x80, 0x28, 0x6c, 0x2a, 0x4f, 0x46, 0x54, 0xb6,
-	0x65, 0x11, 0x85, 0x8d, 0xaf, 0x60, 0x41, 0x22, 0x06, 0x9e, 0x6b, 0x5f, 0x49, 0x13, 0xe7, 0x18,
-	0xe0, 0x4a, 0x16, 0x90, 0x49, 0x28, 0x0b, 0x91, 0x35, 0x46, 0x1d, 0x87, 0x13, 0xf6, 0x55, 0x26,
-	0xc2, 0x29, 0xf3, 0x32, 0x70, 0x89, 0x75, 0x67, 0x41, 0x4c, 0x4c, 0xec, 0x3b, 0x61, 0xe0, 0xfa,
-	0x2a, 0x08, 0xaa, 0x19, 0xb8, 0x9d, 0x20, 0x26, 0xdb, 0x42, 0x22, 0xb1, 0xee, 0x6c, 0x8c, 0x3a,
-	0x0e, 0x27, 0xac, 0x83, 0x89, 0x70, 0x89, 0x75, 0x67, 0x63, 0x54, 0xf4, 0x35, 0x74, 0x2e, 0x82,
-	0xe8, 0xb5, 0x17, 0x58, 0xce, 0x98, 0x85, 0x35, 0x06, 0x79, 0x57, 0x40, 0x7e, 0x25, 0xc4, 0xc6,
-	0xac, 0x5c, 0xba, 0x28, 0xe4, 0x14, 0x43, 0x0b, 0x6b, 0xeb, 0xd7, 0x42, 0x2b, 0x8b, 0xc7, 0xa0,
-	0x85, 0xd5, 0x1f, 0x43, 0xc3, 0x0e, 0xfc, 0x13, 0xf7, 0x54, 0x9a, 0xda, 0x60, 0x78, 0xf3, 0x02,
-	0x6f, 0x8b, 0xf1, 0x94, 0x81, 0x75, 0x3b, 0xd5, 0x56, 0x0e, 0x1c, 0x62, 0x62, 0x39, 0x56, 0x32,
-	0xab, 0x9a, 0x63, 0x0e, 0x7c, 0x25, 0x24, 0xb2, 0xdf, 0x23, 0x4b, 0x45, 0xef, 0x41, 0x2b, 0xa6,
-	0x09, 0xc2, 0xb7, 0xb1, 0xe9, 0x8f, 0x86, 0xc7, 0x38, 0xea, 0xb4, 0xee, 0x6b, 0xeb, 0x33, 0x46,
-	0x53, 0x92, 0x77, 0x19, 0x15, 0xf5, 0xa0, 0xed, 0x86, 0xd6, 0xd0, 0x0c, 0x83, 0xc0, 0x93, 0x7d,
-	0xb6, 0x59, 0x9f, 0x8b, 0x6a, 0x1a, 0xf6, 0x5e, 0xed, 0x07, 0x81, 0xa7, 0xfa, 0x6b, 0x52, 0x85,
-	0x84, 0x92, 0x85, 0x10, 0x9e, 0xbc, 0x55, 0x08, 0xa1, 0x3c, 0xa8, 0x20, 0x72, 0xd1, 0xa8, 0x46,
-	0x2f, 0x60, 0xd0, 0xc4, 0xd1, 0x67, 0xc3, 0x27, 0x4b, 0x45, 0x07, 0xb0, 0x14, 0xe3, 0xe8, 0xdc,
-	0xb5, 0xb1, 0x69, 0xd9, 0x76, 0x30, 0x4a, 0x82, 0x67, 0x9e, 0x01, 0xde, 0x16, 0x80, 0x07, 0x5c,
-	0xa8, 0xc7, 0x65, 0xd4, 0x00, 0x17, 0xe2, 0x02, 0x7a, 0x11, 0xa8, 0xb0, 0x72, 0xe1, 0x1a, 0x50,
-	0x65, 0x67, 0x0e, 0x54, 0x58, 0xba, 0x05, 0x6d, 0xdf, 0x1a, 0xe2, 0x38, 0xb4, 0x6c, 0x95, 0xc3,
-	0x16, 0x19, 0xdc, 0x92, 0x80, 0xdb, 0x95, 0x6c, 0x65, 0x5e, 0xcb, 0xcf, 0x92, 0xb2, 0x20, 0xc2,
-	0xa6, 0xa5, 0x62, 0x10, 0x65, 0x4e, 0x02, 0x22, 0x2c, 0x79, 0x06, 0xf5, 0x28, 0x18, 0x11, 0x65,
-	0xc5, 0x72, 0x26, 0x17, 0x1b, 0x94, 0x95, 0xac, 0x06, 0x51, 0xd2, 0x4c, 0x14, 0x45, 0xcf, 0x9d,
-	0x71, 0xc5, 0x24, 0x89, 0x47, 0x49, 0x13, 0x6d, 0x41, 0xed, 0x9c, 0xe0, 0x50, 0x76, 0xb8, 0xc2,
-	0xf4, 0xee, 0x0b, 0xbd, 0xa3, 0xdf, 0x78, 0xd9, 0xdb, 0x3d, 0x1c, 0xf9, 0x3e, 0xf6, 0xc6, 0xa6,
-	0x36, 0x50, 0x35, 0x35, 0x76, 0x0e, 0x22, 0x3a, 0x5f, 0x7d, 0x13, 0x88, 0x32, 0x85, 0x81, 0x08,
-	0x4b, 0x7e, 0x0a, 0x2b, 0x17, 0x6e, 0x84, 0x4f, 0x47, 0x56, 0x34, 0x9e, 0x6f, 0x6e, 0x33, 0xc8,
-	0x35, 0x99, 0x14, 0xa4, 0xdc, 0x98, 0x55, 0xcb, 0x17, 0xc5, 0xac, 0x09, 0xe8, 0xc2, 0xe0, 0x3b,
-	0xd7, 0xa3, 0x2b, 0x73, 0xc7, 0xd1, 0x85, 0xed, 0x5f, 0x41, 0xe7, 0xd4, 0x0b, 0x8e, 0x2d, 0xcf,
-	0x3c, 0x3e, 0x0d, 0xcd, 0x6c, 0xfe, 0xb9, 0xcb, 0xc0, 0xef, 0x08, 0xf0, 0xcf, 0x98, 0xd8, 0x8b,
-	0xcf, 0xf6, 0x73, 0x89, 0x68, 0x91, 0xeb, 0xbf, 0x38, 0x0d, 0xd3, 0x0c, 0xf4, 0x23, 0x68, 0x60,
-	0xdf, 0xb6, 0xc2, 0x78, 0xe4, 0x59, 0xc4, 0x0d, 0xfc, 0xce, 0x1a, 0x43, 0x5b, 0x10, 0x68, 0xdb,
-	0x69, 0xde, 0xce, 0x94, 0x91, 0x15, 0x46, 0xbf, 0x06, 0x4d, 0x39, 0x5b, 0x84, 0x31, 0xf7, 0x32,
-	0xea, 0x62, 0x96, 0x28, 0x23, 0x1a, 0x71, 0x9a, 0x90, 0x56, 0x17, 0x8e, 0xba, 0x5f, 0xa4, 0xae,
-	0xdc, 0x23, 0xd5, 0x85, 0x53, 0x6c, 0xb8, 0x53, 0xe0, 0xf2, 0xf3, 0xae, 0xb4, 0xe5, 0x9d, 0x4c,
-	0x98, 0x8c, 0x79, 0xfd, 0xa8, 0xab, 0xec, 0x5a, 0xb9, 0x98, 0xc4, 0x9c, 0xdc, 0x89, 0xb0, 0x58,
-	0x7f, 0x53, 0x27, 0xca, 0xfa, 0xa2, 0x4e, 0xc4, 0x48, 0x0e, 0x61, 0x39, 0x9b, 0x19, 0x93, 0x41,
-	0x3c, 0xc8, 0xa4, 0x9d, 0x74, 0x72, 0x4c, 0xd9, 0xbf, 0x70, 0x56, 0x40, 0x2f, 0x44, 0x15, 0x56,
-	0xbf, 0x7b, 0x0d, 0x6a, 0x92, 0xcc, 0xce, 0x0a, 0xe8, 0xe8, 0x27, 0xb0, 0x92, 0x43, 0xdd, 0x4c,
-	0xac, 0x7d, 0x98, 0x59, 0x5b, 0x33, 0xb8, 0x9b, 0x29, 0x7b, 0x97, 0x32, 0xc8, 0x9b, 0xe7, 0xd2,
-	0xe2, 0x62, 0x6c, 0x61, 0xf3, 0xf7, 0xae, 0xc5, 0x4e, 0xd6, 0xed, 0x3c, 0x36, 0xe7, 0xbc, 0xa8,
-	0xc2, 0x5c, 0x68, 0x5d, 0xd1, 0x05, 0x5d, 0xff, 0xa7, 0x59, 0x68, 0x7c, 0x1a, 0x05, 0xc3, 0x64,
-	0x3f, 0xbd, 0x0f, 0x8b, 0x61, 0x14, 0xd8, 0x38, 0x8e, 0xcd, 0x98, 0x58, 0x64, 0x14, 0x67, 0xf7,
-	0xbb, 0x72, 0x63, 0xb8, 0xcf, 0x65, 0x0e, 0x98, 0x48, 0xb2, 0xd5, 0x0c, 0xc7, 0xc9, 0xe8, 0xb7,
-	0xe0, 0x76, 0x76, 0xaf, 0x94, 0xc5, 0xe5, 0x9b, 0xe0, 0x7b, 0x05, 0x5b, 0xa6, 0x1c, 0x78, 0xe7,
-	0x6c, 0x02, 0x6f, 0x62, 0x0f, 0xc2, 0x5d, 0xb3, 0x6f, 0xe8, 0x41, 0x39, 0xac, 0xa0, 0x07, 0xf1,
-	0xa9, 0x3d, 0xb8, 0x37, 0xbe, 0x8b, 0xca, 0x8e, 0x83, 0x6f, 0x9c, 0x1f, 0x4c, 0xd8, 0x4c, 0xe5,
-	0xc6, 0x72, 0xe7, 0xe2, 0x1a, 0xfe, 0xb5, 0xbd, 0x89, 0x31, 0xcd, 0xdd, 0xa0, 0x37, 0x35, 0xae,
-	0x09, 0xbd, 0x89, 0xb1, 0x15, 0xec, 0x9d, 0x2a, 0x85, 0x7b, 0xa7, 0x23, 0x48, 0xb2, 0x72, 0x6e,
-	0xf0, 0xd5, 0x4c, 0xe6, 0x55, 0x73, 0x3f, 0x37, 0xea, 0xc5, 0x8b, 0x22, 0x06, 0xea, 0xc3, 0x2d,
-	0x47, 0xc6, 0x9f, 0x29, 0x0f, 0x73, 0x90, 0x59, 0xd0, 0x55, 0x7c, 0xaa, 0x53, 0x5d, 0xcb, 0xc9,
-	0x92, 0xd2, 0x51, 0xfd, 0x8f, 0x25, 0xa8, 0x67, 0x72, 0xfb, 0x33, 0x28, 0xf3, 0x95, 0xa2, 0xa3,
-	0xdd, 0x9f, 0x4e, 0xc5, 0x42, 0x5a, 0x48, 0x34, 0xb6, 0x7d, 0x12, 0x5d, 0x19, 0x42, 0x1c, 0xfd,
-	0x26, 0x2c, 0xc4, 0xc1, 0x28, 0xb2, 0xb1, 0x49, 0x02, 0x33, 0xb2, 0x2e, 0xc4, 0x82, 0xd3, 0x29,
-	0x31, 0x98, 0x47, 0x45, 0x30, 0x07, 0x4c, 0xfe, 0x30, 0x30, 0xac, 0x8b, 0x34, 0xe2, 0xad, 0x38,
-	0x4f, 0x47, 0x1d, 0x98, 0x1b, 0xe2, 0x38, 0xb6, 0x4e, 0xf9, 0xe4, 0xaa, 0x1a, 0xb2, 0xb9, 0xfa,
-	0x1c, 0x6a, 0x29, 0x5d, 0xd4, 0x86, 0xe9, 0xd7, 0xf8, 0x8a, 0x9d, 0x6f, 0xab, 0x06, 0xfd, 0x89,
-	0x16, 0x60, 0xf6, 0xdc, 0xf2, 0x46, 0xfc, 0x10, 0x5b, 0x35, 0x78, 0xe3, 0xe3, 0xd2, 0x0f, 0xb5,
-	0xd5, 0x23, 0x58, 0x2a, 0xb6, 0x20, 0x8d, 0xd2, 0xe0, 0x28, 0xdf, 0x4b, 0xa3, 0xd4, 0x36, 0xda,
-	0x72, 0x0f, 0x23, 0xf5, 0x52, 0xb8, 0xfa, 0x9f, 0x69, 0x50, 0x4d, 0x4c, 0x5f, 0x82, 0x32, 0x1f,
-	0x8f, 0x30, 0x4a, 0xb4, 0xd0, 0xa6, 0x72, 0x34, 0xf7, 0xd0, 0x9d, 0x3c, 0x64, 0x91, 0x97, 0x7f,
-	0x85, 0xe1, 0xea, 0x15, 0x28, 0xf3, 0xef, 0xaf, 0xff, 0x85, 0x06, 0xb5, 0xd4, 0x21, 0x1e, 0x35,
-	0xa1, 0xe4, 0x3a, 0x02, 0xa4, 0xe4, 0x3a, 0xdc, 0xdb, 0x34, 0x8e, 0x63, 0x66, 0x1b, 0xf3, 0x36,
-	0x6b, 0xa2, 0x27, 0x30, 0x43, 0xae, 0x42, 0xfe, 0x11, 0x9a, 0xca, 0xe4, 0x14, 0x16, 0xff, 0x7d,
-	0x78, 0x15, 0x62, 0x83, 0x49, 0xea, 0x1f, 0x40, 0x55, 0x91, 0x50, 0x19, 0x4a, 0x83, 0xfd, 0xf6,
-	0x14, 0x6a, 0xd1, 0xfe, 0xcd, 0xde, 0x6e, 0xdf, 0xdc, 0xdf, 0x33, 0x0e, 0xdb, 0x1a, 0x9a, 0x83,
-	0xe9, 0xdd, 0xed, 0xc3, 0x76, 0x49, 0x0f, 0xa1, 0x9d, 0xaf, 0x0f, 0x8c, 0x99, 0xf7, 0x00, 0x1a,
-	0x96, 0xe3, 0x60, 0xc7, 0xcc, 0x1a, 0x59, 0x67, 0xc4, 0x57, 0xc2, 0xd2, 0xf7, 0xa0, 0xc5, 0xe7,
-	0x7f, 0x22, 0x36, 0xcd, 0xc4, 0x9a, 0x82, 0x2c, 0x04, 0xf5, 0xbb, 0xc2, 0x17, 0x62, 0x8a, 0xe7,
-	0x3a, 0xd3, 0x2d, 0x98, 0x2f, 0xa8, 0x15, 0xa0, 0xfb, 0x4a, 0x2c, 0x09, 0x06, 0x21, 0x31, 0xe8,
-	0x33, 0x2b, 0xd7, 0x61, 0x4e, 0xd4, 0x0b, 0x44, 0xcc, 0x34, 0xb3, 0x62, 0x86, 0x64, 0xeb, 0xcf,
-	0x72, 0x5d, 0x08, 0x4b, 0xde, 0xd8, 0x85, 0x7e, 0x0f, 0xaa, 0x8a, 0x80, 0x10, 0xcc, 0xd0, 0x8d,
-	0xbb, 0x30, 0x9d, 0xfd, 0xd6, 0x03, 0x98, 0x13, 0x02, 0xe8, 0x09, 0x34, 0x5c, 0xff, 0x38, 0x18,
-	0xf9, 0x8e, 0x19, 0x8d, 0x3c, 0x1c, 0x8b, 0xe9, 0x5d, 0x93, 0x51, 0x37, 0xf2, 0xb0, 0x51, 0x17,
-	0x12, 0xb4, 0x11, 0xa3, 0x0d, 0x68, 0x06, 0x23, 0x92, 0x56, 0x29, 0x8d, 0xab, 0x34, 0xa4, 0x08,
-	0xd3, 0xd1, 0x7f, 0x0a, 0x68, 0xbc, 0x6c, 0x81, 0xee, 0xa5, 0x46, 0xd2, 0x92, 0x23, 0x61, 0x02,
-	0xc2, 0x57, 0x0f, 0xa1, 0xcc, 0x4b, 0x17, 0xc2, 0x55, 0x8d, 0x8c, 0x90, 0x21, 0x98, 0xfa, 0xd3,
-	0x2c, 0xba, 0xf0, 0xd3, 0x9b, 0xd0, 0xf5, 0x0d, 0xa8, 0xc8, 0x36, 0xf5, 0x12, 0x71, 0x71, 0x24,
-	0xbd, 0x44, 0x7f, 0x2b, 0xcf, 0x95, 0x52, 0x9e, 0xfb, 0x4f, 0x0d, 0xca, 0x5c, 0xe9, 0xff, 0xc6,
-	0x73, 0xe8, 0x0e, 0x54, 0x47, 0x3e, 0x89, 0x2c, 0xfb, 0x35, 0x76, 0xd8, 0xf4, 0xaa, 0x18, 0x09,
-	0x01, 0xad, 0x40, 0x25, 0x8c, 0xb0, 0xe9, 0xf8, 0x16, 0x61, 0xbb, 0x80, 0x0a, 0x8d, 0x1e, 0xdc,
-	0xf7, 0x2d, 0x42, 0x15, 0xd5, 0x81, 0x8d, 0xad, 0xdf, 0x55, 0x23, 0x21, 0xa0, 0xef, 0xc3, 0xad,
-	0x20, 0x72, 0x4f, 0x5d, 0xdf, 0xf2, 0xcc, 0x18, 0x7b, 0xd8, 0x26, 0x41, 0xc4, 0xd6, 0xdf, 0xaa,
-	0xd1, 0x96, 0x8c, 0x03, 0x41, 0xd7, 0xff, 0xba, 0x0d, 0x33, 0xd4, 0x1a, 0x9a, 0xb3, 0x2c, 0x9b,
-	0xed, 0xec, 0x45, 0xce, 0xe2, 0x2d, 0xf4, 0x21, 0x80, 0x1b, 0x9a, 0xe7, 0x38, 0x8a, 0x29, 0xaf,
-	0xc4, 0x92, 0x40, 0x5b, 0x25, 0x81, 0x23, 0x4e, 0x37, 0xaa, 0x6e, 0x28, 0x7e, 0xa2, 0xef, 0x53,
-	0xbb, 0x03, 0x12, 0xd8, 0x81, 0x27, 0x76, 0x45, 0xad, 0x24, 0x92, 0x19, 0xd9, 0x50, 0x02, 0x68,
-	0x19, 0xe6, 0xe2, 0xc8, 0x36, 0x7d, 0x4c, 0xc7, 0x38, 0xcd, 0x52, 0x65, 0x64, 0xef, 0x62, 0x82,
-	0x3e, 0x80, 0x2a, 0x65, 0x84, 0x41, 0x44, 0xe2, 0xce, 0x2c, 0x73, 0xa5, 0x9a, 0x10, 0x41, 0x44,
-	0x0c, 0xcb, 0x3f, 0xc5, 0x46, 0x25, 0x8e, 0x6c, 0xda, 0x8a, 0x29, 0x8e, 0x13, 0x13, 0x86, 0x53,
-	0xe6, 0x38, 0x4e, 0x4c, 0x04, 0x0e, 0x65, 0x70, 0x9c, 0xb9, 0x49, 0x38, 0x4e, 0x4c, 0x38, 0xce,
-	0x5d, 0xa8, 0xba, 0xf6, 0x30, 0x34, 0x59, 0xc6, 0xa3, 0xeb, 0xfc, 0xec, 0xce, 0x94, 0x51, 0xa1,
-	0x24, 0x96, 0xcc, 0x3e, 0x81, 0xa6, 0x62, 0x9b, 0x76, 0xe0, 0xc8, 0xa5, 0x5d, 0x2e, 0xc4, 0x03,
-	0x21, 0xd8, 0xf3, 0x9d, 0xad, 0xc0, 0x61, 0x75, 0x1d, 0xa9, 0x4b, 0xdb, 0xe8, 0x01, 0x34, 0xe9,
-	0xa8, 0xdc, 0xd0, 0x8c, 0x31, 0x31, 0x5d, 0x27, 0xee, 0x00, 0xb3, 0xb6, 0x16, 0x47, 0xf6, 0x20,
-	0x3c, 0xc0, 0x64, 0xe0, 0xc4, 0x54, 0x88, 0x9a, 0x9c, 0x12, 0xaa, 0x71, 0x21, 0x27, 0x26, 0x4a,
-	0xe8, 0x19, 0xac, 0x30, 0xc7, 0x59, 0x43, 0xec, 0xb0, 0xd1, 0xa5, 0xe5, 0xeb, 0x4c, 0x7e, 0x81,
-	0xba, 0x92, 0xf2, 0xe9, 0xd0, 0xd2, 0x8a, 0xcc, 0x53, 0x85, 0x8a, 0x0d, 0xae, 0x48, 0x7d, 0x37,
-	0xa6, 0xf8, 0x03, 0x98, 0x17, 0x66, 0x31, 0x2d, 0xa9, 0xd2, 0x62, 0x2a, 0x2d, 0x66, 0x1b, 0x95,
-	0x17, 0xd2, 0x1b, 0x50, 0xf7, 0x03, 0x62, 0xaa, 0x48, 0x38, 0x29, 0x8e, 0x84, 0x9a, 0x1f, 0x10,
-	0xd9, 0x40, 0x6b, 0x40, 0x9b, 0xa6, 0x0c, 0x88, 0x53, 0x86, 0x5c, 0xf5, 0x03, 0x72, 0xc0, 0x63,
-	0x62, 0x13, 0x1a, 0x92, 0xcf, 0xbf, 0xe7, 0xd9, 0x84, 0xef, 0x59, 0xe3, 0x3a, 0xfc, 0x93, 0x0a,
-	0x54, 0x19, 0x1e, 0xae, 0x42, 0xed, 0xf3, 0x08, 0x11, 0xa8, 0x49, 0x94, 0xfc, 0xf6, 0x35, 0xa8,
-	0x7d, 0x19, 0x28, 0xef, 0x72, 0xad, 0x24, 0x58, 0x5e, 0xb3, 0x60, 0xd1, 0x98, 0x94, 0x0c, 0x03,
-	0xb4, 0x0d, 0x28, 0x23, 0xc5, 0x63, 0xc6, 0xbb, 0x36, 0x66, 0x34, 0xa3, 0x95, 0x82, 0x60, 0x61,
-	0xf3, 0x88, 0xc3, 0xe4, 0x42, 0x67, 0xc8, 0xd7, 0x36, 0x3e, 0x56, 0xf5, 0x99, 0x84, 0x6c, 0x2e,
-	0x82, 0x7c, 0x25, 0xdb, 0x4f, 0x05, 0xd1, 0x27, 0x70, 0x57, 0x39, 0xbc, 0x30, 0x1e, 0x42, 0xa6,
-	0xb6, 0x2c, 0x3e, 0xc1, 0x58, 0x48, 0x08, 0xfd, 0xc9, 0xf1, 0xf4, 0x8d, 0xd2, 0xef, 0x17, 0x85,
-	0xd4, 0x06, 0x2c, 0x26, 0x99, 0x2a, 0xb2, 0x93, 0x6c, 0x15, 0xb1, 0x14, 0x34, 0xaf, 0xb2, 0x55,
-	0x64, 0xcb, 0x84, 0x95, 0xd1, 0xa1, 0x1d, 0x2b, 0x9d, 0x38, 0xab, 0xd3, 0x8f, 0x89, 0xd2, 0xd9,
-	0x86, 0x7b, 0x99, 0x7e, 0x92, 0xfa, 0x98, 0xd2, 0x26, 0x4c, 0xfb, 0x4e, 0xaa, 0x47, 0x55, 0x25,
-	0x2b, 0x84, 0x91, 0x63, 0xce, 0xc1, 0x8c, 0xb2, 0x30, 0x62, 0xd4, 0x59, 0x98, 0xe7, 0xb0, 0xa2,
-	0x60, 0xa4, 0xfb, 0x15, 0xc0, 0x39, 0x03, 0x58, 0x92, 0x02, 0xbb, 0xcc, 0xf3, 0x13, 0x55, 0x33,
-	0x0e, 0xb8, 0x18, 0x53, 0x4d, 0xfb, 0xe0, 0x4b, 0x9e, 0x30, 0xf2, 0x45, 0xcb, 0xa1, 0x45, 0xec,
-	0xb3, 0xce, 0x65, 0xe6, 0xf4, 0x9a, 0xad, 0x59, 0xbe, 0xa2, 0x12, 0xc6, 0x52, 0x4c, 0xcd, 0x18,
-	0xa3, 0x53, 0x58, 0x6e, 0x44, 0x11, 0xec, 0xd5, 0x9b, 0x61, 0x1d, 0x6a, 0xe2, 0x38, 0xec, 0x87,
-	0x00, 0x67, 0x84, 0x84, 0x02, 0xe7, 0x67, 0x99, 0x0d, 0xd1, 0xce, 0xe1, 0xe1, 0x3e, 0xd7, 0xae,
-	0x52, 0x19, 0xa9, 0x50, 0x91, 0xc5, 0x80, 0xce, 0xef, 0x64, 0x0a, 0xed, 0x74, 0x75, 0x53, 0x15,
-	0x61, 0x25, 0x84, 0xfe, 0x1f, 0x2c, 0xe4, 0xe2, 0x88, 0x59, 0xd1, 0xf9, 0x3d, 0xbe, 0xfc, 0xa1,
-	0x4c, 0x1c, 0x31, 0x16, 0xea, 0xc3, 0x5a, 0x91, 0x4a, 0x12, 0x07, 0x9d, 0xdf, 0xe7, 0xca, 0xb7,
-	0xc7, 0x95, 0x55, 0x18, 0x64, 0x3a, 0x4e, 0x7d, 0x91, 0xce, 0xcf, 0x73, 0x1d, 0x1f, 0x28, 0x87,
-	0x67, 0x3a, 0x4e, 0x7f, 0xc4, 0xa4, 0xe3, 0x3f, 0xc8, 0x75, 0x9c, 0x28, 0x27, 0x1d, 0x77, 0x60,
-	0x8e, 0xee, 0x4c, 0x4c, 0xd7, 0xe9, 0xfc, 0x52, 0xac, 0xf1, 0xb4, 0x3d, 0x70, 0x5e, 0x94, 0x61,
-	0x86, 0xa6, 0xa8, 0x17, 0x00, 0x15, 0x99, 0xae, 0x3e, 0x2f, 0x57, 0x7e, 0xa1, 0xb5, 0x7f, 0xa9,
-	0x19, 0xe0, 0x05, 0xa7, 0x66, 0x18, 0xe1, 0x13, 0xf7, 0x52, 0xff, 0x0c, 0xe6, 0x8b, 0x3e, 0xd6,
-	0x2a, 0x54, 0x54, 0x10, 0x72, 0x60, 0xd5, 0xa6, 0x67, 0x13, 0x66, 0xa5, 0xd8, 0xb0, 0xf3, 0x86,
-	0xfe, 0x57, 0x1a, 0x54, 0xd5, 0x67, 0xe4, 0x67, 0x0f, 0x72, 0x16, 0x38, 0x7c, 0x9f, 0xc5, 0xce,
-	0x1e, 0xac, 0x89, 0x9e, 0xc0, 0x6c, 0x68, 0x91, 0x33, 0xb9, 0x99, 0x5a, 0xcd, 0x47, 0xc0, 0xe3,
-	0x7d, 0x8b, 0x9c, 0xf1, 0x58, 0xe0, 0x82, 0xab, 0x5f, 0x40, 0x55, 0xd1, 0xd0, 0x12, 0xcc, 0xe2,
-	0x4b, 0xcb, 0x26, 0xdc, 0xaa, 0x9d, 0x29, 0x83, 0x37, 0x51, 0x07, 0xca, 0x7c, 0x44, 0x7c, 0xff,
-	0xb7, 0x33, 0x65, 0x88, 0xf6, 0x8b, 0x3a, 0x00, 0xc5, 0xe1, 0x71, 0xa7, 0xff, 0xb9, 0x06, 0xf5,
-	0x74, 0xf8, 0xa0, 0x4f, 0xa1, 0x66, 0xf9, 0x7e, 0x40, 0x58, 0x55, 0x53, 0xee, 0x0a, 0xdf, 0x2d,
-	0x08, 0xb4, 0xc7, 0xbd, 0x44, 0x8c, 0x9f, 0xe6, 0xd2, 0x8a, 0xab, 0x9f, 0x40, 0x3b, 0x2f, 0xf0,
-	0x56, 0xe7, 0xba, 0xe7, 0xd0, 0xca, 0x2d, 0x1b, 0x6c, 0x97, 0x4b, 0xd7, 0x21, 0xaa, 0x3f, 0xcb,
-	0x0f, 0x62, 0x94, 0xc6, 0x16, 0x9c, 0x12, 0xa7, 0xd1, 0xdf, 0xfa, 0x4b, 0xa8, 0xa8, 0x05, 0xb7,
-	0x03, 0x65, 0x51, 0xd2, 0xd0, 0xc4, 0x56, 0x47, 0xb4, 0xd1, 0x42, 0x7a, 0x7f, 0xbc, 0x33, 0xc5,
-	0x77, 0xc8, 0x2f, 0xda, 0xd0, 0xe4, 0x7c, 0x33, 0x88, 0x58, 0xf0, 0xe9, 0x4f, 0xa1, 0xaa, 0x16,
-	0x48, 0x6a, 0xef, 0x89, 0x1b, 0xc5, 0x44, 0xd8, 0xc0, 0x1b, 0xd4, 0x08, 0xcf, 0x8a, 0x89, 0x34,
-	0x82, 0xfe, 0xd6, 0xff, 0x44, 0x03, 0x94, 0xaf, 0xca, 0x0c, 0xfa, 0xf4, 0x00, 0x17, 0x44, 0xf6,
-	0x19, 0x8e, 0x49, 0x64, 0x91, 0x20, 0xa2, 0x91, 0xca, 0x87, 0xde, 0x4c, 0x93, 0x07, 0x0e, 0xba,
-	0x07, 0x35, 0x55, 0x02, 0x72, 0x1d, 0x51, 0x1f, 0x00, 0x49, 0xe2, 0x02, 0xaa, 0x34, 0xe4, 0x3a,
-	0x6c, 0xff, 0x5c, 0x35, 0x40, 0x92, 0x06, 0xce, 0xe7, 0x33, 0x15, 0xad, 0x5d, 0x32, 0x2a, 0x67,
-	0x41, 0x4c, 0xd8, 0x40, 0x2e, 0x61, 0xa9, 0xf8, 0xf2, 0x10, 0xbd, 0x9f, 0x3a, 0x6b, 0xac, 0x4c,
-	0xa8, 0x28, 0x89, 0x33, 0xcd, 0x47, 0x50, 0x91, 0x5d, 0x88, 0xb2, 0xda, 0xf2, 0xa4, 0xdb, 0x43,
-	0x25, 0xa8, 0xff, 0xd7, 0x34, 0xb4, 0xf3, 0x6c, 0xea, 0xca, 0x98, 0x58, 0x44, 0x1e, 0xed, 0x78,
-	0xa3, 0xe8, 0xd4, 0x42, 0xc3, 0x66, 0x68, 0xd9, 0xc2, 0x05, 0xf4, 0x27, 0x1d, 0xbb, 0xbc, 0xb5,
-	0xa6, 0x6b, 0x30, 0xdf, 0x57, 0x83, 0x20, 0xd1, 0x65, 0xf7, 0x36, 0x54, 0xdd, 0xf0, 0x7c, 0x93,
-	0x6e, 0x87, 0xf8, 0xde, 0xba, 0x6a, 0x54, 0x28, 0x61, 0x17, 0x13, 0xc9, 0xec, 0x72, 0x66, 0x59,
-	0x31, 0xbb, 0x8c, 0xf9, 0x10, 0x66, 0xe9, 0xf1, 0x49, 0xee, 0xa4, 0xe5, 0x76, 0xee, 0xd0, 0xc5,
-	0xd1, 0xc0, 0x3f, 0x09, 0x0c, 0xce, 0x45, 0xef, 0x43, 0x85, 0x77, 0x60, 0x91, 0x4e, 0x85, 0x49,
-	0x36, 0xd5, 0xd5, 0x13, 0x61, 0x82, 0x73, 0xac, 0x3f, 0x8b, 0x08, 0xd1, 0x2e, 0x13, 0xad, 0x4e,
-	0x14, 0xed, 0x52, 0xd1, 0x1e, 0xdc, 0xb5, 0x3c, 0x2f, 0xb8, 0x30, 0xe3, 0x30, 0x08, 0x4e, 0xb0,
-	0x63, 0x8a, 0xda, 0x13, 0x9f, 0xba, 0x58, 0xee, 0xa5, 0x57, 0x99, 0xd0, 0x01, 0x97, 0xe1, 0xc5,
-	0x9e, 0x7d, 0x21, 0x81, 0x3e, 0xcf, 0xce, 0xdf, 0x1a, 0xeb, 0x70, 0x7d, 0xc2, 0x37, 0xfa, 0x5f,
-	0x9e, 0xc3, 0x5b, 0xe3, 0x11, 0x27, 0x4e, 0xb7, 0x37, 0x8f, 0x38, 0xbd, 0x07, 0xcd, 0x74, 0xc5,
-	0x76, 0xd0, 0xcf, 0x47, 0x7e, 0xe9, 0x8d, 0x91, 0xef, 0x01, 0x1a, 0xbf, 0xd8, 0x47, 0x0f, 0x53,
-	0x36, 0x2c, 0x16, 0xd4, 0x86, 0x45, 0xc4, 0x7f, 0x98, 0x8a, 0xf8, 0xe9, 0xcc, 0xb2, 0x9b, 0xb9,
-	0xdd, 0x4f, 0xa2, 0xfd, 0x3f, 0x4a, 0x50, 0x4f, 0xb3, 0x8a, 0x6a, 0x18, 0xf9, 0x08, 0x2e, 0x8d,
-	0x45, 0xb0, 0x8a, 0xc3, 0xe9, 0x6b, 0xe3, 0xf0, 0x31, 0xcc, 0xe3, 0xcb, 0x10, 0xdb, 0x04, 0x3b,
-	0x26, 0x0b, 0x48, 0xcb, 0x71, 0x22, 0x39, 0x23, 0x6e, 0x49, 0xd6, 0x20, 0x3c, 0xdf, 0xec, 0x51,
-	0x46, 0x5e, 0xbe, 0x2b, 0xe4, 0x67, 0xc7, 0xe4, 0xbb, 0x5c, 0xfe, 0x87, 0xd0, 0x52, 0xe7, 0x75,
-	0x93, 0x1b, 0x54, 0x2e, 0x36, 0xa8, 0xa9, 0xe4, 0x0e, 0x99, 0x65, 0x4f, 0xa1, 0x29, 0x0f, 0xf7,
-	0xe6, 0xb5, 0x33, 0xaa, 0x2e, 0xce, 0xfc, 0x5c, 0x6d, 0x13, 0x1a, 0x27, 0x41, 0x74, 0x61, 0x45,
-	0xb2, 0xbb, 0xca, 0x04, 0x2d, 0x21, 0xc5, 0xb4, 0xf4, 0xff, 0x9f, 0xfd, 0xc2, 0x22, 0xca, 0x6e,
-	0xf6, 0x85, 0xf5, 0xbf, 0xd4, 0xa0, 0x22, 0x71, 0x0b, 0x3f, 0xd6, 0xfb, 0xd0, 0x76, 0xfd, 0xd3,
-	0x08, 0xc7, 0x31, 0x7f, 0x8b, 0xe2, 0xaa, 0xc5, 0xbe, 0x25, 0xe8, 0xfb, 0x82, 0x4c, 0xf3, 0x3b,
-	0xce, 0x49, 0x8a, 0x02, 0x1d, 0xce, 0x0a, 0x3e, 0x84, 0xa6, 0x83, 0x4f, 0xac, 0x91, 0x47, 0x4c,
-	0x51, 0x94, 0xe0, 0x19, 0xbc, 0x21, 0xa8, 0x3d, 0x46, 0xd4, 0x9f, 0xc1, 0x9c, 0xc8, 0x12, 0x68,
-	0x11, 0xca, 0xf8, 0x92, 0x9e, 0x3d, 0x64, 0xc6, 0xc4, 0x97, 0x64, 0x10, 0x52, 0x32, 0x9b, 0x08,
-	0xa1, 0x9c, 0x7f, 0x74, 0x60, 0xa1, 0x6e, 0xc0, 0x7c, 0xc1, 0x15, 0x0d, 0x7a, 0x00, 0x0d, 0x37,
-	0x0e, 0x4c, 0xe2, 0x0e, 0x71, 0x4c, 0xac, 0xa1, 0xc4, 0xaa, 0xbb, 0x71, 0x70, 0x28, 0x69, 0x68,
-	0x09, 0xca, 0xa3, 0x90, 0x8a, 0x30, 0x48, 0xcd, 0x10, 0x2d, 0x3d, 0x84, 0xce, 0xa4, 0xeb, 0x99,
-	0x9b, 0xce, 0xa6, 0x0f, 0xa0, 0xcc, 0x2f, 0x0e, 0x44, 0x4d, 0x6c, 0x51, 0xdd, 0xae, 0x66, 0x2e,
-	0x26, 0x84, 0x90, 0xbe, 0x0e, 0xcd, 0x2c, 0x87, 0x15, 0x9e, 0x39, 0x80, 0x2c, 0x3c, 0x73, 0xc9,
-	0x5e, 0x91, 0x6d, 0x6f, 0x17, 0x07, 0x97, 0x70, 0xe7, 0xba, 0x5b, 0x9b, 0xb7, 0x59, 0x26, 0xdf,
-	0x72, 0x98, 0x83, 0x49, 0x3d, 0xbf, 0x7d, 0xba, 0x3c, 0x85, 0xc5, 0xc2, 0xdb, 0x17, 0x74, 0x17,
-	0x20, 0x1c, 0x1d, 0x7b, 0xae, 0x6d, 0x26, 0xf9, 0xbb, 0xca, 0x29, 0x5f, 0xe0, 0xab, 0xb7, 0x2e,
-	0x82, 0xe9, 0xb7, 0xa0, 0x95, 0xbb, 0x94, 0xd1, 0xff, 0xb0, 0x04, 0x4b, 0xc5, 0x17, 0x9d, 0x74,
-	0x03, 0x2d, 0xd3, 0xb1, 0xdc, 0x40, 0xcb, 0xb6, 0x5a, 0xac, 0x69, 0x2a, 0x12, 0x41, 0xcc, 0x16,
-	0x57, 0x9a, 0x81, 0xd4, 0x62, 0xcd, 0x98, 0xd3, 0x8a, 0xc9, 0xd2, 0x13, 0x45, 0xb5, 0x62, 0xb1,
-	0xbf, 0xe3, 0xd3, 0x47, 0xb5, 0x51, 0x0f, 0xca, 0x9e, 0x75, 0x8c, 0x3d, 0x59, 0x5b, 0x7b, 0xff,
-	0xda, 0x9b, 0xd8, 0xc7, 0x2f, 0x99, 0xac, 0xb8, 0x96, 0xe0, 0x8a, 0xab, 0xcf, 0xa1, 0x96, 0x22,
-	0xbf, 0xd5, 0xd2, 0xf7, 0xeb, 0xe3, 0x9e, 0x10, 0xdf, 0xf2, 0x7f, 0xea, 0x09, 0xfd, 0x15, 0xcf,
-	0x71, 0xb9, 0x87, 0x4f, 0xdf, 0x15, 0xdc, 0xaf, 0x6a, 0xdd, 0x1e, 0x2c, 0x14, 0xdd, 0xc8, 0xdf,
-	0x00, 0xb0, 0x9b, 0x07, 0xec, 0x16, 0x03, 0xde, 0xd8, 0xc2, 0x09, 0x80, 0xdb, 0xd0, 0xcc, 0x3e,
-	0xed, 0x2a, 0xb8, 0x82, 0x99, 0x09, 0x83, 0xc0, 0x13, 0x73, 0xb6, 0x95, 0x7f, 0xcc, 0xc5, 0x98,
-	0xfa, 0xfd, 0x04, 0x66, 0xc2, 0xe5, 0xca, 0xcf, 0xa0, 0x22, 0x25, 0xd8, 0xf9, 0xc4, 0x75, 0x54,
-	0x65, 0x9e, 0xfe, 0x46, 0x6b, 0x00, 0x43, 0x2b, 0xfe, 0x66, 0x84, 0x23, 0x4b, 0x9c, 0x5c, 0x2a,
-	0x46, 0x8a, 0xc2, 0x47, 0xe1, 0x86, 0xe6, 0x90, 0x1e, 0x6c, 0x54, 0xc8, 0xbb, 0xe1, 0x2b, 0x7a,
-	0x08, 0xba, 0x0b, 0x70, 0x7e, 0xe9, 0x59, 0x3e, 0xe7, 0xf2, 0xa0, 0xaf, 0x32, 0x0a, 0x65, 0xeb,
-	0xbf, 0xab, 0x41, 0x23, 0xf3, 0x52, 0x05, 0xbd, 0x03, 0x75, 0x86, 0x86, 0x7d, 0xeb, 0xd8, 0xc3,
-	0xdc, 0xce, 0x8a, 0x51, 0xa3, 0xb4, 0x6d, 0x4e, 0xa2, 0x8b, 0x02, 0xc7, 0x94, 0x32, 0xdc, 0xa6,
-	0x3a, 0x23, 0x4a, 0xa1, 0x75, 0x68, 0x67, 0x84, 0xcc, 0xf3, 0xae, 0xa8, 0xe8, 0x37, 0xd3, 0x72,
-	0x47, 0x5d, 0xfd, 0x6f, 0x35, 0x58, 0x28, 0x7a, 0x69, 0x86, 0xde, 0x4b, 0xa5, 0xb1, 0xe5, 0xc2,
-	0x92, 0x89, 0x48, 0x9f, 0x3f, 0x56, 0x73, 0x97, 0x9f, 0x8a, 0xdf, 0xbb, 0xe6, 0xfd, 0xda, 0x77,
-	0x3d, 0x73, 0x7f, 0x9c, 0x37, 0x5e, 0xdd, 0x92, 0xdf, 0xcc, 0x78, 0xbd, 0x0f, 0xed, 0x3c, 0x3d,
-	0x7b, 0x9d, 0xa1, 0xe5, 0xaf, 0x33, 0x8a, 0xae, 0x6a, 0xfe, 0x46, 0x83, 0x56, 0xee, 0x29, 0x1c,
-	0xd2, 0x53, 0x26, 0xa0, 0xfc, 0x4b, 0x37, 0xe1, 0xba, 0x8f, 0x73, 0xae, 0xd3, 0x8b, 0x9f, 0xd5,
-	0x7d, 0xd7, 0x5e, 0x7b, 0x9a, 0xb2, 0x56, 0x38, 0xec, 0x06, 0xd6, 0xea, 0xef, 0x40, 0x2d, 0x45,
-	0x2a, 0xbc, 0xed, 0x3b, 0x04, 0xe0, 0x2f, 0xda, 0x0e, 0xc5, 0x79, 0x9f, 0x46, 0xae, 0x88, 0x62,
-	0xf6, 0x9b, 0x59, 0x45, 0x23, 0x50, 0x84, 0x2d, 0x6f, 0x50, 0x97, 0xab, 0xd7, 0x06, 0xf2, 0xea,
-	0x49, 0x11, 0xf4, 0x7f, 0x2e, 0x41, 0x2d, 0xf5, 0xc6, 0x0f, 0xbd, 0x9b, 0xaa, 0x2d, 0x24, 0x0b,
-	0x1f, 0x93, 0x48, 0xae, 0x7d, 0xd1, 0x47, 0x74, 0x2e, 0xf1, 0x77, 0x9f, 0x4c, 0x9a, 0x2f, 0x93,
-	0xb7, 0x54, 0xa2, 0xa0, 0x53, 0x9e, 0x89, 0x83, 0x1b, 0xca, 0xdf, 0xd4, 0x8d, 0x4e, 0x4c, 0xe4,
-	0xf1, 0xd5, 0x89, 0x09, 0xd2, 0xa1, 0xc1, 0x8a, 0xab, 0x81, 0xc3, 0x0b, 0x5c, 0x62, 0x1a, 0xd7,
-	0x9c, 0x98, 0xec, 0x06, 0x0e, 0xab, 0x67, 0xa1, 0x35, 0xa8, 0x29, 0x19, 0x37, 0x94, 0x57, 0x60,
-	0x42, 0x62, 0x10, 0xd2, 0x03, 0x44, 0x6c, 0x0d, 0xb1, 0x19, 0x8f, 0x8e, 0x7d, 0x4c, 0xd8, 0x73,
-	0x90, 0x8a, 0x01, 0x94, 0x74, 0xc0, 0x28, 0x74, 0xde, 0xd3, 0xad, 0x77, 0x30, 0x22, 0xa7, 0x81,
-	0xeb, 0x9f, 0xb2, 0xab, 0x9e, 0x8a, 0x51, 0xf3, 0x2d, 0xb2, 0x27, 0x48, 0x74, 0x0f, 0xea, 0x05,
-	0xb6, 0xe5, 0x99, 0xb2, 0xac, 0xc0, 0xee, 0x7a, 0x2a, 0x46, 0x83, 0x51, 0xe5, 0x06, 0x03, 0x6d,
-	0x40, 0x8d, 0xb0, 0x2f, 0xc0, 0x07, 0xcd, 0x1f, 0x66, 0xc8, 0x41, 0x27, 0xdf, 0xc6, 0x00, 0xa2,
-	0x7e, 0xeb, 0xf7, 0x84, 0x7b, 0x45, 0x2c, 0x08, 0x1f, 0x94, 0x94, 0x0f, 0xf4, 0x7f, 0xd3, 0x60,
-	0x65, 0xe2, 0x9b, 0x47, 0x16, 0x08, 0x34, 0xbf, 0xc9, 0x40, 0xa0, 0x99, 0x4f, 0x94, 0x01, 0x4a,
-	0x49, 0x19, 0x20, 0xb3, 0x20, 0x4d, 0xe7, 0x36, 0x0e, 0xeb, 0xd0, 0x0e, 0xad, 0x08, 0xfb, 0xc4,
-	0x74, 0x30, 0x2b, 0x25, 0xba, 0xa1, 0xf0, 0x73, 0x93, 0xd3, 0xfb, 0x8c, 0xcc, 0x77, 0xd0, 0x43,
-	0xcb, 0xa6, 0xf9, 0x8c, 0x7b, 0x79, 0x76, 0x68, 0xd9, 0x47, 0xdd, 0xec, 0x62, 0x52, 0xce, 0xed,
-	0x3c, 0x7e, 0x00, 0x28, 0x8f, 0x7e, 0xde, 0x65, 0x5f, 0xa1, 0x6a, 0xb4, 0xb3, 0xf8, 0xe7, 0x5d,
-	0xfd, 0xc3, 0xc2, 0xb1, 0x0a, 0xdf, 0x14, 0x8c, 0x55, 0xff, 0xb9, 0x06, 0xcb, 0x13, 0x5e, 0x5e,
-	0x5e, 0xbb, 0x00, 0x66, 0x37, 0x79, 0xa5, 0xfc, 0x26, 0xef, 0x31, 0xcc, 0xbb, 0x3e, 0xc1, 0xd1,
-	0x89, 0xc5, 0x2d, 0xce, 0xb8, 0xee, 0x96, 0x62, 0xc9, 0xe3, 0xa2, 0xfe, 0xb4, 0xc0, 0x8a, 0x37,
-	0x2f, 0xc3, 0xfa, 0x1f, 0x6b, 0xb0, 0x32, 0xf1, 0x8d, 0xe1, 0xb5, 0xf6, 0xeb, 0xd0, 0x48, 0xec,
-	0xa7, 0x5f, 0x84, 0x0f, 0xa1, 0xa6, 0x86, 0x70, 0xd4, 0x1d, 0x1b, 0x44, 0x77, 0xe2, 0x20, 0xf8,
-	0xba, 0xff, 0xac, 0xd0, 0x98, 0x1b, 0x0c, 0xe3, 0xef, 0x34, 0x58, 0x2c, 0x7c, 0x43, 0x8a, 0x36,
-	0x60, 0x51, 0x16, 0xa8, 0x6d, 0x6f, 0x14, 0x13, 0x1c, 0x99, 0x74, 0x65, 0x97, 0xc5, 0xdd, 0x79,
-	0xc1, 0xdc, 0xe2, 0xbc, 0x2d, 0xca, 0x42, 0x9b, 0xc9, 0x73, 0x6a, 0x7c, 0x49, 0x70, 0xe4, 0x5b,
-	0x9e, 0x50, 0x2a, 0x89, 0xbb, 0x4c, 0xce, 0xdd, 0x16, 0x4c, 0xae, 0xf5, 0x23, 0x58, 0x95, 0x5a,
-	0x74, 0x2e, 0x1e, 0x5b, 0x9e, 0xe5, 0xdb, 0xaa, 0x3b, 0x7e, 0xb4, 0xec, 0x08, 0x89, 0x97, 0x29,
-	0x01, 0xa6, 0xad, 0x7f, 0x0d, 0x35, 0xb1, 0x14, 0xed, 0x07, 0x11, 0xa1, 0x83, 0x95, 0x85, 0x51,
-	0x39, 0x58, 0x55, 0x28, 0x45, 0x30, 0x43, 0x65, 0x64, 0x0d, 0x53, 0xca, 0xd3, 0x6c, 0xc3, 0xe8,
-	0xd3, 0x8c, 0xae, 0xda, 0xfa, 0xbf, 0x6b, 0xd0, 0xc8, 0xbc, 0x69, 0x2d, 0x3c, 0x39, 0x67, 0xd6,
-	0xbd, 0x52, 0xc1, 0xba, 0xa7, 0xde, 0xdd, 0x54, 0x45, 0x8a, 0xbd, 0x07, 0x35, 0xe9, 0x52, 0x37,
-	0x54, 0xa5, 0x3d, 0x41, 0x1a, 0x84, 0xec, 0x84, 0x9d, 0xf1, 0x84, 0x4a, 0x8e, 0xcd, 0x34, 0x79,
-	0x10, 0xd2, 0x04, 0xa8, 0x1c, 0x4d, 0xa1, 0x78, 0xa5, 0xaf, 0x26, 0x69, 0x14, 0x6b, 0x1d, 0x66,
-	0xd3, 0xd7, 0xe6, 0x28, 0xbb, 0xac, 0xb3, 0xb2, 0x2f, 0x17, 0xd0, 0x7b, 0x6a, 0xb4, 0xa9, 0x59,
-	0xfb, 0x56, 0xa3, 0x7d, 0xb4, 0x0e, 0x55, 0x75, 0x90, 0x42, 0x73, 0x30, 0xdd, 0xdb, 0xfd, 0xba,
-	0x3d, 0x85, 0x2a, 0x30, 0x33, 0xd8, 0x3f, 0xda, 0x6c, 0xcf, 0x88, 0x5f, 0xdd, 0x76, 0xf9, 0xd1,
-	0x1f, 0x69, 0x50, 0x55, 0x4b, 0x0f, 0x6a, 0x40, 0x75, 0x6b, 0xd0, 0x37, 0xcc, 0xc1, 0xee, 0xa7,
-	0x7b, 0xed, 0x29, 0x34, 0x0f, 0x2d, 0x63, 0xfb, 0xd5, 0xde, 0xe1, 0xb6, 0xf9, 0xd5, 0x9e, 0xf1,
-	0xc5, 0xcb, 0xbd, 0x5e, 0xbf, 0xad, 0xa1, 0x16, 0xd4, 0x04, 0x71, 0x67, 0xef, 0xe0, 0xb0, 0x5d,
-	0x42, 0x08, 0x9a, 0x2f, 0xf7, 0xb6, 0x7a, 0x2f, 0x13, 0xa1, 0x69, 0xd4, 0x04, 0xe0, 0x34, 0x26,
-	0x33, 0x83, 0x6e, 0x41, 0x43, 0x28, 0x1d, 0x7e, 0xb9, 0xbb, 0xbb, 0xfd, 0xb2, 0x3d, 0x8b, 0xda,
-	0x50, 0xe7, 0x22, 0x82, 0x52, 0x7e, 0xf4, 0x1c, 0x20, 0x59, 0xd7, 0xa8, 0x8d, 0xbb, 0x7b, 0xbb,
-	0xdb, 0xed, 0x29, 0x54, 0x87, 0xca, 0xee, 0x9e, 0xb9, 0xbd, 0xbb, 0xd5, 0xdb, 0x6f, 0x6b, 0xa8,
-	0x0a, 0xb3, 0x2c, 0xc1, 0xb5, 0x4b, 0x7c, 0x18, 0x83, 0xfd, 0xf6, 0xf4, 0xc6, 0x27, 0x00, 0xfc,
-	0xb1, 0x09, 0xfb, 0xef, 0xab, 0x27, 0x30, 0xc3, 0xfe, 0x2a, 0x27, 0x27, 0xff, 0xd3, 0xb5, 0x2a,
-	0x69, 0xa9, 0xff, 0xeb, 0x7a, 0xa2, 0xbd, 0x58, 0xfe, 0xc5, 0xb7, 0x6b, 0xda, 0x3f, 0x7c, 0xbb,
-	0xa6, 0xfd, 0xcb, 0xb7, 0x6b, 0xda, 0x9f, 0xfe, 0xeb, 0xda, 0xd4, 0x4f, 0x66, 0xd9, 0x65, 0xfb,
-	0x71, 0x99, 0xfd, 0xf9, 0xe8, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x30, 0x8b, 0xfe, 0xdc, 0x35,
+	// 4275 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5b, 0xcd, 0x73, 0x23, 0x49,
+	0x56, 0xb7, 0x64, 0x4b, 0x96, 0x9e, 0x2c, 0xa9, 0x3a, 0xfd, 0x25, 0xbb, 0x3f, 0xa7, 0x66, 0x7a,
+	0xc7, 0xdd, 0xbb, 0xd3, 0xd3, 0xf4, 0xb8, 0xd5, 0x3b, 0xc3, 0x32, 0x1b, 0x6a, 0xcb, 0x33, 0xad,
+	0x99, 0x6e, 0xd9, 0x94, 0x3d, 0x1e, 0x66, 0xd9, 0x88, 0xa2, 0x5c, 0x95, 0xb6, 0x8b, 0x29, 0x55,
+	0xd5, 0x54, 0xa5, 0xfc, 0xb1, 0x9c, 0x80, 0x25, 0x02, 0x82, 0x03, 0x1c, 0x08, 0x02, 0xee, 0x9c,
+	0x08, 0xfe, 0x03, 0x0e, 0x5c, 0x77, 0x83, 0x20, 0x02, 0x82, 0x33, 0x11, 0xc4, 0x70, 0x23, 0xb8,
+	0xc0, 0x5f, 0x40, 0xe4, 0x67, 0x7d, 0xa8, 0xa4, 0x76, 0x33, 0x1b, 0x9c, 0xac, 0x7c, 0x1f, 0xbf,
+	0x7c, 0xf9, 0xea, 0xe5, 0xcb, 0xcc, 0x97, 0x69, 0x40, 0x27, 0xd8, 0x73, 0x2f, 0x8f, 0x2d, 0xfb,
+	0x6b, 0xec, 0x3b, 0x8f, 0xc2, 0x28, 0x20, 0x01, 0xaa, 0x30, 0x9a, 0xde, 0x84, 0xc6, 0xc1, 0x95,
+	0x6f, 0x1b, 0xf8, 0x9b, 0x31, 0x8e, 0x89, 0xfe, 0x8f, 0x6b, 0xd0, 0x38, 0x0c, 0xfa, 0x16, 0xb1,
+	0x42, 0xcf, 0xf2, 0x31, 0xda, 0x82, 0x45, 0xd7, 0x37, 0xe3, 0x2b, 0xdf, 0xee, 0x94, 0xee, 0x95,
+	0xb6, 0x1a, 0x4f, 0x9a, 0x8f, 0x98, 0xde, 0xa3, 0x81, 0x4f, 0xd5, 0x5e, 0xcc, 0x19, 0x55, 0x97,
+	0xfd, 0x42, 0xcf, 0x60, 0xc9, 0x0d, 0x63, 0x4c, 0xcc, 0x71, 0xe8, 0x58, 0x04, 0x77, 0xca, 0x4c,
+	0x1c, 0x49, 0xf1, 0xfd, 0x03, 0x4c, 0xbe, 0x60, 0x9c, 0x17, 0x73, 0x46, 0x83, 0x49, 0xf2, 0x26,
+	0xfa, 0x14, 0x10, 0x57, 0x74, 0xb0, 0x47, 0x2c, 0xa9, 0x3e, 0xcf, 0xd4, 0xd7, 0xd3, 0xea, 0x7d,
+	0xca, 0x57, 0x18, 0x1a, 0x53, 0x4a, 0xd1, 0x12, 0x0b, 0x22, 0x3c, 0x0a, 0xce, 0x71, 0x67, 0x61,
+	0xd2, 0x02, 0x83, 0x71, 0x94, 0x05, 0xbc, 0x89, 0xf6, 0x61, 0xd5, 0xb2, 0x89, 0x7b, 0x8e, 0xcd,
+	0x30, 0x0a, 0x4e, 0x5c, 0x0f, 0x4b, 0x23, 0x2a, 0x0c, 0x61, 0x53, 0x20, 0xf4, 0x98, 0xcc, 0x3e,
+	0x17, 0x51, 0x76, 0x2c, 0x5b, 0x93, 0xe4, 0x02, 0x44, 0x61, 0x53, 0x75, 0x3a, 0xa2, 0xb2, 0x6d,
+	0xd9, 0x9a, 0x24, 0xa3, 0x57, 0xb0, 0x22, 0x11, 0x03, 0xcf, 0xb5, 0xaf, 0xa4, 0x89, 0x8b, 0x0c,
+	0x70, 0x23, 0x0b, 0xc8, 0x24, 0x94, 0x85, 0xc8, 0x9a, 0xa0, 0x4e, 0xc2, 0x09, 0xfb, 0x6a, 0x53,
+	0xe1, 0x94, 0x79, 0xc8, 0x9a, 0xa0, 0x52, 0xb8, 0xb3, 0x20, 0x26, 0x26, 0xf6, 0x9d, 0x30, 0x70,
+	0x7d, 0x15, 0x04, 0xf5, 0x0c, 0xdc, 0x8b, 0x20, 0x26, 0xbb, 0x42, 0x22, 0xb1, 0xee, 0x6c, 0x82,
+	0x3a, 0x09, 0x27, 0xac, 0x83, 0xa9, 0x70, 0x89, 0x75, 0x67, 0x13, 0x54, 0xf4, 0x15, 0x74, 0x2e,
+	0x82, 0xe8, 0x6b, 0x2f, 0xb0, 0x9c, 0x09, 0x0b, 0x1b, 0x0c, 0xf2, 0xb6, 0x80, 0xfc, 0x52, 0x88,
+	0x4d, 0x58, 0xb9, 0x76, 0x51, 0xc8, 0x29, 0x86, 0x16, 0xd6, 0x2e, 0xcd, 0x84, 0x56, 0x16, 0xaf,
+	0x5d, 0x14, 0x72, 0xd0, 0x47, 0xd0, 0xb4, 0x03, 0xff, 0xc4, 0x3d, 0x95, 0xa6, 0x36, 0x19, 0xde,
+	0xb2, 0xc0, 0xdb, 0x61, 0x3c, 0x65, 0xe0, 0x92, 0x9d, 0x6a, 0x2b, 0x07, 0x8e, 0x30, 0xb1, 0x1c,
+	0x2b, 0x99, 0x55, 0xad, 0x09, 0x07, 0xbe, 0x12, 0x12, 0xd9, 0xef, 0x91, 0xa5, 0xa2, 0x77, 0xa1,
+	0x1d, 0xd3, 0x04, 0xe1, 0xdb, 0xd8, 0xf4, 0xc7, 0xa3, 0x63, 0x1c, 0x75, 0xda, 0xf7, 0x4a, 0x5b,
+	0x0b, 0x46, 0x4b, 0x92, 0x87, 0x8c, 0x8a, 0x7a, 0xa0, 0xb9, 0xa1, 0x35, 0x32, 0xc3, 0x20, 0xf0,
+	0x64, 0x9f, 0x1a, 0xeb, 0x73, 0x55, 0x4d, 0xc3, 0xde, 0xab, 0xfd, 0x20, 0xf0, 0x54, 0x7f, 0x2d,
+	0xaa, 0x90, 0x50, 0xb2, 0x10, 0xc2, 0x93, 0x37, 0x0a, 0x21, 0x94, 0x07, 0x15, 0x44, 0x2e, 0x1a,
+	0xd5, 0xe8, 0x05, 0x0c, 0x9a, 0x3a, 0xfa, 0x6c, 0xf8, 0x64, 0xa9, 0xe8, 0x00, 0xd6, 0x62, 0x1c,
+	0x9d, 0xbb, 0x36, 0x36, 0x2d, 0xdb, 0x0e, 0xc6, 0x49, 0xf0, 0x2c, 0x33, 0xc0, 0x9b, 0x02, 0xf0,
+	0x80, 0x0b, 0xf5, 0xb8, 0x8c, 0x1a, 0xe0, 0x4a, 0x5c, 0x40, 0x2f, 0x02, 0x15, 0x56, 0xae, 0xcc,
+	0x00, 0x55, 0x76, 0xae, 0xc4, 0x05, 0x74, 0xb4, 0x03, 0x9a, 0x6f, 0x8d, 0x70, 0x1c, 0x5a, 0xb6,
+	0xca, 0x61, 0xab, 0x0c, 0x6e, 0x4d, 0xc0, 0x0d, 0x25, 0x5b, 0x99, 0xd7, 0xf6, 0xb3, 0xa4, 0x2c,
+	0x88, 0xb0, 0x69, 0xad, 0x18, 0x44, 0x99, 0xd3, 0xf6, 0xb3, 0x24, 0x9a, 0x8b, 0xa3, 0x60, 0x4c,
+	0x94, 0x15, 0xeb, 0x99, 0x5c, 0x6c, 0x50, 0x56, 0xb2, 0x1a, 0x44, 0x49, 0x33, 0x51, 0x14, 0x3d,
+	0x77, 0x26, 0x15, 0x93, 0x24, 0x1e, 0x25, 0x4d, 0xb4, 0x03, 0x8d, 0x73, 0x82, 0x43, 0xd9, 0xe1,
+	0x06, 0xd3, 0xbb, 0x27, 0xf4, 0x8e, 0x7e, 0xeb, 0x65, 0x6f, 0x78, 0x38, 0xf6, 0x7d, 0xec, 0x4d,
+	0x4c, 0x6d, 0xa0, 0x6a, 0x6a, 0xec, 0x1c, 0x44, 0x74, 0xbe, 0xf9, 0x3a, 0x10, 0x65, 0x0a, 0x03,
+	0x11, 0x96, 0xfc, 0x14, 0x36, 0x2e, 0xdc, 0x08, 0x9f, 0x8e, 0xad, 0x68, 0x32, 0xdf, 0xdc, 0x64,
+	0x90, 0x77, 0x64, 0x52, 0x90, 0x72, 0x13, 0x56, 0xad, 0x5f, 0x14, 0xb3, 0xa6, 0xa0, 0x0b, 0x83,
+	0x6f, 0xcd, 0x46, 0x57, 0xe6, 0xae, 0x5f, 0x14, 0xb3, 0xd0, 0x97, 0xd0, 0x39, 0xf5, 0x82, 0x63,
+	0xcb, 0x33, 0x8f, 0x4f, 0x43, 0x33, 0x9b, 0x7f, 0x6e, 0x33, 0xf0, 0x5b, 0x02, 0xfc, 0x53, 0x26,
+	0xf6, 0xfc, 0xd3, 0xfd, 0x5c, 0x22, 0x5a, 0xe5, 0xfa, 0xcf, 0x4f, 0xc3, 0x34, 0x03, 0xfd, 0x08,
+	0x9a, 0xd8, 0xb7, 0xad, 0x30, 0x1e, 0x7b, 0x16, 0x71, 0x03, 0xbf, 0x73, 0x87, 0xa1, 0xad, 0x08,
+	0xb4, 0xdd, 0x34, 0xef, 0xc5, 0x9c, 0x91, 0x15, 0x46, 0xbf, 0x01, 0x2d, 0x39, 0x5b, 0x84, 0x31,
+	0x77, 0x33, 0xea, 0x62, 0x96, 0x28, 0x23, 0x9a, 0x71, 0x9a, 0x90, 0x56, 0x17, 0x8e, 0xba, 0x57,
+	0xa4, 0xae, 0xdc, 0xd3, 0x8c, 0xd3, 0x04, 0x64, 0xc3, 0xad, 0x02, 0x97, 0x9f, 0x77, 0xa5, 0x2d,
+	0x6f, 0x65, 0xc2, 0x64, 0xc2, 0xeb, 0x47, 0x5d, 0x65, 0xd7, 0xc6, 0xc5, 0x34, 0xe6, 0xf4, 0x4e,
+	0x84, 0xc5, 0xfa, 0xeb, 0x3a, 0x51, 0xd6, 0x6f, 0x5c, 0x4c, 0x63, 0xa2, 0x43, 0x58, 0xcf, 0x66,
+	0xc6, 0x64, 0x10, 0x6f, 0x67, 0xd2, 0x4e, 0x3a, 0x39, 0xa6, 0xec, 0x5f, 0x39, 0x2b, 0xa0, 0x17,
+	0xa2, 0x0a, 0xab, 0xdf, 0x99, 0x81, 0x9a, 0x24, 0xb3, 0xb3, 0x02, 0x3a, 0xfa, 0x09, 0x6c, 0xe4,
+	0x50, 0xb7, 0x13, 0x6b, 0xef, 0x67, 0xd6, 0xd6, 0x0c, 0xee, 0x76, 0xca, 0xde, 0xb5, 0x0c, 0xf2,
+	0xf6, 0xb9, 0xb4, 0xb8, 0x18, 0x5b, 0xd8, 0xfc, 0xbd, 0x99, 0xd8, 0xc9, 0xba, 0x9d, 0xc7, 0xe6,
+	0x9c, 0xe7, 0x75, 0x58, 0x0c, 0xad, 0x2b, 0xba, 0xa0, 0xeb, 0xff, 0x5a, 0x81, 0xe6, 0x27, 0x51,
+	0x30, 0x4a, 0xf6, 0xd3, 0xfb, 0xb0, 0x1a, 0x46, 0x81, 0x8d, 0xe3, 0xd8, 0x8c, 0x89, 0x45, 0xc6,
+	0x71, 0x76, 0xbf, 0x2b, 0x37, 0x86, 0xfb, 0x5c, 0xe6, 0x80, 0x89, 0x24, 0x5b, 0xcd, 0x70, 0x92,
+	0x8c, 0x7e, 0x07, 0x6e, 0x66, 0xf7, 0x4a, 0x59, 0x5c, 0xbe, 0x09, 0xbe, 0x5b, 0xb0, 0x65, 0xca,
+	0x81, 0x77, 0xce, 0xa6, 0xf0, 0xa6, 0xf6, 0x20, 0xdc, 0x55, 0x79, 0x4d, 0x0f, 0xca, 0x61, 0x9d,
+	0xb3, 0x29, 0x3c, 0xe4, 0xc1, 0xdd, 0xc9, 0x5d, 0x54, 0x76, 0x1c, 0x7c, 0xe3, 0xfc, 0xf6, 0x94,
+	0xcd, 0x54, 0x6e, 0x2c, 0xb7, 0x2e, 0x66, 0xf0, 0x67, 0xf6, 0x26, 0xc6, 0xb4, 0x78, 0x8d, 0xde,
+	0xd4, 0xb8, 0x6e, 0x5d, 0xcc, 0xe0, 0x17, 0xed, 0x9d, 0x6a, 0x85, 0x7b, 0xa7, 0x23, 0x48, 0xb2,
+	0x72, 0x6e, 0xf0, 0xf5, 0x4c, 0xe6, 0x55, 0x73, 0x3f, 0x37, 0xea, 0xd5, 0x8b, 0x22, 0x06, 0xea,
+	0xc3, 0x0d, 0x47, 0xc6, 0x9f, 0x29, 0x0f, 0x73, 0x90, 0x59, 0xd0, 0x55, 0x7c, 0xaa, 0x53, 0x5d,
+	0xdb, 0xc9, 0x92, 0xd2, 0x51, 0xfd, 0x2f, 0x65, 0x58, 0xca, 0xe4, 0xf6, 0x67, 0x50, 0xe5, 0x2b,
+	0x45, 0xa7, 0x74, 0x6f, 0x3e, 0x15, 0x0b, 0x69, 0x21, 0xd1, 0xd8, 0xf5, 0x49, 0x74, 0x65, 0x08,
+	0x71, 0xf4, 0xdb, 0xb0, 0x12, 0x07, 0xe3, 0xc8, 0xc6, 0x26, 0x09, 0xcc, 0xc8, 0xba, 0x10, 0x0b,
+	0x4e, 0xa7, 0xcc, 0x60, 0x1e, 0x16, 0xc1, 0x1c, 0x30, 0xf9, 0xc3, 0xc0, 0xb0, 0x2e, 0xd2, 0x88,
+	0x37, 0xe2, 0x3c, 0x1d, 0x75, 0x60, 0x71, 0x84, 0xe3, 0xd8, 0x3a, 0xe5, 0x93, 0xab, 0x6e, 0xc8,
+	0xe6, 0xe6, 0x87, 0xd0, 0x48, 0xe9, 0x22, 0x0d, 0xe6, 0xbf, 0xc6, 0x57, 0xec, 0x7c, 0x5b, 0x37,
+	0xe8, 0x4f, 0xb4, 0x02, 0x95, 0x73, 0xcb, 0x1b, 0xf3, 0x43, 0x6c, 0xdd, 0xe0, 0x8d, 0x8f, 0xca,
+	0x3f, 0x2c, 0x6d, 0x1e, 0xc1, 0x5a, 0xb1, 0x05, 0x69, 0x94, 0x26, 0x47, 0xf9, 0x5e, 0x1a, 0xa5,
+	0xf1, 0x44, 0x93, 0x7b, 0x18, 0xa9, 0x97, 0xc2, 0xd5, 0xff, 0xa2, 0x04, 0xf5, 0xc4, 0xf4, 0x35,
+	0xa8, 0xf2, 0xf1, 0x08, 0xa3, 0x44, 0x0b, 0x6d, 0x43, 0x35, 0xe3, 0xa1, 0x5b, 0x79, 0xc8, 0x22,
+	0x2f, 0x7f, 0x87, 0xe1, 0xea, 0x35, 0xa8, 0xf2, 0xef, 0xaf, 0xff, 0x55, 0x09, 0x1a, 0xa9, 0x43,
+	0x3c, 0x6a, 0x41, 0xd9, 0x75, 0x04, 0x48, 0xd9, 0x75, 0xb8, 0xb7, 0x69, 0x1c, 0xc7, 0xcc, 0xb6,
+	0xba, 0x21, 0x9b, 0xe8, 0x31, 0x2c, 0x90, 0xab, 0x90, 0x7f, 0x84, 0x96, 0x32, 0x39, 0x85, 0xc5,
+	0x7f, 0x1f, 0x5e, 0x85, 0xd8, 0x60, 0x92, 0xfa, 0x7b, 0x50, 0x57, 0x24, 0x54, 0x85, 0xf2, 0x60,
+	0x5f, 0x9b, 0x43, 0x6d, 0xda, 0xbf, 0xd9, 0x1b, 0xf6, 0xcd, 0xfd, 0x3d, 0xe3, 0x50, 0x2b, 0xa1,
+	0x45, 0x98, 0x1f, 0xee, 0x1e, 0x6a, 0x65, 0x3d, 0x04, 0x2d, 0x5f, 0x1f, 0x98, 0x30, 0xef, 0x6d,
+	0x68, 0x5a, 0x8e, 0x83, 0x1d, 0x33, 0x6b, 0xe4, 0x12, 0x23, 0xbe, 0x12, 0x96, 0xbe, 0x0b, 0x6d,
+	0x3e, 0xff, 0x13, 0xb1, 0x79, 0x26, 0xd6, 0x12, 0x64, 0x21, 0xa8, 0xdf, 0x16, 0xbe, 0x10, 0x53,
+	0x3c, 0xd7, 0x99, 0x6e, 0xc1, 0x72, 0x41, 0xad, 0x00, 0xdd, 0x53, 0x62, 0x49, 0x30, 0x08, 0x89,
+	0x41, 0x9f, 0x59, 0xb9, 0x05, 0x8b, 0xa2, 0x5e, 0x20, 0x62, 0xa6, 0x95, 0x15, 0x33, 0x24, 0x5b,
+	0x7f, 0x96, 0xeb, 0x42, 0x58, 0xf2, 0xda, 0x2e, 0xf4, 0xbb, 0x50, 0x57, 0x04, 0x84, 0x60, 0x81,
+	0x6e, 0xdc, 0x85, 0xe9, 0xec, 0xb7, 0x1e, 0xc0, 0xa2, 0x10, 0x40, 0x8f, 0xa1, 0xe9, 0xfa, 0xc7,
+	0xc1, 0xd8, 0x77, 0xcc, 0x68, 0xec, 0xe1, 0x58, 0x4c, 0xef, 0x86, 0x8c, 0xba, 0xb1, 0x87, 0x8d,
+	0x25, 0x21, 0x41, 0x1b, 0x31, 0x7a, 0x02, 0xad, 0x60, 0x4c, 0xd2, 0x2a, 0xe5, 0x49, 0x95, 0xa6,
+	0x14, 0x61, 0x3a, 0xfa, 0x4f, 0x01, 0x4d, 0x96, 0x2d, 0xd0, 0xdd, 0xd4, 0x48, 0xda, 0x72, 0x24,
+	0x4c, 0x40, 0xf8, 0xea, 0x3e, 0x54, 0x79, 0xe9, 0xa2, 0x53, 0xce, 0x14, 0xa6, 0xb8, 0x90, 0x21,
+	0x98, 0xfa, 0xd3, 0x2c, 0xba, 0xf0, 0xd3, 0xeb, 0xd0, 0xf5, 0x27, 0x50, 0x93, 0x6d, 0xea, 0x25,
+	0xe2, 0xe2, 0x48, 0x7a, 0x89, 0xfe, 0x56, 0x9e, 0x2b, 0xa7, 0x3c, 0xf7, 0x3f, 0x25, 0xa8, 0x72,
+	0xa5, 0xff, 0x1f, 0xcf, 0xa1, 0x5b, 0x50, 0x1f, 0xfb, 0x24, 0xa2, 0x65, 0x3d, 0x87, 0x4d, 0xaf,
+	0x9a, 0x91, 0x10, 0xd0, 0x06, 0xd4, 0xc2, 0x08, 0x9b, 0x8e, 0x6f, 0x11, 0xb6, 0x0b, 0xa8, 0xd1,
+	0xe8, 0xc1, 0x7d, 0xdf, 0x22, 0x54, 0x51, 0x1d, 0xd8, 0xd8, 0xfa, 0x5d, 0x37, 0x12, 0x02, 0xfa,
+	0x3e, 0xdc, 0x08, 0x22, 0xf7, 0xd4, 0xf5, 0x2d, 0xcf, 0x8c, 0xb1, 0x87, 0x6d, 0x12, 0x44, 0x6c,
+	0xfd, 0xad, 0x1b, 0x9a, 0x64, 0x1c, 0x08, 0xba, 0xfe, 0xb7, 0x1a, 0x2c, 0x50, 0x6b, 0x68, 0xce,
+	0xb2, 0x6c, 0xb6, 0xb3, 0x17, 0x39, 0x8b, 0xb7, 0xd0, 0xfb, 0x00, 0x6e, 0x68, 0x9e, 0xe3, 0x28,
+	0xa6, 0xbc, 0x32, 0x4b, 0x02, 0x9a, 0x4a, 0x02, 0x47, 0x9c, 0x6e, 0xd4, 0xdd, 0x50, 0xfc, 0x44,
+	0xdf, 0xa7, 0x76, 0x07, 0x24, 0xb0, 0x03, 0xaf, 0x33, 0x9f, 0xfd, 0x42, 0x82, 0x6c, 0x28, 0x01,
+	0xb4, 0x0e, 0x8b, 0x71, 0x64, 0x9b, 0x3e, 0xa6, 0x63, 0x9c, 0x67, 0xa9, 0x32, 0xb2, 0x87, 0x98,
+	0xa0, 0xf7, 0xa0, 0x4e, 0x19, 0x61, 0x10, 0x91, 0xb8, 0x53, 0x61, 0xae, 0x54, 0x13, 0x22, 0x88,
+	0x88, 0x61, 0xf9, 0xa7, 0xd8, 0xa8, 0xc5, 0x91, 0x4d, 0x5b, 0x31, 0xc5, 0x71, 0x62, 0xc2, 0x70,
+	0xaa, 0x1c, 0xc7, 0x89, 0x89, 0xc0, 0xa1, 0x0c, 0x8e, 0xb3, 0x38, 0x0d, 0xc7, 0x89, 0x09, 0xc7,
+	0xb9, 0x0d, 0x75, 0xd7, 0x1e, 0x85, 0x26, 0xcb, 0x78, 0x74, 0x9d, 0xaf, 0xbc, 0x98, 0x33, 0x6a,
+	0x94, 0xc4, 0x92, 0xd9, 0xc7, 0xd0, 0x52, 0x6c, 0xd3, 0x0e, 0x1c, 0xb9, 0xb4, 0xcb, 0x85, 0x78,
+	0x20, 0x04, 0x7b, 0xbe, 0xb3, 0x13, 0x38, 0xac, 0xae, 0x23, 0x75, 0x69, 0x1b, 0xbd, 0x0d, 0x2d,
+	0x3a, 0x2a, 0x37, 0x34, 0x69, 0x9d, 0xd3, 0x75, 0xe2, 0x0e, 0x30, 0x6b, 0x1b, 0x71, 0x64, 0x0f,
+	0xc2, 0x03, 0x4c, 0x06, 0x4e, 0x4c, 0x85, 0xa8, 0xc9, 0x29, 0xa1, 0x06, 0x17, 0x72, 0x62, 0xa2,
+	0x84, 0x9e, 0xc1, 0x06, 0x73, 0x9c, 0x35, 0xc2, 0x0e, 0x1b, 0x5d, 0x5a, 0x7e, 0x89, 0xc9, 0xaf,
+	0x50, 0x57, 0x52, 0x3e, 0x1d, 0x5a, 0x5a, 0x91, 0x79, 0xaa, 0x50, 0xb1, 0xc9, 0x15, 0xa9, 0xef,
+	0x26, 0x14, 0x7f, 0x00, 0xcb, 0xc2, 0x2c, 0xa6, 0x25, 0x55, 0xda, 0x4c, 0xa5, 0xcd, 0x6c, 0xa3,
+	0xf2, 0x42, 0xfa, 0x09, 0x2c, 0xf9, 0x01, 0x31, 0x55, 0x24, 0x9c, 0x14, 0x47, 0x42, 0xc3, 0x0f,
+	0x88, 0x6c, 0xa0, 0x3b, 0x40, 0x9b, 0xa6, 0x0c, 0x88, 0x53, 0x86, 0x5c, 0xf7, 0x03, 0x72, 0xc0,
+	0x63, 0x62, 0x1b, 0x9a, 0x92, 0xcf, 0xbf, 0xe7, 0xd9, 0x94, 0xef, 0xd9, 0xe0, 0x3a, 0xfc, 0x93,
+	0x0a, 0x54, 0x19, 0x1e, 0xae, 0x42, 0xed, 0xc7, 0x24, 0x85, 0x9a, 0x44, 0xc9, 0xef, 0xce, 0x40,
+	0xed, 0xcb, 0x40, 0x79, 0x87, 0x6b, 0x25, 0xc1, 0xf2, 0x35, 0x0b, 0x96, 0x12, 0x93, 0x92, 0x61,
+	0x80, 0x76, 0x01, 0x65, 0xa4, 0x78, 0xcc, 0x78, 0x33, 0x63, 0xa6, 0x64, 0xb4, 0x53, 0x10, 0x94,
+	0x84, 0x1e, 0x02, 0x92, 0x03, 0x4f, 0x7d, 0xac, 0x11, 0x5f, 0xdb, 0xf8, 0x58, 0xd5, 0x67, 0x12,
+	0xb2, 0xb9, 0x08, 0xf2, 0x95, 0x6c, 0x3f, 0x15, 0x44, 0x1f, 0xc3, 0x6d, 0xe5, 0xf0, 0xc2, 0x78,
+	0x08, 0x99, 0xda, 0xba, 0xf8, 0x04, 0x13, 0x21, 0x21, 0xf4, 0xa7, 0xc7, 0xd3, 0x37, 0x4a, 0xbf,
+	0x5f, 0x14, 0x52, 0x4f, 0x60, 0x35, 0xc9, 0x54, 0x91, 0x9d, 0x64, 0xab, 0x88, 0xa5, 0xa0, 0x65,
+	0x95, 0xad, 0x22, 0x5b, 0x26, 0xac, 0x8c, 0x0e, 0xed, 0x58, 0xe9, 0xc4, 0x59, 0x9d, 0x7e, 0x4c,
+	0x94, 0xce, 0x2e, 0xdc, 0xcd, 0xf4, 0x93, 0xd4, 0xc7, 0x94, 0x36, 0x61, 0xda, 0xb7, 0x52, 0x3d,
+	0xaa, 0x2a, 0x59, 0x21, 0x8c, 0x1c, 0x73, 0x0e, 0x66, 0x9c, 0x85, 0x11, 0xa3, 0xce, 0xc2, 0x7c,
+	0x08, 0x1b, 0x0a, 0x46, 0xba, 0x5f, 0x01, 0x9c, 0x33, 0x80, 0x35, 0x29, 0x30, 0x64, 0x9e, 0x9f,
+	0xaa, 0x9a, 0x71, 0xc0, 0xc5, 0x84, 0x6a, 0xda, 0x07, 0x5f, 0xf0, 0x84, 0x91, 0x2f, 0x5a, 0x8e,
+	0x2c, 0x62, 0x9f, 0x75, 0x2e, 0x33, 0xa7, 0xd7, 0x6c, 0xcd, 0xf2, 0x15, 0x95, 0x30, 0xd6, 0xe2,
+	0xc8, 0x2e, 0xa0, 0x53, 0x58, 0x6e, 0x44, 0x11, 0xec, 0xd5, 0xeb, 0x61, 0x9d, 0x98, 0x14, 0xd0,
+	0xe9, 0xaa, 0x73, 0x46, 0x48, 0x28, 0x70, 0x7e, 0x96, 0xd9, 0x10, 0xbd, 0x38, 0x3c, 0xdc, 0xe7,
+	0xda, 0x75, 0x2a, 0x23, 0x15, 0x6a, 0xb2, 0x18, 0xd0, 0xf9, 0xbd, 0x4c, 0xa1, 0x9d, 0xae, 0x6e,
+	0xaa, 0x22, 0xac, 0x84, 0xd0, 0xaf, 0xc1, 0x4a, 0x2e, 0x8e, 0x98, 0x15, 0x9d, 0x3f, 0xe0, 0xcb,
+	0x1f, 0xca, 0xc4, 0x11, 0x63, 0xa1, 0x3e, 0xdc, 0x29, 0x52, 0x49, 0xe2, 0xa0, 0xf3, 0x87, 0x5c,
+	0xf9, 0xe6, 0xa4, 0xb2, 0x0a, 0x83, 0x4c, 0xc7, 0xa9, 0x2f, 0xd2, 0xf9, 0x79, 0xae, 0xe3, 0x83,
+	0xc8, 0x2e, 0xea, 0x38, 0xfd, 0x11, 0x93, 0x8e, 0xff, 0x28, 0xd7, 0x71, 0xa2, 0x9c, 0x74, 0xdc,
+	0x81, 0x45, 0xba, 0x33, 0x31, 0x5d, 0xa7, 0xf3, 0x4b, 0xb1, 0xc6, 0xd3, 0xf6, 0xc0, 0x79, 0x5e,
+	0x85, 0x05, 0x9a, 0xa2, 0x9e, 0x03, 0xd4, 0x64, 0xba, 0xfa, 0xac, 0x5a, 0xfb, 0x45, 0x49, 0xfb,
+	0x65, 0xc9, 0x00, 0x2f, 0x38, 0x35, 0xc3, 0x08, 0x9f, 0xb8, 0x97, 0xfa, 0xa7, 0xb0, 0x5c, 0xf4,
+	0xb1, 0x36, 0xa1, 0xa6, 0x82, 0x90, 0x03, 0xab, 0x36, 0x3d, 0x9b, 0x30, 0x2b, 0xc5, 0x86, 0x9d,
+	0x37, 0xf4, 0xbf, 0x29, 0x41, 0x5d, 0x7d, 0x46, 0x7e, 0xf6, 0x20, 0x67, 0x81, 0xc3, 0xf7, 0x59,
+	0x75, 0x43, 0x36, 0xd1, 0x63, 0xa8, 0x84, 0x16, 0x39, 0x93, 0x9b, 0xa9, 0xcd, 0x7c, 0x04, 0x3c,
+	0xda, 0xb7, 0xc8, 0x19, 0xfb, 0x65, 0x70, 0xc1, 0xcd, 0xcf, 0xa1, 0xae, 0x68, 0x68, 0x0d, 0x2a,
+	0xf8, 0xd2, 0xb2, 0x09, 0xb7, 0xea, 0xc5, 0x9c, 0xc1, 0x9b, 0xa8, 0x03, 0x55, 0x3e, 0x22, 0xbe,
+	0xff, 0xa3, 0xb7, 0xa0, 0xbc, 0xfd, 0x7c, 0x09, 0x80, 0xe2, 0xf0, 0xb8, 0xd3, 0xff, 0xb2, 0x04,
+	0x4b, 0xe9, 0xf0, 0x41, 0x9f, 0x40, 0xc3, 0xf2, 0xfd, 0x80, 0xb0, 0xaa, 0xa6, 0xdc, 0x15, 0xbe,
+	0x53, 0x10, 0x68, 0x8f, 0x7a, 0x89, 0x18, 0x3f, 0xcd, 0xa5, 0x15, 0x37, 0x3f, 0x06, 0x2d, 0x2f,
+	0xf0, 0x46, 0xe7, 0xba, 0x0f, 0xa1, 0x9d, 0x5b, 0x36, 0xd8, 0x2e, 0x97, 0xae, 0x43, 0x54, 0xbf,
+	0xc2, 0x0f, 0x62, 0x94, 0xc6, 0x16, 0x9c, 0x32, 0xa7, 0xd1, 0xdf, 0xfa, 0x4b, 0xa8, 0xa9, 0x05,
+	0xb7, 0x03, 0x55, 0x51, 0xd2, 0x28, 0x89, 0xad, 0x8e, 0x68, 0xa3, 0x95, 0xf4, 0xfe, 0xf8, 0xc5,
+	0x1c, 0xdf, 0x21, 0x3f, 0xd7, 0xa0, 0xc5, 0xf9, 0x66, 0x10, 0xb1, 0xe0, 0xd3, 0x9f, 0x42, 0x5d,
+	0x2d, 0x90, 0xd4, 0xde, 0x13, 0x37, 0x8a, 0x89, 0xb0, 0x81, 0x37, 0xa8, 0x11, 0x9e, 0x15, 0x13,
+	0x69, 0x04, 0xfd, 0xad, 0xff, 0x59, 0x09, 0x50, 0xbe, 0x2a, 0x33, 0xe8, 0xd3, 0x03, 0x5c, 0x10,
+	0xd9, 0x67, 0x38, 0x26, 0x91, 0x45, 0x82, 0x88, 0x46, 0x2a, 0x1f, 0x7a, 0x2b, 0x4d, 0x1e, 0x38,
+	0xe8, 0x2e, 0x34, 0x54, 0x09, 0xc8, 0x75, 0x44, 0x7d, 0x00, 0x24, 0x89, 0x0b, 0xa8, 0xd2, 0x90,
+	0xeb, 0xb0, 0xfd, 0x73, 0xdd, 0x00, 0x49, 0x1a, 0x38, 0x9f, 0x2d, 0xd4, 0x4a, 0x5a, 0xd9, 0xa8,
+	0xd1, 0x92, 0x16, 0x1b, 0xc8, 0x25, 0xac, 0x15, 0x5f, 0x1e, 0xa2, 0x07, 0xa9, 0xb3, 0xc6, 0xc6,
+	0x94, 0x8a, 0x92, 0x38, 0xd3, 0x7c, 0x00, 0x35, 0xd9, 0x45, 0xa7, 0x92, 0xb9, 0x00, 0xcf, 0x2b,
+	0x18, 0x4a, 0x50, 0xff, 0xa7, 0x05, 0xd0, 0xf2, 0x6c, 0xea, 0xca, 0x98, 0x58, 0x44, 0x1e, 0xed,
+	0x78, 0xa3, 0xe8, 0xd4, 0x42, 0xc3, 0x66, 0x64, 0xd9, 0xc2, 0x05, 0xf4, 0x27, 0x1d, 0xbb, 0xbc,
+	0xb5, 0xa6, 0x6b, 0x30, 0xdf, 0x57, 0x83, 0x20, 0xd1, 0x65, 0xf7, 0x26, 0xd4, 0xdd, 0xf0, 0x7c,
+	0x9b, 0x6e, 0x87, 0xf8, 0xde, 0xba, 0x6e, 0xd4, 0x28, 0x61, 0x88, 0x89, 0x64, 0x76, 0x39, 0xb3,
+	0xaa, 0x98, 0x5d, 0xc6, 0xbc, 0x0f, 0x15, 0x7a, 0x7c, 0x92, 0x3b, 0x69, 0xb9, 0x9d, 0x3b, 0x74,
+	0x71, 0x34, 0xf0, 0x4f, 0x02, 0x83, 0x73, 0xd1, 0x03, 0xa8, 0xf1, 0x0e, 0x2c, 0xd2, 0xa9, 0xdd,
+	0x9b, 0x4f, 0x1d, 0x84, 0x87, 0x16, 0x61, 0x82, 0x8b, 0xac, 0x3f, 0x8b, 0x08, 0xd1, 0x2e, 0x13,
+	0xad, 0x4f, 0x15, 0xed, 0x52, 0xd1, 0x1e, 0xdc, 0xb6, 0x3c, 0x2f, 0xb8, 0x30, 0xe3, 0x30, 0x08,
+	0x4e, 0xb0, 0x63, 0x8a, 0xda, 0x13, 0x9f, 0xba, 0x58, 0xee, 0xa5, 0x37, 0x99, 0xd0, 0x01, 0x97,
+	0xe1, 0xc5, 0x9e, 0x7d, 0x21, 0x81, 0x3e, 0xcb, 0xce, 0xdf, 0x06, 0xeb, 0x70, 0x6b, 0xca, 0x37,
+	0x9a, 0x3d, 0x87, 0xe9, 0x31, 0xcb, 0xf5, 0x4f, 0x23, 0x5a, 0x0a, 0x3e, 0xb6, 0x7c, 0xe7, 0xc2,
+	0x75, 0xc8, 0x19, 0xbb, 0x33, 0x9e, 0x37, 0x34, 0xc1, 0x78, 0x2e, 0xe9, 0xe8, 0x01, 0x68, 0x38,
+	0x2f, 0xdb, 0x64, 0xb2, 0x6d, 0x9c, 0x15, 0xfd, 0xce, 0xb9, 0x61, 0x67, 0x32, 0x92, 0xc5, 0xa9,
+	0xf9, 0xfa, 0x91, 0xac, 0xf7, 0xa0, 0x95, 0xae, 0x04, 0x0f, 0xfa, 0xf9, 0x19, 0x55, 0x7e, 0xed,
+	0x8c, 0xf2, 0x00, 0x4d, 0x3e, 0x18, 0x40, 0xf7, 0x53, 0x36, 0xac, 0x16, 0xd4, 0x9c, 0xc5, 0x4c,
+	0x7a, 0x3f, 0x35, 0x93, 0xe6, 0x33, 0xcb, 0x79, 0x5a, 0x38, 0x35, 0x8b, 0xfe, 0xbb, 0x0c, 0x4b,
+	0x69, 0x56, 0x51, 0x6d, 0x24, 0x3f, 0x33, 0xca, 0x13, 0x33, 0x43, 0xc5, 0xf7, 0xfc, 0xcc, 0xf8,
+	0x7e, 0x04, 0xcb, 0xf8, 0x32, 0xc4, 0x36, 0xc1, 0x8e, 0xc9, 0x02, 0xdd, 0x72, 0x9c, 0x48, 0xce,
+	0xb4, 0x1b, 0x92, 0x35, 0x08, 0xcf, 0xb7, 0x7b, 0x8e, 0x33, 0x29, 0xdf, 0x15, 0xf2, 0x95, 0x09,
+	0xf9, 0x2e, 0x97, 0xff, 0x21, 0xb4, 0x55, 0x1d, 0xc0, 0xe4, 0x06, 0x55, 0x8b, 0x0d, 0x6a, 0x29,
+	0xb9, 0x43, 0x66, 0xd9, 0x53, 0x68, 0xc9, 0xa2, 0x81, 0x39, 0x73, 0xa6, 0x2e, 0x89, 0x5a, 0x02,
+	0x57, 0xdb, 0x86, 0xe6, 0x49, 0x10, 0x5d, 0xd0, 0xca, 0x35, 0xd7, 0xaa, 0x4d, 0xd1, 0x12, 0x52,
+	0x4c, 0x4b, 0xff, 0xf5, 0xec, 0x17, 0x16, 0x51, 0x76, 0xbd, 0x2f, 0xac, 0xff, 0x75, 0x09, 0x6a,
+	0x12, 0xb7, 0xf0, 0x63, 0x3d, 0x00, 0x39, 0x8d, 0xf8, 0x1b, 0x17, 0x57, 0x6d, 0x22, 0xda, 0x82,
+	0xbe, 0x2f, 0xc8, 0x74, 0xdd, 0xc0, 0x39, 0x49, 0x51, 0xf8, 0xc3, 0x59, 0xc1, 0xfb, 0xd0, 0x72,
+	0xf0, 0x89, 0x35, 0xf6, 0x88, 0x29, 0x8a, 0x1d, 0x7c, 0x65, 0x68, 0x0a, 0x6a, 0x8f, 0x11, 0xf5,
+	0x67, 0xb0, 0x28, 0xb2, 0x0f, 0x5a, 0x85, 0x2a, 0xbe, 0xa4, 0x67, 0x1a, 0x99, 0x89, 0xf1, 0x25,
+	0x19, 0x84, 0x94, 0xcc, 0x26, 0x42, 0x28, 0xe7, 0x1f, 0x1d, 0x58, 0xa8, 0x1b, 0xb0, 0x5c, 0x70,
+	0xf5, 0x43, 0xab, 0x97, 0x6e, 0x1c, 0x98, 0xc4, 0x1d, 0xe1, 0x98, 0x58, 0x23, 0x89, 0xb5, 0xe4,
+	0xc6, 0xc1, 0xa1, 0xa4, 0xd1, 0x02, 0xcc, 0x38, 0xa4, 0x22, 0x0c, 0xb2, 0x64, 0x88, 0x96, 0x1e,
+	0x42, 0x67, 0xda, 0xb5, 0xcf, 0x75, 0x67, 0xd3, 0x7b, 0x50, 0xe5, 0x17, 0x12, 0x9d, 0x72, 0x46,
+	0x34, 0x8b, 0x69, 0x08, 0x21, 0x7d, 0x0b, 0x5a, 0x59, 0x0e, 0xb5, 0x4d, 0x00, 0xc8, 0x82, 0x36,
+	0x97, 0xec, 0x15, 0xd9, 0xf6, 0x66, 0x71, 0x70, 0x09, 0xb7, 0x66, 0xdd, 0x06, 0xbd, 0xc9, 0xf2,
+	0xfb, 0x86, 0xc3, 0x1c, 0x4c, 0xeb, 0xf9, 0xcd, 0xd3, 0xe5, 0x29, 0xac, 0x16, 0xde, 0xea, 0xa0,
+	0xdb, 0x00, 0xe1, 0xf8, 0xd8, 0x73, 0x6d, 0x33, 0xc9, 0xdf, 0x75, 0x4e, 0xf9, 0x1c, 0x5f, 0xbd,
+	0x71, 0x71, 0x4d, 0xbf, 0x01, 0xed, 0xdc, 0x65, 0x8f, 0xfe, 0xc7, 0x65, 0x58, 0x2b, 0xbe, 0x40,
+	0xa5, 0x1b, 0x73, 0x99, 0x8e, 0xe5, 0xc6, 0x5c, 0xb6, 0xd5, 0x26, 0x80, 0xa6, 0x22, 0x11, 0xc4,
+	0x6c, 0xd1, 0xa6, 0x19, 0x48, 0x6d, 0x02, 0x18, 0x73, 0x5e, 0x31, 0x59, 0x7a, 0xa2, 0xa8, 0x56,
+	0x2c, 0xf6, 0x8d, 0x7c, 0xfa, 0xa8, 0x36, 0xea, 0x41, 0xd5, 0xb3, 0x8e, 0xb1, 0x27, 0x6b, 0x76,
+	0x0f, 0x66, 0xde, 0xf0, 0x3e, 0x7a, 0xc9, 0x64, 0xc5, 0x75, 0x07, 0x57, 0xa4, 0xd7, 0x1d, 0x29,
+	0xf2, 0x1b, 0x2d, 0x7d, 0xbf, 0x39, 0xe9, 0x09, 0xf1, 0x2d, 0xff, 0xaf, 0x9e, 0xd0, 0x5f, 0x01,
+	0x4a, 0x43, 0x7e, 0x47, 0xc7, 0xe6, 0xe1, 0xbe, 0xab, 0x75, 0x7b, 0xb0, 0x52, 0x74, 0xd3, 0x7f,
+	0x0d, 0xc0, 0x6e, 0x1e, 0xb0, 0x5b, 0x0c, 0x78, 0x6d, 0x0b, 0xa7, 0x00, 0xee, 0x42, 0x2b, 0xfb,
+	0x64, 0xac, 0xe0, 0x6a, 0x67, 0x21, 0x0c, 0x02, 0x4f, 0xcc, 0xd9, 0x76, 0xfe, 0x91, 0x18, 0x63,
+	0xea, 0xf7, 0x12, 0x98, 0x29, 0x97, 0x36, 0x3f, 0x83, 0x9a, 0x94, 0x60, 0xe7, 0x1e, 0xd7, 0x51,
+	0x15, 0x7f, 0xfa, 0x1b, 0xdd, 0x01, 0x18, 0x59, 0xf1, 0x37, 0x63, 0x1c, 0x59, 0xe2, 0x44, 0x54,
+	0x33, 0x52, 0x14, 0x3e, 0x0a, 0x37, 0x34, 0x47, 0xf4, 0xc0, 0xa4, 0x42, 0xde, 0x0d, 0x5f, 0xd1,
+	0xc3, 0xd5, 0x6d, 0x80, 0xf3, 0x4b, 0xcf, 0xf2, 0x39, 0x97, 0x07, 0x7d, 0x9d, 0x51, 0x28, 0x5b,
+	0xff, 0xfd, 0x12, 0x34, 0x33, 0x2f, 0x60, 0xd0, 0x5b, 0xf4, 0x2d, 0xab, 0x1b, 0x9a, 0xd8, 0xb7,
+	0x8e, 0x3d, 0xcc, 0xed, 0xac, 0xd1, 0x57, 0xab, 0x6e, 0xb8, 0xcb, 0x49, 0x74, 0x51, 0xe0, 0x98,
+	0x52, 0x86, 0xdb, 0xb4, 0xc4, 0x88, 0x52, 0x68, 0x0b, 0xb4, 0x8c, 0x90, 0x79, 0xde, 0x15, 0x37,
+	0x05, 0xad, 0xb4, 0xdc, 0x51, 0x57, 0xff, 0xfb, 0x12, 0xac, 0x14, 0xbd, 0x60, 0x43, 0xef, 0xa6,
+	0xd2, 0xd8, 0x7a, 0x61, 0x29, 0x46, 0xa4, 0xcf, 0x1f, 0xab, 0xb9, 0xcb, 0x4f, 0xdb, 0xef, 0xce,
+	0x78, 0x17, 0xf7, 0xab, 0x9e, 0xb9, 0x3f, 0xce, 0x1b, 0xaf, 0x6e, 0xdf, 0xaf, 0x67, 0xbc, 0xde,
+	0x07, 0x2d, 0x4f, 0xcf, 0x5e, 0x93, 0x94, 0xf2, 0xd7, 0x24, 0x45, 0x57, 0x40, 0x7f, 0x57, 0x82,
+	0x76, 0xee, 0x89, 0x1d, 0xd2, 0x53, 0x26, 0xa0, 0xfc, 0x0b, 0x3a, 0xe1, 0xba, 0x8f, 0x72, 0xae,
+	0xd3, 0x8b, 0x9f, 0xeb, 0xfd, 0xaa, 0xbd, 0xf6, 0x34, 0x65, 0xad, 0x70, 0xd8, 0x35, 0xac, 0xd5,
+	0xdf, 0x82, 0x46, 0x8a, 0x54, 0x78, 0x8b, 0x78, 0x08, 0xc0, 0x5f, 0xca, 0x1d, 0x8a, 0x3a, 0x02,
+	0x8d, 0x5c, 0x11, 0xc5, 0xec, 0x37, 0xb3, 0x8a, 0x46, 0xa0, 0x08, 0x5b, 0xde, 0xa0, 0x2e, 0x57,
+	0xaf, 0x18, 0xe4, 0x95, 0x96, 0x22, 0xe8, 0xff, 0x56, 0x86, 0x46, 0xea, 0xed, 0x20, 0x7a, 0x27,
+	0x55, 0xb3, 0x48, 0x16, 0x3e, 0x26, 0x91, 0x5c, 0x27, 0xa3, 0x0f, 0xe8, 0x5c, 0xe2, 0xef, 0x49,
+	0x99, 0x34, 0x5f, 0x26, 0x6f, 0xa8, 0x44, 0x41, 0xa7, 0x3c, 0x13, 0x07, 0x37, 0x94, 0xbf, 0xa9,
+	0x1b, 0x9d, 0x98, 0xc8, 0x63, 0xb1, 0x13, 0x13, 0xa4, 0x43, 0x93, 0x15, 0x6d, 0x03, 0x87, 0x17,
+	0xce, 0xc4, 0x34, 0xa6, 0xb7, 0x2a, 0xc3, 0xc0, 0x61, 0x75, 0x32, 0x7a, 0x57, 0xa0, 0x64, 0xdc,
+	0x50, 0x5e, 0xad, 0x09, 0x89, 0x41, 0x48, 0x0f, 0x10, 0xb1, 0x35, 0xc2, 0x66, 0x3c, 0x3e, 0xa6,
+	0x77, 0x09, 0x8b, 0x3c, 0x8b, 0x50, 0xd2, 0x01, 0xa3, 0xd0, 0x79, 0x4f, 0xb7, 0xde, 0xc1, 0x98,
+	0x9c, 0x06, 0xae, 0x7f, 0xca, 0xae, 0x90, 0x6a, 0x46, 0xc3, 0xb7, 0xc8, 0x9e, 0x20, 0xd1, 0x3d,
+	0xa8, 0x17, 0xd8, 0x96, 0x67, 0xca, 0x72, 0x05, 0xbb, 0x43, 0xaa, 0x19, 0x4d, 0x46, 0x95, 0x1b,
+	0x0c, 0xf4, 0x04, 0x1a, 0x84, 0x7d, 0x01, 0x3e, 0x68, 0xfe, 0xe0, 0x43, 0x0e, 0x3a, 0xf9, 0x36,
+	0x06, 0x10, 0xf5, 0x5b, 0xbf, 0x2b, 0xdc, 0x2b, 0x62, 0x41, 0xf8, 0xa0, 0xac, 0x7c, 0xa0, 0xff,
+	0x67, 0x09, 0x36, 0xa6, 0xbe, 0xa5, 0x64, 0x81, 0x10, 0x38, 0xfc, 0x73, 0xd0, 0x40, 0x08, 0x1c,
+	0x55, 0x5e, 0x28, 0x27, 0xe5, 0x85, 0xcc, 0x82, 0x34, 0x9f, 0xdb, 0x38, 0x6c, 0x81, 0x16, 0x5a,
+	0x11, 0xf6, 0x89, 0xe9, 0x60, 0x56, 0xa2, 0x74, 0x43, 0xe1, 0xe7, 0x16, 0xa7, 0xf7, 0x19, 0x99,
+	0xef, 0xa0, 0x47, 0x96, 0x4d, 0xf3, 0x19, 0xf7, 0x72, 0x65, 0x64, 0xd9, 0x47, 0xdd, 0xec, 0x62,
+	0x52, 0xcd, 0xed, 0x3c, 0x7e, 0x00, 0x28, 0x8f, 0x7e, 0xde, 0x65, 0x5f, 0xa1, 0x6e, 0x68, 0x59,
+	0xfc, 0xf3, 0xae, 0xfe, 0x7e, 0xe1, 0x58, 0x85, 0x6f, 0x0a, 0xc6, 0xaa, 0xff, 0xbc, 0x04, 0xeb,
+	0x53, 0x5e, 0x74, 0xce, 0x5c, 0x00, 0xb3, 0x9b, 0xbc, 0x72, 0x7e, 0x93, 0xf7, 0x08, 0x96, 0x5d,
+	0x9f, 0xe0, 0xe8, 0xc4, 0xe2, 0x16, 0x67, 0x5c, 0x77, 0x43, 0xb1, 0xe4, 0x71, 0x51, 0x7f, 0x5a,
+	0x60, 0xc5, 0xeb, 0x97, 0x61, 0xfd, 0x4f, 0x4b, 0xb0, 0x31, 0xf5, 0xed, 0xe2, 0x4c, 0xfb, 0x75,
+	0x68, 0x26, 0xf6, 0xd3, 0x2f, 0xc2, 0x87, 0xd0, 0x50, 0x43, 0x38, 0xea, 0x4e, 0x0c, 0xa2, 0x3b,
+	0x75, 0x10, 0x7c, 0xdd, 0x7f, 0x56, 0x68, 0xcc, 0x35, 0x86, 0xf1, 0x0f, 0x25, 0x58, 0x2d, 0x7c,
+	0x9b, 0x4a, 0x6f, 0x7e, 0x64, 0xe1, 0xdb, 0xf6, 0xc6, 0x31, 0xc1, 0x91, 0x49, 0x57, 0x76, 0x59,
+	0x34, 0x5e, 0x16, 0xcc, 0x1d, 0xce, 0xdb, 0xa1, 0x2c, 0xb4, 0x9d, 0x3c, 0xd3, 0xc6, 0x97, 0x04,
+	0x47, 0xb4, 0x82, 0xce, 0x95, 0xca, 0xe2, 0x8e, 0x94, 0x73, 0x77, 0x05, 0x93, 0x6b, 0xfd, 0x08,
+	0x36, 0xa5, 0x16, 0x9d, 0x8b, 0xc7, 0x96, 0x67, 0xf9, 0xb6, 0xea, 0x8e, 0x1f, 0x2d, 0x3b, 0x42,
+	0xe2, 0x65, 0x4a, 0x80, 0x69, 0xeb, 0x5f, 0x41, 0x43, 0x2c, 0x45, 0xb4, 0x34, 0x8a, 0x36, 0x93,
+	0x82, 0xab, 0x1c, 0xac, 0x6c, 0xd3, 0x28, 0xa4, 0x32, 0xb2, 0x36, 0x2a, 0xe5, 0x69, 0xb6, 0x61,
+	0xf4, 0x79, 0x46, 0x57, 0x6d, 0xfd, 0xbf, 0x4a, 0xd0, 0xcc, 0xbc, 0x95, 0x2d, 0x3c, 0x39, 0x67,
+	0xd6, 0xbd, 0x72, 0xc1, 0xba, 0xa7, 0xde, 0xf3, 0xd4, 0x45, 0x8a, 0xbd, 0x0b, 0x0d, 0xe9, 0x52,
+	0x37, 0x54, 0x25, 0x43, 0x41, 0x1a, 0x84, 0xec, 0x84, 0x9d, 0xf1, 0x84, 0x4a, 0x8e, 0xad, 0x34,
+	0x79, 0x10, 0xd2, 0x04, 0xa8, 0x1c, 0xed, 0x86, 0xbc, 0x6e, 0x51, 0x37, 0x1a, 0x92, 0x46, 0xb1,
+	0xb6, 0xa0, 0x92, 0xbe, 0x8e, 0x47, 0xd9, 0x65, 0x9d, 0x8e, 0xd3, 0xe0, 0x02, 0x7a, 0x4f, 0x8d,
+	0x36, 0x35, 0x6b, 0xdf, 0x68, 0xb4, 0x0f, 0xb7, 0xe8, 0x5b, 0x24, 0xf9, 0x34, 0x61, 0x11, 0xe6,
+	0x7b, 0xc3, 0xaf, 0xb4, 0x39, 0x54, 0x83, 0x85, 0xc1, 0xfe, 0xd1, 0xb6, 0xb6, 0x20, 0x7e, 0x75,
+	0xb5, 0xea, 0xc3, 0x3f, 0xa1, 0x4f, 0xb8, 0xe4, 0xd2, 0x83, 0x9a, 0x50, 0xdf, 0x19, 0xf4, 0x0d,
+	0x73, 0x30, 0xfc, 0x64, 0x4f, 0x9b, 0x43, 0xcb, 0xd0, 0x36, 0x76, 0x5f, 0xed, 0x1d, 0xee, 0x9a,
+	0x5f, 0xee, 0x19, 0x9f, 0xbf, 0xdc, 0xeb, 0xf5, 0xb5, 0x12, 0x7d, 0xd2, 0x24, 0x88, 0x2f, 0xf6,
+	0x0e, 0x0e, 0xb5, 0x32, 0x42, 0xd0, 0x7a, 0xb9, 0xb7, 0xd3, 0x7b, 0x99, 0x08, 0xcd, 0xa3, 0x16,
+	0x00, 0xa7, 0x31, 0x99, 0x05, 0x74, 0x03, 0x9a, 0x42, 0xe9, 0xf0, 0x8b, 0xe1, 0x70, 0xf7, 0xa5,
+	0x56, 0x41, 0x1a, 0x2c, 0x71, 0x11, 0x41, 0xa9, 0x3e, 0xfc, 0x10, 0x20, 0x59, 0xd7, 0xa8, 0x8d,
+	0xc3, 0xbd, 0xe1, 0xae, 0x36, 0x87, 0x96, 0xa0, 0x36, 0xdc, 0x33, 0x77, 0x87, 0x3b, 0xbd, 0x7d,
+	0xad, 0x84, 0xea, 0x50, 0x61, 0x09, 0x4e, 0x2b, 0xf3, 0x61, 0x0c, 0xf6, 0xb5, 0xf9, 0x27, 0x1f,
+	0x03, 0xf0, 0x47, 0x2c, 0xec, 0xbf, 0xba, 0x1e, 0xc3, 0x02, 0xfb, 0xab, 0x9c, 0x9c, 0xfc, 0xaf,
+	0xd8, 0xa6, 0xa4, 0xa5, 0xfe, 0x5f, 0xec, 0x71, 0xe9, 0xf9, 0xfa, 0x2f, 0xbe, 0xbd, 0x53, 0xfa,
+	0xe7, 0x6f, 0xef, 0x94, 0xfe, 0xfd, 0xdb, 0x3b, 0xa5, 0x3f, 0xff, 0x8f, 0x3b, 0x73, 0x3f, 0xa9,
+	0xb0, 0x4b, 0xfc, 0xe3, 0x2a, 0xfb, 0xf3, 0xc1, 0xff, 0x0e, 0x00, 0x71, 0x0e, 0x87, 0x84, 0x8d,
 	0x36, 0x00, 0x00,
 }
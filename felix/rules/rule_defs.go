@@ -67,6 +67,10 @@ const (
 	IPSetIDAllVXLANSourceNets = "all-vxlan-net"
 	IPSetIDThisHostIPs        = "this-host"
 
+	// IPSetIDXDPBlocklist holds the nets of the GlobalNetworkSets that the XDP DDoS protection
+	// of the host endpoints blocks.
+	IPSetIDXDPBlocklist = "xdp-blocklist"
+
 	ChainFIPDnat = ChainNamePrefix + "fip-dnat"
 	ChainFIPSnat = ChainNamePrefix + "fip-snat"
 
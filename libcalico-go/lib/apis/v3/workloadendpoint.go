@@ -81,6 +81,18 @@ type WorkloadEndpointSpec struct {
 	// AllowSpoofedSourcePrefixes is a list of CIDRs that the endpoint should be able to send traffic from,
 	// bypassing the RPF check.
 	AllowSpoofedSourcePrefixes []string `json:"allowSpoofedSourcePrefixes,omitempty" validate:"omitempty,dive,cidr"`
+	// QoSControls contains the bandwidth limits of the endpoint.
+	QoSControls *QoSControls `json:"qosControls,omitempty"`
+}
+
+// QoSControls contains the bandwidth limits of a WorkloadEndpoint.
+type QoSControls struct {
+	// IngressBandwidth is the bandwidth limit, in bits per second, of the traffic to the endpoint.
+	// Zero means no limit.
+	IngressBandwidth int64 `json:"ingressBandwidth,omitempty" validate:"gte=0"`
+	// EgressBandwidth is the bandwidth limit, in bits per second, of the traffic from the endpoint.
+	// Zero means no limit.
+	EgressBandwidth int64 `json:"egressBandwidth,omitempty" validate:"gte=0"`
 }
 
 // WorkloadEndpointPort represents one endpoint's named or mapped port
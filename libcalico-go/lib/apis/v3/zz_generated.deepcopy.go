@@ -601,6 +601,22 @@ func (in *OrchRef) DeepCopy() *OrchRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSControls) DeepCopyInto(out *QoSControls) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSControls.
+func (in *QoSControls) DeepCopy() *QoSControls {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSControls)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadEndpoint) DeepCopyInto(out *WorkloadEndpoint) {
 	*out = *in
@@ -706,6 +722,11 @@ func (in *WorkloadEndpointSpec) DeepCopyInto(out *WorkloadEndpointSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.QoSControls != nil {
+		in, out := &in.QoSControls, &out.QoSControls
+		*out = new(QoSControls)
+		**out = **in
+	}
 	return
 }
 
@@ -156,6 +156,46 @@ type SyncerParseFailCallbacks interface {
 	ParseFailed(rawKey string, rawValue string)
 }
 
+// SyncerResourceTypeCallbacks is an optional interface that can be implemented
+// by a Syncer callback that wants to know which of the syncer's resource types
+// each update came from and the datastore revision that each resource type has
+// reached, for example, to save them and resume a later Syncer from them (see
+// SyncerResumeCallbacks).  Syncers that support it call OnResourceTypeUpdates
+// in place of OnUpdates.
+type SyncerResourceTypeCallbacks interface {
+	SyncerCallbacks
+
+	// OnResourceTypeUpdates is called with updates that all came from the
+	// resource type with the given list root (as returned by
+	// model.ListOptionsToDefaultPathRoot).  revision is the revision that the
+	// resource type has reached once the updates are applied, or "" if it
+	// can't be resumed from, for example, because the resource type is in the
+	// middle of a resync.  updates is empty if only the revision has changed.
+	OnResourceTypeUpdates(listRoot string, revision string, updates []Update)
+}
+
+// SyncerResumeCallbacks is an optional interface that can be implemented by a
+// Syncer callback that already holds the state from an earlier Syncer, as
+// reported to OnResourceTypeUpdates.  Syncers that support it resume watching
+// from that state instead of sending all the resources again.
+type SyncerResumeCallbacks interface {
+	SyncerResourceTypeCallbacks
+
+	// ResumeState returns the state of each resource type, keyed by list root.
+	ResumeState() map[string]ResourceTypeState
+}
+
+// ResourceTypeState is the state of one of a Syncer's resource types that a
+// later Syncer can resume from.
+type ResourceTypeState struct {
+	// Revision is the revision that the resource type had reached, "" if it
+	// can't be resumed from.
+	Revision string
+	// KVs contains the key and revision of each KV that the resource type had
+	// sent; the values are not needed.
+	KVs []model.KVPair
+}
+
 // Update from the Syncer.  A KV pair plus extra metadata.
 type Update struct {
 	model.KVPair
@@ -40,6 +40,11 @@ const (
 	// on older Pods.
 	AnnotationContainerID = "cni.projectcalico.org/containerID"
 
+	// AnnotationIngressBandwidth and AnnotationEgressBandwidth are the standard Kubernetes annotations
+	// that limit the bandwidth of a pod, as quantities of bits per second, for example "10M".
+	AnnotationIngressBandwidth = "kubernetes.io/ingress-bandwidth"
+	AnnotationEgressBandwidth  = "kubernetes.io/egress-bandwidth"
+
 	// NameLabel is a label that can be used to match a serviceaccount or namespace
 	// name exactly.
 	NameLabel = "projectcalico.org/name"
@@ -25,6 +25,7 @@ import (
 	"github.com/projectcalico/api/pkg/lib/numorstring"
 	log "github.com/sirupsen/logrus"
 	kapiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
@@ -185,6 +186,8 @@ func (wc defaultWorkloadEndpointConverter) podToDefaultWorkloadEndpoint(pod *kap
 		return nil, err
 	}
 
+	qosControls := handleQoSControlsAnnotations(pod.Annotations)
+
 	// Map any named ports through.
 	var endpointPorts []libapiv3.WorkloadEndpointPort
 	endpointPorts = appendEndpointPorts(endpointPorts, pod, pod.Spec.Containers)
@@ -217,6 +220,7 @@ func (wc defaultWorkloadEndpointConverter) podToDefaultWorkloadEndpoint(pod *kap
 		IPNATs:                     floatingIPs,
 		ServiceAccountName:         pod.Spec.ServiceAccountName,
 		AllowSpoofedSourcePrefixes: sourcePrefixes,
+		QoSControls:                qosControls,
 	}
 
 	if v, ok := pod.Annotations["k8s.v1.cni.cncf.io/network-status"]; ok {
@@ -296,3 +300,41 @@ func HandleSourceIPSpoofingAnnotation(annot map[string]string) ([]string, error)
 	}
 	return sourcePrefixes, nil
 }
+
+// Limits of the bandwidth annotations, the same as the ones of the CNI bandwidth plugin.
+const (
+	minBandwidth = 1000
+	maxBandwidth = 1000 * 1000 * 1000 * 1000 * 1000
+)
+
+// handleQoSControlsAnnotations parses the bandwidth annotations of a pod.  Invalid values are
+// ignored so that they do not stop the rest of the pod from being networked.
+func handleQoSControlsAnnotations(annot map[string]string) *libapiv3.QoSControls {
+	parse := func(name string) int64 {
+		str, ok := annot[name]
+		if !ok || str == "" {
+			return 0
+		}
+		q, err := resource.ParseQuantity(str)
+		if err != nil {
+			log.WithError(err).WithField(name, str).Warn("Ignoring invalid bandwidth annotation")
+			return 0
+		}
+		bw := q.Value()
+		if bw < minBandwidth || bw > maxBandwidth {
+			log.WithField(name, str).Warnf("Ignoring bandwidth annotation outside of the range %d-%d",
+				minBandwidth, maxBandwidth)
+			return 0
+		}
+		return bw
+	}
+
+	qc := &libapiv3.QoSControls{
+		IngressBandwidth: parse(AnnotationIngressBandwidth),
+		EgressBandwidth:  parse(AnnotationEgressBandwidth),
+	}
+	if qc.IngressBandwidth == 0 && qc.EgressBandwidth == 0 {
+		return nil
+	}
+	return qc
+}
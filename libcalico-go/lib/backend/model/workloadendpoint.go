@@ -163,6 +163,13 @@ type WorkloadEndpoint struct {
 	GenerateName               string            `json:"generate_name,omitempty"`
 	AllowSpoofedSourcePrefixes []net.IPNet       `json:"allow_spoofed_source_ips,omitempty"`
 	Annotations                map[string]string `json:"annotations,omitempty"`
+	QoSControls                *QoSControls      `json:"qos_controls,omitempty"`
+}
+
+// QoSControls contains the bandwidth limits, in bits per second, of a workload endpoint.
+type QoSControls struct {
+	IngressBandwidth int64 `json:"ingress_bandwidth,omitempty"`
+	EgressBandwidth  int64 `json:"egress_bandwidth,omitempty"`
 }
 
 type EndpointPort struct {
@@ -102,3 +102,7 @@ func (sup *simpleUpdateProcessor) Process(kvp *model.KVPair) ([]*model.KVPair, e
 func (sup *simpleUpdateProcessor) OnSyncerStarting() {
 	// Do nothing
 }
+
+// Stateless implements watchersyncer.StatelessUpdateProcessor.
+func (sup *simpleUpdateProcessor) Stateless() {
+}
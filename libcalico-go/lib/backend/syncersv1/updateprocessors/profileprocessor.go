@@ -101,6 +101,10 @@ func (pup *profileUpdateProcessor) OnSyncerStarting() {
 	// Do nothing
 }
 
+// Stateless implements watchersyncer.StatelessUpdateProcessor.
+func (pup *profileUpdateProcessor) Stateless() {
+}
+
 func convertProfileV2ToV1Value(val interface{}) (*model.Profile, error) {
 	v3res, ok := val.(*apiv3.Profile)
 	if !ok {
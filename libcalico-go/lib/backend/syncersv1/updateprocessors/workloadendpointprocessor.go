@@ -163,6 +163,14 @@ func convertWorkloadEndpointV2ToV1Value(val interface{}) (interface{}, error) {
 		}
 	}
 
+	var qosControls *model.QoSControls
+	if qc := v3res.Spec.QoSControls; qc != nil && (qc.IngressBandwidth != 0 || qc.EgressBandwidth != 0) {
+		qosControls = &model.QoSControls{
+			IngressBandwidth: qc.IngressBandwidth,
+			EgressBandwidth:  qc.EgressBandwidth,
+		}
+	}
+
 	v1value := &model.WorkloadEndpoint{
 		State:                      "active",
 		Name:                       v3res.Spec.InterfaceName,
@@ -179,6 +187,7 @@ func convertWorkloadEndpointV2ToV1Value(val interface{}) (interface{}, error) {
 		GenerateName:               v3res.GenerateName,
 		AllowSpoofedSourcePrefixes: allowedSources,
 		Annotations:                v3res.GetObjectMeta().GetAnnotations(),
+		QoSControls:                qosControls,
 	}
 
 	return v1value, nil
@@ -153,6 +153,7 @@ var _ = Describe("Test the WorkloadEndpoint update processor", func() {
 			},
 		}
 		res.Spec.AllowSpoofedSourcePrefixes = []string{"8.8.8.8/32"}
+		res.Spec.QoSControls = &libapiv3.QoSControls{EgressBandwidth: 1000000}
 
 		kvps, err = up.Process(&model.KVPair{
 			Key:      v3WorkloadEndpointKey2,
@@ -185,6 +186,7 @@ var _ = Describe("Test the WorkloadEndpoint update processor", func() {
 						},
 					},
 					AllowSpoofedSourcePrefixes: []cnet.IPNet{cnet.MustParseCIDR("8.8.8.8/32")},
+					QoSControls:                &model.QoSControls{EgressBandwidth: 1000000},
 				},
 				Revision: "1234",
 			},
@@ -33,10 +33,11 @@ import (
 // channel is untyped - however the watcherSyncer only expects one of the following
 // types:
 // -  An error
-// -  An api.Update
+// -  A resourceTypeUpdates
 // -  A api.SyncStatus (only for the very first InSync notification)
 type watcherCache struct {
 	logger               *logrus.Entry
+	listRoot             string
 	client               api.Client
 	watch                api.WatchInterface
 	resources            map[string]cacheEntry
@@ -46,6 +47,17 @@ type watcherCache struct {
 	resourceType         ResourceType
 	currentWatchRevision string
 	resyncBlockedUntil   time.Time
+	// resyncing is set while we're listing the resources, currentWatchRevision can't be resumed from
+	// until the list is complete.
+	resyncing bool
+}
+
+// resourceTypeUpdates is sent from a watcherCache to the main WatcherSyncer with a batch of updates and
+// the revision that they bring the resource type to.
+type resourceTypeUpdates struct {
+	listRoot string
+	revision string
+	updates  []api.Update
 }
 
 var (
@@ -65,8 +77,10 @@ type cacheEntry struct {
 
 // Create a new watcherCache.
 func newWatcherCache(client api.Client, resourceType ResourceType, results chan<- interface{}) *watcherCache {
+	listRoot := model.ListOptionsToDefaultPathRoot(resourceType.ListInterface)
 	return &watcherCache{
-		logger:               logrus.WithField("ListRoot", model.ListOptionsToDefaultPathRoot(resourceType.ListInterface)),
+		logger:               logrus.WithField("ListRoot", listRoot),
+		listRoot:             listRoot,
 		client:               client,
 		resourceType:         resourceType,
 		results:              results,
@@ -76,6 +90,41 @@ func newWatcherCache(client api.Client, resourceType ResourceType, results chan<
 	}
 }
 
+// resume seeds the cache with the state from an earlier watcherCache so that it only sends the changes
+// since then.  If the resource type can be resumed, we watch from the saved revision instead of listing
+// the resources again.  Otherwise, we list them, only sending updates for those that have changed and
+// deletions for those that are no longer present.  Must be called before run.
+func (wc *watcherCache) resume(state api.ResourceTypeState) {
+	for _, kv := range state.KVs {
+		wc.resources[kv.Key.String()] = cacheEntry{
+			revision: kv.Revision,
+			key:      kv.Key,
+		}
+	}
+	_, stateless := wc.resourceType.UpdateProcessor.(StatelessUpdateProcessor)
+	canResume := wc.resourceType.UpdateProcessor == nil || stateless
+	if state.Revision != "" && canResume {
+		wc.currentWatchRevision = state.Revision
+	}
+	wc.logger.WithFields(logrus.Fields{
+		"numKVs":   len(state.KVs),
+		"revision": wc.currentWatchRevision,
+	}).Info("Resuming from earlier state")
+}
+
+// sendUpdates sends updates to the main WatcherSyncer along with the revision that they bring us to.
+func (wc *watcherCache) sendUpdates(updates []api.Update) {
+	revision := wc.currentWatchRevision
+	if wc.resyncing {
+		revision = ""
+	}
+	wc.results <- resourceTypeUpdates{
+		listRoot: wc.listRoot,
+		revision: revision,
+		updates:  updates,
+	}
+}
+
 // run creates the watcher and loops indefinitely reading from the watcher.
 func (wc *watcherCache) run(ctx context.Context) {
 	wc.logger.Debug("Watcher cache starting, start initial sync processing")
@@ -134,13 +183,14 @@ mainLoop:
 
 	// The watcher cache has exited. This can only mean that it has been shutdown, so emit all updates in the cache as
 	// delete events.
+	wc.resyncing = true
 	for _, value := range wc.resources {
-		wc.results <- []api.Update{{
+		wc.sendUpdates([]api.Update{{
 			UpdateType: api.UpdateTypeKVDeleted,
 			KVPair: model.KVPair{
 				Key: value.key,
 			},
-		}}
+		}})
 	}
 }
 
@@ -179,6 +229,7 @@ func (wc *watcherCache) resyncAndCreateWatcher(ctx context.Context) {
 
 		if performFullResync {
 			wc.logger.Info("Full resync is required")
+			wc.resyncing = true
 
 			// Notify the converter that we are resyncing.
 			if wc.resourceType.UpdateProcessor != nil {
@@ -219,6 +270,8 @@ func (wc *watcherCache) resyncAndCreateWatcher(ctx context.Context) {
 
 			// Store the current watch revision.  This gets updated on any new add/modified event.
 			wc.currentWatchRevision = l.Revision
+			wc.resyncing = false
+			wc.sendUpdates(nil)
 
 			// Mark the resync as complete.
 			performFullResync = false
@@ -253,6 +306,11 @@ func (wc *watcherCache) resyncAndCreateWatcher(ctx context.Context) {
 		// Store the watcher and exit back to the main event loop.
 		wc.logger.Debug("Resync completed, now watching for change events")
 		wc.watch = w
+		if !wc.hasSynced {
+			// We resumed from an earlier state without listing, we're in sync as soon as we're watching.
+			wc.sendUpdates(nil)
+			wc.finishResync()
+		}
 		return
 	}
 }
@@ -312,7 +370,7 @@ func (wc *watcherCache) finishResync() {
 				},
 			})
 		}
-		wc.results <- updates
+		wc.sendUpdates(updates)
 	}
 	wc.oldResources = nil
 }
@@ -370,10 +428,10 @@ func (wc *watcherCache) handleAddedOrModifiedUpdate(kvp *model.KVPair) {
 		}
 		// Resource is modified, send an update event and store the latest revision.
 		wc.logger.WithField("Key", thisKeyString).Debug("Datastore entry modified, sending syncer update")
-		wc.results <- []api.Update{{
+		wc.sendUpdates([]api.Update{{
 			UpdateType: api.UpdateTypeKVUpdated,
 			KVPair:     *kvp,
-		}}
+		}})
 		resource.revision = thisRevision
 		wc.resources[thisKeyString] = resource
 		return
@@ -382,10 +440,10 @@ func (wc *watcherCache) handleAddedOrModifiedUpdate(kvp *model.KVPair) {
 	// The resource has not been seen before, so send a new event, and store the
 	// current revision.
 	wc.logger.WithField("Key", thisKeyString).Debug("Cache entry added, sending syncer update")
-	wc.results <- []api.Update{{
+	wc.sendUpdates([]api.Update{{
 		UpdateType: api.UpdateTypeKVNew,
 		KVPair:     *kvp,
-	}}
+	}})
 	wc.resources[thisKeyString] = cacheEntry{
 		revision: thisRevision,
 		key:      thisKey,
@@ -401,12 +459,12 @@ func (wc *watcherCache) handleDeletedUpdate(key model.Key) {
 	// from the cache.
 	if _, ok := wc.resources[thisKeyString]; ok {
 		wc.logger.WithField("Key", thisKeyString).Debug("Datastore entry deleted, sending syncer update")
-		wc.results <- []api.Update{{
+		wc.sendUpdates([]api.Update{{
 			UpdateType: api.UpdateTypeKVDeleted,
 			KVPair: model.KVPair{
 				Key: key,
 			},
-		}}
+		}})
 		delete(wc.resources, thisKeyString)
 	}
 }
@@ -60,15 +60,37 @@ type SyncerUpdateProcessor interface {
 	OnSyncerStarting()
 }
 
+// StatelessUpdateProcessor is implemented by SyncerUpdateProcessors that keep no state between calls
+// to Process.  When resuming from an earlier state (see api.SyncerResumeCallbacks), resource types with
+// no processor or a stateless one resume watching from the saved revision; the others are listed again
+// so that their processors can rebuild their state.
+type StatelessUpdateProcessor interface {
+	SyncerUpdateProcessor
+	// Stateless is a marker method; it does nothing.
+	Stateless()
+}
+
 // New creates a new multiple Watcher-backed api.Syncer.
+//
+// If the callbacks implement api.SyncerResourceTypeCallbacks, the updates are sent to
+// OnResourceTypeUpdates, one resource type at a time.  If they implement api.SyncerResumeCallbacks,
+// the syncer resumes from their state.
 func New(client api.Client, resourceTypes []ResourceType, callbacks api.SyncerCallbacks) api.Syncer {
 	rs := &watcherSyncer{
 		watcherCaches: make([]*watcherCache, len(resourceTypes)),
 		results:       make(chan interface{}, 2000),
 		callbacks:     callbacks,
 	}
+	rs.resourceTypeCallbacks, _ = callbacks.(api.SyncerResourceTypeCallbacks)
+	var resumeState map[string]api.ResourceTypeState
+	if rc, ok := callbacks.(api.SyncerResumeCallbacks); ok {
+		resumeState = rc.ResumeState()
+	}
 	for i, r := range resourceTypes {
 		rs.watcherCaches[i] = newWatcherCache(client, r, rs.results)
+		if state, ok := resumeState[rs.watcherCaches[i].listRoot]; ok {
+			rs.watcherCaches[i].resume(state)
+		}
 	}
 	return rs
 }
@@ -80,9 +102,15 @@ type watcherSyncer struct {
 	results       chan interface{}
 	numSynced     int
 	callbacks     api.SyncerCallbacks
-	wgwc          *sync.WaitGroup
-	wgws          *sync.WaitGroup
-	cancel        context.CancelFunc
+	// resourceTypeCallbacks is set if the callbacks want the updates of each resource type separately.
+	// In that case, the pending updates all belong to pendingListRoot and bring it to pendingRevision.
+	resourceTypeCallbacks api.SyncerResourceTypeCallbacks
+	pendingListRoot       string
+	pendingRevision       string
+	revisionPending       bool
+	wgwc                  *sync.WaitGroup
+	wgws                  *sync.WaitGroup
+	cancel                context.CancelFunc
 }
 
 func (ws *watcherSyncer) Start() {
@@ -178,14 +206,22 @@ func (ws *watcherSyncer) processResult(updates []api.Update, result interface{})
 
 	// Switch on the result type.
 	switch r := result.(type) {
-	case []api.Update:
+	case resourceTypeUpdates:
+		if ws.resourceTypeCallbacks != nil && r.listRoot != ws.pendingListRoot {
+			// Callbacks want the updates of each resource type separately.
+			updates = ws.sendUpdates(updates)
+		}
+
 		// This is an update.  If we don't have previous updates then also check to see
 		// if we need to shift the status into Resync.
 		// We append these updates to the previous if there were any.
-		if len(updates) == 0 && ws.status == api.WaitForDatastore {
+		if len(updates) == 0 && len(r.updates) > 0 && ws.status == api.WaitForDatastore {
 			ws.sendStatusUpdate(api.ResyncInProgress)
 		}
-		updates = append(updates, r...)
+		updates = append(updates, r.updates...)
+		ws.pendingListRoot = r.listRoot
+		ws.pendingRevision = r.revision
+		ws.revisionPending = true
 
 	case error:
 		// Received an error.  Firstly, send any updates that we have grouped.
@@ -232,6 +268,13 @@ func (ws *watcherSyncer) processResult(updates []api.Update, result interface{})
 // sendUpdates is used to send the consolidated set of updates.  Returns nil.
 func (ws *watcherSyncer) sendUpdates(updates []api.Update) []api.Update {
 	log.WithField("NumUpdates", len(updates)).Debug("Sending syncer updates (if any to send)")
+	if ws.resourceTypeCallbacks != nil {
+		if len(updates) > 0 || ws.revisionPending {
+			ws.resourceTypeCallbacks.OnResourceTypeUpdates(ws.pendingListRoot, ws.pendingRevision, updates)
+		}
+		ws.revisionPending = false
+		return nil
+	}
 	if len(updates) > 0 {
 		ws.callbacks.OnUpdates(updates)
 	}
@@ -617,6 +617,74 @@ var _ = Describe("Test the backend datastore multi-watch syncer", func() {
 		}, false)
 	})
 
+	It("should resume from the saved revisions and only send changes", func() {
+		rs := newResumingWatcherSyncerTester([]watchersyncer.ResourceType{r1, r2}, map[string]api.ResourceTypeState{
+			model.ListOptionsToDefaultPathRoot(r1.ListInterface): {
+				Revision: "100",
+				KVs:      []model.KVPair{{Key: l1Key1, Revision: "1"}},
+			},
+			// A resource type that can't be resumed from is listed again.
+			model.ListOptionsToDefaultPathRoot(r2.ListInterface): {
+				KVs: []model.KVPair{{Key: l2Key1, Revision: "5"}, {Key: l2Key2, Revision: "6"}},
+			},
+		})
+		rs.ExpectStatusUpdate(api.WaitForDatastore)
+
+		// r1 watches from its saved revision without listing and is in sync as soon as it is watching.
+		rs.clientWatchResponse(r1, nil)
+		Eventually(rs.fc.getLatestWatchRevision).Should(Equal("100"))
+		rs.ExpectStatusUpdate(api.ResyncInProgress)
+
+		// r2 lists, unchanged resources are not sent again.
+		rs.clientListResponse(r2, &model.KVPairList{
+			Revision: "200",
+			KVPairs:  []*model.KVPair{{Key: l2Key1, Value: "v", Revision: "5"}},
+		})
+		rs.ExpectStatusUpdate(api.InSync)
+		Eventually(rs.fc.getLatestWatchRevision).Should(Equal("200"))
+		rs.clientWatchResponse(r2, nil)
+		Eventually(rs.resumeCallbacks.updateTypes).Should(Equal(map[model.Key]api.UpdateType{
+			l2Key2: api.UpdateTypeKVDeleted,
+		}))
+		Eventually(func() string { return rs.resumeCallbacks.revision(r2) }).Should(Equal("200"))
+
+		// Changes to the resumed resources are sent as updates, along with the revision.
+		event := modifiedEvent(l1Key1)
+		rs.sendEvent(r1, event)
+		Eventually(rs.resumeCallbacks.updateTypes).Should(HaveKeyWithValue(l1Key1, api.UpdateTypeKVUpdated))
+		Eventually(func() string { return rs.resumeCallbacks.revision(r1) }).Should(Equal(event.New.Revision))
+		rs.expectAllEventsHandled()
+	})
+
+	It("should list resource types with stateful converters when resuming", func() {
+		rc1 := watchersyncer.ResourceType{
+			UpdateProcessor: &statefulConverter{},
+			ListInterface:   model.ResourceListOptions{Kind: apiv3.KindNetworkPolicy},
+		}
+		rs := newResumingWatcherSyncerTester([]watchersyncer.ResourceType{rc1}, map[string]api.ResourceTypeState{
+			model.ListOptionsToDefaultPathRoot(rc1.ListInterface): {
+				Revision: "100",
+				KVs:      []model.KVPair{{Key: l1Key1, Revision: "1"}, {Key: l1Key2, Revision: "2"}},
+			},
+		})
+		rs.ExpectStatusUpdate(api.WaitForDatastore)
+		rs.clientListResponse(rc1, &model.KVPairList{
+			Revision: "300",
+			KVPairs: []*model.KVPair{
+				{Key: l1Key1, Value: "v", Revision: "1"},
+				{Key: l1Key3, Value: "v", Revision: "3"},
+			},
+		})
+		rs.ExpectStatusUpdate(api.ResyncInProgress)
+		rs.ExpectStatusUpdate(api.InSync)
+		Expect(rs.fc.getLatestListRevision()).To(Equal("0"))
+		Eventually(rs.resumeCallbacks.updateTypes).Should(Equal(map[model.Key]api.UpdateType{
+			l1Key2: api.UpdateTypeKVDeleted,
+			l1Key3: api.UpdateTypeKVNew,
+		}))
+		Eventually(func() string { return rs.resumeCallbacks.revision(rc1) }).Should(Equal("300"))
+	})
+
 	It("Should invoke the supplied converter to alter the update", func() {
 		rc1 := watchersyncer.ResourceType{
 			UpdateProcessor: &fakeConverter{},
@@ -803,6 +871,17 @@ func (fc *fakeConverter) Process(kvp *model.KVPair) ([]*model.KVPair, error) {
 func (fc *fakeConverter) OnSyncerStarting() {
 }
 
+// statefulConverter passes KVs through unchanged but, unlike the simple update processors, doesn't
+// claim to be stateless.
+type statefulConverter struct{}
+
+func (sc *statefulConverter) Process(kvp *model.KVPair) ([]*model.KVPair, error) {
+	return []*model.KVPair{kvp}, nil
+}
+
+func (sc *statefulConverter) OnSyncerStarting() {
+}
+
 // Create a delete event from a Key. The value types don't need to match the
 // Key types since we aren't unmarshaling/marshaling them in this package.
 func deleteEvent(key model.Key) api.WatchEvent {
@@ -845,6 +924,13 @@ func modifiedEvent(key model.Key) api.WatchEvent {
 // Create a new watcherSyncerTester - this creates and starts a WatcherSyncer with
 // client and sync consumer interfaces implemented and controlled by the test.
 func newWatcherSyncerTester(l []watchersyncer.ResourceType) *watcherSyncerTester {
+	return newResumingWatcherSyncerTester(l, nil)
+}
+
+// Create a new watcherSyncerTester that resumes from the given state.  If the state is non-nil, the
+// WatcherSyncer's callbacks implement api.SyncerResumeCallbacks and its updates are recorded in
+// rst.resumeCallbacks instead of the SyncerTester.
+func newResumingWatcherSyncerTester(l []watchersyncer.ResourceType, state map[string]api.ResourceTypeState) *watcherSyncerTester {
 	// Create the required watchers.  This hs methods that we use to drive
 	// responses.
 	lws := map[string]*listWatchSource{}
@@ -868,11 +954,20 @@ func newWatcherSyncerTester(l []watchersyncer.ResourceType) *watcherSyncerTester
 	// Create the syncer tester.
 	st := testutils.NewSyncerTester()
 	rst := &watcherSyncerTester{
-		SyncerTester:  st,
-		fc:            fc,
-		watcherSyncer: watchersyncer.New(fc, l, st),
-		lws:           lws,
+		SyncerTester: st,
+		fc:           fc,
+		lws:          lws,
 	}
+	var callbacks api.SyncerCallbacks = st
+	if state != nil {
+		rst.resumeCallbacks = &resumeCallbacks{
+			SyncerTester: st,
+			state:        state,
+			revisions:    map[string]string{},
+		}
+		callbacks = rst.resumeCallbacks
+	}
+	rst.watcherSyncer = watchersyncer.New(fc, l, callbacks)
 	rst.watcherSyncer.Start()
 	return rst
 }
@@ -884,8 +979,49 @@ func newWatcherSyncerTester(l []watchersyncer.ResourceType) *watcherSyncerTester
 type watcherSyncerTester struct {
 	*testutils.SyncerTester
 	fc            *fakeClient
-	lws           map[string]*listWatchSource
-	watcherSyncer api.Syncer
+	lws             map[string]*listWatchSource
+	watcherSyncer   api.Syncer
+	resumeCallbacks *resumeCallbacks
+}
+
+// resumeCallbacks implements api.SyncerResumeCallbacks.  It passes status updates to the SyncerTester
+// but records the updates itself since they may refer to resources that the SyncerTester hasn't seen.
+type resumeCallbacks struct {
+	*testutils.SyncerTester
+	state map[string]api.ResourceTypeState
+
+	lock      sync.Mutex
+	revisions map[string]string
+	updates   []api.Update
+}
+
+func (rc *resumeCallbacks) ResumeState() map[string]api.ResourceTypeState {
+	return rc.state
+}
+
+func (rc *resumeCallbacks) OnResourceTypeUpdates(listRoot string, revision string, updates []api.Update) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	rc.revisions[listRoot] = revision
+	rc.updates = append(rc.updates, updates...)
+}
+
+// revision returns the latest revision reported for the given resource type.
+func (rc *resumeCallbacks) revision(r watchersyncer.ResourceType) string {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	return rc.revisions[model.ListOptionsToDefaultPathRoot(r.ListInterface)]
+}
+
+// updateTypes returns the type of each update received so far, keyed by the update's key.
+func (rc *resumeCallbacks) updateTypes() map[model.Key]api.UpdateType {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	types := map[model.Key]api.UpdateType{}
+	for _, u := range rc.updates {
+		types[u.Key] = u.UpdateType
+	}
+	return types
 }
 
 // Call to test that all of the client and watcher events have been processed.
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/names"
+)
+
+// ApplyDefaults fills in the fields of a resource that the client defaults when it writes
+// or reads the resource, including the tier labels of the policies, so that a resource
+// from a file can be compared with the stored one.  Resources of other kinds are left
+// unchanged.
+func ApplyDefaults(obj runtime.Object) {
+	switch r := obj.(type) {
+	case *apiv3.GlobalNetworkPolicy:
+		defaultPolicyTypesField(r.Spec.Ingress, r.Spec.Egress, &r.Spec.Types)
+		r.Labels = defaultTierLabels(r.Labels, r.Spec.Tier)
+	case *apiv3.NetworkPolicy:
+		defaultPolicyTypesField(r.Spec.Ingress, r.Spec.Egress, &r.Spec.Types)
+		r.Labels = defaultTierLabels(r.Labels, r.Spec.Tier)
+	case *apiv3.IPPool:
+		// An invalid CIDR is reported by the validation.
+		_ = convertIpPoolFromStorage(r)
+	case *apiv3.FelixConfiguration:
+		setDefaults(r)
+	case *apiv3.KubeControllersConfiguration:
+		kubeControllersConfiguration{}.fillDefaults(r)
+	}
+}
+
+// defaultTierLabels sets the tier label of a policy as the client does when it writes and
+// reads the policy.
+func defaultTierLabels(labels map[string]string, tier string) map[string]string {
+	if tier := names.TierOrDefault(tier); tier != names.DefaultTierName {
+		labels = addTierLabel(labels, tier)
+	}
+	return defaultTierLabelIfMissing(labels)
+}
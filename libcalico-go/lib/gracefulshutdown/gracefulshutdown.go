@@ -0,0 +1,130 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gracefulshutdown defines how a BGP graceful shutdown (RFC 8326) of a node is
+// requested and reported.  A shutdown is requested by annotating the Node resource.
+// confd then advertises all routes with the GRACEFUL_SHUTDOWN community until the
+// teardown time, at which point it shuts down the BGP sessions.  confd writes the
+// progress to a file in the calico/node run directory for the node status reporter and
+// calicoctl to read back.
+package gracefulshutdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Annotation is the Node annotation that requests a BGP graceful shutdown.  The value
+	// is either the RFC 3339 time at which to tear down the BGP sessions, a duration to
+	// wait from when the annotation is first seen, or "true" to wait for DefaultWait.
+	Annotation = "projectcalico.org/bgp-graceful-shutdown"
+
+	// DefaultWait is how long routes are advertised with the GRACEFUL_SHUTDOWN community
+	// before the BGP sessions are torn down, when no time is given in the annotation.
+	DefaultWait = 30 * time.Second
+
+	// DefaultPath is the location that confd writes the graceful shutdown status to.
+	// It is in the same directory as the BIRD control sockets so that it is visible on the host.
+	DefaultPath = "/var/run/calico/bgp-graceful-shutdown.json"
+)
+
+// fileStatus is the content of the status file.  Alongside the reported status it records
+// when the shutdown was first requested, so that a relative wait carries on from where it
+// was rather than restarting if confd restarts.
+type fileStatus struct {
+	apiv3.CalicoNodeBGPGracefulShutdown `json:",inline"`
+	RequestTime                         metav1.Time `json:"requestTime"`
+}
+
+// TeardownTime returns the time at which the BGP sessions should be torn down for the
+// given annotation value, where firstSeen is when the annotation was first seen.
+func TeardownTime(value string, firstSeen time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("negative graceful shutdown wait %q", value)
+		}
+		return firstSeen.Add(d), nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil && b {
+		return firstSeen.Add(DefaultWait), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid value %q for annotation %s: expected a time, a duration or \"true\"", value, Annotation)
+}
+
+// Write atomically replaces the graceful shutdown status stored at the given path, along
+// with the time at which the shutdown was first requested.  A nil status removes the file.
+func Write(path string, status *apiv3.CalicoNodeBGPGracefulShutdown, requestTime time.Time) error {
+	if status == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(fileStatus{CalicoNodeBGPGracefulShutdown: *status, RequestTime: metav1.NewTime(requestTime)})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read returns the graceful shutdown status stored at the given path, or nil if no
+// graceful shutdown is in progress.
+func Read(path string) (*apiv3.CalicoNodeBGPGracefulShutdown, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	status := &apiv3.CalicoNodeBGPGracefulShutdown{}
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// RequestTime returns when the graceful shutdown stored at the given path was first
+// requested, or the zero time if no graceful shutdown is in progress.
+func RequestTime(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	var status fileStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return time.Time{}, err
+	}
+	return status.RequestTime.Time, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gracefulshutdown_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+)
+
+var _ = Describe("BGP graceful shutdown", func() {
+	firstSeen := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	DescribeTable("TeardownTime",
+		func(value string, expected time.Time) {
+			t, err := gracefulshutdown.TeardownTime(value, firstSeen)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Equal(expected)).To(BeTrue(), "got %v, expected %v", t, expected)
+		},
+		Entry("an RFC 3339 time", "2024-05-06T08:00:00Z", time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)),
+		Entry("a duration", "2m", firstSeen.Add(2*time.Minute)),
+		Entry("a zero duration", "0s", firstSeen),
+		Entry("true", "true", firstSeen.Add(gracefulshutdown.DefaultWait)),
+	)
+
+	DescribeTable("TeardownTime with an invalid value",
+		func(value string) {
+			_, err := gracefulshutdown.TeardownTime(value, firstSeen)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("a negative duration", "-1m"),
+		Entry("false", "false"),
+		Entry("garbage", "soon"),
+		Entry("empty", ""),
+	)
+
+	Describe("status file", func() {
+		var dir, path string
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "gracefulshutdown")
+			Expect(err).NotTo(HaveOccurred())
+			path = filepath.Join(dir, "run", "status.json")
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should report no shutdown when there is no file", func() {
+			status, err := gracefulshutdown.Read(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(BeNil())
+			requestTime, err := gracefulshutdown.RequestTime(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestTime.IsZero()).To(BeTrue())
+		})
+
+		It("should read back the status and request time that were written", func() {
+			status := &apiv3.CalicoNodeBGPGracefulShutdown{
+				State:        apiv3.BGPGracefulShutdownStateDraining,
+				TeardownTime: metav1.NewTime(firstSeen.Add(time.Minute)),
+			}
+			Expect(gracefulshutdown.Write(path, status, firstSeen)).To(Succeed())
+
+			read, err := gracefulshutdown.Read(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read.State).To(Equal(apiv3.BGPGracefulShutdownStateDraining))
+			Expect(read.TeardownTime.Equal(&status.TeardownTime)).To(BeTrue())
+			requestTime, err := gracefulshutdown.RequestTime(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestTime.Equal(firstSeen)).To(BeTrue())
+
+			// Overwriting replaces the status.
+			status.State = apiv3.BGPGracefulShutdownStateComplete
+			Expect(gracefulshutdown.Write(path, status, firstSeen)).To(Succeed())
+			read, err = gracefulshutdown.Read(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read.State).To(Equal(apiv3.BGPGracefulShutdownStateComplete))
+			Expect(filepath.Join(filepath.Dir(path), "status.json.tmp")).NotTo(BeAnExistingFile())
+		})
+
+		It("should remove the file when written a nil status", func() {
+			Expect(gracefulshutdown.Write(path, &apiv3.CalicoNodeBGPGracefulShutdown{}, firstSeen)).To(Succeed())
+			Expect(gracefulshutdown.Write(path, nil, time.Time{})).To(Succeed())
+			Expect(path).NotTo(BeAnExistingFile())
+			// Removing it again is not an error.
+			Expect(gracefulshutdown.Write(path, nil, time.Time{})).To(Succeed())
+		})
+
+		It("should fail to read a corrupt file", func() {
+			Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte("{"), 0o644)).To(Succeed())
+			_, err := gracefulshutdown.Read(path)
+			Expect(err).To(HaveOccurred())
+			_, err = gracefulshutdown.RequestTime(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
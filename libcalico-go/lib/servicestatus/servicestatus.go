@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package servicestatus shares the service advertisement decisions made by confd with
+// the node status reporter and calicoctl.  confd writes the current decisions to a file
+// in the calico/node run directory and the consumers read it back and print them with
+// PrintTable.
+package servicestatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/olekukonko/tablewriter"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+)
+
+// DefaultPath is the location that confd writes the service advertisement status to.
+// It is in the same directory as the BIRD control sockets so that it is visible on the host.
+const DefaultPath = "/var/run/calico/bgp-service-advertisements.json"
+
+// Write atomically replaces the service advertisement status stored at the given path.
+func Write(path string, advertisements []apiv3.CalicoNodeServiceAdvertisement) error {
+	if advertisements == nil {
+		advertisements = []apiv3.CalicoNodeServiceAdvertisement{}
+	}
+	data, err := json.Marshal(advertisements)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Read returns the service advertisement status stored at the given path.  If the
+// file does not exist, confd is not generating routes for services and an error
+// satisfying os.IsNotExist is returned.
+func Read(path string) ([]apiv3.CalicoNodeServiceAdvertisement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var advertisements []apiv3.CalicoNodeServiceAdvertisement
+	if err := json.Unmarshal(data, &advertisements); err != nil {
+		return nil, err
+	}
+	return advertisements, nil
+}
+
+// PrintTable prints the service advertisements in table format.
+func PrintTable(out io.Writer, advertisements []apiv3.CalicoNodeServiceAdvertisement) {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Service", "Destination", "Type", "Advertised", "Reason"})
+
+	for _, a := range advertisements {
+		row := []string{
+			a.Service,
+			a.Destination,
+			string(a.Type),
+			fmt.Sprint(a.Advertised),
+			a.Reason,
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicestatus_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
+)
+
+var _ = Describe("Service advertisement status", func() {
+	advertisements := []apiv3.CalicoNodeServiceAdvertisement{
+		{
+			Service:     "default/web",
+			Destination: "10.96.0.10/32",
+			Type:        apiv3.ServiceIPTypeClusterIP,
+			Advertised:  true,
+		},
+		{
+			Service:     "default/web",
+			Destination: "fd00:96::10/128",
+			Type:        apiv3.ServiceIPTypeClusterIP,
+			Advertised:  false,
+			Reason:      "Only the primary cluster IP of a dual-stack service is advertised",
+		},
+	}
+
+	var dir string
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "servicestatus")
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should read back the status that was written", func() {
+		path := filepath.Join(dir, "run", "status.json")
+		Expect(servicestatus.Write(path, advertisements)).To(Succeed())
+		Expect(servicestatus.Read(path)).To(Equal(advertisements))
+	})
+
+	It("should return a not exist error if the status has not been written", func() {
+		_, err := servicestatus.Read(filepath.Join(dir, "status.json"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should print a row for each advertisement", func() {
+		var out bytes.Buffer
+		servicestatus.PrintTable(&out, advertisements)
+		Expect(out.String()).To(ContainSubstring("10.96.0.10/32"))
+		Expect(out.String()).To(ContainSubstring("fd00:96::10/128"))
+		Expect(out.String()).To(ContainSubstring("Only the primary cluster IP"))
+	})
+})
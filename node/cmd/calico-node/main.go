@@ -29,6 +29,7 @@ import (
 	"github.com/projectcalico/calico/node/buildinfo"
 	"github.com/projectcalico/calico/node/cmd/calico-node/bpf"
 	"github.com/projectcalico/calico/node/pkg/allocateip"
+	"github.com/projectcalico/calico/node/pkg/bgpdaemon"
 	"github.com/projectcalico/calico/node/pkg/cni"
 	"github.com/projectcalico/calico/node/pkg/health"
 	"github.com/projectcalico/calico/node/pkg/hostpathinit"
@@ -58,11 +59,13 @@ var monitorToken = flagSet.Bool("monitor-token", false, "Watch for Kubernetes to
 var felixLive = flagSet.Bool("felix-live", false, "Run felix liveness checks")
 var birdLive = flagSet.Bool("bird-live", false, "Run bird liveness checks")
 var bird6Live = flagSet.Bool("bird6-live", false, "Run bird6 liveness checks")
+var bgpDaemonLive = flagSet.Bool("bgp-daemon-live", false, "Run native BGP speaker liveness checks, in place of -bird-live when using the gobgp backend")
 
 // Options for readiness checks.
 var birdReady = flagSet.Bool("bird-ready", false, "Run BIRD readiness checks")
 var bird6Ready = flagSet.Bool("bird6-ready", false, "Run BIRD6 readiness checks")
 var felixReady = flagSet.Bool("felix-ready", false, "Run felix readiness checks")
+var bgpDaemonReady = flagSet.Bool("bgp-daemon-ready", false, "Run native BGP speaker readiness checks, in place of -bird-ready when using the gobgp backend")
 
 // thresholdTime is introduced for bird readiness check. Default value is 30 sec.
 var thresholdTime = flagSet.Duration("threshold-time", 30*time.Second, "Threshold time for bird readiness")
@@ -77,6 +80,9 @@ var confdRunOnce = flagSet.Bool("confd-run-once", false, "Run confd in oneshot m
 var confdKeep = flagSet.Bool("confd-keep-stage-file", false, "Keep stage file when running confd")
 var confdConfDir = flagSet.String("confd-confdir", "/etc/calico/confd", "Confd configuration directory.")
 
+// Native BGP speaker flags
+var runBGPDaemon = flagSet.Bool("bgp-daemon", false, "Run the native BGP speaker instead of BIRD and confd")
+
 // non-root hostpath init flags
 var initHostpaths = flagSet.Bool("hostpath-init", false, "Initialize hostpaths for non-root access")
 
@@ -97,7 +103,7 @@ func main() {
 
 	// Perform some validation on the parsed flags. Only one of the following may be
 	// specified at a time.
-	onlyOne := []*bool{version, runFelix, runStartup, runConfd, monitorAddrs}
+	onlyOne := []*bool{version, runFelix, runStartup, runConfd, runBGPDaemon, monitorAddrs}
 	oneSelected := false
 	for _, o := range onlyOne {
 		if oneSelected && *o {
@@ -111,8 +117,8 @@ func main() {
 	}
 
 	// Check for liveness / readiness flags. Will only run checks specified by flags.
-	if *felixLive || *birdReady || *bird6Ready || *felixReady || *birdLive || *bird6Live {
-		health.Run(*birdReady, *bird6Ready, *felixReady, *felixLive, *birdLive, *bird6Live, *thresholdTime)
+	if *felixLive || *birdReady || *bird6Ready || *felixReady || *birdLive || *bird6Live || *bgpDaemonReady || *bgpDaemonLive {
+		health.Run(*birdReady, *bird6Ready, *felixReady, *felixLive, *birdLive, *bird6Live, *bgpDaemonReady, *bgpDaemonLive, *thresholdTime)
 		os.Exit(0)
 	}
 
@@ -153,6 +159,9 @@ func main() {
 		cfg.KeepStageFile = *confdKeep
 		cfg.Onetime = *confdRunOnce
 		confd.Run(cfg)
+	} else if *runBGPDaemon {
+		logrus.SetFormatter(&logutils.Formatter{Component: "bgp-daemon"})
+		bgpdaemon.Run()
 	} else if *runAllocateTunnelAddrs {
 		logrus.SetFormatter(&logutils.Formatter{Component: "tunnel-ip-allocator"})
 		if *allocateTunnelAddrsRunOnce {
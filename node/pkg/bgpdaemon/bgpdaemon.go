@@ -0,0 +1,409 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bgpdaemon is a native BGP speaker for calico/node, used in place of BIRD and
+// confd when CALICO_NETWORKING_BACKEND is "gobgp".  It consumes the same BGP syncer as
+// confd and programs peers, BGPFilters and route advertisements directly into an
+// embedded GoBGP server, whose gRPC API exposes the live session state.
+//
+// Service IP advertisement, the advertisement of annotated pod IPs and BGPFilter rules
+// that match on an interface are not supported.  The daemon refuses to start if any of
+// these are configured.  If they are configured once it is running, it logs a warning and
+// sets the CalicoBGPConfigurationUnsupported condition on the Kubernetes Node.
+//
+// The calico/node liveness and readiness probes should use the -bgp-daemon-live and
+// -bgp-daemon-ready flags in place of -bird-live and -bird-ready (and their BIRD6
+// equivalents), which check BIRD and confd.
+package bgpdaemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/osrg/gobgp/v3/api"
+	"github.com/osrg/gobgp/v3/pkg/server"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	kapiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/bgpsyncer"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+	"github.com/projectcalico/calico/libcalico-go/lib/winutils"
+	"github.com/projectcalico/calico/node/buildinfo"
+	"github.com/projectcalico/calico/node/pkg/calicoclient"
+	"github.com/projectcalico/calico/node/pkg/lifecycle/startup"
+	"github.com/projectcalico/calico/typha/pkg/syncclientutils"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+const (
+	// envGRPCListenAddress configures the address of the GoBGP gRPC API.
+	envGRPCListenAddress     = "CALICO_BGP_DAEMON_GRPC_LISTEN_ADDRESS"
+	defaultGRPCListenAddress = "127.0.0.1:50051"
+
+	// retryInterval is how long to wait before retrying a failed update of the speaker.
+	retryInterval = 5 * time.Second
+
+	// envAdvertiseAnnotatedPodIPs enables the advertisement of annotated pod IPs by confd,
+	// which the native speaker does not support.
+	envAdvertiseAnnotatedPodIPs = "CALICO_ADVERTISE_ANNOTATED_POD_IPS"
+
+	// unsupportedCondition is the Kubernetes Node condition that reports BGP configuration
+	// that the native speaker does not implement.
+	unsupportedCondition kapiv1.NodeConditionType = "CalicoBGPConfigurationUnsupported"
+)
+
+// backendClientAccessor is an interface to access the backend client from the main v2 client.
+type backendClientAccessor interface {
+	Backend() bapi.Client
+}
+
+// GRPCListenAddress returns the address of the GoBGP gRPC API.
+func GRPCListenAddress() string {
+	if addr := os.Getenv(envGRPCListenAddress); addr != "" {
+		return addr
+	}
+	return defaultGRPCListenAddress
+}
+
+// Run runs the native BGP speaker.
+func Run() {
+	startup.ConfigureLogging()
+
+	// This binary is only ever invoked _after_ the
+	// startup binary has been invoked and the modified environments have
+	// been sourced.  Therefore, the NODENAME environment will always be
+	// set at this point.
+	nodename := os.Getenv("NODENAME")
+	if nodename == "" {
+		log.Panic("NODENAME environment is not set")
+	}
+
+	if strings.ToLower(os.Getenv(envAdvertiseAnnotatedPodIPs)) == "true" {
+		log.Fatalf("%s is not supported by the native BGP speaker", envAdvertiseAnnotatedPodIPs)
+	}
+
+	// Load the client config from environment.
+	cfg, c := calicoclient.CreateClient()
+
+	grpcAddress := GRPCListenAddress()
+	log.WithField("address", grpcAddress).Info("Starting GoBGP server")
+	bgpServer := server.NewBgpServer(server.GrpcListenAddress(grpcAddress))
+	go bgpServer.Serve()
+
+	clientset, namespace := newClientset()
+	d := newDaemon(nodename, newSpeaker(bgpServer, newPasswordFunc(clientset, namespace)))
+	d.setCondition = newConditionFunc(clientset, nodename)
+
+	// Program the best routes learned from our peers into the kernel.
+	if err := bgpServer.WatchEvent(context.Background(), &api.WatchEventRequest{
+		Table: &api.WatchEventRequest_Table{
+			Filters: []*api.WatchEventRequest_Table_Filter{{Type: api.WatchEventRequest_Table_Filter_BEST, Init: true}},
+		},
+	}, d.onWatchEvent); err != nil {
+		log.WithError(err).Fatal("Failed to watch BGP routes")
+	}
+
+	// Either create a typha syncclient or a local syncer depending on configuration. This calls back into the
+	// daemon to trigger updates when necessary.
+
+	// Read Typha settings from the environment.
+	// When Typha is in use, there will already be variables prefixed with FELIX_, so it's
+	// convenient if we honor those as well as the CALICO variables.
+	typhaConfig := syncclientutils.ReadTyphaConfig([]string{"FELIX_", "CALICO_"})
+	if syncclientutils.MustStartSyncerClientIfTyphaConfigured(
+		&typhaConfig, syncproto.SyncerTypeBGP,
+		buildinfo.GitVersion, nodename, fmt.Sprintf("bgp-daemon %s", buildinfo.GitVersion),
+		d,
+	) {
+		log.Debug("Using typha syncclient")
+	} else {
+		// Use the syncer locally.
+		log.Debug("Using local syncer")
+		syncer := bgpsyncer.New(c.(backendClientAccessor).Backend(), d, nodename, cfg.Spec)
+		syncer.Start()
+	}
+
+	if err := d.run(context.Background()); err != nil {
+		log.WithError(err).Fatal("Native BGP speaker failed")
+	}
+}
+
+// daemon receives updates from the BGP syncer and applies the resulting BGP
+// configuration to the speaker.
+type daemon struct {
+	lock    sync.Mutex
+	state   *datastoreState
+	inSync  bool
+	ch      chan struct{}
+	speaker *speaker
+	kernel  *kernelRoutes
+
+	// setCondition reports the unsupported settings on the Kubernetes Node, if running
+	// under Kubernetes.
+	setCondition        conditionFunc
+	unsupported         []string
+	unsupportedReported bool
+
+	gracefulShutdownPath     string
+	gracefulShutdown         *apiv3.CalicoNodeBGPGracefulShutdown
+	gracefulShutdownReported bool
+}
+
+func newDaemon(nodename string, s *speaker) *daemon {
+	d := &daemon{
+		state:                newDatastoreState(nodename),
+		ch:                   make(chan struct{}, 1),
+		speaker:              s,
+		kernel:               newKernelRoutes(),
+		gracefulShutdownPath: gracefulshutdown.DefaultPath,
+	}
+
+	// Carry on with any graceful shutdown that was in progress before a restart, so
+	// that a relative wait is not restarted.
+	if requested, err := gracefulshutdown.RequestTime(d.gracefulShutdownPath); err != nil {
+		log.WithError(err).Warning("Failed to read previous BGP graceful shutdown status")
+	} else {
+		d.state.gracefulShutdownSeen = requested
+	}
+	return d
+}
+
+// run is the main loop, recalculating and applying the BGP configuration whenever it is
+// kicked by the syncer.  It returns an error if the BGP configuration uses settings
+// that the native speaker does not support when it first starts BGP.
+func (d *daemon) run(ctx context.Context) error {
+	var retryC, teardownC <-chan time.Time
+	for {
+		select {
+		case <-d.ch:
+		case <-retryC:
+		case <-teardownC:
+		case <-ctx.Done():
+			return nil
+		}
+		retryC, teardownC = nil, nil
+
+		d.lock.Lock()
+		desired := d.state.calculate()
+		gracefulShutdownRequested := d.state.gracefulShutdownSeen
+		d.lock.Unlock()
+
+		if desired == nil {
+			if d.speaker.global == nil {
+				log.Info("No BGP configuration for this node, waiting")
+				continue
+			}
+			// BGP has been removed from this node. Remove all peers and routes.
+			desired = &desiredState{Global: *d.speaker.global}
+		}
+
+		if !d.reportUnsupported(desired.Unsupported) {
+			retryC = time.After(retryInterval)
+		}
+		if d.speaker.global == nil && len(desired.Unsupported) > 0 {
+			return fmt.Errorf("BGP configuration uses settings that are not supported by the native BGP speaker: %s",
+				strings.Join(desired.Unsupported, "; "))
+		}
+
+		if err := d.speaker.apply(ctx, desired); err != nil {
+			log.WithError(err).Warning("Failed to update BGP configuration, will retry")
+			retryC = time.After(retryInterval)
+		}
+		d.kernel.setDesiredState(desired)
+
+		d.reportGracefulShutdown(desired.GracefulShutdown, gracefulShutdownRequested)
+		if gs := desired.GracefulShutdown; gs != nil && gs.State == apiv3.BGPGracefulShutdownStateDraining {
+			// Recalculate when the drain period is over, to shut down the sessions.
+			teardownC = time.After(time.Until(gs.TeardownTime.Time))
+		}
+	}
+}
+
+// reportUnsupported warns about, and sets the Node condition for, any change in the
+// unsupported settings.  It returns false if the condition could not be updated.
+func (d *daemon) reportUnsupported(unsupported []string) bool {
+	if d.unsupportedReported && reflect.DeepEqual(unsupported, d.unsupported) {
+		return true
+	}
+	for _, setting := range unsupported {
+		log.WithField("setting", setting).Warning("BGP configuration is not supported by the native BGP speaker")
+	}
+	if d.setCondition != nil {
+		if err := d.setCondition(unsupported); err != nil {
+			log.WithError(err).Warning("Failed to set the unsupported BGP configuration condition, will retry")
+			return false
+		}
+	}
+	d.unsupported = unsupported
+	d.unsupportedReported = true
+	return true
+}
+
+// reportGracefulShutdown writes the progress of any BGP graceful shutdown for the node
+// status reporter and calicoctl.  The first report also clears out any status left behind
+// by a previous run.
+func (d *daemon) reportGracefulShutdown(status *apiv3.CalicoNodeBGPGracefulShutdown, requested time.Time) {
+	if d.gracefulShutdownReported && reflect.DeepEqual(status, d.gracefulShutdown) {
+		return
+	}
+	if status == nil {
+		if d.gracefulShutdown != nil {
+			log.Info("BGP graceful shutdown cancelled")
+		}
+	} else if status.State == apiv3.BGPGracefulShutdownStateDraining {
+		log.WithField("teardownTime", status.TeardownTime).Info("BGP graceful shutdown requested, draining routes")
+	} else {
+		log.Info("BGP graceful shutdown drain period complete, shut down BGP sessions")
+	}
+	if err := gracefulshutdown.Write(d.gracefulShutdownPath, status, requested); err != nil {
+		log.WithError(err).Warning("Failed to write BGP graceful shutdown status")
+		return
+	}
+	d.gracefulShutdown = status
+	d.gracefulShutdownReported = true
+}
+
+// OnStatusUpdated handles the syncer status callback method.
+func (d *daemon) OnStatusUpdated(status bapi.SyncStatus) {
+	if status == bapi.InSync {
+		log.Info("BGP syncer is in sync")
+		d.lock.Lock()
+		d.inSync = true
+		d.lock.Unlock()
+		d.kick()
+	}
+}
+
+// OnUpdates handles the syncer resource updates.
+func (d *daemon) OnUpdates(updates []bapi.Update) {
+	d.lock.Lock()
+	updated := false
+	for _, u := range updates {
+		if d.state.onUpdate(u) {
+			updated = true
+		}
+	}
+	inSync := d.inSync
+	d.lock.Unlock()
+
+	if updated && inSync {
+		d.kick()
+	}
+}
+
+// kick triggers a recalculation, without blocking if there is already one pending.
+func (d *daemon) kick() {
+	select {
+	case d.ch <- struct{}{}:
+	default:
+	}
+}
+
+func (d *daemon) onWatchEvent(r *api.WatchEventResponse) {
+	if t := r.GetTable(); t != nil {
+		for _, p := range t.Paths {
+			d.kernel.onBestPath(p)
+		}
+	}
+}
+
+// newClientset returns a Kubernetes clientset and the calico/node namespace, or a nil
+// clientset if not running under Kubernetes.
+func newClientset() (*kubernetes.Clientset, string) {
+	config, err := winutils.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+	if err != nil {
+		log.WithError(err).Info("Not running under Kubernetes, BGP passwords and Node conditions are not supported")
+		return nil, ""
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.WithError(err).Warning("Failed to create clientset, BGP passwords and Node conditions are not supported")
+		return nil, ""
+	}
+	namespace := os.Getenv("NAMESPACE")
+	if namespace == "" {
+		namespace = "kube-system"
+	}
+	return clientset, namespace
+}
+
+type conditionFunc func(unsupported []string) error
+
+// newConditionFunc returns a function to set the unsupported BGP configuration condition
+// on the Kubernetes Node, or nil if not running under Kubernetes.
+func newConditionFunc(clientset *kubernetes.Clientset, nodename string) conditionFunc {
+	if clientset == nil {
+		return nil
+	}
+	k8sNodeName := os.Getenv("CALICO_K8S_NODE_REF")
+	if k8sNodeName == "" {
+		k8sNodeName = nodename
+	}
+
+	return func(unsupported []string) error {
+		condition := kapiv1.NodeCondition{
+			Type:               unsupportedCondition,
+			Status:             kapiv1.ConditionFalse,
+			Reason:             "BGPConfigurationSupported",
+			Message:            "The native BGP speaker supports all of the BGP configuration",
+			LastTransitionTime: metav1.Now(),
+			LastHeartbeatTime:  metav1.Now(),
+		}
+		if len(unsupported) > 0 {
+			condition.Status = kapiv1.ConditionTrue
+			condition.Reason = "BGPConfigurationUnsupported"
+			condition.Message = "Not supported by the native BGP speaker: " + strings.Join(unsupported, "; ")
+		}
+		raw, err := json.Marshal(&[]kapiv1.NodeCondition{condition})
+		if err != nil {
+			return err
+		}
+		patch := []byte(fmt.Sprintf(`{"status":{"conditions":%s}}`, raw))
+		_, err = clientset.CoreV1().Nodes().PatchStatus(context.Background(), k8sNodeName, patch)
+		return err
+	}
+}
+
+// newPasswordFunc returns a function to read BGP passwords from Kubernetes secrets in
+// the calico/node namespace, or nil if not running under Kubernetes.
+func newPasswordFunc(clientset *kubernetes.Clientset, namespace string) passwordFunc {
+	if clientset == nil {
+		return nil
+	}
+
+	return func(p *apiv3.BGPPassword) (string, error) {
+		if p.SecretKeyRef == nil {
+			return "", nil
+		}
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(context.Background(), p.SecretKeyRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		value, ok := secret.Data[p.SecretKeyRef.Key]
+		if !ok {
+			return "", fmt.Errorf("secret %s/%s has no key %s", namespace, p.SecretKeyRef.Name, p.SecretKeyRef.Key)
+		}
+		return string(value), nil
+	}
+}
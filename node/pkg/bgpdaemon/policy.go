@@ -0,0 +1,369 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpdaemon
+
+import (
+	"fmt"
+	"net"
+
+	api "github.com/osrg/gobgp/v3/api"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+const (
+	communitiesPolicy = "calico-communities"
+	keepNextHopPolicy = "calico-keep-next-hop"
+	exportPoolsPolicy = "calico-export-pools"
+	nonRRClientsSet   = "calico-non-rr-clients"
+)
+
+// filterRule is the IP family independent form of a BGPFilter rule.
+type filterRule struct {
+	CIDR          string
+	MinLength     *int32
+	MaxLength     *int32
+	Source        apiv3.BGPFilterMatchSource
+	Interface     string
+	MatchOperator apiv3.BGPFilterMatchOperator
+	Action        apiv3.BGPFilterAction
+}
+
+// policyBuilder accumulates the GoBGP defined sets and policies that implement the
+// Calico export rules and the BGPFilters of each peer.
+type policyBuilder struct {
+	sets         []*api.DefinedSet
+	neighborSets map[string]string
+	policies     map[api.PolicyDirection][]*api.Policy
+}
+
+// buildPolicies returns the full set of GoBGP policies for the desired state.  The
+// policies are evaluated in order and the first statement with a route action wins,
+// matching the behaviour of the BIRD filter functions generated by confd.  On export:
+//   - add the communities from the prefix advertisements, and the GRACEFUL_SHUTDOWN
+//     community while a graceful shutdown is draining this node
+//   - keep the original next hop for eBGP peers with keepOriginalNextHop
+//   - each peer's BGPFilters, in the order listed on the BGPPeer
+//   - for peers that are not route reflector clients, reject any route outside the IP pools.
+func buildPolicies(d *desiredState) *api.SetPoliciesRequest {
+	b := &policyBuilder{neighborSets: map[string]string{}, policies: map[api.PolicyDirection][]*api.Policy{}}
+	b.addCommunityPolicy(d)
+	b.addKeepNextHopPolicy(d)
+
+	var nonRRClients []string
+	for _, addr := range sortedKeys(d.Peers) {
+		p := d.Peers[addr]
+		if !p.RRClient {
+			nonRRClients = append(nonRRClients, hostPrefix(addr))
+		}
+		if len(p.Filters) > 0 {
+			b.addPeerFilters(p, d.Filters)
+		}
+	}
+
+	if len(nonRRClients) > 0 {
+		b.sets = append(b.sets, &api.DefinedSet{
+			DefinedType: api.DefinedType_NEIGHBOR,
+			Name:        nonRRClientsSet,
+			List:        nonRRClients,
+		})
+		b.addPoolPolicy(d.Pools)
+	}
+
+	req := &api.SetPoliciesRequest{DefinedSets: b.sets}
+	for _, dir := range []api.PolicyDirection{api.PolicyDirection_IMPORT, api.PolicyDirection_EXPORT} {
+		req.Policies = append(req.Policies, b.policies[dir]...)
+		req.Assignments = append(req.Assignments, &api.PolicyAssignment{
+			Name:          "global",
+			Direction:     dir,
+			Policies:      b.policies[dir],
+			DefaultAction: api.RouteAction_ACCEPT,
+		})
+	}
+	return req
+}
+
+// addPeerFilters adds import and export policies for the BGPFilters of a single peer.
+func (b *policyBuilder) addPeerFilters(p *peerSpec, filters map[string]*apiv3.BGPFilter) {
+	neighborSet := b.neighborSet(p.Address)
+
+	v6 := net.ParseIP(p.Address).To4() == nil
+	for _, dir := range []api.PolicyDirection{api.PolicyDirection_IMPORT, api.PolicyDirection_EXPORT} {
+		policy := &api.Policy{Name: fmt.Sprintf("calico-%s-%s", directionName(dir), p.Address)}
+		for _, name := range p.Filters {
+			f, ok := filters[name]
+			if !ok {
+				continue
+			}
+			for i, r := range filterRules(f, dir, v6) {
+				stmtName := fmt.Sprintf("%s-%s-%d", policy.Name, name, i)
+				policy.Statements = append(policy.Statements, b.ruleStatements(stmtName, neighborSet, r, v6)...)
+			}
+		}
+		if len(policy.Statements) > 0 {
+			b.policies[dir] = append(b.policies[dir], policy)
+		}
+	}
+}
+
+// neighborSet returns the name of the defined set that matches the peer, adding it if
+// it does not already exist.
+func (b *policyBuilder) neighborSet(addr string) string {
+	if name, ok := b.neighborSets[addr]; ok {
+		return name
+	}
+	name := "calico-peer-" + addr
+	b.sets = append(b.sets, &api.DefinedSet{
+		DefinedType: api.DefinedType_NEIGHBOR,
+		Name:        name,
+		List:        []string{hostPrefix(addr)},
+	})
+	b.neighborSets[addr] = name
+	return name
+}
+
+// addCommunityPolicy adds the export statements that add communities to routes.  The
+// statements have no route action, so evaluation continues with the following policies.
+func (b *policyBuilder) addCommunityPolicy(d *desiredState) {
+	policy := &api.Policy{Name: communitiesPolicy}
+	if gs := d.GracefulShutdown; gs != nil && gs.State == apiv3.BGPGracefulShutdownStateDraining {
+		// GoBGP cannot set the local preference to zero as BIRD does, but peers that
+		// support RFC 8326 lower the preference of routes with this community.
+		policy.Statements = append(policy.Statements, &api.Statement{
+			Name: communitiesPolicy + "-graceful-shutdown",
+			Actions: &api.Actions{
+				Community: &api.CommunityAction{Type: api.CommunityAction_ADD, Communities: []string{gracefulShutdownCommunity}},
+			},
+		})
+	}
+	for i, pc := range d.Communities {
+		_, ipNet, err := net.ParseCIDR(pc.CIDR)
+		if err != nil {
+			log.WithField("cidr", pc.CIDR).Warning("Invalid CIDR in prefix advertisement")
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		name := fmt.Sprintf("%s-%d", communitiesPolicy, i)
+		b.sets = append(b.sets, &api.DefinedSet{
+			DefinedType: api.DefinedType_PREFIX,
+			Name:        name,
+			Prefixes:    []*api.Prefix{{IpPrefix: ipNet.String(), MaskLengthMin: uint32(ones), MaskLengthMax: uint32(bits)}},
+		})
+		actions := &api.Actions{}
+		if len(pc.Communities) > 0 {
+			actions.Community = &api.CommunityAction{Type: api.CommunityAction_ADD, Communities: pc.Communities}
+		}
+		if len(pc.LargeCommunities) > 0 {
+			actions.LargeCommunity = &api.CommunityAction{Type: api.CommunityAction_ADD, Communities: pc.LargeCommunities}
+		}
+		policy.Statements = append(policy.Statements, &api.Statement{
+			Name:       name,
+			Conditions: &api.Conditions{PrefixSet: &api.MatchSet{Type: api.MatchSet_ANY, Name: name}},
+			Actions:    actions,
+		})
+	}
+	if len(policy.Statements) > 0 {
+		b.policies[api.PolicyDirection_EXPORT] = append(b.policies[api.PolicyDirection_EXPORT], policy)
+	}
+}
+
+// addKeepNextHopPolicy adds the export statements that keep the original next hop of
+// routes sent to eBGP peers with keepOriginalNextHop set.  As with BIRD, the setting has
+// no effect on iBGP peers, where the next hop is not changed anyway.
+func (b *policyBuilder) addKeepNextHopPolicy(d *desiredState) {
+	policy := &api.Policy{Name: keepNextHopPolicy}
+	for _, addr := range sortedKeys(d.Peers) {
+		p := d.Peers[addr]
+		if !p.KeepNextHop || p.ASNumber == d.Global.ASNumber {
+			continue
+		}
+		policy.Statements = append(policy.Statements, &api.Statement{
+			Name:       fmt.Sprintf("%s-%s", keepNextHopPolicy, addr),
+			Conditions: &api.Conditions{NeighborSet: &api.MatchSet{Type: api.MatchSet_ANY, Name: b.neighborSet(addr)}},
+			Actions:    &api.Actions{Nexthop: &api.NexthopAction{Unchanged: true}},
+		})
+	}
+	if len(policy.Statements) > 0 {
+		b.policies[api.PolicyDirection_EXPORT] = append(b.policies[api.PolicyDirection_EXPORT], policy)
+	}
+}
+
+// ruleStatements converts a BGPFilter rule to GoBGP statements.  More than one
+// statement is returned when the rule matches several route types.
+func (b *policyBuilder) ruleStatements(name, neighborSet string, r filterRule, v6 bool) []*api.Statement {
+	if r.Interface != "" {
+		// Interface rules match routes learned from the kernel, which the native
+		// speaker does not import.  The daemon reports these as unsupported.
+		log.WithField("rule", name).Debug("Skipping BGPFilter rule that matches on interface")
+		return nil
+	}
+
+	prefixSet := name
+	b.sets = append(b.sets, &api.DefinedSet{
+		DefinedType: api.DefinedType_PREFIX,
+		Name:        prefixSet,
+		Prefixes:    []*api.Prefix{rulePrefix(r, v6)},
+	})
+	matchType := api.MatchSet_ANY
+	if r.MatchOperator == apiv3.NotEqual || r.MatchOperator == apiv3.NotIn {
+		matchType = api.MatchSet_INVERT
+	}
+
+	action := api.RouteAction_REJECT
+	if r.Action == apiv3.Accept {
+		action = api.RouteAction_ACCEPT
+	}
+
+	routeTypes := []api.Conditions_RouteType{api.Conditions_ROUTE_TYPE_NONE}
+	if r.Source == apiv3.BGPFilterSourceRemotePeers {
+		routeTypes = []api.Conditions_RouteType{api.Conditions_ROUTE_TYPE_INTERNAL, api.Conditions_ROUTE_TYPE_EXTERNAL}
+	}
+
+	var stmts []*api.Statement
+	for i, rt := range routeTypes {
+		stmts = append(stmts, &api.Statement{
+			Name: fmt.Sprintf("%s-%d", name, i),
+			Conditions: &api.Conditions{
+				NeighborSet: &api.MatchSet{Type: api.MatchSet_ANY, Name: neighborSet},
+				PrefixSet:   &api.MatchSet{Type: matchType, Name: prefixSet},
+				RouteType:   rt,
+			},
+			Actions: &api.Actions{RouteAction: action},
+		})
+	}
+	return stmts
+}
+
+// addPoolPolicy rejects the export of routes outside of the IP pools to peers that
+// are not route reflector clients.
+func (b *policyBuilder) addPoolPolicy(pools []model.IPPool) {
+	policy := &api.Policy{Name: exportPoolsPolicy}
+	for _, family := range []struct {
+		name string
+		any  string
+		bits uint32
+	}{{"v4", "0.0.0.0/0", 32}, {"v6", "::/0", 128}} {
+		setName := fmt.Sprintf("calico-pools-%s", family.name)
+		set := &api.DefinedSet{DefinedType: api.DefinedType_PREFIX, Name: setName}
+		for _, pool := range pools {
+			ones, bits := pool.CIDR.Mask.Size()
+			if uint32(bits) != family.bits {
+				continue
+			}
+			set.Prefixes = append(set.Prefixes, &api.Prefix{
+				IpPrefix:      pool.CIDR.String(),
+				MaskLengthMin: uint32(ones),
+				MaskLengthMax: family.bits,
+			})
+		}
+
+		matchType := api.MatchSet_INVERT
+		if len(set.Prefixes) == 0 {
+			// No pools in this family, so reject everything.
+			set.Prefixes = []*api.Prefix{{IpPrefix: family.any, MaskLengthMin: 0, MaskLengthMax: family.bits}}
+			matchType = api.MatchSet_ANY
+		}
+		b.sets = append(b.sets, set)
+		policy.Statements = append(policy.Statements, &api.Statement{
+			Name: fmt.Sprintf("%s-%s", exportPoolsPolicy, family.name),
+			Conditions: &api.Conditions{
+				NeighborSet: &api.MatchSet{Type: api.MatchSet_ANY, Name: nonRRClientsSet},
+				PrefixSet:   &api.MatchSet{Type: matchType, Name: setName},
+			},
+			Actions: &api.Actions{RouteAction: api.RouteAction_REJECT},
+		})
+	}
+	b.policies[api.PolicyDirection_EXPORT] = append(b.policies[api.PolicyDirection_EXPORT], policy)
+}
+
+// filterRules returns the rules of the filter that apply in the given direction and IP family.
+func filterRules(f *apiv3.BGPFilter, dir api.PolicyDirection, v6 bool) []filterRule {
+	var rules []filterRule
+	if v6 {
+		src := f.Spec.ImportV6
+		if dir == api.PolicyDirection_EXPORT {
+			src = f.Spec.ExportV6
+		}
+		for _, r := range src {
+			fr := filterRule{CIDR: r.CIDR, Source: r.Source, Interface: r.Interface, MatchOperator: r.MatchOperator, Action: r.Action}
+			if r.PrefixLength != nil {
+				fr.MinLength, fr.MaxLength = r.PrefixLength.Min, r.PrefixLength.Max
+			}
+			rules = append(rules, fr)
+		}
+		return rules
+	}
+
+	src := f.Spec.ImportV4
+	if dir == api.PolicyDirection_EXPORT {
+		src = f.Spec.ExportV4
+	}
+	for _, r := range src {
+		fr := filterRule{CIDR: r.CIDR, Source: r.Source, Interface: r.Interface, MatchOperator: r.MatchOperator, Action: r.Action}
+		if r.PrefixLength != nil {
+			fr.MinLength, fr.MaxLength = r.PrefixLength.Min, r.PrefixLength.Max
+		}
+		rules = append(rules, fr)
+	}
+	return rules
+}
+
+// rulePrefix returns the prefix matched by a rule.  Rules without a CIDR match every
+// route in the family.  For the negated operators the prefix length range is applied
+// before negation.
+func rulePrefix(r filterRule, v6 bool) *api.Prefix {
+	maxBits := uint32(32)
+	cidr := "0.0.0.0/0"
+	if v6 {
+		maxBits = 128
+		cidr = "::/0"
+	}
+
+	p := &api.Prefix{IpPrefix: cidr, MaskLengthMin: 0, MaskLengthMax: maxBits}
+	if r.CIDR != "" {
+		if _, ipNet, err := net.ParseCIDR(r.CIDR); err == nil {
+			ones, _ := ipNet.Mask.Size()
+			p.IpPrefix = ipNet.String()
+			p.MaskLengthMin = uint32(ones)
+			if r.MatchOperator == apiv3.Equal || r.MatchOperator == apiv3.NotEqual {
+				p.MaskLengthMax = uint32(ones)
+			}
+		}
+	}
+	if r.MinLength != nil {
+		p.MaskLengthMin = uint32(*r.MinLength)
+	}
+	if r.MaxLength != nil {
+		p.MaskLengthMax = uint32(*r.MaxLength)
+	}
+	return p
+}
+
+func directionName(dir api.PolicyDirection) string {
+	if dir == api.PolicyDirection_IMPORT {
+		return "import"
+	}
+	return "export"
+}
+
+// hostPrefix returns the host prefix for an address, as used in neighbor sets.
+func hostPrefix(addr string) string {
+	if net.ParseIP(addr).To4() != nil {
+		return addr + "/32"
+	}
+	return addr + "/128"
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpdaemon
+
+import (
+	"net"
+	"sync"
+
+	api "github.com/osrg/gobgp/v3/api"
+	"github.com/osrg/gobgp/v3/pkg/apiutil"
+	"github.com/osrg/gobgp/v3/pkg/packet/bgp"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/encap"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// kernelRoutes programs the best routes learned over BGP into the kernel, blackhole
+// routes for the blocks originated by this node and static routes to the peers that are
+// reached through a gateway.  Routes are programmed with the same
+// protocol as BIRD uses so that Felix treats them in the same way.
+type kernelRoutes struct {
+	lock sync.Mutex
+
+	pools      []model.IPPool
+	local      map[string]bool
+	static     map[string]net.IP
+	learned    map[string]net.IP
+	programmed map[string]*netlink.Route
+}
+
+func newKernelRoutes() *kernelRoutes {
+	return &kernelRoutes{
+		local:      make(map[string]bool),
+		static:     make(map[string]net.IP),
+		learned:    make(map[string]net.IP),
+		programmed: make(map[string]*netlink.Route),
+	}
+}
+
+// setDesiredState updates the pools, the locally originated prefixes and the static
+// routes, and reprograms any routes that are affected.
+func (k *kernelRoutes) setDesiredState(d *desiredState) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	k.pools = d.Pools
+	k.local = make(map[string]bool, len(d.Prefixes))
+	for cidr := range d.Prefixes {
+		k.local[cidr] = true
+	}
+	k.static = make(map[string]net.IP, len(d.StaticRoutes))
+	for cidr, gw := range d.StaticRoutes {
+		k.static[cidr] = net.ParseIP(gw)
+	}
+	k.resync()
+}
+
+// onBestPath handles a best path event from the BGP server.
+func (k *kernelRoutes) onBestPath(p *api.Path) {
+	nlri, err := apiutil.GetNativeNlri(p)
+	if err != nil {
+		log.WithError(err).Debug("Ignoring path with unknown NLRI")
+		return
+	}
+	_, dst, err := net.ParseCIDR(nlri.String())
+	if err != nil {
+		log.WithField("nlri", nlri.String()).Debug("Ignoring non-prefix NLRI")
+		return
+	}
+	cidr := dst.String()
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	if p.IsWithdraw {
+		delete(k.learned, cidr)
+	} else if nextHop := pathNextHop(p); nextHop != nil {
+		k.learned[cidr] = nextHop
+	}
+	k.syncRoute(cidr)
+}
+
+// resync reprograms every route that is learned, local, static or already programmed.
+func (k *kernelRoutes) resync() {
+	cidrs := map[string]bool{}
+	for cidr := range k.learned {
+		cidrs[cidr] = true
+	}
+	for cidr := range k.local {
+		cidrs[cidr] = true
+	}
+	for cidr := range k.static {
+		cidrs[cidr] = true
+	}
+	for cidr := range k.programmed {
+		cidrs[cidr] = true
+	}
+	for cidr := range cidrs {
+		k.syncRoute(cidr)
+	}
+}
+
+// syncRoute programs the kernel route for a single destination.
+func (k *kernelRoutes) syncRoute(cidr string) {
+	logCtx := log.WithField("cidr", cidr)
+	route := k.calculateRoute(cidr)
+	existing := k.programmed[cidr]
+	if route == nil {
+		if existing != nil {
+			logCtx.Debug("Removing kernel route")
+			if err := netlink.RouteDel(existing); err != nil {
+				logCtx.WithError(err).Warning("Failed to remove kernel route")
+			}
+			delete(k.programmed, cidr)
+		}
+		return
+	}
+	if existing != nil && existing.Equal(*route) {
+		return
+	}
+	logCtx.WithField("route", route).Debug("Programming kernel route")
+	if err := netlink.RouteReplace(route); err != nil {
+		logCtx.WithError(err).Warning("Failed to program kernel route")
+		return
+	}
+	k.programmed[cidr] = route
+}
+
+// calculateRoute returns the kernel route that should exist for the destination, or nil
+// if there should not be one.
+func (k *kernelRoutes) calculateRoute(cidr string) *netlink.Route {
+	_, dst, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil
+	}
+
+	if k.local[cidr] {
+		// Blocks owned by this node are blackholed; Felix programs the more specific
+		// routes to the local workloads.
+		return &netlink.Route{Dst: dst, Type: unix.RTN_BLACKHOLE, Protocol: unix.RTPROT_BIRD}
+	}
+	if gw, ok := k.static[cidr]; ok {
+		// A BGPPeer that is reachable through a gateway (reachableBy).
+		return &netlink.Route{Dst: dst, Gw: gw, Protocol: unix.RTPROT_BIRD}
+	}
+
+	nextHop, ok := k.learned[cidr]
+	if !ok {
+		return nil
+	}
+
+	route := &netlink.Route{Dst: dst, Gw: nextHop, Protocol: unix.RTPROT_BIRD}
+	directLink, direct := directlyConnected(nextHop)
+	if direct {
+		route.LinkIndex = directLink
+	}
+
+	pool := k.poolContaining(dst)
+	if pool == nil {
+		return route
+	}
+	if pool.VXLANMode == encap.Always || pool.VXLANMode == encap.CrossSubnet {
+		// Felix programs the routes for VXLAN pools.
+		return nil
+	}
+	if pool.IPIPInterface != "" && (pool.IPIPMode == encap.Always || (pool.IPIPMode == encap.CrossSubnet && !direct)) {
+		link, err := netlink.LinkByName(pool.IPIPInterface)
+		if err != nil {
+			log.WithError(err).WithField("interface", pool.IPIPInterface).Warning("IPIP interface not found")
+			return nil
+		}
+		route.LinkIndex = link.Attrs().Index
+		route.Flags = int(netlink.FLAG_ONLINK)
+	}
+	return route
+}
+
+func (k *kernelRoutes) poolContaining(dst *net.IPNet) *model.IPPool {
+	for i := range k.pools {
+		if k.pools[i].CIDR.Contains(dst.IP) {
+			return &k.pools[i]
+		}
+	}
+	return nil
+}
+
+// directlyConnected returns the index of the interface that the address is reachable on,
+// if it is on a directly connected subnet.
+func directlyConnected(ip net.IP) (int, bool) {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil || len(routes) == 0 {
+		return 0, false
+	}
+	if routes[0].Gw != nil {
+		return 0, false
+	}
+	return routes[0].LinkIndex, true
+}
+
+// pathNextHop returns the next hop of a BGP path.
+func pathNextHop(p *api.Path) net.IP {
+	attrs, err := apiutil.GetNativePathAttributes(p)
+	if err != nil {
+		return nil
+	}
+	for _, attr := range attrs {
+		switch a := attr.(type) {
+		case *bgp.PathAttributeNextHop:
+			return a.Value
+		case *bgp.PathAttributeMpReachNLRI:
+			return a.Nexthop
+		}
+	}
+	return nil
+}
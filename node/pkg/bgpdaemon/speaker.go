@@ -0,0 +1,242 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpdaemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"time"
+
+	api "github.com/osrg/gobgp/v3/api"
+	"github.com/osrg/gobgp/v3/pkg/apiutil"
+	"github.com/osrg/gobgp/v3/pkg/packet/bgp"
+	"github.com/osrg/gobgp/v3/pkg/server"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// passwordFunc resolves a BGP password from its secret reference.
+type passwordFunc func(*apiv3.BGPPassword) (string, error)
+
+// speaker programs the desired BGP state into an embedded GoBGP server, tracking what it
+// has programmed so that each update only makes incremental changes.
+type speaker struct {
+	bgp         *server.BgpServer
+	getPassword passwordFunc
+
+	global   *globalSpec
+	policies *api.SetPoliciesRequest
+	peers    map[string]*peerSpec
+	prefixes map[string]string
+}
+
+func newSpeaker(bgpServer *server.BgpServer, getPassword passwordFunc) *speaker {
+	return &speaker{
+		bgp:         bgpServer,
+		getPassword: getPassword,
+		peers:       make(map[string]*peerSpec),
+		prefixes:    make(map[string]string),
+	}
+}
+
+// apply reconciles the GoBGP server with the desired state.
+func (s *speaker) apply(ctx context.Context, d *desiredState) error {
+	if s.global != nil && *s.global != d.Global {
+		// These can only be set when BGP starts, so restart it.  This drops all the
+		// peers, policies and routes, which are then added back below.
+		log.Info("AS number, router ID or listen port changed, restarting BGP")
+		if err := s.bgp.StopBgp(ctx, &api.StopBgpRequest{}); err != nil {
+			return err
+		}
+		s.global = nil
+		s.policies = nil
+		s.peers = make(map[string]*peerSpec)
+		s.prefixes = make(map[string]string)
+	}
+	if s.global == nil {
+		log.WithFields(log.Fields{
+			"asNumber":   d.Global.ASNumber,
+			"routerID":   d.Global.RouterID,
+			"listenPort": d.Global.ListenPort,
+		}).Info("Starting BGP")
+		if err := s.bgp.StartBgp(ctx, &api.StartBgpRequest{Global: &api.Global{
+			Asn:        d.Global.ASNumber,
+			RouterId:   d.Global.RouterID,
+			ListenPort: int32(d.Global.ListenPort),
+		}}); err != nil {
+			return err
+		}
+		global := d.Global
+		s.global = &global
+	}
+
+	policies := buildPolicies(d)
+	if s.policies == nil || !proto.Equal(s.policies, policies) {
+		log.Debug("Updating BGP policies")
+		if err := s.bgp.SetPolicies(ctx, policies); err != nil {
+			return err
+		}
+		s.policies = policies
+	}
+
+	var lastErr error
+	for addr, p := range s.peers {
+		if dp, ok := d.Peers[addr]; ok && reflect.DeepEqual(dp, p) {
+			continue
+		}
+		log.WithField("peer", addr).Info("Removing BGP peer")
+		if err := s.bgp.DeletePeer(ctx, &api.DeletePeerRequest{Address: addr}); err != nil {
+			log.WithError(err).WithField("peer", addr).Warning("Failed to remove BGP peer")
+			lastErr = err
+			continue
+		}
+		delete(s.peers, addr)
+	}
+	for addr, p := range d.Peers {
+		if _, ok := s.peers[addr]; ok {
+			continue
+		}
+		peer, err := s.peerFromSpec(p, d.RRClusterID)
+		if err != nil {
+			log.WithError(err).WithField("peer", addr).Warning("Failed to configure BGP peer")
+			lastErr = err
+			continue
+		}
+		log.WithField("peer", addr).Info("Adding BGP peer")
+		if err := s.bgp.AddPeer(ctx, &api.AddPeerRequest{Peer: peer}); err != nil {
+			log.WithError(err).WithField("peer", addr).Warning("Failed to add BGP peer")
+			lastErr = err
+			continue
+		}
+		s.peers[addr] = p
+	}
+
+	for cidr, nextHop := range s.prefixes {
+		if d.Prefixes[cidr] == nextHop {
+			continue
+		}
+		log.WithField("cidr", cidr).Debug("Withdrawing route")
+		if err := s.deletePath(ctx, cidr, nextHop); err != nil {
+			log.WithError(err).WithField("cidr", cidr).Warning("Failed to withdraw route")
+			lastErr = err
+			continue
+		}
+		delete(s.prefixes, cidr)
+	}
+	for cidr, nextHop := range d.Prefixes {
+		if _, ok := s.prefixes[cidr]; ok {
+			continue
+		}
+		log.WithField("cidr", cidr).Debug("Advertising route")
+		if err := s.addPath(ctx, cidr, nextHop); err != nil {
+			log.WithError(err).WithField("cidr", cidr).Warning("Failed to advertise route")
+			lastErr = err
+			continue
+		}
+		s.prefixes[cidr] = nextHop
+	}
+
+	return lastErr
+}
+
+// peerFromSpec converts the peer to its GoBGP form.
+func (s *speaker) peerFromSpec(p *peerSpec, clusterID string) (*api.Peer, error) {
+	var password string
+	if p.Password != nil {
+		if s.getPassword == nil {
+			return nil, errors.New("BGP passwords require access to the Kubernetes API")
+		}
+		var err error
+		if password, err = s.getPassword(p.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	afiSafi := &api.AfiSafi{Config: &api.AfiSafiConfig{Family: familyForAddress(p.Address), Enabled: true}}
+	peer := &api.Peer{
+		Conf: &api.PeerConf{
+			NeighborAddress: p.Address,
+			PeerAsn:         p.ASNumber,
+			AuthPassword:    password,
+			AllowOwnAsn:     p.AllowOwnAS,
+		},
+		Transport: &api.Transport{
+			RemotePort:   uint32(p.Port),
+			LocalAddress: p.LocalAddress,
+		},
+		AfiSafis: []*api.AfiSafi{afiSafi},
+	}
+	if p.RRClient {
+		peer.RouteReflector = &api.RouteReflector{RouteReflectorClient: true, RouteReflectorClusterId: clusterID}
+	}
+	if p.TTLSecurity > 0 {
+		// TTLSecurity is the maximum number of hops to the peer.
+		peer.TtlSecurity = &api.TtlSecurity{Enabled: true, TtlMin: 256 - uint32(p.TTLSecurity)}
+	}
+	if p.RestartTime > 0 {
+		peer.GracefulRestart = &api.GracefulRestart{Enabled: true, RestartTime: p.RestartTime}
+		afiSafi.MpGracefulRestart = &api.MpGracefulRestart{Config: &api.MpGracefulRestartConfig{Enabled: true}}
+	}
+	return peer, nil
+}
+
+func (s *speaker) addPath(ctx context.Context, cidr, nextHop string) error {
+	path, err := newPath(cidr, nextHop)
+	if err != nil {
+		return err
+	}
+	_, err = s.bgp.AddPath(ctx, &api.AddPathRequest{TableType: api.TableType_GLOBAL, Path: path})
+	return err
+}
+
+func (s *speaker) deletePath(ctx context.Context, cidr, nextHop string) error {
+	path, err := newPath(cidr, nextHop)
+	if err != nil {
+		return err
+	}
+	return s.bgp.DeletePath(ctx, &api.DeletePathRequest{TableType: api.TableType_GLOBAL, Path: path})
+}
+
+// newPath builds the GoBGP path for a route originated by this node.
+func newPath(cidr, nextHop string) (*api.Path, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	ones, _ := ipNet.Mask.Size()
+	origin := bgp.NewPathAttributeOrigin(bgp.BGP_ORIGIN_ATTR_TYPE_IGP)
+	if ipNet.IP.To4() != nil {
+		nlri := bgp.NewIPAddrPrefix(uint8(ones), ipNet.IP.String())
+		return apiutil.NewPath(nlri, false, []bgp.PathAttributeInterface{
+			origin,
+			bgp.NewPathAttributeNextHop(nextHop),
+		}, time.Now())
+	}
+	nlri := bgp.NewIPv6AddrPrefix(uint8(ones), ipNet.IP.String())
+	return apiutil.NewPath(nlri, false, []bgp.PathAttributeInterface{
+		origin,
+		bgp.NewPathAttributeMpReachNLRI(nextHop, []bgp.AddrPrefixInterface{nlri}),
+	}, time.Now())
+}
+
+func familyForAddress(addr string) *api.Family {
+	if net.ParseIP(addr).To4() != nil {
+		return &api.Family{Afi: api.Family_AFI_IP, Safi: api.Family_SAFI_UNICAST}
+	}
+	return &api.Family{Afi: api.Family_AFI_IP6, Safi: api.Family_SAFI_UNICAST}
+}
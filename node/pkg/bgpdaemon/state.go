@@ -0,0 +1,795 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpdaemon
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/osrg/gobgp/v3/api"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
+)
+
+const (
+	defaultASNumber   = 64512
+	defaultListenPort = 179
+
+	globalConfigName        = "default"
+	perNodeConfigNamePrefix = "node."
+
+	// gracefulShutdownCommunity is the GRACEFUL_SHUTDOWN well-known community from RFC 8326.
+	gracefulShutdownCommunity = "65535:0"
+)
+
+// datastoreState is the subset of the datastore provided by the BGP syncer that is
+// relevant to the BGP configuration of this node.
+type datastoreState struct {
+	nodename string
+
+	nodes      map[string]*libapiv3.Node
+	peers      map[string]*apiv3.BGPPeer
+	filters    map[string]*apiv3.BGPFilter
+	configs    map[string]*apiv3.BGPConfiguration
+	pools      map[string]*model.IPPool
+	affinities map[string]bool
+
+	// The BGP graceful shutdown annotation on this node and when it was first seen.
+	gracefulShutdown     string
+	gracefulShutdownSeen time.Time
+}
+
+func newDatastoreState(nodename string) *datastoreState {
+	return &datastoreState{
+		nodename:   nodename,
+		nodes:      make(map[string]*libapiv3.Node),
+		peers:      make(map[string]*apiv3.BGPPeer),
+		filters:    make(map[string]*apiv3.BGPFilter),
+		configs:    make(map[string]*apiv3.BGPConfiguration),
+		pools:      make(map[string]*model.IPPool),
+		affinities: make(map[string]bool),
+	}
+}
+
+// onUpdate applies a single syncer update to the state, returning true if the state was
+// modified.
+func (s *datastoreState) onUpdate(u bapi.Update) bool {
+	switch k := u.Key.(type) {
+	case model.ResourceKey:
+		switch k.Kind {
+		case libapiv3.KindNode:
+			if k.Name == s.nodename {
+				s.onGracefulShutdownAnnotation(u.Value)
+			}
+			return updateMap(s.nodes, k.Name, u.Value)
+		case apiv3.KindBGPPeer:
+			return updateMap(s.peers, k.Name, u.Value)
+		case apiv3.KindBGPFilter:
+			return updateMap(s.filters, k.Name, u.Value)
+		case apiv3.KindBGPConfiguration:
+			return updateMap(s.configs, k.Name, u.Value)
+		}
+	case model.IPPoolKey:
+		return updateMap(s.pools, k.CIDR.String(), u.Value)
+	case model.BlockAffinityKey:
+		// Typha serves the affinities of all nodes, so filter out any that are not ours.
+		// Pending affinities are treated as deletes until they are confirmed.
+		if k.Host != s.nodename {
+			return false
+		}
+		cidr := k.CIDR.String()
+		aff, _ := u.Value.(*model.BlockAffinity)
+		confirmed := aff != nil && aff.State == model.StateConfirmed && !aff.Deleted
+		if s.affinities[cidr] == confirmed {
+			return false
+		}
+		if confirmed {
+			s.affinities[cidr] = true
+		} else {
+			delete(s.affinities, cidr)
+		}
+		return true
+	}
+	log.WithField("key", u.Key).Debug("Ignoring update for unexpected resource")
+	return false
+}
+
+// onGracefulShutdownAnnotation tracks the BGP graceful shutdown annotation on this node,
+// recording when it is first seen so that relative wait times can be resolved.
+func (s *datastoreState) onGracefulShutdownAnnotation(value interface{}) {
+	var annotation string
+	if n, ok := value.(*libapiv3.Node); ok && n != nil {
+		annotation = n.Annotations[gracefulshutdown.Annotation]
+	}
+	if annotation == "" {
+		s.gracefulShutdownSeen = time.Time{}
+	} else if s.gracefulShutdownSeen.IsZero() {
+		s.gracefulShutdownSeen = time.Now()
+	}
+	s.gracefulShutdown = annotation
+}
+
+// updateMap stores or deletes the value in the map, returning true if the map was modified.
+func updateMap[T any](m map[string]*T, name string, value interface{}) bool {
+	v, ok := value.(*T)
+	if !ok || v == nil {
+		if _, exists := m[name]; !exists {
+			return false
+		}
+		delete(m, name)
+		return true
+	}
+	if existing, exists := m[name]; exists && reflect.DeepEqual(existing, v) {
+		return false
+	}
+	m[name] = v
+	return true
+}
+
+// globalSpec contains the BGP settings that can only be applied when the speaker starts.
+type globalSpec struct {
+	ASNumber   uint32
+	RouterID   string
+	ListenPort uint16
+}
+
+// peerSpec describes a BGP session that should exist from this node.
+type peerSpec struct {
+	Address      string
+	Port         uint16
+	ASNumber     uint32
+	LocalAddress string
+	RRClient     bool
+	TTLSecurity  uint8
+	RestartTime  uint32
+	AllowOwnAS   uint32
+	Filters      []string
+	CalicoNode   bool
+	Password     *apiv3.BGPPassword
+
+	// KeepNextHop is set if the original next hop should be advertised to an eBGP peer,
+	// rather than this node's address.
+	KeepNextHop bool
+
+	// ReachableBy is the gateway that the peer is reached through, if it is not on a
+	// directly connected network.
+	ReachableBy string
+}
+
+// prefixCommunities are the BGP communities added to the routes within a CIDR.
+type prefixCommunities struct {
+	CIDR             string
+	Communities      []string
+	LargeCommunities []string
+}
+
+// desiredState is the BGP configuration calculated for this node from the datastore state.
+type desiredState struct {
+	Global globalSpec
+
+	// RRClusterID is non-empty if this node is a route reflector.
+	RRClusterID string
+
+	// Peers is keyed by peer address.
+	Peers map[string]*peerSpec
+
+	// Prefixes is the set of CIDRs that this node originates, mapped to the next hop
+	// that should be advertised for them.
+	Prefixes map[string]string
+
+	// Filters contains the BGPFilters referenced by Peers.
+	Filters map[string]*apiv3.BGPFilter
+
+	// Pools are the IP pools, used when programming learned routes into the kernel.
+	Pools []model.IPPool
+
+	// StaticRoutes maps the host prefixes of the peers that are reached through a
+	// gateway to that gateway.
+	StaticRoutes map[string]string
+
+	// Communities are the communities to add to the routes advertised by this node, from
+	// the BGPConfiguration prefixAdvertisements.
+	Communities []prefixCommunities
+
+	// GracefulShutdown is the progress of a BGP graceful shutdown of this node, if one
+	// has been requested.  While draining, routes are advertised with the
+	// GRACEFUL_SHUTDOWN community; once complete, Peers is empty.
+	GracefulShutdown *apiv3.CalicoNodeBGPGracefulShutdown
+
+	// Unsupported describes the settings in the datastore that the native speaker does
+	// not implement.
+	Unsupported []string
+}
+
+// calculate computes the desired BGP configuration for this node.  It returns nil if
+// this node does not have any BGP configuration.
+func (s *datastoreState) calculate() *desiredState {
+	me := s.nodes[s.nodename]
+	if me == nil || me.Spec.BGP == nil {
+		return nil
+	}
+	myV4, myV6 := nodeIPs(me)
+	myCluster := me.Spec.BGP.RouteReflectorClusterID
+
+	d := &desiredState{
+		Global: globalSpec{
+			ASNumber:   s.nodeASNumber(me),
+			RouterID:   s.routerID(myV4),
+			ListenPort: s.listenPort(s.nodename),
+		},
+		RRClusterID:  myCluster,
+		Peers:        make(map[string]*peerSpec),
+		Prefixes:     make(map[string]string),
+		Filters:      make(map[string]*apiv3.BGPFilter),
+		StaticRoutes: make(map[string]string),
+	}
+
+	// Explicit peerings take precedence over implicit reverse peerings, which in turn
+	// take precedence over the node-to-node mesh.
+	emit := func(p *peerSpec) {
+		if _, ok := d.Peers[p.Address]; ok {
+			log.WithField("peer", p.Address).Debug("Peering already exists")
+			return
+		}
+		if myCluster != "" && p.ASNumber == d.Global.ASNumber {
+			// iBGP peers are clients of this route reflector, unless they are route
+			// reflectors in the same cluster.
+			p.RRClient = s.clusterIDForAddress(p.Address) != myCluster
+		}
+		d.Peers[p.Address] = p
+	}
+
+	for _, p := range s.explicitPeers(me, myV4, myV6) {
+		emit(p)
+	}
+	for _, p := range s.reversePeers(me, myV4, myV6) {
+		emit(p)
+	}
+	for _, p := range s.meshPeers(me, myV4, myV6) {
+		emit(p)
+	}
+
+	for _, p := range d.Peers {
+		for _, name := range p.Filters {
+			if f, ok := s.filters[name]; ok {
+				d.Filters[name] = f
+			} else {
+				log.WithFields(log.Fields{"peer": p.Address, "filter": name}).Warning("Peer references unknown BGPFilter")
+			}
+		}
+		if p.ReachableBy != "" {
+			d.StaticRoutes[hostPrefix(p.Address)] = p.ReachableBy
+		}
+	}
+
+	// Advertise the blocks affine to this node, as long as they are within a pool that
+	// allows BGP export.
+	for cidr := range s.affinities {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		pool := s.poolContaining(block)
+		if pool == nil {
+			log.WithField("block", cidr).Debug("Block is not within a known IP pool")
+			continue
+		}
+		if pool.DisableBGPExport {
+			log.WithField("block", cidr).Debug("Skip block, BGP export is disabled for its pool")
+			continue
+		}
+		nextHop := myV4
+		if block.IP.To4() == nil {
+			nextHop = myV6
+		}
+		if nextHop == "" {
+			continue
+		}
+		d.Prefixes[cidr] = nextHop
+	}
+
+	for _, pool := range s.pools {
+		d.Pools = append(d.Pools, *pool)
+	}
+	sort.Slice(d.Pools, func(i, j int) bool {
+		return d.Pools[i].CIDR.String() < d.Pools[j].CIDR.String()
+	})
+
+	d.Communities = s.prefixCommunities()
+	d.Unsupported = s.unsupported(d.Filters)
+
+	if d.GracefulShutdown = s.gracefulShutdownStatus(time.Now()); d.GracefulShutdown != nil &&
+		d.GracefulShutdown.State == apiv3.BGPGracefulShutdownStateComplete {
+		// The drain period is over, shut down all the BGP sessions.
+		d.Peers = make(map[string]*peerSpec)
+		d.Filters = make(map[string]*apiv3.BGPFilter)
+		d.StaticRoutes = make(map[string]string)
+	}
+
+	return d
+}
+
+// gracefulShutdownStatus returns the progress of any BGP graceful shutdown of this node at
+// the given time, or nil if none has been requested.
+func (s *datastoreState) gracefulShutdownStatus(now time.Time) *apiv3.CalicoNodeBGPGracefulShutdown {
+	if s.gracefulShutdown == "" {
+		return nil
+	}
+	teardown, err := gracefulshutdown.TeardownTime(s.gracefulShutdown, s.gracefulShutdownSeen)
+	if err != nil {
+		log.WithError(err).Warningf("Invalid BGP graceful shutdown request, waiting %v before teardown", gracefulshutdown.DefaultWait)
+		teardown = s.gracefulShutdownSeen.Add(gracefulshutdown.DefaultWait)
+	}
+	state := apiv3.BGPGracefulShutdownStateDraining
+	if !now.Before(teardown) {
+		state = apiv3.BGPGracefulShutdownStateComplete
+	}
+	return &apiv3.CalicoNodeBGPGracefulShutdown{State: state, TeardownTime: metav1.NewTime(teardown)}
+}
+
+// prefixCommunities returns the communities to add to advertised routes.  As with the BIRD
+// configuration, the prefix advertisements in the per-node BGPConfiguration replace the
+// global ones, and communities may be given by value or by name.
+func (s *datastoreState) prefixCommunities() []prefixCommunities {
+	cfg := s.configs[perNodeConfigNamePrefix+s.nodename]
+	if cfg == nil || cfg.Spec.PrefixAdvertisements == nil {
+		cfg = s.configs[globalConfigName]
+	}
+	if cfg == nil {
+		return nil
+	}
+
+	var pcs []prefixCommunities
+	for _, pa := range cfg.Spec.PrefixAdvertisements {
+		pc := prefixCommunities{CIDR: pa.CIDR}
+		for _, c := range pa.Communities {
+			value := c
+			for _, named := range cfg.Spec.Communities {
+				if named.Name == c {
+					value = named.Value
+					break
+				}
+			}
+			switch strings.Count(value, ":") {
+			case 1:
+				pc.Communities = append(pc.Communities, value)
+			case 2:
+				pc.LargeCommunities = append(pc.LargeCommunities, value)
+			default:
+				log.WithFields(log.Fields{"cidr": pa.CIDR, "community": c}).Warning("Unknown BGP community in prefix advertisement")
+			}
+		}
+		sort.Strings(pc.Communities)
+		sort.Strings(pc.LargeCommunities)
+		pcs = append(pcs, pc)
+	}
+	return pcs
+}
+
+// unsupported describes the settings in the datastore, and in the BGPFilters used by this
+// node, that the native speaker does not implement.
+func (s *datastoreState) unsupported(filters map[string]*apiv3.BGPFilter) []string {
+	var settings []string
+	if cfg := s.configs[globalConfigName]; cfg != nil {
+		if len(cfg.Spec.ServiceClusterIPs) > 0 {
+			settings = append(settings, "BGPConfiguration serviceClusterIPs (service IP advertisement)")
+		}
+		if len(cfg.Spec.ServiceExternalIPs) > 0 {
+			settings = append(settings, "BGPConfiguration serviceExternalIPs (service IP advertisement)")
+		}
+		if len(cfg.Spec.ServiceLoadBalancerIPs) > 0 {
+			settings = append(settings, "BGPConfiguration serviceLoadBalancerIPs (service IP advertisement)")
+		}
+	}
+	for _, name := range sortedKeys(filters) {
+		for _, v6 := range []bool{false, true} {
+			for _, dir := range []api.PolicyDirection{api.PolicyDirection_IMPORT, api.PolicyDirection_EXPORT} {
+				for _, r := range filterRules(filters[name], dir, v6) {
+					if r.Interface != "" {
+						settings = append(settings, fmt.Sprintf("BGPFilter %s rule matching interface %q", name, r.Interface))
+					}
+				}
+			}
+		}
+	}
+	return settings
+}
+
+// explicitPeers returns the peerings configured by BGPPeer resources that select this node.
+func (s *datastoreState) explicitPeers(me *libapiv3.Node, myV4, myV6 string) []*peerSpec {
+	var peers []*peerSpec
+	// Global peerings are emitted first so that they take precedence over node-specific
+	// peerings to the same address.
+	for _, globalPass := range []bool{true, false} {
+		for _, name := range sortedKeys(s.peers) {
+			bp := s.peers[name]
+			isGlobal := bp.Spec.Node == "" && bp.Spec.NodeSelector == ""
+			if isGlobal != globalPass {
+				continue
+			}
+			if !isGlobal && !s.nodeSelectedBy(me, bp.Spec.Node, bp.Spec.NodeSelector) {
+				continue
+			}
+
+			if bp.Spec.PeerSelector != "" {
+				for _, peerName := range s.nodesMatching(bp.Spec.PeerSelector) {
+					if peerName == s.nodename {
+						continue
+					}
+					peers = append(peers, s.nodeAsPeers(s.nodes[peerName], myV4, myV6, bp)...)
+				}
+				continue
+			}
+
+			host, port := parseIPPort(bp.Spec.PeerIP)
+			ip := net.ParseIP(host)
+			if ip == nil {
+				log.WithField("peer", name).Error("PeerIP is not assigned or is malformed")
+				continue
+			}
+			if (ip.To4() != nil && myV4 == "") || (ip.To4() == nil && myV6 == "") {
+				log.WithField("peer", name).Debug("No local address in the peer's IP family")
+				continue
+			}
+			asNum := uint32(bp.Spec.ASNumber)
+			calicoNode := false
+			if peerNode := s.nodeWithIP(ip.String()); peerNode != nil {
+				calicoNode = true
+				if port == 0 {
+					port = s.listenPort(peerNode.Name)
+				}
+			}
+			p := s.peerFromResource(ip.String(), port, asNum, myV4, myV6, bp)
+			p.CalicoNode = calicoNode
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// reversePeers returns the peerings to other nodes that are configured to peer with this
+// node, so that BGP sessions between Calico nodes are symmetric.
+func (s *datastoreState) reversePeers(me *libapiv3.Node, myV4, myV6 string) []*peerSpec {
+	var peers []*peerSpec
+	myAS := s.nodeASNumber(me)
+	for _, name := range sortedKeys(s.peers) {
+		bp := s.peers[name]
+		includeV4, includeV6 := false, false
+		if bp.Spec.PeerSelector != "" {
+			if !s.nodeSelectedBy(me, "", bp.Spec.PeerSelector) {
+				continue
+			}
+			includeV4, includeV6 = true, true
+		} else {
+			host, _ := parseIPPort(bp.Spec.PeerIP)
+			if host == "" || (host != myV4 && host != myV6) {
+				continue
+			}
+			asNum := uint32(bp.Spec.ASNumber)
+			if asNum == 0 {
+				asNum = s.globalASNumber()
+			}
+			if asNum != myAS {
+				continue
+			}
+			includeV4 = strings.Contains(host, ".")
+			includeV6 = !includeV4
+		}
+
+		var localNodes []string
+		if bp.Spec.NodeSelector != "" {
+			localNodes = s.nodesMatching(bp.Spec.NodeSelector)
+		} else if bp.Spec.Node != "" {
+			localNodes = []string{bp.Spec.Node}
+		} else {
+			localNodes = s.nodesMatching("all()")
+		}
+		for _, n := range localNodes {
+			if n == s.nodename || s.nodes[n] == nil {
+				continue
+			}
+			v4, v6 := myV4, myV6
+			if !includeV4 {
+				v4 = ""
+			}
+			if !includeV6 {
+				v6 = ""
+			}
+			peers = append(peers, s.nodeAsPeers(s.nodes[n], v4, v6, bp)...)
+		}
+	}
+	return peers
+}
+
+// meshPeers returns the node-to-node mesh peerings, if the mesh is enabled.
+func (s *datastoreState) meshPeers(me *libapiv3.Node, myV4, myV6 string) []*peerSpec {
+	if !s.meshEnabled() {
+		return nil
+	}
+	if me.Spec.BGP.RouteReflectorClusterID != "" {
+		log.Debug("This node is a route reflector, ignoring node-to-node mesh setting")
+		return nil
+	}
+
+	var restartTime uint32
+	if cfg := s.configs[globalConfigName]; cfg != nil && cfg.Spec.NodeMeshMaxRestartTime != nil {
+		restartTime = uint32(cfg.Spec.NodeMeshMaxRestartTime.Duration.Seconds())
+	}
+	var password *apiv3.BGPPassword
+	if cfg := s.configs[globalConfigName]; cfg != nil {
+		password = cfg.Spec.NodeMeshPassword
+	}
+
+	var peers []*peerSpec
+	for _, name := range sortedKeys(s.nodes) {
+		n := s.nodes[name]
+		if name == s.nodename || n.Spec.BGP == nil {
+			continue
+		}
+		if n.Spec.BGP.RouteReflectorClusterID != "" {
+			log.WithField("node", name).Debug("Skipping route reflector in node-to-node mesh")
+			continue
+		}
+		v4, v6 := nodeIPs(n)
+		for _, addr := range []string{v4, v6} {
+			if addr == "" || (addr == v4 && myV4 == "") || (addr == v6 && myV6 == "") {
+				continue
+			}
+			peers = append(peers, &peerSpec{
+				Address:     addr,
+				Port:        s.listenPort(name),
+				ASNumber:    s.nodeASNumber(n),
+				RestartTime: restartTime,
+				CalicoNode:  true,
+				Password:    password,
+			})
+		}
+	}
+	return peers
+}
+
+// nodeAsPeers returns the peerings to the given Calico node, in each of the IP families
+// for which this node has an address.
+func (s *datastoreState) nodeAsPeers(n *libapiv3.Node, myV4, myV6 string, bp *apiv3.BGPPeer) []*peerSpec {
+	if n == nil || n.Spec.BGP == nil {
+		return nil
+	}
+	v4, v6 := nodeIPs(n)
+	var peers []*peerSpec
+	for _, addr := range []string{v4, v6} {
+		if addr == "" || (addr == v4 && myV4 == "") || (addr == v6 && myV6 == "") {
+			continue
+		}
+		p := s.peerFromResource(addr, s.listenPort(n.Name), s.nodeASNumber(n), myV4, myV6, bp)
+		p.CalicoNode = true
+		peers = append(peers, p)
+	}
+	return peers
+}
+
+// peerFromResource builds the peering to the given address, using the session settings
+// from the BGPPeer resource.
+func (s *datastoreState) peerFromResource(addr string, port uint16, asNum uint32, myV4, myV6 string, bp *apiv3.BGPPeer) *peerSpec {
+	if asNum == 0 {
+		asNum = s.globalASNumber()
+	}
+	p := &peerSpec{
+		Address:  addr,
+		Port:     port,
+		ASNumber: asNum,
+		Filters:  bp.Spec.Filters,
+		Password: bp.Spec.Password,
+	}
+	if bp.Spec.SourceAddress != apiv3.SourceAddressNone {
+		// The default is to use the node IP as the source address.
+		if strings.Contains(addr, ":") {
+			p.LocalAddress = myV6
+		} else {
+			p.LocalAddress = myV4
+		}
+	}
+	if bp.Spec.TTLSecurity != nil {
+		p.TTLSecurity = *bp.Spec.TTLSecurity
+	}
+	if bp.Spec.MaxRestartTime != nil {
+		p.RestartTime = uint32(bp.Spec.MaxRestartTime.Duration.Seconds())
+	}
+	if bp.Spec.NumAllowedLocalASNumbers != nil {
+		p.AllowOwnAS = uint32(*bp.Spec.NumAllowedLocalASNumbers)
+	}
+	p.KeepNextHop = bp.Spec.KeepOriginalNextHop
+	if bp.Spec.ReachableBy != "" {
+		gw := net.ParseIP(bp.Spec.ReachableBy)
+		if gw == nil {
+			log.WithField("peer", addr).Error("ReachableBy address is malformed")
+		} else if (gw.To4() == nil) != strings.Contains(addr, ":") {
+			log.WithField("peer", addr).Error("ReachableBy address family does not match PeerIP")
+		} else {
+			p.ReachableBy = gw.String()
+		}
+	}
+	return p
+}
+
+func (s *datastoreState) nodeSelectedBy(n *libapiv3.Node, nodeName, rawSelector string) bool {
+	if nodeName != "" {
+		return nodeName == n.Name
+	}
+	sel, err := selector.Parse(rawSelector)
+	if err != nil {
+		log.Errorf("Couldn't parse selector: %v", rawSelector)
+		return false
+	}
+	return sel.Evaluate(n.Labels)
+}
+
+func (s *datastoreState) nodesMatching(rawSelector string) []string {
+	nodeNames := []string{}
+	sel, err := selector.Parse(rawSelector)
+	if err != nil {
+		log.Errorf("Couldn't parse selector: %v", rawSelector)
+		return nodeNames
+	}
+	for _, name := range sortedKeys(s.nodes) {
+		if sel.Evaluate(s.nodes[name].Labels) {
+			nodeNames = append(nodeNames, name)
+		}
+	}
+	return nodeNames
+}
+
+func (s *datastoreState) nodeWithIP(ip string) *libapiv3.Node {
+	for _, name := range sortedKeys(s.nodes) {
+		v4, v6 := nodeIPs(s.nodes[name])
+		if v4 == ip || v6 == ip {
+			return s.nodes[name]
+		}
+	}
+	return nil
+}
+
+func (s *datastoreState) clusterIDForAddress(addr string) string {
+	if n := s.nodeWithIP(addr); n != nil && n.Spec.BGP != nil {
+		return n.Spec.BGP.RouteReflectorClusterID
+	}
+	return ""
+}
+
+func (s *datastoreState) poolContaining(block *net.IPNet) *model.IPPool {
+	for _, pool := range s.pools {
+		ones, _ := pool.CIDR.Mask.Size()
+		blockOnes, _ := block.Mask.Size()
+		if pool.CIDR.Contains(block.IP) && ones <= blockOnes {
+			return pool
+		}
+	}
+	return nil
+}
+
+func (s *datastoreState) meshEnabled() bool {
+	if cfg := s.configs[globalConfigName]; cfg != nil && cfg.Spec.NodeToNodeMeshEnabled != nil {
+		return *cfg.Spec.NodeToNodeMeshEnabled
+	}
+	return true
+}
+
+func (s *datastoreState) globalASNumber() uint32 {
+	if cfg := s.configs[globalConfigName]; cfg != nil && cfg.Spec.ASNumber != nil {
+		return uint32(*cfg.Spec.ASNumber)
+	}
+	return defaultASNumber
+}
+
+func (s *datastoreState) nodeASNumber(n *libapiv3.Node) uint32 {
+	if n.Spec.BGP != nil && n.Spec.BGP.ASNumber != nil {
+		return uint32(*n.Spec.BGP.ASNumber)
+	}
+	return s.globalASNumber()
+}
+
+// listenPort returns the BGP port used by the given node, preferring the per-node
+// BGPConfiguration over the global one.
+func (s *datastoreState) listenPort(nodeName string) uint16 {
+	if cfg := s.configs[perNodeConfigNamePrefix+nodeName]; cfg != nil && cfg.Spec.ListenPort != 0 {
+		return cfg.Spec.ListenPort
+	}
+	if cfg := s.configs[globalConfigName]; cfg != nil && cfg.Spec.ListenPort != 0 {
+		return cfg.Spec.ListenPort
+	}
+	return defaultListenPort
+}
+
+// routerID returns the BGP router ID, honouring CALICO_ROUTER_ID in the same way as the
+// BIRD configuration.
+func (s *datastoreState) routerID(myV4 string) string {
+	routerID := os.Getenv("CALICO_ROUTER_ID")
+	if routerID == "hash" {
+		return hashToIPv4(s.nodename)
+	}
+	if routerID != "" {
+		return routerID
+	}
+	return myV4
+}
+
+// hashToIPv4 derives a stable IPv4 router ID from the node name, matching the function
+// of the same name used by the confd templates.
+func hashToIPv4(nodeName string) string {
+	hash := sha256.Sum256([]byte(nodeName))
+	ip0 := int(hash[0])
+	// BGP doesn't allow router IDs in special IP ranges (e.g., 224.x.x.x)
+	if ip0 > 223 {
+		ip0 -= 32
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", ip0, hash[1], hash[2], hash[3])
+}
+
+// nodeIPs returns the BGP IPv4 and IPv6 addresses of the node, without prefix lengths.
+func nodeIPs(n *libapiv3.Node) (string, string) {
+	if n.Spec.BGP == nil {
+		return "", ""
+	}
+	return stripPrefixLength(n.Spec.BGP.IPv4Address), stripPrefixLength(n.Spec.BGP.IPv6Address)
+}
+
+func stripPrefixLength(cidr string) string {
+	if cidr == "" {
+		return ""
+	}
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		if ip = net.ParseIP(cidr); ip == nil {
+			return ""
+		}
+	}
+	return ip.String()
+}
+
+// parseIPPort splits a BGPPeer PeerIP into its address and optional port.
+func parseIPPort(ipPort string) (string, uint16) {
+	host, port, err := net.SplitHostPort(ipPort)
+	if err != nil {
+		return ipPort, 0
+	}
+	p, err := strconv.ParseUint(port, 0, 16)
+	if err != nil {
+		log.Warning("Error parsing port.")
+		return ipPort, 0
+	}
+	return host, uint16(p)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
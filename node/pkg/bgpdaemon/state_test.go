@@ -0,0 +1,421 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpdaemon
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	api "github.com/osrg/gobgp/v3/api"
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+func nodeUpdate(name, ipv4 string, labels map[string]string, rrClusterID string) bapi.Update {
+	return bapi.Update{
+		KVPair: model.KVPair{
+			Key: model.ResourceKey{Kind: libapiv3.KindNode, Name: name},
+			Value: &libapiv3.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				Spec: libapiv3.NodeSpec{BGP: &libapiv3.NodeBGPSpec{
+					IPv4Address:             ipv4,
+					RouteReflectorClusterID: rrClusterID,
+				}},
+			},
+		},
+		UpdateType: bapi.UpdateTypeKVNew,
+	}
+}
+
+func peerUpdate(name string, spec apiv3.BGPPeerSpec) bapi.Update {
+	return bapi.Update{
+		KVPair: model.KVPair{
+			Key:   model.ResourceKey{Kind: apiv3.KindBGPPeer, Name: name},
+			Value: &apiv3.BGPPeer{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec},
+		},
+		UpdateType: bapi.UpdateTypeKVNew,
+	}
+}
+
+func configUpdate(name string, spec apiv3.BGPConfigurationSpec) bapi.Update {
+	return bapi.Update{
+		KVPair: model.KVPair{
+			Key:   model.ResourceKey{Kind: apiv3.KindBGPConfiguration, Name: name},
+			Value: &apiv3.BGPConfiguration{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec},
+		},
+		UpdateType: bapi.UpdateTypeKVNew,
+	}
+}
+
+func poolUpdate(cidr string, disableExport bool) bapi.Update {
+	c := cnet.MustParseCIDR(cidr)
+	return bapi.Update{
+		KVPair: model.KVPair{
+			Key:   model.IPPoolKey{CIDR: c},
+			Value: &model.IPPool{CIDR: c, DisableBGPExport: disableExport},
+		},
+		UpdateType: bapi.UpdateTypeKVNew,
+	}
+}
+
+func affinityUpdate(cidr, host string, state model.BlockAffinityState) bapi.Update {
+	return bapi.Update{
+		KVPair: model.KVPair{
+			Key:   model.BlockAffinityKey{CIDR: cnet.MustParseCIDR(cidr), Host: host},
+			Value: &model.BlockAffinity{State: state},
+		},
+		UpdateType: bapi.UpdateTypeKVNew,
+	}
+}
+
+var _ = Describe("BGP daemon state calculation", func() {
+	var s *datastoreState
+
+	apply := func(updates ...bapi.Update) {
+		for _, u := range updates {
+			s.onUpdate(u)
+		}
+	}
+
+	BeforeEach(func() {
+		s = newDatastoreState("node1")
+		apply(
+			nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rack": "a"}, ""),
+			nodeUpdate("node2", "10.0.0.2/24", map[string]string{"rack": "a"}, ""),
+			nodeUpdate("node3", "10.0.1.3/24", map[string]string{"rack": "b"}, ""),
+		)
+	})
+
+	It("should return nil if this node has no BGP configuration", func() {
+		s = newDatastoreState("node4")
+		Expect(s.calculate()).To(BeNil())
+	})
+
+	It("should use the defaults for global settings", func() {
+		d := s.calculate()
+		Expect(d.Global).To(Equal(globalSpec{ASNumber: 64512, RouterID: "10.0.0.1", ListenPort: 179}))
+	})
+
+	It("should use AS number and listen port from BGPConfiguration", func() {
+		asn := numorstring.ASNumber(65001)
+		apply(
+			configUpdate("default", apiv3.BGPConfigurationSpec{ASNumber: &asn, ListenPort: 1179}),
+			configUpdate("node.node1", apiv3.BGPConfigurationSpec{ListenPort: 2179}),
+		)
+		d := s.calculate()
+		Expect(d.Global).To(Equal(globalSpec{ASNumber: 65001, RouterID: "10.0.0.1", ListenPort: 2179}))
+		Expect(d.Peers["10.0.0.2"].Port).To(Equal(uint16(1179)))
+		Expect(d.Peers["10.0.0.2"].ASNumber).To(Equal(uint32(65001)))
+	})
+
+	It("should peer with every other node when the mesh is enabled", func() {
+		d := s.calculate()
+		Expect(d.Peers).To(HaveLen(2))
+		Expect(d.Peers).To(HaveKey("10.0.0.2"))
+		Expect(d.Peers).To(HaveKey("10.0.1.3"))
+		Expect(d.Peers["10.0.0.2"].CalicoNode).To(BeTrue())
+	})
+
+	It("should not peer with other nodes when the mesh is disabled", func() {
+		disabled := false
+		apply(configUpdate("default", apiv3.BGPConfigurationSpec{NodeToNodeMeshEnabled: &disabled}))
+		Expect(s.calculate().Peers).To(BeEmpty())
+	})
+
+	It("should handle global and selected BGPPeers", func() {
+		disabled := false
+		apply(
+			configUpdate("default", apiv3.BGPConfigurationSpec{NodeToNodeMeshEnabled: &disabled}),
+			peerUpdate("tor", apiv3.BGPPeerSpec{NodeSelector: "rack == 'a'", PeerIP: "10.0.0.254", ASNumber: 65100}),
+			peerUpdate("other-rack", apiv3.BGPPeerSpec{NodeSelector: "rack == 'b'", PeerIP: "10.0.1.254", ASNumber: 65100}),
+			peerUpdate("global", apiv3.BGPPeerSpec{PeerIP: "192.168.0.1:1179", ASNumber: 65200}),
+		)
+		d := s.calculate()
+		Expect(d.Peers).To(HaveLen(2))
+		Expect(d.Peers["10.0.0.254"].ASNumber).To(Equal(uint32(65100)))
+		Expect(d.Peers["10.0.0.254"].LocalAddress).To(Equal("10.0.0.1"))
+		Expect(d.Peers["10.0.0.254"].CalicoNode).To(BeFalse())
+		Expect(d.Peers["192.168.0.1"].Port).To(Equal(uint16(1179)))
+	})
+
+	It("should add reverse peerings for selector based peerings", func() {
+		disabled := false
+		apply(
+			configUpdate("default", apiv3.BGPConfigurationSpec{NodeToNodeMeshEnabled: &disabled}),
+			peerUpdate("to-rack-b", apiv3.BGPPeerSpec{NodeSelector: "rack == 'b'", PeerSelector: "rack == 'a'"}),
+		)
+		// node1 is selected by the peer selector, so should peer with node3.
+		d := s.calculate()
+		Expect(d.Peers).To(HaveLen(1))
+		Expect(d.Peers).To(HaveKey("10.0.1.3"))
+	})
+
+	It("should configure route reflector clients", func() {
+		disabled := false
+		apply(
+			configUpdate("default", apiv3.BGPConfigurationSpec{NodeToNodeMeshEnabled: &disabled}),
+			nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rr": "true"}, "224.0.0.1"),
+			nodeUpdate("node2", "10.0.0.2/24", map[string]string{"rr": "true"}, "224.0.0.1"),
+			peerUpdate("rr", apiv3.BGPPeerSpec{NodeSelector: "all()", PeerSelector: "has(rr)"}),
+		)
+		d := s.calculate()
+		Expect(d.RRClusterID).To(Equal("224.0.0.1"))
+		Expect(d.Peers).To(HaveLen(2))
+		Expect(d.Peers["10.0.0.2"].RRClient).To(BeFalse())
+		Expect(d.Peers["10.0.1.3"].RRClient).To(BeTrue())
+	})
+
+	It("should advertise confirmed blocks in pools that allow export", func() {
+		apply(
+			poolUpdate("192.168.0.0/16", false),
+			poolUpdate("172.16.0.0/16", true),
+			affinityUpdate("192.168.1.0/26", "node1", model.StateConfirmed),
+			affinityUpdate("192.168.2.0/26", "node1", model.StatePending),
+			affinityUpdate("192.168.3.0/26", "node2", model.StateConfirmed),
+			affinityUpdate("172.16.1.0/26", "node1", model.StateConfirmed),
+			affinityUpdate("10.10.0.0/26", "node1", model.StateConfirmed),
+		)
+		d := s.calculate()
+		Expect(d.Prefixes).To(Equal(map[string]string{"192.168.1.0/26": "10.0.0.1"}))
+		Expect(d.Pools).To(HaveLen(2))
+
+		// Confirmed blocks that move back to pending are withdrawn.
+		Expect(s.onUpdate(affinityUpdate("192.168.1.0/26", "node1", model.StatePending))).To(BeTrue())
+		Expect(s.calculate().Prefixes).To(BeEmpty())
+	})
+
+	It("should report whether an update modified the state", func() {
+		u := peerUpdate("global", apiv3.BGPPeerSpec{PeerIP: "192.168.0.1", ASNumber: 65200})
+		Expect(s.onUpdate(u)).To(BeTrue())
+		Expect(s.onUpdate(u)).To(BeFalse())
+		u.Value = nil
+		u.UpdateType = bapi.UpdateTypeKVDeleted
+		Expect(s.onUpdate(u)).To(BeTrue())
+		Expect(s.peers).To(BeEmpty())
+	})
+
+	It("should hash the node name for the router ID if configured", func() {
+		Expect(os.Setenv("CALICO_ROUTER_ID", "hash")).To(Succeed())
+		defer os.Unsetenv("CALICO_ROUTER_ID")
+		Expect(s.calculate().Global.RouterID).To(Equal(hashToIPv4("node1")))
+	})
+
+	It("should keep the original next hop and add static routes for peers reached through a gateway", func() {
+		apply(
+			peerUpdate("tor", apiv3.BGPPeerSpec{PeerIP: "172.20.0.1", ASNumber: 65100, KeepOriginalNextHop: true, ReachableBy: "10.0.0.254"}),
+			peerUpdate("bad-family", apiv3.BGPPeerSpec{PeerIP: "172.20.0.2", ASNumber: 65100, ReachableBy: "fd00::1"}),
+		)
+		d := s.calculate()
+		Expect(d.Peers["172.20.0.1"].KeepNextHop).To(BeTrue())
+		Expect(d.Peers["172.20.0.2"].ReachableBy).To(BeEmpty())
+		Expect(d.StaticRoutes).To(Equal(map[string]string{"172.20.0.1/32": "10.0.0.254"}))
+	})
+
+	It("should resolve the communities of prefix advertisements, preferring the per-node configuration", func() {
+		apply(configUpdate("default", apiv3.BGPConfigurationSpec{
+			Communities: []apiv3.Community{{Name: "anycast", Value: "65001:100:1"}},
+			PrefixAdvertisements: []apiv3.PrefixAdvertisement{
+				{CIDR: "192.168.0.0/16", Communities: []string{"anycast", "65001:10"}},
+			},
+		}))
+		Expect(s.calculate().Communities).To(Equal([]prefixCommunities{{
+			CIDR:             "192.168.0.0/16",
+			Communities:      []string{"65001:10"},
+			LargeCommunities: []string{"65001:100:1"},
+		}}))
+
+		apply(configUpdate("node.node1", apiv3.BGPConfigurationSpec{
+			PrefixAdvertisements: []apiv3.PrefixAdvertisement{
+				{CIDR: "192.168.1.0/24", Communities: []string{"65001:20"}},
+			},
+		}))
+		Expect(s.calculate().Communities).To(Equal([]prefixCommunities{{
+			CIDR:        "192.168.1.0/24",
+			Communities: []string{"65001:20"},
+		}}))
+	})
+
+	It("should report the settings that are not supported", func() {
+		Expect(s.calculate().Unsupported).To(BeEmpty())
+		apply(
+			configUpdate("default", apiv3.BGPConfigurationSpec{
+				ServiceClusterIPs: []apiv3.ServiceClusterIPBlock{{CIDR: "10.96.0.0/12"}},
+			}),
+			peerUpdate("tor", apiv3.BGPPeerSpec{PeerIP: "10.0.0.254", ASNumber: 65100, Filters: []string{"f1"}}),
+			bapi.Update{
+				KVPair: model.KVPair{
+					Key: model.ResourceKey{Kind: apiv3.KindBGPFilter, Name: "f1"},
+					Value: &apiv3.BGPFilter{
+						ObjectMeta: metav1.ObjectMeta{Name: "f1"},
+						Spec: apiv3.BGPFilterSpec{
+							ExportV4: []apiv3.BGPFilterRuleV4{{Interface: "eth0", Action: apiv3.Accept}},
+						},
+					},
+				},
+				UpdateType: bapi.UpdateTypeKVNew,
+			},
+		)
+		Expect(s.calculate().Unsupported).To(Equal([]string{
+			"BGPConfiguration serviceClusterIPs (service IP advertisement)",
+			`BGPFilter f1 rule matching interface "eth0"`,
+		}))
+	})
+
+	It("should drain routes and then shut down the sessions for a graceful shutdown", func() {
+		gracefulShutdown := func(value string) {
+			u := nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rack": "a"}, "")
+			u.Value.(*libapiv3.Node).Annotations = map[string]string{"projectcalico.org/bgp-graceful-shutdown": value}
+			apply(u)
+		}
+
+		teardown := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		gracefulShutdown(teardown.Format(time.RFC3339))
+		d := s.calculate()
+		Expect(d.GracefulShutdown.State).To(Equal(apiv3.BGPGracefulShutdownStateDraining))
+		Expect(d.GracefulShutdown.TeardownTime.Time).To(BeTemporally("==", teardown))
+		Expect(d.Peers).To(HaveLen(2))
+
+		gracefulShutdown(time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+		d = s.calculate()
+		Expect(d.GracefulShutdown.State).To(Equal(apiv3.BGPGracefulShutdownStateComplete))
+		Expect(d.Peers).To(BeEmpty())
+
+		apply(nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rack": "a"}, ""))
+		d = s.calculate()
+		Expect(d.GracefulShutdown).To(BeNil())
+		Expect(d.Peers).To(HaveLen(2))
+	})
+
+	It("should resolve a relative graceful shutdown wait from the previously recorded request time", func() {
+		requested := time.Now().Add(-time.Minute).Truncate(time.Second)
+		s.gracefulShutdownSeen = requested
+
+		u := nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rack": "a"}, "")
+		u.Value.(*libapiv3.Node).Annotations = map[string]string{"projectcalico.org/bgp-graceful-shutdown": "1h"}
+		apply(u)
+		Expect(s.calculate().GracefulShutdown.TeardownTime.Time).To(BeTemporally("==", requested.Add(time.Hour)))
+
+		apply(nodeUpdate("node1", "10.0.0.1/24", map[string]string{"rack": "a"}, ""))
+		Expect(s.gracefulShutdownSeen.IsZero()).To(BeTrue())
+	})
+})
+
+var _ = Describe("BGP daemon policy calculation", func() {
+	It("should reject routes outside the IP pools for non route reflector clients", func() {
+		d := &desiredState{
+			Peers: map[string]*peerSpec{"10.0.0.2": {Address: "10.0.0.2"}},
+			Pools: []model.IPPool{{CIDR: cnet.MustParseCIDR("192.168.0.0/16")}},
+		}
+		req := buildPolicies(d)
+		Expect(req.Policies).To(HaveLen(1))
+		Expect(req.Policies[0].Name).To(Equal(exportPoolsPolicy))
+		Expect(req.Policies[0].Statements[0].Conditions.PrefixSet.Type).To(Equal(api.MatchSet_INVERT))
+		Expect(req.Policies[0].Statements[0].Actions.RouteAction).To(Equal(api.RouteAction_REJECT))
+		Expect(req.DefinedSets).To(ContainElement(&api.DefinedSet{
+			DefinedType: api.DefinedType_PREFIX,
+			Name:        "calico-pools-v4",
+			Prefixes:    []*api.Prefix{{IpPrefix: "192.168.0.0/16", MaskLengthMin: 16, MaskLengthMax: 32}},
+		}))
+	})
+
+	It("should not restrict exports to route reflector clients", func() {
+		d := &desiredState{Peers: map[string]*peerSpec{"10.0.0.2": {Address: "10.0.0.2", RRClient: true}}}
+		req := buildPolicies(d)
+		Expect(req.Policies).To(BeEmpty())
+		Expect(req.Assignments).To(HaveLen(2))
+	})
+
+	It("should convert BGPFilter rules to per-peer statements", func() {
+		filter := &apiv3.BGPFilter{
+			ObjectMeta: metav1.ObjectMeta{Name: "f1"},
+			Spec: apiv3.BGPFilterSpec{
+				ImportV4: []apiv3.BGPFilterRuleV4{
+					{CIDR: "10.10.0.0/16", MatchOperator: apiv3.In, Action: apiv3.Reject},
+				},
+				ExportV4: []apiv3.BGPFilterRuleV4{
+					{CIDR: "192.168.1.0/24", MatchOperator: apiv3.NotEqual, Action: apiv3.Accept},
+					{Interface: "eth0", Action: apiv3.Accept},
+				},
+			},
+		}
+		d := &desiredState{
+			Peers:   map[string]*peerSpec{"10.0.0.2": {Address: "10.0.0.2", RRClient: true, Filters: []string{"f1"}}},
+			Filters: map[string]*apiv3.BGPFilter{"f1": filter},
+		}
+		req := buildPolicies(d)
+		Expect(req.Policies).To(HaveLen(2))
+
+		imp := req.Policies[0]
+		Expect(imp.Name).To(Equal("calico-import-10.0.0.2"))
+		Expect(imp.Statements).To(HaveLen(1))
+		Expect(imp.Statements[0].Conditions.NeighborSet.Name).To(Equal("calico-peer-10.0.0.2"))
+		Expect(imp.Statements[0].Actions.RouteAction).To(Equal(api.RouteAction_REJECT))
+
+		// The interface rule cannot match and is skipped.
+		exp := req.Policies[1]
+		Expect(exp.Name).To(Equal("calico-export-10.0.0.2"))
+		Expect(exp.Statements).To(HaveLen(1))
+		Expect(exp.Statements[0].Conditions.PrefixSet.Type).To(Equal(api.MatchSet_INVERT))
+		Expect(exp.Statements[0].Actions.RouteAction).To(Equal(api.RouteAction_ACCEPT))
+
+		Expect(rulePrefix(filterRules(filter, api.PolicyDirection_IMPORT, false)[0], false)).To(Equal(
+			&api.Prefix{IpPrefix: "10.10.0.0/16", MaskLengthMin: 16, MaskLengthMax: 32},
+		))
+		Expect(rulePrefix(filterRules(filter, api.PolicyDirection_EXPORT, false)[0], false)).To(Equal(
+			&api.Prefix{IpPrefix: "192.168.1.0/24", MaskLengthMin: 24, MaskLengthMax: 24},
+		))
+	})
+
+	It("should add communities and keep next hops before the filters", func() {
+		d := &desiredState{
+			Global: globalSpec{ASNumber: 64512},
+			Peers: map[string]*peerSpec{
+				"10.0.0.2":   {Address: "10.0.0.2", ASNumber: 64512, RRClient: true, KeepNextHop: true},
+				"10.0.0.254": {Address: "10.0.0.254", ASNumber: 65100, RRClient: true, KeepNextHop: true},
+			},
+			Communities: []prefixCommunities{
+				{CIDR: "192.168.0.0/16", Communities: []string{"65001:10"}, LargeCommunities: []string{"65001:100:1"}},
+			},
+			GracefulShutdown: &apiv3.CalicoNodeBGPGracefulShutdown{State: apiv3.BGPGracefulShutdownStateDraining},
+		}
+		req := buildPolicies(d)
+		Expect(req.Policies).To(HaveLen(2))
+
+		communities := req.Policies[0]
+		Expect(communities.Name).To(Equal(communitiesPolicy))
+		Expect(communities.Statements).To(HaveLen(2))
+		Expect(communities.Statements[0].Conditions).To(BeNil())
+		Expect(communities.Statements[0].Actions.Community.Communities).To(Equal([]string{"65535:0"}))
+		Expect(communities.Statements[1].Conditions.PrefixSet.Name).To(Equal("calico-communities-0"))
+		Expect(communities.Statements[1].Actions.Community.Communities).To(Equal([]string{"65001:10"}))
+		Expect(communities.Statements[1].Actions.LargeCommunity.Communities).To(Equal([]string{"65001:100:1"}))
+		Expect(communities.Statements[1].Actions.RouteAction).To(Equal(api.RouteAction_NONE))
+
+		// The next hop is only kept for the eBGP peer.
+		nextHop := req.Policies[1]
+		Expect(nextHop.Name).To(Equal(keepNextHopPolicy))
+		Expect(nextHop.Statements).To(HaveLen(1))
+		Expect(nextHop.Statements[0].Conditions.NeighborSet.Name).To(Equal("calico-peer-10.0.0.254"))
+		Expect(nextHop.Statements[0].Actions.Nexthop.Unchanged).To(BeTrue())
+	})
+})
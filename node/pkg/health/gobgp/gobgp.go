@@ -0,0 +1,85 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gobgp queries the native BGP speaker over the GoBGP gRPC API for the
+// calico/node health checks.
+package gobgp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	api "github.com/osrg/gobgp/v3/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/projectcalico/calico/node/pkg/bgpdaemon"
+)
+
+// Peer is the state of a BGP session of the native BGP speaker.
+type Peer struct {
+	PeerIP      string
+	Established bool
+	Restarting  bool
+}
+
+// Ping checks that the native BGP speaker is running BGP.
+func Ping(ctx context.Context) error {
+	return withClient(func(c api.GobgpApiClient) error {
+		r, err := c.GetBgp(ctx, &api.GetBgpRequest{})
+		if err != nil {
+			return fmt.Errorf("Error querying the native BGP speaker: %v", err)
+		}
+		if r.Global.GetAsn() == 0 {
+			return errors.New("the native BGP speaker has not started BGP")
+		}
+		return nil
+	})
+}
+
+// GetPeers returns the BGP sessions of the native BGP speaker.
+func GetPeers(ctx context.Context) ([]Peer, error) {
+	var peers []Peer
+	err := withClient(func(c api.GobgpApiClient) error {
+		stream, err := c.ListPeer(ctx, &api.ListPeerRequest{})
+		if err != nil {
+			return fmt.Errorf("Error querying the native BGP speaker: %v", err)
+		}
+		for {
+			r, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("Error querying the native BGP speaker: %v", err)
+			}
+			peers = append(peers, Peer{
+				PeerIP:      r.Peer.GetConf().GetNeighborAddress(),
+				Established: r.Peer.GetState().GetSessionState() == api.PeerState_ESTABLISHED,
+				Restarting:  r.Peer.GetGracefulRestart().GetLocalRestarting(),
+			})
+		}
+	})
+	return peers, err
+}
+
+func withClient(f func(api.GobgpApiClient) error) error {
+	conn, err := grpc.NewClient(bgpdaemon.GRPCListenAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("Error connecting to the native BGP speaker: %v", err)
+	}
+	defer conn.Close() // nolint: errcheck
+	return f(api.NewGobgpApiClient(conn))
+}
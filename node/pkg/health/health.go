@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/projectcalico/calico/node/pkg/health/bird"
+	"github.com/projectcalico/calico/node/pkg/health/gobgp"
 )
 
 var (
@@ -54,12 +56,15 @@ func init() {
 	felixLivenessEp = "http://" + felixHost + ":" + felixPort + "/liveness"
 }
 
-func Run(bird, bird6, felixReady, felixLive, birdLive, bird6Live bool, thresholdTime time.Duration) {
-	livenessChecks := felixLive || birdLive || bird6Live
-	readinessChecks := bird || felixReady || bird6
+// Run runs the requested health checks and exits with a non-zero status if any fail.  The
+// BIRD checks apply when CALICO_NETWORKING_BACKEND is "bird", and the BGP daemon checks
+// when it is "gobgp".
+func Run(bird, bird6, felixReady, felixLive, birdLive, bird6Live, bgpDaemonReady, bgpDaemonLive bool, thresholdTime time.Duration) {
+	livenessChecks := felixLive || birdLive || bird6Live || bgpDaemonLive
+	readinessChecks := bird || felixReady || bird6 || bgpDaemonReady
 
 	if !livenessChecks && !readinessChecks {
-		fmt.Printf("calico/node check error: must specify at least one of -bird-live, -bird6-live, -felix-live, -bird, -bird6, or -felix\n")
+		fmt.Printf("calico/node check error: must specify at least one of -bird-live, -bird6-live, -bgp-daemon-live, -felix-live, -bird, -bird6, -bgp-daemon-ready, or -felix\n")
 		os.Exit(1)
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), thresholdTime)
@@ -113,6 +118,20 @@ func Run(bird, bird6, felixReady, felixLive, birdLive, bird6Live bool, threshold
 		})
 	}
 
+	if bgpDaemonLive {
+		g.Go(func() error {
+			if err := checkServiceIsLive([]string{"calico-bgp-daemon"}); err != nil {
+				return fmt.Errorf("calico/node is not ready: BGP daemon is not live: %+v", err)
+			}
+
+			// Check that the BGP daemon is actually responding to requests.
+			if err := gobgp.Ping(ctx); err != nil {
+				return fmt.Errorf("calico/node is not ready: BGP daemon is not live: %+v", err)
+			}
+			return nil
+		})
+	}
+
 	if felixReady {
 		g.Go(func() error {
 			if err := checkFelixHealth(ctx, felixReadinessEp, "readiness"); err != nil {
@@ -139,6 +158,14 @@ func Run(bird, bird6, felixReady, felixLive, birdLive, bird6Live bool, threshold
 			return nil
 		})
 	}
+	if bgpDaemonReady {
+		g.Go(func() error {
+			if err := checkBGPDaemonReady(ctx, thresholdTime); err != nil {
+				return fmt.Errorf("calico/node is not ready: BGP daemon is not ready: %+v", err)
+			}
+			return nil
+		})
+	}
 	if err := g.Wait(); err != nil {
 		fmt.Printf("%s\n", err)
 		os.Exit(1)
@@ -174,34 +201,67 @@ func checkService(serviceName string) error {
 // socket to gather all BGP peer connection status, and overall graceful
 // restart status.
 func checkBIRDReady(ipv string, thresholdTime time.Duration) error {
-	// Stat nodename file to get the modified time of the file.
-	nodenameFileStat, err := os.Stat("/var/lib/calico/nodename")
+	// Check for unestablished peers
+	peers, err := bird.GetPeers(ipv)
+	log.Debugf("peers: %v", peers)
 	if err != nil {
-		return fmt.Errorf("Failed to stat() nodename file: %v", err)
+		return err
 	}
 
-	// Check for unestablished peers
-	peers, err := bird.GetPeers(ipv)
+	established := map[string]bool{}
+	for _, peer := range peers {
+		established[peer.PeerIP] = peer.BGPState == "Established"
+	}
+	return checkPeersReady(established, thresholdTime, func() (bool, error) {
+		return bird.GRInProgress(ipv)
+	})
+}
+
+// checkBGPDaemonReady checks if the native BGP speaker is ready, in the same way as for
+// BIRD, using the BGP peer status from its gRPC API.
+func checkBGPDaemonReady(ctx context.Context, thresholdTime time.Duration) error {
+	peers, err := gobgp.GetPeers(ctx)
 	log.Debugf("peers: %v", peers)
 	if err != nil {
 		return err
 	}
 
+	established := map[string]bool{}
+	restarting := false
+	for _, peer := range peers {
+		established[peer.PeerIP] = peer.Established
+		restarting = restarting || peer.Restarting
+	}
+	return checkPeersReady(established, thresholdTime, func() (bool, error) {
+		return restarting, nil
+	})
+}
+
+// checkPeersReady checks whether enough BGP peerings are established, given whether
+// each peering is established.
+func checkPeersReady(established map[string]bool, thresholdTime time.Duration, grInProgress func() (bool, error)) error {
+	// Stat nodename file to get the modified time of the file.
+	nodenameFileStat, err := os.Stat("/var/lib/calico/nodename")
+	if err != nil {
+		return fmt.Errorf("Failed to stat() nodename file: %v", err)
+	}
+
 	s := []string{}
 
 	// numEstablishedPeer keeps count of number of peers with bgp state established.
 	numEstablishedPeer := 0
 
-	for _, peer := range peers {
-		if peer.BGPState == "Established" {
+	for peerIP, up := range established {
+		if up {
 			numEstablishedPeer += 1
 		} else {
-			s = append(s, peer.PeerIP)
+			s = append(s, peerIP)
 		}
 	}
+	sort.Strings(s)
 	log.Infof("Number of node(s) with BGP peering established = %v", numEstablishedPeer)
 
-	if len(peers) == 0 {
+	if len(established) == 0 {
 		// In case of no BGP peers return bird to be ready.
 		log.Debugf("There are no bgp peers, returning ready.")
 	} else if time.Since(nodenameFileStat.ModTime()) < thresholdTime {
@@ -211,7 +271,7 @@ func checkBIRDReady(ipv string, thresholdTime time.Duration) error {
 			return fmt.Errorf("BGP not established with %+v", strings.Join(s, ","))
 		}
 		// Check for GR
-		gr, err := bird.GRInProgress(ipv)
+		gr, err := grInProgress()
 		if err != nil {
 			return err
 		} else if gr {
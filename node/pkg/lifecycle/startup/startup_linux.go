@@ -32,9 +32,9 @@ func getOSType() string {
 // Checks that the filesystem is as expected and fix it if possible
 func ensureFilesystemAsExpected() {
 	// BIRD requires the /var/run/calico directory in order to provide status
-	// information over the control socket, but other backends do not
-	// need this check.
-	if strings.ToLower(os.Getenv("CALICO_NETWORKING_BACKEND")) == "bird" {
+	// information over the control socket, and the native BGP speaker writes its
+	// graceful shutdown status there, but other backends do not need this check.
+	if backend := strings.ToLower(os.Getenv("CALICO_NETWORKING_BACKEND")); backend == "bird" || backend == "gobgp" {
 		runDir := "/var/run/calico"
 		// Check if directory already exists
 		if _, err := os.Stat(runDir); err != nil {
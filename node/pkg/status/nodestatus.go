@@ -95,6 +95,7 @@ func GetPopulators() PopulatorRegistry {
 		populators[ipv][apiv3.NodeStatusClassTypeAgent] = populator.NewBirdInfo(ipv)
 		populators[ipv][apiv3.NodeStatusClassTypeBGP] = populator.NewBirdBGPPeers(ipv)
 		populators[ipv][apiv3.NodeStatusClassTypeRoutes] = populator.NewBirdRoutes(ipv)
+		populators[ipv][apiv3.NodeStatusClassTypeServices] = populator.NewServiceAdvertisements(ipv)
 	}
 
 	return populators
@@ -107,6 +108,7 @@ func Show() {
 			apiv3.NodeStatusClassTypeAgent,
 			apiv3.NodeStatusClassTypeBGP,
 			apiv3.NodeStatusClassTypeRoutes,
+			apiv3.NodeStatusClassTypeServices,
 		} {
 			if p, ok := GetPopulators()[ipv][class]; ok {
 				p.Show()
@@ -29,6 +29,8 @@ import (
 	"github.com/olekukonko/tablewriter"
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
 )
 
 // Check for Word_<IP> where every octate is separated by "_", regardless of IP protocols
@@ -342,11 +344,12 @@ func getBGPPeers(ipv IPFamily) ([]*bgpPeer, error) {
 
 // BirdBGPPeers implement populator interface.
 type BirdBGPPeers struct {
-	ipv IPFamily
+	ipv                  IPFamily
+	gracefulShutdownPath string
 }
 
 func NewBirdBGPPeers(ipv IPFamily) BirdBGPPeers {
-	return BirdBGPPeers{ipv: ipv}
+	return BirdBGPPeers{ipv: ipv, gracefulShutdownPath: gracefulshutdown.DefaultPath}
 }
 
 func (b BirdBGPPeers) Populate(status *apiv3.CalicoNodeStatus) error {
@@ -389,6 +392,17 @@ func (b BirdBGPPeers) Populate(status *apiv3.CalicoNodeStatus) error {
 		bgp.PeersV6, bgp.NumberEstablishedV6, bgp.NumberNotEstablishedV6 = convert(peers)
 	}
 
+	return b.populateGracefulShutdown(bgp)
+}
+
+// populateGracefulShutdown sets the progress of any BGP graceful shutdown written by confd.
+func (b BirdBGPPeers) populateGracefulShutdown(bgp *apiv3.CalicoNodeBGPStatus) error {
+	gs, err := gracefulshutdown.Read(b.gracefulShutdownPath)
+	if err != nil {
+		log.WithError(err).Errorf("failed to get BGP graceful shutdown status")
+		return err
+	}
+	bgp.GracefulShutdown = gs
 	return nil
 }
 
@@ -402,6 +416,12 @@ func (b BirdBGPPeers) Show() {
 
 	fmt.Printf("\nbird v%s BGP peers\n", b.ipv.String())
 	printPeers(peers, os.Stdout)
+
+	if gs, err := gracefulshutdown.Read(b.gracefulShutdownPath); err != nil {
+		fmt.Printf("Error getting BGP graceful shutdown status: %v\n", err)
+	} else if gs != nil {
+		fmt.Printf("BGP graceful shutdown: %s (teardown at %s)\n", gs.State, gs.TeardownTime.Format(time.RFC3339))
+	}
 }
 
 // printPeers prints out the slice of peers in table format.
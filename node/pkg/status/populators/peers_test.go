@@ -15,10 +15,17 @@
 package populator
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/gracefulshutdown"
 )
 
 var _ = Describe("Test BIRD BGP peer Scanner", func() {
@@ -259,3 +266,39 @@ BGP state:          Established
 		),
 	)
 })
+
+var _ = Describe("Test BGP graceful shutdown status", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "gracefulshutdown")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("should report the graceful shutdown written by confd", func() {
+		gs := &v3.CalicoNodeBGPGracefulShutdown{
+			State:        v3.BGPGracefulShutdownStateDraining,
+			TeardownTime: metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+		}
+		path := filepath.Join(dir, "gs.json")
+		Expect(gracefulshutdown.Write(path, gs, time.Date(2024, 5, 1, 11, 59, 0, 0, time.UTC))).To(Succeed())
+
+		bgp := &v3.CalicoNodeBGPStatus{}
+		Expect(BirdBGPPeers{ipv: IPFamilyV4, gracefulShutdownPath: path}.populateGracefulShutdown(bgp)).To(Succeed())
+		Expect(bgp.GracefulShutdown).NotTo(BeNil())
+		Expect(bgp.GracefulShutdown.State).To(Equal(v3.BGPGracefulShutdownStateDraining))
+		Expect(bgp.GracefulShutdown.TeardownTime.Equal(&gs.TeardownTime)).To(BeTrue())
+	})
+
+	It("should clear the status when no graceful shutdown is in progress", func() {
+		bgp := &v3.CalicoNodeBGPStatus{GracefulShutdown: &v3.CalicoNodeBGPGracefulShutdown{}}
+		p := BirdBGPPeers{ipv: IPFamilyV4, gracefulShutdownPath: filepath.Join(dir, "missing.json")}
+		Expect(p.populateGracefulShutdown(bgp)).To(Succeed())
+		Expect(bgp.GracefulShutdown).To(BeNil())
+	})
+})
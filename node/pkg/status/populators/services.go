@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package populator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
+)
+
+// getServiceAdvertisements reads the service advertisement status written by confd and
+// returns the entries for the given IP family.
+func getServiceAdvertisements(ipv IPFamily, path string) ([]apiv3.CalicoNodeServiceAdvertisement, error) {
+	all, err := servicestatus.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	advertisements := []apiv3.CalicoNodeServiceAdvertisement{}
+	for _, a := range all {
+		isV6 := strings.Contains(a.Destination, ":")
+		if isV6 == (ipv == IPFamilyV6) {
+			advertisements = append(advertisements, a)
+		}
+	}
+	return advertisements, nil
+}
+
+// ServiceAdvertisements implement populator interface.
+type ServiceAdvertisements struct {
+	ipv  IPFamily
+	path string
+}
+
+func NewServiceAdvertisements(ipv IPFamily) ServiceAdvertisements {
+	return ServiceAdvertisements{ipv: ipv, path: servicestatus.DefaultPath}
+}
+
+func (s ServiceAdvertisements) Populate(status *apiv3.CalicoNodeStatus) error {
+	advertisements, err := getServiceAdvertisements(s.ipv, s.path)
+	if err != nil {
+		// If the status has not been written, confd is not advertising services.
+		// Set empty status.
+		if os.IsNotExist(err) {
+			advertisements = nil
+		} else {
+			log.WithError(err).Errorf("failed to get service advertisements")
+			return err
+		}
+	}
+
+	if s.ipv == IPFamilyV4 {
+		status.Status.Services.ServicesV4 = advertisements
+	} else {
+		status.Status.Services.ServicesV6 = advertisements
+	}
+
+	return nil
+}
+
+// Show displays service advertisements written by confd.
+func (s ServiceAdvertisements) Show() {
+	advertisements, err := getServiceAdvertisements(s.ipv, s.path)
+	if err != nil {
+		fmt.Printf("Error getting service advertisements: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\nIPv%s service advertisements\n", s.ipv.String())
+	servicestatus.PrintTable(os.Stdout, advertisements)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package populator
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/servicestatus"
+)
+
+var _ = Describe("Test service advertisements populator", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "services")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("should split service advertisements by IP family", func() {
+		v4 := v3.CalicoNodeServiceAdvertisement{
+			Service:     "default/web",
+			Destination: "10.96.0.10/32",
+			Type:        v3.ServiceIPTypeClusterIP,
+			Advertised:  true,
+			Reason:      "externalTrafficPolicy=Local with local endpoints",
+		}
+		v6 := v3.CalicoNodeServiceAdvertisement{
+			Service:     "default/web",
+			Destination: "fd00:96::10/128",
+			Type:        v3.ServiceIPTypeClusterIP,
+			Advertised:  false,
+			Reason:      "externalTrafficPolicy=Local with no local endpoints",
+		}
+		path := filepath.Join(dir, "services.json")
+		Expect(servicestatus.Write(path, []v3.CalicoNodeServiceAdvertisement{v4, v6})).To(Succeed())
+
+		status := &v3.CalicoNodeStatus{}
+		Expect(ServiceAdvertisements{ipv: IPFamilyV4, path: path}.Populate(status)).To(Succeed())
+		Expect(ServiceAdvertisements{ipv: IPFamilyV6, path: path}.Populate(status)).To(Succeed())
+		Expect(status.Status.Services.ServicesV4).To(Equal([]v3.CalicoNodeServiceAdvertisement{v4}))
+		Expect(status.Status.Services.ServicesV6).To(Equal([]v3.CalicoNodeServiceAdvertisement{v6}))
+
+		// Check we can print service advertisements.
+		servicestatus.PrintTable(GinkgoWriter, []v3.CalicoNodeServiceAdvertisement{v4, v6})
+	})
+
+	It("should set empty status if confd has not written any", func() {
+		status := &v3.CalicoNodeStatus{}
+		status.Status.Services.ServicesV4 = []v3.CalicoNodeServiceAdvertisement{{Service: "stale/svc"}}
+		p := ServiceAdvertisements{ipv: IPFamilyV4, path: filepath.Join(dir, "missing.json")}
+		Expect(p.Populate(status)).To(Succeed())
+		Expect(status.Status.Services.ServicesV4).To(BeNil())
+	})
+})
@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// diff compares the snapshots from two Typhas, printing the differences.  It returns true if they differ.
+func diff(ctx context.Context, arguments docopt.Opts) (bool, error) {
+	addrs := []string{stringArg(arguments, "<server>"), stringArg(arguments, "<other-server>")}
+	timeout := durationArg(arguments, "--timeout")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var snapshots []*snapshotCollector
+	var clientsFinished sync.WaitGroup
+	for _, addr := range addrs {
+		c := newSnapshotCollector()
+		client := startClient(ctx, addr, arguments, c)
+		clientsFinished.Add(1)
+		go func() {
+			client.Finished.Wait()
+			clientsFinished.Done()
+		}()
+		snapshots = append(snapshots, c)
+	}
+	for i, c := range snapshots {
+		select {
+		case <-c.inSyncC:
+			log.WithField("server", addrs[i]).Info("Typha is in sync.")
+		case <-ctx.Done():
+			return false, fmt.Errorf("timed out waiting for %s to be in sync", addrs[i])
+		}
+	}
+	kvsA, kvsB := snapshots[0].snapshot(), snapshots[1].snapshot()
+	cancel()
+	clientsFinished.Wait()
+
+	var onlyInA, onlyInB, differ []string
+	for key, a := range kvsA {
+		b, ok := kvsB[key]
+		if !ok {
+			onlyInA = append(onlyInA, key)
+		} else if !bytes.Equal(a.Value, b.Value) {
+			differ = append(differ, key)
+		}
+	}
+	for key := range kvsB {
+		if _, ok := kvsA[key]; !ok {
+			onlyInB = append(onlyInB, key)
+		}
+	}
+	sort.Strings(onlyInA)
+	sort.Strings(onlyInB)
+	sort.Strings(differ)
+
+	for _, key := range onlyInA {
+		fmt.Printf("Only in %s: %s\n", addrs[0], key)
+	}
+	for _, key := range onlyInB {
+		fmt.Printf("Only in %s: %s\n", addrs[1], key)
+	}
+	showValues := boolArg(arguments, "--show-values")
+	for _, key := range differ {
+		fmt.Printf("Differs: %s\n", key)
+		if showValues {
+			fmt.Printf("  < %s\n", kvsA[key].Value)
+			fmt.Printf("  > %s\n", kvsB[key].Value)
+		}
+	}
+	fmt.Printf("%d keys only in %s, %d keys only in %s, %d keys differ, %d keys in common.\n",
+		len(onlyInA), addrs[0], len(onlyInB), addrs[1], len(differ), len(kvsA)-len(onlyInA))
+	return len(onlyInA)+len(onlyInB)+len(differ) > 0, nil
+}
+
+// snapshotCollector is a SyncerCallbacks that maintains the current state of the KVs, in serialized form
+// for ease of comparison.
+type snapshotCollector struct {
+	lock     sync.Mutex
+	kvs      map[string]syncproto.SerializedUpdate
+	inSync   bool
+	inSyncC  chan struct{}
+	numBadKV int
+}
+
+func newSnapshotCollector() *snapshotCollector {
+	return &snapshotCollector{
+		kvs:     map[string]syncproto.SerializedUpdate{},
+		inSyncC: make(chan struct{}),
+	}
+}
+
+func (c *snapshotCollector) OnStatusUpdated(status api.SyncStatus) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if status == api.InSync && !c.inSync {
+		c.inSync = true
+		close(c.inSyncC)
+	}
+}
+
+func (c *snapshotCollector) OnUpdates(updates []api.Update) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, u := range updates {
+		su, err := syncproto.SerializeUpdate(u)
+		if err != nil {
+			c.numBadKV++
+			continue
+		}
+		if su.Value == nil {
+			delete(c.kvs, su.Key)
+		} else {
+			c.kvs[su.Key] = su
+		}
+	}
+}
+
+// snapshot returns a copy of the current KVs.
+func (c *snapshotCollector) snapshot() map[string]syncproto.SerializedUpdate {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.numBadKV > 0 {
+		log.WithField("num", c.numBadKV).Warn("Ignored updates that couldn't be serialized.")
+	}
+	kvs := make(map[string]syncproto.SerializedUpdate, len(c.kvs))
+	for k, v := range c.kvs {
+		kvs[k] = v
+	}
+	return kvs
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/typha/pkg/recording"
+)
+
+// record records the stream from Typha to a file.
+func record(ctx context.Context, arguments docopt.Opts) error {
+	path := stringArg(arguments, "<file>")
+	addr := stringArg(arguments, "--server")
+	if d := durationArg(arguments, "--duration"); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	w, err := recording.Create(path, recording.Header{
+		SyncerType: syncerType(arguments),
+		Server:     addr,
+		StartTime:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r := &recorder{w: w, cancel: cancel}
+	client := startClient(ctx, addr, arguments, r)
+	log.WithField("file", path).Info("Recording; interrupt to stop.")
+	client.Finished.Wait()
+
+	err = errors.Join(r.err, w.Close())
+	log.WithFields(log.Fields{
+		"file":       path,
+		"numUpdates": r.numUpdates,
+	}).Info("Finished recording.")
+	return err
+}
+
+// recorder is a SyncerCallbacks that writes everything it receives to a recording.  Each event is flushed
+// to the file straight away so that the recording is useful even if the client is killed.
+type recorder struct {
+	w      *recording.Writer
+	cancel context.CancelFunc
+
+	numUpdates int
+	err        error
+}
+
+func (r *recorder) OnStatusUpdated(status api.SyncStatus) {
+	log.WithField("status", status).Info("Status received")
+	r.write(recording.StatusEvent(time.Now(), status), nil)
+}
+
+func (r *recorder) OnUpdates(updates []api.Update) {
+	if len(updates) == 0 {
+		return
+	}
+	r.numUpdates += len(updates)
+	r.write(recording.UpdatesEvent(time.Now(), updates))
+}
+
+func (r *recorder) write(e recording.Event, err error) {
+	if r.err != nil {
+		return
+	}
+	if err == nil {
+		err = r.w.Write(e)
+	}
+	if err == nil {
+		err = r.w.Flush()
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to write to recording, stopping.")
+		r.err = err
+		r.cancel()
+	}
+}
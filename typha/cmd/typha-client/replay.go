@@ -0,0 +1,108 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/typha/pkg/recording"
+	"github.com/projectcalico/calico/typha/pkg/snapcache"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+	"github.com/projectcalico/calico/typha/pkg/syncserver"
+)
+
+// replay replays a recording into a local server.  Events are replayed with their original timing, adjusted
+// by the --speed factor.
+func replay(ctx context.Context, arguments docopt.Opts) error {
+	path := stringArg(arguments, "<file>")
+	port, err := arguments.Int("--port")
+	if err != nil {
+		return err
+	}
+	speed, err := arguments.Float64("--speed")
+	if err != nil {
+		return err
+	}
+
+	r, err := recording.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	st := r.Header.SyncerType
+	log.WithFields(log.Fields{
+		"file":       path,
+		"syncerType": st,
+		"server":     r.Header.Server,
+		"recorded":   r.Header.StartTime,
+	}).Info("Loaded recording.")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cache := snapcache.New(snapcache.Config{Name: string(st)})
+	cache.Start(ctx)
+	server := syncserver.New(
+		map[syncproto.SyncerType]syncserver.BreadcrumbProvider{st: cache},
+		syncserver.Config{Port: port},
+	)
+	server.Start(ctx)
+	log.WithField("port", server.Port()).Info("Serving recording.")
+
+	lastEventTime := r.Header.StartTime
+	numEvents := 0
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if speed > 0 {
+			select {
+			case <-time.After(time.Duration(float64(e.Time.Sub(lastEventTime)) / speed)):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		lastEventTime = e.Time
+
+		status, isStatus, err := e.SyncStatus()
+		if err != nil {
+			return err
+		}
+		if isStatus {
+			log.WithField("status", status).Info("Replaying status update.")
+			cache.OnStatusUpdated(status)
+		} else {
+			updates, err := e.APIUpdates()
+			if err != nil {
+				return err
+			}
+			cache.OnUpdates(updates)
+		}
+		numEvents++
+	}
+	log.WithField("numEvents", numEvents).Info("Finished replaying recording; still serving.  Interrupt to stop.")
+	<-ctx.Done()
+	server.Finished.Wait()
+	return nil
+}
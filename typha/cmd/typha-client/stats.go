@@ -0,0 +1,267 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docopt/docopt-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/typha/pkg/recording"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// printStats prints statistics about the keys and updates, either from a recording or from a live Typha.
+func printStats(ctx context.Context, arguments docopt.Opts) error {
+	if path := stringArg(arguments, "<file>"); path != "" {
+		return printStatsFromRecording(path)
+	}
+
+	addr := stringArg(arguments, "--server")
+	if d := durationArg(arguments, "--duration"); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	s := newStatsCollector(time.Now())
+	cbs := &statsCallbacks{stats: s}
+	client := startClient(ctx, addr, arguments, cbs)
+	log.Info("Collecting stats; interrupt to stop.")
+
+	ticker := time.NewTicker(durationArg(arguments, "--interval"))
+	defer ticker.Stop()
+	lastNumUpdates, lastTickTime := 0, time.Now()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case now := <-ticker.C:
+			s.lock.Lock()
+			numKeys, numUpdates := s.numKeys(), s.numUpdates
+			s.lock.Unlock()
+			fmt.Printf("%s: %d keys, %d updates in the last %v (%.1f/s)\n",
+				now.Format(time.TimeOnly), numKeys, numUpdates-lastNumUpdates, now.Sub(lastTickTime).Round(time.Second),
+				float64(numUpdates-lastNumUpdates)/now.Sub(lastTickTime).Seconds())
+			lastNumUpdates, lastTickTime = numUpdates, now
+		}
+	}
+	client.Finished.Wait()
+	s.print(os.Stdout, fmt.Sprintf("%s (%s)", addr, syncerType(arguments)), time.Now())
+	return nil
+}
+
+func printStatsFromRecording(path string) error {
+	r, err := recording.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	s := newStatsCollector(r.Header.StartTime)
+	endTime := r.Header.StartTime
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		endTime = e.Time
+		status, isStatus, err := e.SyncStatus()
+		if err != nil {
+			return err
+		}
+		if isStatus {
+			s.onStatusUpdated(e.Time, status)
+			continue
+		}
+		updates := make([]syncproto.SerializedUpdate, 0, len(e.Updates))
+		for _, u := range e.Updates {
+			su, err := u.SerializedUpdate()
+			if err != nil {
+				return err
+			}
+			updates = append(updates, su)
+		}
+		s.onUpdates(updates)
+	}
+	s.print(os.Stdout, fmt.Sprintf("%s (%s, recorded from %s)", path, r.Header.SyncerType, r.Header.Server), endTime)
+	return nil
+}
+
+// statsCallbacks is a SyncerCallbacks that feeds the updates from a live Typha to a statsCollector.
+type statsCallbacks struct {
+	stats *statsCollector
+}
+
+func (c *statsCallbacks) OnStatusUpdated(status api.SyncStatus) {
+	log.WithField("status", status).Info("Status received")
+	c.stats.lock.Lock()
+	defer c.stats.lock.Unlock()
+	c.stats.onStatusUpdated(time.Now(), status)
+}
+
+func (c *statsCallbacks) OnUpdates(updates []api.Update) {
+	sus := make([]syncproto.SerializedUpdate, 0, len(updates))
+	for _, u := range updates {
+		su, err := syncproto.SerializeUpdate(u)
+		if err != nil {
+			continue
+		}
+		sus = append(sus, su)
+	}
+	c.stats.lock.Lock()
+	defer c.stats.lock.Unlock()
+	c.stats.onUpdates(sus)
+}
+
+// statsCollector tracks the number and size of the keys of each type and counts the updates to them after
+// the initial snapshot.  Callers must hold the lock when the collector is shared between goroutines.
+type statsCollector struct {
+	lock sync.Mutex
+
+	startTime  time.Time
+	inSyncTime time.Time
+	status     api.SyncStatus
+	numUpdates int
+
+	keyTypes  map[string]string
+	keySizes  map[string]int
+	typeStats map[string]*keyTypeStats
+}
+
+type keyTypeStats struct {
+	numKeys    int
+	numBytes   int
+	numUpdates int
+}
+
+func newStatsCollector(startTime time.Time) *statsCollector {
+	return &statsCollector{
+		startTime: startTime,
+		keyTypes:  map[string]string{},
+		keySizes:  map[string]int{},
+		typeStats: map[string]*keyTypeStats{},
+	}
+}
+
+func (s *statsCollector) onStatusUpdated(t time.Time, status api.SyncStatus) {
+	s.status = status
+	if status == api.InSync && s.inSyncTime.IsZero() {
+		s.inSyncTime = t
+	}
+}
+
+func (s *statsCollector) onUpdates(updates []syncproto.SerializedUpdate) {
+	for _, u := range updates {
+		kt, ok := s.keyTypes[u.Key]
+		if !ok {
+			kt = keyType(u.Key)
+		}
+		ts := s.typeStats[kt]
+		if ts == nil {
+			ts = &keyTypeStats{}
+			s.typeStats[kt] = ts
+		}
+		if !s.inSyncTime.IsZero() {
+			// Only count updates after the initial snapshot.
+			s.numUpdates++
+			ts.numUpdates++
+		}
+		if size, ok := s.keySizes[u.Key]; ok {
+			ts.numKeys--
+			ts.numBytes -= size
+			delete(s.keySizes, u.Key)
+			delete(s.keyTypes, u.Key)
+		}
+		if u.Value != nil {
+			ts.numKeys++
+			ts.numBytes += len(u.Value)
+			s.keySizes[u.Key] = len(u.Value)
+			s.keyTypes[u.Key] = kt
+		}
+	}
+}
+
+func (s *statsCollector) numKeys() int {
+	return len(s.keySizes)
+}
+
+func (s *statsCollector) print(out io.Writer, source string, endTime time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	fmt.Fprintf(out, "Source:      %s\n", source)
+	fmt.Fprintf(out, "Sync status: %s\n", s.status)
+	var updatesDuration time.Duration
+	if s.inSyncTime.IsZero() {
+		fmt.Fprintf(out, "Never reached in-sync; no update rates available.\n")
+	} else {
+		updatesDuration = endTime.Sub(s.inSyncTime)
+		fmt.Fprintf(out, "In sync after %v; counting updates over the following %v.\n",
+			s.inSyncTime.Sub(s.startTime).Round(time.Millisecond), updatesDuration.Round(time.Millisecond))
+	}
+	fmt.Fprintln(out)
+
+	rate := func(n int) string {
+		if updatesDuration <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f", float64(n)/updatesDuration.Seconds())
+	}
+	var types []string
+	for kt := range s.typeStats {
+		types = append(types, kt)
+	}
+	sort.Strings(types)
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY TYPE\tKEYS\tBYTES\tUPDATES\tUPDATES/S")
+	var total keyTypeStats
+	for _, kt := range types {
+		ts := s.typeStats[kt]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\n", kt, ts.numKeys, ts.numBytes, ts.numUpdates, rate(ts.numUpdates))
+		total.numKeys += ts.numKeys
+		total.numBytes += ts.numBytes
+		total.numUpdates += ts.numUpdates
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\t%d\t%s\n", total.numKeys, total.numBytes, total.numUpdates, rate(total.numUpdates))
+	_ = tw.Flush()
+}
+
+// keyType returns a short description of the type of the key, for example "WorkloadEndpointKey" or
+// "v3 IPPool".
+func keyType(key string) string {
+	switch k := model.KeyFromDefaultPath(key).(type) {
+	case nil:
+		return "unknown"
+	case model.ResourceKey:
+		return "v3 " + k.Kind
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", k), "model.")
+	}
+}
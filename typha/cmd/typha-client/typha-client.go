@@ -1,4 +1,4 @@
-// Copyright (c) 2017-2018 Tigera, Inc. All rights reserved.
+// Copyright (c) 2017-2024 Tigera, Inc. All rights reserved.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -17,7 +17,9 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/docopt/docopt-go"
@@ -32,21 +34,46 @@ import (
 	"github.com/projectcalico/calico/typha/pkg/syncproto"
 )
 
-const usage = `Test client for Typha, Calico's fan-out proxy.
+const usage = `Test and diagnostics client for Typha, Calico's fan-out proxy.
 
 Usage:
   typha-client [options]
+  typha-client record [options] <file>
+  typha-client replay [options] <file>
+  typha-client diff [options] <server> <other-server>
+  typha-client stats [options] [<file>]
+
+With no command, typha-client connects to Typha and logs the updates that it receives.
+
+Commands:
+  record  Record the stream of updates from Typha to a file, until interrupted or until
+          --duration has passed.
+  replay  Replay a recording into a local Typha-compatible server, for example to reproduce a
+          Felix bug by pointing Felix at it.  The server keeps serving the final state of the
+          recording until interrupted.  The server doesn't use TLS.
+  diff    Compare the snapshots from two Typhas once they are both in sync.  Exits with status 1
+          if they differ.
+  stats   Print the number of keys of each type, their size and the rate of updates to them,
+          either from Typha (until interrupted or until --duration has passed) or from a recording.
 
 Options:
   --version                    Print the version and exit.
   --server=<ADDR>              Set the server to connect to [default: localhost:5473].
-  --type=<TYPE>                Use a particular syncer type.
+  --type=<TYPE>                Use a particular syncer type [default: felix].
   --key-file=<FILE>            TLS: private key file.  Used to authenticate to the server.
   --cert-file=<FILE>           TLS: certificate file.  Used to authenticate to the server.
                                Must be signed by the CA that the server accepts.
   --ca-file=<FILE>             TLS: CA certificate file.  Used to authenticate the server's certificate.
   --server-cn=<NAME>           TLS: expected server common name.  Used to authenticate the server's certificate.
   --server-uri=<URI>           TLS: expected server URI SAN.  Used to authenticate the server's certificate.
+  --duration=<DURATION>        record, stats: stop after this long, for example "10m".
+  --interval=<DURATION>        stats: how often to print the recent update rate [default: 10s].
+  --timeout=<DURATION>         diff: how long to wait for both Typhas to be in sync [default: 60s].
+  --show-values                diff: print the values of keys that differ.
+  --port=<PORT>                replay: port to serve the recording on [default: 5473].
+  --speed=<FACTOR>             replay: replay faster (or slower) than real time by this factor; 0 means
+                               replay as fast as possible [default: 1].
+  --log-level=<LEVEL>          Log level for messages to stderr [default: info].
 
 `
 
@@ -69,13 +96,6 @@ func (s *syncerCallbacks) OnUpdates(updates []api.Update) {
 }
 
 func main() {
-	// Set up logging.
-	logutils.ConfigureEarlyLogging()
-	logutils.ConfigureLogging(&config.Config{
-		LogSeverityScreen:       "info",
-		DebugDisableLogDropping: true,
-	})
-
 	// Parse command-line args.
 	version := "Version:            " + buildinfo.GitVersion + "\n" +
 		"Full git commit ID: " + buildinfo.GitRevision + "\n" +
@@ -86,6 +106,14 @@ func main() {
 		println(usage)
 		log.Fatalf("Failed to parse usage, exiting: %v", err)
 	}
+
+	// Set up logging.
+	logutils.ConfigureEarlyLogging()
+	logutils.ConfigureLogging(&config.Config{
+		LogSeverityScreen:       stringArg(arguments, "--log-level"),
+		DebugDisableLogDropping: true,
+	})
+
 	buildInfoLogCxt := log.WithFields(log.Fields{
 		"version":    buildinfo.GitVersion,
 		"buildDate":  buildinfo.BuildDate,
@@ -95,28 +123,82 @@ func main() {
 	buildInfoLogCxt.Info("Typha client starting up")
 	log.Infof("Command line arguments: %v", arguments)
 
-	callbacks := &syncerCallbacks{}
-	addr := arguments["--server"].(string)
-	var syncerType syncproto.SyncerType
-	if t, ok := arguments["--type"].(string); ok {
-		syncerType = syncproto.SyncerType(t)
+	// Stop cleanly on Ctrl-C so that recordings are flushed and stats are printed.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	switch {
+	case boolArg(arguments, "record"):
+		err = record(ctx, arguments)
+	case boolArg(arguments, "replay"):
+		err = replay(ctx, arguments)
+	case boolArg(arguments, "diff"):
+		var differ bool
+		differ, err = diff(ctx, arguments)
+		if err == nil && differ {
+			os.Exit(1)
+		}
+	case boolArg(arguments, "stats"):
+		err = printStats(ctx, arguments)
+	default:
+		client := startClient(ctx, stringArg(arguments, "--server"), arguments, &syncerCallbacks{})
+		client.Finished.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Panic("Client failed")
 	}
+	if err != nil {
+		log.WithError(err).Fatal("Command failed")
+	}
+}
+
+// startClient starts a client that connects to the Typha at addr and sends the updates to the callbacks.
+// The client stops when the context is canceled.
+func startClient(ctx context.Context, addr string, arguments docopt.Opts, callbacks api.SyncerCallbacks) *syncclient.SyncerClient {
 	options := &syncclient.Options{
-		SyncerType:   syncerType,
-		KeyFile:      arguments["--key-file"].(string),
-		CertFile:     arguments["--cert-file"].(string),
-		CAFile:       arguments["--ca-file"].(string),
-		ServerCN:     arguments["--server-cn"].(string),
-		ServerURISAN: arguments["--server-uri"].(string),
+		SyncerType:   syncerType(arguments),
+		KeyFile:      stringArg(arguments, "--key-file"),
+		CertFile:     stringArg(arguments, "--cert-file"),
+		CAFile:       stringArg(arguments, "--ca-file"),
+		ServerCN:     stringArg(arguments, "--server-cn"),
+		ServerURISAN: stringArg(arguments, "--server-uri"),
 	}
 
 	hostname, _ := os.Hostname()
 	discoverer := discovery.New(discovery.WithAddrOverride(addr))
 	client := syncclient.New(discoverer, buildinfo.GitVersion, hostname, "typha command-line client", callbacks, options)
-	err = client.Start(context.Background())
+	err := client.Start(ctx)
+	if err != nil {
+		log.WithError(err).WithField("server", addr).Fatal("Failed to connect to Typha")
+	}
+	return client
+}
+
+func syncerType(arguments docopt.Opts) syncproto.SyncerType {
+	return syncproto.SyncerType(stringArg(arguments, "--type"))
+}
+
+// stringArg returns the value of the given argument, or "" if it wasn't specified.
+func stringArg(arguments docopt.Opts, name string) string {
+	s, _ := arguments.String(name)
+	return s
+}
+
+func boolArg(arguments docopt.Opts, name string) bool {
+	b, _ := arguments.Bool(name)
+	return b
+}
+
+// durationArg returns the value of the given duration argument, or 0 if it wasn't specified.
+func durationArg(arguments docopt.Opts, name string) time.Duration {
+	s := stringArg(arguments, name)
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
 	if err != nil {
-		log.WithError(err).Panic("Client failed")
+		log.WithError(err).Fatalf("Invalid value for %s", name)
 	}
-	client.Finished.Wait()
-	log.Panic("Client failed")
+	return d
 }
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fvtests_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/typha/pkg/syncclient"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// BenchmarkSnapshotCompression compares the compression algorithms by having a client connect and receive
+// a snapshot of simulated pods.  It reports the number of bytes sent to each client; the time includes
+// decompressing and decoding the snapshot at the client side.  Run with, for example:
+//
+//	go test -run XXX -bench SnapshotCompression ./typha/fv-tests
+func BenchmarkSnapshotCompression(b *testing.B) {
+	for _, alg := range []syncproto.CompressionAlgorithm{syncproto.CompressionSnappy, syncproto.CompressionZstd} {
+		b.Run(string(alg), func(b *testing.B) {
+			benchmarkSnapshotCompression(b, alg, 10000)
+		})
+	}
+}
+
+func benchmarkSnapshotCompression(b *testing.B, alg syncproto.CompressionAlgorithm, numPods int) {
+	RegisterTestingT(b)
+	logLevel := log.GetLevel()
+	log.SetLevel(log.WarnLevel)
+	defer log.SetLevel(logLevel)
+
+	h := NewHarness()
+	h.Config.CompressionAlgorithms = []syncproto.CompressionAlgorithm{alg}
+	h.Start()
+	defer h.Stop()
+	h.SendInitialSnapshotPods(numPods)
+	Eventually(func() api.SyncStatus {
+		return h.FelixCache.CurrentBreadcrumb().SyncStatus
+	}, 10*time.Second).Should(Equal(api.InSync))
+
+	var bytesSent uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cbs := &inSyncCallbacks{inSyncC: make(chan struct{})}
+		c := h.createClient(i, syncclient.Options{SyncerType: syncproto.SyncerTypeFelix}, cbs)
+		<-cbs.inSyncC
+		b.StopTimer()
+		Expect(cbs.numUpdates).To(Equal(numPods))
+		for _, info := range h.Server.Clients() {
+			if info.Compression != alg {
+				b.Fatalf("Client negotiated %q compression, expected %q", info.Compression, alg)
+			}
+			bytesSent += info.BytesSent
+		}
+		c.clientCancel()
+		c.client.Finished.Wait()
+		c.recorderCancel()
+		Eventually(h.Server.NumActiveConnections).Should(BeZero())
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(bytesSent)/float64(b.N), "bytes/client")
+}
+
+// inSyncCallbacks counts the updates that the client receives and closes inSyncC when the client is in sync.
+type inSyncCallbacks struct {
+	numUpdates int
+	inSyncC    chan struct{}
+}
+
+func (c *inSyncCallbacks) OnStatusUpdated(status api.SyncStatus) {
+	if status == api.InSync {
+		close(c.inSyncC)
+	}
+}
+
+func (c *inSyncCallbacks) OnUpdates(updates []api.Update) {
+	c.numUpdates += len(updates)
+}
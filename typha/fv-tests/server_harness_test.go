@@ -32,6 +32,7 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	calinet "github.com/projectcalico/calico/libcalico-go/lib/net"
 	. "github.com/projectcalico/calico/typha/fv-tests"
 	"github.com/projectcalico/calico/typha/pkg/calc"
 	"github.com/projectcalico/calico/typha/pkg/discovery"
@@ -170,6 +171,24 @@ func (h *ServerHarness) CreateClientNoDecodeRestart(id interface{}, syncType syn
 	return c
 }
 
+func (h *ServerHarness) CreateClientNoResume(id interface{}, syncType syncproto.SyncerType) *ClientState {
+	recorder := NewRecorder()
+	c := h.createClient(id, syncclient.Options{SyncerType: syncType, DisableResume: true}, recorder)
+	c.recorder = recorder
+	go recorder.Loop(c.recorderCtx)
+	h.ClientStates = append(h.ClientStates, c)
+	return c
+}
+
+func (h *ServerHarness) CreateNodeScopedClient(id interface{}, syncType syncproto.SyncerType) *ClientState {
+	recorder := NewRecorder()
+	c := h.createClient(id, syncclient.Options{SyncerType: syncType, ScopeToNode: true}, recorder)
+	c.recorder = recorder
+	go recorder.Loop(c.recorderCtx)
+	h.ClientStates = append(h.ClientStates, c)
+	return c
+}
+
 func (h *ServerHarness) CreateNoOpClientNoDecodeRestart(id interface{}, syncType syncproto.SyncerType) *ClientState {
 	c := h.createClient(id, syncclient.Options{SyncerType: syncType, DisableDecoderRestart: true, DebugDiscardKVUpdates: true}, NoOpCallbacks{})
 	h.NoOpClientStates = append(h.ClientStates, c)
@@ -236,6 +255,12 @@ func (h *ServerHarness) CreateClients(n int) {
 	}
 }
 
+func (h *ServerHarness) CreateClientsNoResume(n int) {
+	for i := 0; i < n; i++ {
+		h.CreateClientNoResume(i, syncproto.SyncerTypeFelix)
+	}
+}
+
 func (h *ServerHarness) SendStatus(s api.SyncStatus) {
 	h.Decoupler.OnStatusUpdated(s)
 }
@@ -272,6 +297,40 @@ func (h *ServerHarness) SendPodUpdates(numPods int) map[string]api.Update {
 	return expectedEndState
 }
 
+// SendWorkloadEndpointUpdates sends v1 WorkloadEndpoints of the given node, as used by the Felix syncer,
+// returning the expected state of clients that see them in full.
+func (h *ServerHarness) SendWorkloadEndpointUpdates(hostname string, n int) map[string]api.Update {
+	expectedEndState := map[string]api.Update{}
+	for i := 0; i < n; i++ {
+		update := api.Update{
+			KVPair: model.KVPair{
+				Key: model.WorkloadEndpointKey{
+					Hostname:       hostname,
+					OrchestratorID: "k8s",
+					WorkloadID:     fmt.Sprintf("default/pod-%d", h.updIdx),
+					EndpointID:     "eth0",
+				},
+				Value: &model.WorkloadEndpoint{
+					State:        "active",
+					Name:         fmt.Sprintf("cali%d", h.updIdx),
+					ProfileIDs:   []string{"kns.default"},
+					IPv4Nets:     []calinet.IPNet{calinet.MustParseCIDR(fmt.Sprintf("10.%d.%d.%d/32", h.updIdx>>16&0xff, h.updIdx>>8&0xff, h.updIdx&0xff))},
+					Labels:       map[string]string{"app": "web"},
+					GenerateName: "pod-",
+				},
+				Revision: fmt.Sprintf("%v", h.updIdx),
+			},
+			UpdateType: api.UpdateTypeKVNew,
+		}
+		h.updIdx++
+		path, err := model.KeyToDefaultPath(update.Key)
+		Expect(err).NotTo(HaveOccurred())
+		expectedEndState[path] = update
+		h.Decoupler.OnUpdates([]api.Update{update})
+	}
+	return expectedEndState
+}
+
 func (h *ServerHarness) SendInitialSnapshotConfigs(numConfigs int) map[string]api.Update {
 	expState := h.SendInitialSnapshotConfigsNoInSync(numConfigs)
 	h.SendStatus(api.InSync)
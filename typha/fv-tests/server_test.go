@@ -18,10 +18,13 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sync"
@@ -401,6 +404,199 @@ var _ = Describe("With an in-process Server", func() {
 			clientCancel()
 			expectGlobalGaugeValue("typha_connections_active", 0.0)
 		})
+
+		Describe("with the introspection API", func() {
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				server = httptest.NewServer(h.Server.IntrospectionHandler())
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			get := func(path string, result interface{}) func() error {
+				return func() error {
+					resp, err := http.Get(server.URL + path)
+					if err != nil {
+						return err
+					}
+					defer resp.Body.Close()
+					if resp.StatusCode != http.StatusOK {
+						return fmt.Errorf("unexpected status: %v", resp.Status)
+					}
+					return json.NewDecoder(resp.Body).Decode(result)
+				}
+			}
+
+			It("should list the client and how far behind it is", func() {
+				h.Decoupler.OnStatusUpdated(api.ResyncInProgress)
+				h.Decoupler.OnUpdates([]api.Update{configFoobarBazzBiff})
+				h.Decoupler.OnStatusUpdated(api.InSync)
+				Eventually(recorder.Status).Should(Equal(api.InSync))
+
+				var clients []syncserver.ClientInfo
+				latestSeqNo := func() uint64 {
+					Expect(get(syncserver.IntrospectionClientsPath, &clients)()).To(Succeed())
+					Expect(clients).To(HaveLen(1))
+					return clients[0].LatestBreadcrumbSeqNo
+				}
+				Eventually(latestSeqNo).Should(Equal(h.FelixCache.CurrentBreadcrumb().SequenceNumber))
+				Eventually(func() uint64 {
+					Expect(get(syncserver.IntrospectionClientsPath, &clients)()).To(Succeed())
+					return clients[0].BreadcrumbSeqNo
+				}).Should(Equal(clients[0].LatestBreadcrumbSeqNo))
+				c := clients[0]
+				Expect(c.Hostname).To(Equal("test-host-0"))
+				Expect(c.Version).To(Equal("test-version"))
+				Expect(c.SyncerType).To(Equal(syncproto.SyncerTypeFelix))
+				Expect(c.Compression).To(Equal(syncproto.CompressionSnappy))
+				Expect(c.SupportsDecoderRestart).To(BeTrue())
+				Expect(c.Streaming).To(BeTrue())
+				Expect(c.BytesSent).To(BeNumerically(">", 0))
+			})
+
+			It("should dump the snapshot cache filtered by prefix", func() {
+				h.Decoupler.OnStatusUpdated(api.ResyncInProgress)
+				h.Decoupler.OnUpdates([]api.Update{configFoobarBazzBiff, configFoobar2BazzBiff})
+				h.SendWorkloadEndpointUpdates("some-host", 2)
+				h.Decoupler.OnStatusUpdated(api.InSync)
+
+				var dump syncserver.CacheDump
+				Eventually(func() string {
+					Expect(get(syncserver.IntrospectionCachePath+"?prefix=/calico/v1/config/", &dump)()).To(Succeed())
+					return dump.SyncStatus
+				}).Should(Equal(api.InSync.String()))
+				Expect(dump.NumKVs).To(Equal(2))
+				Expect(dump.KVs).To(Equal([]syncserver.CachedKV{
+					{Key: "/calico/v1/config/foobar", Value: json.RawMessage(`"bazzbiff"`), Revision: "1234"},
+					{Key: "/calico/v1/config/foobar2", Value: json.RawMessage(`"bazzbiff"`), Revision: "1237"},
+				}))
+
+				Expect(get(syncserver.IntrospectionCachePath+"?prefix=/calico/v1/host/&limit=1", &dump)()).To(Succeed())
+				Expect(dump.NumKVs).To(Equal(2))
+				Expect(dump.KVs).To(HaveLen(1))
+
+				Expect(get(syncserver.IntrospectionCachePath+"?syncer=unknown", &dump)()).To(MatchError(ContainSubstring("404")))
+			})
+
+			It("should report the update rates", func() {
+				h.SendInitialSnapshotConfigs(100)
+				var rates []syncserver.SyncerUpdateRates
+				Eventually(func() float64 {
+					Expect(get(syncserver.IntrospectionUpdateRatesPath, &rates)()).To(Succeed())
+					Expect(rates).To(HaveLen(2))
+					return rates[1].UpdatesPerSec["10s"]
+				}, 5*time.Second).Should(BeNumerically("==", 10.0))
+				Expect(rates[1].SyncerType).To(Equal(syncproto.SyncerTypeFelix))
+				Expect(rates[1].NumClients).To(Equal(1))
+				Expect(rates[1].NumKVs).To(Equal(100))
+				Expect(rates[1].BreadcrumbsPerSec["10s"]).To(BeNumerically(">", 0))
+				Expect(rates[0].SyncerType).To(Equal(syncproto.SyncerTypeBGP))
+				Expect(rates[0].UpdatesPerSec["10s"]).To(BeZero())
+			})
+		})
+	})
+
+	Describe("with a client that has received a snapshot", func() {
+		var (
+			c         *ClientState
+			expState  map[string]api.Update
+			origValue float64
+		)
+
+		BeforeEach(func() {
+			var err error
+			origValue, err = getPerSyncerCounter(syncproto.SyncerTypeFelix, "typha_connections_resumed")
+			Expect(err).NotTo(HaveOccurred())
+
+			c = h.CreateClient("resumable", syncproto.SyncerTypeFelix)
+			expState = h.SendInitialSnapshotPods(100)
+			h.ExpectAllClientsToReachState(api.InSync, expState)
+		})
+
+		It("should resume its session after being disconnected", func() {
+			Expect(h.Server.TerminateRandomConnection(log.WithField("test", "resume"), "test")).To(BeTrue())
+			for k, v := range h.SendPodUpdates(10) {
+				expState[k] = v
+			}
+			h.ExpectAllClientsToReachState(api.InSync, expState)
+			Eventually(func() (float64, error) {
+				return getPerSyncerCounter(syncproto.SyncerTypeFelix, "typha_connections_resumed")
+			}).Should(Equal(origValue + 1))
+
+			// Only the deltas should have been sent again.
+			Expect(c.recorder.NumUpdates()).To(Equal(110))
+			finishedC := make(chan struct{})
+			go func() {
+				c.client.Finished.Wait()
+				close(finishedC)
+			}()
+			Consistently(finishedC, 100*time.Millisecond).ShouldNot(BeClosed())
+		})
+	})
+
+	Describe("with a node-scoped client and a full client", func() {
+		var scopedClient, fullClient *ClientState
+
+		BeforeEach(func() {
+			scopedClient = h.CreateNodeScopedClient("scoped", syncproto.SyncerTypeFelix)
+			fullClient = h.CreateClient("full", syncproto.SyncerTypeFelix)
+		})
+
+		// condense returns the expected state of the node-scoped client for the given remote updates.
+		condense := func(fullState map[string]api.Update) map[string]api.Update {
+			condensed := map[string]api.Update{}
+			for k, upd := range fullState {
+				upd.Value = syncproto.CondenseWorkloadEndpoint(upd.Value.(*model.WorkloadEndpoint))
+				condensed[k] = upd
+			}
+			return condensed
+		}
+
+		expectStates := func(scopedState, fullState map[string]api.Update) {
+			for _, c := range []struct {
+				*ClientState
+				state map[string]api.Update
+			}{{scopedClient, scopedState}, {fullClient, fullState}} {
+				Eventually(c.recorder.Status, 10*time.Second, 50*time.Millisecond).Should(Equal(api.InSync))
+				Eventually(c.recorder.KVCompareFn(c.state), 10*time.Second, 50*time.Millisecond).ShouldNot(HaveOccurred())
+			}
+		}
+
+		It("should send the client's own workload endpoints in full and condense the others", func() {
+			h.SendStatus(api.ResyncInProgress)
+			fullState := h.SendWorkloadEndpointUpdates("test-host-scoped", 3)
+			scopedState := map[string]api.Update{}
+			for k, v := range fullState {
+				scopedState[k] = v
+			}
+			remote := h.SendWorkloadEndpointUpdates("other-host", 3)
+			for k, v := range remote {
+				fullState[k] = v
+			}
+			for k, v := range condense(remote) {
+				scopedState[k] = v
+			}
+			h.SendStatus(api.InSync)
+			expectStates(scopedState, fullState)
+
+			// Same for the deltas.
+			local := h.SendWorkloadEndpointUpdates("test-host-scoped", 2)
+			remote = h.SendWorkloadEndpointUpdates("other-host", 2)
+			for k, v := range local {
+				fullState[k] = v
+				scopedState[k] = v
+			}
+			for k, v := range remote {
+				fullState[k] = v
+			}
+			for k, v := range condense(remote) {
+				scopedState[k] = v
+			}
+			expectStates(scopedState, fullState)
+		})
 	})
 
 	// Simulate an old client.
@@ -442,7 +638,8 @@ var _ = Describe("With an in-process Server", func() {
 	Describe("with 100 client connections", func() {
 		BeforeEach(func() {
 			log.SetLevel(log.InfoLevel) // Debug too verbose with 100 clients.
-			h.CreateClients(100)
+			// Clients that can't resume finish when they're dropped, which lets us count them.
+			h.CreateClientsNoResume(100)
 		})
 
 		It("should drop expected number of connections", func() {
@@ -531,6 +728,53 @@ var _ = Describe("With an in-process Server", func() {
 	})
 })
 
+var _ = Describe("With an in-process Server that prefers zstd compression", func() {
+	var h *ServerHarness
+
+	BeforeEach(func() {
+		log.SetLevel(log.InfoLevel)
+		h = NewHarness()
+		h.Config.CompressionAlgorithms = []syncproto.CompressionAlgorithm{
+			syncproto.CompressionZstd,
+			syncproto.CompressionSnappy,
+		}
+		h.Start()
+	})
+
+	AfterEach(func() {
+		h.Stop()
+	})
+
+	It("should use zstd for the clients that support it", func() {
+		h.CreateClients(2)
+		h.CreateNodeScopedClient("scoped", syncproto.SyncerTypeFelix)
+		h.CreateClientNoDecodeRestart("no decoder restart", syncproto.SyncerTypeFelix)
+
+		expState := h.SendInitialSnapshotConfigs(1000)
+		h.ExpectAllClientsToReachState(api.InSync, expState)
+		for k, v := range h.SendConfigUpdates(100) {
+			expState[k] = v
+		}
+		h.ExpectAllClientsToReachState(api.InSync, expState)
+
+		// A client that connects later gets the binary snapshot.
+		h.CreateClient("late", syncproto.SyncerTypeFelix)
+		h.ExpectAllClientsToReachState(api.InSync, expState)
+
+		compressionByHost := map[string]syncproto.CompressionAlgorithm{}
+		for _, c := range h.Server.Clients() {
+			compressionByHost[c.Hostname] = c.Compression
+		}
+		Expect(compressionByHost).To(Equal(map[string]syncproto.CompressionAlgorithm{
+			"test-host-0":                  syncproto.CompressionZstd,
+			"test-host-1":                  syncproto.CompressionZstd,
+			"test-host-scoped":             syncproto.CompressionZstd,
+			"test-host-late":               syncproto.CompressionZstd,
+			"test-host-no decoder restart": "",
+		}))
+	})
+})
+
 var _ = Describe("with no client connections", func() {
 	var h *ServerHarness
 
@@ -931,8 +1175,9 @@ var _ = Describe("With an in-process Server with long ping interval", func() {
 			"test-info",
 			recorder,
 			&syncclient.Options{
-				ReadTimeout:  1 * time.Second,
-				WriteTimeout: 10 * time.Second,
+				ReadTimeout:   1 * time.Second,
+				WriteTimeout:  10 * time.Second,
+				DisableResume: true,
 			},
 		)
 		err := client.Start(clientCxt)
@@ -46,6 +46,7 @@ type StateRecorder struct {
 	L             sync.Mutex
 	status        api.SyncStatus
 	kvs           map[string]api.Update
+	numUpdates    int
 	err           error
 	blockAfter    int
 	blockDuration time.Duration
@@ -82,6 +83,15 @@ func (r *StateRecorder) KVs() map[string]api.Update {
 	return kvsCpy
 }
 
+// NumUpdates returns the number of updates that we've received, including any that were
+// later overwritten.
+func (r *StateRecorder) NumUpdates() int {
+	r.L.Lock()
+	defer r.L.Unlock()
+
+	return r.numUpdates
+}
+
 // Len returns the number of KVs that we've recorded.
 func (r *StateRecorder) Len() int {
 	r.L.Lock()
@@ -106,6 +116,7 @@ func (r *StateRecorder) handleUpdates(updates []api.Update) {
 	defer r.L.Unlock()
 
 	for _, u := range updates {
+		r.numUpdates++
 		path, err := model.KeyToDefaultPath(u.Key)
 		if err != nil {
 			r.err = err
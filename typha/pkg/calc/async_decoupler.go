@@ -40,6 +40,30 @@ func (a *SyncerCallbacksDecoupler) OnUpdates(updates []api.Update) {
 	a.c <- updates
 }
 
+func (a *SyncerCallbacksDecoupler) OnResourceTypeUpdates(listRoot string, revision string, updates []api.Update) {
+	a.c <- resourceTypeUpdates{
+		listRoot: listRoot,
+		revision: revision,
+		updates:  updates,
+	}
+}
+
+type resourceTypeUpdates struct {
+	listRoot string
+	revision string
+	updates  []api.Update
+}
+
+// sendResourceTypeUpdates passes the updates to the sink's OnResourceTypeUpdates method, if it has one,
+// otherwise, it drops the resource type and revision and passes the updates to OnUpdates.
+func sendResourceTypeUpdates(sink api.SyncerCallbacks, listRoot string, revision string, updates []api.Update) {
+	if rtc, ok := sink.(api.SyncerResourceTypeCallbacks); ok {
+		rtc.OnResourceTypeUpdates(listRoot, revision, updates)
+	} else if len(updates) > 0 {
+		sink.OnUpdates(updates)
+	}
+}
+
 func (a *SyncerCallbacksDecoupler) SendTo(sink api.SyncerCallbacks) {
 	a.SendToContext(context.Background(), sink)
 }
@@ -53,6 +77,8 @@ func (a *SyncerCallbacksDecoupler) SendToContext(cxt context.Context, sink api.S
 				sink.OnStatusUpdated(obj)
 			case []api.Update:
 				sink.OnUpdates(obj)
+			case resourceTypeUpdates:
+				sendResourceTypeUpdates(sink, obj.listRoot, obj.revision, obj.updates)
 			}
 		case <-cxt.Done():
 			logrus.WithError(cxt.Err()).Info("Context asked us to stop")
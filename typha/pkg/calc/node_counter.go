@@ -47,6 +47,24 @@ func (c *NodeCounter) OnStatusUpdated(status api.SyncStatus) {
 }
 
 func (c *NodeCounter) OnUpdates(updates []api.Update) {
+	c.countNodes(updates)
+	c.sink.OnUpdates(updates)
+}
+
+func (c *NodeCounter) OnResourceTypeUpdates(listRoot string, revision string, updates []api.Update) {
+	c.countNodes(updates)
+	sendResourceTypeUpdates(c.sink, listRoot, revision, updates)
+}
+
+// AddNodes records nodes that the sink already knows about, for example, because its snapshot was
+// loaded from disk.
+func (c *NodeCounter) AddNodes(names []string) {
+	for _, name := range names {
+		c.setNode(name)
+	}
+}
+
+func (c *NodeCounter) countNodes(updates []api.Update) {
 	for _, update := range updates {
 		switch k := update.Key.(type) {
 		case model.ResourceKey:
@@ -61,7 +79,6 @@ func (c *NodeCounter) OnUpdates(updates []api.Update) {
 			}
 		}
 	}
-	c.sink.OnUpdates(updates)
 }
 
 func (c *NodeCounter) GetNumNodes() (int, error) {
@@ -42,6 +42,15 @@ func (v *ValidationFilter) OnStatusUpdated(status api.SyncStatus) {
 }
 
 func (v *ValidationFilter) OnUpdates(updates []api.Update) {
+	v.sink.OnUpdates(v.filter(updates))
+}
+
+func (v *ValidationFilter) OnResourceTypeUpdates(listRoot string, revision string, updates []api.Update) {
+	sendResourceTypeUpdates(v.sink, listRoot, revision, v.filter(updates))
+}
+
+// filter returns a copy of the updates with any invalid values replaced by nil.
+func (v *ValidationFilter) filter(updates []api.Update) []api.Update {
 	filteredUpdates := make([]api.Update, len(updates))
 	for i, update := range updates {
 		logCxt := logrus.WithFields(logrus.Fields{
@@ -96,5 +105,5 @@ func (v *ValidationFilter) OnUpdates(updates []api.Update) {
 		}
 		filteredUpdates[i] = update
 	}
-	v.sink.OnUpdates(filteredUpdates)
+	return filteredUpdates
 }
@@ -112,7 +112,13 @@ type Config struct {
 	PrometheusGoMetricsEnabled      bool   `config:"bool;true"`
 	PrometheusProcessMetricsEnabled bool   `config:"bool;true"`
 
-	SnapshotCacheMaxBatchSize int `config:"int(1,);100"`
+	SnapshotCacheMaxBatchSize     int `config:"int(1,);100"`
+	SnapshotCacheMaxResumeHistory int `config:"int(1,);1000"`
+	// SnapshotCachePersistenceDir, if set, is the directory that Typha saves its snapshot caches to, every
+	// SnapshotCachePersistenceIntervalSecs.  After a restart, Typha serves the saved snapshots (as not in
+	// sync) straight away and resumes watching the datastore from the saved revisions.
+	SnapshotCachePersistenceDir          string        `config:"file;;local"`
+	SnapshotCachePersistenceIntervalSecs time.Duration `config:"seconds;60"`
 
 	ServerMaxMessageSize                 int           `config:"int(1,);100"`
 	ServerMaxFallBehindSecs              time.Duration `config:"seconds;300"`
@@ -122,6 +128,13 @@ type Config struct {
 	ServerPongTimeoutSecs                time.Duration `config:"seconds;60"`
 	ServerHandshakeTimeoutSecs           time.Duration `config:"seconds;10"`
 	ServerPort                           int           `config:"port;0"`
+	// ServerPreferredCompression is the compression algorithm that Typha uses for clients that support it.
+	// Typha falls back to snappy for clients that don't.  zstd compresses better but uses more CPU.
+	ServerPreferredCompression string `config:"oneof(snappy,zstd);snappy"`
+	// ServerGRPCPort is the port for the optional gRPC API, which streams the syncers' data to clients other
+	// than Felix.  0 disables the gRPC API.  It uses the same TLS config as the main server and its streams
+	// count towards MaxConns.
+	ServerGRPCPort int `config:"int(0,65535);0"`
 
 	// Server-side TLS config for Typha's communication with Felix.  If any of these are
 	// specified, they _all_ must be - except that either ClientCN or ClientURISAN may be left
@@ -134,6 +147,19 @@ type Config struct {
 	ClientCN       string `config:"string;"`
 	ClientURISAN   string `config:"string;"`
 
+	// Upstream Typha config.  If UpstreamTyphaAddr or UpstreamTyphaK8sServiceName is set, this Typha gets its
+	// data from another ("root") Typha instead of from the datastore and re-serves it to its own clients.
+	UpstreamTyphaAddr           string `config:"authority;;local"`
+	UpstreamTyphaK8sServiceName string `config:"string;;local"`
+	UpstreamTyphaK8sNamespace   string `config:"string;kube-system;non-zero,local"`
+	// Client-side TLS config for the connection to the upstream Typha, with the same rules as Felix's
+	// TyphaKeyFile, TyphaCertFile, TyphaCAFile, TyphaCN and TyphaURISAN.
+	UpstreamTyphaKeyFile  string `config:"file(must-exist);;local"`
+	UpstreamTyphaCertFile string `config:"file(must-exist);;local"`
+	UpstreamTyphaCAFile   string `config:"file(must-exist);;local"`
+	UpstreamTyphaCN       string `config:"string;;local"`
+	UpstreamTyphaURISAN   string `config:"string;;local"`
+
 	DebugMemoryProfilePath  string `config:"file;;"`
 	DebugDisableLogDropping bool   `config:"bool;false"`
 
@@ -141,6 +167,9 @@ type Config struct {
 	DebugHost string `config:"host-address;localhost"`
 	// DebugPort is the port to bind the pprof debug server to or 0 to disable the debug port.
 	DebugPort int `config:"int(0,65535);"`
+	// DebugIntrospectionEnabled enables the introspection API (connected clients, snapshot cache contents and
+	// update rates) on the debug port.  Only used if DebugPort is non-zero.
+	DebugIntrospectionEnabled bool `config:"bool;false"`
 
 	ConnectionRebalancingMode             string        `config:"oneof(none,kubernetes);none"`
 	ConnectionDropIntervalSecs            time.Duration `config:"seconds;1"`
@@ -311,6 +340,12 @@ func (config *Config) requiringTLS() bool {
 	return config.ServerKeyFile+config.ServerCertFile+config.CAFile+config.ClientCN+config.ClientURISAN != ""
 }
 
+func (config *Config) requiringUpstreamTLS() bool {
+	// True if any of the upstream TLS parameters are set.
+	return config.UpstreamTyphaKeyFile+config.UpstreamTyphaCertFile+config.UpstreamTyphaCAFile+
+		config.UpstreamTyphaCN+config.UpstreamTyphaURISAN != ""
+}
+
 // Validate() performs cross-field validation.
 func (config *Config) Validate() (err error) {
 	if config.DatastoreType == "etcdv3" && len(config.EtcdEndpoints) == 0 {
@@ -335,9 +370,27 @@ func (config *Config) Validate() (err error) {
 				" - except that either ClientCN or ClientURISAN may be left unset.")
 		}
 	}
+
+	// Similarly for the connection to the upstream Typha.
+	if config.requiringUpstreamTLS() {
+		if config.UpstreamTyphaKeyFile == "" ||
+			config.UpstreamTyphaCertFile == "" ||
+			config.UpstreamTyphaCAFile == "" ||
+			(config.UpstreamTyphaCN == "" && config.UpstreamTyphaURISAN == "") {
+			err = errors.New("If any upstream Typha TLS config parameters are specified," +
+				" they _all_ must be" +
+				" - except that either UpstreamTyphaCN or UpstreamTyphaURISAN may be left unset.")
+		}
+	}
 	return
 }
 
+// UpstreamTyphaEnabled returns true if this Typha should get its data from another Typha instead of the
+// datastore.
+func (config *Config) UpstreamTyphaEnabled() bool {
+	return config.UpstreamTyphaAddr != "" || config.UpstreamTyphaK8sServiceName != ""
+}
+
 var knownParams map[string]param
 
 func loadParams() {
@@ -125,4 +125,15 @@ var _ = DescribeTable("Config validation",
 		"ClientCN":       "typha-peer",
 		"ClientURISAN":   "spiffe://k8s.example.com/typha-peer",
 	}, true),
+	Entry("just one upstream TLS setting", map[string]string{
+		"UpstreamTyphaAddr":    "10.0.0.1:5473",
+		"UpstreamTyphaKeyFile": "/usr",
+	}, false),
+	Entry("upstream TLS certs and key and CN", map[string]string{
+		"UpstreamTyphaAddr":     "10.0.0.1:5473",
+		"UpstreamTyphaKeyFile":  "/usr",
+		"UpstreamTyphaCertFile": "/usr",
+		"UpstreamTyphaCAFile":   "/usr",
+		"UpstreamTyphaCN":       "typha-server",
+	}, true),
 )
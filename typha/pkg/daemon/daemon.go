@@ -16,8 +16,11 @@ package daemon
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
@@ -31,7 +34,9 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	v3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/bgpsyncer"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/felixsyncer"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/nodestatussyncer"
@@ -45,12 +50,16 @@ import (
 	"github.com/projectcalico/calico/typha/pkg/buildinfo"
 	"github.com/projectcalico/calico/typha/pkg/calc"
 	"github.com/projectcalico/calico/typha/pkg/config"
+	"github.com/projectcalico/calico/typha/pkg/discovery"
 	"github.com/projectcalico/calico/typha/pkg/jitter"
 	"github.com/projectcalico/calico/typha/pkg/k8s"
 	"github.com/projectcalico/calico/typha/pkg/logutils"
 	"github.com/projectcalico/calico/typha/pkg/snapcache"
+	"github.com/projectcalico/calico/typha/pkg/syncclient"
+	"github.com/projectcalico/calico/typha/pkg/syncgrpc"
 	"github.com/projectcalico/calico/typha/pkg/syncproto"
 	"github.com/projectcalico/calico/typha/pkg/syncserver"
+	"github.com/projectcalico/calico/typha/pkg/upstream"
 )
 
 const usage = `Typha, Calico's fan-out proxy.
@@ -76,6 +85,8 @@ type TyphaDaemon struct {
 	SyncerPipelines    []*syncerPipeline
 	CachesBySyncerType map[syncproto.SyncerType]syncserver.BreadcrumbProvider
 	Server             *syncserver.Server
+	// GRPCServer is the optional gRPC API server; nil if it's disabled.
+	GRPCServer *syncgrpc.Server
 
 	// The functions below default to real library functions but they can be overridden for testing.
 	NewClientV3           func(config apiconfig.CalicoAPIConfig) (DatastoreClient, error)
@@ -228,6 +239,11 @@ configRetry:
 			continue configRetry
 		}
 
+		if configParams.UpstreamTyphaEnabled() {
+			// We get our data from another Typha, no need for the datastore.
+			break configRetry
+		}
+
 		// We should now have enough config to connect to the datastore.
 		datastoreConfig = configParams.DatastoreConfig()
 		t.DatastoreClient, err = t.NewClientV3(datastoreConfig)
@@ -247,6 +263,15 @@ configRetry:
 	t.BuildInfoLogCxt.WithField("config", configParams).Info(
 		"Successfully loaded configuration.")
 
+	if configParams.UpstreamTyphaEnabled() {
+		log.WithFields(log.Fields{
+			"addr":    configParams.UpstreamTyphaAddr,
+			"service": configParams.UpstreamTyphaK8sServiceName,
+		}).Info("Upstream Typha configured, skipping datastore initialization.")
+		t.ConfigParams = configParams
+		return nil
+	}
+
 	if datastoreConfig.Spec.DatastoreType == apiconfig.Kubernetes {
 		// Special case: for KDD v1 datamodel to v3 datamodel upgrade, we need to ensure that the datastore migration
 		// has completed before we start serving requests.  Otherwise, we might serve partially-migrated data to
@@ -330,9 +355,27 @@ func (t *TyphaDaemon) addSyncerPipeline(
 	syncerType syncproto.SyncerType,
 	newSyncer func(callbacks bapi.SyncerCallbacks) bapi.Syncer,
 ) {
+	// Create our snapshot cache, which stores point-in-time copies of the datastore contents.  If
+	// persistence is enabled, this loads the snapshot that we saved before we restarted.
+	cacheConfig := snapcache.Config{
+		MaxBatchSize:     t.ConfigParams.SnapshotCacheMaxBatchSize,
+		MaxResumeHistory: t.ConfigParams.SnapshotCacheMaxResumeHistory,
+		HealthAggregator: t.healthAggregator,
+		Name:             string(syncerType),
+	}
+	if dir := t.ConfigParams.SnapshotCachePersistenceDir; dir != "" {
+		cacheConfig.PersistencePath = filepath.Join(dir, string(syncerType)+".snapshot")
+		cacheConfig.PersistenceInterval = t.ConfigParams.SnapshotCachePersistenceIntervalSecs
+	}
+	cache := snapcache.New(cacheConfig)
+
 	// Get a Syncer from the datastore, which will feed the validator layer with updates.
 	syncerToValidator := calc.NewSyncerCallbacksDecoupler()
-	syncer := newSyncer(syncerToValidator)
+	var syncerCallbacks bapi.SyncerCallbacks = syncerToValidator
+	if cacheConfig.PersistencePath != "" {
+		syncerCallbacks = resumingCallbacks{SyncerCallbacksDecoupler: syncerToValidator, cache: cache}
+	}
+	syncer := newSyncer(syncerCallbacks)
 	log.Debugf("Created Syncer: %#v", syncer)
 
 	toCache := calc.NewSyncerCallbacksDecoupler()
@@ -342,19 +385,13 @@ func (t *TyphaDaemon) addSyncerPipeline(
 		// the number of nodes in the cluster. We only want to count nodes once, which is why we only do this
 		// for the felix syncer and not the BGP syncer as well.
 		t.nodeCounter = calc.NewNodeCounter(toCache)
+		t.nodeCounter.AddNodes(nodeNames(cache.CurrentBreadcrumb()))
 		validator = calc.NewValidationFilter(t.nodeCounter)
 	} else {
 		// Otherwise, just go from validator to cache directly.
 		validator = calc.NewValidationFilter(toCache)
 	}
 
-	// Create our snapshot cache, which stores point-in-time copies of the datastore contents.
-	cache := snapcache.New(snapcache.Config{
-		MaxBatchSize:     t.ConfigParams.SnapshotCacheMaxBatchSize,
-		HealthAggregator: t.healthAggregator,
-		Name:             string(syncerType),
-	})
-
 	pipeline := &syncerPipeline{
 		Type:              syncerType,
 		Syncer:            syncer,
@@ -367,16 +404,84 @@ func (t *TyphaDaemon) addSyncerPipeline(
 	t.CachesBySyncerType[syncerType] = cache
 }
 
+// resumingCallbacks are the callbacks of a Syncer whose snapshot cache is persisted.  They ask the Syncer to
+// resume from the snapshot that the cache loaded, if any.
+type resumingCallbacks struct {
+	*calc.SyncerCallbacksDecoupler
+	cache *snapcache.Cache
+}
+
+func (r resumingCallbacks) ResumeState() map[string]bapi.ResourceTypeState {
+	return r.cache.ResumeState()
+}
+
+// nodeNames returns the names of the v3 Nodes in the given Breadcrumb.
+func nodeNames(crumb *snapcache.Breadcrumb) []string {
+	prefix := model.ListOptionsToDefaultPathRoot(model.ResourceListOptions{Kind: v3.KindNode}) + "/"
+	var names []string
+	crumb.KVs.AscendGreaterOrEqual(syncproto.SerializedUpdate{Key: prefix}, func(su syncproto.SerializedUpdate) bool {
+		if !strings.HasPrefix(su.Key, prefix) {
+			return false
+		}
+		if key, ok := model.KeyFromDefaultPath(su.Key).(model.ResourceKey); ok {
+			names = append(names, key.Name)
+		}
+		return true
+	})
+	return names
+}
+
+// upstreamSyncerFactory returns a function that creates a Syncer of the given type that streams from the
+// upstream Typha.
+func (t *TyphaDaemon) upstreamSyncerFactory(syncerType syncproto.SyncerType) func(callbacks bapi.SyncerCallbacks) bapi.Syncer {
+	return func(callbacks bapi.SyncerCallbacks) bapi.Syncer {
+		opts := []discovery.Option{discovery.WithAddrOverride(t.ConfigParams.UpstreamTyphaAddr)}
+		if t.ConfigParams.UpstreamTyphaK8sServiceName != "" {
+			opts = append(opts,
+				discovery.WithInClusterKubeClient(),
+				discovery.WithKubeService(t.ConfigParams.UpstreamTyphaK8sNamespace, t.ConfigParams.UpstreamTyphaK8sServiceName),
+			)
+		}
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.WithError(err).Warn("Failed to get hostname, upstream Typha will not know who we are.")
+		}
+		return upstream.New(
+			discovery.New(opts...),
+			callbacks,
+			upstream.Config{
+				Version:  buildinfo.GitVersion,
+				Hostname: hostname,
+				Info:     fmt.Sprintf("Typha; Revision: %s; Build date: %s", buildinfo.GitRevision, buildinfo.BuildDate),
+				ClientOptions: syncclient.Options{
+					SyncerType:   syncerType,
+					KeyFile:      t.ConfigParams.UpstreamTyphaKeyFile,
+					CertFile:     t.ConfigParams.UpstreamTyphaCertFile,
+					CAFile:       t.ConfigParams.UpstreamTyphaCAFile,
+					ServerCN:     t.ConfigParams.UpstreamTyphaCN,
+					ServerURISAN: t.ConfigParams.UpstreamTyphaURISAN,
+				},
+			},
+		)
+	}
+}
+
 // CreateServer creates and configures (but does not start) the server components.
 func (t *TyphaDaemon) CreateServer() {
 	// Health monitoring, for liveness and readiness endpoints.
 	t.healthAggregator = health.NewHealthAggregator()
 
 	// Now create the Syncer and caching layer (one pipeline for each syncer we support).
-	t.addSyncerPipeline(syncproto.SyncerTypeFelix, t.DatastoreClient.FelixSyncerByIface)
-	t.addSyncerPipeline(syncproto.SyncerTypeBGP, t.DatastoreClient.BGPSyncerByIface)
-	t.addSyncerPipeline(syncproto.SyncerTypeTunnelIPAllocation, t.DatastoreClient.TunnelIPAllocationSyncerByIface)
-	t.addSyncerPipeline(syncproto.SyncerTypeNodeStatus, t.DatastoreClient.NodeStatusSyncerByIface)
+	if t.ConfigParams.UpstreamTyphaEnabled() {
+		for _, st := range syncproto.AllSyncerTypes {
+			t.addSyncerPipeline(st, t.upstreamSyncerFactory(st))
+		}
+	} else {
+		t.addSyncerPipeline(syncproto.SyncerTypeFelix, t.DatastoreClient.FelixSyncerByIface)
+		t.addSyncerPipeline(syncproto.SyncerTypeBGP, t.DatastoreClient.BGPSyncerByIface)
+		t.addSyncerPipeline(syncproto.SyncerTypeTunnelIPAllocation, t.DatastoreClient.TunnelIPAllocationSyncerByIface)
+		t.addSyncerPipeline(syncproto.SyncerTypeNodeStatus, t.DatastoreClient.NodeStatusSyncerByIface)
+	}
 
 	// Create the server, which listens for connections from Felix.
 	t.Server = syncserver.New(
@@ -400,8 +505,36 @@ func (t *TyphaDaemon) CreateServer() {
 			CAFile:                         t.ConfigParams.CAFile,
 			ClientCN:                       t.ConfigParams.ClientCN,
 			ClientURISAN:                   t.ConfigParams.ClientURISAN,
+			CompressionAlgorithms:          compressionAlgorithms(t.ConfigParams.ServerPreferredCompression),
 		},
 	)
+
+	if t.ConfigParams.ServerGRPCPort != 0 {
+		t.GRPCServer = syncgrpc.New(
+			t.CachesBySyncerType,
+			t.Server,
+			syncgrpc.Config{
+				Port:           t.ConfigParams.ServerGRPCPort,
+				MaxMessageSize: t.ConfigParams.ServerMaxMessageSize,
+				MaxFallBehind:  t.ConfigParams.ServerMaxFallBehindSecs,
+				KeyFile:        t.ConfigParams.ServerKeyFile,
+				CertFile:       t.ConfigParams.ServerCertFile,
+				CAFile:         t.ConfigParams.CAFile,
+				ClientCN:       t.ConfigParams.ClientCN,
+				ClientURISAN:   t.ConfigParams.ClientURISAN,
+			},
+		)
+	}
+}
+
+// compressionAlgorithms returns the compression algorithms for the server to use, in order of preference.
+// Snappy is always included as a fallback since all clients that support compression support it.
+func compressionAlgorithms(preferred string) []syncproto.CompressionAlgorithm {
+	algs := []syncproto.CompressionAlgorithm{syncproto.CompressionSnappy}
+	if preferred != "" && syncproto.CompressionAlgorithm(preferred) != syncproto.CompressionSnappy {
+		algs = append([]syncproto.CompressionAlgorithm{syncproto.CompressionAlgorithm(preferred)}, algs...)
+	}
+	return algs
 }
 
 // Start starts all the server components in background goroutines.
@@ -412,6 +545,10 @@ func (t *TyphaDaemon) Start(cxt context.Context) {
 		s.Start(cxt)
 	}
 	t.Server.Start(cxt)
+	if t.GRPCServer != nil {
+		log.WithField("port", t.ConfigParams.ServerGRPCPort).Info("Starting gRPC server.")
+		t.GRPCServer.Start(cxt)
+	}
 	if t.ConfigParams.ConnectionRebalancingMode == "kubernetes" {
 		log.Info("Kubernetes connection rebalancing is enabled, starting k8s poll goroutine.")
 		k8sAPI := k8s.NewK8sAPI(t.nodeCounter)
@@ -423,6 +560,10 @@ func (t *TyphaDaemon) Start(cxt context.Context) {
 	log.Info("Started the datastore Syncer/cache layer/server.")
 
 	if t.ConfigParams.DebugPort != 0 {
+		if t.ConfigParams.DebugIntrospectionEnabled {
+			log.Info("Introspection API enabled on the debug port.")
+			http.Handle("/typha/", t.Server.IntrospectionHandler())
+		}
 		debugserver.StartDebugPprofServer(t.ConfigParams.DebugHost, t.ConfigParams.DebugPort)
 	}
 	if t.ConfigParams.PrometheusMetricsEnabled {
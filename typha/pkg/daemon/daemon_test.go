@@ -28,6 +28,7 @@ import (
 
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
 	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
 	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
 	fvtests "github.com/projectcalico/calico/typha/fv-tests"
@@ -37,6 +38,7 @@ import (
 	"github.com/projectcalico/calico/typha/pkg/syncclient"
 	"github.com/projectcalico/calico/typha/pkg/syncproto"
 	"github.com/projectcalico/calico/typha/pkg/syncserver"
+	"github.com/projectcalico/calico/typha/pkg/upstream"
 )
 
 var configContents = []byte(`[default]
@@ -188,6 +190,108 @@ var _ = Describe("Daemon", func() {
 				// It should make it all the way through to our recorder.
 				Eventually(cbs.Status).Should(Equal(bapi.InSync))
 			})
+
+			It("should serve the data of an upstream Typha", func() {
+				d.ConfigParams.ServerPort = syncserver.PortRandom
+				d.CreateServer()
+				cxt, cancelFn := context.WithCancel(context.Background())
+				defer cancelFn()
+				d.Start(cxt)
+
+				// Create a leaf Typha that gets its data from the first one.
+				leafConfig := *d.ConfigParams
+				leafConfig.UpstreamTyphaAddr = fmt.Sprintf("127.0.0.1:%d", d.Server.Port())
+				leaf := New()
+				leaf.ConfigParams = &leafConfig
+				leaf.CreateServer()
+				Expect(leaf.SyncerPipelines[0].Syncer).To(BeAssignableToTypeOf(&upstream.Syncer{}))
+				leaf.Start(cxt)
+				defer func() {
+					for _, p := range leaf.SyncerPipelines {
+						p.Syncer.Stop()
+					}
+				}()
+
+				cbs := fvtests.NewRecorder()
+				client := syncclient.New(
+					discovery.New(discovery.WithAddrOverride(fmt.Sprintf("127.0.0.1:%d", leaf.Server.Port()))),
+					"",
+					"",
+					"",
+					cbs,
+					nil,
+				)
+				clientCxt, clientCancelFn := context.WithCancel(context.Background())
+				recorderCtx, recorderCancelFn := context.WithCancel(context.Background())
+				defer func() {
+					clientCancelFn()
+					client.Finished.Wait()
+					recorderCancelFn()
+				}()
+				err := client.Start(clientCxt)
+				go cbs.Loop(recorderCtx)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Updates and the sync status should propagate through both Typhas.
+				update := bapi.Update{
+					KVPair: model.KVPair{
+						Key:      model.GlobalConfigKey{Name: "foo"},
+						Value:    "bar",
+						Revision: "1",
+					},
+					UpdateType: bapi.UpdateTypeKVNew,
+				}
+				d.SyncerPipelines[0].SyncerToValidator.OnUpdates([]bapi.Update{update})
+				d.SyncerPipelines[0].SyncerToValidator.OnStatusUpdated(bapi.InSync)
+				Eventually(cbs.Status).Should(Equal(bapi.InSync))
+				Eventually(cbs.KVCompareFn(map[string]bapi.Update{"/calico/v1/config/foo": update})).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Describe("with upstream Typha configured", func() {
+			BeforeEach(func() {
+				Expect(os.Setenv("TYPHA_UPSTREAMTYPHAADDR", "127.0.0.1:5473")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("TYPHA_UPSTREAMTYPHAADDR")).To(Succeed())
+			})
+
+			It("should create upstream syncers without using the datastore", func() {
+				Expect(d.LoadConfiguration(cxt)).To(Succeed())
+				Expect(datastore.getNumInitCalls()).To(Equal(0))
+				d.CreateServer()
+				Expect(d.CachesBySyncerType).To(HaveLen(syncproto.NumSyncerTypes))
+				for _, p := range d.SyncerPipelines {
+					Expect(p.Syncer).To(BeAssignableToTypeOf(&upstream.Syncer{}))
+				}
+				Expect(datastore.felixSyncerCalled).To(BeFalse())
+			})
+		})
+
+		Describe("with snapshot persistence configured", func() {
+			var dir string
+
+			BeforeEach(func() {
+				var err error
+				dir, err = os.MkdirTemp("", "typha-snapshots")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.Setenv("TYPHA_SNAPSHOTCACHEPERSISTENCEDIR", dir)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("TYPHA_SNAPSHOTCACHEPERSISTENCEDIR")).To(Succeed())
+				Expect(os.RemoveAll(dir)).To(Succeed())
+			})
+
+			It("should ask the syncers to resume from the saved snapshot", func() {
+				Expect(d.LoadConfiguration(cxt)).To(Succeed())
+				d.CreateServer()
+				rc, ok := datastore.felixSyncerCallbacks.(bapi.SyncerResumeCallbacks)
+				Expect(ok).To(BeTrue())
+				// Nothing saved yet.
+				Expect(rc.ResumeState()).To(BeEmpty())
+			})
 		})
 
 		downSecsStr := strconv.Itoa(downSecs)
@@ -244,6 +348,7 @@ type mockDatastore struct {
 	allocateTunnelIpSyncerCalled bool
 	bgpSyncerCalled              bool
 	felixSyncerCalled            bool
+	felixSyncerCallbacks         bapi.SyncerCallbacks
 	nodestatusSyncerCalled       bool
 	initCalled                   int
 	failInit                     bool
@@ -253,6 +358,7 @@ func (b *mockDatastore) FelixSyncerByIface(callbacks bapi.SyncerCallbacks) bapi.
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 	b.felixSyncerCalled = true
+	b.felixSyncerCallbacks = callbacks
 	return &dummySyncer{}
 }
 
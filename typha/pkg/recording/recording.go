@@ -0,0 +1,272 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recording reads and writes recordings of the stream of updates that a client receives from Typha.
+// A recording can be inspected by hand, summarised or replayed into a local server to reproduce a problem.
+//
+// The format is JSON lines: a Header followed by one Event per line.  Keys and values are in the same form
+// as in the datastore (and on the wire to Typha's clients), so recordings don't depend on the Go types.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// FormatVersion is the version of the recording format.  Readers reject recordings with a different version.
+const FormatVersion = 1
+
+type Header struct {
+	Version    int                  `json:"version"`
+	SyncerType syncproto.SyncerType `json:"syncerType"`
+	// Server is the address of the Typha that the recording was taken from.
+	Server    string    `json:"server,omitempty"`
+	StartTime time.Time `json:"startTime"`
+}
+
+// Event is a single callback from the syncer: either a status update or a batch of updates.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status,omitempty"`
+	Updates []Update  `json:"updates,omitempty"`
+}
+
+type Update struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	// Value is the serialized value, nil for a deletion.
+	Value           *string       `json:"value,omitempty"`
+	Revision        string        `json:"revision,omitempty"`
+	ResourceVersion string        `json:"resourceVersion,omitempty"`
+	TTL             time.Duration `json:"ttl,omitempty"`
+}
+
+var (
+	statusToString = map[api.SyncStatus]string{
+		api.WaitForDatastore: api.WaitForDatastore.String(),
+		api.ResyncInProgress: api.ResyncInProgress.String(),
+		api.InSync:           api.InSync.String(),
+	}
+	updateTypeToString = map[api.UpdateType]string{
+		api.UpdateTypeKVUnknown: "unknown",
+		api.UpdateTypeKVNew:     "new",
+		api.UpdateTypeKVUpdated: "updated",
+		api.UpdateTypeKVDeleted: "deleted",
+	}
+	stringToStatus     = map[string]api.SyncStatus{}
+	stringToUpdateType = map[string]api.UpdateType{}
+)
+
+func init() {
+	for s, str := range statusToString {
+		stringToStatus[str] = s
+	}
+	for t, str := range updateTypeToString {
+		stringToUpdateType[str] = t
+	}
+}
+
+// StatusEvent returns an Event for a status update.
+func StatusEvent(t time.Time, status api.SyncStatus) Event {
+	return Event{Time: t, Status: statusToString[status]}
+}
+
+// UpdatesEvent returns an Event for a batch of updates.
+func UpdatesEvent(t time.Time, updates []api.Update) (Event, error) {
+	e := Event{Time: t, Updates: make([]Update, 0, len(updates))}
+	for _, u := range updates {
+		su, err := syncproto.SerializeUpdate(u)
+		if err != nil {
+			return Event{}, err
+		}
+		e.Updates = append(e.Updates, FromSerializedUpdate(su))
+	}
+	return e, nil
+}
+
+// SyncStatus returns the status carried by the event, if it is a status update.
+func (e Event) SyncStatus() (api.SyncStatus, bool, error) {
+	if e.Status == "" {
+		return 0, false, nil
+	}
+	s, ok := stringToStatus[e.Status]
+	if !ok {
+		return 0, false, fmt.Errorf("unknown sync status %q", e.Status)
+	}
+	return s, true, nil
+}
+
+// APIUpdates parses the updates carried by the event.
+func (e Event) APIUpdates() ([]api.Update, error) {
+	updates := make([]api.Update, 0, len(e.Updates))
+	for _, u := range e.Updates {
+		su, err := u.SerializedUpdate()
+		if err != nil {
+			return nil, err
+		}
+		upd, err := su.ToUpdate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse update for key %q: %w", u.Key, err)
+		}
+		updates = append(updates, upd)
+	}
+	return updates, nil
+}
+
+func FromSerializedUpdate(su syncproto.SerializedUpdate) Update {
+	u := Update{
+		Type:            updateTypeToString[su.UpdateType],
+		Key:             su.Key,
+		ResourceVersion: su.V3ResourceVersion,
+		TTL:             su.TTL,
+	}
+	if su.Value != nil {
+		v := string(su.Value)
+		u.Value = &v
+	}
+	if su.Revision != nil {
+		u.Revision = fmt.Sprint(su.Revision)
+	}
+	return u
+}
+
+func (u Update) SerializedUpdate() (syncproto.SerializedUpdate, error) {
+	updateType, ok := stringToUpdateType[u.Type]
+	if !ok {
+		return syncproto.SerializedUpdate{}, fmt.Errorf("unknown update type %q for key %q", u.Type, u.Key)
+	}
+	su := syncproto.SerializedUpdate{
+		Key:               u.Key,
+		Revision:          u.Revision,
+		V3ResourceVersion: u.ResourceVersion,
+		TTL:               u.TTL,
+		UpdateType:        updateType,
+	}
+	if u.Value != nil {
+		su.Value = []byte(*u.Value)
+	}
+	return su, nil
+}
+
+// Writer writes a recording.
+type Writer struct {
+	bufW   *bufio.Writer
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// Create creates the file at path and writes the header to it.
+func Create(path string, header Header) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWriter(f, header)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w.closer = f
+	return w, nil
+}
+
+// NewWriter writes the header to w and returns a Writer for the events.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	header.Version = FormatVersion
+	bufW := bufio.NewWriter(w)
+	rw := &Writer{
+		bufW: bufW,
+		enc:  json.NewEncoder(bufW),
+	}
+	if err := rw.enc.Encode(header); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+func (w *Writer) Write(e Event) error {
+	return w.enc.Encode(e)
+}
+
+func (w *Writer) Flush() error {
+	return w.bufW.Flush()
+}
+
+// Close flushes the recording and closes the underlying file, if the Writer was created by Create.
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if w.closer != nil {
+		err = errors.Join(err, w.closer.Close())
+	}
+	return err
+}
+
+// Reader reads a recording.
+type Reader struct {
+	Header Header
+
+	dec    *json.Decoder
+	closer io.Closer
+}
+
+// Open opens the recording at path and reads its header.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	r.closer = f
+	return r, nil
+}
+
+// NewReader reads the header from r and returns a Reader for the events.
+func NewReader(r io.Reader) (*Reader, error) {
+	rr := &Reader{
+		dec: json.NewDecoder(bufio.NewReader(r)),
+	}
+	if err := rr.dec.Decode(&rr.Header); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if rr.Header.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported recording version %d (expected %d)", rr.Header.Version, FormatVersion)
+	}
+	return rr, nil
+}
+
+// Next returns the next event.  It returns io.EOF at the end of the recording.
+func (r *Reader) Next() (Event, error) {
+	var e Event
+	err := r.dec.Decode(&e)
+	return e, err
+}
+
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recording
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	calinet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+func TestRoundTrip(t *testing.T) {
+	RegisterTestingT(t)
+
+	pool := apiv3.NewIPPool()
+	pool.Name = "default-pool"
+	pool.ResourceVersion = "1234"
+	pool.Spec.CIDR = "10.0.0.0/16"
+	wepKey := model.WorkloadEndpointKey{
+		Hostname:       "node-1",
+		OrchestratorID: "k8s",
+		WorkloadID:     "default/pod-1",
+		EndpointID:     "eth0",
+	}
+	updates := []api.Update{
+		{
+			KVPair:     model.KVPair{Key: model.ResourceKey{Kind: apiv3.KindIPPool, Name: "default-pool"}, Value: pool, Revision: "1234"},
+			UpdateType: api.UpdateTypeKVNew,
+		},
+		{
+			KVPair: model.KVPair{Key: wepKey, Value: &model.WorkloadEndpoint{
+				State:    "active",
+				Name:     "cali12345",
+				IPv4Nets: []calinet.IPNet{calinet.MustParseNetwork("10.0.0.1/32")},
+			}, Revision: "1235"},
+			UpdateType: api.UpdateTypeKVUpdated,
+		},
+		{
+			KVPair:     model.KVPair{Key: model.GlobalConfigKey{Name: "LogSeverityScreen"}, Revision: "1236"},
+			UpdateType: api.UpdateTypeKVDeleted,
+		},
+	}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{SyncerType: syncproto.SyncerTypeFelix, Server: "typha:5473", StartTime: start})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(w.Write(StatusEvent(start, api.ResyncInProgress))).To(Succeed())
+	e, err := UpdatesEvent(start.Add(time.Second), updates)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(w.Write(e)).To(Succeed())
+	Expect(w.Write(StatusEvent(start.Add(2*time.Second), api.InSync))).To(Succeed())
+	Expect(w.Close()).To(Succeed())
+
+	// One line per event, after the header.
+	Expect(strings.Count(buf.String(), "\n")).To(Equal(4))
+
+	r, err := NewReader(&buf)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(r.Header).To(Equal(Header{
+		Version:    FormatVersion,
+		SyncerType: syncproto.SyncerTypeFelix,
+		Server:     "typha:5473",
+		StartTime:  start,
+	}))
+
+	e, err = r.Next()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(e.Time).To(Equal(start))
+	status, ok, err := e.SyncStatus()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(ok).To(BeTrue())
+	Expect(status).To(Equal(api.ResyncInProgress))
+
+	e, err = r.Next()
+	Expect(err).NotTo(HaveOccurred())
+	_, ok, err = e.SyncStatus()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(ok).To(BeFalse())
+	parsed, err := e.APIUpdates()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(parsed).To(HaveLen(3))
+	Expect(parsed[0].Key).To(Equal(updates[0].Key))
+	Expect(parsed[0].Value.(*apiv3.IPPool).Spec.CIDR).To(Equal("10.0.0.0/16"))
+	Expect(parsed[0].Value.(*apiv3.IPPool).ResourceVersion).To(Equal("1234"))
+	Expect(parsed[1].Key).To(Equal(wepKey))
+	Expect(parsed[1].Value).To(Equal(updates[1].Value))
+	Expect(parsed[1].UpdateType).To(Equal(api.UpdateTypeKVUpdated))
+	Expect(parsed[1].Revision).To(Equal("1235"))
+	Expect(parsed[2].Value).To(BeNil())
+	Expect(parsed[2].UpdateType).To(Equal(api.UpdateTypeKVDeleted))
+
+	e, err = r.Next()
+	Expect(err).NotTo(HaveOccurred())
+	status, _, _ = e.SyncStatus()
+	Expect(status).To(Equal(api.InSync))
+
+	_, err = r.Next()
+	Expect(err).To(Equal(io.EOF))
+}
+
+func TestRejectsUnknownVersion(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewReader(strings.NewReader(`{"version":2,"syncerType":"felix"}` + "\n"))
+	Expect(err).To(MatchError(ContainSubstring("unsupported recording version 2")))
+}
@@ -23,6 +23,7 @@ import (
 	"unsafe"
 
 	"github.com/google/btree"
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
@@ -35,8 +36,10 @@ import (
 )
 
 const (
-	defaultMaxBatchSize   = 100
-	defaultWakeUpInterval = time.Second
+	defaultMaxBatchSize        = 100
+	defaultWakeUpInterval      = time.Second
+	defaultMaxResumeHistory    = 1000
+	defaultPersistenceInterval = time.Minute
 )
 
 var (
@@ -110,6 +113,30 @@ func init() {
 // When it reaches the end of the list, the Next() method blocks on a global condition variable,
 // which is Broadcast() by the main thread once the next snapshot is available.
 //
+// # Resuming
+//
+// The Cache retains the most recent Breadcrumbs (up to Config.MaxResumeHistory of them) so that a
+// client that lost its connection can resume from the last Breadcrumb that it applied rather than
+// receiving a fresh snapshot.  Breadcrumbs are identified either by the ID of the Cache and their
+// sequence number or by the fingerprint of their KVs, which is the same for any Cache that holds
+// the same KVs, for example, the cache of another Typha instance.
+//
+// The fingerprint covers the keys and values of the KVs but not their revisions: the Cache skips
+// updates that only change the revision, so two Caches can hold the same value with different
+// revisions.  A client that resumes from another Cache by fingerprint therefore keeps the revisions
+// that it already has, which may differ from the ones that the new Cache would have sent in a
+// snapshot.  That is fine for Felix, which ignores revisions, but a client that relies on them
+// (say, for a compare-and-swap write back to the datastore) should disable resuming.
+//
+// # Persistence
+//
+// If Config.PersistencePath is set, the Cache periodically saves its KVs to disk, along with the datastore
+// revision that each of the syncer's resource types had reached (as reported to OnResourceTypeUpdates).  At
+// start of day, it loads the saved KVs and serves them straight away, with status ResyncInProgress, and
+// ResumeState tells the syncer to resume from the saved revisions.  Once the syncer is in sync, any loaded
+// KVs that the syncer can't account for (because their resource type wasn't resumed and they weren't sent
+// again) are deleted.
+//
 // Why not use channels to fan out to the clients?  I think it'd be more tricky to make robust and
 // non-blocking:  We'd need to keep a list of channels to send to (one per client); the
 // bookkeeping around adding/removing from that list is a little fiddly and we'd need to
@@ -120,6 +147,8 @@ func init() {
 // client look after itself.
 type Cache struct {
 	config Config
+	// id is a random ID for this Cache, sequence numbers are only meaningful in the context of it.
+	id string
 
 	inputC chan interface{}
 
@@ -137,6 +166,32 @@ type Cache struct {
 	// As described above, we use an unsafe.Pointer so we can do opportunistic atomic reads of the value to avoid
 	// blocking.
 	currentBreadcrumb unsafe.Pointer
+	// fingerprint is the sum of the fingerprints of the KVs in kvs.
+	fingerprint uint64
+
+	// historyLock protects the history fields below, which are written by the main loop and read by
+	// client threads looking up a Breadcrumb to resume from.
+	historyLock sync.Mutex
+	// history is a ring buffer of the most recent Breadcrumbs, indexed by sequence number.
+	history []*Breadcrumb
+	// historyByFingerprint maps the fingerprints of the Breadcrumbs in history to the most recent
+	// Breadcrumb with that fingerprint.
+	historyByFingerprint map[uint64]*Breadcrumb
+
+	// The fields below are only used if persistence is enabled.  owners maps the keys of our KVs to the
+	// list root of the syncer resource type that they came from, if known.  revisions maps the list root of
+	// each resource type to the revision that it has reached.
+	owners    map[string]string
+	revisions map[string]string
+	// unconfirmedKeys contains the keys that we loaded from disk and haven't heard about from the syncer
+	// since.  It is nil once the syncer is in sync.
+	unconfirmedKeys map[string]struct{}
+	// resumedOwners contains the list roots of the resource types that the syncer has reported on since
+	// we loaded from disk.
+	resumedOwners  map[string]bool
+	persistTicks   <-chan time.Time
+	persistNeeded  bool
+	persistRunning atomic.Bool
 
 	wakeUpTicker *jitter.Ticker
 	healthTicks  <-chan time.Time
@@ -165,6 +220,12 @@ type Config struct {
 	HealthAggregator healthAggregator
 	Name             string
 	HealthName       string
+	// MaxResumeHistory is the number of recent Breadcrumbs to retain for clients to resume from.
+	MaxResumeHistory int
+	// PersistencePath, if set, is the file that the cache saves its snapshot to, every PersistenceInterval,
+	// and loads it from when it is created.
+	PersistencePath     string
+	PersistenceInterval time.Duration
 }
 
 func (config *Config) ApplyDefaults() {
@@ -182,6 +243,20 @@ func (config *Config) ApplyDefaults() {
 		}).Info("Defaulting WakeUpInterval.")
 		config.WakeUpInterval = defaultWakeUpInterval
 	}
+	if config.MaxResumeHistory <= 0 {
+		log.WithFields(log.Fields{
+			"value":   config.MaxResumeHistory,
+			"default": defaultMaxResumeHistory,
+		}).Info("Defaulting MaxResumeHistory.")
+		config.MaxResumeHistory = defaultMaxResumeHistory
+	}
+	if config.PersistencePath != "" && config.PersistenceInterval <= 0 {
+		log.WithFields(log.Fields{
+			"value":   config.PersistenceInterval,
+			"default": defaultPersistenceInterval,
+		}).Info("Defaulting PersistenceInterval.")
+		config.PersistenceInterval = defaultPersistenceInterval
+	}
 	if config.HealthName == "" {
 		if config.Name == "" {
 			config.HealthName = "cache"
@@ -202,12 +277,15 @@ func New(config Config) *Cache {
 	cond := sync.NewCond(&sync.Mutex{})
 
 	c := &Cache{
-		config:         config,
-		inputC:         make(chan interface{}, config.MaxBatchSize*2),
-		breadcrumbCond: cond,
-		kvs:            kvs,
-		wakeUpTicker:   jitter.NewTicker(config.WakeUpInterval, config.WakeUpInterval/10),
-		healthTicks:    time.NewTicker(healthInterval).C,
+		config:               config,
+		id:                   uuid.NewString(),
+		inputC:               make(chan interface{}, config.MaxBatchSize*2),
+		breadcrumbCond:       cond,
+		kvs:                  kvs,
+		history:              make([]*Breadcrumb, config.MaxResumeHistory),
+		historyByFingerprint: map[uint64]*Breadcrumb{},
+		wakeUpTicker:         jitter.NewTicker(config.WakeUpInterval, config.WakeUpInterval/10),
+		healthTicks:          time.NewTicker(healthInterval).C,
 	}
 
 	var err error
@@ -230,14 +308,28 @@ func New(config Config) *Cache {
 		},
 	}))
 
+	if config.PersistencePath != "" {
+		c.owners = map[string]string{}
+		c.revisions = map[string]string{}
+		c.persistTicks = time.NewTicker(config.PersistenceInterval).C
+		if err := c.loadSnapshot(); err != nil {
+			log.WithError(err).WithField("path", config.PersistencePath).Warn(
+				"Failed to load saved snapshot, starting with an empty cache.")
+		}
+	}
+
 	snap := &Breadcrumb{
+		cacheID:                   c.id,
 		Timestamp:                 time.Now(),
 		nextCond:                  cond,
-		KVs:                       kvs.Clone(),
+		KVs:                       c.kvs.Clone(),
+		Fingerprint:               c.fingerprint,
+		SyncStatus:                c.pendingStatus,
 		counterBreadcrumbBlock:    c.counterBreadcrumbBlock,
 		counterBreadcrumbNonBlock: c.counterBreadcrumbNonBlock,
 	}
 	c.currentBreadcrumb = (unsafe.Pointer)(snap)
+	c.recordHistory(snap)
 
 	if config.HealthAggregator != nil {
 		config.HealthAggregator.RegisterReporter(config.HealthName, &health.HealthReport{Live: true, Ready: true}, healthInterval*2)
@@ -253,6 +345,40 @@ func (c *Cache) CurrentBreadcrumb() *Breadcrumb {
 	return (*Breadcrumb)(atomic.LoadPointer(&c.currentBreadcrumb))
 }
 
+// ID returns the random ID of the cache, which identifies it in ResumeTokens.
+func (c *Cache) ID() string {
+	return c.id
+}
+
+// ResumeBreadcrumb returns the retained Breadcrumb that holds the state identified by the token,
+// or nil if there is no such Breadcrumb.  A Breadcrumb of this cache with the token's sequence
+// number is preferred; otherwise, the most recent Breadcrumb with the same fingerprint is returned,
+// whose KVs may have different revisions from the client's.  It is safe to call from any goroutine.
+func (c *Cache) ResumeBreadcrumb(token syncproto.ResumeToken) *Breadcrumb {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	if token.CacheID == c.id {
+		crumb := c.history[token.SequenceNumber%uint64(len(c.history))]
+		if crumb != nil && crumb.SequenceNumber == token.SequenceNumber {
+			return crumb
+		}
+	}
+	return c.historyByFingerprint[token.Fingerprint]
+}
+
+func (c *Cache) recordHistory(crumb *Breadcrumb) {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	idx := crumb.SequenceNumber % uint64(len(c.history))
+	if old := c.history[idx]; old != nil && c.historyByFingerprint[old.Fingerprint] == old {
+		delete(c.historyByFingerprint, old.Fingerprint)
+	}
+	c.history[idx] = crumb
+	c.historyByFingerprint[crumb.Fingerprint] = crumb
+}
+
 // OnStatusUpdated implements the SyncerCallbacks API.  It shouldn't be called directly.
 func (c *Cache) OnStatusUpdated(status api.SyncStatus) {
 	c.inputC <- status
@@ -267,6 +393,26 @@ func (c *Cache) OnUpdates(updates []api.Update) {
 	c.inputC <- updates
 }
 
+// OnResourceTypeUpdates implements the SyncerResourceTypeCallbacks API.  It shouldn't be called directly.
+func (c *Cache) OnResourceTypeUpdates(listRoot string, revision string, updates []api.Update) {
+	if c.config.PersistencePath == "" {
+		// We only need to know the resource types if we're saving them.
+		c.OnUpdates(updates)
+		return
+	}
+	c.inputC <- resourceTypeUpdates{
+		listRoot: listRoot,
+		revision: revision,
+		updates:  updates,
+	}
+}
+
+type resourceTypeUpdates struct {
+	listRoot string
+	revision string
+	updates  []api.Update
+}
+
 // Start starts the cache's main loop in a background goroutine.
 func (c *Cache) Start(ctx context.Context) {
 	go c.loop(ctx)
@@ -296,6 +442,14 @@ func (c *Cache) fillBatchFromInputQueue(ctx context.Context) error {
 		switch obj := obj.(type) {
 		case api.SyncStatus:
 			log.WithField("status", obj).Info("Received status update message from datastore.")
+			if c.unconfirmedKeys != nil {
+				if obj == api.InSync {
+					c.deleteUnconfirmedKeys()
+				} else {
+					// We're already serving the snapshot that we loaded, don't go back to WaitForDatastore.
+					obj = api.ResyncInProgress
+				}
+			}
 			c.pendingStatus = obj
 			batchSize++
 			// Report health immediately in case our sync status has changed.
@@ -304,6 +458,17 @@ func (c *Cache) fillBatchFromInputQueue(ctx context.Context) error {
 			log.WithField("numUpdates", len(obj)).Debug("Received updates.")
 			c.pendingUpdates = append(c.pendingUpdates, obj...)
 			batchSize += len(obj)
+			c.recordResourceType("", obj)
+		case resourceTypeUpdates:
+			log.WithFields(log.Fields{
+				"listRoot":   obj.listRoot,
+				"revision":   obj.revision,
+				"numUpdates": len(obj.updates),
+			}).Debug("Received resource type updates.")
+			c.pendingUpdates = append(c.pendingUpdates, obj.updates...)
+			batchSize += len(obj.updates)
+			c.recordResourceType(obj.listRoot, obj.updates)
+			c.revisions[obj.listRoot] = obj.revision
 		default:
 			log.WithField("obj", obj).Panic("Unexpected object")
 		}
@@ -345,6 +510,8 @@ func (c *Cache) fillBatchFromInputQueue(ctx context.Context) error {
 			c.summaryUpdateSize.Observe(0)
 		case <-c.healthTicks:
 			c.reportHealth()
+		case <-c.persistTicks:
+			c.maybePersist()
 		}
 	}
 	return ctx.Err()
@@ -352,9 +519,11 @@ func (c *Cache) fillBatchFromInputQueue(ctx context.Context) error {
 
 func (c *Cache) reportHealth() {
 	if c.config.HealthAggregator != nil {
+		// If we loaded a snapshot from disk, we're ready to serve it while we resync.
+		ready := c.pendingStatus == api.InSync || c.unconfirmedKeys != nil
 		c.config.HealthAggregator.Report(c.config.HealthName, &health.HealthReport{
 			Live:  true,
-			Ready: c.pendingStatus == api.InSync,
+			Ready: ready,
 		})
 	}
 }
@@ -388,6 +557,7 @@ func (c *Cache) publishBreadcrumb() {
 	// Create the new crumb.
 	oldCrumb := c.CurrentBreadcrumb()
 	newCrumb := &Breadcrumb{
+		cacheID:        c.id,
 		SequenceNumber: oldCrumb.SequenceNumber + 1,
 		Timestamp:      time.Now(),
 		SyncStatus:     oldCrumb.SyncStatus,
@@ -425,6 +595,9 @@ func (c *Cache) publishBreadcrumb() {
 			// This is either a deletion or a validation failure.  We can't skip deletions even if we
 			// didn't have that key before because we need to pass through the UpdateType for Felix to
 			// correctly calculate its stats.
+			if exists {
+				c.fingerprint -= oldUpd.Fingerprint()
+			}
 			c.kvs.Delete(newUpd)
 		} else {
 			if exists && newUpd.WouldBeNoOp(oldUpd) {
@@ -438,6 +611,10 @@ func (c *Cache) publishBreadcrumb() {
 			updToStore := newUpd
 			updToStore.UpdateType = api.UpdateTypeKVNew
 			c.kvs.ReplaceOrInsert(updToStore)
+			if exists {
+				c.fingerprint -= oldUpd.Fingerprint()
+			}
+			c.fingerprint += updToStore.Fingerprint()
 		}
 
 		// Record the update in the new Breadcrumb so that clients following the chain of
@@ -458,6 +635,7 @@ func (c *Cache) publishBreadcrumb() {
 	c.gaugeSnapSize.Set(float64(c.kvs.Len()))
 	// Add the new read-only snapshot to the new crumb.
 	newCrumb.KVs = c.kvs.Clone()
+	newCrumb.Fingerprint = c.fingerprint
 
 	// Replace the Breadcrumb and link the old Breadcrumb to the new so that clients can follow
 	// the trail.
@@ -467,6 +645,7 @@ func (c *Cache) publishBreadcrumb() {
 	atomic.StorePointer(&(oldCrumb.next), (unsafe.Pointer)(newCrumb))
 	atomic.StorePointer(&c.currentBreadcrumb, (unsafe.Pointer)(newCrumb))
 	c.breadcrumbCond.L.Unlock()
+	c.recordHistory(newCrumb)
 	// Then wake up any watching clients.  Note: Go's Cond doesn't require us to hold the lock
 	// while calling Broadcast.
 	log.WithField("seqNo", newCrumb.SequenceNumber).Debug("Broadcasting new Breadcrumb")
@@ -479,10 +658,14 @@ type Breadcrumb struct {
 	SequenceNumber uint64
 	Timestamp      time.Time
 
-	KVs        *btree.BTreeG[syncproto.SerializedUpdate]
-	Deltas     []syncproto.SerializedUpdate
-	SyncStatus api.SyncStatus
+	KVs *btree.BTreeG[syncproto.SerializedUpdate]
+	// Fingerprint is the sum of the fingerprints of the KVs.  It excludes revisions, see the
+	// Cache's description of resuming.
+	Fingerprint uint64
+	Deltas      []syncproto.SerializedUpdate
+	SyncStatus  api.SyncStatus
 
+	cacheID  string
 	nextCond *sync.Cond
 	next     unsafe.Pointer
 
@@ -516,6 +699,15 @@ func (b *Breadcrumb) Next(ctx context.Context) (*Breadcrumb, error) {
 	return next, nil
 }
 
+// ResumeToken returns the token that identifies the state held by this Breadcrumb.
+func (b *Breadcrumb) ResumeToken() syncproto.ResumeToken {
+	return syncproto.ResumeToken{
+		CacheID:        b.cacheID,
+		SequenceNumber: b.SequenceNumber,
+		Fingerprint:    b.Fingerprint,
+	}
+}
+
 // loadNext does an atomic load of the next pointer.  It returns nil or the next Breadcrumb.
 func (b *Breadcrumb) loadNext() *Breadcrumb {
 	return (*Breadcrumb)(atomic.LoadPointer(&b.next))
@@ -17,6 +17,8 @@ package snapcache_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"time"
@@ -620,6 +622,192 @@ func (f *follower) Loop(cxt context.Context) {
 	logCxt.WithError(cxt.Err()).WithField("crumb", crumb.SequenceNumber).Info("Exiting")
 }
 
+var _ = Describe("Resuming from the history", func() {
+	var cxt context.Context
+	var cancel context.CancelFunc
+
+	configUpdate := func(name, value, rev string) api.Update {
+		return api.Update{
+			KVPair: model.KVPair{
+				Key:      model.GlobalConfigKey{Name: name},
+				Value:    value,
+				Revision: rev,
+			},
+			UpdateType: api.UpdateTypeKVNew,
+		}
+	}
+	newCache := func(historySize int) *snapcache.Cache {
+		cache := snapcache.New(snapcache.Config{
+			MaxBatchSize:     1,
+			WakeUpInterval:   10 * time.Second,
+			MaxResumeHistory: historySize,
+		})
+		cache.Start(cxt)
+		return cache
+	}
+	waitForSeqNo := func(cache *snapcache.Cache, seqNo uint64) *snapcache.Breadcrumb {
+		Eventually(func() uint64 { return cache.CurrentBreadcrumb().SequenceNumber }).Should(Equal(seqNo))
+		return cache.CurrentBreadcrumb()
+	}
+
+	BeforeEach(func() {
+		cxt, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("should find retained breadcrumbs by sequence number", func() {
+		cache := newCache(3)
+		var crumbs []*snapcache.Breadcrumb
+		for i := 1; i <= 4; i++ {
+			cache.OnUpdates([]api.Update{configUpdate(fmt.Sprint("key", i), "value", "1")})
+			crumbs = append(crumbs, waitForSeqNo(cache, uint64(i)))
+		}
+
+		for _, crumb := range crumbs[1:] {
+			token := crumb.ResumeToken()
+			Expect(token.CacheID).To(Equal(cache.ID()))
+			Expect(cache.ResumeBreadcrumb(token)).To(BeIdenticalTo(crumb))
+		}
+		// The first breadcrumb has dropped out of the history.
+		Expect(cache.ResumeBreadcrumb(crumbs[0].ResumeToken())).To(BeNil())
+	})
+
+	It("should find equivalent breadcrumbs in another cache by fingerprint", func() {
+		cache1 := newCache(10)
+		cache1.OnUpdates([]api.Update{configUpdate("a", "1", "1"), configUpdate("b", "2", "2")})
+		cache1.OnUpdates([]api.Update{configUpdate("a", "3", "3")})
+		crumb1 := waitForSeqNo(cache1, 3)
+
+		// Same state, reached through different updates, batches and revisions.
+		cache2 := newCache(10)
+		cache2.OnUpdates([]api.Update{configUpdate("b", "2", "12")})
+		cache2.OnUpdates([]api.Update{configUpdate("c", "4", "13")})
+		cache2.OnUpdates([]api.Update{configUpdate("a", "3", "14")})
+		cache2.OnUpdates([]api.Update{{
+			KVPair:     model.KVPair{Key: model.GlobalConfigKey{Name: "c"}, Revision: "15"},
+			UpdateType: api.UpdateTypeKVDeleted,
+		}})
+		crumb2 := waitForSeqNo(cache2, 4)
+
+		Expect(crumb2.Fingerprint).To(Equal(crumb1.Fingerprint))
+		Expect(cache2.ResumeBreadcrumb(crumb1.ResumeToken())).To(BeIdenticalTo(crumb2))
+
+		cache2.OnUpdates([]api.Update{configUpdate("a", "5", "16")})
+		waitForSeqNo(cache2, 5)
+		Expect(cache2.ResumeBreadcrumb(cache2.CurrentBreadcrumb().ResumeToken()).Fingerprint).NotTo(
+			Equal(crumb1.Fingerprint))
+		Expect(cache2.ResumeBreadcrumb(crumb1.ResumeToken())).To(BeIdenticalTo(crumb2))
+	})
+})
+
+var _ = Describe("Persisting the snapshot", func() {
+	var cxt context.Context
+	var cancel context.CancelFunc
+	var dir string
+	var config snapcache.Config
+
+	configUpdate := func(name, value, rev string) api.Update {
+		return api.Update{
+			KVPair: model.KVPair{
+				Key:      model.GlobalConfigKey{Name: name},
+				Value:    value,
+				Revision: rev,
+			},
+			UpdateType: api.UpdateTypeKVNew,
+		}
+	}
+	configKeys := func(crumb *snapcache.Breadcrumb) []string {
+		var names []string
+		crumb.KVs.Ascend(func(su syncproto.SerializedUpdate) bool {
+			names = append(names, model.KeyFromDefaultPath(su.Key).(model.GlobalConfigKey).Name)
+			return true
+		})
+		return names
+	}
+
+	BeforeEach(func() {
+		cxt, cancel = context.WithCancel(context.Background())
+		var err error
+		dir, err = os.MkdirTemp("", "snapcache")
+		Expect(err).NotTo(HaveOccurred())
+		config = snapcache.Config{
+			Name:                "felix",
+			WakeUpInterval:      10 * time.Second,
+			PersistencePath:     filepath.Join(dir, "felix.snapshot"),
+			PersistenceInterval: 100 * time.Millisecond,
+		}
+
+		// Fill a cache and wait for it to save its snapshot.
+		cache := snapcache.New(config)
+		cache.OnStatusUpdated(api.ResyncInProgress)
+		cache.OnResourceTypeUpdates("/calico/resources/v3/a", "10", []api.Update{
+			configUpdate("a", "1", "5"),
+			configUpdate("b", "2", "6"),
+		})
+		cache.OnUpdates([]api.Update{configUpdate("c", "3", "7")})
+		cache.OnStatusUpdated(api.InSync)
+		cacheCxt, cancelCache := context.WithCancel(cxt)
+		defer cancelCache()
+		cache.Start(cacheCxt)
+		Eventually(func() error {
+			_, err := os.Stat(config.PersistencePath)
+			return err
+		}).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		cancel()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should serve the saved snapshot as not in sync", func() {
+		cache := snapcache.New(config)
+		crumb := cache.CurrentBreadcrumb()
+		Expect(crumb.SyncStatus).To(Equal(api.ResyncInProgress))
+		Expect(configKeys(crumb)).To(Equal([]string{"a", "b", "c"}))
+
+		// The fingerprint matches a cache that received the same KVs.
+		other := snapcache.New(snapcache.Config{})
+		other.OnUpdates([]api.Update{configUpdate("a", "1", "5"), configUpdate("b", "2", "6"), configUpdate("c", "3", "7")})
+		other.Start(cxt)
+		Eventually(func() uint64 { return other.CurrentBreadcrumb().Fingerprint }).Should(Equal(crumb.Fingerprint))
+	})
+
+	It("should return the saved revisions and the KVs of each resource type to resume from", func() {
+		cache := snapcache.New(config)
+		Expect(cache.ResumeState()).To(Equal(map[string]api.ResourceTypeState{
+			"/calico/resources/v3/a": {
+				Revision: "10",
+				KVs: []model.KVPair{
+					{Key: model.GlobalConfigKey{Name: "a"}, Revision: "5"},
+					{Key: model.GlobalConfigKey{Name: "b"}, Revision: "6"},
+				},
+			},
+		}))
+	})
+
+	It("should delete loaded KVs that the syncer doesn't account for once it is in sync", func() {
+		cache := snapcache.New(config)
+		cache.Start(cxt)
+		cache.OnStatusUpdated(api.WaitForDatastore)
+		cache.OnResourceTypeUpdates("/calico/resources/v3/a", "11", []api.Update{{
+			KVPair:     model.KVPair{Key: model.GlobalConfigKey{Name: "b"}, Revision: "11"},
+			UpdateType: api.UpdateTypeKVDeleted,
+		}})
+		Eventually(func() []string { return configKeys(cache.CurrentBreadcrumb()) }).Should(Equal([]string{"a", "c"}))
+		Expect(cache.CurrentBreadcrumb().SyncStatus).To(Equal(api.ResyncInProgress))
+
+		// "a" belongs to a resource type that the syncer resumed so it stays, "c" wasn't sent again so it
+		// must have been deleted.
+		cache.OnStatusUpdated(api.InSync)
+		Eventually(func() api.SyncStatus { return cache.CurrentBreadcrumb().SyncStatus }).Should(Equal(api.InSync))
+		Expect(configKeys(cache.CurrentBreadcrumb())).To(Equal([]string{"a"}))
+	})
+})
+
 var _ = Describe("Zero config after applying defaults", func() {
 	var config snapcache.Config
 
@@ -634,6 +822,9 @@ var _ = Describe("Zero config after applying defaults", func() {
 	It("should default the wake up interval", func() {
 		Expect(config.WakeUpInterval).To(Equal(time.Second))
 	})
+	It("should default the resume history", func() {
+		Expect(config.MaxResumeHistory).To(Equal(1000))
+	})
 })
 
 var _ = Describe("Non-zero config after applying defaults", func() {
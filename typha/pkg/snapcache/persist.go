@@ -0,0 +1,282 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapcache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/google/btree"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// persistenceFormatVersion is the version of the on-disk format.  Snapshots with a different version
+// are ignored.
+const persistenceFormatVersion = 1
+
+// The on-disk format is a snappy stream of gobs: a snapshotHeader followed by NumKVs persistedKVs.
+type snapshotHeader struct {
+	FormatVersion int
+	Name          string
+	Timestamp     time.Time
+	Revisions     map[string]string
+	NumKVs        int
+}
+
+type persistedKV struct {
+	Update   syncproto.SerializedUpdate
+	ListRoot string
+}
+
+// recordResourceType records the resource type that the given updates came from, so that we can save it
+// along with the KVs.  listRoot is "" if the syncer didn't tell us.
+func (c *Cache) recordResourceType(listRoot string, updates []api.Update) {
+	if c.owners == nil {
+		// Persistence disabled.
+		return
+	}
+	c.persistNeeded = true
+	if c.unconfirmedKeys != nil && listRoot != "" {
+		c.resumedOwners[listRoot] = true
+	}
+	for _, u := range updates {
+		path, err := model.KeyToDefaultPath(u.Key)
+		if err != nil {
+			// Will be logged when we serialize the update.
+			continue
+		}
+		delete(c.unconfirmedKeys, path)
+		if u.Value == nil || listRoot == "" {
+			delete(c.owners, path)
+		} else {
+			c.owners[path] = listRoot
+		}
+	}
+}
+
+// deleteUnconfirmedKeys queues deletions for the keys that we loaded from disk that the syncer, now in sync,
+// hasn't sent us again.  Keys of resource types that the syncer resumed are left alone since the syncer
+// knew about them and would have sent deletions if they were gone.
+func (c *Cache) deleteUnconfirmedKeys() {
+	numDeleted := 0
+	for path := range c.unconfirmedKeys {
+		if owner := c.owners[path]; owner != "" && c.resumedOwners[owner] {
+			continue
+		}
+		key := model.KeyFromDefaultPath(path)
+		if key == nil {
+			continue
+		}
+		c.pendingUpdates = append(c.pendingUpdates, api.Update{
+			KVPair:     model.KVPair{Key: key},
+			UpdateType: api.UpdateTypeKVDeleted,
+		})
+		numDeleted++
+	}
+	log.WithField("numDeleted", numDeleted).Info("Syncer in sync, deleting loaded KVs that are no longer present.")
+	c.unconfirmedKeys = nil
+	c.resumedOwners = nil
+}
+
+// ResumeState returns the state of each of the syncer's resource types in the snapshot that we loaded from
+// disk, for the syncer to resume from (see api.SyncerResumeCallbacks).  It must be called before Start.
+func (c *Cache) ResumeState() map[string]api.ResourceTypeState {
+	states := map[string]api.ResourceTypeState{}
+	for listRoot, revision := range c.revisions {
+		states[listRoot] = api.ResourceTypeState{Revision: revision}
+	}
+	c.kvs.Ascend(func(su syncproto.SerializedUpdate) bool {
+		owner := c.owners[su.Key]
+		if owner == "" {
+			return true
+		}
+		key := model.KeyFromDefaultPath(su.Key)
+		if key == nil {
+			return true
+		}
+		revision, _ := su.Revision.(string)
+		state := states[owner]
+		state.KVs = append(state.KVs, model.KVPair{Key: key, Revision: revision})
+		states[owner] = state
+		return true
+	})
+	return states
+}
+
+// maybePersist starts saving the current snapshot in the background, if it has changed since we last
+// saved it and we're not already saving it.
+func (c *Cache) maybePersist() {
+	if !c.persistNeeded || c.persistRunning.Load() {
+		return
+	}
+	crumb := c.CurrentBreadcrumb()
+	header := snapshotHeader{
+		FormatVersion: persistenceFormatVersion,
+		Name:          c.config.Name,
+		Timestamp:     crumb.Timestamp,
+		Revisions:     make(map[string]string, len(c.revisions)),
+		NumKVs:        crumb.KVs.Len(),
+	}
+	for listRoot, revision := range c.revisions {
+		header.Revisions[listRoot] = revision
+	}
+	owners := make(map[string]string, len(c.owners))
+	for path, owner := range c.owners {
+		owners[path] = owner
+	}
+	c.persistNeeded = false
+	c.persistRunning.Store(true)
+	go func() {
+		defer c.persistRunning.Store(false)
+		startTime := time.Now()
+		logCxt := log.WithFields(log.Fields{
+			"path":   c.config.PersistencePath,
+			"numKVs": header.NumKVs,
+		})
+		if err := writeSnapshot(c.config.PersistencePath, header, crumb.KVs, owners); err != nil {
+			logCxt.WithError(err).Warn("Failed to save snapshot.")
+			return
+		}
+		logCxt.WithField("duration", time.Since(startTime)).Info("Saved snapshot.")
+	}()
+}
+
+// writeSnapshot writes the snapshot to a temporary file and then renames it over the old one so that we
+// never leave a partial snapshot behind.
+func writeSnapshot(
+	path string,
+	header snapshotHeader,
+	kvs *btree.BTreeG[syncproto.SerializedUpdate],
+	owners map[string]string,
+) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	w := snappy.NewBufferedWriter(f)
+	enc := gob.NewEncoder(w)
+	if err = enc.Encode(header); err != nil {
+		return err
+	}
+	kvs.Ascend(func(su syncproto.SerializedUpdate) bool {
+		err = enc.Encode(persistedKV{Update: su, ListRoot: owners[su.Key]})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// loadSnapshot loads the snapshot saved by a previous Cache, if there is one.  On success, the loaded KVs
+// become the current state of the cache, with status ResyncInProgress until the syncer is in sync.
+func (c *Cache) loadSnapshot() error {
+	f, err := os.Open(c.config.PersistencePath)
+	if errors.Is(err, os.ErrNotExist) {
+		log.WithField("path", c.config.PersistencePath).Info("No saved snapshot to load.")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := gob.NewDecoder(snappy.NewReader(f))
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+	if header.FormatVersion != persistenceFormatVersion {
+		return fmt.Errorf("unsupported snapshot format version %d", header.FormatVersion)
+	}
+	if header.Name != c.config.Name {
+		return fmt.Errorf("snapshot belongs to cache %q", header.Name)
+	}
+
+	kvs := c.kvs.Clone()
+	var fingerprint uint64
+	owners := map[string]string{}
+	for i := 0; i < header.NumKVs; i++ {
+		var kv persistedKV
+		if err := dec.Decode(&kv); err != nil {
+			return err
+		}
+		// Round-trip the update so that it is in the form that this version of Typha would produce.
+		upd, err := kv.Update.ToUpdate()
+		if err != nil || upd.Value == nil {
+			log.WithField("key", kv.Update.Key).Warn("Failed to parse saved KV, ignoring it.")
+			continue
+		}
+		su, err := syncproto.SerializeUpdate(upd)
+		if err != nil {
+			continue
+		}
+		su.UpdateType = api.UpdateTypeKVNew
+		kvs.ReplaceOrInsert(su)
+		fingerprint += su.Fingerprint()
+		if kv.ListRoot != "" {
+			owners[su.Key] = kv.ListRoot
+		}
+	}
+
+	c.kvs = kvs
+	c.fingerprint = fingerprint
+	c.owners = owners
+	if header.Revisions != nil {
+		c.revisions = header.Revisions
+	}
+	c.unconfirmedKeys = make(map[string]struct{}, kvs.Len())
+	kvs.Ascend(func(su syncproto.SerializedUpdate) bool {
+		c.unconfirmedKeys[su.Key] = struct{}{}
+		return true
+	})
+	c.resumedOwners = map[string]bool{}
+	c.pendingStatus = api.ResyncInProgress
+	c.gaugeSnapSize.Set(float64(kvs.Len()))
+	log.WithFields(log.Fields{
+		"path":      c.config.PersistencePath,
+		"numKVs":    kvs.Len(),
+		"savedAt":   header.Timestamp,
+		"revisions": header.Revisions,
+	}).Info("Loaded saved snapshot.")
+	return nil
+}
@@ -27,7 +27,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/golang/snappy"
 	log "github.com/sirupsen/logrus"
 
 	calicotls "github.com/projectcalico/calico/crypto/pkg/tls"
@@ -60,6 +59,14 @@ type Options struct {
 	// it (such as compression).  Useful for simulating an older client in UT.
 	DisableDecoderRestart bool
 
+	// DisableResume disables resuming the session after the connection to Typha fails.  Without it, the
+	// client gives up when the connection fails, as older clients did.
+	DisableResume bool
+
+	// ScopeToNode asks Typha for a view scoped to our hostname: full detail for our own WorkloadEndpoints
+	// and a condensed form of the remote ones.  Typha versions that don't support it send the full view.
+	ScopeToNode bool
+
 	// DebugLogReads tells the client to wrap each connection with a Reader that
 	// logs every read.  Intended only for use in tests!
 	DebugLogReads bool
@@ -152,12 +159,16 @@ type SyncerClient struct {
 	myHostname, myVersion, myInfo string
 	options                       *Options
 
+	// connLock protects connection, which is replaced when we reconnect, from the shut-down goroutine.
+	connLock                    sync.Mutex
 	connection                  net.Conn
 	connR                       io.Reader
 	encoder                     *gob.Encoder
 	decoder                     *gob.Decoder
 	handshakeStatus             *handshakeStatus
 	supportsNodeResourceUpdates bool
+	// resumeToken identifies the state that we've received from Typha, if Typha supports resuming.
+	resumeToken *syncproto.ResumeToken
 
 	callbacks callbacksWithKeysKnown
 	Finished  sync.WaitGroup
@@ -184,21 +195,61 @@ type handshakeStatus struct {
 func (s *SyncerClient) Start(cxt context.Context) error {
 	// Connect synchronously so that we can return an error early if we can't connect at all.
 	s.logCxt.Info("Starting Typha client...")
+	if err := s.connectToAnyTypha(cxt, nil); err != nil {
+		return err
+	}
 
+	// Then start our background goroutines.  We start the main loop and a second goroutine to
+	// manage shutdown.
+	cxt, cancelFn := context.WithCancel(cxt)
+	s.Finished.Add(1)
+	go s.loop(cxt, cancelFn)
+
+	s.Finished.Add(1)
+	go func() {
+		// Broadcast that we're finished.
+		defer s.Finished.Done()
+
+		// Wait for the context to finish, either due to external cancel or our own loop
+		// exiting.
+		<-cxt.Done()
+		s.logCxt.Info("Typha client Context asked us to exit, closing connection...")
+		// Close the connection.  This will trigger the main loop to exit if it hasn't
+		// already.
+		s.closeConnection()
+	}()
+	return nil
+}
+
+// connectToAnyTypha tries the discovered Typha instances in turn until it manages to connect to one.
+// If avoid is non-nil, that instance is only tried after all the others; we use that when reconnecting
+// so that we don't go straight back to a Typha that disconnected us to rebalance its load.
+func (s *SyncerClient) connectToAnyTypha(cxt context.Context, avoid *discovery.Typha) error {
 	// Defensive: in case there's a bug in NextAddr() and it never stops returning values,
 	// set a sanity limit on the number of tries.
 	maxTries := s.calculateConnectionAttemptLimit(len(s.discoverer.CachedTyphaAddrs()))
 	remainingTries := maxTries
 	cat := discovery.NewConnAttemptTracker(s.discoverer)
+	avoided := false
 	for {
 		remainingTries--
 		if remainingTries < 0 {
 			return fmt.Errorf("failed to connect to Typha after %d tries", maxTries)
 		}
 		addr, err := cat.NextAddr()
+		if errors.Is(err, discovery.ErrTriedAllAddrs) && avoided {
+			s.logCxt.Info("No other Typha available, falling back to the one we were connected to.")
+			addr, err = *avoid, nil
+			avoid, avoided = nil, false
+		}
 		if err != nil {
 			return fmt.Errorf("failed to load next Typha address to try: %w", err)
 		}
+		if avoid != nil && addr.Addr == avoid.Addr {
+			s.logCxt.Infof("Trying other Typha instances before %s.", addr.Addr)
+			avoided = true
+			continue
+		}
 		s.logCxt.Infof("Connecting to typha endpoint %s.", addr.Addr)
 		err = s.connect(cxt, addr)
 		if err != nil {
@@ -206,33 +257,21 @@ func (s *SyncerClient) Start(cxt context.Context) error {
 			time.Sleep(100 * time.Millisecond) // Avoid tight loop.
 		} else {
 			s.logCxt.Infof("Successfully connected to Typha at %s.", addr.Addr)
-			break
+			return nil
 		}
 	}
+}
 
-	// Then start our background goroutines.  We start the main loop and a second goroutine to
-	// manage shutdown.
-	cxt, cancelFn := context.WithCancel(cxt)
-	s.Finished.Add(1)
-	go s.loop(cxt, cancelFn)
-
-	s.Finished.Add(1)
-	go func() {
-		// Broadcast that we're finished.
-		defer s.Finished.Done()
-
-		// Wait for the context to finish, either due to external cancel or our own loop
-		// exiting.
-		<-cxt.Done()
-		s.logCxt.Info("Typha client Context asked us to exit, closing connection...")
-		// Close the connection.  This will trigger the main loop to exit if it hasn't
-		// already.
-		err := s.connection.Close()
-		if err != nil {
-			log.WithError(err).Warn("Ignoring error from Close during shut-down of client.")
-		}
-	}()
-	return nil
+func (s *SyncerClient) closeConnection() {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
+	if s.connection == nil {
+		return
+	}
+	err := s.connection.Close()
+	if err != nil {
+		log.WithError(err).Debug("Ignoring error from Close of connection.")
+	}
 }
 
 func (s *SyncerClient) calculateConnectionAttemptLimit(numDiscoveredTyphas int) int {
@@ -271,7 +310,6 @@ func (s *SyncerClient) SupportsNodeResourceUpdates(timeout time.Duration) (bool,
 
 func (s *SyncerClient) connect(cxt context.Context, typhaAddr discovery.Typha) error {
 	log.Info("Starting Typha client")
-	var err error
 	logCxt := s.logCxt.WithField("address", typhaAddr)
 
 	var connFunc func(string) (net.Conn, error)
@@ -324,22 +362,20 @@ func (s *SyncerClient) connect(cxt context.Context, typhaAddr discovery.Typha) e
 	}
 	if cxt.Err() == nil {
 		logCxt.Info("Connecting to Typha.")
-		s.connection, err = connFunc(typhaAddr.Addr)
+		conn, err := connFunc(typhaAddr.Addr)
 		if err != nil {
 			return err
 		}
+		s.connLock.Lock()
+		s.connection = conn
+		s.connLock.Unlock()
 		s.connR = s.connection
 		if s.options.DebugLogReads {
 			s.connR = readlogger.New(s.connection)
 		}
 	}
 	if cxt.Err() != nil {
-		if s.connection != nil {
-			err := s.connection.Close()
-			if err != nil {
-				log.WithError(err).Warn("Ignoring error from Close during shut-down of client.")
-			}
-		}
+		s.closeConnection()
 		return cxt.Err()
 	}
 
@@ -404,6 +440,27 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 	defer s.Finished.Done()
 	defer cancelFn()
 
+	for {
+		s.runConnection(cxt)
+		if cxt.Err() != nil {
+			return
+		}
+		if s.options.DisableResume || s.resumeToken == nil {
+			// Either we can't resume or we failed to.
+			return
+		}
+		s.logCxt.WithField("token", *s.resumeToken).Info("Connection to Typha failed, trying to resume the session.")
+		s.closeConnection()
+		if err := s.connectToAnyTypha(cxt, s.connInfo); err != nil {
+			s.logCxt.WithError(err).Error("Failed to reconnect to Typha.")
+			return
+		}
+	}
+}
+
+// runConnection does the handshake over the current connection and then processes messages from
+// the server until the connection fails.
+func (s *SyncerClient) runConnection(cxt context.Context) {
 	logCxt := s.logCxt.WithField("connection", s.connInfo)
 	logCxt.Info("Started Typha client main loop")
 
@@ -415,11 +472,19 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 	if ourSyncerType == "" {
 		ourSyncerType = syncproto.SyncerTypeFelix
 	}
-	compAlgs := []syncproto.CompressionAlgorithm{syncproto.CompressionSnappy}
+	compAlgs := []syncproto.CompressionAlgorithm{syncproto.CompressionZstd, syncproto.CompressionSnappy}
 	if s.options.DisableDecoderRestart {
 		// Compression requires decoder restart.
 		compAlgs = nil
 	}
+	var scopeToNode string
+	if s.options.ScopeToNode {
+		scopeToNode = s.myHostname
+	}
+	var resumeToken *syncproto.ResumeToken
+	if !s.options.DisableResume {
+		resumeToken = s.resumeToken
+	}
 	err := s.sendMessageToServer(cxt, logCxt, "send hello to server",
 		syncproto.MsgClientHello{
 			Hostname:                       s.myHostname,
@@ -429,6 +494,9 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 			SupportsDecoderRestart:         !s.options.DisableDecoderRestart,
 			SupportedCompressionAlgorithms: compAlgs,
 			ClientConnID:                   s.ID,
+			SupportsResume:                 !s.options.DisableResume,
+			ResumeToken:                    resumeToken,
+			ScopeToNode:                    scopeToNode,
 		},
 	)
 	if err != nil {
@@ -455,7 +523,21 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 		logCxt.Info("Server responded without support for node resource updates, assuming older Typha")
 	}
 	s.supportsNodeResourceUpdates = serverHello.SupportsNodeResourceUpdates
-	s.handshakeStatus.helloReceivedChan <- struct{}{}
+	if scopeToNode != "" && !serverHello.ScopedToNode {
+		logCxt.Info("Server responded without support for node-scoped views, assuming older Typha")
+	}
+	select {
+	case s.handshakeStatus.helloReceivedChan <- struct{}{}:
+	default:
+		// Already signalled by a previous connection.
+	}
+
+	if resumeToken != nil && !serverHello.Resumed {
+		// We have no way to reconcile a fresh snapshot with the state that we already passed on.
+		logCxt.WithField("token", *resumeToken).Error("Typha was unable to resume our session.")
+		s.resumeToken = nil
+		return
+	}
 
 	// Check the SyncerType reported by the server.  If the server is too old to support SyncerType then
 	// the message will have an empty string in place of the SyncerType.  In that case we only proceed if
@@ -516,6 +598,9 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 				keys = append(keys, kv.Key)
 			}
 			s.callbacks.OnUpdatesKeysKnown(updates, keys)
+			if msg.ResumeToken != nil {
+				s.resumeToken = msg.ResumeToken
+			}
 		case syncproto.MsgDecoderRestart:
 			if s.options.DisableDecoderRestart {
 				log.Error("Server sent MsgDecoderRestart but we signalled no support.")
@@ -536,14 +621,16 @@ func (s *SyncerClient) loop(cxt context.Context, cancelFn context.CancelFunc) {
 func (s *SyncerClient) restartDecoder(cxt context.Context, logCxt *log.Entry, msg syncproto.MsgDecoderRestart) error {
 	logCxt.WithField("msg", msg).Info("Server asked us to restart our decoder")
 	// Check if we should enable compression.
-	switch msg.CompressionAlgorithm {
-	case syncproto.CompressionSnappy:
-		logCxt.Info("Server selected snappy compression.")
-		r := snappy.NewReader(s.connR)
-		s.decoder = gob.NewDecoder(r)
-	case "":
+	if msg.CompressionAlgorithm == "" {
 		logCxt.Info("Server selected no compression.")
 		s.decoder = gob.NewDecoder(s.connR)
+	} else {
+		logCxt.WithField("algorithm", msg.CompressionAlgorithm).Info("Server selected compression.")
+		r, err := syncproto.NewDecompressingReader(msg.CompressionAlgorithm, s.connR)
+		if err != nil {
+			return err
+		}
+		s.decoder = gob.NewDecoder(r)
 	}
 	// Server requires an ack of the MsgDecoderRestart before it can send data in the new format.
 	err := s.sendMessageToServer(cxt, logCxt, "send ACK to server",
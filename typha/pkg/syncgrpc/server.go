@@ -0,0 +1,362 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syncgrpc provides a gRPC API for Typha's syncer data, for clients that aren't written in Go or that
+// don't want to depend on Calico's internals.  Each stream carries a snapshot of the v3 resources in one syncer's
+// data followed by the updates to them, as seen by Typha's own clients.  See proto/syncer.proto for the API.
+package syncgrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/typha/pkg/syncgrpc/proto"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+	"github.com/projectcalico/calico/typha/pkg/syncserver"
+	"github.com/projectcalico/calico/typha/pkg/tlsutils"
+)
+
+var (
+	gaugeNumStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "typha_grpc_streams",
+		Help: "Number of open gRPC sync streams.",
+	})
+	counterNumStreamsAccepted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "typha_grpc_streams_accepted",
+		Help: "Total number of gRPC sync streams accepted.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(gaugeNumStreams)
+	prometheus.MustRegister(counterNumStreamsAccepted)
+}
+
+const (
+	defaultMaxMessageSize = 100
+	defaultMaxFallBehind  = 300 * time.Second
+
+	v3ResourcePrefix = "/calico/resources/v3/"
+)
+
+type Config struct {
+	// Port is the port to listen on; 0 means "choose a free port".
+	Port int
+	// MaxMessageSize is the maximum number of updates to send in each SyncResponse.
+	MaxMessageSize int
+	// MaxFallBehind is how far behind the latest updates a client may get before it is disconnected.
+	MaxFallBehind time.Duration
+
+	// TLS config, with the same meaning as in syncserver.Config.  If any of these are set, the server requires
+	// TLS and checks each client's certificate.
+	KeyFile      string
+	CertFile     string
+	CAFile       string
+	ClientCN     string
+	ClientURISAN string
+}
+
+func (c *Config) ApplyDefaults() {
+	if c.MaxMessageSize < 1 {
+		log.WithFields(log.Fields{
+			"value":   c.MaxMessageSize,
+			"default": defaultMaxMessageSize,
+		}).Info("Defaulting MaxMessageSize.")
+		c.MaxMessageSize = defaultMaxMessageSize
+	}
+	if c.MaxFallBehind <= 0 {
+		log.WithFields(log.Fields{
+			"value":   c.MaxFallBehind,
+			"default": defaultMaxFallBehind,
+		}).Info("Defaulting MaxFallBehind.")
+		c.MaxFallBehind = defaultMaxFallBehind
+	}
+}
+
+func (c *Config) requiringTLS() bool {
+	// True if any of the TLS parameters are set.  This must match config.Config.requiringTLS().
+	return c.KeyFile+c.CertFile+c.CAFile+c.ClientCN+c.ClientURISAN != ""
+}
+
+// ConnectionTracker accounts for the gRPC streams along with the connections of the main server, so that
+// they count towards its MaxConns and get closed to re-balance load or to shut down.  It is implemented by
+// syncserver.Server.
+type ConnectionTracker interface {
+	TrackExternalConnection(cancel context.CancelFunc) (func(), error)
+}
+
+// Server serves the data from Typha's caches over gRPC.
+type Server struct {
+	config      Config
+	caches      map[syncproto.SyncerType]syncserver.BreadcrumbProvider
+	connTracker ConnectionTracker
+
+	grpcServer *grpc.Server
+	listener   net.Listener
+	nextID     atomic.Uint64
+
+	Finished sync.WaitGroup
+}
+
+func New(
+	caches map[syncproto.SyncerType]syncserver.BreadcrumbProvider,
+	connTracker ConnectionTracker,
+	config Config,
+) *Server {
+	config.ApplyDefaults()
+	log.WithField("config", config).Info("Creating gRPC server")
+	return &Server{
+		config:      config,
+		caches:      caches,
+		connTracker: connTracker,
+	}
+}
+
+// Start opens the listen socket and starts serving in a background goroutine.  The server stops when the
+// context is canceled.
+func (s *Server) Start(cxt context.Context) {
+	logCxt := log.WithField("port", s.config.Port)
+	var opts []grpc.ServerOption
+	if s.config.requiringTLS() {
+		logCxt.Info("Using TLS for gRPC server")
+		tlsConfig, err := tlsutils.NewServerTLSConfig(
+			logCxt,
+			s.config.CertFile,
+			s.config.KeyFile,
+			s.config.CAFile,
+			s.config.ClientCN,
+			s.config.ClientURISAN,
+		)
+		if err != nil {
+			logCxt.WithError(err).Panic("Failed to create TLS config")
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.grpcServer = grpc.NewServer(opts...)
+	proto.RegisterSyncerServer(s.grpcServer, s)
+
+	var err error
+	s.listener, err = net.ListenTCP("tcp", &net.TCPAddr{Port: s.config.Port})
+	if err != nil {
+		logCxt.WithError(err).Panic("Failed to open gRPC listen socket")
+	}
+	logCxt.WithField("addr", s.listener.Addr()).Info("Opened gRPC listen socket")
+
+	s.Finished.Add(2)
+	go func() {
+		defer s.Finished.Done()
+		err := s.grpcServer.Serve(s.listener)
+		if err != nil && cxt.Err() == nil {
+			logCxt.WithError(err).Panic("gRPC server failed")
+		}
+	}()
+	go func() {
+		defer s.Finished.Done()
+		<-cxt.Done()
+		log.Info("Context finished, stopping gRPC server.")
+		// Streams never finish on their own so there's no point in waiting for them with GracefulStop().
+		s.grpcServer.Stop()
+	}()
+}
+
+// Port returns the port that the server is listening on.  Only valid after Start() has been called.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Sync implements the Syncer gRPC service.
+func (s *Server) Sync(req *proto.SyncRequest, stream proto.Syncer_SyncServer) error {
+	logCxt := log.WithFields(log.Fields{
+		"streamID":   s.nextID.Add(1),
+		"syncerType": req.SyncerType,
+		"hostname":   req.Hostname,
+		"info":       req.Info,
+	})
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		logCxt = logCxt.WithField("client", p.Addr)
+	}
+	cache, ok := s.caches[syncproto.SyncerType(req.SyncerType)]
+	if !ok {
+		logCxt.Info("Client requested unknown syncer type.")
+		return status.Errorf(codes.InvalidArgument, "unknown syncer type %q", req.SyncerType)
+	}
+
+	// The stream's context is canceled when the client goes away, ours is also canceled when the main
+	// server closes the stream.
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	untrack, err := s.connTracker.TrackExternalConnection(cancel)
+	if err != nil {
+		logCxt.WithError(err).Info("Rejecting gRPC sync stream.")
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer untrack()
+
+	logCxt.Info("New gRPC sync stream.")
+	counterNumStreamsAccepted.Inc()
+	gaugeNumStreams.Inc()
+	defer gaugeNumStreams.Dec()
+
+	w := &streamWriter{
+		stream:         stream,
+		kinds:          map[string]bool{},
+		maxMessageSize: s.config.MaxMessageSize,
+	}
+	for _, kind := range req.Kinds {
+		w.kinds[kind] = true
+	}
+
+	// Send the snapshot from the current Breadcrumb, then follow the Breadcrumbs, sending their deltas.
+	breadcrumb := cache.CurrentBreadcrumb()
+	breadcrumb.KVs.Ascend(func(upd syncproto.SerializedUpdate) bool {
+		return w.add(upd) == nil
+	})
+	if w.err != nil {
+		logCxt.WithError(w.err).Info("Failed to send snapshot.")
+		return w.err
+	}
+	if err := w.flush(breadcrumb.SyncStatus); err != nil {
+		logCxt.WithError(err).Info("Failed to send snapshot.")
+		return err
+	}
+	logCxt.WithField("numKeys", breadcrumb.KVs.Len()).Info("Finished sending snapshot to client.")
+
+	for {
+		breadcrumb, err = breadcrumb.Next(ctx)
+		if err != nil {
+			if stream.Context().Err() == nil {
+				logCxt.Info("gRPC sync stream closed by the server.")
+				return status.Error(codes.Unavailable, "stream closed by the server")
+			}
+			logCxt.WithError(err).Info("Getting next Breadcrumb canceled by context.")
+			return status.FromContextError(err).Err()
+		}
+		if crumbAge := cache.CurrentBreadcrumb().Timestamp.Sub(breadcrumb.Timestamp); crumbAge > s.config.MaxFallBehind {
+			logCxt.WithField("snapAge", crumbAge).Warn("Client fell behind. Disconnecting.")
+			return status.Error(codes.Aborted, "client fell behind")
+		}
+		for _, upd := range breadcrumb.Deltas {
+			if err := w.add(upd); err != nil {
+				logCxt.WithError(err).Info("Failed to send to client.")
+				return err
+			}
+		}
+		if err := w.flush(breadcrumb.SyncStatus); err != nil {
+			logCxt.WithError(err).Info("Failed to send to client.")
+			return err
+		}
+	}
+}
+
+// streamWriter batches up updates for a stream, filtering them by kind.  Only v3 resources are sent, the
+// other keys of the syncers are in Calico's internal data model, which is not part of the API.
+type streamWriter struct {
+	stream         proto.Syncer_SyncServer
+	kinds          map[string]bool
+	maxMessageSize int
+
+	updates    []*proto.Update
+	sentStatus bool
+	lastStatus api.SyncStatus
+	err        error
+}
+
+// add converts the update and adds it to the batch, sending the batch if it is full.
+func (w *streamWriter) add(upd syncproto.SerializedUpdate) error {
+	u := convertUpdate(upd)
+	if u == nil || (len(w.kinds) > 0 && !w.kinds[u.Kind]) {
+		return nil
+	}
+	w.updates = append(w.updates, u)
+	if len(w.updates) >= w.maxMessageSize {
+		w.err = w.stream.Send(&proto.SyncResponse{Updates: w.updates})
+		w.updates = nil
+	}
+	return w.err
+}
+
+// flush sends any batched updates along with the sync status, if it has changed.
+func (w *streamWriter) flush(syncStatus api.SyncStatus) error {
+	resp := &proto.SyncResponse{Updates: w.updates}
+	if !w.sentStatus || syncStatus != w.lastStatus {
+		resp.Status = convertSyncStatus(syncStatus)
+	}
+	if len(resp.Updates) == 0 && resp.Status == proto.SyncStatus_SYNC_STATUS_UNCHANGED {
+		return nil
+	}
+	w.updates = nil
+	w.err = w.stream.Send(resp)
+	if w.err == nil {
+		w.sentStatus = true
+		w.lastStatus = syncStatus
+	}
+	return w.err
+}
+
+// convertUpdate converts the update of a v3 resource to its API form; it returns nil for other keys.
+func convertUpdate(upd syncproto.SerializedUpdate) *proto.Update {
+	if !strings.HasPrefix(upd.Key, v3ResourcePrefix) {
+		return nil
+	}
+	key, ok := model.KeyFromDefaultPath(upd.Key).(model.ResourceKey)
+	if !ok {
+		return nil
+	}
+	u := &proto.Update{
+		Key:       upd.Key,
+		Kind:      key.Kind,
+		Namespace: key.Namespace,
+		Name:      key.Name,
+		Value:     upd.Value,
+	}
+	switch upd.UpdateType {
+	case api.UpdateTypeKVNew:
+		u.Type = proto.UpdateType_NEW
+	case api.UpdateTypeKVUpdated:
+		u.Type = proto.UpdateType_UPDATED
+	case api.UpdateTypeKVDeleted:
+		u.Type = proto.UpdateType_DELETED
+	}
+	if upd.Revision != nil {
+		u.Revision = fmt.Sprint(upd.Revision)
+	}
+	return u
+}
+
+func convertSyncStatus(syncStatus api.SyncStatus) proto.SyncStatus {
+	switch syncStatus {
+	case api.WaitForDatastore:
+		return proto.SyncStatus_WAIT_FOR_DATASTORE
+	case api.ResyncInProgress:
+		return proto.SyncStatus_RESYNC_IN_PROGRESS
+	case api.InSync:
+		return proto.SyncStatus_IN_SYNC
+	}
+	return proto.SyncStatus_SYNC_STATUS_UNCHANGED
+}
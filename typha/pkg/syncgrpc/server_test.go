@@ -0,0 +1,282 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/typha/pkg/snapcache"
+	"github.com/projectcalico/calico/typha/pkg/syncgrpc/proto"
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+	"github.com/projectcalico/calico/typha/pkg/syncserver"
+	"github.com/projectcalico/calico/typha/pkg/tlsutils"
+)
+
+func ipPoolUpdate(name, cidr string) api.Update {
+	pool := apiv3.NewIPPool()
+	pool.Name = name
+	pool.Spec.CIDR = cidr
+	return api.Update{
+		KVPair: model.KVPair{
+			Key:      model.ResourceKey{Kind: apiv3.KindIPPool, Name: name},
+			Value:    pool,
+			Revision: "1",
+		},
+		UpdateType: api.UpdateTypeKVNew,
+	}
+}
+
+func configUpdate(name, value string) api.Update {
+	return api.Update{
+		KVPair:     model.KVPair{Key: model.GlobalConfigKey{Name: name}, Value: value, Revision: "2"},
+		UpdateType: api.UpdateTypeKVNew,
+	}
+}
+
+func startServer(t *testing.T, config Config) (*snapcache.Cache, *Server) {
+	cache, _, s := startServers(t, config)
+	return cache, s
+}
+
+// startServers starts a gRPC server along with the main server that accounts for its streams, the main
+// server doesn't listen.
+func startServers(t *testing.T, config Config) (*snapcache.Cache, *syncserver.Server, *Server) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cache := snapcache.New(snapcache.Config{WakeUpInterval: 10 * time.Millisecond})
+	cache.Start(ctx)
+	caches := map[syncproto.SyncerType]syncserver.BreadcrumbProvider{syncproto.SyncerTypeFelix: cache}
+	mainServer := syncserver.New(caches, syncserver.Config{})
+	s := New(caches, mainServer, config)
+	s.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		s.Finished.Wait()
+	})
+	return cache, mainServer, s
+}
+
+func dial(t *testing.T, s *Server, creds credentials.TransportCredentials) proto.SyncerClient {
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", s.Port()), grpc.WithTransportCredentials(creds))
+	Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return proto.NewSyncerClient(conn)
+}
+
+// recv reads responses from the stream until it has seen the given sync status, returning the updates.
+func recv(stream proto.Syncer_SyncClient, until proto.SyncStatus) (updates []*proto.Update) {
+	for {
+		resp, err := stream.Recv()
+		Expect(err).NotTo(HaveOccurred())
+		updates = append(updates, resp.Updates...)
+		if resp.Status == until {
+			return
+		}
+	}
+}
+
+func TestSnapshotAndDeltas(t *testing.T) {
+	RegisterTestingT(t)
+
+	cache, s := startServer(t, Config{MaxMessageSize: 2})
+	cache.OnStatusUpdated(api.ResyncInProgress)
+	cache.OnUpdates([]api.Update{
+		ipPoolUpdate("pool-1", "10.0.0.0/16"),
+		ipPoolUpdate("pool-2", "10.1.0.0/16"),
+		configUpdate("LogSeverityScreen", "Info"),
+	})
+	cache.OnStatusUpdated(api.InSync)
+	Eventually(func() api.SyncStatus {
+		return cache.CurrentBreadcrumb().SyncStatus
+	}).Should(Equal(api.InSync))
+
+	client := dial(t, s, insecure.NewCredentials())
+	stream, err := client.Sync(context.Background(), &proto.SyncRequest{SyncerType: "felix", Hostname: "test"})
+	Expect(err).NotTo(HaveOccurred())
+	updates := recv(stream, proto.SyncStatus_IN_SYNC)
+	// The config update isn't a v3 resource so it isn't sent.
+	Expect(updates).To(HaveLen(2))
+	Expect(updates[0].Key).To(Equal("/calico/resources/v3/projectcalico.org/ippools/pool-1"))
+	Expect(updates[0].Kind).To(Equal(apiv3.KindIPPool))
+	Expect(updates[0].Name).To(Equal("pool-1"))
+	Expect(updates[0].Revision).To(Equal("1"))
+	Expect(updates[0].Type).To(Equal(proto.UpdateType_NEW))
+	Expect(string(updates[0].Value)).To(ContainSubstring(`"cidr":"10.0.0.0/16"`))
+	Expect(updates[1].Name).To(Equal("pool-2"))
+
+	cache.OnUpdates([]api.Update{{
+		KVPair:     model.KVPair{Key: model.ResourceKey{Kind: apiv3.KindIPPool, Name: "pool-1"}},
+		UpdateType: api.UpdateTypeKVDeleted,
+	}})
+	resp, err := stream.Recv()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.Status).To(Equal(proto.SyncStatus_SYNC_STATUS_UNCHANGED))
+	Expect(resp.Updates).To(HaveLen(1))
+	Expect(resp.Updates[0].Type).To(Equal(proto.UpdateType_DELETED))
+	Expect(resp.Updates[0].Name).To(Equal("pool-1"))
+	Expect(resp.Updates[0].Value).To(BeEmpty())
+}
+
+func TestKindsFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	cache, s := startServer(t, Config{})
+	cache.OnUpdates([]api.Update{
+		ipPoolUpdate("pool-1", "10.0.0.0/16"),
+		configUpdate("LogSeverityScreen", "Info"),
+	})
+	cache.OnStatusUpdated(api.InSync)
+	Eventually(func() api.SyncStatus {
+		return cache.CurrentBreadcrumb().SyncStatus
+	}).Should(Equal(api.InSync))
+
+	client := dial(t, s, insecure.NewCredentials())
+	stream, err := client.Sync(context.Background(), &proto.SyncRequest{SyncerType: "felix", Kinds: []string{apiv3.KindIPPool}})
+	Expect(err).NotTo(HaveOccurred())
+	updates := recv(stream, proto.SyncStatus_IN_SYNC)
+	Expect(updates).To(HaveLen(1))
+	Expect(updates[0].Name).To(Equal("pool-1"))
+
+	// Updates of other kinds shouldn't be sent at all.
+	cache.OnUpdates([]api.Update{configUpdate("LogSeverityFile", "Debug")})
+	cache.OnUpdates([]api.Update{ipPoolUpdate("pool-2", "10.1.0.0/16")})
+	resp, err := stream.Recv()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.Updates).To(HaveLen(1))
+	Expect(resp.Updates[0].Name).To(Equal("pool-2"))
+}
+
+func TestConnectionAccounting(t *testing.T) {
+	RegisterTestingT(t)
+
+	cache, mainServer, s := startServers(t, Config{})
+	cache.OnStatusUpdated(api.InSync)
+	Eventually(func() api.SyncStatus {
+		return cache.CurrentBreadcrumb().SyncStatus
+	}).Should(Equal(api.InSync))
+
+	client := dial(t, s, insecure.NewCredentials())
+	stream, err := client.Sync(context.Background(), &proto.SyncRequest{SyncerType: "felix"})
+	Expect(err).NotTo(HaveOccurred())
+	recv(stream, proto.SyncStatus_IN_SYNC)
+	Expect(mainServer.NumActiveConnections()).To(Equal(1))
+
+	// The main server may close the stream, for example, to re-balance load.
+	Expect(mainServer.TerminateRandomConnection(log.WithField("test", t.Name()), "test")).To(BeTrue())
+	_, err = stream.Recv()
+	Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	Eventually(mainServer.NumActiveConnections).Should(BeZero())
+
+	// No new streams once the main server is shutting down.
+	mainServer.ShutDownGracefully()
+	stream, err = client.Sync(context.Background(), &proto.SyncRequest{SyncerType: "felix"})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = stream.Recv()
+	Expect(status.Code(err)).To(Equal(codes.Unavailable))
+	Expect(mainServer.NumActiveConnections()).To(BeZero())
+}
+
+func TestUnknownSyncerType(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, s := startServer(t, Config{})
+	client := dial(t, s, insecure.NewCredentials())
+	stream, err := client.Sync(context.Background(), &proto.SyncRequest{SyncerType: "bgp"})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = stream.Recv()
+	Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+}
+
+func TestTLSClientChecks(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir := t.TempDir()
+	caCert, caKey := tlsutils.MakeCACert("typha-ca")
+	tlsutils.WriteCert(caCert.Raw, filepath.Join(dir, "ca.crt"))
+	serverCert, serverKey := tlsutils.MakePeerCert("typha-server", "", x509.ExtKeyUsageServerAuth, caCert, caKey)
+	tlsutils.WriteCert(serverCert, filepath.Join(dir, "server.crt"))
+	tlsutils.WriteKey(serverKey, filepath.Join(dir, "server.key"))
+
+	cache, s := startServer(t, Config{
+		KeyFile:      filepath.Join(dir, "server.key"),
+		CertFile:     filepath.Join(dir, "server.crt"),
+		CAFile:       filepath.Join(dir, "ca.crt"),
+		ClientCN:     "my-controller",
+		ClientURISAN: "spiffe://k8s.example.com/my-controller",
+	})
+	cache.OnStatusUpdated(api.InSync)
+	Eventually(func() api.SyncStatus {
+		return cache.CurrentBreadcrumb().SyncStatus
+	}).Should(Equal(api.InSync))
+
+	clientCreds := func(cn, uriSAN string) credentials.TransportCredentials {
+		certDER, key := tlsutils.MakePeerCert(cn, uriSAN, x509.ExtKeyUsageClientAuth, caCert, caKey)
+		certFile := filepath.Join(dir, fmt.Sprintf("client-%s.crt", cn))
+		keyFile := filepath.Join(dir, fmt.Sprintf("client-%s.key", cn))
+		tlsutils.WriteCert(certDER, certFile)
+		tlsutils.WriteKey(key, keyFile)
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		Expect(err).NotTo(HaveOccurred())
+		// As in the Typha client, verify the server's CN rather than its hostname.
+		roots := x509.NewCertPool()
+		roots.AddCert(caCert)
+		return credentials.NewTLS(&tls.Config{
+			Certificates:          []tls.Certificate{cert},
+			InsecureSkipVerify:    true,
+			VerifyPeerCertificate: tlsutils.CertificateVerifier(log.WithField("test", t.Name()), roots, "typha-server", ""),
+		})
+	}
+
+	for _, c := range []struct {
+		cn, uriSAN string
+		allowed    bool
+	}{
+		{"my-controller", "", true},
+		{"someone-else", "spiffe://k8s.example.com/my-controller", true},
+		{"someone-else", "", false},
+	} {
+		client := dial(t, s, clientCreds(c.cn, c.uriSAN))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		stream, err := client.Sync(ctx, &proto.SyncRequest{SyncerType: "felix"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		cancel()
+		if c.allowed {
+			Expect(err).NotTo(HaveOccurred(), "client with CN %q should be allowed", c.cn)
+		} else {
+			Expect(status.Code(err)).To(Equal(codes.Unavailable), "client with CN %q should be rejected", c.cn)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncproto
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressingWriter is a Writer that compresses the data written to it.  Flush writes out all the data written
+// so far.  Note: when the encoding is restarted, the old CompressingWriter should simply be flushed and then
+// discarded rather than closed; closing a zstd stream writes a trailer that the client wouldn't consume.
+type CompressingWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// NewCompressingWriter returns a CompressingWriter that writes to w using the given algorithm.
+func NewCompressingWriter(alg CompressionAlgorithm, w io.Writer) (CompressingWriter, error) {
+	switch alg {
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w,
+			// Typha has an encoder per connection so we trade some compression for lower memory usage.
+			// Using a single goroutine also means that each Flush writes synchronously to w.
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithEncoderLevel(zstd.SpeedFastest),
+			zstd.WithWindowSize(zstdWindowSize),
+			zstd.WithLowerEncoderMem(true),
+			zstd.WithEncoderDictRaw(zstdDictID, zstdDict),
+		)
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %q", alg)
+	}
+}
+
+// NewDecompressingReader returns a Reader that decompresses the data from r using the given algorithm.  The
+// Reader doesn't read ahead from r beyond the data that it has been asked for, so that r can be handed to
+// a new Reader when the decoder is restarted.
+func NewDecompressingReader(alg CompressionAlgorithm, r io.Reader) (io.Reader, error) {
+	switch alg {
+	case CompressionSnappy:
+		return snappy.NewReader(r), nil
+	case CompressionZstd:
+		// In single-goroutine mode, the zstd decoder only reads the blocks that it needs to satisfy each read.
+		// It has no background goroutines so there's no need to Close it.
+		return zstd.NewReader(r,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderLowmem(true),
+			zstd.WithDecoderMaxWindow(zstdWindowSize),
+			zstd.WithDecoderDictRaw(zstdDictID, zstdDict),
+		)
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %q", alg)
+	}
+}
+
+const (
+	zstdWindowSize = 256 * 1024
+
+	// zstdDictID identifies the dictionary in each zstd frame.  The content of the dictionary must never change
+	// without also changing its ID and the name of the compression algorithm; the client and server must have
+	// exactly the same dictionary.
+	zstdDictID = 0x74797031
+)
+
+// zstdDict is the shared dictionary used by CompressionZstd.  It primes the compressor with fragments that are
+// common in the protocol's gob stream: the gob type descriptors of our messages, KV keys and the JSON field
+// names and values of commonly-sent resources.  zstd finds matches at smaller offsets more cheaply so the
+// most common fragments come last.
+var zstdDict = []byte(strings.Join([]string{
+	// Rarer resources.
+	`/calico/resources/v3/projectcalico.org/bgpconfigurations/default`,
+	`/calico/resources/v3/projectcalico.org/bgppeers/`,
+	`/calico/resources/v3/projectcalico.org/ippools/`,
+	`/calico/resources/v3/projectcalico.org/kubecontrollersconfigurations/default`,
+	`/calico/resources/v3/projectcalico.org/clusterinformations/default`,
+	`/calico/resources/v3/projectcalico.org/felixconfigurations/`,
+	`/calico/resources/v3/projectcalico.org/nodes/`,
+	`/calico/resources/v3/projectcalico.org/networksets/`,
+	`/calico/resources/v3/projectcalico.org/globalnetworksets/`,
+	`/calico/v1/config/`,
+	`/calico/v1/host/`,
+	`/bird_ip`,
+	`/calico/ipam/v2/host/`,
+	`/calico/ipam/v2/assignment/ipv4/block/`,
+	`/calico/ipam/v2/assignment/ipv6/block/`,
+	`{"cidr":"`,
+	`"affinity":"host:`,
+	`"allocations":[`,
+	`"unallocated":[`,
+	`"attributes":[{"handle_id":"`,
+	`"strictAffinity":false,"deleted":false}`,
+	`{"metadata":{"name":"`,
+	`","uid":"`,
+	`","resourceVersion":"`,
+	`","creationTimestamp":"`,
+	`"annotations":{`,
+	`"labels":{"kubernetes.io/arch":"amd64","kubernetes.io/hostname":"`,
+	`","kubernetes.io/os":"linux"`,
+	`"spec":{"bgp":{"ipv4Address":"`,
+	`"orchRefs":[{"nodeName":"`,
+	`","orchestrator":"k8s"}]`,
+	`"addresses":[{"address":"`,
+	`","type":"InternalIP"}`,
+	`"status":{}}`,
+
+	// Policies and profiles.
+	`/calico/v1/policy/tier/default/policy/`,
+	`/calico/v1/policy/profile/`,
+	`/calico/resources/v3/projectcalico.org/profiles/kns.`,
+	`/calico/resources/v3/projectcalico.org/profiles/ksa.`,
+	`{"metadata":{"creationTimestamp":null},"spec":{"ingress":[{"action":"Allow","source":{},"destination":{}}],` +
+		`"egress":[{"action":"Allow","source":{},"destination":{}}],"labelsToApply":{"pcns.`,
+	`"labelsToApply":{"pcsa.projectcalico.org/name":"`,
+	`"inbound_rules":[{"action":"allow","protocol":"`,
+	`"outbound_rules":[{"action":"allow","protocol":"`,
+	`"src_selector":"`,
+	`"dst_selector":"`,
+	`"dst_ports":[`,
+	`"src_net":"`,
+	`"dst_net":"`,
+	`"selector":"projectcalico.org/namespace == '`,
+	`"namespace_selector":"`,
+	`"order":1000,`,
+	`"types":["ingress","egress"],`,
+	`"untracked":false,"pre_dnat":false,"apply_on_forward":false,`,
+	`"outbound_rules":[],"inbound_rules":[],`,
+
+	// Workload endpoints, which make up the bulk of the data in a large cluster.
+	`{"state":"active","name":"cali`,
+	`","active_instance_id":"","mac":"`,
+	`"mac":null,`,
+	`"profile_ids":["kns.default","ksa.default.default"],`,
+	`"ipv4_nets":["`,
+	`/32"],"ipv6_nets":null,`,
+	`"ports":[{"name":"`,
+	`","protocol":"TCP","port":`,
+	`"labels":{"app":"`,
+	`"app.kubernetes.io/name":"`,
+	`"pod-template-hash":"`,
+	`"projectcalico.org/namespace":"`,
+	`","projectcalico.org/orchestrator":"k8s",`,
+	`"projectcalico.org/serviceaccount":"default"}}`,
+	`/workload/k8s/`,
+	`/endpoint/eth0`,
+
+	// The gob type descriptors that start each stream.
+	`Envelope`,
+	`Message`,
+	`MsgDecoderRestart`,
+	`CompressionAlgorithm`,
+	`MsgSyncStatus`,
+	`SyncStatus`,
+	`MsgPing`,
+	`Timestamp`,
+	`ResumeToken`,
+	`CacheID`,
+	`SequenceNumber`,
+	`Fingerprint`,
+	`github.com/projectcalico/calico/typha/pkg/syncproto.MsgKVs`,
+	`SerializedUpdate`,
+	`Key`,
+	`Value`,
+	`Revision`,
+	`V3ResourceVersion`,
+	`TTL`,
+	`UpdateType`,
+	`KVs`,
+}, ""))
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncproto
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	calinet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// countingReader hides the methods of the underlying reader (so that the decompressor can't tell that it's a
+// bytes.Buffer) and counts the bytes read.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestCompressedStreamRestart(t *testing.T) {
+	for _, alg := range []CompressionAlgorithm{CompressionSnappy, CompressionZstd} {
+		t.Run(string(alg), func(t *testing.T) {
+			RegisterTestingT(t)
+
+			var kvs []SerializedUpdate
+			for i := 0; i < 100; i++ {
+				su, err := SerializeUpdate(api.Update{
+					KVPair: model.KVPair{
+						Key: model.WorkloadEndpointKey{
+							Hostname:       "node-1",
+							OrchestratorID: "k8s",
+							WorkloadID:     fmt.Sprintf("default/pod-%d", i),
+							EndpointID:     "eth0",
+						},
+						Value: &model.WorkloadEndpoint{
+							State:      "active",
+							Name:       fmt.Sprintf("cali%011d", i),
+							ProfileIDs: []string{"kns.default", "ksa.default.default"},
+							IPv4Nets:   []calinet.IPNet{calinet.MustParseNetwork(fmt.Sprintf("10.65.0.%d/32", i))},
+						},
+						Revision: fmt.Sprint(i),
+					},
+					UpdateType: api.UpdateTypeKVNew,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				kvs = append(kvs, su)
+			}
+
+			// Write a compressed stream that ends with a MsgDecoderRestart, then flush it and abandon it, as the
+			// server does.
+			var buf bytes.Buffer
+			w, err := NewCompressingWriter(alg, &buf)
+			Expect(err).NotTo(HaveOccurred())
+			enc := gob.NewEncoder(w)
+			Expect(enc.Encode(&Envelope{Message: MsgKVs{KVs: kvs}})).To(Succeed())
+			Expect(enc.Encode(&Envelope{Message: MsgDecoderRestart{Message: "restart"}})).To(Succeed())
+			Expect(w.Flush()).To(Succeed())
+			Expect(buf.Len()).To(BeNumerically("<", len(kvs)*len(kvs[0].Value)/2))
+
+			// The client should decode both messages and consume exactly the compressed stream; anything left
+			// over, or read past the end, would be misinterpreted by the client's next decoder.
+			compressedLen := buf.Len()
+			buf.WriteString("data for the next decoder")
+			cr := &countingReader{r: bytes.NewReader(buf.Bytes())}
+			r, err := NewDecompressingReader(alg, cr)
+			Expect(err).NotTo(HaveOccurred())
+			dec := gob.NewDecoder(r)
+			var env Envelope
+			Expect(dec.Decode(&env)).To(Succeed())
+			Expect(env.Message.(MsgKVs).KVs).To(HaveLen(len(kvs)))
+			Expect(env.Message.(MsgKVs).KVs[99].Key).To(Equal(kvs[99].Key))
+			Expect(dec.Decode(&env)).To(Succeed())
+			Expect(env.Message).To(Equal(MsgDecoderRestart{Message: "restart"}))
+			Expect(cr.n).To(Equal(compressedLen))
+		})
+	}
+}
+
+func TestUnknownCompressionAlgorithm(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewCompressingWriter("lzma", io.Discard)
+	Expect(err).To(HaveOccurred())
+	_, err = NewDecompressingReader("lzma", &bytes.Buffer{})
+	Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncproto
+
+import (
+	"fmt"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+// CondenseWorkloadEndpoint returns a copy of the WorkloadEndpoint with only the fields that Felix
+// uses for the endpoints of other nodes: the name, which is required by validation, and the fields
+// that feed IP set membership and route calculation.  The source spoofing prefixes are retained
+// because Felix treats endpoints that request spoofing as invalid if it doesn't allow it.
+func CondenseWorkloadEndpoint(wep *model.WorkloadEndpoint) *model.WorkloadEndpoint {
+	return &model.WorkloadEndpoint{
+		Name:                       wep.Name,
+		ProfileIDs:                 wep.ProfileIDs,
+		IPv4Nets:                   wep.IPv4Nets,
+		IPv6Nets:                   wep.IPv6Nets,
+		Labels:                     wep.Labels,
+		Ports:                      wep.Ports,
+		AllowSpoofedSourcePrefixes: wep.AllowSpoofedSourcePrefixes,
+	}
+}
+
+// WorkloadEndpointKeyPrefix returns the prefix of the serialized keys of the WorkloadEndpoints of the
+// given node.
+func WorkloadEndpointKeyPrefix(nodeName string) string {
+	return fmt.Sprintf("/calico/v1/host/%s/workload/", nodeName)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncproto
+
+import (
+	"bytes"
+	"encoding/gob"
+	gonet "net"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+var (
+	wepKey = model.WorkloadEndpointKey{
+		Hostname:       "node-1",
+		OrchestratorID: "k8s",
+		WorkloadID:     "default/pod-1",
+		EndpointID:     "eth0",
+	}
+	wep = &model.WorkloadEndpoint{
+		State:      "active",
+		Name:       "cali1234",
+		Mac:        &net.MAC{HardwareAddr: gonet.HardwareAddr{1, 2, 3, 4, 5, 6}},
+		ProfileIDs: []string{"kns.default"},
+		IPv4Nets:   []net.IPNet{net.MustParseCIDR("10.0.0.1/32")},
+		Labels:     map[string]string{"app": "web"},
+	}
+)
+
+func TestCondenseWorkloadEndpoint(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(CondenseWorkloadEndpoint(wep)).To(Equal(&model.WorkloadEndpoint{
+		Name:       "cali1234",
+		ProfileIDs: []string{"kns.default"},
+		IPv4Nets:   []net.IPNet{net.MustParseCIDR("10.0.0.1/32")},
+		Labels:     map[string]string{"app": "web"},
+	}))
+}
+
+func TestSerializedUpdateForNode(t *testing.T) {
+	RegisterTestingT(t)
+
+	su, err := SerializeUpdate(api.Update{
+		KVPair:     model.KVPair{Key: wepKey, Value: wep, Revision: "1"},
+		UpdateType: api.UpdateTypeKVNew,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(su.NodeName()).To(Equal("node-1"))
+	Expect(strings.HasPrefix(su.Key, WorkloadEndpointKeyPrefix("node-1"))).To(BeTrue())
+
+	Expect(su.ForNode("node-1")).To(Equal(su))
+	upd, err := su.ForNode("node-2").ToUpdate()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(upd.Value).To(Equal(CondenseWorkloadEndpoint(wep)))
+	Expect(upd.Key).To(Equal(wepKey))
+
+	// Other resources are the same for every node.
+	su, err = SerializeUpdate(api.Update{
+		KVPair:     model.KVPair{Key: model.HostIPKey{Hostname: "node-1"}, Value: net.ParseIP("10.0.0.1")},
+		UpdateType: api.UpdateTypeKVNew,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(su.ForNode("node-2")).To(Equal(su))
+}
+
+func TestSerializedUpdateSentWithoutCondensedValue(t *testing.T) {
+	RegisterTestingT(t)
+
+	su, err := SerializeUpdate(api.Update{
+		KVPair:     model.KVPair{Key: wepKey, Value: wep, Revision: "1"},
+		UpdateType: api.UpdateTypeKVNew,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	var b bytes.Buffer
+	Expect(gob.NewEncoder(&b).Encode(Envelope{Message: MsgKVs{KVs: []SerializedUpdate{su}}})).To(Succeed())
+	var env Envelope
+	Expect(gob.NewDecoder(&b).Decode(&env)).To(Succeed())
+
+	received := env.Message.(MsgKVs).KVs[0]
+	Expect(received.NodeName()).To(BeEmpty())
+	Expect(received.Value).To(Equal(su.Value))
+	Expect(received.Condensed().Value).To(Equal(su.Value))
+}
@@ -34,6 +34,29 @@
 // After the initial snapshot is sent, Typha sends KVs and SyncStatus messages
 // as new updates are received from the datastore.
 //
+// # Node-scoped views
+//
+// A client may ask for a view of the datastore scoped to its node by setting ScopeToNode in its
+// ClientHello.  If Typha supports that, it sets ScopedToNode in the ServerHello and then sends the
+// WorkloadEndpoints of other nodes in their condensed form (see CondenseWorkloadEndpoint), with only
+// the fields needed to calculate IP set memberships and routes.  The client's own WorkloadEndpoints
+// are sent in full.  Since the snapshot may be shared with other clients, Typha may send the condensed
+// form of the client's own WorkloadEndpoints first, followed by their full form.
+//
+// # Resuming a session
+//
+// If the client signals SupportsResume, Typha attaches a ResumeToken to the KVs
+// message that completes the snapshot (sending an empty KVs message if need be)
+// and to each subsequent KVs message.  The token identifies the state that the
+// client will have reached once it has applied that message.  If the connection
+// is lost, the client can present its most recent token in the ClientHello of a
+// new connection, to the same or to another Typha.  If that Typha's cache still
+// retains a Breadcrumb with the same ID and sequence number, or one with the same
+// fingerprint, it replies with a ServerHello with Resumed set and skips the snapshot,
+// sending only the deltas since that Breadcrumb.  Otherwise, it sends the snapshot
+// as normal and it is up to the client to reconcile (or to give up on) the state
+// it already has.
+//
 //	+-------+                +-------+
 //	| Felix |                | Typha |
 //	+-------+                +-------+
@@ -83,6 +106,15 @@
 //	|<-----------------------|
 //	|                        |
 //
+// # Compression
+//
+// The client lists the compression algorithms that it supports in its ClientHello and the server
+// picks one of them (according to its own preference) when it restarts the client's decoder.  The
+// zstd algorithm uses a dictionary that primes the compressor with the fragments that are common in
+// the protocol; it compresses the highly repetitive stream of KVs better than snappy, at some CPU
+// cost.  When the encoding is restarted, the old compressed stream is flushed and abandoned rather
+// than closed, so that it ends exactly after the MsgDecoderRestart.
+//
 // # Wire format
 //
 // The protocol uses gob to encode messages.  Each message is wrapped in an Envelope
@@ -177,6 +209,7 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"reflect"
 	"strings"
 	"time"
@@ -244,6 +277,8 @@ type CompressionAlgorithm string
 
 const (
 	CompressionSnappy CompressionAlgorithm = "snappy"
+	// CompressionZstd is zstd compression, using a dictionary that is shared by the client and server.
+	CompressionZstd CompressionAlgorithm = "zstd"
 )
 
 // MsgClientHello is the first message sent by the client after it opens the connection.  It begins the handshake.
@@ -261,6 +296,15 @@ type MsgClientHello struct {
 	SupportedCompressionAlgorithms []CompressionAlgorithm
 
 	ClientConnID uint64
+
+	// SupportsResume tells the server that the client tracks the ResumeTokens attached to MsgKVs.
+	SupportsResume bool
+	// ResumeToken, if set, asks the server to resume the session from the state identified by the token
+	// instead of sending a snapshot.
+	ResumeToken *ResumeToken
+
+	// ScopeToNode, if set, asks the server for a view of the datastore scoped to the given node.
+	ScopeToNode string
 }
 
 // MsgServerHello is the server's response to MsgClientHello.
@@ -275,6 +319,24 @@ type MsgServerHello struct {
 	SupportsNodeResourceUpdates bool
 
 	ServerConnID uint64
+
+	// Resumed is set if the server accepted the client's ResumeToken; it will send only the deltas from
+	// the state identified by the token rather than a snapshot.
+	Resumed bool
+
+	// ScopedToNode is set if the server honours the client's ScopeToNode request.
+	ScopedToNode bool
+}
+
+// ResumeToken identifies a state of the datastore, as held by a Breadcrumb of the server's cache.
+type ResumeToken struct {
+	// CacheID is the random ID of the server's cache; sequence numbers are only meaningful within the
+	// same cache.
+	CacheID        string
+	SequenceNumber uint64
+	// Fingerprint is a hash of all the KVs in the state, see SerializedUpdate.Fingerprint.  It allows for
+	// resuming from an equivalent state in another cache, such as that of another Typha.
+	Fingerprint uint64
 }
 
 // MsgDecoderRestart is sent (currently only from server to client) to tell it to restart its decoder with new
@@ -300,6 +362,10 @@ type MsgPong struct {
 }
 type MsgKVs struct {
 	KVs []SerializedUpdate
+
+	// ResumeToken is only sent to clients that signalled SupportsResume.  It identifies the state that the
+	// client reaches once it has applied the KVs.
+	ResumeToken *ResumeToken
 }
 
 func (m MsgKVs) String() string {
@@ -370,6 +436,22 @@ func SerializeUpdate(u api.Update) (su SerializedUpdate, err error) {
 	}
 	su.Value = value
 
+	if wepKey, ok := u.Key.(model.WorkloadEndpointKey); ok {
+		su.nodeName = wepKey.Hostname
+		if wep, ok := u.Value.(*model.WorkloadEndpoint); ok {
+			su.condensedValue, err = model.SerializeValue(&model.KVPair{
+				Key:   u.Key,
+				Value: CondenseWorkloadEndpoint(wep),
+			})
+			if err != nil {
+				log.WithError(err).WithField("update", u).Error(
+					"Bug: failed to serialize condensed value, sending the full value instead.")
+				su.condensedValue = nil
+				err = nil
+			}
+		}
+	}
+
 	return
 }
 
@@ -380,6 +462,34 @@ type SerializedUpdate struct {
 	V3ResourceVersion string
 	TTL               time.Duration
 	UpdateType        api.UpdateType
+
+	// nodeName is the node that the resource belongs to, if it's a node-scoped resource.  Unexported
+	// fields are not sent over the wire.
+	nodeName string
+	// condensedValue is the serialized, condensed form of the value, for resources that have one.
+	condensedValue []byte
+}
+
+// NodeName returns the node that the resource belongs to, or "" if the resource isn't node-scoped.
+func (s SerializedUpdate) NodeName() string {
+	return s.nodeName
+}
+
+// Condensed returns the update with its value replaced by the condensed form, if it has one.
+func (s SerializedUpdate) Condensed() SerializedUpdate {
+	if s.condensedValue != nil {
+		s.Value = s.condensedValue
+	}
+	return s
+}
+
+// ForNode returns the update as seen by a client with a view scoped to the given node: resources of
+// other nodes are condensed.
+func (s SerializedUpdate) ForNode(nodeName string) SerializedUpdate {
+	if s.nodeName == nodeName {
+		return s
+	}
+	return s.Condensed()
 }
 
 var ErrBadKey = errors.New("Unable to parse key.")
@@ -446,6 +556,17 @@ func (s SerializedUpdate) WouldBeNoOp(previous SerializedUpdate) bool {
 	return reflect.DeepEqual(s, previous)
 }
 
+// Fingerprint returns a hash of the key and value of the update.  The fingerprint of a set of KVs is the
+// sum of the fingerprints of its members, which allows for maintaining it incrementally.  Revisions are
+// excluded since the same value may be stored with a different revision by different caches.
+func (s SerializedUpdate) Fingerprint() uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s.Key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(s.Value)
+	return h.Sum64()
+}
+
 func (s SerializedUpdate) String() string {
 	return fmt.Sprintf("SerializedUpdate<Key:%s, Value:%s, Revision:%v, TTL:%v, UpdateType:%v>",
 		s.Key, string(s.Value), s.Revision, s.TTL, s.UpdateType)
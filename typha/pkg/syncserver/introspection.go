@@ -0,0 +1,354 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
+)
+
+// The introspection API is a read-only, JSON-over-HTTP view of the server's state, intended for debugging.
+// It is served on the following paths:
+//
+//	/typha/clients        the connected clients and how far behind each one is.
+//	/typha/cache          the contents of a snapshot cache, filtered by the "syncer" and "prefix" query
+//	                      parameters and limited to "limit" KVs.
+//	/typha/update-rates   the rate of updates passing through each syncer type's cache.
+const (
+	IntrospectionClientsPath     = "/typha/clients"
+	IntrospectionCachePath       = "/typha/cache"
+	IntrospectionUpdateRatesPath = "/typha/update-rates"
+
+	defaultCacheDumpLimit = 1000
+)
+
+// ClientInfo describes a client connection in the introspection API.  The fields that come from the client's
+// hello are empty until the handshake has completed.
+type ClientInfo struct {
+	ConnID      uint64    `json:"connID"`
+	RemoteAddr  string    `json:"remoteAddr"`
+	ConnectedAt time.Time `json:"connectedAt"`
+
+	Hostname               string                         `json:"hostname,omitempty"`
+	Info                   string                         `json:"info,omitempty"`
+	Version                string                         `json:"version,omitempty"`
+	SyncerType             syncproto.SyncerType           `json:"syncerType,omitempty"`
+	Compression            syncproto.CompressionAlgorithm `json:"compression,omitempty"`
+	SupportsDecoderRestart bool                           `json:"supportsDecoderRestart"`
+	SupportsResume         bool                           `json:"supportsResume"`
+	ScopeToNode            string                         `json:"scopeToNode,omitempty"`
+
+	// Streaming is true once the client has been sent its snapshot (or resumed its session).  The sequence
+	// numbers are those of the most recent Breadcrumb that the client has been sent and of the latest
+	// Breadcrumb of its syncer type's cache; LagSecs is the difference between their timestamps.
+	Streaming             bool    `json:"streaming"`
+	BreadcrumbSeqNo       uint64  `json:"breadcrumbSeqNo"`
+	LatestBreadcrumbSeqNo uint64  `json:"latestBreadcrumbSeqNo"`
+	LagSecs               float64 `json:"lagSecs"`
+
+	BytesSent uint64 `json:"bytesSent"`
+}
+
+// CacheDump is the result of a snapshot cache query in the introspection API.
+type CacheDump struct {
+	SyncerType      syncproto.SyncerType `json:"syncerType"`
+	BreadcrumbSeqNo uint64               `json:"breadcrumbSeqNo"`
+	SyncStatus      string               `json:"syncStatus"`
+	// NumKVs is the number of KVs in the cache that matched the prefix, which may be more than the
+	// number returned.
+	NumKVs int        `json:"numKVs"`
+	KVs    []CachedKV `json:"kvs"`
+}
+
+type CachedKV struct {
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+	Revision interface{}     `json:"revision,omitempty"`
+}
+
+// SyncerUpdateRates describes the recent activity of a syncer type's cache in the introspection API.
+// The rates are averaged over the last 10s, 1m and 5m.
+type SyncerUpdateRates struct {
+	SyncerType      syncproto.SyncerType `json:"syncerType"`
+	SyncStatus      string               `json:"syncStatus"`
+	BreadcrumbSeqNo uint64               `json:"breadcrumbSeqNo"`
+	NumKVs          int                  `json:"numKVs"`
+	NumClients      int                  `json:"numClients"`
+
+	UpdatesPerSec     map[string]float64 `json:"updatesPerSec"`
+	BreadcrumbsPerSec map[string]float64 `json:"breadcrumbsPerSec"`
+}
+
+var updateRateWindows = []struct {
+	name string
+	secs int
+}{
+	{"10s", 10},
+	{"1m", 60},
+	{"5m", updateRateHistorySecs},
+}
+
+// IntrospectionHandler returns an http.Handler that serves the introspection API.
+func (s *Server) IntrospectionHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(IntrospectionClientsPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.Clients())
+	})
+	mux.HandleFunc(IntrospectionCachePath, func(w http.ResponseWriter, r *http.Request) {
+		syncerType := syncproto.SyncerType(r.URL.Query().Get("syncer"))
+		if syncerType == "" {
+			syncerType = syncproto.SyncerTypeFelix
+		}
+		limit := defaultCacheDumpLimit
+		if l := r.URL.Query().Get("limit"); l != "" {
+			var err error
+			limit, err = strconv.Atoi(l)
+			if err != nil || limit < 0 {
+				http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+		}
+		dump, ok := s.DumpCache(syncerType, r.URL.Query().Get("prefix"), limit)
+		if !ok {
+			http.Error(w, "unknown syncer type "+string(syncerType), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, dump)
+	})
+	mux.HandleFunc(IntrospectionUpdateRatesPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.UpdateRates())
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.WithError(err).Debug("Failed to write introspection response.")
+	}
+}
+
+// Clients returns the current client connections, ordered by connection ID.
+func (s *Server) Clients() []ClientInfo {
+	s.lock.Lock()
+	conns := make([]*connection, 0, len(s.connIDToConn))
+	for _, conn := range s.connIDToConn {
+		conns = append(conns, conn)
+	}
+	s.lock.Unlock()
+
+	clients := make([]ClientInfo, 0, len(conns))
+	for _, conn := range conns {
+		clients = append(clients, conn.clientInfo())
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ConnID < clients[j].ConnID
+	})
+	return clients
+}
+
+func (h *connection) clientInfo() ClientInfo {
+	info := ClientInfo{
+		ConnID:      h.ID,
+		RemoteAddr:  h.conn.RemoteAddr().String(),
+		ConnectedAt: h.connectedAt,
+		BytesSent:   h.bytesSent.Load(),
+	}
+
+	h.helloLock.Lock()
+	hello := h.hello
+	h.helloLock.Unlock()
+	if hello == nil {
+		return info
+	}
+	info.Hostname = hello.Hostname
+	info.Info = hello.Info
+	info.Version = hello.Version
+	info.SyncerType = hello.SyncerType
+	if info.SyncerType == "" {
+		info.SyncerType = syncproto.SyncerTypeFelix
+	}
+	info.SupportsDecoderRestart = hello.SupportsDecoderRestart
+	info.SupportsResume = hello.SupportsResume
+	info.ScopeToNode = hello.ScopeToNode
+
+	crumb := h.sentBreadcrumb.Load()
+	cache := h.allCaches[info.SyncerType]
+	if crumb == nil || cache == nil {
+		return info
+	}
+	// The handshake is complete, so chosenCompression won't change again.
+	info.Compression = h.chosenCompression
+	latest := cache.CurrentBreadcrumb()
+	info.Streaming = true
+	info.BreadcrumbSeqNo = crumb.SequenceNumber
+	info.LatestBreadcrumbSeqNo = latest.SequenceNumber
+	info.LagSecs = latest.Timestamp.Sub(crumb.Timestamp).Seconds()
+	return info
+}
+
+// DumpCache returns up to limit KVs from the current snapshot of the given syncer type's cache whose keys
+// start with the given prefix.  It returns false if there is no such cache.
+func (s *Server) DumpCache(syncerType syncproto.SyncerType, prefix string, limit int) (*CacheDump, bool) {
+	cache := s.caches[syncerType]
+	if cache == nil {
+		return nil, false
+	}
+	crumb := cache.CurrentBreadcrumb()
+	dump := &CacheDump{
+		SyncerType:      syncerType,
+		BreadcrumbSeqNo: crumb.SequenceNumber,
+		SyncStatus:      crumb.SyncStatus.String(),
+		KVs:             []CachedKV{},
+	}
+	crumb.KVs.AscendGreaterOrEqual(syncproto.SerializedUpdate{Key: prefix}, func(su syncproto.SerializedUpdate) bool {
+		if !strings.HasPrefix(su.Key, prefix) {
+			return false
+		}
+		dump.NumKVs++
+		if len(dump.KVs) < limit {
+			value := json.RawMessage(su.Value)
+			if !json.Valid(value) {
+				// Shouldn't happen since values are serialized as JSON but make sure that we return valid JSON.
+				value, _ = json.Marshal(string(su.Value))
+			}
+			dump.KVs = append(dump.KVs, CachedKV{
+				Key:      su.Key,
+				Value:    value,
+				Revision: su.Revision,
+			})
+		}
+		return true
+	})
+	return dump, true
+}
+
+// UpdateRates returns the recent activity of each syncer type, ordered by syncer type.
+func (s *Server) UpdateRates() []SyncerUpdateRates {
+	numClients := map[syncproto.SyncerType]int{}
+	for _, c := range s.Clients() {
+		if c.Streaming {
+			numClients[c.SyncerType]++
+		}
+	}
+
+	now := time.Now()
+	rates := make([]SyncerUpdateRates, 0, len(s.caches))
+	for syncerType, cache := range s.caches {
+		crumb := cache.CurrentBreadcrumb()
+		r := SyncerUpdateRates{
+			SyncerType:        syncerType,
+			SyncStatus:        crumb.SyncStatus.String(),
+			BreadcrumbSeqNo:   crumb.SequenceNumber,
+			NumKVs:            crumb.KVs.Len(),
+			NumClients:        numClients[syncerType],
+			UpdatesPerSec:     map[string]float64{},
+			BreadcrumbsPerSec: map[string]float64{},
+		}
+		for _, window := range updateRateWindows {
+			r.UpdatesPerSec[window.name], r.BreadcrumbsPerSec[window.name] =
+				s.updateRates[syncerType].rates(now, window.secs)
+		}
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].SyncerType < rates[j].SyncerType
+	})
+	return rates
+}
+
+// trackUpdateRate follows the cache's Breadcrumbs, recording the number of updates in each one.
+func (s *Server) trackUpdateRate(cxt context.Context, cache BreadcrumbProvider, tracker *updateRateTracker) {
+	defer s.Finished.Done()
+	crumb := cache.CurrentBreadcrumb()
+	for {
+		var err error
+		crumb, err = crumb.Next(cxt)
+		if err != nil {
+			return
+		}
+		tracker.record(time.Now(), len(crumb.Deltas))
+	}
+}
+
+// updateRateHistorySecs is the number of seconds of history that an updateRateTracker keeps.
+const updateRateHistorySecs = 300
+
+// updateRateTracker counts the updates and Breadcrumbs in each second, over the last updateRateHistorySecs
+// seconds.
+type updateRateTracker struct {
+	lock    sync.Mutex
+	buckets [updateRateHistorySecs]updateRateBucket
+}
+
+type updateRateBucket struct {
+	second      int64
+	updates     uint64
+	breadcrumbs uint64
+}
+
+func (t *updateRateTracker) record(now time.Time, numUpdates int) {
+	sec := now.Unix()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	b := &t.buckets[sec%updateRateHistorySecs]
+	if b.second != sec {
+		*b = updateRateBucket{second: sec}
+	}
+	b.updates += uint64(numUpdates)
+	b.breadcrumbs++
+}
+
+// rates returns the average number of updates and Breadcrumbs per second over the given number of whole
+// seconds before now.  secs must not exceed updateRateHistorySecs.
+func (t *updateRateTracker) rates(now time.Time, secs int) (updates, breadcrumbs float64) {
+	sec := now.Unix()
+	var numUpdates, numCrumbs uint64
+	t.lock.Lock()
+	for s := sec - int64(secs); s < sec; s++ {
+		if b := t.buckets[s%updateRateHistorySecs]; b.second == s {
+			numUpdates += b.updates
+			numCrumbs += b.breadcrumbs
+		}
+	}
+	t.lock.Unlock()
+	return float64(numUpdates) / float64(secs), float64(numCrumbs) / float64(secs)
+}
+
+// byteCountingWriter counts the bytes written to the underlying Writer.
+type byteCountingWriter struct {
+	w io.Writer
+	n *atomic.Uint64
+}
+
+func (b byteCountingWriter) Write(p []byte) (int, error) {
+	n, err := b.w.Write(p)
+	b.n.Add(uint64(n))
+	return n, err
+}
@@ -22,7 +22,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/golang/snappy"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
@@ -62,11 +61,17 @@ func init() {
 	prometheus.MustRegister(gaugeVecSnapCompressedBytes)
 }
 
-type SnappySnapshotCache struct {
+// BinarySnapshotCache generates compressed binary snapshots of a cache and shares them between the clients
+// that connect while they are fresh.
+type BinarySnapshotCache struct {
 	snapValidityTimeout time.Duration
 	logCtx              *logrus.Entry
+	compression         syncproto.CompressionAlgorithm
 
 	cache BreadcrumbProvider
+	// condensed is set if the snapshots contain the condensed form of the KVs, as used by clients with
+	// node-scoped views.
+	condensed bool
 
 	lock           sync.Mutex
 	cond           sync.Cond
@@ -81,15 +86,21 @@ type SnappySnapshotCache struct {
 	writeTimeout time.Duration
 }
 
-func NewSnappySnapCache(
+func NewBinarySnapCache(
 	syncerName string,
+	compression syncproto.CompressionAlgorithm,
 	cache BreadcrumbProvider,
 	snapValidityTimeout time.Duration,
 	writeTimeout time.Duration,
-) *SnappySnapshotCache {
-	s := &SnappySnapshotCache{
+) *BinarySnapshotCache {
+	if compression != syncproto.CompressionSnappy {
+		// Keep the metrics of each algorithm separate.  Snappy keeps the plain syncer name for compatibility.
+		syncerName += "-" + string(compression)
+	}
+	s := &BinarySnapshotCache{
 		snapValidityTimeout: snapValidityTimeout,
 		writeTimeout:        writeTimeout,
+		compression:         compression,
 		cache:               cache,
 		logCtx: logrus.WithFields(logrus.Fields{
 			"thread": "snapshotter",
@@ -104,11 +115,25 @@ func NewSnappySnapCache(
 	return s
 }
 
-// SendSnapshot waits for a binary snapshot to be ready and then sends it as a raw snappy-compressed gob stream
-// on the given connection.  Since the stream is cached, it starts with fresh snappy/gob headers.  Hence, the
+// NewCondensedBinarySnapCache returns a BinarySnapshotCache whose snapshots contain the condensed form of
+// each KV.  Clients with node-scoped views need the full form of their own node's KVs on top.
+func NewCondensedBinarySnapCache(
+	syncerName string,
+	compression syncproto.CompressionAlgorithm,
+	cache BreadcrumbProvider,
+	snapValidityTimeout time.Duration,
+	writeTimeout time.Duration,
+) *BinarySnapshotCache {
+	s := NewBinarySnapCache(syncerName+"-condensed", compression, cache, snapValidityTimeout, writeTimeout)
+	s.condensed = true
+	return s
+}
+
+// SendSnapshot waits for a binary snapshot to be ready and then sends it as a raw compressed gob stream
+// on the given connection.  Since the stream is cached, it starts with fresh compression/gob headers.  Hence, the
 // decoder at the client side must also be reset before sending such a snapshot.  The snapshot ends with
 // a MsgDecoderRestart, so the caller should wait for an ACK and then reset their encoder.
-func (s *SnappySnapshotCache) SendSnapshot(ctx context.Context, w io.Writer, conn WriteDeadlineSetter) (*snapcache.Breadcrumb, error) {
+func (s *BinarySnapshotCache) SendSnapshot(ctx context.Context, w io.Writer, conn WriteDeadlineSetter) (*snapcache.Breadcrumb, error) {
 	// activeBinarySnapshot ensures there is an active snapshot and returns it.  The snapshot may or may not
 	// be complete yet.
 	snap := s.activeBinarySnapshot()
@@ -124,7 +149,7 @@ type WriteDeadlineSetter interface {
 
 // activeBinarySnapshot either returns the current active snapshot (which may still be being created on a background
 // goroutine), or it starts a new snapshot.  The returned snapshot's complete flag will be set once it is finished.
-func (s *SnappySnapshotCache) activeBinarySnapshot() *snapshot {
+func (s *BinarySnapshotCache) activeBinarySnapshot() *snapshot {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -146,7 +171,7 @@ func (s *SnappySnapshotCache) activeBinarySnapshot() *snapshot {
 	return s.activeSnapshot
 }
 
-func (s *SnappySnapshotCache) populateSnapshot(snap *snapshot) {
+func (s *BinarySnapshotCache) populateSnapshot(snap *snapshot) {
 	s.writeDataToSnapshot(snap)
 	// Wait until the snapshot expires...
 	time.Sleep(s.snapValidityTimeout)
@@ -155,14 +180,14 @@ func (s *SnappySnapshotCache) populateSnapshot(snap *snapshot) {
 	s.clearSnapshot()
 }
 
-func (s *SnappySnapshotCache) clearSnapshot() {
+func (s *BinarySnapshotCache) clearSnapshot() {
 	s.lock.Lock()
 	s.activeSnapshot = nil
 	s.lock.Unlock()
 }
 
 type progressWriter struct {
-	W            *snappy.Writer
+	W            io.Writer
 	BytesWritten int
 }
 
@@ -172,10 +197,14 @@ func (p2 *progressWriter) Write(p []byte) (n int, err error) {
 	return
 }
 
-func (s *SnappySnapshotCache) writeDataToSnapshot(snap *snapshot) {
+func (s *BinarySnapshotCache) writeDataToSnapshot(snap *snapshot) {
 	s.counterBinSnapsGenerated.Inc()
-	snappyW := snappy.NewBufferedWriter(snap.buf)
-	progressW := progressWriter{W: snappyW}
+	compW, err := syncproto.NewCompressingWriter(s.compression, snap.buf)
+	if err != nil {
+		// Shouldn't happen, the server only creates caches for the algorithms that it supports.
+		s.logCtx.WithError(err).Panic("Failed to create compressor for datastore snapshot.")
+	}
+	progressW := progressWriter{W: compW}
 	encoder := gob.NewEncoder(&progressW)
 	writeMsg := func(msg any) error {
 		envelope := syncproto.Envelope{
@@ -187,10 +216,15 @@ func (s *SnappySnapshotCache) writeDataToSnapshot(snap *snapshot) {
 		}
 		return nil
 	}
-	err := writeSnapshotMessages(
+	var view func(syncproto.SerializedUpdate) syncproto.SerializedUpdate
+	if s.condensed {
+		view = syncproto.SerializedUpdate.Condensed
+	}
+	err = writeSnapshotMessages(
 		context.Background(),
 		s.logCtx.WithField("destination", "compressed in-memory cache"),
 		snap.crumb,
+		view,
 		writeMsg,
 		1000, // Allow bigger messages in the snapshot.
 	)
@@ -201,14 +235,16 @@ func (s *SnappySnapshotCache) writeDataToSnapshot(snap *snapshot) {
 
 	err = writeMsg(syncproto.MsgDecoderRestart{
 		Message:              "End of compressed snapshot.",
-		CompressionAlgorithm: syncproto.CompressionSnappy,
+		CompressionAlgorithm: s.compression,
 	})
 	if err != nil {
 		// Shouldn't happen because we're serialising to an in-memory buffer.
 		s.logCtx.WithError(err).Panic("Failed to serialise datastore snapshot end message.")
 	}
 
-	err = snappyW.Close() // Does Flush() for us.
+	// Note: we flush rather than close the compressed stream; the client restarts its decoder at this point
+	// and wouldn't read any trailer.
+	err = compW.Flush()
 	if err != nil {
 		// Shouldn't happen because we're serialising to an in-memory buffer.
 		s.logCtx.WithError(err).Panic("Failed to close datastore snapshot.")
@@ -229,7 +265,7 @@ func (s *SnappySnapshotCache) writeDataToSnapshot(snap *snapshot) {
 	s.setLastSnapSize(snapSize)
 }
 
-func (s *SnappySnapshotCache) setLastSnapSize(snapSize int) {
+func (s *BinarySnapshotCache) setLastSnapSize(snapSize int) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.lastSnapSize = snapSize
@@ -18,7 +18,6 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"encoding/gob"
 	"errors"
 	"fmt"
@@ -26,15 +25,15 @@ import (
 	"math"
 	"net"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/golang/snappy"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
-	calicotls "github.com/projectcalico/calico/crypto/pkg/tls"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/health"
 	cprometheus "github.com/projectcalico/calico/libcalico-go/lib/prometheus"
@@ -78,6 +77,10 @@ var (
 		Help: "Total number of connections that made use of the grace period to catch up after sending the initial " +
 			"snapshot.",
 	}, []string{"syncer"})
+	counterVecConnectionsResumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "typha_connections_resumed",
+		Help: "Total number of connections that resumed a previous session instead of receiving a snapshot.",
+	}, []string{"syncer"})
 )
 
 func init() {
@@ -89,6 +92,8 @@ func init() {
 	promutils.PreCreateGaugePerSyncer(gaugeVecNumConnectionsStreaming)
 	prometheus.MustRegister(counterVecGracePeriodUsed)
 	promutils.PreCreateCounterPerSyncer(counterVecGracePeriodUsed)
+	prometheus.MustRegister(counterVecConnectionsResumed)
+	promutils.PreCreateCounterPerSyncer(counterVecConnectionsResumed)
 }
 
 const (
@@ -110,18 +115,26 @@ type Server struct {
 	config        Config
 	caches        map[syncproto.SyncerType]BreadcrumbProvider
 	binSnapCaches map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache
-	nextConnID    uint64
-	maxConnsC     chan int
-	chosenPort    int
-	listeningC    chan struct{}
+	// condensedBinSnapCaches hold the compressed snapshots for clients with node-scoped views.
+	condensedBinSnapCaches map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache
+	nextConnID             uint64
+	maxConnsC              chan int
+	chosenPort             int
+	listeningC             chan struct{}
 
 	lock sync.Mutex
 
 	shuttingDown bool
 	shutdownC    chan struct{}
 	connIDToConn map[uint64]*connection
+	// externalConns holds the cancel functions of the connections that other servers, such as the gRPC
+	// server, serve from the same caches.  See TrackExternalConnection.
+	nextExternalConnID uint64
+	externalConns      map[uint64]context.CancelFunc
 
 	perSyncerConnMetrics map[syncproto.SyncerType]perSyncerConnMetrics
+	// updateRates tracks the recent rate of updates of each syncer type, for the introspection API.
+	updateRates map[syncproto.SyncerType]*updateRateTracker
 
 	Finished sync.WaitGroup
 }
@@ -130,6 +143,13 @@ type BreadcrumbProvider interface {
 	CurrentBreadcrumb() *snapcache.Breadcrumb
 }
 
+// ResumableBreadcrumbProvider is implemented by BreadcrumbProviders that retain their recent
+// Breadcrumbs, allowing clients to resume their session from the state identified by a ResumeToken.
+type ResumableBreadcrumbProvider interface {
+	BreadcrumbProvider
+	ResumeBreadcrumb(token syncproto.ResumeToken) *snapcache.Breadcrumb
+}
+
 type Config struct {
 	Port                           int
 	MaxMessageSize                 int
@@ -152,6 +172,9 @@ type Config struct {
 	ClientCN                       string
 	ClientURISAN                   string
 	WriteBufferSize                int
+	// CompressionAlgorithms are the compression algorithms that the server may use, in order of preference.
+	// The server uses the first one that the client supports.  Defaults to snappy only.
+	CompressionAlgorithms []syncproto.CompressionAlgorithm
 
 	// DebugLogWrites tells the server to wrap each connection with a Writer that
 	// logs every write.  Intended only for use in tests!
@@ -253,6 +276,10 @@ func (c *Config) ApplyDefaults() {
 		}).Info("Defaulting MaxConns.")
 		c.MaxConns = defaultMaxConns
 	}
+	if len(c.CompressionAlgorithms) == 0 {
+		log.WithField("default", syncproto.CompressionSnappy).Info("Defaulting CompressionAlgorithms.")
+		c.CompressionAlgorithms = []syncproto.CompressionAlgorithm{syncproto.CompressionSnappy}
+	}
 	if c.Port == 0 {
 		// We use 0 to mean "use the default port".
 		log.WithFields(log.Fields{
@@ -279,21 +306,31 @@ func New(caches map[syncproto.SyncerType]BreadcrumbProvider, config Config) *Ser
 	config.ApplyDefaults()
 	log.WithField("config", config).Info("Creating server")
 	s := &Server{
-		config:               config,
-		caches:               caches,
-		binSnapCaches:        map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache{},
-		maxConnsC:            make(chan int),
-		shutdownC:            make(chan struct{}),
-		nextConnID:           1,
-		connIDToConn:         map[uint64]*connection{},
-		listeningC:           make(chan struct{}),
-		perSyncerConnMetrics: map[syncproto.SyncerType]perSyncerConnMetrics{},
-	}
-
-	s.binSnapCaches[syncproto.CompressionSnappy] = map[syncproto.SyncerType]snapshotCache{}
+		config:                 config,
+		caches:                 caches,
+		binSnapCaches:          map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache{},
+		condensedBinSnapCaches: map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache{},
+		maxConnsC:              make(chan int),
+		shutdownC:              make(chan struct{}),
+		nextConnID:             1,
+		connIDToConn:           map[uint64]*connection{},
+		externalConns:          map[uint64]context.CancelFunc{},
+		listeningC:             make(chan struct{}),
+		perSyncerConnMetrics:   map[syncproto.SyncerType]perSyncerConnMetrics{},
+		updateRates:            map[syncproto.SyncerType]*updateRateTracker{},
+	}
+
+	for _, alg := range config.CompressionAlgorithms {
+		s.binSnapCaches[alg] = map[syncproto.SyncerType]snapshotCache{}
+		s.condensedBinSnapCaches[alg] = map[syncproto.SyncerType]snapshotCache{}
+	}
 	for st, cache := range caches {
 		s.perSyncerConnMetrics[st] = makePerSyncerConnMetrics(st)
-		s.binSnapCaches[syncproto.CompressionSnappy][st] = NewSnappySnapCache(string(st), cache, config.BinarySnapshotTimeout, config.WriteTimeout)
+		s.updateRates[st] = &updateRateTracker{}
+		for _, alg := range config.CompressionAlgorithms {
+			s.binSnapCaches[alg][st] = NewBinarySnapCache(string(st), alg, cache, config.BinarySnapshotTimeout, config.WriteTimeout)
+			s.condensedBinSnapCaches[alg][st] = NewCondensedBinarySnapCache(string(st), alg, cache, config.BinarySnapshotTimeout, config.WriteTimeout)
+		}
 	}
 
 	// Register that we will report liveness.
@@ -314,6 +351,10 @@ func (s *Server) Start(cxt context.Context) {
 	go s.serve(cxt)
 	go s.governNumberOfConnections(cxt)
 	go s.handleGracefulShutDown(cxt, cancelFn)
+	for st, cache := range s.caches {
+		s.Finished.Add(1)
+		go s.trackUpdateRate(cxt, cache, s.updateRates[st])
+	}
 }
 
 func (s *Server) SetMaxConns(numConns int) {
@@ -339,34 +380,21 @@ func (s *Server) serve(cxt context.Context) {
 	if s.config.requiringTLS() {
 		pwd, _ := os.Getwd()
 		logCxt.WithField("pwd", pwd).Info("Opening TLS listen socket")
-		cert, tlsErr := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
+		tlsConfig, tlsErr := tlsutils.NewServerTLSConfig(
+			logCxt,
+			s.config.CertFile,
+			s.config.KeyFile,
+			s.config.CAFile,
+			s.config.ClientCN,
+			s.config.ClientURISAN,
+		)
 		if tlsErr != nil {
 			logCxt.WithFields(log.Fields{
 				"certFile": s.config.CertFile,
 				"keyFile":  s.config.KeyFile,
-			}).WithError(tlsErr).Panic("Failed to load certificate and key")
-		}
-		tlsConfig := calicotls.NewTLSConfig()
-		tlsConfig.Certificates = []tls.Certificate{cert}
-
-		// Arrange for server to verify the clients' certificates.
-		logCxt.Info("Will verify client certificates")
-		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
-		caPEMBlock, tlsErr := os.ReadFile(s.config.CAFile)
-		if tlsErr != nil {
-			logCxt.WithError(tlsErr).Panic("Failed to read CA data")
-		}
-		tlsConfig.ClientCAs = x509.NewCertPool()
-		ok := tlsConfig.ClientCAs.AppendCertsFromPEM(caPEMBlock)
-		if !ok {
-			logCxt.Panic("Failed to add CA data to pool")
+				"caFile":   s.config.CAFile,
+			}).WithError(tlsErr).Panic("Failed to create TLS config")
 		}
-		tlsConfig.VerifyPeerCertificate = tlsutils.CertificateVerifier(
-			logCxt,
-			tlsConfig.ClientCAs,
-			s.config.ClientCN,
-			s.config.ClientURISAN,
-		)
 
 		laddr := fmt.Sprintf("0.0.0.0:%v", s.config.ListenPort())
 		l, err = tls.Listen("tcp", laddr, tlsConfig)
@@ -434,30 +462,31 @@ func (s *Server) serve(cxt context.Context) {
 		// Create a new connection-scoped context, which we'll use for signaling to our child
 		// goroutines to halt.
 		connCxt, cancel := context.WithCancel(cxt)
-		var connW io.Writer = conn
-		if s.config.DebugLogWrites {
-			connW = writelogger.New(conn)
-		}
 		connection := &connection{
-			ID:              connID,
-			config:          &s.config,
-			allCaches:       s.caches,
-			allSnapshotters: s.binSnapCaches,
-			cxt:             connCxt,
-			cancelCxt:       cancel,
-			conn:            conn,
-			connW:           connW,
+			ID:                       connID,
+			config:                   &s.config,
+			allCaches:                s.caches,
+			allSnapshotters:          s.binSnapCaches,
+			allCondensedSnapshotters: s.condensedBinSnapCaches,
+			cxt:                      connCxt,
+			cancelCxt:                cancel,
+			conn:                     conn,
 			logCxt: log.WithFields(log.Fields{
 				"client": conn.RemoteAddr(),
 				"connID": connID,
 			}),
 
-			encoder:     gob.NewEncoder(connW),
 			flushWriter: func() error { return nil },
 			readC:       make(chan interface{}),
+			connectedAt: time.Now(),
 
 			allMetrics: s.perSyncerConnMetrics,
 		}
+		connection.connW = byteCountingWriter{w: conn, n: &connection.bytesSent}
+		if s.config.DebugLogWrites {
+			connection.connW = writelogger.New(connection.connW)
+		}
+		connection.encoder = gob.NewEncoder(connection.connW)
 		// Track the connection's lifetime in connIDToConn so we can kill it later if needed.
 		s.recordConnection(connection)
 		// Defer to the connection-handler.
@@ -608,10 +637,31 @@ func (s *Server) handleGracefulShutDown(cxt context.Context, serverCancelFn cont
 	}
 }
 
+// TrackExternalConnection adds a connection that another server serves from the same caches to the
+// connections of this Server, so that it counts towards MaxConns and it is closed, by calling cancel,
+// to re-balance load or to shut down gracefully, like the Server's own connections.  The caller must
+// call the returned function once the connection finishes.  Returns an error if the Server is shutting
+// down.
+func (s *Server) TrackExternalConnection(cancel context.CancelFunc) (func(), error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.shuttingDown {
+		return nil, errors.New("server is shutting down")
+	}
+	id := s.nextExternalConnID
+	s.nextExternalConnID++
+	s.externalConns[id] = cancel
+	return func() {
+		s.lock.Lock()
+		delete(s.externalConns, id)
+		s.lock.Unlock()
+	}, nil
+}
+
 func (s *Server) NumActiveConnections() int {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	return len(s.connIDToConn)
+	return len(s.connIDToConn) + len(s.externalConns)
 }
 
 func (s *Server) ShuttingDown() bool {
@@ -630,6 +680,11 @@ func (s *Server) TerminateRandomConnection(logCtx *log.Entry, reason string) boo
 		conn.cancelCxt()
 		return true
 	}
+	for id, cancel := range s.externalConns {
+		logCtx.WithField("externalConnID", id).Infof("Closing external connection; reason: %s.", reason)
+		cancel()
+		return true
+	}
 	return false
 }
 
@@ -668,9 +723,11 @@ type connection struct {
 	// cache in the "cache" field.
 	allCaches       map[syncproto.SyncerType]BreadcrumbProvider
 	allSnapshotters map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache
-	cache           BreadcrumbProvider
-	syncerType      syncproto.SyncerType
-	conn            net.Conn
+	// allCondensedSnapshotters contains the snapshot caches for clients with node-scoped views.
+	allCondensedSnapshotters map[syncproto.CompressionAlgorithm]map[syncproto.SyncerType]snapshotCache
+	cache                    BreadcrumbProvider
+	syncerType               syncproto.SyncerType
+	conn                     net.Conn
 	// connW is the writer to use to send things to the client.  It may be the net.Conn itself or a wrapper
 	// around it.
 	connW io.Writer
@@ -686,6 +743,21 @@ type connection struct {
 	logCxt                       *log.Entry
 	chosenCompression            syncproto.CompressionAlgorithm
 	clientSupportsDecoderRestart bool
+	clientSupportsResume         bool
+	// resumeBreadcrumb is the Breadcrumb that the client resumed from, if any.  The client already has
+	// its snapshot so we only need to send the deltas from there.
+	resumeBreadcrumb *snapcache.Breadcrumb
+	// scopeToNode is the node that the client's view is scoped to, if it asked for a node-scoped view.
+	scopeToNode string
+
+	// The fields below are read by the introspection API.  hello is written once by the handshake, under
+	// helloLock.  bytesSent counts the bytes written to connW.  sentBreadcrumb is the most recent
+	// Breadcrumb whose state the client has been sent.
+	helloLock      sync.Mutex
+	hello          *syncproto.MsgClientHello
+	connectedAt    time.Time
+	bytesSent      atomic.Uint64
+	sentBreadcrumb atomic.Pointer[snapcache.Breadcrumb]
 
 	// Similarly to allCaches, allMetrics contains all the metrics relevant to a particular syncer.  We copy one
 	// of them to the unnamed field after the handshake.
@@ -733,7 +805,11 @@ func (h *connection) handle(finishedWG *sync.WaitGroup) (err error) {
 	// Figure out if we should restart the decoder with new settings.
 	var binSnapCache snapshotCache
 	if h.clientSupportsDecoderRestart {
-		binSnapCache = h.allSnapshotters[h.chosenCompression][h.syncerType]
+		if h.resumeBreadcrumb == nil && h.scopeToNode == "" {
+			binSnapCache = h.allSnapshotters[h.chosenCompression][h.syncerType]
+		} else if h.resumeBreadcrumb == nil {
+			binSnapCache = h.allCondensedSnapshotters[h.chosenCompression][h.syncerType]
+		}
 		var reasonsToRestart []string
 		if h.chosenCompression != "" {
 			reasonsToRestart = append(reasonsToRestart, fmt.Sprintf("enable compression: %v", h.chosenCompression))
@@ -753,7 +829,12 @@ func (h *connection) handle(finishedWG *sync.WaitGroup) (err error) {
 	}
 
 	var breadcrumb *snapcache.Breadcrumb
-	if binSnapCache != nil {
+	if h.resumeBreadcrumb != nil {
+		// Client already has the state of this breadcrumb, skip straight to the deltas.
+		h.logCxt.WithField("seqNo", h.resumeBreadcrumb.SequenceNumber).Info("Resuming client's session.")
+		breadcrumb = h.resumeBreadcrumb
+		h.counterConnectionsResumed.Inc()
+	} else if binSnapCache != nil {
 		// We have a binary snapshot cache that supports this compression mode; send the compressed
 		// binary snapshot instead of a streamed snapshot.
 		snapStart := time.Now()
@@ -770,6 +851,15 @@ func (h *connection) handle(finishedWG *sync.WaitGroup) (err error) {
 			return
 		}
 		h.logCxt.Info("Sent compressed binary snapshot and received ACK from client.")
+		if h.scopeToNode != "" {
+			// The shared snapshot has the condensed form of everything, follow up with the full form of the
+			// client's own resources.
+			err = h.sendNodeResourcesToClient(breadcrumb)
+			if err != nil {
+				log.WithError(err).Info("Failed to send node's resources to client, tearing down connection.")
+				return
+			}
+		}
 		h.summarySnapshotSendTime.Observe(time.Since(snapStart).Seconds())
 	} else {
 		// Either client is old or we don't have support for sending a compressed snapshot of this type.
@@ -782,6 +872,16 @@ func (h *connection) handle(finishedWG *sync.WaitGroup) (err error) {
 			return
 		}
 	}
+	if h.resumeBreadcrumb == nil && h.clientSupportsResume {
+		// Tell the client which state the snapshot corresponds to.
+		err = h.sendMsg(syncproto.MsgKVs{ResumeToken: h.resumeToken(breadcrumb)})
+		if err != nil {
+			log.WithError(err).Info("Failed to send resume token to client, tearing down connection.")
+			return
+		}
+	}
+
+	h.sentBreadcrumb.Store(breadcrumb)
 
 	// Start a goroutine to stream deltas to the client.
 	h.shutDownWG.Add(1)
@@ -904,6 +1004,9 @@ func (h *connection) doHandshake() error {
 		return ErrUnexpectedClientMsg
 	}
 	h.logCxt.WithField("msg", hello).Info("Received Hello message from client.")
+	h.helloLock.Lock()
+	h.hello = &hello
+	h.helloLock.Unlock()
 
 	syncerType := hello.SyncerType
 	if syncerType == "" {
@@ -921,10 +1024,11 @@ func (h *connection) doHandshake() error {
 	}
 	h.cache = desiredSyncerCache
 
-	for _, alg := range hello.SupportedCompressionAlgorithms {
-		switch alg {
-		case syncproto.CompressionSnappy:
-			h.chosenCompression = syncproto.CompressionSnappy
+	// Use our most-preferred algorithm that the client supports.
+	for _, alg := range h.config.CompressionAlgorithms {
+		if slices.Contains(hello.SupportedCompressionAlgorithms, alg) {
+			h.chosenCompression = alg
+			break
 		}
 	}
 	h.clientSupportsDecoderRestart = hello.SupportsDecoderRestart
@@ -932,6 +1036,27 @@ func (h *connection) doHandshake() error {
 		log.WithError(err).Warning("Client signalled compression but no support for decoder restart")
 		h.chosenCompression = ""
 	}
+	h.clientSupportsResume = hello.SupportsResume
+	h.scopeToNode = hello.ScopeToNode
+	if h.scopeToNode != "" {
+		h.logCxt = h.logCxt.WithField("scopeToNode", h.scopeToNode)
+		h.logCxt.Info("Client asked for a node-scoped view.")
+	}
+	if hello.ResumeToken != nil {
+		if rbp, ok := h.cache.(ResumableBreadcrumbProvider); ok {
+			h.resumeBreadcrumb = rbp.ResumeBreadcrumb(*hello.ResumeToken)
+		}
+		if h.resumeBreadcrumb != nil &&
+			h.cache.CurrentBreadcrumb().Timestamp.Sub(h.resumeBreadcrumb.Timestamp) > h.config.MaxFallBehind {
+			// Client would immediately be too far behind; most likely it was disconnected for falling behind.
+			h.logCxt.Info("Client's state is too old to resume from.")
+			h.resumeBreadcrumb = nil
+		}
+		if h.resumeBreadcrumb == nil {
+			h.logCxt.WithField("token", *hello.ResumeToken).Info(
+				"Client asked to resume its session but we no longer have its state.")
+		}
+	}
 
 	// Respond to client's hello.
 	err = h.sendMsg(syncproto.MsgServerHello{
@@ -941,6 +1066,8 @@ func (h *connection) doHandshake() error {
 		SyncerType:                  syncerType,
 		SupportsNodeResourceUpdates: true,
 		ServerConnID:                h.ID,
+		Resumed:                     h.resumeBreadcrumb != nil,
+		ScopedToNode:                h.scopeToNode != "",
 	})
 	if err != nil {
 		log.WithError(err).Warning("Failed to send hello to client")
@@ -986,22 +1113,25 @@ func (h *connection) waitForAckAndRestartEncoder() error {
 	}
 	h.logCxt.WithField("msg", ack).Info("Received ACK message from client.")
 
-	// Upgrade to compressed connection if required.
+	// Upgrade to compressed connection if required.  Any previous compressed stream has already been flushed
+	// and is simply abandoned.
 	bw := bufio.NewWriter(h.connW)
-	switch h.chosenCompression {
-	case syncproto.CompressionSnappy:
-		w := snappy.NewBufferedWriter(bw)
-		h.encoder = gob.NewEncoder(w) // Need a new Encoder, there's no way to change out the Writer.
-		h.flushWriter = func() error {
-			err := w.Flush()
-			if err != nil {
-				return err
-			}
-			return bw.Flush()
-		}
-	default:
+	if h.chosenCompression == "" {
 		h.encoder = gob.NewEncoder(bw) // Need a new Encoder, there's no way to change out the Writer.
 		h.flushWriter = bw.Flush
+		return nil
+	}
+	w, err := syncproto.NewCompressingWriter(h.chosenCompression, bw)
+	if err != nil {
+		return err
+	}
+	h.encoder = gob.NewEncoder(w) // Need a new Encoder, there's no way to change out the Writer.
+	h.flushWriter = func() error {
+		err := w.Flush()
+		if err != nil {
+			return err
+		}
+		return bw.Flush()
 	}
 	return nil
 }
@@ -1181,18 +1311,23 @@ func (h *connection) sendDeltaUpdatesToClient(logCxt *log.Entry, breadcrumb *sna
 			}
 		}
 
+		if len(deltas) > 0 && h.scopeToNode != "" {
+			deltas = h.scopeUpdates(deltas)
+		}
 		if len(deltas) > 0 {
 			// Send the deltas relative to the previous snapshot.
 			logCxt.WithField("num", len(deltas)).Debug("Sending deltas")
 			h.summaryNumKVsPerMsg.Observe(float64(len(deltas)))
 			err := h.sendMsg(syncproto.MsgKVs{
-				KVs: deltas,
+				KVs:         deltas,
+				ResumeToken: h.resumeToken(breadcrumb),
 			})
 			if err != nil {
 				logCxt.WithError(err).Info("Failed to send to client.")
 				return
 			}
 		}
+		h.sentBreadcrumb.Store(breadcrumb)
 
 		// Newest breadcrumb may have updated the sync status, send an update if so.
 		if err := maybeSendStatus(); err != nil {
@@ -1201,13 +1336,28 @@ func (h *connection) sendDeltaUpdatesToClient(logCxt *log.Entry, breadcrumb *sna
 	}
 }
 
+// resumeToken returns the token identifying the given breadcrumb if the client supports resuming,
+// nil otherwise.
+func (h *connection) resumeToken(breadcrumb *snapcache.Breadcrumb) *syncproto.ResumeToken {
+	if !h.clientSupportsResume {
+		return nil
+	}
+	token := breadcrumb.ResumeToken()
+	return &token
+}
+
 // streamSnapshotToClient takes the snapshot contained in the Breadcrumb and streams it to the client in chunks.
 func (h *connection) streamSnapshotToClient(logCxt *log.Entry, breadcrumb *snapcache.Breadcrumb) error {
 	startTime := time.Now()
+	var view func(syncproto.SerializedUpdate) syncproto.SerializedUpdate
+	if h.scopeToNode != "" {
+		view = h.scopeUpdate
+	}
 	err := writeSnapshotMessages(
 		h.cxt,
 		h.logCxt.WithField("destination", "direct to client"),
 		breadcrumb,
+		view,
 		h.sendMsg,
 		h.config.MaxMessageSize,
 	)
@@ -1219,11 +1369,50 @@ func (h *connection) streamSnapshotToClient(logCxt *log.Entry, breadcrumb *snapc
 	return nil
 }
 
+// scopeUpdate returns the update as seen by the client with a node-scoped view.
+func (h *connection) scopeUpdate(upd syncproto.SerializedUpdate) syncproto.SerializedUpdate {
+	return upd.ForNode(h.scopeToNode)
+}
+
+// scopeUpdates returns a copy of the updates as seen by the client with a node-scoped view.  The input
+// may be shared with other clients so it is not modified.
+func (h *connection) scopeUpdates(upds []syncproto.SerializedUpdate) []syncproto.SerializedUpdate {
+	scoped := make([]syncproto.SerializedUpdate, len(upds))
+	for i, upd := range upds {
+		scoped[i] = h.scopeUpdate(upd)
+	}
+	return scoped
+}
+
+// sendNodeResourcesToClient sends the full form of the WorkloadEndpoints of the client's node from the
+// given breadcrumb.
+func (h *connection) sendNodeResourcesToClient(breadcrumb *snapcache.Breadcrumb) (err error) {
+	prefix := syncproto.WorkloadEndpointKeyPrefix(h.scopeToNode)
+	var kvs []syncproto.SerializedUpdate
+	breadcrumb.KVs.AscendGreaterOrEqual(syncproto.SerializedUpdate{Key: prefix}, func(upd syncproto.SerializedUpdate) bool {
+		if !strings.HasPrefix(upd.Key, prefix) {
+			return false
+		}
+		kvs = append(kvs, upd)
+		if len(kvs) >= h.config.MaxMessageSize {
+			err = h.sendMsg(syncproto.MsgKVs{KVs: kvs})
+			kvs = nil
+		}
+		return err == nil
+	})
+	if err != nil || len(kvs) == 0 {
+		return
+	}
+	return h.sendMsg(syncproto.MsgKVs{KVs: kvs})
+}
+
 // writeSnapshotMessages chunks the given breadcrumb up into syncproto.MsgKVs objects and calls writeMsg for each one.
+// If view is non-nil, each KV is passed through it first.
 func writeSnapshotMessages(
 	ctx context.Context,
 	logCxt *log.Entry,
 	breadcrumb *snapcache.Breadcrumb,
+	view func(syncproto.SerializedUpdate) syncproto.SerializedUpdate,
 	writeMsg func(any) error,
 	maxMsgSize int,
 ) (err error) {
@@ -1257,6 +1446,9 @@ func writeSnapshotMessages(
 			err = ctx.Err()
 			return false
 		}
+		if view != nil {
+			entry = view(entry)
+		}
 		kvs = append(kvs, entry)
 		if len(kvs) >= maxMsgSize {
 			// Buffer is full, send the next batch.
@@ -1313,6 +1505,7 @@ func (h *connection) sendPingsToClient(logCxt *log.Entry) {
 // set per syncer type.
 type perSyncerConnMetrics struct {
 	counterGracePeriodUsed       prometheus.Counter
+	counterConnectionsResumed    prometheus.Counter
 	summarySnapshotSendTime      prometheus.Summary
 	summaryClientLatency         prometheus.Summary
 	summaryWriteLatency          prometheus.Summary
@@ -1361,6 +1554,7 @@ func makePerSyncerConnMetrics(syncerType syncproto.SyncerType) perSyncerConnMetr
 		ConstLabels: syncerLabels,
 	}))
 	c.counterGracePeriodUsed = counterVecGracePeriodUsed.WithLabelValues(string(syncerType))
+	c.counterConnectionsResumed = counterVecConnectionsResumed.WithLabelValues(string(syncerType))
 	c.gaugeNumConnectionsStreaming = gaugeVecNumConnectionsStreaming.WithLabelValues(string(syncerType))
 	return c
 }
@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/projectcalico/calico/typha/pkg/syncproto"
 	. "github.com/projectcalico/calico/typha/pkg/syncserver"
 )
 
@@ -46,6 +47,7 @@ var _ = Describe("With zero config", func() {
 			ShutdownTimeout:                300 * time.Second,
 			ShutdownMaxDropInterval:        time.Second,
 			MaxConns:                       math.MaxInt32,
+			CompressionAlgorithms:          []syncproto.CompressionAlgorithm{syncproto.CompressionSnappy},
 			Port:                           5473,
 		}))
 	})
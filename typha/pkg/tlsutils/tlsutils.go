@@ -17,18 +17,53 @@ package tlsutils
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"math/big"
 	"net/url"
 	"os"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	calicotls "github.com/projectcalico/calico/crypto/pkg/tls"
 )
 
+// NewServerTLSConfig loads the server's certificate and key and returns a TLS config that requires each client
+// to present a certificate that is signed by the CA in caFile and that has the required CN and/or URI SAN.
+func NewServerTLSConfig(logCxt *log.Entry, certFile, keyFile, caFile, requiredCN, requiredURISAN string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate and key: %w", err)
+	}
+	tlsConfig := calicotls.NewTLSConfig()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	// Arrange for server to verify the clients' certificates.
+	logCxt.Info("Will verify client certificates")
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	caPEMBlock, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA data: %w", err)
+	}
+	tlsConfig.ClientCAs = x509.NewCertPool()
+	ok := tlsConfig.ClientCAs.AppendCertsFromPEM(caPEMBlock)
+	if !ok {
+		return nil, errors.New("failed to add CA data to pool")
+	}
+	tlsConfig.VerifyPeerCertificate = CertificateVerifier(
+		logCxt,
+		tlsConfig.ClientCAs,
+		requiredCN,
+		requiredURISAN,
+	)
+	return tlsConfig, nil
+}
+
 // Common code for verifying whether a peer certificate has a required Common Name and/or a required
 // URI SAN.
 func CertificateVerifier(logCxt *log.Entry, roots *x509.CertPool, requiredCN, requiredURISAN string) func([][]byte, [][]*x509.Certificate) error {
@@ -0,0 +1,207 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upstream provides a Syncer that gets its data from another Typha instead of the datastore.  It
+// allows Typhas to be arranged in a tree: a small set of "root" Typhas watch the datastore and "leaf" Typhas
+// re-serve their data to Felix.
+package upstream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/typha/pkg/discovery"
+	"github.com/projectcalico/calico/typha/pkg/syncclient"
+)
+
+const defaultRetryInterval = time.Second
+
+type Config struct {
+	// Version, Hostname and Info identify us to the upstream Typha.
+	Version  string
+	Hostname string
+	Info     string
+	// RetryInterval is the time to wait before reconnecting after the connection to the upstream
+	// Typha fails.
+	RetryInterval time.Duration
+	// ClientOptions configures the connection to the upstream Typha, including the syncer type and TLS.
+	ClientOptions syncclient.Options
+}
+
+func (c Config) retryInterval() time.Duration {
+	if c.RetryInterval == 0 {
+		return defaultRetryInterval
+	}
+	return c.RetryInterval
+}
+
+// Syncer is an api.Syncer that streams the data of the given syncer type from an upstream Typha.
+//
+// The upstream's SyncStatus is passed through unchanged.  If the connection fails and the client is unable
+// to resume its session, the Syncer reconnects and receives a fresh snapshot.  It reports ResyncInProgress
+// while it does so and, once the upstream reports InSync, it sends deletions for the keys that were not in
+// the new snapshot, so that its callbacks see a consistent stream of updates across reconnections.
+type Syncer struct {
+	config     Config
+	discoverer *discovery.Discoverer
+	callbacks  api.SyncerCallbacks
+	newClient  func(cbs api.SyncerCallbacks) client
+
+	// knownKeys maps the serialized form of each key that we've sent to our callbacks to the key.
+	knownKeys map[string]model.Key
+	// staleKeys contains the known keys from previous connections that the current connection
+	// hasn't sent yet.
+	staleKeys map[string]model.Key
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// client is the subset of syncclient.SyncerClient that we use, to allow it to be replaced in UT.
+type client interface {
+	Start(ctx context.Context) error
+	Wait()
+}
+
+type syncerClient struct {
+	*syncclient.SyncerClient
+}
+
+func (c syncerClient) Wait() {
+	c.Finished.Wait()
+}
+
+func New(discoverer *discovery.Discoverer, callbacks api.SyncerCallbacks, config Config) *Syncer {
+	s := &Syncer{
+		config:     config,
+		discoverer: discoverer,
+		callbacks:  callbacks,
+		knownKeys:  map[string]model.Key{},
+		staleKeys:  map[string]model.Key{},
+	}
+	s.newClient = func(cbs api.SyncerCallbacks) client {
+		options := s.config.ClientOptions
+		return syncerClient{syncclient.New(
+			s.discoverer,
+			s.config.Version,
+			s.config.Hostname,
+			s.config.Info,
+			cbs,
+			&options,
+		)}
+	}
+	return s
+}
+
+func (s *Syncer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.loop(ctx)
+}
+
+func (s *Syncer) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Syncer) loop(ctx context.Context) {
+	defer s.wg.Done()
+	logCxt := log.WithField("syncerType", s.config.ClientOptions.SyncerType)
+	for ctx.Err() == nil {
+		s.runClient(ctx, logCxt)
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.config.retryInterval()):
+		}
+	}
+	logCxt.Info("Context finished, stopping upstream syncer.")
+}
+
+// runClient connects to the upstream Typha and processes its updates until the connection fails
+// permanently.
+func (s *Syncer) runClient(ctx context.Context, logCxt *log.Entry) {
+	if len(s.knownKeys) > 0 {
+		// We'll get a fresh snapshot, anything that isn't in it has been deleted while we were away.
+		logCxt.WithField("numKeys", len(s.knownKeys)).Info(
+			"Reconnecting to upstream Typha, resyncing our existing keys.")
+		for k, v := range s.knownKeys {
+			s.staleKeys[k] = v
+		}
+		s.knownKeys = map[string]model.Key{}
+		s.callbacks.OnStatusUpdated(api.ResyncInProgress)
+	}
+
+	clientCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := s.newClient(s)
+	if err := c.Start(clientCtx); err != nil {
+		logCxt.WithError(err).Warn("Failed to connect to upstream Typha, will retry.")
+		return
+	}
+	c.Wait()
+	logCxt.Info("Connection to upstream Typha finished.")
+}
+
+func (s *Syncer) OnStatusUpdated(status api.SyncStatus) {
+	if status == api.InSync && len(s.staleKeys) > 0 {
+		log.WithField("numKeys", len(s.staleKeys)).Info(
+			"Upstream Typha in sync, deleting keys that were removed while we were disconnected.")
+		deletions := make([]api.Update, 0, len(s.staleKeys))
+		for _, k := range s.staleKeys {
+			deletions = append(deletions, api.Update{
+				KVPair:     model.KVPair{Key: k},
+				UpdateType: api.UpdateTypeKVDeleted,
+			})
+		}
+		s.staleKeys = map[string]model.Key{}
+		s.callbacks.OnUpdates(deletions)
+	}
+	s.callbacks.OnStatusUpdated(status)
+}
+
+func (s *Syncer) OnUpdates(updates []api.Update) {
+	keys := make([]string, len(updates))
+	for i, u := range updates {
+		path, err := model.KeyToDefaultPath(u.Key)
+		if err != nil {
+			log.WithError(err).WithField("key", u.Key).Warn("Failed to serialize key, ignoring it for resync.")
+		}
+		keys[i] = path
+	}
+	s.OnUpdatesKeysKnown(updates, keys)
+}
+
+// OnUpdatesKeysKnown is called by the syncclient in place of OnUpdates, it saves us from serializing
+// the keys again.
+func (s *Syncer) OnUpdatesKeysKnown(updates []api.Update, keys []string) {
+	for i, u := range updates {
+		path := keys[i]
+		if path == "" {
+			continue
+		}
+		delete(s.staleKeys, path)
+		if u.Value == nil {
+			delete(s.knownKeys, path)
+		} else {
+			s.knownKeys[path] = u.Key
+		}
+	}
+	s.callbacks.OnUpdates(updates)
+}
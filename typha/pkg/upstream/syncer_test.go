@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+)
+
+type recorder struct {
+	statuses []api.SyncStatus
+	kvs      map[model.Key]interface{}
+}
+
+func (r *recorder) OnStatusUpdated(status api.SyncStatus) {
+	r.statuses = append(r.statuses, status)
+}
+
+func (r *recorder) OnUpdates(updates []api.Update) {
+	for _, u := range updates {
+		if u.Value == nil {
+			delete(r.kvs, u.Key)
+		} else {
+			r.kvs[u.Key] = u.Value
+		}
+	}
+}
+
+// fakeClient plays the given function against the callbacks when it is started.
+type fakeClient struct {
+	cbs  api.SyncerCallbacks
+	play func(cbs api.SyncerCallbacks)
+}
+
+func (c fakeClient) Start(ctx context.Context) error {
+	c.play(c.cbs)
+	return nil
+}
+
+func (c fakeClient) Wait() {}
+
+func update(name, value string) api.Update {
+	return api.Update{
+		KVPair:     model.KVPair{Key: model.GlobalConfigKey{Name: name}, Value: value},
+		UpdateType: api.UpdateTypeKVNew,
+	}
+}
+
+func TestResyncAfterReconnect(t *testing.T) {
+	RegisterTestingT(t)
+
+	r := &recorder{kvs: map[model.Key]interface{}{}}
+	s := New(nil, r, Config{})
+	var play func(cbs api.SyncerCallbacks)
+	s.newClient = func(cbs api.SyncerCallbacks) client {
+		return fakeClient{cbs: cbs, play: play}
+	}
+
+	play = func(cbs api.SyncerCallbacks) {
+		cbs.OnStatusUpdated(api.ResyncInProgress)
+		cbs.OnUpdates([]api.Update{update("a", "1"), update("b", "1"), update("c", "1")})
+		cbs.OnStatusUpdated(api.InSync)
+	}
+	s.runClient(context.Background(), log.WithField("test", "upstream"))
+	Expect(r.statuses).To(Equal([]api.SyncStatus{api.ResyncInProgress, api.InSync}))
+	Expect(r.kvs).To(HaveLen(3))
+
+	// The new snapshot doesn't have "b" and "c" but has a new "d".  We should only delete "b" and "c"
+	// once the upstream is in sync.
+	r.statuses = nil
+	play = func(cbs api.SyncerCallbacks) {
+		cbs.OnUpdates([]api.Update{update("a", "2"), update("d", "1")})
+		Expect(r.kvs).To(HaveLen(4))
+		cbs.OnStatusUpdated(api.InSync)
+	}
+	s.runClient(context.Background(), log.WithField("test", "upstream"))
+	Expect(r.statuses).To(Equal([]api.SyncStatus{api.ResyncInProgress, api.InSync}))
+	Expect(r.kvs).To(Equal(map[model.Key]interface{}{
+		model.GlobalConfigKey{Name: "a"}: "2",
+		model.GlobalConfigKey{Name: "d"}: "1",
+	}))
+
+	// If the connection fails before the upstream is in sync, the keys that we haven't seen yet remain
+	// stale.
+	play = func(cbs api.SyncerCallbacks) {
+		cbs.OnUpdates([]api.Update{update("a", "3")})
+	}
+	s.runClient(context.Background(), log.WithField("test", "upstream"))
+	play = func(cbs api.SyncerCallbacks) {
+		cbs.OnUpdates([]api.Update{update("a", "3")})
+		Expect(r.kvs).To(HaveLen(2))
+		cbs.OnStatusUpdated(api.InSync)
+	}
+	s.runClient(context.Background(), log.WithField("test", "upstream"))
+	Expect(r.kvs).To(Equal(map[model.Key]interface{}{
+		model.GlobalConfigKey{Name: "a"}: "3",
+	}))
+}